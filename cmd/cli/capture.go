@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// replayTimeout bounds a replayed request
+const replayTimeout = 30 * time.Second
+
+// unreplayedHeaders are set by the client for the replayed request itself
+var unreplayedHeaders = []string{"Host", "Content-Length", "Connection", "Accept-Encoding", "Transfer-Encoding"}
+
+func newExportCaptureCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-capture <id>",
+		Short: "Print a debug capture as JSON, to replay it elsewhere with replay-capture",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capture id %q", args[0])
+			}
+
+			return withApp(cmd.Context(), func(app *core.App) error {
+				capture, err := app.Service.DebugCapture.GetCapture(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(capture)
+			})
+		},
+	}
+}
+
+func newReplayCaptureCommand() *cobra.Command {
+	var (
+		target  string
+		headers []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay-capture <file>",
+		Short: "Send the request of a debug capture to a local server and print its response",
+		Long: `Send the request of a debug capture to --target and print the response next to the
+captured one. The file (- for stdin) holds the output of export-capture or of
+GET /admin/captures/{id}. Captures are sanitized: the credential headers and sensitive
+fields are masked, so the masked headers are left out and local credentials are passed
+with --header, e.g. --header "Authorization: Bearer <token>".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			capture, err := readCapture(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			req, err := replayRequest(cmd, target, capture.Request, headers)
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: replayTimeout}
+			res, err := client.Do(req)
+			if err != nil {
+				return fmt.Errorf("failed to replay the capture: %w", err)
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				return fmt.Errorf("failed to read the response: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s %s\n", req.Method, req.URL)
+			fmt.Fprintf(out, "captured: %d in %dms\n", capture.Response.Status, capture.Response.DurationMs)
+			fmt.Fprintf(out, "replayed: %d\n\n", res.StatusCode)
+			fmt.Fprintln(out, "captured body:")
+			fmt.Fprintln(out, capture.Response.Body)
+			fmt.Fprintln(out, "\nreplayed body:")
+			fmt.Fprintln(out, string(body))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "target", "http://localhost:8080", "base URL of the server to replay the capture on")
+	cmd.Flags().StringArrayVar(&headers, "header", nil, `header to send, replacing the captured one, e.g. "X-API-Key: local-key"`)
+	return cmd
+}
+
+// readCapture reads the capture of file, or stdin for -, either bare (export-capture) or in
+// the data of an API response (GET /admin/captures/{id})
+func readCapture(cmd *cobra.Command, file string) (*models.DebugCaptureResponse, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the capture: %w", err)
+	}
+
+	var envelope struct {
+		Data *models.DebugCaptureResponse `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err == nil && envelope.Data != nil {
+		return envelope.Data, nil
+	}
+	var capture models.DebugCaptureResponse
+	if err := json.Unmarshal(data, &capture); err != nil || capture.Request.Method == "" {
+		return nil, fmt.Errorf("%s is not a debug capture", file)
+	}
+	return &capture, nil
+}
+
+// replayRequest builds the captured request against target, without the masked headers and
+// with the headers of --header
+func replayRequest(cmd *cobra.Command, target string, captured models.CapturedRequest, overrides []string) (*http.Request, error) {
+	if captured.BodyOmitted || captured.BodyTruncated {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: the captured body is incomplete, the replayed request may differ")
+	}
+
+	req, err := http.NewRequestWithContext(cmd.Context(), captured.Method, strings.TrimRight(target, "/")+captured.URL, strings.NewReader(captured.Body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid replay request: %w", err)
+	}
+	for name, value := range captured.Header {
+		if value == logger.MaskString || slices.Contains(unreplayedHeaders, http.CanonicalHeaderKey(name)) {
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	for _, header := range overrides {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid --header %q, want \"Name: value\"", header)
+		}
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return req, nil
+}
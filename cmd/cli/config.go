@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/pkg/generator"
+
+	"github.com/spf13/cobra"
+)
+
+func newCheckConfigCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-config",
+		Short: "Load and validate the configuration of --env without connecting to anything",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configuration, err := loadConfiguration(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := core.CheckConfiguration(configuration); err != nil {
+				return fmt.Errorf("invalid configuration: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "configuration of environment %q is valid\n", configuration.Application.Environment)
+			return nil
+		},
+	}
+}
+
+func newRotateAPIKeyCommand() *cobra.Command {
+	var admin bool
+
+	cmd := &cobra.Command{
+		Use:   "rotate-api-key",
+		Short: "Generate a new API key to replace authorization.api_key",
+		Long: `Generate a new API key to replace authorization.api_key (or authorization.admin_api_key
+with --admin). API keys are read from the configuration, so the command only prints the key:
+store it where the current one is kept (secret store or APP_AUTHORIZATION_API_KEY), restart
+the instances, and hand it to the clients. The old key stops working on restart.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			key, err := generator.APIKey()
+			if err != nil {
+				return err
+			}
+
+			setting := "authorization.api_key"
+			if admin {
+				setting = "authorization.admin_api_key"
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "new %s (store it and restart the instances):\n", setting)
+			fmt.Fprintln(cmd.OutOrStdout(), key)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&admin, "admin", false, "rotate authorization.admin_api_key instead")
+	return cmd
+}
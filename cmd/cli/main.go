@@ -0,0 +1,100 @@
+// Command cli runs operational tasks (user administration, config checks) against the same
+// configuration and service layer as the HTTP server, without going through the API.
+//
+//	go run ./cmd/cli --env=dev create-user --name "Jane Doe" --email jane@example.com --password-stdin
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/models"
+	_ "go-echo-boilerplate/internal/modules" // registers the feature modules
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "cli",
+		Short:        "Operational tasks for the go-echo-boilerplate service",
+		SilenceUsage: true,
+	}
+
+	// config.Initialize reads these from os.Args itself, they are declared so cobra accepts them
+	root.PersistentFlags().String("env", "", "environment whose config file is loaded (overrides ENV)")
+	root.PersistentFlags().String("log-level", "", "minimum log level (logger.level)")
+
+	root.AddCommand(
+		newAnonymizeAccountsCommand(),
+		newCheckConfigCommand(),
+		newCreateUserCommand(),
+		newExportCaptureCommand(),
+		newListDeadLettersCommand(),
+		newMigrateCommand(),
+		newReplayCaptureCommand(),
+		newResetPasswordCommand(),
+		newRetryDeadLetterCommand(),
+		newRevokeSessionsCommand(),
+		newRotateAPIKeyCommand(),
+	)
+	return root
+}
+
+// loadConfiguration loads and validates the configuration like cmd/http does
+func loadConfiguration(ctx context.Context) (*config.Configuration, error) {
+	configuration, err := config.Initialize(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize configuration: %w", err)
+	}
+	return configuration, nil
+}
+
+// withApp loads the configuration, connects to the databases, and runs fn with the
+// services, disconnecting and flushing the logs afterwards.
+func withApp(ctx context.Context, fn func(app *core.App) error) error {
+	configuration, err := loadConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Initialize(configuration)
+	defer func() { _ = logger.Close() }()
+
+	app, err := core.NewApp(configuration)
+	if err != nil {
+		return fmt.Errorf("failed to setup application: %w", err)
+	}
+	defer func() { _ = core.Teardown(ctx) }()
+
+	return describe(fn(app))
+}
+
+// describe turns a service error into a readable one, listing the fields of a validation error
+func describe(err error) error {
+	var httpErr *errorc.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+
+	message := []string{httpErr.Response.Message}
+	if fields, ok := httpErr.Response.Errors.([]models.ErrorValidationResponse); ok {
+		for _, field := range fields {
+			message = append(message, fmt.Sprintf("  %s: %s", field.Field, field.Message))
+		}
+	}
+	return errors.New(strings.Join(message, "\n"))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/pkg/lock"
+
+	"github.com/pressly/goose/v3"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply the SQL migrations of every registered module",
+		Long: `Apply the SQL migrations of every registered module. Each module ships its migrations
+(core.Module.Migrations); they are merged into one goose directory and versioned by their
+file name prefix, so they run in timestamp order whatever module they come from.`,
+	}
+
+	cmd.AddCommand(
+		newMigrateRunCommand("up", "Apply every pending migration", func(provider *goose.Provider, cmd *cobra.Command) error {
+			results, err := provider.Up(cmd.Context())
+			printResults(cmd.OutOrStdout(), results...)
+			return err
+		}),
+		newMigrateRunCommand("down", "Roll back the last applied migration", func(provider *goose.Provider, cmd *cobra.Command) error {
+			result, err := provider.Down(cmd.Context())
+			if result != nil {
+				printResults(cmd.OutOrStdout(), result)
+			}
+			return err
+		}),
+		newMigrateRunCommand("status", "List the migrations and whether they are applied", func(provider *goose.Provider, cmd *cobra.Command) error {
+			statuses, err := provider.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+			for _, status := range statuses {
+				appliedAt := "pending"
+				if status.State == goose.StateApplied {
+					appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %s\n", appliedAt, status.Source.Path)
+			}
+			return nil
+		}),
+	)
+	return cmd
+}
+
+// newMigrateRunCommand creates a migrate subcommand running fn with a goose provider over
+// the merged migrations of the modules
+func newMigrateRunCommand(use, short string, fn func(provider *goose.Provider, cmd *cobra.Command) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withApp(cmd.Context(), func(app *core.App) error {
+				migrations, err := core.Migrations(core.Modules()...)
+				if err != nil {
+					return err
+				}
+
+				db, err := app.DB.PostgreDatabase.DB()
+				if err != nil {
+					return err
+				}
+
+				// Migrations may take longer than postgresql.statement_timeout, e.g. building an
+				// index: they run on a single connection with the session timeouts off
+				db.SetMaxOpenConns(1)
+				db.SetMaxIdleConns(1)
+				for _, setting := range []string{"statement_timeout", "idle_in_transaction_session_timeout"} {
+					if _, err := db.ExecContext(cmd.Context(), "SET "+setting+" = 0"); err != nil {
+						return fmt.Errorf("failed to disable %s: %w", setting, err)
+					}
+				}
+
+				provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations)
+				if err != nil {
+					return err
+				}
+
+				// Replicas migrating on startup take turns; the lock is held outside of db
+				return lock.WithLockWait(cmd.Context(), "migrate", func(ctx context.Context) error {
+					cmd.SetContext(ctx)
+					return fn(provider, cmd)
+				})
+			})
+		},
+	}
+}
+
+func printResults(w io.Writer, results ...*goose.MigrationResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "no migrations to apply")
+	}
+	for _, result := range results {
+		fmt.Fprintln(w, result)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"go-echo-boilerplate/internal/core"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultDeadLetterLimit is the default of list-dead-letters --limit
+const defaultDeadLetterLimit = 50
+
+func newListDeadLettersCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list-dead-letters",
+		Short: "List the notification deliveries that failed every attempt, the oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withApp(cmd.Context(), func(app *core.App) error {
+				letters, err := app.Service.Notification.ListDeadLetters(cmd.Context(), limit)
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tFAILED AT\tEVENT\tCHANNEL\tACCOUNT\tATTEMPTS\tERROR")
+				for _, letter := range letters {
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%s\n", letter.ID, letter.FailedAt.Format(time.RFC3339),
+						letter.Event, letter.Channel, letter.AccountNumber, letter.Attempts, letter.Error)
+				}
+				return w.Flush()
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", defaultDeadLetterLimit, "most dead letters to list")
+	return cmd
+}
+
+func newRetryDeadLetterCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry-dead-letter <id>",
+		Short: "Deliver a notification dead letter again, on its channel; it is deleted once queued",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid dead letter id %q", args[0])
+			}
+
+			// Teardown waits for the delivery; a failure comes back as a new dead letter
+			return withApp(cmd.Context(), func(app *core.App) error {
+				if err := app.Service.Notification.RetryDeadLetter(cmd.Context(), id); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "queued dead letter %d\n", id)
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func newCreateUserCommand() *cobra.Command {
+	var (
+		request       models.CreateUserRequest
+		passwordStdin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create-user",
+		Short: "Create a user, with the same validation and password policy as POST /api/v1/users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if passwordStdin {
+				password, err := readPassword(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				request.Password = password
+			}
+			if err := validator.Input(&request, i18n.English); err != nil {
+				return err
+			}
+
+			return withApp(cmd.Context(), func(app *core.App) error {
+				user, err := app.Service.User.Create(cmd.Context(), &request)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "created user %s\n", user.AccountNumber)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&request.Name, "name", "", "full name")
+	cmd.Flags().StringVar(&request.Username, "username", "", "username, optional")
+	cmd.Flags().StringVar(&request.Email, "email", "", "email address (required without --phone)")
+	cmd.Flags().StringVar(&request.PhoneNumber.Number, "phone", "", "phone number (required without --email)")
+	cmd.Flags().StringVar(&request.PhoneNumber.CountryCode, "country-code", "", "phone number country code, defaults to ID")
+	cmd.Flags().StringVar(&request.Password, "password", "", "password (prefer --password-stdin, flags end up in the shell history)")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from stdin")
+	cmd.Flags().StringVar(&request.TermsVersion, "terms-version", "", "terms version the user accepted, required to be consent.terms_version when set")
+	cmd.Flags().BoolVar(&request.MarketingOptIn, "marketing-opt-in", false, "the user agreed to marketing")
+	cmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+	return cmd
+}
+
+func newResetPasswordCommand() *cobra.Command {
+	var (
+		password       string
+		passwordStdin  bool
+		revokeSessions bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reset-password <account-number>",
+		Short: "Replace the password of a user and sign them out everywhere",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if passwordStdin {
+				var err error
+				if password, err = readPassword(cmd.InOrStdin()); err != nil {
+					return err
+				}
+			}
+			if password == "" {
+				return errors.New("a password is required: use --password or --password-stdin")
+			}
+
+			return withApp(cmd.Context(), func(app *core.App) error {
+				accountNumber := args[0]
+				// Checked before the password changes, not after it already did
+				if revokeSessions {
+					if err := requireSharedRevocations(app); err != nil {
+						return err
+					}
+				}
+				if err := app.Service.User.ResetPassword(cmd.Context(), accountNumber, password); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "reset the password of user %s\n", accountNumber)
+
+				if !revokeSessions {
+					return nil
+				}
+				return revokeUserSessions(cmd, app, accountNumber)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&password, "password", "", "new password (prefer --password-stdin, flags end up in the shell history)")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the new password from stdin")
+	cmd.Flags().BoolVar(&revokeSessions, "revoke-sessions", true, "also revoke the tokens issued so far")
+	cmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+	return cmd
+}
+
+func newRevokeSessionsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-sessions <account-number>",
+		Short: "Reject every token issued to a user so far; they have to sign in again",
+		Long: `Reject every token issued to a user so far; they have to sign in again.
+Revocations are shared through Redis, so redis.addr must be configured for the running
+instances to see them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withApp(cmd.Context(), func(app *core.App) error {
+				return revokeUserSessions(cmd, app, args[0])
+			})
+		},
+	}
+}
+
+// revokeUserSessions revokes the tokens of the user, see requireSharedRevocations.
+func revokeUserSessions(cmd *cobra.Command, app *core.App, accountNumber string) error {
+	if err := requireSharedRevocations(app); err != nil {
+		return err
+	}
+	if err := app.Service.User.RevokeSessions(cmd.Context(), accountNumber); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "revoked the sessions of user %s\n", accountNumber)
+	return nil
+}
+
+// requireSharedRevocations refuses to revoke sessions without Redis: the revocation would only
+// live in this process rather than reach the servers.
+func requireSharedRevocations(app *core.App) error {
+	if app.DB.Redis == nil {
+		return errors.New("revoking sessions requires redis.addr: the servers would not see a revocation kept in memory")
+	}
+	return nil
+}
+
+// readPassword reads the first line of r
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func newAnonymizeAccountsCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "anonymize-accounts",
+		Short: "Anonymize the accounts whose deletion grace period is over, like the server's periodic sweep",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withApp(cmd.Context(), func(app *core.App) error {
+				anonymized, err := app.Service.User.AnonymizeDueAccounts(cmd.Context(), limit)
+				fmt.Fprintf(cmd.OutOrStdout(), "anonymized %d accounts\n", anonymized)
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", service.DefaultDeletionBatchSize, "most accounts to anonymize")
+	return cmd
+}
@@ -3,11 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"time"
 
 	"go-echo-boilerplate/internal/config"
 	"go-echo-boilerplate/internal/core"
+	_ "go-echo-boilerplate/internal/modules" // registers the feature modules
 	"go-echo-boilerplate/internal/pkg/graceful"
 	"go-echo-boilerplate/internal/pkg/logger"
 )
@@ -25,7 +27,9 @@ func run() error {
 	ctx := context.Background()
 	config, err := config.Initialize(ctx)
 	if err != nil {
-		panic(fmt.Errorf("failed to initialize configuration: %w", err))
+		// The logger is not initialized yet, L() writes to stderr
+		logger.L().Error(ctx, "failed to initialize configuration", logger.Error(err))
+		return fmt.Errorf("failed to initialize configuration: %w", err)
 	}
 
 	// 2. Setup application core, from logger, to echo server, database, etc.
@@ -34,35 +38,61 @@ func run() error {
 		return fmt.Errorf("failed to setup application: %w", err)
 	}
 
-	port := fmt.Sprintf(":%d", config.Application.Port)
+	serverOptions, err := core.ServerOptions(config)
+	if err != nil {
+		return fmt.Errorf("failed to configure http server: %w", err)
+	}
+
+	addr := config.Application.Address()
 
-	// 3. Define processes to manage
+	// 3. Define processes to manage, with the background jobs
 	processes := map[string]graceful.Process{
-		"http-server": graceful.NewEchoProcess(e, port),
+		"http-server": graceful.NewEchoProcess(e, addr, serverOptions...),
 		"cleanup":     graceful.NewFuncProcess(core.Teardown),
+		"log-flush":   graceful.NewFuncProcess(logger.Flush),
 	}
+	maps.Copy(processes, core.Jobs())
 
-	// 4. Configure graceful shutdown
+	// 4. Configure graceful shutdown, started once the dependencies are ready
 	shutdownTimeout := 10 * time.Second
-	logAdapter := graceful.NewLoggerAdapter(logger.Instance, ctx)
+	logAdapter := graceful.NewLoggerAdapter(logger.L(), ctx)
+
+	readinessChecks, err := core.ReadinessChecks(config)
+	if err != nil {
+		return fmt.Errorf("failed to configure readiness checks: %w", err)
+	}
 
 	// 5. Run with graceful lifecycle management
-	graceful.Graceful(processes,
+	options := []graceful.Option{
 		graceful.WithTimeout(shutdownTimeout),
 		graceful.WithLogger(logAdapter),
-		graceful.WithStartupHook(func() {
-			logger.Instance.Info(ctx, "All processes started successfully",
+		graceful.WithRestart(graceful.DefaultRestartTimeout), // kill -HUP <pid> restarts without dropping a connection
+		graceful.WithStartupHook("log", func(context.Context) error {
+			logger.L().Info(ctx, "All processes started successfully",
 				logger.String("env", config.Application.Environment),
-				logger.String("addr", port),
+				logger.String("addr", addr),
 			)
+			return nil
 		}),
-		graceful.WithShutdownHook(func() {
-			logger.Instance.Info(ctx, "Beginning graceful shutdown",
+		graceful.WithShutdownHook("log", func(context.Context) error {
+			logger.L().Info(ctx, "Beginning graceful shutdown",
 				logger.String("timeout", shutdownTimeout.String()),
 			)
+			return nil
 		}),
-	)
+	}
+	// A crashed background job is restarted instead of stopping the server
+	for _, job := range core.Jobs() {
+		options = append(options, graceful.WithRestartPolicy(job, time.Second, 0))
+	}
+	if err := graceful.Graceful(processes, append(options, readinessChecks...)...); err != nil {
+		logger.L().Error(ctx, "Server stopped with errors", logger.Error(err))
+		_ = logger.Close()
+		return err
+	}
+
+	logger.L().Info(ctx, "Server shutdown completed successfully")
 
-	logger.Instance.Info(ctx, "Server shutdown completed successfully")
-	return nil
+	// Flush buffered log sinks (file, otlp) last so the shutdown logs are exported
+	return logger.Close()
 }
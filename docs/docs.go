@@ -9,15 +9,86 @@ const docTemplate = `{
     "info": {
         "description": "{{escape .Description}}",
         "title": "{{.Title}}",
-        "contact": {},
+        "termsOfService": "http://swagger.io/terms/",
+        "contact": {
+            "name": "API Support",
+            "url": "http://www.swagger.io/support",
+            "email": "support@swagger.io"
+        },
+        "license": {
+            "name": "Apache 2.0",
+            "url": "http://www.apache.org/licenses/LICENSE-2.0.html"
+        },
         "version": "{{.Version}}"
     },
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/users": {
+        "/admin/captures": {
+            "get": {
+                "description": "List the debug captures that did not expire, the latest first, without their request and response",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Debug Captures",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most captures to list (default 50, at most 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DebugCaptureSummaryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/captures/tokens": {
             "post": {
-                "description": "Register a new user with email, phone number, and password. Auto-generates account number.",
+                "description": "Create a token capturing the sanitized request and response of every request sending it in the X-Debug-Capture header, until it expires after debug_capture.token_ttl. Requires debug_capture.enabled.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,23 +96,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Admin"
                 ],
-                "summary": "Create New User",
+                "summary": "Create Debug Capture Token",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Label of the captures",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
+                            "$ref": "#/definitions/models.CreateDebugCaptureTokenRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User Created Successfully",
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -51,7 +129,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.CreateUserResponse"
+                                            "$ref": "#/definitions/models.DebugCaptureTokenResponse"
                                         }
                                     }
                                 }
@@ -61,45 +139,68 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid Input / Validation Error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "409": {
-                        "description": "User Already Exists (Email or Phone)",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Debug Capture Not Enabled",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/users/me": {
+        "/admin/captures/{id}": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get user information by access token",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a debug capture with its sanitized request and response; saved to a file, it is replayed locally with the cli replay-capture command",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Admin"
+                ],
+                "summary": "Get Debug Capture",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Capture ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get User By Access Token",
                 "responses": {
                     "200": {
-                        "description": "User Information Retrieved Successfully",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -109,37 +210,76 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.GetUserByAccountNumberResponse"
+                                            "$ref": "#/definitions/models.DebugCaptureResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid Input / Validation Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User Not Found",
+                        "description": "Capture Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/loglevel": {
+            "get": {
+                "description": "Get the global log level and the per-path overrides from logger.path_levels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get Log Level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LogLevelResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/users/tokens": {
-            "post": {
-                "description": "Get tokens for a user",
+            },
+            "put": {
+                "description": "Change the global log level without a restart. The change lasts until the next restart or config reload.",
                 "consumes": [
                     "application/json"
                 ],
@@ -147,23 +287,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Admin"
                 ],
-                "summary": "Get Tokens",
+                "summary": "Update Log Level",
                 "parameters": [
                     {
-                        "description": "User Token Request",
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New log level",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.GetUserTokenRequest"
+                            "$ref": "#/definitions/models.UpdateLogLevelRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Tokens Retrieved Successfully",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -173,7 +320,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.GetUserTokenResponse"
+                                            "$ref": "#/definitions/models.LogLevelResponse"
                                         }
                                     }
                                 }
@@ -183,37 +330,99 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid Input / Validation Error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "User Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/logstats": {
+            "get": {
+                "description": "Get the queue depth and written/dropped counts of the async logger (logger.async), and the business data cut by logger.limits",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get Log Stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LogStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/health": {
+        "/admin/maintenance": {
             "get": {
-                "description": "Check the health status of the service and its dependencies",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get whether the instance answers 503 to every route but /health, /version, /admin, and /debug, as set by maintenance in the config or with PUT /admin/maintenance",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Admin"
+                ],
+                "summary": "Get Maintenance Mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Check health status",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -226,159 +435,3779 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.HealthDetailResponse"
-                                            }
+                                            "$ref": "#/definitions/models.MaintenanceResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.CreateUserRequest": {
-            "type": "object",
-            "required": [
-                "name",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "john.doe@example.com"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "John Doe"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
-                },
-                "phoneNumber": {
-                    "$ref": "#/definitions/models.PhoneNumber"
-                }
-            }
-        },
-        "models.CreateUserResponse": {
-            "type": "object",
-            "properties": {
-                "accountNumber": {
-                    "type": "string",
-                    "example": "1234567890"
-                },
-                "createdAt": {
-                    "type": "string",
-                    "example": "2026-01-24T15:57:37+07:00"
-                },
-                "email": {
-                    "type": "string",
-                    "example": "john.doe@example.com"
-                },
-                "name": {
-                    "type": "string",
+            },
+            "put": {
+                "description": "Turn the maintenance mode on or off on this instance without a restart, e.g. around a migration. It lasts until the next restart or a config reload changing maintenance; other instances are changed through the config.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update Maintenance Mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Maintenance mode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMaintenanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MaintenanceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/pii/resolve": {
+            "post": {
+                "description": "Resolve a token of the pii masking strategy back to the logged value, for incident investigation. Every attempt is written to the audit log with its reason; the value itself is never logged. Tokens are resolvable for pii.ttl after they were last logged, and not after a restart.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Resolve PII Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Token and reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolvePIITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ResolvePIITokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown or expired token, or pii.key is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/quotas/overrides": {
+            "get": {
+                "description": "List the overrides of the quotas of authorization.clients, expired ones included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Quota Overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.QuotaOverrideResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/quotas/overrides/{client}": {
+            "get": {
+                "description": "Get the override of the quotas of a client of authorization.clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get Quota Override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client name",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuotaOverrideResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown Client or No Override",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the daily or monthly quota of a client of authorization.clients, e.g. to unblock it; a null quota keeps the configured one and 0 is unlimited. It applies at once on this instance and within quota.overrides_ttl on the others, until expires_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Save Quota Override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client name",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quota override",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SaveQuotaOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuotaOverrideResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown Client",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the override of the quotas of a client of authorization.clients, so its configured quotas apply again",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete Quota Override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client name",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Deleted"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown Client or No Override",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runtime": {
+            "get": {
+                "description": "Get the goroutine count, memory statistics, and GC statistics of the instance answering, to diagnose a leak or GC pressure without a shell on the host. Reading them stops the world briefly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get Runtime",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RuntimeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runtime/heap-dumps": {
+            "post": {
+                "description": "Run a GC and write the heap profile of the instance answering to diagnostics.dump_dir, read offline with go tool pprof. Requires diagnostics.dump_dir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create Heap Dump",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.HeapDumpResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Heap Dumps Not Enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/quota": {
+            "get": {
+                "description": "Get the daily and monthly quotas of the API key of the request (authorization.clients), with the requests used and remaining in the current period, this one included. Periods are UTC calendar days and months. The shared authorization.api_key has no quota.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API"
+                ],
+                "summary": "Get API Key Quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuotaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or Unknown API Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Quota Exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/users": {
+            "get": {
+                "description": "List users with optional name and creation date filters. Answers 404 unless authorization.admin_api_key is set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partial, case-insensitive name match",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after (YYYY-MM-DD)",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before (YYYY-MM-DD)",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "createdAt",
+                            "-createdAt"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserSummaryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Admin API Disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/users/export": {
+            "get": {
+                "description": "Download every user that is not deleted as CSV (the columns name, username, email, phone_number, phone_country_code, account_number, created_at, and deletion_scheduled_at, the first five as an import takes them) or NDJSON. The file is streamed as the users are read, in pages of 500. Answers 404 unless authorization.admin_api_key is set.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Export Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users, a line each",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserExportResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Admin API Disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/users/import": {
+            "post": {
+                "description": "Create up to 200 users from a CSV file (a header row naming the columns name, username, email, phone_number, phone_country_code, and password) or NDJSON (a user registration per line). Each row is validated like a signup and the valid ones are inserted in transactions of 100 rows; the report has the outcome of every row, by line. Answers 404 unless authorization.admin_api_key is set.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Import Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Users to import",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users Imported",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Unreadable File / Too Many Rows",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Admin API Disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Neither CSV Nor NDJSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/users/search": {
+            "get": {
+                "description": "Search users by name, username, email, or phone number, tolerating typos, or by exact account number. Results are ranked best match first. Answers 404 unless authorization.admin_api_key is set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Search Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 100,
+                        "minLength": 2,
+                        "type": "string",
+                        "description": "Search",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users Found Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserSearchResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Admin API Disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "post": {
+                "description": "Register a new user with email, phone number, and password, and optionally a username. Auto-generates account number.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create New User",
+                "parameters": [
+                    {
+                        "description": "User Registration Details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAPTCHA answer, required when captcha.routes lists this route",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User Created Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CreateUserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "User Already Exists (Email, Phone, or Username)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Captcha Response Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/availability": {
+            "get": {
+                "description": "Tell whether a username can be registered. Usernames are compared lowercased; unavailable ones come with the reason: invalid (3 to 30 letters, digits, dots, or underscores), reserved, or taken.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Check Username Availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username to check",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability Checked",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UsernameAvailabilityResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/email/confirm": {
+            "post": {
+                "description": "Confirm an email change with the token emailed to the current or to the new address. The email changes once both addresses confirmed, which signs the user out everywhere and notifies the old address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Confirm Email Change",
+                "parameters": [
+                    {
+                        "description": "Confirmation Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ConfirmEmailChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email Change Confirmed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EmailChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid, Expired, or Cancelled Token",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Email Already Exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user information by access token. Responses carry an ETag; send it back in If-None-Match to get 304 when nothing changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get User By Access Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Information Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetUserByAccountNumberResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule the deletion of the authenticated user after the grace period (account_deletion.grace_period) and sign them out everywhere. Signing in before then cancels the deletion; after it, the name, email, phone number, password, and logins of the account are erased.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete Account",
+                "responses": {
+                    "202": {
+                        "description": "Deletion Scheduled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DeleteUserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename the authenticated user. The request carries the version of GET /api/v1/users/me it was based on; when the user changed since, it fails with 409 VERSION_CONFLICT and nothing is updated, so reload the user and retry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "description": "Profile Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Updated Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetUserByAccountNumberResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User Changed Since The Version Of The Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the terms version the authenticated user accepted, whether it is the current one, and their marketing choice",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Consents",
+                "responses": {
+                    "200": {
+                        "description": "Consents Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ConsentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept the current terms version and/or change the marketing choice of the authenticated user. Every change is recorded with its time and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update Consents",
+                "parameters": [
+                    {
+                        "description": "Consent Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateConsentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consents Updated Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ConsentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/email": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start changing the email of the authenticated user. A confirmation token is emailed to the current and to the new address; the email changes once both were confirmed with POST /api/v1/users/email/confirm, within email_change.ttl. A newer request cancels the pending one. Accounts without an email only confirm the new address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change Email",
+                "parameters": [
+                    {
+                        "description": "New Email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Confirmation Emails Sent",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EmailChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email Already Exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/logins": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the most recent logins of the authenticated user, newest first. Logins from a country or device the account never used before are flagged as suspicious. Recorded when login_alerts is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List Recent Logins",
+                "parameters": [
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of logins",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Logins Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.LoginEventResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the channels the authenticated user gets optional notifications (e.g. sign-in alerts) on. Verification codes and confirmations are sent whatever they are.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Notification Preferences",
+                "responses": {
+                    "200": {
+                        "description": "Notification Preferences Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn the email and/or SMS channel on or off for the optional notifications of the authenticated user; the channels left out keep their preference.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update Notification Preferences",
+                "parameters": [
+                    {
+                        "description": "Notification Preference Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification Preferences Updated Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/phone": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start changing the phone number of the authenticated user. The number is formatted to E.164 and a 6-digit code is texted to it; the number changes once the code is verified with POST /api/v1/users/me/phone/verify, within phone_change.ttl. A newer request cancels the pending one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change Phone Number",
+                "parameters": [
+                    {
+                        "description": "New Phone Number",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangePhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Verification Code Sent",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PhoneChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone Number Already Exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/me/phone/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verify the code texted to the new phone number of the authenticated user, which switches the number. After phone_change.max_attempts wrong codes a new one must be requested.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Verify Phone Change",
+                "parameters": [
+                    {
+                        "description": "Verification Code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerifyPhoneChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Phone Number Changed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PhoneChangeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or Expired Code",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone Number Already Exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Wrong Codes",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/tokens": {
+            "post": {
+                "description": "Get tokens for a user, identified by email, phone number, or username",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Tokens",
+                "parameters": [
+                    {
+                        "description": "User Token Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GetUserTokenRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAPTCHA answer, required after login_throttle.challenge_after failures",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Tokens Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GetUserTokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Validation Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ErrorValidationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Captcha Response Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Failed Attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user information by access token. Unlike v1, contact details are grouped under contact and missing ones are null instead of empty strings. Responses carry an ETag; send it back in If-None-Match to get 304 when nothing changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get User By Access Token (v2)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Information Retrieved Successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserV2Response"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/versions": {
+            "get": {
+                "description": "List the API versions with their status, deprecation and sunset dates, and successor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API"
+                ],
+                "summary": "List API Versions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.APIVersionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check the health status of the service and its dependencies. PostgreSQL reports its connection pool and, on a primary with streaming replicas, their replay lag; it is WARN past the health.* thresholds. Leader tells whether the instance runs the singleton background jobs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Check health status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.HealthDetailResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Get the version, git commit, and build time of the running binary, injected at build time (see make build-prod), and its Go runtime. Without an injected version, it is application.version; without an injected commit and build time, the VCS stamp of go build.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Get the build version",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BuildResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "logger.AsyncStats": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "dropped": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "policy": {
+                    "type": "string"
+                },
+                "queued": {
+                    "type": "integer"
+                },
+                "written": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logger.TruncationStats": {
+            "type": "object",
+            "properties": {
+                "dropped_keys": {
+                    "type": "integer"
+                },
+                "truncated_values": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.APIVersionResponse": {
+            "type": "object",
+            "properties": {
+                "deprecated": {
+                    "type": "string",
+                    "example": "2026-06-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "v1"
+                },
+                "status": {
+                    "description": "supported or deprecated",
+                    "type": "string",
+                    "example": "supported"
+                },
+                "successor": {
+                    "type": "string",
+                    "example": "v2"
+                },
+                "sunset": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.BuildResponse": {
+            "type": "object",
+            "properties": {
+                "arch": {
+                    "type": "string",
+                    "example": "amd64"
+                },
+                "buildTime": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "commit": {
+                    "description": "suffixed with -dirty for uncommitted changes",
+                    "type": "string",
+                    "example": "4f9c2d1e8b7a6c5d4e3f2a1b0c9d8e7f6a5b4c3d"
+                },
+                "goVersion": {
+                    "type": "string",
+                    "example": "go1.24.4"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "linux"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.2.0"
+                }
+            }
+        },
+        "models.CapturedRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "body_omitted": {
+                    "description": "binary or multipart, not kept",
+                    "type": "boolean"
+                },
+                "body_truncated": {
+                    "description": "cut at debug_capture.max_body_size",
+                    "type": "boolean"
+                },
+                "header": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "url": {
+                    "description": "path and query",
+                    "type": "string",
+                    "example": "/api/v1/users?include=profile"
+                }
+            }
+        },
+        "models.CapturedResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "body_omitted": {
+                    "type": "boolean"
+                },
+                "body_truncated": {
+                    "type": "boolean"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "header": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                }
+            }
+        },
+        "models.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.new@example.com"
+                }
+            }
+        },
+        "models.ChangePhoneRequest": {
+            "type": "object",
+            "properties": {
+                "phoneNumber": {
+                    "description": "an empty number is rejected by the service",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PhoneNumber"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.ConfirmEmailChangeRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "AAAAAGkX3w1lbWFpbF9jaGFuZ2U6bmV3OjE.n8J2..."
+                }
+            }
+        },
+        "models.ConsentResponse": {
+            "type": "object",
+            "properties": {
+                "currentTermsVersion": {
+                    "type": "string",
+                    "example": "2026-10-01"
+                },
+                "marketingOptIn": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "marketingUpdatedAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "termsAccepted": {
+                    "description": "the current version is accepted",
+                    "type": "boolean",
+                    "example": true
+                },
+                "termsAcceptedAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "termsVersion": {
+                    "type": "string",
+                    "example": "2026-10-01"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "consent"
+                }
+            }
+        },
+        "models.CreateDebugCaptureTokenRequest": {
+            "type": "object",
+            "required": [
+                "label"
+            ],
+            "properties": {
+                "label": {
+                    "description": "e.g. the support ticket, stored with each capture",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "SUP-1234"
+                }
+            }
+        },
+        "models.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "marketingOptIn": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "termsVersion": {
+                    "description": "TermsVersion is the terms version the user accepted, required to be the current one\nwhen consent.terms_version is set",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "2026-10-01"
+                },
+                "username": {
+                    "description": "optional, unique regardless of case",
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "models.CreateUserResponse": {
+            "type": "object",
+            "properties": {
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "models.DebugCaptureResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "captured_at": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-27T15:57:37Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "label": {
+                    "type": "string",
+                    "example": "SUP-1234"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/v1/users"
+                },
+                "request": {
+                    "$ref": "#/definitions/models.CapturedRequest"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "0d6f2ab4-5c1e-4d0e-9a57-8f3b2c1d4e5f"
+                },
+                "response": {
+                    "$ref": "#/definitions/models.CapturedResponse"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "trigger": {
+                    "type": "string",
+                    "enum": [
+                        "token",
+                        "account"
+                    ],
+                    "example": "token"
+                }
+            }
+        },
+        "models.DebugCaptureSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "captured_at": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-27T15:57:37Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "label": {
+                    "type": "string",
+                    "example": "SUP-1234"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/v1/users"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "0d6f2ab4-5c1e-4d0e-9a57-8f3b2c1d4e5f"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "trigger": {
+                    "type": "string",
+                    "enum": [
+                        "token",
+                        "account"
+                    ],
+                    "example": "token"
+                }
+            }
+        },
+        "models.DebugCaptureTokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-24T16:57:37Z"
+                },
+                "header": {
+                    "type": "string",
+                    "example": "X-Debug-Capture"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "SUP-1234"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeleteUserResponse": {
+            "type": "object",
+            "properties": {
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "deletionScheduledAt": {
+                    "type": "string",
+                    "example": "2026-11-15T15:57:37+07:00"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "models.EmailChangeResponse": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "description": "the account uses the new email",
+                    "type": "boolean",
+                    "example": false
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2026-01-25T15:57:37+07:00"
+                },
+                "newConfirmed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "newEmail": {
+                    "type": "string",
+                    "example": "john.new@example.com"
+                },
+                "oldConfirmed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "emailChange"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "errors": {},
+                "message": {
+                    "type": "string",
+                    "example": "user not found"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/models.Metadata"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "DATA_NOT_FOUND"
+                }
+            }
+        },
+        "models.ErrorValidationResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "MISSING_FIELD"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "email is required"
+                }
+            }
+        },
+        "models.GetUserByAccountNumberResponse": {
+            "type": "object",
+            "properties": {
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                },
+                "version": {
+                    "description": "send back in UpdateUserRequest",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.GetUserTokenRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "john.doe"
+                }
+            }
+        },
+        "models.GetUserTokenResponse": {
+            "type": "object",
+            "properties": {
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
                     "example": "John Doe"
                 },
-                "phoneNumber": {
-                    "$ref": "#/definitions/models.PhoneNumber"
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Token"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "models.HealthConcurrencyResponse": {
+            "type": "object",
+            "properties": {
+                "admitted": {
+                    "description": "requests served since startup",
+                    "type": "integer",
+                    "example": 120345
+                },
+                "avgWaitMs": {
+                    "description": "queue wait of the admitted requests, on average",
+                    "type": "integer",
+                    "example": 3
+                },
+                "inFlight": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "route": {
+                    "description": "\"*\" for every request",
+                    "type": "string",
+                    "example": "GET /api/v1/admin/users/export"
+                },
+                "shed": {
+                    "description": "requests turned away with 503 since startup",
+                    "type": "integer",
+                    "example": 17
+                },
+                "waiting": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "models.HealthDetailResponse": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string"
+                },
+                "concurrency": {
+                    "description": "Concurrency is reported by the concurrency limits",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HealthConcurrencyResponse"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "leader": {
+                    "description": "Leader is reported by the leader election",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HealthLeaderResponse"
+                        }
+                    ]
+                },
+                "pool": {
+                    "description": "Pool and Replication are reported by PostgreSQL",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HealthPoolResponse"
+                        }
+                    ]
+                },
+                "replication": {
+                    "$ref": "#/definitions/models.HealthReplicationResponse"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HealthLeaderResponse": {
+            "type": "object",
+            "properties": {
+                "election": {
+                    "type": "string",
+                    "example": "jobs"
+                },
+                "leading": {
+                    "description": "runs the singleton background jobs",
+                    "type": "boolean",
+                    "example": true
+                },
+                "since": {
+                    "description": "when it became leader or follower",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.HealthPoolResponse": {
+            "type": "object",
+            "properties": {
+                "idle": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "inUse": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "maxOpen": {
+                    "description": "0 is unlimited",
+                    "type": "integer",
+                    "example": 100
+                },
+                "open": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "waitCount": {
+                    "description": "connections waited for since startup",
+                    "type": "integer",
+                    "example": 3
+                },
+                "waitDurationMs": {
+                    "description": "total time waited for them",
+                    "type": "integer",
+                    "example": 150
+                }
+            }
+        },
+        "models.HealthReplicationResponse": {
+            "type": "object",
+            "properties": {
+                "lagMs": {
+                    "description": "replay lag of the slowest replica",
+                    "type": "integer",
+                    "example": 40
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.HeapDumpResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "/var/dumps/heap-api-1-1234-20260101T000000Z.pb.gz"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "heap-api-1-1234-20260101T000000Z.pb.gz"
+                },
+                "size": {
+                    "description": "in bytes",
+                    "type": "integer",
+                    "example": 204800
+                }
+            }
+        },
+        "models.Links": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string",
+                    "example": "/api/v1/users?page=3"
+                },
+                "prev": {
+                    "type": "string",
+                    "example": "/api/v1/users?page=1"
+                },
+                "self": {
+                    "type": "string",
+                    "example": "/api/v1/users?page=2"
+                }
+            }
+        },
+        "models.LogLevelResponse": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string"
+                },
+                "pathLevels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.LogStatsResponse": {
+            "type": "object",
+            "properties": {
+                "async": {
+                    "$ref": "#/definitions/logger.AsyncStats"
+                },
+                "truncation": {
+                    "$ref": "#/definitions/logger.TruncationStats"
+                }
+            }
+        },
+        "models.LoginEventResponse": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string",
+                    "example": "Safari"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "London"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "GB"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "device": {
+                    "type": "string",
+                    "example": "mobile"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "81.2.69.142"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "iOS"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "new_country"
+                    ]
+                },
+                "suspicious": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "loginEvent"
+                }
+            }
+        },
+        "models.MaintenanceResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "retry_after": {
+                    "type": "string"
+                },
+                "since": {
+                    "description": "when it was last turned on or off",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "config",
+                        "admin"
+                    ]
+                }
+            }
+        },
+        "models.Metadata": {
+            "type": "object",
+            "properties": {
+                "requestId": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "totalRows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotificationPreferencesResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sms": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "type": {
+                    "type": "string",
+                    "example": "notificationPreferences"
+                },
+                "updatedAt": {
+                    "description": "null until changed",
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                }
+            }
+        },
+        "models.PaginationOutput": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PhoneChangeResponse": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "description": "the account uses the new number",
+                    "type": "boolean",
+                    "example": false
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2026-01-24T16:07:37+07:00"
+                },
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "phoneChange"
+                }
+            }
+        },
+        "models.PhoneNumber": {
+            "type": "object",
+            "properties": {
+                "countryCode": {
+                    "type": "string",
+                    "example": "ID"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "6281234567890"
+                }
+            }
+        },
+        "models.QuotaOverrideResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "false once expired",
+                    "type": "boolean",
+                    "example": true
+                },
+                "client": {
+                    "type": "string",
+                    "example": "partner-a"
+                },
+                "daily_quota": {
+                    "type": "integer",
+                    "x-nullable": true,
+                    "example": 20000
+                },
+                "expires_at": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2026-02-01T00:00:00Z"
+                },
+                "monthly_quota": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "SUP-1234 launch week"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                }
+            }
+        },
+        "models.QuotaResponse": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "description": "empty for authorization.api_key, which has no quota",
+                    "type": "string",
+                    "example": "partner-a"
+                },
+                "quotas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuotaUsageResponse"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "quota"
+                }
+            }
+        },
+        "models.QuotaUsageResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "monthly"
+                    ],
+                    "example": "daily"
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 8750
+                },
+                "resetAt": {
+                    "type": "string",
+                    "example": "2026-01-25T00:00:00Z"
+                },
+                "used": {
+                    "type": "integer",
+                    "example": 1250
+                }
+            }
+        },
+        "models.ResolvePIITokenRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "token"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "e.g. the incident ticket, recorded in the audit log",
+                    "type": "string",
+                    "minLength": 10
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResolvePIITokenResponse": {
+            "type": "object",
+            "properties": {
+                "pii_value": {
+                    "description": "masked by name in the request log",
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {},
+                "errors": {},
+                "links": {
+                    "$ref": "#/definitions/models.Links"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Request has been successfully processed."
+                },
+                "metadata": {
+                    "$ref": "#/definitions/models.Metadata"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.PaginationOutput"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "models.RuntimeGCResponse": {
+            "type": "object",
+            "properties": {
+                "cpuFraction": {
+                    "description": "of the CPU time used by the GC since startup",
+                    "type": "number",
+                    "example": 0.001
+                },
+                "lastGC": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "lastPauseMs": {
+                    "type": "number",
+                    "example": 0.3
+                },
+                "nextGC": {
+                    "description": "heap size triggering the next cycle, in bytes",
+                    "type": "integer",
+                    "example": 16777216
+                },
+                "numForcedGC": {
+                    "description": "by runtime.GC, e.g. a heap dump",
+                    "type": "integer",
+                    "example": 1
+                },
+                "numGC": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "pauseTotalMs": {
+                    "type": "number",
+                    "example": 35.2
+                }
+            }
+        },
+        "models.RuntimeMemoryResponse": {
+            "type": "object",
+            "properties": {
+                "frees": {
+                    "description": "since startup",
+                    "type": "integer",
+                    "example": 1148800
+                },
+                "heapAlloc": {
+                    "description": "of the live objects, and the unswept dead ones",
+                    "type": "integer",
+                    "example": 8388608
+                },
+                "heapIdle": {
+                    "description": "of the idle spans, returnable to the OS",
+                    "type": "integer",
+                    "example": 4194304
+                },
+                "heapInuse": {
+                    "description": "of the spans in use",
+                    "type": "integer",
+                    "example": 10485760
+                },
+                "heapObjects": {
+                    "description": "allocated",
+                    "type": "integer",
+                    "example": 51200
+                },
+                "mallocs": {
+                    "description": "since startup",
+                    "type": "integer",
+                    "example": 1200000
+                },
+                "stackInuse": {
+                    "description": "of the goroutine stacks",
+                    "type": "integer",
+                    "example": 1048576
+                },
+                "sys": {
+                    "description": "obtained from the OS",
+                    "type": "integer",
+                    "example": 25165824
                 },
-                "type": {
+                "totalAlloc": {
+                    "description": "allocated since startup",
+                    "type": "integer",
+                    "example": 536870912
+                }
+            }
+        },
+        "models.RuntimeResponse": {
+            "type": "object",
+            "properties": {
+                "cpus": {
+                    "description": "usable by the process, GOMAXPROCS",
+                    "type": "integer",
+                    "example": 4
+                },
+                "gc": {
+                    "$ref": "#/definitions/models.RuntimeGCResponse"
+                },
+                "goVersion": {
                     "type": "string",
-                    "example": "user"
+                    "example": "go1.24.4"
                 },
-                "updatedAt": {
+                "goroutines": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "memory": {
+                    "$ref": "#/definitions/models.RuntimeMemoryResponse"
+                },
+                "uptime": {
                     "type": "string",
-                    "example": "2026-01-24T15:57:37+07:00"
+                    "example": "72h3m0s"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.SaveQuotaOverrideRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
+                "daily_quota": {
+                    "description": "null keeps the configured quota, 0 is unlimited",
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "errors": {},
-                "message": {
+                "expires_at": {
+                    "description": "the configured quotas apply again after; null never expires",
                     "type": "string"
                 },
-                "metadata": {
-                    "$ref": "#/definitions/models.Metadata"
+                "monthly_quota": {
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "status": {
-                    "type": "string"
+                "reason": {
+                    "description": "e.g. the support ticket, recorded in the audit log",
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
-        "models.GetUserByAccountNumberResponse": {
+        "models.Token": {
             "type": "object",
             "properties": {
-                "accountNumber": {
-                    "type": "string",
-                    "example": "1234567890"
+                "expiredIn": {
+                    "type": "integer",
+                    "example": 300
                 },
-                "createdAt": {
+                "token": {
                     "type": "string",
-                    "example": "2026-01-24T15:57:37+07:00"
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 },
-                "email": {
+                "type": {
                     "type": "string",
-                    "example": "john.doe@example.com"
+                    "example": "accessToken"
+                }
+            }
+        },
+        "models.UpdateConsentRequest": {
+            "type": "object",
+            "properties": {
+                "marketingOptIn": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "name": {
+                "termsVersion": {
                     "type": "string",
-                    "example": "John Doe"
-                },
-                "phoneNumber": {
-                    "$ref": "#/definitions/models.PhoneNumber"
-                },
-                "type": {
+                    "maxLength": 32,
+                    "example": "2026-10-01"
+                }
+            }
+        },
+        "models.UpdateLogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
                     "type": "string",
-                    "example": "user"
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ]
+                }
+            }
+        },
+        "models.UpdateMaintenanceRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
                 },
-                "updatedAt": {
+                "message": {
                     "type": "string",
-                    "example": "2026-01-24T15:57:37+07:00"
+                    "maxLength": 255
+                },
+                "retry_after": {
+                    "description": "e.g. \"10m\", sent in Retry-After; defaults to 5m",
+                    "type": "string"
                 }
             }
         },
-        "models.GetUserTokenRequest": {
+        "models.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "sms": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.UpdateUserRequest": {
             "type": "object",
             "required": [
-                "password"
+                "name",
+                "version"
             ],
             "properties": {
-                "email": {
+                "name": {
                     "type": "string",
-                    "example": "john.doe@example.com"
+                    "maxLength": 255,
+                    "example": "John Doe"
                 },
-                "password": {
+                "version": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "models.UserContact": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string",
-                    "example": "password123"
+                    "x-nullable": true,
+                    "example": "john.doe@example.com"
                 },
                 "phoneNumber": {
-                    "$ref": "#/definitions/models.PhoneNumber"
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PhoneNumber"
+                        }
+                    ],
+                    "x-nullable": true
                 }
             }
         },
-        "models.GetUserTokenResponse": {
+        "models.UserExportResponse": {
             "type": "object",
             "properties": {
                 "accountNumber": {
                     "type": "string",
                     "example": "1234567890"
                 },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "deletionScheduledAt": {
+                    "type": "string",
+                    "example": "2026-02-23T15:57:37+07:00"
+                },
                 "email": {
                     "type": "string",
                     "example": "john.doe@example.com"
@@ -390,132 +4219,217 @@ const docTemplate = `{
                 "phoneNumber": {
                     "$ref": "#/definitions/models.PhoneNumber"
                 },
-                "tokens": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Token"
-                    }
-                },
-                "type": {
+                "username": {
                     "type": "string",
-                    "example": "user"
+                    "example": "john.doe"
                 }
             }
         },
-        "models.HealthDetailResponse": {
+        "models.UserImportResponse": {
             "type": "object",
             "properties": {
-                "component": {
-                    "type": "string"
+                "failed": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "description": {
-                    "type": "string"
+                "imported": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "status": {
-                    "type": "string"
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserImportRowResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
                 },
                 "type": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "userImport"
                 }
             }
         },
-        "models.Metadata": {
+        "models.UserImportRowResponse": {
             "type": "object",
             "properties": {
-                "requestId": {
-                    "type": "string"
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
                 },
-                "timestamp": {
-                    "type": "string"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ErrorValidationResponse"
+                    }
                 },
-                "totalRows": {
-                    "type": "integer"
+                "line": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "imported",
+                        "failed"
+                    ],
+                    "example": "failed"
                 }
             }
         },
-        "models.PaginationOutput": {
+        "models.UserSearchResponse": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
                 },
-                "next": {
-                    "type": "string"
+                "createdAt": {
+                    "type": "string",
+                    "example": "2026-01-24T15:57:37+07:00"
                 },
-                "prev": {
-                    "type": "string"
+                "deletionScheduledAt": {
+                    "type": "string",
+                    "example": "2026-02-23T15:57:37+07:00"
                 },
-                "total": {
-                    "type": "integer"
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "phoneNumber": {
+                    "$ref": "#/definitions/models.PhoneNumber"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "type": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
                 }
             }
         },
-        "models.PhoneNumber": {
+        "models.UserSummaryResponse": {
             "type": "object",
             "properties": {
-                "countryCode": {
+                "accountNumber": {
                     "type": "string",
-                    "example": "ID"
+                    "example": "1234567890"
                 },
-                "number": {
+                "createdAt": {
                     "type": "string",
-                    "example": "6281234567890"
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "user"
                 }
             }
         },
-        "models.Response": {
+        "models.UserV2Response": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 200
+                "accountNumber": {
+                    "type": "string",
+                    "example": "1234567890"
                 },
-                "data": {},
-                "errors": {},
-                "message": {
+                "contact": {
+                    "$ref": "#/definitions/models.UserContact"
+                },
+                "createdAt": {
                     "type": "string",
-                    "example": "Request has been successfully processed."
+                    "example": "2026-01-24T15:57:37+07:00"
                 },
-                "metadata": {
-                    "$ref": "#/definitions/models.Metadata"
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
                 },
-                "pagination": {
-                    "$ref": "#/definitions/models.PaginationOutput"
+                "type": {
+                    "type": "string",
+                    "example": "user"
                 },
-                "status": {
+                "updatedAt": {
                     "type": "string",
-                    "example": "OK"
+                    "example": "2026-01-24T15:57:37+07:00"
+                },
+                "username": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "john.doe"
                 }
             }
         },
-        "models.Token": {
+        "models.UsernameAvailabilityResponse": {
             "type": "object",
             "properties": {
-                "expiredIn": {
-                    "type": "integer",
-                    "example": 300
+                "available": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "token": {
+                "reason": {
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                    "enum": [
+                        "invalid",
+                        "reserved",
+                        "taken"
+                    ],
+                    "example": "taken"
                 },
                 "type": {
                     "type": "string",
-                    "example": "accessToken"
+                    "example": "username_availability"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "models.VerifyPhoneChangeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "042917"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access token from POST /api/v1/users/tokens, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
-	Host:             "",
-	BasePath:         "",
-	Schemes:          []string{},
-	Title:            "",
-	Description:      "",
+	Version:          "1.0",
+	Host:             "localhost:8080",
+	BasePath:         "/",
+	Schemes:          []string{"http", "https"},
+	Title:            "GO-ECHO-BOILERPLATE API DOCUMENTATION",
+	Description:      "This is a go-echo-boilerplate api docs.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -3,23 +3,69 @@ package config
 import (
 	"context"
 	"fmt"
+	"go-echo-boilerplate/internal/pkg/buildinfo"
+	"go-echo-boilerplate/internal/pkg/secrets"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix prefixes every environment variable override, e.g. APP_POSTGRESQL_HOST
+// overrides postgresql.host and APP_AUTHORIZATION_ACCESS_SECRET overrides authorization.access.secret.
+const EnvPrefix = "APP"
+
+// secretProviders resolve secret:// references. They are shared with reloads so their clients
+// are created once, while every load resolves with a new secrets.Resolver: a rotated secret is
+// fetched again on the next reload instead of being served from the cache of the first load.
+var secretProviders = secrets.DefaultProviders()
+
+// Directory holds the config files
+const Directory = "./config"
+
+// Initialize loads the configuration for the environment selected by the --env flag,
+// or the ENV variable when the flag isn't set.
+//
+// The environment's file is an overlay on top of an optional shared base file: maps are
+// deep-merged, while scalars and lists in the overlay replace the base value.
+//
+// Values are resolved with the following precedence (highest first):
+//  1. Command line flags: --port, --host, --log-level
+//  2. Environment variables: APP_<KEY> with dots replaced by underscores
+//  3. The environment file: ./config/config.<env>.yaml (optional when a base file exists)
+//  4. The base file: ./config/config.base.yaml (optional)
+//  5. Defaults
+//
+// String values of the form secret://<provider>/<path>[#<key>] are then replaced
+// in the Configuration with the secret from AWS Secrets Manager, GCP Secret Manager, or Vault
+// (see package secrets), whichever layer they come from. Viper keeps the references.
+//
+// The resolved configuration is validated before it is returned, see Configuration.Validate.
 func Initialize(ctx context.Context) (*Configuration, error) {
 	var configuration Configuration
 
-	viper.AutomaticEnv()
-	environment := strings.ToLower(viper.GetString("env"))
-	configName := fmt.Sprintf("config.%s", environment)
+	flags, err := parseFlags(os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	environment, _ := flags.GetString("env")
+	if environment == "" {
+		environment = os.Getenv("ENV")
+	}
+	loadedEnvironment = strings.ToLower(environment)
+
+	if err := readConfigFiles(viper.GetViper(), Directory, loadedEnvironment); err != nil {
+		return nil, err
+	}
 
-	viper.AddConfigPath("./config")
-	viper.SetConfigName(configName)
-	viper.SetConfigType("yaml")
+	bindEnv(viper.GetViper())
 
-	if err := viper.ReadInConfig(); err != nil {
+	if err := bindFlags(viper.GetViper(), flags); err != nil {
 		return nil, err
 	}
 
@@ -27,5 +73,202 @@ func Initialize(ctx context.Context) (*Configuration, error) {
 		return nil, err
 	}
 
+	if err := resolveSecrets(ctx, &configuration, secrets.NewResolver(secretProviders)); err != nil {
+		return nil, err
+	}
+	configuration.stampBuild()
+
+	if err := configuration.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	setCurrent(&configuration)
+
 	return &configuration, nil
 }
+
+// stampBuild sets the build of the binary on Application
+func (c *Configuration) stampBuild() {
+	build := buildinfo.Get()
+	if build.Version != "" {
+		c.Application.Version = build.Version
+	}
+	c.Application.Commit = build.Commit
+	c.Application.BuildTime = build.BuildTime
+	c.Application.GoVersion = build.GoVersion
+}
+
+// loadedEnvironment is the environment selected at startup, reused on reload
+var loadedEnvironment string
+
+// readConfigFiles reads config.base.yaml and merges config.<environment>.yaml on top of it.
+// At least one of the two files must exist. The environment file is read last, so it is
+// the file watched for changes.
+func readConfigFiles(v *viper.Viper, dir, environment string) error {
+	v.SetConfigType("yaml")
+
+	base := filepath.Join(dir, "config.base.yaml")
+	hasBase := fileExists(base)
+	if hasBase {
+		v.SetConfigFile(base)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", base, err)
+		}
+	}
+
+	overlay := filepath.Join(dir, fmt.Sprintf("config.%s.yaml", environment))
+	if !fileExists(overlay) {
+		if hasBase && environment != "" {
+			return nil
+		}
+		return fmt.Errorf("config file %s not found", overlay)
+	}
+
+	v.SetConfigFile(overlay)
+	read := v.ReadInConfig
+	if hasBase {
+		read = v.MergeInConfig
+	}
+	if err := read(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", overlay, err)
+	}
+
+	return nil
+}
+
+// fileExists reports whether path is an existing regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// bindEnv makes every key of Configuration overridable from the environment,
+// including keys that are missing from the config file.
+func bindEnv(v *viper.Viper) {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	v.AutomaticEnv()
+
+	for _, key := range configKeys(reflect.TypeOf(Configuration{}), "") {
+		_ = v.BindEnv(key) // only fails without a key
+	}
+}
+
+// configKeys lists the dotted mapstructure keys of every leaf field in t.
+// Map fields are listed as a single key since their entries aren't known up front.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, key)...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// parseFlags parses the supported command line flags. Unknown flags are ignored so
+// the binary can still be run by tools that pass their own flags.
+func parseFlags(args []string) (*pflag.FlagSet, error) {
+	flags := pflag.NewFlagSet("config", pflag.ContinueOnError)
+	flags.ParseErrorsAllowlist.UnknownFlags = true
+	flags.Usage = func() {}
+
+	flags.String("env", "", "environment whose config file is loaded (overrides ENV)")
+	flags.Int("port", 0, "HTTP port (application.port)")
+	flags.String("host", "", "HTTP host (application.host)")
+	flags.String("log-level", "", "minimum log level (logger.level)")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+// bindFlags binds the config flags that were passed on the command line.
+func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
+	for key, flag := range map[string]string{
+		"application.port": "port",
+		"application.host": "host",
+		"logger.level":     "log-level",
+	} {
+		// Only flags that were actually passed override lower layers
+		if flags.Changed(flag) {
+			if err := v.BindPFlag(key, flags.Lookup(flag)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveSecrets replaces every secret reference among the string values of c, in nested
+// structs, lists, and maps included, with its value.
+// Every reference is attempted so all failures are reported together.
+func resolveSecrets(ctx context.Context, c *Configuration, resolver *secrets.Resolver) error {
+	var errs *multierror.Error
+	resolveValue(reflect.ValueOf(c).Elem(), "", func(key, reference string) (string, bool) {
+		resolved, err := resolver.Resolve(ctx, reference)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", key, err))
+			return "", false
+		}
+		return resolved, true
+	})
+	return errs.ErrorOrNil()
+}
+
+// resolveValue calls resolve with the dotted mapstructure key of every secret reference in
+// value and sets the value it returns. Map entries aren't addressable, so they are resolved
+// on a copy set back into the map.
+func resolveValue(value reflect.Value, key string, resolve func(key, reference string) (string, bool)) {
+	switch value.Kind() {
+	case reflect.String:
+		if secrets.IsReference(value.String()) {
+			if resolved, ok := resolve(key, value.String()); ok {
+				value.SetString(resolved)
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			name := value.Type().Field(i).Tag.Get("mapstructure")
+			if name == "" || name == "-" {
+				continue
+			}
+			if key != "" {
+				name = key + "." + name
+			}
+			resolveValue(value.Field(i), name, resolve)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			resolveValue(value.Index(i), fmt.Sprintf("%s[%d]", key, i), resolve)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			entry := reflect.New(iter.Value().Type()).Elem()
+			entry.Set(iter.Value())
+			resolveValue(entry, fmt.Sprintf("%s.%v", key, iter.Key()), resolve)
+			value.SetMapIndex(iter.Key(), entry)
+		}
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() && value.Elem().CanSet() {
+			resolveValue(value.Elem(), key, resolve)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/pkg/buildinfo"
+	"go-echo-boilerplate/internal/pkg/secrets"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `
+application:
+  port: 8080
+  host: localhost
+postgresql:
+  host: db
+logger:
+  level: info
+`
+
+func newTestViper(t *testing.T) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(testConfigFile)))
+	return v
+}
+
+func TestOverridePrecedence(t *testing.T) {
+	t.Setenv("APP_APPLICATION_PORT", "9090")
+	t.Setenv("APP_LOGGER_LEVEL", "debug")
+	t.Setenv("APP_POSTGRESQL_HOST", "env-db")
+	// Not present in the file at all
+	t.Setenv("APP_AUTHORIZATION_SIGNING_SECRET", "from-env")
+
+	v := newTestViper(t)
+	bindEnv(v)
+	flags, err := parseFlags([]string{"--log-level=warn", "--unknown", "value"})
+	require.NoError(t, err)
+	require.NoError(t, bindFlags(v, flags))
+
+	var configuration Configuration
+	require.NoError(t, v.Unmarshal(&configuration))
+
+	assert.Equal(t, 9090, configuration.Application.Port, "env overrides file")
+	assert.Equal(t, "localhost", configuration.Application.Host, "file is kept without overrides")
+	assert.Equal(t, "env-db", configuration.PostgreSQL.Host)
+	assert.Equal(t, "warn", configuration.Logger.Level, "flag overrides env")
+	assert.Equal(t, "from-env", configuration.Authorization.SigningSecret)
+}
+
+func TestBindFlagsUnsetDoesNotOverride(t *testing.T) {
+	v := newTestViper(t)
+	flags, err := parseFlags(nil)
+	require.NoError(t, err)
+	require.NoError(t, bindFlags(v, flags))
+
+	assert.Equal(t, 8080, v.GetInt("application.port"))
+	assert.Equal(t, "info", v.GetString("logger.level"))
+}
+
+func TestParseFlagsInvalidValue(t *testing.T) {
+	_, err := parseFlags([]string{"--port=abc"})
+	assert.Error(t, err)
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("APP_AUTHORIZATION_ACCESS_SECRET", "secret://fake/jwt#access")
+
+	v := newTestViper(t)
+	v.Set("postgresql.password", "secret://fake/db")
+	bindEnv(v)
+
+	resolver := secrets.NewResolver(map[string]secrets.Provider{
+		"fake": secrets.ProviderFunc(func(ctx context.Context, path string) (string, error) {
+			if path == "jwt" {
+				return `{"access":"jwt-secret"}`, nil
+			}
+			return "db-password", nil
+		}),
+	})
+	var configuration Configuration
+	require.NoError(t, v.Unmarshal(&configuration))
+	require.NoError(t, resolveSecrets(context.Background(), &configuration, resolver))
+
+	assert.Equal(t, "db-password", configuration.PostgreSQL.Password)
+	assert.Equal(t, "jwt-secret", configuration.Authorization.Access.Secret)
+	assert.Equal(t, "db", configuration.PostgreSQL.Host)
+	// The resolved values aren't written back as viper overrides
+	assert.Equal(t, "secret://fake/db", v.GetString("postgresql.password"))
+}
+
+func TestResolveSecretsNested(t *testing.T) {
+	configuration := Configuration{
+		Logger: Logger{Sinks: []LogSink{{Type: "otlp", Headers: map[string]string{"authorization": "secret://fake/otlp"}}}},
+	}
+	configuration.Authorization.Clients = []APIClient{{Name: "partner", Key: "secret://fake/partner"}}
+
+	resolver := secrets.NewResolver(map[string]secrets.Provider{
+		"fake": secrets.ProviderFunc(func(ctx context.Context, path string) (string, error) {
+			return "resolved-" + path, nil
+		}),
+	})
+	require.NoError(t, resolveSecrets(context.Background(), &configuration, resolver))
+
+	assert.Equal(t, "resolved-otlp", configuration.Logger.Sinks[0].Headers["authorization"])
+	assert.Equal(t, "resolved-partner", configuration.Authorization.Clients[0].Key)
+	assert.Equal(t, "partner", configuration.Authorization.Clients[0].Name)
+}
+
+func TestResolveSecretsReportsAllFailures(t *testing.T) {
+	v := newTestViper(t)
+	v.Set("postgresql.password", "secret://missing/db")
+	v.Set("authorization.signing_secret", "secret://missing/signing")
+
+	var configuration Configuration
+	require.NoError(t, v.Unmarshal(&configuration))
+
+	err := resolveSecrets(context.Background(), &configuration, secrets.NewResolver(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "postgresql.password")
+	assert.Contains(t, err.Error(), "authorization.signing_secret")
+}
+
+func TestReadConfigFilesOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.base.yaml"), `
+application:
+  name: app
+  port: 8080
+  timezone: Asia/Jakarta
+postgresql:
+  host: localhost
+  port: 5432
+cors:
+  headers_allowed: ["X-Api-Key", "X-Request-Id"]
+`)
+	writeFile(t, filepath.Join(dir, "config.prod.yaml"), `
+application:
+  port: 80
+postgresql:
+  host: prod-db
+cors:
+  headers_allowed: ["X-Api-Key"]
+`)
+
+	v := viper.New()
+	require.NoError(t, readConfigFiles(v, dir, "prod"))
+
+	var configuration Configuration
+	require.NoError(t, v.Unmarshal(&configuration))
+
+	assert.Equal(t, "app", configuration.Application.Name, "kept from base")
+	assert.Equal(t, "Asia/Jakarta", configuration.Application.Timezone, "kept from base")
+	assert.Equal(t, 80, configuration.Application.Port, "overridden by overlay")
+	assert.Equal(t, "prod-db", configuration.PostgreSQL.Host)
+	assert.Equal(t, 5432, configuration.PostgreSQL.Port)
+	assert.Equal(t, []string{"X-Api-Key"}, configuration.CORS.HeadersAllowed, "lists are replaced, not appended")
+}
+
+func TestReadConfigFilesMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	// Neither file exists
+	assert.Error(t, readConfigFiles(viper.New(), dir, "dev"))
+
+	// The overlay is optional once a base exists
+	writeFile(t, filepath.Join(dir, "config.base.yaml"), "application:\n  name: app\n")
+	v := viper.New()
+	require.NoError(t, readConfigFiles(v, dir, "staging"))
+	assert.Equal(t, "app", v.GetString("application.name"))
+
+	// Without a base the environment file is required, and works on its own
+	only := t.TempDir()
+	writeFile(t, filepath.Join(only, "config.local.yaml"), "application:\n  name: local\n")
+	v = viper.New()
+	require.NoError(t, readConfigFiles(v, only, "local"))
+	assert.Equal(t, "local", v.GetString("application.name"))
+}
+
+func TestStampBuild(t *testing.T) {
+	configuration := Configuration{Application: Application{Version: "1.0.0"}}
+	configuration.stampBuild()
+	assert.Equal(t, "1.0.0", configuration.Application.Version, "kept without an injected version")
+	assert.Equal(t, runtime.Version(), configuration.Application.GoVersion)
+
+	t.Cleanup(func() { buildinfo.Version, buildinfo.Commit = "", "" })
+	buildinfo.Version, buildinfo.Commit = "v1.2.0", "abc123"
+	configuration.stampBuild()
+	assert.Equal(t, "v1.2.0", configuration.Application.Version)
+	assert.Equal(t, "abc123", configuration.Application.Commit)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
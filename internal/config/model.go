@@ -1,11 +1,45 @@
 package config
 
+import "fmt"
+
 type (
 	Configuration struct {
-		Application   Application   `mapstructure:"application"`
-		PostgreSQL    PostgreSQL    `mapstructure:"postgresql"`
-		Authorization Authorization `mapstructure:"authorization"`
-		CORS          CORS          `mapstructure:"cors"`
+		Application     Application     `mapstructure:"application"`
+		PostgreSQL      PostgreSQL      `mapstructure:"postgresql"`
+		Health          Health          `mapstructure:"health"`
+		Startup         Startup         `mapstructure:"startup"`
+		Redis           Redis           `mapstructure:"redis"`
+		Authorization   Authorization   `mapstructure:"authorization"`
+		CORS            CORS            `mapstructure:"cors"`
+		CSRF            CSRF            `mapstructure:"csrf"`
+		Logger          Logger          `mapstructure:"logger"`
+		Server          Server          `mapstructure:"server"`
+		Password        Password        `mapstructure:"password"`
+		Hash            Hash            `mapstructure:"hash"`
+		AccountNumber   AccountNumber   `mapstructure:"account_number"`
+		PII             PII             `mapstructure:"pii"`
+		ClientInfo      ClientInfo      `mapstructure:"client_info"`
+		LoginAlerts     LoginAlerts     `mapstructure:"login_alerts"`
+		LoginThrottle   LoginThrottle   `mapstructure:"login_throttle"`
+		Captcha         Captcha         `mapstructure:"captcha"`
+		Consent         Consent         `mapstructure:"consent"`
+		Email           Email           `mapstructure:"email"`
+		Username        Username        `mapstructure:"username"`
+		Deletion        Deletion        `mapstructure:"account_deletion"`
+		EmailChange     EmailChange     `mapstructure:"email_change"`
+		PhoneChange     PhoneChange     `mapstructure:"phone_change"`
+		Notifications   Notifications   `mapstructure:"notifications"`
+		Response        Response        `mapstructure:"response"`
+		ResponseCache   ResponseCache   `mapstructure:"response_cache"`
+		RepositoryCache RepositoryCache `mapstructure:"repository_cache"`
+		Lock            Lock            `mapstructure:"lock"`
+		Leader          Leader          `mapstructure:"leader"`
+		Maintenance     Maintenance     `mapstructure:"maintenance"`
+		Concurrency     Concurrency     `mapstructure:"concurrency"`
+		Quota           Quota           `mapstructure:"quota"`
+		DebugCapture    DebugCapture    `mapstructure:"debug_capture"`
+		Diagnostics     Diagnostics     `mapstructure:"diagnostics"`
+		Docs            Docs            `mapstructure:"docs"`
 
 		Google Google `mapstructure:"google"`
 	}
@@ -18,10 +52,364 @@ type (
 		Host        string `mapstructure:"host"`
 		Timeout     int    `mapstructure:"timeout"`
 		Timezone    string `mapstructure:"timezone"`
+		Mode        string `mapstructure:"mode"`   // live (default), or mock: in-memory repositories and the documented operations answering their OpenAPI examples
+		Listen      string `mapstructure:"listen"` // instead of port: "host:port", "unix:/path/to.sock", or "systemd[:name]" for a socket-activated one
+
+		// The build of the binary, see package buildinfo; a version injected at build time
+		// replaces Version
+		Commit    string `mapstructure:"-"`
+		BuildTime string `mapstructure:"-"`
+		GoVersion string `mapstructure:"-"`
+	}
+
+	// Startup gates the start of the server on its dependencies: the readiness checks
+	// (PostgreSQL, Redis, and the migrations when required) must pass before it accepts traffic
+	Startup struct {
+		ReadinessTimeout  string `mapstructure:"readiness_timeout"`  // how long the checks may take to pass, retried every second; defaults to 30s
+		RequireMigrations bool   `mapstructure:"require_migrations"` // wait until every migration is applied, e.g. by a migrate job
+	}
+
+	// Server tunes the HTTP server; unset timeouts fall back to the core defaults (never "no timeout")
+	Server struct {
+		MaxBodySize       string      `mapstructure:"max_body_size"`       // e.g. "1MB", "512KB"; defaults to 1MB
+		ReadTimeout       string      `mapstructure:"read_timeout"`        // whole request, including the body; defaults to 30s
+		ReadHeaderTimeout string      `mapstructure:"read_header_timeout"` // request headers; defaults to 5s
+		WriteTimeout      string      `mapstructure:"write_timeout"`       // from the end of the request headers to the end of the response; defaults to 30s
+		IdleTimeout       string      `mapstructure:"idle_timeout"`        // keep-alive; defaults to 120s
+		MaxHeaderBytes    string      `mapstructure:"max_header_bytes"`    // e.g. "1MB", defaults to 1MB
+		HTTP2             HTTP2       `mapstructure:"http2"`
+		TLS               TLS         `mapstructure:"tls"`
+		Compression       Compression `mapstructure:"compression"`
+	}
+
+	// Compression compresses responses with gzip, or Brotli when enabled and accepted by the client
+	Compression struct {
+		Enabled      bool     `mapstructure:"enabled"`
+		MinSize      string   `mapstructure:"min_size"`      // smaller responses are sent as is; defaults to 1KB
+		ContentTypes []string `mapstructure:"content_types"` // e.g. "application/json", "text/*"; defaults to text, JSON, JavaScript, XML, and SVG
+		Level        int      `mapstructure:"level"`         // gzip, 1 (fastest) to 9 (smallest); defaults to 6
+		Brotli       bool     `mapstructure:"brotli"`        // prefer br over gzip when the client accepts it
+		BrotliLevel  int      `mapstructure:"brotli_level"`  // 1 (fastest) to 11 (smallest); defaults to 4
+	}
+
+	// HTTP2 configures HTTP/2. Over TLS it is negotiated automatically; H2C serves
+	// HTTP/2 over cleartext, for deployments behind a proxy or load balancer that speaks it.
+	// The limits below apply to both.
+	HTTP2 struct {
+		H2C                  bool   `mapstructure:"h2c"`
+		MaxConcurrentStreams uint32 `mapstructure:"max_concurrent_streams"` // per connection; defaults to 250
+		MaxReadFrameSize     string `mapstructure:"max_read_frame_size"`    // e.g. "1MiB"; at least 16KiB and below 16MiB, defaults to 1MiB
+		IdleTimeout          string `mapstructure:"idle_timeout"`           // defaults to server.idle_timeout
+	}
+
+	// TLS serves HTTPS on application.port, with either a static certificate or one
+	// managed by autocert (Let's Encrypt). It is off when neither is configured.
+	TLS struct {
+		CertFile     string   `mapstructure:"cert_file"`     // PEM, with key_file
+		KeyFile      string   `mapstructure:"key_file"`      // PEM, with cert_file
+		Autocert     Autocert `mapstructure:"autocert"`      // instead of cert_file/key_file
+		RedirectPort int      `mapstructure:"redirect_port"` // plain HTTP port that redirects to HTTPS, e.g. 80; 0 disables
+	}
+
+	Autocert struct {
+		Enabled  bool     `mapstructure:"enabled"`
+		Hosts    []string `mapstructure:"hosts"`     // certificates are only requested for these hosts
+		Email    string   `mapstructure:"email"`     // optional, for expiry notices from the CA
+		CacheDir string   `mapstructure:"cache_dir"` // keeps certificates across restarts, e.g. "/var/cache/autocert"
+	}
+
+	Hash struct {
+		Cost   int    `mapstructure:"cost"` // bcrypt cost, defaults to 12
+		Pepper Pepper `mapstructure:"pepper"`
+	}
+
+	// Pepper secrets are never stored in config; they are read from <env_prefix><version> environment variables
+	Pepper struct {
+		CurrentVersion int    `mapstructure:"current_version"` // 0 disables peppering
+		EnvPrefix      string `mapstructure:"env_prefix"`      // defaults to HASH_PEPPER_V
+	}
+
+	AccountNumber struct {
+		MaxRetries int                             `mapstructure:"max_retries"` // attempts after a collision, defaults to 5
+		Profile    string                          `mapstructure:"profile"`     // profile used for new users, defaults to card16
+		Profiles   map[string]AccountNumberProfile `mapstructure:"profiles"`    // custom profiles, added to the built-in card16 and iban-like
+	}
+
+	AccountNumberProfile struct {
+		Length     int      `mapstructure:"length"`
+		Prefixes   []string `mapstructure:"prefixes"`
+		CheckDigit string   `mapstructure:"check_digit"` // luhn or mod97
+	}
+
+	// PII tokenizes personal data in logs (masking strategy "pii"); admins resolve tokens
+	// back with POST /admin/pii/resolve. Read at startup only.
+	PII struct {
+		Key        string `mapstructure:"key"`         // HMAC key of the tokens, the pii strategy fully masks when empty
+		MaxEntries int    `mapstructure:"max_entries"` // tokens kept resolvable in memory, defaults to 100000
+		TTL        string `mapstructure:"ttl"`         // how long a token stays resolvable after it was logged, defaults to 24h
+	}
+
+	// ClientInfo adds the client's location and device to wide events ("client") and login
+	// audit records, e.g. to spot logins from a new country or device. Read at startup only.
+	ClientInfo struct {
+		GeoIPDatabase string `mapstructure:"geoip_database"` // MaxMind GeoIP2/GeoLite2 City or Country .mmdb file; empty disables locations
+		UserAgent     bool   `mapstructure:"user_agent"`     // parse the User-Agent into device, OS, and browser
+	}
+
+	// LoginAlerts records the logins of every account and flags the ones from a country or
+	// device the account never logged in from, notifying its owner. Read at startup only.
+	LoginAlerts struct {
+		Enabled bool `mapstructure:"enabled"` // requires client_info, which tells the country and device
+	}
+
+	// LoginThrottle slows down and then locks out repeated failed logins on
+	// POST /api/v1/users/tokens, counted per identifier (email or phone number) and per client
+	// IP within a sliding window. Read at startup only.
+	LoginThrottle struct {
+		Enabled        bool   `mapstructure:"enabled"`
+		Store          string `mapstructure:"store"`           // memory (default) or redis, which requires redis.addr
+		Window         string `mapstructure:"window"`          // failures older than this are forgotten; defaults to 15m
+		MaxFailures    int    `mapstructure:"max_failures"`    // per identifier before it is locked; defaults to 10
+		MaxIPFailures  int    `mapstructure:"max_ip_failures"` // per IP before it is locked; defaults to 50
+		FreeFailures   int    `mapstructure:"free_failures"`   // before each attempt has to wait; defaults to 3
+		BaseDelay      string `mapstructure:"base_delay"`      // wait after the first delayed failure, doubling after each one; defaults to 1s
+		MaxDelay       string `mapstructure:"max_delay"`       // defaults to 1m
+		ChallengeAfter int    `mapstructure:"challenge_after"` // failures before a CAPTCHA is required; 0 disables
+	}
+
+	// Captcha requires an answered CAPTCHA (the X-Captcha-Token header) on the listed routes
+	// and on logins past login_throttle.challenge_after. Read at startup only.
+	Captcha struct {
+		Provider  string   `mapstructure:"provider"`   // recaptcha, hcaptcha, or turnstile; empty disables CAPTCHAs
+		Secret    string   `mapstructure:"secret"`     // the provider's secret key
+		MinScore  float64  `mapstructure:"min_score"`  // reCAPTCHA v3 answers scoring below this fail, 0-1; 0 accepts any
+		VerifyURL string   `mapstructure:"verify_url"` // overrides the provider's siteverify endpoint
+		Timeout   string   `mapstructure:"timeout"`    // per verification; defaults to 5s
+		Routes    []string `mapstructure:"routes"`     // "METHOD /path" as registered, e.g. "POST /api/v1/users"
+	}
+
+	// Consent tracks the terms version each account accepted and its marketing choice.
+	// Read at startup only.
+	Consent struct {
+		// TermsVersion is the current terms, which signups must accept and routes wrapped in
+		// middleware.RequireConsent require; empty requires none
+		TermsVersion string `mapstructure:"terms_version"`
+	}
+
+	// Email tunes how the email addresses of signups, logins, and email changes are
+	// normalized before uniqueness checks, and which domains are refused. Read at startup only.
+	Email struct {
+		// FoldGmail stores Gmail addresses as the mailbox they deliver to, dropping dots and
+		// "+tags" and mapping googlemail.com to gmail.com
+		FoldGmail bool `mapstructure:"fold_gmail"`
+		// DisposableDomains refuses signups and email changes to these domains and their
+		// subdomains, along with the domains listed in DisposableDomainsFile (one per line)
+		DisposableDomains     []string `mapstructure:"disposable_domains"`
+		DisposableDomainsFile string   `mapstructure:"disposable_domains_file"`
+	}
+
+	// Username tunes the optional usernames of accounts
+	Username struct {
+		// Reserved are refused as usernames on top of validator.DefaultReservedUsernames, e.g.
+		// the name of the product
+		Reserved []string `mapstructure:"reserved"`
+	}
+
+	// Deletion schedules DELETE /api/v1/users/me after a grace period, during which signing in
+	// cancels it; a background sweep then anonymizes the account. Read at startup only.
+	Deletion struct {
+		GracePeriod string `mapstructure:"grace_period"` // defaults to 720h (30 days)
+		Interval    string `mapstructure:"interval"`     // between sweeps of the accounts due; defaults to 1h, "0" disables the sweep
+		BatchSize   int    `mapstructure:"batch_size"`   // accounts anonymized per sweep; defaults to 100
+	}
+
+	// EmailChange tunes POST /api/v1/users/me/email, which switches the email once both the old
+	// and the new address confirmed it with the signed token emailed to them. It needs
+	// authorization.signing_secret and a notifications.smtp channel.
+	EmailChange struct {
+		TTL string `mapstructure:"ttl"` // how long the confirmation tokens are valid; defaults to 24h
+		// ConfirmURL is the page the emails link to, the token is appended as its token query
+		// parameter; empty emails the bare token
+		ConfirmURL string `mapstructure:"confirm_url"`
+	}
+
+	// PhoneChange tunes PUT /api/v1/users/me/phone, which switches the phone number once the
+	// code texted to the new number is verified. It needs authorization.signing_secret and a
+	// notifications.sms channel.
+	PhoneChange struct {
+		TTL         string `mapstructure:"ttl"`          // how long a code is valid; defaults to 10m
+		MaxAttempts int    `mapstructure:"max_attempts"` // wrong codes before a new one must be requested; defaults to 5
+	}
+
+	// Notifications are the channels of user and system notifications; each is disabled
+	// when its address is empty. Read at startup only.
+	Notifications struct {
+		Webhook     NotificationWebhook `mapstructure:"webhook"`
+		SMTP        NotificationSMTP    `mapstructure:"smtp"`
+		SMS         NotificationSMS     `mapstructure:"sms"`
+		MaxAttempts int                 `mapstructure:"max_attempts"` // deliveries before a notification is dropped; defaults to 3
+		Timeout     string              `mapstructure:"timeout"`      // per delivery; defaults to 10s
+	}
+
+	NotificationWebhook struct {
+		URL    string `mapstructure:"url"`    // receives every notification as a JSON POST
+		Secret string `mapstructure:"secret"` // signs the body (X-Webhook-Signature); optional
+	}
+
+	// NotificationSMTP sends email notifications to the user's address
+	NotificationSMTP struct {
+		Addr     string `mapstructure:"addr"` // host:port
+		From     string `mapstructure:"from"` // e.g. "Security <security@example.com>"
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password"`
+	}
+
+	// NotificationSMS sends text messages to the user's phone number through an HTTP gateway
+	NotificationSMS struct {
+		URL   string `mapstructure:"url"`   // receives {"to", "text", "event"} as a JSON POST
+		Token string `mapstructure:"token"` // sent as a bearer token; optional
+	}
+
+	Password struct {
+		MinScore    int         `mapstructure:"min_score"` // 0-4, 0 disables strength scoring
+		BreachCheck BreachCheck `mapstructure:"breach_check"`
+	}
+
+	BreachCheck struct {
+		Enabled    bool   `mapstructure:"enabled"`
+		APIURL     string `mapstructure:"api_url"`
+		Timeout    string `mapstructure:"timeout"`
+		MaxRetries int    `mapstructure:"max_retries"`
+	}
+
+	Logger struct {
+		Level string `mapstructure:"level"` // debug, info, warn, error; defaults by environment
+
+		// PathLevels overrides the level for matching request paths, e.g. "/api/v1/users/*": "debug".
+		// A trailing * matches any path with that prefix.
+		PathLevels map[string]string `mapstructure:"path_levels"`
+
+		// SuccessSampleRate is the fraction (0-1] of 2xx/3xx wide events to emit; errors and warnings
+		// are always emitted. 0 or unset emits every event.
+		SuccessSampleRate float64 `mapstructure:"success_sample_rate"`
+
+		// AccessLog emits a compact access log line (method, path, status, duration, request ID)
+		// for every request, in addition to the wide event.
+		AccessLog bool `mapstructure:"access_log"`
+
+		// Sinks are the log destinations, every entry is written to all of them. Defaults to stdout.
+		Sinks []LogSink `mapstructure:"sinks"`
+
+		// Async queues entries and writes them to the sinks from a background goroutine.
+		Async LogAsync `mapstructure:"async"`
+
+		// Limits bound the business data of each wide event.
+		Limits LogLimits `mapstructure:"limits"`
+
+		// Masking adds value patterns and JSON paths to the field-name masking of logged data.
+		Masking Masking `mapstructure:"masking"`
+
+		// Performance flags slow requests and large responses on their wide events.
+		Performance LogPerformance `mapstructure:"performance"`
+
+		// BodyCapture controls when request bodies are added to wide events.
+		BodyCapture LogBodyCapture `mapstructure:"body_capture"`
+
+		// Headers picks the request and response headers added to wide events.
+		Headers LogHeaders `mapstructure:"headers"`
 	}
 
+	LogHeaders struct {
+		Mode  string   `mapstructure:"mode"`  // deny (default): every header, sensitive ones masked; allow: only Allow
+		Allow []string `mapstructure:"allow"` // case-insensitive; defaults to Accept, Content-Type, Content-Length, Content-Encoding, User-Agent, X-Request-ID
+	}
+
+	// LogBodyCapture picks a mode per request: the most specific path pattern, then the
+	// mode of the environment, then Mode. Modes are always (default), errors, or off.
+	LogBodyCapture struct {
+		Mode         string            `mapstructure:"mode"`         // errors captures the body of status >= 400 responses only
+		Environments map[string]string `mapstructure:"environments"` // mode per application.environment, e.g. prod: errors
+		Paths        map[string]string `mapstructure:"paths"`        // mode per request path, e.g. "/api/v1/uploads/*": off
+	}
+
+	LogPerformance struct {
+		SlowRequest   string `mapstructure:"slow_request"`   // duration, e.g. "1s"; tags slow_request=true, empty disables
+		LargeResponse string `mapstructure:"large_response"` // size, e.g. "1MB"; tags large_response=true, empty disables
+		Warn          bool   `mapstructure:"warn"`           // also emit a separate WARN event for flagged requests
+	}
+
+	Masking struct {
+		SensitiveFields []string `mapstructure:"sensitive_fields"` // masked in addition to the built-in names
+
+		Patterns []MaskingPattern `mapstructure:"patterns"`
+		Paths    []string         `mapstructure:"paths"` // e.g. $.user.address.*, $.items[*].card_number
+
+		// Default is the strategy for sensitive fields, Fields overrides it per field name
+		// (and masks fields that aren't sensitive by name, e.g. email).
+		Default MaskingStrategy            `mapstructure:"default"`
+		Fields  map[string]MaskingStrategy `mapstructure:"fields"`
+		HashKey string                     `mapstructure:"hash_key"` // key of the hash and tokenize strategies
+	}
+
+	MaskingStrategy struct {
+		Strategy string `mapstructure:"strategy"` // full, partial, hash, tokenize, or pii
+		Prefix   int    `mapstructure:"prefix"`   // partial: characters kept at the start
+		Suffix   int    `mapstructure:"suffix"`   // partial: characters kept at the end
+	}
+
+	MaskingPattern struct {
+		Name  string `mapstructure:"name"`  // without a regex: credit_card, jwt, or email
+		Regex string `mapstructure:"regex"` // matches in string values are masked
+	}
+
+	LogLimits struct {
+		MaxKeys       int `mapstructure:"max_keys"`        // defaults to 100
+		MaxValueBytes int `mapstructure:"max_value_bytes"` // defaults to 16384
+		MaxTotalBytes int `mapstructure:"max_total_bytes"` // defaults to 65536
+	}
+
+	LogAsync struct {
+		Enabled    bool   `mapstructure:"enabled"`
+		BufferSize int    `mapstructure:"buffer_size"` // queued entries, defaults to 4096
+		Policy     string `mapstructure:"policy"`      // when the queue is full: drop (default) or block
+	}
+
+	LogSink struct {
+		Type string `mapstructure:"type"` // stdout, file, or otlp
+
+		// file: rotated by size with lumberjack
+		Path       string `mapstructure:"path"`
+		MaxSizeMB  int    `mapstructure:"max_size_mb"`  // defaults to 100
+		MaxBackups int    `mapstructure:"max_backups"`  // 0 keeps all
+		MaxAgeDays int    `mapstructure:"max_age_days"` // 0 keeps all
+		Compress   bool   `mapstructure:"compress"`
+
+		// otlp: OTLP/HTTP JSON logs endpoint, e.g. http://collector:4318/v1/logs
+		Endpoint      string            `mapstructure:"endpoint"`
+		Headers       map[string]string `mapstructure:"headers"`
+		BatchSize     int               `mapstructure:"batch_size"`     // defaults to 100
+		FlushInterval string            `mapstructure:"flush_interval"` // defaults to 2s
+	}
+
+	// CORS configures cross-origin requests; it is reloaded without restart
 	CORS struct {
-		HeadersAllowed []string `mapstructure:"headers_allowed"`
+		Origins          []string `mapstructure:"origins"`           // e.g. "https://app.example.com", "https://*.example.com"; defaults to ["*"]
+		Methods          []string `mapstructure:"methods"`           // defaults to GET, HEAD, POST, PUT, PATCH, DELETE
+		HeadersAllowed   []string `mapstructure:"headers_allowed"`   // in addition to Origin, Content-Type, Accept, Authorization
+		HeadersExposed   []string `mapstructure:"headers_exposed"`   // response headers readable by the browser, e.g. X-Request-ID
+		AllowCredentials bool     `mapstructure:"allow_credentials"` // cookies and Authorization; requires explicit origins
+		MaxAge           string   `mapstructure:"max_age"`           // preflight cache, e.g. "10m"; unset sends no Access-Control-Max-Age
+	}
+
+	// CSRF enables double-submit-cookie protection for state-changing requests authenticated by
+	// the refresh token cookie
+	CSRF struct {
+		Enabled     bool     `mapstructure:"enabled"`
+		CookieName  string   `mapstructure:"cookie_name"`  // defaults to "_csrf"
+		HeaderName  string   `mapstructure:"header_name"`  // defaults to X-CSRF-Token
+		SameSite    string   `mapstructure:"same_site"`    // lax (default), strict, or none (always Secure)
+		Secure      bool     `mapstructure:"secure"`       // send the cookie over HTTPS only
+		ExemptPaths []string `mapstructure:"exempt_paths"` // path prefixes, e.g. "/api/v1/webhooks"
 	}
 
 	PostgreSQL struct {
@@ -34,6 +422,94 @@ type (
 		MaxIdleConns    int    `mapstructure:"max_idle_conns"`
 		MaxOpenConns    int    `mapstructure:"max_open_conns"`
 		ConnMaxLifetime string `mapstructure:"conn_max_lifetime"`
+
+		// Driver is "simple" (default) for the simple protocol, safe behind PgBouncer in
+		// transaction mode, or "pgx" for a pgx pool with the extended protocol and a
+		// prepared statement cache of statement_cache_capacity statements per connection
+		Driver                 string `mapstructure:"driver"`
+		StatementCacheCapacity int    `mapstructure:"statement_cache_capacity"` // defaults to 512
+
+		// Session settings SET on connect; "0" leaves the server default, e.g. of the role
+		StatementTimeout                string `mapstructure:"statement_timeout"`                   // longest statement; defaults to 30s
+		IdleInTransactionSessionTimeout string `mapstructure:"idle_in_transaction_session_timeout"` // longest idle open transaction; defaults to 1m
+	}
+
+	// Health sets when GET /health reports PostgreSQL as WARN
+	Health struct {
+		MaxPoolUsage      float64 `mapstructure:"max_pool_usage"`      // share of max_open_conns in use; defaults to 0.9
+		MaxPoolWait       string  `mapstructure:"max_pool_wait"`       // average wait for a pooled connection; defaults to 100ms
+		MaxReplicationLag string  `mapstructure:"max_replication_lag"` // replay lag of the slowest replica; defaults to 10s
+	}
+
+	// Redis is optional; the client is only created when addr is set
+	Redis struct {
+		Addr     string `mapstructure:"addr"` // host:port
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password"`
+		DB       int    `mapstructure:"db"`
+	}
+
+	// Response shapes the JSON of every response, see response.NewSerializer
+	Response struct {
+		Format string `mapstructure:"format"` // camelCase (default), snake_case, or jsonapi
+	}
+
+	// ResponseCache backs the opt-in per-route response cache (middleware.ResponseCache)
+	ResponseCache struct {
+		Store      string `mapstructure:"store"`       // memory (default) or redis, which requires redis.addr
+		TTL        string `mapstructure:"ttl"`         // for routes without their own; defaults to 30s
+		MaxEntries int    `mapstructure:"max_entries"` // memory store; defaults to 10000
+	}
+
+	// RepositoryCache caches the reads of pgsql.PostgreRepository.CacheUsers; off unless ttl is set
+	RepositoryCache struct {
+		Store      string `mapstructure:"store"`       // memory (default) or redis, which requires redis.addr
+		TTL        string `mapstructure:"ttl"`         // lifetime of a cached row; empty disables the cache
+		MaxEntries int    `mapstructure:"max_entries"` // memory store; defaults to 10000
+	}
+
+	// Lock holds the locks that keep background jobs and migrations to one replica at a time
+	Lock struct {
+		Store string `mapstructure:"store"` // postgresql (default, advisory locks on a pool of its own) or redis, which requires redis.addr
+		TTL   string `mapstructure:"ttl"`   // lease of a redis lock, renewed while held; defaults to 30s
+	}
+
+	// Leader elects, through the locks of lock.store, the replica running the singleton
+	// background processes
+	Leader struct {
+		RetryInterval string `mapstructure:"retry_interval"` // how often a follower tries to take over; defaults to 5s, "0" runs them on every replica
+	}
+
+	// Maintenance answers every request but /health, /version, /admin, and /debug with 503 and
+	// Retry-After, e.g. during a migration. Reloaded without restart; PUT /admin/maintenance
+	// overrides it until the next reload changing it.
+	Maintenance struct {
+		Enabled    bool   `mapstructure:"enabled"`
+		RetryAfter string `mapstructure:"retry_after"` // sent in Retry-After; defaults to 5m
+		Message    string `mapstructure:"message"`     // of the 503 responses; defaults to "the service is under maintenance, try again later"
+	}
+
+	// Concurrency caps the requests served at once, in total and per route. Past a cap,
+	// requests wait for a slot in a bounded queue, and are shed with 503 and Retry-After when
+	// it is full or they waited concurrency.queue_timeout. Read at startup only.
+	Concurrency struct {
+		MaxInFlight  int                `mapstructure:"max_in_flight"` // requests served at once; 0 leaves them unlimited
+		MaxQueue     int                `mapstructure:"max_queue"`     // requests waiting for a slot, the others are shed right away; defaults to max_in_flight
+		QueueTimeout string             `mapstructure:"queue_timeout"` // longest wait for a slot; defaults to 1s
+		RetryAfter   string             `mapstructure:"retry_after"`   // sent with the shed requests; defaults to 1s
+		Routes       []ConcurrencyRoute `mapstructure:"routes"`        // caps of their own, on top of max_in_flight
+	}
+
+	// ConcurrencyRoute caps the requests served at once on one route, e.g. an expensive export
+	ConcurrencyRoute struct {
+		Route       string `mapstructure:"route"`         // "METHOD /path" as registered, e.g. "GET /api/v1/admin/users/export"
+		MaxInFlight int    `mapstructure:"max_in_flight"` // requests served at once
+		MaxQueue    int    `mapstructure:"max_queue"`     // requests waiting for a slot; defaults to max_in_flight
+	}
+
+	// Docs gates the API documentation: the Swagger UI at /docs and the spec at /swagger/*
+	Docs struct {
+		Environments []string `mapstructure:"environments"` // application.environment values serving it; defaults to all but prod and production
 	}
 
 	Authorization struct {
@@ -41,6 +517,47 @@ type (
 		Access  TokenConfiguration `mapstructure:"access"`
 		Refresh TokenConfiguration `mapstructure:"refresh"`
 		APIKey  string             `mapstructure:"api_key"`
+
+		// Clients have API keys of their own, accepted like api_key, with optional quotas
+		Clients []APIClient `mapstructure:"clients"`
+
+		// AdminAPIKey guards the /admin and /api/v1/admin endpoints (X-Admin-Key header); they are
+		// disabled when empty
+		AdminAPIKey string `mapstructure:"admin_api_key"`
+
+		// SigningSecret signs generator.SignedPayload tokens (email links, unsubscribe links, download URLs)
+		SigningSecret string `mapstructure:"signing_secret"`
+	}
+
+	// APIClient is a client of the API identified by its own X-API-Key. Quotas count its
+	// requests per UTC day and month; zero leaves them unlimited.
+	APIClient struct {
+		Name         string `mapstructure:"name"` // in the wide events and the usage table, e.g. "partner-a"
+		Key          string `mapstructure:"key"`
+		DailyQuota   int64  `mapstructure:"daily_quota"`
+		MonthlyQuota int64  `mapstructure:"monthly_quota"`
+	}
+
+	// Quota tunes the usage counting of authorization.clients
+	Quota struct {
+		FlushInterval string `mapstructure:"flush_interval"` // how often each instance persists its counts and reads the others'; defaults to 1m
+		OverridesTTL  string `mapstructure:"overrides_ttl"`  // how long the overrides of /admin/quotas/overrides are cached; defaults to 30s
+	}
+
+	// DebugCapture stores the sanitized request and response of the API requests carrying an
+	// X-Debug-Capture token (POST /admin/captures/tokens, signed with authorization.signing_secret)
+	// or made by a flagged account, to replay them locally with the cli replay-capture command
+	DebugCapture struct {
+		Enabled     bool     `mapstructure:"enabled"`
+		Accounts    []string `mapstructure:"accounts"`      // account numbers whose requests are all captured; reloaded without restart
+		MaxBodySize string   `mapstructure:"max_body_size"` // of a request or response body kept, the rest is cut; defaults to 64KB
+		Retention   string   `mapstructure:"retention"`     // how long a capture is kept; defaults to 72h
+		TokenTTL    string   `mapstructure:"token_ttl"`     // how long a token captures requests; defaults to 1h
+	}
+
+	// Diagnostics configures the runtime diagnostics of /admin/runtime
+	Diagnostics struct {
+		DumpDir string `mapstructure:"dump_dir"` // where POST /admin/runtime/heap-dumps writes, e.g. a mounted volume; unset disables the dumps
 	}
 
 	TokenConfiguration struct {
@@ -56,3 +573,41 @@ type (
 		UserInfoURL  string `mapstructure:"user_info_url"`
 	}
 )
+
+// Mock reports whether application.mode is mock
+func (a Application) Mock() bool {
+	return a.Mode == ModeMock
+}
+
+// Address is the address the HTTP server listens on: application.listen, or application.port
+// on every interface
+func (a Application) Address() string {
+	if a.Listen != "" {
+		return a.Listen
+	}
+	return fmt.Sprintf(":%d", a.Port)
+}
+
+// Enabled reports whether HTTPS is configured, with a static certificate or autocert
+func (t TLS) Enabled() bool {
+	return t.Autocert.Enabled || t.CertFile != ""
+}
+
+// Enabled reports whether any client info enrichment is configured
+func (c ClientInfo) Enabled() bool {
+	return c.GeoIPDatabase != "" || c.UserAgent
+}
+
+// Enabled reports whether any notification channel is configured
+func (c Captcha) Enabled() bool {
+	return c.Provider != ""
+}
+
+func (n Notifications) Enabled() bool {
+	return n.Webhook.URL != "" || n.SMTP.Addr != "" || n.SMS.URL != ""
+}
+
+// Enabled reports whether any concurrency cap is configured
+func (c Concurrency) Enabled() bool {
+	return c.MaxInFlight > 0 || len(c.Routes) > 0
+}
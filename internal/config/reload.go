@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/secrets"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadState holds the latest configuration snapshot and its subscribers
+var reloadState struct {
+	mu          sync.RWMutex
+	current     *Configuration
+	subscribers []func(new Configuration)
+}
+
+// immutableFields are kept at their startup values on reload, since changing them
+// requires re-binding the listener, reconnecting the database, or rebuilding the container
+var immutableFields = []struct {
+	name string
+	get  func(c *Configuration) any
+	set  func(dst, src *Configuration)
+}{
+	{"application.port", func(c *Configuration) any { return c.Application.Port }, func(dst, src *Configuration) { dst.Application.Port = src.Application.Port }},
+	{"application.listen", func(c *Configuration) any { return c.Application.Listen }, func(dst, src *Configuration) { dst.Application.Listen = src.Application.Listen }},
+	{"application.mode", func(c *Configuration) any { return c.Application.Mode }, func(dst, src *Configuration) { dst.Application.Mode = src.Application.Mode }},
+	{"application.host", func(c *Configuration) any { return c.Application.Host }, func(dst, src *Configuration) { dst.Application.Host = src.Application.Host }},
+	{"response.format", func(c *Configuration) any { return c.Response.Format }, func(dst, src *Configuration) { dst.Response.Format = src.Response.Format }},
+	{"server.tls", func(c *Configuration) any { return c.Server.TLS }, func(dst, src *Configuration) { dst.Server.TLS = src.Server.TLS }},
+	{"postgresql", func(c *Configuration) any { return c.PostgreSQL }, func(dst, src *Configuration) { dst.PostgreSQL = src.PostgreSQL }},
+}
+
+// Current returns the latest configuration snapshot.
+// Snapshots are never modified after they are published, so they are safe to read concurrently.
+func Current() *Configuration {
+	reloadState.mu.RLock()
+	defer reloadState.mu.RUnlock()
+	return reloadState.current
+}
+
+// OnChange registers fn to be called with the new configuration after every reload.
+// Subscribers run synchronously on the watcher goroutine and should return quickly.
+//
+// Example:
+//
+//	config.OnChange(func(new config.Configuration) {
+//	    logger.SetLevel(new.Logger.Level)
+//	})
+func OnChange(fn func(new Configuration)) {
+	reloadState.mu.Lock()
+	defer reloadState.mu.Unlock()
+	reloadState.subscribers = append(reloadState.subscribers, fn)
+}
+
+// Watch starts watching the environment's config file and reloads it on change.
+// Changes to config.base.yaml are picked up on the next reload or restart.
+// Reload problems (unparseable file, attempts to change immutable fields) are passed to onError;
+// an unparseable or invalid file keeps the previous configuration.
+func Watch(onError func(err error)) {
+	viper.OnConfigChange(func(fsnotify.Event) {
+		// Viper only re-read the environment file, so layer it on the base file again
+		if err := readConfigFiles(viper.GetViper(), Directory, loadedEnvironment); err != nil {
+			if onError != nil {
+				onError(fmt.Errorf("failed to reload configuration: %w", err))
+			}
+			return
+		}
+
+		var next Configuration
+		if err := viper.Unmarshal(&next); err != nil {
+			if onError != nil {
+				onError(fmt.Errorf("failed to reload configuration: %w", err))
+			}
+			return
+		}
+		if err := resolveSecrets(context.Background(), &next, secrets.NewResolver(secretProviders)); err != nil {
+			if onError != nil {
+				onError(fmt.Errorf("failed to reload configuration: %w", err))
+			}
+			return
+		}
+		next.stampBuild()
+
+		if err := next.Validate(); err != nil {
+			if onError != nil {
+				onError(fmt.Errorf("configuration change rejected: %w", err))
+			}
+			return
+		}
+
+		if ignored := apply(next); len(ignored) > 0 && onError != nil {
+			onError(fmt.Errorf("configuration reloaded, but changes to %v require a restart and were ignored", ignored))
+		}
+	})
+	viper.WatchConfig()
+}
+
+// setCurrent publishes the initial configuration
+func setCurrent(configuration *Configuration) {
+	reloadState.mu.Lock()
+	defer reloadState.mu.Unlock()
+	reloadState.current = configuration
+}
+
+// apply publishes next as the current configuration, keeping immutable fields at their
+// previous values, and notifies subscribers. It returns the immutable fields that changed.
+func apply(next Configuration) []string {
+	reloadState.mu.Lock()
+	previous := reloadState.current
+
+	var ignored []string
+	if previous != nil {
+		for _, field := range immutableFields {
+			if !reflect.DeepEqual(field.get(previous), field.get(&next)) {
+				ignored = append(ignored, field.name)
+				field.set(&next, previous)
+			}
+		}
+	}
+
+	reloadState.current = &next
+	subscribers := append([]func(Configuration){}, reloadState.subscribers...)
+	reloadState.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(next)
+	}
+
+	return ignored
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	initial := &Configuration{
+		Application: Application{Port: 8080, Host: "localhost"},
+		PostgreSQL:  PostgreSQL{Host: "db"},
+		Logger:      Logger{Level: "info"},
+	}
+	setCurrent(initial)
+
+	var received []Configuration
+	OnChange(func(new Configuration) {
+		received = append(received, new)
+	})
+
+	next := *initial
+	next.Logger.Level = "debug"
+	next.Application.Port = 9090
+	next.PostgreSQL.Host = "other-db"
+
+	ignored := apply(next)
+
+	assert.ElementsMatch(t, []string{"application.port", "postgresql"}, ignored)
+
+	// Mutable fields change, immutable ones keep their startup values
+	assert.Equal(t, "debug", Current().Logger.Level)
+	assert.Equal(t, 8080, Current().Application.Port)
+	assert.Equal(t, "db", Current().PostgreSQL.Host)
+
+	// Subscribers see the guarded configuration, and the old snapshot is untouched
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, 8080, received[0].Application.Port)
+		assert.Equal(t, "debug", received[0].Logger.Level)
+	}
+	assert.Equal(t, "info", initial.Logger.Level)
+}
@@ -0,0 +1,630 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	gbytes "github.com/labstack/gommon/bytes"
+)
+
+// MinSecretLength is the minimum length of JWT and signing secrets (256 bits for HS256)
+const MinSecretLength = 32
+
+// MaxTermsVersionLength is the length of consents.terms_version
+const MaxTermsVersionLength = 32
+
+// Environments lists the accepted values of application.environment
+var Environments = []string{"local", "dev", "staging", "prod", "production"}
+
+// Application modes, see application.mode
+const (
+	ModeLive = "live"
+	ModeMock = "mock"
+)
+
+// productionEnvironments never run in mock mode
+var productionEnvironments = []string{"prod", "production"}
+
+var (
+	logLevels         = []string{"debug", "info", "warn", "error"}
+	checkDigits       = []string{"luhn", "mod97"}
+	maskingPatterns   = []string{"credit_card", "jwt", "email"} // built-in, see logger.builtinMaskPatterns
+	maskingStrategies = []string{"full", "partial", "hash", "tokenize", "pii"}
+	corsMethods       = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	sameSiteModes     = []string{"lax", "strict", "none"}
+	cacheStores       = []string{"memory", "redis"}
+	bodyCaptureModes  = []string{"always", "errors", "off"}
+	headerModes       = []string{"deny", "allow"}
+	captchaProviders  = []string{"recaptcha", "hcaptcha", "turnstile"}
+	postgresDrivers   = []string{"simple", "pgx"}
+	lockStores        = []string{"postgresql", "redis"}
+	applicationModes  = []string{ModeLive, ModeMock}
+	responseFormats   = []string{"camelCase", "snake_case", "jsonapi"} // see response.NewSerializer
+)
+
+// Validate checks the configuration for missing required fields and invalid values,
+// reporting every problem at once instead of failing later at first use.
+// The returned error is a *multierror.Error, or nil when the configuration is valid.
+func (c *Configuration) Validate() error {
+	var errs *multierror.Error
+	add := func(key, format string, args ...any) {
+		errs = multierror.Append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	required := func(key, value string) bool {
+		if strings.TrimSpace(value) == "" {
+			add(key, "is required")
+			return false
+		}
+		return true
+	}
+
+	port := func(key string, value int) {
+		if value < 1 || value > 65535 {
+			add(key, "must be between 1 and 65535, got %d", value)
+		}
+	}
+
+	duration := func(key, value string, isRequired bool) {
+		if value == "" {
+			if isRequired {
+				add(key, "is required")
+			}
+			return
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			add(key, "invalid duration %q", value)
+			return
+		}
+		if parsed <= 0 {
+			add(key, "must be positive, got %q", value)
+		}
+	}
+
+	secret := func(key, value string, isRequired bool) {
+		if value == "" {
+			if isRequired {
+				add(key, "is required")
+			}
+			return
+		}
+		if len(value) < MinSecretLength {
+			add(key, "must be at least %d characters", MinSecretLength)
+		}
+	}
+
+	oneOf := func(key, value string, allowed []string) {
+		if value != "" && !slices.Contains(allowed, value) {
+			add(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+		}
+	}
+
+	listenAddress := func(key, value string) {
+		if path, ok := strings.CutPrefix(value, "unix:"); ok {
+			if path == "" {
+				add(key, "unix: needs the path of the socket")
+			}
+			return
+		}
+		if value == "systemd" || strings.HasPrefix(value, "systemd:") {
+			if value == "systemd:" {
+				add(key, "systemd: needs the name of the socket")
+			}
+			return
+		}
+		if _, _, err := net.SplitHostPort(value); err != nil {
+			add(key, "must be host:port, unix:<path>, or systemd[:<name>], got %q", value)
+		}
+	}
+
+	// Application
+	required("application.name", c.Application.Name)
+	if c.Application.Listen == "" {
+		port("application.port", c.Application.Port)
+	} else {
+		listenAddress("application.listen", c.Application.Listen)
+	}
+	if required("application.environment", c.Application.Environment) {
+		oneOf("application.environment", c.Application.Environment, Environments)
+	}
+	oneOf("application.mode", c.Application.Mode, applicationModes)
+	if c.Application.Mode == ModeMock && slices.Contains(productionEnvironments, c.Application.Environment) {
+		add("application.mode", "mock is not allowed in %s", c.Application.Environment)
+	}
+	if c.Application.Timeout < 0 {
+		add("application.timeout", "must not be negative, got %d", c.Application.Timeout)
+	}
+	if c.Application.Timezone != "" {
+		if _, err := time.LoadLocation(c.Application.Timezone); err != nil {
+			add("application.timezone", "unknown timezone %q", c.Application.Timezone)
+		}
+	}
+
+	// Docs
+	for i, environment := range c.Docs.Environments {
+		oneOf(fmt.Sprintf("docs.environments[%d]", i), environment, Environments)
+	}
+
+	// PostgreSQL
+	required("postgresql.host", c.PostgreSQL.Host)
+	required("postgresql.name", c.PostgreSQL.Name)
+	required("postgresql.user", c.PostgreSQL.User)
+	port("postgresql.port", c.PostgreSQL.Port)
+	duration("postgresql.conn_max_lifetime", c.PostgreSQL.ConnMaxLifetime, false)
+	if c.PostgreSQL.MaxIdleConns < 0 || c.PostgreSQL.MaxOpenConns < 0 {
+		add("postgresql", "max_idle_conns and max_open_conns must not be negative")
+	}
+	oneOf("postgresql.driver", c.PostgreSQL.Driver, postgresDrivers)
+	if c.PostgreSQL.StatementCacheCapacity < 0 {
+		add("postgresql.statement_cache_capacity", "must not be negative, got %d", c.PostgreSQL.StatementCacheCapacity)
+	}
+	// Zero disables the session timeouts
+	sessionTimeout := func(key, value string) {
+		if parsed, err := time.ParseDuration(value); value != "" && (err != nil || parsed < 0) {
+			add(key, "invalid duration %q", value)
+		}
+	}
+	sessionTimeout("postgresql.statement_timeout", c.PostgreSQL.StatementTimeout)
+	sessionTimeout("postgresql.idle_in_transaction_session_timeout", c.PostgreSQL.IdleInTransactionSessionTimeout)
+
+	// Health
+	if c.Health.MaxPoolUsage < 0 || c.Health.MaxPoolUsage > 1 {
+		add("health.max_pool_usage", "must be between 0 and 1, got %v", c.Health.MaxPoolUsage)
+	}
+	duration("health.max_pool_wait", c.Health.MaxPoolWait, false)
+	duration("health.max_replication_lag", c.Health.MaxReplicationLag, false)
+
+	// Startup
+	duration("startup.readiness_timeout", c.Startup.ReadinessTimeout, false)
+
+	// Authorization
+	secret("authorization.access.secret", c.Authorization.Access.Secret, true)
+	duration("authorization.access.duration", c.Authorization.Access.Duration, true)
+	secret("authorization.refresh.secret", c.Authorization.Refresh.Secret, true)
+	duration("authorization.refresh.duration", c.Authorization.Refresh.Duration, true)
+	secret("authorization.signing_secret", c.Authorization.SigningSecret, false)
+	secret("authorization.admin_api_key", c.Authorization.AdminAPIKey, false)
+	clientNames, clientKeys := map[string]bool{}, map[string]bool{c.Authorization.APIKey: true}
+	for i, client := range c.Authorization.Clients {
+		key := fmt.Sprintf("authorization.clients[%d]", i)
+		if client.Name == "" {
+			add(key+".name", "is required")
+		} else if clientNames[client.Name] {
+			add(key+".name", "duplicates %q", client.Name)
+		}
+		clientNames[client.Name] = true
+		secret(key+".key", client.Key, true)
+		if client.Key != "" && clientKeys[client.Key] {
+			add(key+".key", "must differ from api_key and the other clients' keys")
+		}
+		clientKeys[client.Key] = true
+		if client.DailyQuota < 0 {
+			add(key+".daily_quota", "must not be negative, got %d", client.DailyQuota)
+		}
+		if client.MonthlyQuota < 0 {
+			add(key+".monthly_quota", "must not be negative, got %d", client.MonthlyQuota)
+		}
+	}
+	duration("quota.flush_interval", c.Quota.FlushInterval, false)
+	duration("quota.overrides_ttl", c.Quota.OverridesTTL, false)
+
+	// Debug capture
+	if c.DebugCapture.Enabled && c.Authorization.SigningSecret == "" {
+		add("debug_capture.enabled", "requires authorization.signing_secret")
+	}
+	if c.DebugCapture.MaxBodySize != "" {
+		if size, err := gbytes.Parse(c.DebugCapture.MaxBodySize); err != nil || size <= 0 {
+			add("debug_capture.max_body_size", "invalid size %q", c.DebugCapture.MaxBodySize)
+		}
+	}
+	duration("debug_capture.retention", c.DebugCapture.Retention, false)
+	duration("debug_capture.token_ttl", c.DebugCapture.TokenTTL, false)
+
+	// Redis, caches, and locks
+	if c.Redis.DB < 0 {
+		add("redis.db", "must not be negative, got %d", c.Redis.DB)
+	}
+	oneOf("response.format", c.Response.Format, responseFormats)
+	oneOf("response_cache.store", c.ResponseCache.Store, cacheStores)
+	if c.ResponseCache.Store == "redis" && c.Redis.Addr == "" {
+		add("response_cache.store", "redis requires redis.addr")
+	}
+	duration("response_cache.ttl", c.ResponseCache.TTL, false)
+	if c.ResponseCache.MaxEntries < 0 {
+		add("response_cache.max_entries", "must not be negative, got %d", c.ResponseCache.MaxEntries)
+	}
+	oneOf("repository_cache.store", c.RepositoryCache.Store, cacheStores)
+	if c.RepositoryCache.Store == "redis" && c.Redis.Addr == "" {
+		add("repository_cache.store", "redis requires redis.addr")
+	}
+	duration("repository_cache.ttl", c.RepositoryCache.TTL, false)
+	if c.RepositoryCache.MaxEntries < 0 {
+		add("repository_cache.max_entries", "must not be negative, got %d", c.RepositoryCache.MaxEntries)
+	}
+	oneOf("lock.store", c.Lock.Store, lockStores)
+	if c.Lock.Store == "redis" && c.Redis.Addr == "" {
+		add("lock.store", "redis requires redis.addr")
+	}
+	duration("lock.ttl", c.Lock.TTL, false)
+	if c.Leader.RetryInterval != "0" {
+		duration("leader.retry_interval", c.Leader.RetryInterval, false)
+	}
+
+	// Maintenance
+	duration("maintenance.retry_after", c.Maintenance.RetryAfter, false)
+
+	// Concurrency
+	if c.Concurrency.MaxInFlight < 0 {
+		add("concurrency.max_in_flight", "must not be negative, got %d", c.Concurrency.MaxInFlight)
+	}
+	if c.Concurrency.MaxQueue < 0 {
+		add("concurrency.max_queue", "must not be negative, got %d", c.Concurrency.MaxQueue)
+	}
+	duration("concurrency.queue_timeout", c.Concurrency.QueueTimeout, false)
+	duration("concurrency.retry_after", c.Concurrency.RetryAfter, false)
+	for i, route := range c.Concurrency.Routes {
+		key := fmt.Sprintf("concurrency.routes[%d]", i)
+		method, path, ok := strings.Cut(route.Route, " ")
+		if !ok || !slices.Contains(corsMethods, method) || !strings.HasPrefix(path, "/") {
+			add(key+".route", "must be \"METHOD /path\", got %q", route.Route)
+		}
+		if route.MaxInFlight <= 0 {
+			add(key+".max_in_flight", "must be positive, got %d", route.MaxInFlight)
+		}
+		if route.MaxQueue < 0 {
+			add(key+".max_queue", "must not be negative, got %d", route.MaxQueue)
+		}
+	}
+
+	// CORS
+	for i, origin := range c.CORS.Origins {
+		if err := validateOrigin(origin); err != nil {
+			add(fmt.Sprintf("cors.origins[%d]", i), "%v", err)
+		}
+		if origin == "*" && c.CORS.AllowCredentials {
+			add("cors.allow_credentials", `cannot be used with the "*" origin, list the allowed origins`)
+		}
+	}
+	if len(c.CORS.Origins) == 0 && c.CORS.AllowCredentials {
+		add("cors.allow_credentials", "requires cors.origins")
+	}
+	for i, method := range c.CORS.Methods {
+		oneOf(fmt.Sprintf("cors.methods[%d]", i), method, corsMethods)
+	}
+	duration("cors.max_age", c.CORS.MaxAge, false)
+	oneOf("csrf.same_site", c.CSRF.SameSite, sameSiteModes)
+	for i, path := range c.CSRF.ExemptPaths {
+		if !strings.HasPrefix(path, "/") {
+			add(fmt.Sprintf("csrf.exempt_paths[%d]", i), "must start with /, got %q", path)
+		}
+	}
+
+	// Server and logger
+	if c.Server.MaxBodySize != "" {
+		if size, err := gbytes.Parse(c.Server.MaxBodySize); err != nil || size <= 0 {
+			add("server.max_body_size", "invalid size %q", c.Server.MaxBodySize)
+		}
+	}
+	duration("server.read_timeout", c.Server.ReadTimeout, false)
+	duration("server.read_header_timeout", c.Server.ReadHeaderTimeout, false)
+	duration("server.write_timeout", c.Server.WriteTimeout, false)
+	duration("server.idle_timeout", c.Server.IdleTimeout, false)
+	if c.Server.MaxHeaderBytes != "" {
+		if size, err := gbytes.Parse(c.Server.MaxHeaderBytes); err != nil || size <= 0 {
+			add("server.max_header_bytes", "invalid size %q", c.Server.MaxHeaderBytes)
+		}
+	}
+	if compression := c.Server.Compression; compression.Enabled {
+		if compression.MinSize != "" {
+			if size, err := gbytes.Parse(compression.MinSize); err != nil || size < 0 {
+				add("server.compression.min_size", "invalid size %q", compression.MinSize)
+			}
+		}
+		if compression.Level != 0 && (compression.Level < 1 || compression.Level > 9) {
+			add("server.compression.level", "must be between 1 and 9, got %d", compression.Level)
+		}
+		if compression.BrotliLevel != 0 && (compression.BrotliLevel < 1 || compression.BrotliLevel > 11) {
+			add("server.compression.brotli_level", "must be between 1 and 11, got %d", compression.BrotliLevel)
+		}
+		for i, contentType := range compression.ContentTypes {
+			if !strings.Contains(contentType, "/") {
+				add(fmt.Sprintf("server.compression.content_types[%d]", i), "must be a media type such as application/json or text/*, got %q", contentType)
+			}
+		}
+	}
+	duration("server.http2.idle_timeout", c.Server.HTTP2.IdleTimeout, false)
+	if c.Server.HTTP2.MaxReadFrameSize != "" {
+		if size, err := gbytes.Parse(c.Server.HTTP2.MaxReadFrameSize); err != nil || size < 16<<10 || size >= 16<<20 {
+			add("server.http2.max_read_frame_size", "must be at least 16KiB and below 16MiB, got %q", c.Server.HTTP2.MaxReadFrameSize)
+		}
+	}
+	if tls := c.Server.TLS; tls.Autocert.Enabled {
+		if tls.CertFile != "" || tls.KeyFile != "" {
+			add("server.tls.autocert", "cannot be combined with cert_file/key_file")
+		}
+		if len(tls.Autocert.Hosts) == 0 {
+			add("server.tls.autocert.hosts", "is required")
+		}
+		required("server.tls.autocert.cache_dir", tls.Autocert.CacheDir)
+	} else if (tls.CertFile == "") != (tls.KeyFile == "") {
+		add("server.tls", "cert_file and key_file must be set together")
+	}
+	if redirect := c.Server.TLS.RedirectPort; redirect != 0 {
+		port("server.tls.redirect_port", redirect)
+		if !c.Server.TLS.Enabled() {
+			add("server.tls.redirect_port", "requires cert_file/key_file or autocert")
+		}
+		if redirect == c.Application.Port {
+			add("server.tls.redirect_port", "must differ from application.port")
+		}
+	}
+	oneOf("logger.level", c.Logger.Level, logLevels)
+	if c.Logger.SuccessSampleRate < 0 || c.Logger.SuccessSampleRate > 1 {
+		add("logger.success_sample_rate", "must be between 0 and 1, got %v", c.Logger.SuccessSampleRate)
+	}
+	for i, sink := range c.Logger.Sinks {
+		key := fmt.Sprintf("logger.sinks[%d]", i)
+		switch sink.Type {
+		case "stdout":
+		case "file":
+			required(key+".path", sink.Path)
+		case "otlp":
+			required(key+".endpoint", sink.Endpoint)
+			duration(key+".flush_interval", sink.FlushInterval, false)
+		default:
+			add(key+".type", "must be one of stdout, file, otlp, got %q", sink.Type)
+		}
+	}
+	if c.Logger.Async.BufferSize < 0 {
+		add("logger.async.buffer_size", "must not be negative, got %d", c.Logger.Async.BufferSize)
+	}
+	oneOf("logger.async.policy", c.Logger.Async.Policy, []string{"drop", "block"})
+	for _, limit := range []struct {
+		key   string
+		value int
+	}{
+		{"logger.limits.max_keys", c.Logger.Limits.MaxKeys},
+		{"logger.limits.max_value_bytes", c.Logger.Limits.MaxValueBytes},
+		{"logger.limits.max_total_bytes", c.Logger.Limits.MaxTotalBytes},
+	} {
+		if limit.value < 0 {
+			add(limit.key, "must not be negative, got %d", limit.value)
+		}
+	}
+	for i, pattern := range c.Logger.Masking.Patterns {
+		key := fmt.Sprintf("logger.masking.patterns[%d]", i)
+		if pattern.Regex == "" {
+			required(key+".name", pattern.Name)
+			oneOf(key+".name", pattern.Name, maskingPatterns)
+		} else if _, err := regexp.Compile(pattern.Regex); err != nil {
+			add(key+".regex", "%v", err)
+		}
+	}
+	maskingStrategy := func(key string, strategy MaskingStrategy) {
+		oneOf(key+".strategy", strategy.Strategy, maskingStrategies)
+		if strategy.Prefix < 0 || strategy.Suffix < 0 {
+			add(key, "prefix and suffix must not be negative")
+		}
+		if (strategy.Strategy == "hash" || strategy.Strategy == "tokenize") && c.Logger.Masking.HashKey == "" {
+			add("logger.masking.hash_key", "is required by %s", key)
+		}
+		if strategy.Strategy == "pii" && c.PII.Key == "" {
+			add("pii.key", "is required by %s", key)
+		}
+	}
+	maskingStrategy("logger.masking.default", c.Logger.Masking.Default)
+	for _, field := range slices.Sorted(maps.Keys(c.Logger.Masking.Fields)) {
+		key := "logger.masking.fields." + field
+		required(key+".strategy", c.Logger.Masking.Fields[field].Strategy)
+		maskingStrategy(key, c.Logger.Masking.Fields[field])
+	}
+	for i, path := range c.Logger.Masking.Paths {
+		if !strings.HasPrefix(path, "$.") {
+			add(fmt.Sprintf("logger.masking.paths[%d]", i), "must start with $., got %q", path)
+		}
+	}
+	duration("logger.performance.slow_request", c.Logger.Performance.SlowRequest, false)
+	if c.Logger.Performance.LargeResponse != "" {
+		if size, err := gbytes.Parse(c.Logger.Performance.LargeResponse); err != nil || size <= 0 {
+			add("logger.performance.large_response", "invalid size %q", c.Logger.Performance.LargeResponse)
+		}
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(c.Logger.PathLevels)) {
+		if !strings.HasPrefix(pattern, "/") {
+			add("logger.path_levels", "path %q must start with /", pattern)
+		}
+		oneOf("logger.path_levels."+pattern, c.Logger.PathLevels[pattern], logLevels)
+	}
+	bodyCapture := c.Logger.BodyCapture
+	oneOf("logger.body_capture.mode", bodyCapture.Mode, bodyCaptureModes)
+	for _, environment := range slices.Sorted(maps.Keys(bodyCapture.Environments)) {
+		oneOf("logger.body_capture.environments", environment, Environments)
+		oneOf("logger.body_capture.environments."+environment, bodyCapture.Environments[environment], bodyCaptureModes)
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(bodyCapture.Paths)) {
+		if !strings.HasPrefix(pattern, "/") {
+			add("logger.body_capture.paths", "path %q must start with /", pattern)
+		}
+		oneOf("logger.body_capture.paths."+pattern, bodyCapture.Paths[pattern], bodyCaptureModes)
+	}
+	oneOf("logger.headers.mode", c.Logger.Headers.Mode, headerModes)
+	for i, name := range c.Logger.Headers.Allow {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :") {
+			add(fmt.Sprintf("logger.headers.allow[%d]", i), "invalid header name %q", name)
+		}
+	}
+
+	// PII
+	secret("pii.key", c.PII.Key, false)
+	if c.PII.MaxEntries < 0 {
+		add("pii.max_entries", "must not be negative, got %d", c.PII.MaxEntries)
+	}
+	duration("pii.ttl", c.PII.TTL, false)
+
+	// Password
+	if c.Password.MinScore < 0 || c.Password.MinScore > 4 {
+		add("password.min_score", "must be between 0 and 4, got %d", c.Password.MinScore)
+	}
+	if c.Password.BreachCheck.Enabled {
+		duration("password.breach_check.timeout", c.Password.BreachCheck.Timeout, false)
+	}
+
+	// Login alerts
+	if c.LoginAlerts.Enabled && !c.ClientInfo.Enabled() {
+		add("login_alerts.enabled", "requires client_info.geoip_database or client_info.user_agent")
+	}
+
+	// Login throttle
+	if c.LoginThrottle.Enabled {
+		throttle := c.LoginThrottle
+		oneOf("login_throttle.store", throttle.Store, cacheStores)
+		if throttle.Store == "redis" && c.Redis.Addr == "" {
+			add("login_throttle.store", "redis requires redis.addr")
+		}
+		duration("login_throttle.window", throttle.Window, false)
+		duration("login_throttle.base_delay", throttle.BaseDelay, false)
+		duration("login_throttle.max_delay", throttle.MaxDelay, false)
+		for _, setting := range []struct {
+			key   string
+			value int
+		}{
+			{"login_throttle.max_failures", throttle.MaxFailures},
+			{"login_throttle.max_ip_failures", throttle.MaxIPFailures},
+			{"login_throttle.free_failures", throttle.FreeFailures},
+			{"login_throttle.challenge_after", throttle.ChallengeAfter},
+		} {
+			if setting.value < 0 {
+				add(setting.key, "must not be negative, got %d", setting.value)
+			}
+		}
+	}
+
+	// CAPTCHA
+	if c.Captcha.Enabled() {
+		captcha := c.Captcha
+		oneOf("captcha.provider", captcha.Provider, captchaProviders)
+		required("captcha.secret", captcha.Secret)
+		if captcha.MinScore < 0 || captcha.MinScore > 1 {
+			add("captcha.min_score", "must be between 0 and 1, got %g", captcha.MinScore)
+		}
+		if captcha.VerifyURL != "" {
+			if parsed, err := url.Parse(captcha.VerifyURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				add("captcha.verify_url", "must be an http or https URL, got %q", captcha.VerifyURL)
+			}
+		}
+		duration("captcha.timeout", captcha.Timeout, false)
+		for i, route := range captcha.Routes {
+			method, path, ok := strings.Cut(route, " ")
+			if !ok || !slices.Contains(corsMethods, method) || !strings.HasPrefix(path, "/") {
+				add(fmt.Sprintf("captcha.routes[%d]", i), "must be \"METHOD /path\", got %q", route)
+			}
+		}
+	}
+
+	// Consent
+	if len(c.Consent.TermsVersion) > MaxTermsVersionLength {
+		add("consent.terms_version", "must be at most %d characters, got %d", MaxTermsVersionLength, len(c.Consent.TermsVersion))
+	}
+
+	// Account deletion
+	duration("account_deletion.grace_period", c.Deletion.GracePeriod, false)
+	if c.Deletion.Interval != "0" {
+		duration("account_deletion.interval", c.Deletion.Interval, false)
+	}
+	if c.Deletion.BatchSize < 0 {
+		add("account_deletion.batch_size", "must not be negative, got %d", c.Deletion.BatchSize)
+	}
+
+	// Email
+	for _, domain := range c.Email.DisposableDomains {
+		if domain = strings.TrimSpace(domain); domain == "" || strings.ContainsAny(domain, "@/ ") {
+			add("email.disposable_domains", "must be domain names, got %q", domain)
+		}
+	}
+
+	// Username
+	for _, name := range c.Username.Reserved {
+		if strings.TrimSpace(name) == "" {
+			add("username.reserved", "must not contain empty names")
+			break
+		}
+	}
+
+	// Email change
+	duration("email_change.ttl", c.EmailChange.TTL, false)
+	if c.EmailChange.ConfirmURL != "" {
+		if parsed, err := url.Parse(c.EmailChange.ConfirmURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add("email_change.confirm_url", "must be an http or https URL, got %q", c.EmailChange.ConfirmURL)
+		}
+	}
+
+	// Phone change
+	duration("phone_change.ttl", c.PhoneChange.TTL, false)
+	if c.PhoneChange.MaxAttempts < 0 {
+		add("phone_change.max_attempts", "must not be negative, got %d", c.PhoneChange.MaxAttempts)
+	}
+
+	// Notifications
+	if c.Notifications.Webhook.URL != "" {
+		if parsed, err := url.Parse(c.Notifications.Webhook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add("notifications.webhook.url", "must be an http or https URL, got %q", c.Notifications.Webhook.URL)
+		}
+	}
+	if c.Notifications.SMTP.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Notifications.SMTP.Addr); err != nil {
+			add("notifications.smtp.addr", "must be host:port, got %q", c.Notifications.SMTP.Addr)
+		}
+		if _, err := mail.ParseAddress(c.Notifications.SMTP.From); err != nil {
+			add("notifications.smtp.from", "must be an email address, got %q", c.Notifications.SMTP.From)
+		}
+	}
+	if c.Notifications.SMS.URL != "" {
+		if parsed, err := url.Parse(c.Notifications.SMS.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add("notifications.sms.url", "must be an http or https URL, got %q", c.Notifications.SMS.URL)
+		}
+	}
+	if c.Notifications.MaxAttempts < 0 {
+		add("notifications.max_attempts", "must not be negative, got %d", c.Notifications.MaxAttempts)
+	}
+	duration("notifications.timeout", c.Notifications.Timeout, false)
+
+	// Account number
+	if c.AccountNumber.MaxRetries < 0 {
+		add("account_number.max_retries", "must not be negative, got %d", c.AccountNumber.MaxRetries)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.AccountNumber.Profiles)) {
+		profile := c.AccountNumber.Profiles[name]
+		key := "account_number.profiles." + name
+		if profile.Length <= 0 {
+			add(key+".length", "must be positive, got %d", profile.Length)
+		}
+		oneOf(key+".check_digit", profile.CheckDigit, checkDigits)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// validateOrigin checks a cors.origins entry: "*", or scheme://host[:port] where the host
+// may start with a "*." wildcard for any subdomain
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://", 1))
+	if err != nil || u.Scheme == "" || u.Host == "" || strings.Contains(u.Host, "*") {
+		return fmt.Errorf(`must be "*" or scheme://host[:port] with an optional "*." subdomain wildcard, got %q`, origin)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("must not have a path, query, or credentials, got %q", origin)
+	}
+	return nil
+}
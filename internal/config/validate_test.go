@@ -0,0 +1,628 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfiguration() Configuration {
+	return Configuration{
+		Application: Application{Name: "app", Port: 8080, Environment: "local", Timezone: "Asia/Jakarta"},
+		PostgreSQL:  PostgreSQL{Host: "db", Name: "app", User: "app", Port: 5432, ConnMaxLifetime: "1h"},
+		Authorization: Authorization{
+			Access:  TokenConfiguration{Secret: "0123456789abcdef0123456789abcdef", Duration: "15m"},
+			Refresh: TokenConfiguration{Secret: "fedcba9876543210fedcba9876543210", Duration: "168h"},
+		},
+		Server: Server{MaxBodySize: "1MB"},
+		Logger: Logger{Level: "info"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	configuration := validConfiguration()
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateAggregatesErrors(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Application.Port = 70000
+	configuration.Application.Environment = "qa"
+	configuration.PostgreSQL.Host = ""
+	configuration.Authorization.Access.Duration = "15 minutes"
+	configuration.Authorization.Refresh.Secret = "short"
+	configuration.Logger.Level = "verbose"
+
+	err := configuration.Validate()
+	require.Error(t, err)
+
+	var merr *multierror.Error
+	require.ErrorAs(t, err, &merr)
+	assert.Len(t, merr.Errors, 6)
+
+	for _, key := range []string{
+		"application.port",
+		"application.environment",
+		"postgresql.host",
+		"authorization.access.duration",
+		"authorization.refresh.secret",
+		"logger.level",
+	} {
+		assert.Contains(t, err.Error(), key)
+	}
+}
+
+func TestValidateApplicationMode(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Application.Mode = "stub"
+	assert.ErrorContains(t, configuration.Validate(), "application.mode")
+
+	configuration.Application.Mode = ModeMock
+	assert.NoError(t, configuration.Validate())
+
+	configuration.Application.Environment = "production"
+	assert.ErrorContains(t, configuration.Validate(), "mock is not allowed in production")
+}
+
+func TestValidateApplicationListen(t *testing.T) {
+	for _, listen := range []string{"127.0.0.1:8080", ":8080", "unix:/run/app/http.sock", "systemd", "systemd:http"} {
+		configuration := validConfiguration()
+		configuration.Application.Port = 0 // not needed with listen
+		configuration.Application.Listen = listen
+		assert.NoError(t, configuration.Validate(), listen)
+		assert.Equal(t, listen, configuration.Application.Address())
+	}
+
+	for _, listen := range []string{"unix:", "systemd:", "localhost"} {
+		configuration := validConfiguration()
+		configuration.Application.Listen = listen
+		assert.ErrorContains(t, configuration.Validate(), "application.listen", listen)
+	}
+
+	assert.Equal(t, ":8080", Application{Port: 8080}.Address())
+}
+
+func TestValidateHealth(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Health.MaxPoolUsage = 1.5
+	configuration.Health.MaxReplicationLag = "10 seconds"
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "health.max_pool_usage")
+	assert.Contains(t, err.Error(), "health.max_replication_lag")
+	assert.NotContains(t, err.Error(), "health.max_pool_wait")
+}
+
+func TestValidateStartup(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Startup.ReadinessTimeout = "0s"
+	assert.ErrorContains(t, configuration.Validate(), "startup.readiness_timeout")
+
+	configuration.Startup.ReadinessTimeout = "2m"
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidatePostgreSQLDriver(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.PostgreSQL.Driver = "pgxpool"
+	configuration.PostgreSQL.StatementCacheCapacity = -1
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "postgresql.driver")
+	assert.Contains(t, err.Error(), "postgresql.statement_cache_capacity")
+
+	configuration.PostgreSQL.Driver = "pgx"
+	configuration.PostgreSQL.StatementCacheCapacity = 0
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateAccountNumberProfiles(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.AccountNumber.Profiles = map[string]AccountNumberProfile{
+		"branch": {Length: 12, CheckDigit: "luhn"},
+		"broken": {Length: 0, CheckDigit: "crc"},
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "account_number.profiles.broken.length")
+	assert.Contains(t, err.Error(), "account_number.profiles.broken.check_digit")
+	assert.NotContains(t, err.Error(), "branch")
+}
+
+func TestValidateLoggerMasking(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Logger.Masking = Masking{
+		Patterns: []MaskingPattern{{Name: "jwt"}, {Name: "phone"}, {Name: "order", Regex: "("}},
+		Paths:    []string{"$.user.address.*", "user.email"},
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.masking.patterns[1].name")
+	assert.Contains(t, err.Error(), "logger.masking.patterns[2].regex")
+	assert.Contains(t, err.Error(), "logger.masking.paths[1]")
+	assert.NotContains(t, err.Error(), "patterns[0]")
+	assert.NotContains(t, err.Error(), "paths[0]")
+}
+
+func TestValidateLoggerMaskingStrategies(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Logger.Masking = Masking{
+		Default: MaskingStrategy{Strategy: "partial", Prefix: -1},
+		Fields: map[string]MaskingStrategy{
+			"email": {Strategy: "hash"},
+			"phone": {Strategy: "scramble"},
+			"ssn":   {Strategy: "full"},
+		},
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.masking.default")
+	assert.Contains(t, err.Error(), "logger.masking.hash_key")
+	assert.Contains(t, err.Error(), "logger.masking.fields.phone.strategy")
+	assert.NotContains(t, err.Error(), "fields.ssn")
+}
+
+func TestValidatePII(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Logger.Masking.Fields = map[string]MaskingStrategy{"email": {Strategy: "pii"}}
+	configuration.PII = PII{MaxEntries: -1, TTL: "forever"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pii.key: is required by logger.masking.fields.email")
+	assert.Contains(t, err.Error(), "pii.max_entries")
+	assert.Contains(t, err.Error(), "pii.ttl")
+
+	configuration.PII = PII{Key: "pii-key-0123456789abcdef0123456789ab"}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLoggerPerformance(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Logger.Performance = LogPerformance{SlowRequest: "slow", LargeResponse: "huge"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.performance.slow_request")
+	assert.Contains(t, err.Error(), "logger.performance.large_response")
+
+	configuration.Logger.Performance = LogPerformance{SlowRequest: "500ms", LargeResponse: "1MB"}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLoggerBodyCapture(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Logger.BodyCapture = LogBodyCapture{
+		Mode:         "sometimes",
+		Environments: map[string]string{"qa": "off", "prod": "never"},
+		Paths:        map[string]string{"api/uploads/*": "off", "/api/v1/files/*": "lazy"},
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.body_capture.mode")
+	assert.Contains(t, err.Error(), `logger.body_capture.environments: must be one of`)
+	assert.Contains(t, err.Error(), "logger.body_capture.environments.prod")
+	assert.Contains(t, err.Error(), `path "api/uploads/*" must start with /`)
+	assert.Contains(t, err.Error(), "logger.body_capture.paths./api/v1/files/*")
+
+	configuration.Logger.BodyCapture = LogBodyCapture{
+		Mode:         "always",
+		Environments: map[string]string{"prod": "errors"},
+		Paths:        map[string]string{"/api/v1/uploads/*": "off"},
+	}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLoggerHeaders(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Logger.Headers = LogHeaders{Mode: "block", Allow: []string{"Accept", "", "X Request"}}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.headers.mode")
+	assert.Contains(t, err.Error(), "logger.headers.allow[1]")
+	assert.Contains(t, err.Error(), "logger.headers.allow[2]")
+
+	configuration.Logger.Headers = LogHeaders{Mode: "allow", Allow: []string{"accept", "X-Request-ID"}}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLoginAlertsAndNotifications(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.LoginAlerts.Enabled = true
+	configuration.Notifications = Notifications{
+		Webhook:     NotificationWebhook{URL: "hooks.example.com/login"},
+		SMTP:        NotificationSMTP{Addr: "smtp.example.com", From: "security"},
+		SMS:         NotificationSMS{URL: "ftp://sms.example.com"},
+		MaxAttempts: -1,
+		Timeout:     "soon",
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	for _, key := range []string{
+		"login_alerts.enabled", "notifications.webhook.url", "notifications.smtp.addr",
+		"notifications.smtp.from", "notifications.sms.url", "notifications.max_attempts", "notifications.timeout",
+	} {
+		assert.Contains(t, err.Error(), key)
+	}
+
+	configuration.ClientInfo.UserAgent = true
+	configuration.Notifications = Notifications{
+		Webhook: NotificationWebhook{URL: "https://hooks.example.com/login"},
+		SMTP:    NotificationSMTP{Addr: "smtp.example.com:587", From: "Security <security@example.com>"},
+		SMS:     NotificationSMS{URL: "https://sms.example.com/send"},
+		Timeout: "5s",
+	}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLoginThrottle(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.LoginThrottle = LoginThrottle{Enabled: true, Store: "redis", Window: "15", MaxFailures: -1, ChallengeAfter: -3}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	for _, key := range []string{"login_throttle.store", "login_throttle.window", "login_throttle.max_failures", "login_throttle.challenge_after"} {
+		assert.Contains(t, err.Error(), key)
+	}
+	assert.NotContains(t, err.Error(), "login_throttle.max_ip_failures")
+
+	configuration.LoginThrottle = LoginThrottle{Enabled: true, Window: "10m", MaxFailures: 5, ChallengeAfter: 3}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateCaptcha(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Captcha = Captcha{
+		Provider:  "friendly",
+		MinScore:  1.5,
+		VerifyURL: "ftp://example.com",
+		Routes:    []string{"POST /api/v1/users", "/api/v1/users", "FETCH /api/v1/users"},
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	for _, key := range []string{"captcha.provider", "captcha.secret", "captcha.min_score", "captcha.verify_url", "captcha.routes[1]", "captcha.routes[2]"} {
+		assert.Contains(t, err.Error(), key)
+	}
+	assert.NotContains(t, err.Error(), "captcha.routes[0]")
+
+	configuration.Captcha = Captcha{Provider: "recaptcha", Secret: "secret", MinScore: 0.5, Routes: []string{"POST /api/v1/users"}}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateConsent(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Consent.TermsVersion = strings.Repeat("v", MaxTermsVersionLength+1)
+	assert.ErrorContains(t, configuration.Validate(), "consent.terms_version")
+
+	configuration.Consent.TermsVersion = "2026-10-01"
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateDeletion(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Deletion = Deletion{GracePeriod: "0s", Interval: "hourly", BatchSize: -1}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "account_deletion.grace_period")
+	assert.Contains(t, err.Error(), "account_deletion.interval")
+	assert.Contains(t, err.Error(), "account_deletion.batch_size")
+
+	configuration.Deletion = Deletion{GracePeriod: "168h", Interval: "0"}
+	assert.NoError(t, configuration.Validate(), "an interval of 0 disables the sweep")
+}
+
+func TestValidateEmailChange(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.EmailChange = EmailChange{TTL: "1 day", ConfirmURL: "app.example.com/confirm"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "email_change.ttl")
+	assert.Contains(t, err.Error(), "email_change.confirm_url")
+
+	configuration.EmailChange = EmailChange{TTL: "2h", ConfirmURL: "https://app.example.com/email/confirm"}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidatePhoneChange(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.PhoneChange = PhoneChange{TTL: "-10m", MaxAttempts: -1}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "phone_change.ttl")
+	assert.Contains(t, err.Error(), "phone_change.max_attempts")
+
+	configuration.PhoneChange = PhoneChange{TTL: "5m", MaxAttempts: 3}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateEmail(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Email = Email{DisposableDomains: []string{"mailinator.com", "john@yopmail.com", " "}}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"john@yopmail.com"`)
+	assert.Contains(t, err.Error(), `""`)
+	assert.NotContains(t, err.Error(), `"mailinator.com"`)
+
+	configuration.Email = Email{FoldGmail: true, DisposableDomains: []string{"mailinator.com"}}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateUsername(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Username = Username{Reserved: []string{"acme", " "}}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "username.reserved")
+
+	configuration.Username = Username{Reserved: []string{"acme"}}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateServer(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Server = Server{MaxBodySize: "1 lot", ReadTimeout: "15", IdleTimeout: "-1s", MaxHeaderBytes: "big"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.max_body_size")
+	assert.Contains(t, err.Error(), "server.read_timeout")
+	assert.Contains(t, err.Error(), "server.idle_timeout")
+	assert.Contains(t, err.Error(), "server.max_header_bytes")
+	assert.NotContains(t, err.Error(), "server.write_timeout")
+
+	configuration = validConfiguration()
+	configuration.Server.HTTP2 = HTTP2{H2C: true, MaxReadFrameSize: "16MiB", IdleTimeout: "0s"}
+	err = configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.http2.max_read_frame_size")
+	assert.Contains(t, err.Error(), "server.http2.idle_timeout")
+
+	configuration.Server.HTTP2 = HTTP2{H2C: true, MaxReadFrameSize: "16KiB", MaxConcurrentStreams: 100}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateServerTLS(t *testing.T) {
+	tests := []struct {
+		name string
+		tls  TLS
+		want []string
+	}{
+		{"Disabled", TLS{}, nil},
+		{"Static Certificate", TLS{CertFile: "cert.pem", KeyFile: "key.pem", RedirectPort: 80}, nil},
+		{"Autocert", TLS{Autocert: Autocert{Enabled: true, Hosts: []string{"api.example.com"}, CacheDir: "/var/cache/autocert"}}, nil},
+		{"Missing Key", TLS{CertFile: "cert.pem"}, []string{"server.tls: cert_file and key_file"}},
+		{"Autocert And Certificate", TLS{CertFile: "cert.pem", KeyFile: "key.pem", Autocert: Autocert{Enabled: true}}, []string{
+			"server.tls.autocert: cannot be combined", "server.tls.autocert.hosts", "server.tls.autocert.cache_dir",
+		}},
+		{"Redirect Without TLS", TLS{RedirectPort: 80}, []string{"server.tls.redirect_port: requires"}},
+		{"Redirect On Application Port", TLS{CertFile: "cert.pem", KeyFile: "key.pem", RedirectPort: 8080}, []string{"server.tls.redirect_port: must differ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configuration := validConfiguration()
+			configuration.Application.Port = 8080
+			configuration.Server.TLS = tt.tls
+
+			err := configuration.Validate()
+			if len(tt.want) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		name string
+		cors CORS
+		want []string
+	}{
+		{"Defaults", CORS{}, nil},
+		{"Origins", CORS{Origins: []string{"https://app.example.com", "https://*.example.com", "http://localhost:3000"}, AllowCredentials: true, MaxAge: "10m"}, nil},
+		{"Invalid Origins", CORS{Origins: []string{"app.example.com", "https://app.example.com/path", "https://app.*.com"}}, []string{
+			"cors.origins[0]", "cors.origins[1]", "cors.origins[2]",
+		}},
+		{"Credentials With Any Origin", CORS{Origins: []string{"*"}, AllowCredentials: true}, []string{"cors.allow_credentials"}},
+		{"Credentials Without Origins", CORS{AllowCredentials: true}, []string{"cors.allow_credentials: requires cors.origins"}},
+		{"Invalid Method And Max Age", CORS{Methods: []string{"GET", "get"}, MaxAge: "ten"}, []string{"cors.methods[1]", "cors.max_age"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configuration := validConfiguration()
+			configuration.CORS = tt.cors
+
+			err := configuration.Validate()
+			if len(tt.want) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestValidateCSRF(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.CSRF = CSRF{Enabled: true, SameSite: "relaxed", ExemptPaths: []string{"/webhooks", "webhooks"}}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "csrf.same_site")
+	assert.Contains(t, err.Error(), "csrf.exempt_paths[1]")
+	assert.NotContains(t, err.Error(), "csrf.exempt_paths[0]")
+}
+
+func TestValidateCompression(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Server.Compression = Compression{Level: 12, BrotliLevel: 4, MinSize: "big", ContentTypes: []string{"json"}}
+	assert.NoError(t, configuration.Validate(), "only checked when enabled")
+
+	configuration.Server.Compression.Enabled = true
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.compression.level")
+	assert.Contains(t, err.Error(), "server.compression.min_size")
+	assert.Contains(t, err.Error(), "server.compression.content_types[0]")
+	assert.NotContains(t, err.Error(), "server.compression.brotli_level")
+}
+
+func TestValidateResponseFormat(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Response.Format = "kebab-case"
+	assert.ErrorContains(t, configuration.Validate(), "response.format")
+
+	for _, format := range responseFormats {
+		configuration.Response.Format = format
+		assert.NoError(t, configuration.Validate())
+	}
+}
+
+func TestValidateResponseCache(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Redis.DB = -1
+	configuration.ResponseCache = ResponseCache{Store: "redis", TTL: "soon", MaxEntries: -1}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redis.db")
+	assert.Contains(t, err.Error(), "redis.addr")
+	assert.Contains(t, err.Error(), "response_cache.ttl")
+	assert.Contains(t, err.Error(), "response_cache.max_entries")
+
+	configuration.Redis = Redis{Addr: "localhost:6379"}
+	configuration.ResponseCache = ResponseCache{Store: "redis", TTL: "1m"}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateRepositoryCache(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.RepositoryCache = RepositoryCache{Store: "redis", TTL: "0s", MaxEntries: -1}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repository_cache.store")
+	assert.Contains(t, err.Error(), "repository_cache.ttl")
+	assert.Contains(t, err.Error(), "repository_cache.max_entries")
+
+	configuration.Redis = Redis{Addr: "localhost:6379"}
+	configuration.RepositoryCache = RepositoryCache{Store: "redis", TTL: "1m"}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLock(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Lock = Lock{Store: "redis", TTL: "forever"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lock.store")
+	assert.Contains(t, err.Error(), "lock.ttl")
+
+	configuration.Redis = Redis{Addr: "localhost:6379"}
+	configuration.Lock = Lock{Store: "redis", TTL: "10s"}
+	assert.NoError(t, configuration.Validate())
+}
+
+func TestValidateLeader(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Leader.RetryInterval = "often"
+	assert.ErrorContains(t, configuration.Validate(), "leader.retry_interval")
+
+	configuration.Leader.RetryInterval = "0"
+	assert.NoError(t, configuration.Validate(), "0 disables the election")
+}
+
+func TestValidateMaintenance(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Maintenance.RetryAfter = "soon"
+	assert.ErrorContains(t, configuration.Validate(), "maintenance.retry_after")
+}
+
+func TestValidateConcurrency(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Concurrency = Concurrency{
+		MaxInFlight:  100,
+		QueueTimeout: "100ms",
+		Routes: []ConcurrencyRoute{
+			{Route: "GET /api/v1/admin/users/export", MaxInFlight: 2},
+			{Route: "/api/v1/users", MaxInFlight: 0},
+		},
+	}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "concurrency.routes[1].route")
+	assert.Contains(t, err.Error(), "concurrency.routes[1].max_in_flight")
+	assert.NotContains(t, err.Error(), "concurrency.routes[0]")
+}
+
+func TestValidateAPIClients(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Authorization.APIKey = "shared-api-key-0123456789abcdef-0123"
+	configuration.Authorization.Clients = []APIClient{
+		{Name: "partner-a", Key: "partner-a-key-0123456789abcdef-01234", DailyQuota: 1000},
+		{Name: "partner-a", Key: "shared-api-key-0123456789abcdef-0123"},
+		{Key: "short", MonthlyQuota: -1},
+	}
+	configuration.Quota.FlushInterval = "soon"
+	configuration.Quota.OverridesTTL = "-1s"
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "authorization.clients[1].name")
+	assert.Contains(t, err.Error(), "authorization.clients[1].key")
+	assert.Contains(t, err.Error(), "authorization.clients[2].name")
+	assert.Contains(t, err.Error(), "authorization.clients[2].key")
+	assert.Contains(t, err.Error(), "authorization.clients[2].monthly_quota")
+	assert.Contains(t, err.Error(), "quota.flush_interval")
+	assert.Contains(t, err.Error(), "quota.overrides_ttl")
+	assert.NotContains(t, err.Error(), "authorization.clients[0]")
+}
+
+func TestValidateDebugCapture(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.DebugCapture = DebugCapture{Enabled: true, MaxBodySize: "lots", Retention: "3d", TokenTTL: "1h"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "debug_capture.enabled")
+	assert.Contains(t, err.Error(), "debug_capture.max_body_size")
+	assert.Contains(t, err.Error(), "debug_capture.retention")
+	assert.NotContains(t, err.Error(), "debug_capture.token_ttl")
+}
+
+func TestValidateDocs(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Docs.Environments = []string{"local", "qa"}
+
+	err := configuration.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "docs.environments[1]")
+	assert.NotContains(t, err.Error(), "docs.environments[0]")
+}
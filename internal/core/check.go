@@ -0,0 +1,41 @@
+package core
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/hashc"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/pii"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// CheckConfiguration runs the checks NewApp does on top of config.Initialize's validation
+// (weak jwt secrets, hashing cost, account number profiles, ...) without connecting to
+// anything, so a bad config fails before a deploy. All problems are reported at once.
+func CheckConfiguration(configuration *config.Configuration) error {
+	var errs *multierror.Error
+
+	if _, err := jwtc.FromConfig(configuration); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := hashc.DefaultConfig(configuration).Validate(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if _, err := pii.FromConfig(configuration.PII); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := registerAccountNumberProfiles(configuration); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if configuration.Password.BreachCheck.Enabled {
+		if _, err := newPasswordBreachChecker(configuration); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	if _, err := newDisposableDomains(configuration); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validConfiguration passes CheckConfiguration
+func validConfiguration() *config.Configuration {
+	configuration := &config.Configuration{}
+	configuration.Authorization.Access = config.TokenConfiguration{Secret: "access-secret-0123456789abcdefghij", Duration: "15m"}
+	configuration.Authorization.Refresh = config.TokenConfiguration{Secret: "refresh-secret-0123456789abcdefghi", Duration: "168h"}
+	configuration.AccountNumber.Profile = "card16"
+	return configuration
+}
+
+func TestCheckConfiguration(t *testing.T) {
+	assert.NoError(t, CheckConfiguration(validConfiguration()))
+
+	configuration := validConfiguration()
+	configuration.Authorization.Access.Secret = "change-me"
+	configuration.AccountNumber.Profile = "unknown"
+	configuration.Password.BreachCheck = config.BreachCheck{Enabled: true, Timeout: "soon"}
+
+	err := CheckConfiguration(configuration)
+	assert.ErrorContains(t, err, "authorization.access.secret")
+	assert.ErrorContains(t, err, `unknown account_number.profile "unknown"`)
+	assert.ErrorContains(t, err, "password.breach_check.timeout")
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/graceful"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"io"
+	stdlog "log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	gbytes "github.com/labstack/gommon/bytes"
+	"github.com/labstack/gommon/log"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+)
+
+// newEcho creates the Echo instance: no banner or startup line on stdout, Echo's own logs
+// and the http.Server error log go to the application logger, and the server timeouts
+// and header limit come from server.* or the defaults below, and the JSON of the responses
+// is shaped by response.format.
+func newEcho(configuration *config.Configuration) (*echo.Echo, error) {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	echoLog := &echoLogger{log: logger.L()}
+	e.Logger = echoLog
+	e.StdLogger = stdlog.New(echoLog, "", 0)
+	e.Server.ErrorLog = e.StdLogger
+	e.JSONSerializer = response.NewSerializer(configuration.Response.Format)
+
+	if err := configureServer(e, configuration.Server); err != nil {
+		return nil, err
+	}
+	if autoTLS := configuration.Server.TLS.Autocert; autoTLS.Enabled {
+		e.AutoTLSManager.Prompt = autocert.AcceptTOS
+		e.AutoTLSManager.HostPolicy = autocert.HostWhitelist(autoTLS.Hosts...)
+		e.AutoTLSManager.Cache = autocert.DirCache(autoTLS.CacheDir)
+		e.AutoTLSManager.Email = autoTLS.Email
+	}
+	return e, nil
+}
+
+// Defaults for the server.* settings left unset. net/http has no timeouts at all, which
+// lets slow or idle clients hold connections open forever. Handlers that stream or run
+// longer than the write timeout extend their own deadline with response.ExtendWriteDeadline.
+const (
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultMaxHeaderBytes    = 1 << 20 // 1MiB
+
+	DefaultHTTP2MaxConcurrentStreams = 250
+	DefaultHTTP2MaxReadFrameSize     = 1 << 20 // 1MiB
+)
+
+// configureServer applies the server.* tuning to the HTTP and HTTPS servers, using the
+// defaults above for unset values
+func configureServer(e *echo.Echo, cfg config.Server) error {
+	timeouts := []struct {
+		key      string
+		value    string
+		fallback time.Duration
+		target   *time.Duration
+	}{
+		{"server.read_timeout", cfg.ReadTimeout, DefaultReadTimeout, &e.Server.ReadTimeout},
+		{"server.read_header_timeout", cfg.ReadHeaderTimeout, DefaultReadHeaderTimeout, &e.Server.ReadHeaderTimeout},
+		{"server.write_timeout", cfg.WriteTimeout, DefaultWriteTimeout, &e.Server.WriteTimeout},
+		{"server.idle_timeout", cfg.IdleTimeout, DefaultIdleTimeout, &e.Server.IdleTimeout},
+	}
+	for _, timeout := range timeouts {
+		parsed, err := parseDuration(timeout.key, timeout.value, timeout.fallback)
+		if err != nil {
+			return err
+		}
+		*timeout.target = parsed
+	}
+	e.TLSServer.ReadTimeout = e.Server.ReadTimeout
+	e.TLSServer.ReadHeaderTimeout = e.Server.ReadHeaderTimeout
+	e.TLSServer.WriteTimeout = e.Server.WriteTimeout
+	e.TLSServer.IdleTimeout = e.Server.IdleTimeout
+
+	size, err := parseSize("server.max_header_bytes", cfg.MaxHeaderBytes, DefaultMaxHeaderBytes)
+	if err != nil {
+		return err
+	}
+	e.Server.MaxHeaderBytes = int(size)
+	e.TLSServer.MaxHeaderBytes = int(size)
+
+	// HTTPS (static or autocert) negotiates HTTP/2 through TLSNextProto, which keeps the
+	// server.http2 settings when echo replaces the TLS config on start
+	h2s, err := newHTTP2Server(cfg)
+	if err != nil {
+		return err
+	}
+	return http2.ConfigureServer(e.TLSServer, h2s)
+}
+
+// ServerOptions returns the graceful.EchoProcess options for server.*: HTTPS with the
+// plain HTTP redirect listener when server.tls is configured, otherwise h2c when
+// server.http2.h2c is enabled. Either way HTTP/2 uses the server.http2 settings.
+func ServerOptions(configuration *config.Configuration) ([]graceful.EchoOption, error) {
+	cfg := configuration.Server
+
+	var options []graceful.EchoOption
+	switch {
+	case cfg.TLS.Autocert.Enabled:
+		options = append(options, graceful.WithAutoTLS())
+	case cfg.TLS.CertFile != "":
+		options = append(options, graceful.WithTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile))
+	case cfg.HTTP2.H2C:
+		h2s, err := newHTTP2Server(cfg)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, graceful.WithH2C(h2s))
+	}
+
+	if cfg.TLS.Enabled() && cfg.TLS.RedirectPort != 0 {
+		options = append(options, graceful.WithHTTPRedirect(fmt.Sprintf(":%d", cfg.TLS.RedirectPort)))
+	}
+	return options, nil
+}
+
+// newHTTP2Server builds the HTTP/2 settings in server.http2
+func newHTTP2Server(cfg config.Server) (*http2.Server, error) {
+	idle, err := parseDuration("server.idle_timeout", cfg.IdleTimeout, DefaultIdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if idle, err = parseDuration("server.http2.idle_timeout", cfg.HTTP2.IdleTimeout, idle); err != nil {
+		return nil, err
+	}
+	frameSize, err := parseSize("server.http2.max_read_frame_size", cfg.HTTP2.MaxReadFrameSize, DefaultHTTP2MaxReadFrameSize)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := cfg.HTTP2.MaxConcurrentStreams
+	if streams == 0 {
+		streams = DefaultHTTP2MaxConcurrentStreams
+	}
+
+	return &http2.Server{
+		MaxConcurrentStreams: streams,
+		MaxReadFrameSize:     uint32(frameSize),
+		IdleTimeout:          idle,
+	}, nil
+}
+
+// parseDuration parses a server.* duration, fallback when it is unset
+func parseDuration(key, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// parseSize parses a server.* size such as "1MB", fallback when it is unset
+func parseSize(key, value string, fallback int64) (int64, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	size, err := gbytes.Parse(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return size, nil
+}
+
+// echoLogger implements echo.Logger with the application logger. The level, prefix, header,
+// and output are owned by logger.* and cannot be changed through Echo.
+type echoLogger struct {
+	log logger.Logger
+}
+
+var _ echo.Logger = (*echoLogger)(nil)
+
+// Write logs each write (e.g. from the http.Server error log) as an error
+func (l *echoLogger) Write(p []byte) (int, error) {
+	l.log.Error(context.Background(), strings.TrimSuffix(string(p), "\n"), echoField)
+	return len(p), nil
+}
+
+func (l *echoLogger) Output() io.Writer     { return l }
+func (l *echoLogger) SetOutput(w io.Writer) {}
+func (l *echoLogger) Prefix() string        { return "echo" }
+func (l *echoLogger) SetPrefix(p string)    {}
+func (l *echoLogger) SetHeader(h string)    {}
+func (l *echoLogger) SetLevel(v log.Lvl)    {}
+
+func (l *echoLogger) Level() log.Lvl {
+	switch logger.GetLevel() {
+	case "debug":
+		return log.DEBUG
+	case "info":
+		return log.INFO
+	case "warn":
+		return log.WARN
+	case "error":
+		return log.ERROR
+	default:
+		return log.OFF
+	}
+}
+
+func (l *echoLogger) Print(i ...interface{})                    { l.Info(i...) }
+func (l *echoLogger) Printf(format string, args ...interface{}) { l.Infof(format, args...) }
+func (l *echoLogger) Printj(j log.JSON)                         { l.Infoj(j) }
+
+func (l *echoLogger) Debug(i ...interface{}) { emit(l.log.Debug, fmt.Sprint(i...)) }
+func (l *echoLogger) Debugf(format string, args ...interface{}) {
+	l.Debug(fmt.Sprintf(format, args...))
+}
+func (l *echoLogger) Debugj(j log.JSON) { emitJSON(l.log.Debug, j) }
+
+func (l *echoLogger) Info(i ...interface{})                    { emit(l.log.Info, fmt.Sprint(i...)) }
+func (l *echoLogger) Infof(format string, args ...interface{}) { l.Info(fmt.Sprintf(format, args...)) }
+func (l *echoLogger) Infoj(j log.JSON)                         { emitJSON(l.log.Info, j) }
+
+func (l *echoLogger) Warn(i ...interface{})                    { emit(l.log.Warn, fmt.Sprint(i...)) }
+func (l *echoLogger) Warnf(format string, args ...interface{}) { l.Warn(fmt.Sprintf(format, args...)) }
+func (l *echoLogger) Warnj(j log.JSON)                         { emitJSON(l.log.Warn, j) }
+
+func (l *echoLogger) Error(i ...interface{}) { emit(l.log.Error, fmt.Sprint(i...)) }
+func (l *echoLogger) Errorf(format string, args ...interface{}) {
+	l.Error(fmt.Sprintf(format, args...))
+}
+func (l *echoLogger) Errorj(j log.JSON) { emitJSON(l.log.Error, j) }
+
+func (l *echoLogger) Fatal(i ...interface{}) { emit(l.log.Fatal, fmt.Sprint(i...)) }
+func (l *echoLogger) Fatalf(format string, args ...interface{}) {
+	l.Fatal(fmt.Sprintf(format, args...))
+}
+func (l *echoLogger) Fatalj(j log.JSON) { emitJSON(l.log.Fatal, j) }
+
+// Panic logs at error level and panics with the message
+func (l *echoLogger) Panic(i ...interface{}) {
+	msg := fmt.Sprint(i...)
+	emit(l.log.Error, msg)
+	panic(msg)
+}
+
+func (l *echoLogger) Panicf(format string, args ...interface{}) {
+	l.Panic(fmt.Sprintf(format, args...))
+}
+
+func (l *echoLogger) Panicj(j log.JSON) {
+	emitJSON(l.log.Error, j)
+	panic(fmt.Sprint(j))
+}
+
+type logFunc func(ctx context.Context, msg string, fields ...logger.Field)
+
+func emit(fn logFunc, msg string) {
+	fn(context.Background(), msg, echoField)
+}
+
+// emitJSON logs the fields of a *j call, sorted by key
+func emitJSON(fn logFunc, j log.JSON) {
+	fields := []logger.Field{echoField}
+	for _, key := range slices.Sorted(maps.Keys(j)) {
+		fields = append(fields, logger.Any(key, j[key]))
+	}
+	fn(context.Background(), "echo", fields...)
+}
+
+var echoField = logger.String("component", "echo")
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEcho(t *testing.T) {
+	e, err := newEcho(&config.Configuration{Server: config.Server{
+		ReadTimeout:    "15s",
+		WriteTimeout:   "30s",
+		IdleTimeout:    "1m",
+		MaxHeaderBytes: "64KB",
+	}})
+	require.NoError(t, err)
+
+	assert.True(t, e.HideBanner)
+	assert.True(t, e.HidePort)
+	assert.IsType(t, &echoLogger{}, e.Logger)
+	assert.Equal(t, 15*time.Second, e.Server.ReadTimeout)
+	assert.Equal(t, DefaultReadHeaderTimeout, e.Server.ReadHeaderTimeout, "unset uses the default")
+	assert.Equal(t, 30*time.Second, e.Server.WriteTimeout)
+	assert.Equal(t, time.Minute, e.Server.IdleTimeout)
+	assert.Equal(t, 64000, e.Server.MaxHeaderBytes)
+	assert.Contains(t, e.TLSServer.TLSNextProto, "h2", "HTTPS uses the server.http2 settings")
+
+	_, err = newEcho(&config.Configuration{Server: config.Server{WriteTimeout: "soon"}})
+	assert.ErrorContains(t, err, "server.write_timeout")
+
+	_, err = newEcho(&config.Configuration{Server: config.Server{HTTP2: config.HTTP2{MaxReadFrameSize: "big"}}})
+	assert.ErrorContains(t, err, "server.http2.max_read_frame_size")
+}
+
+func TestNewEcho_Defaults(t *testing.T) {
+	e, err := newEcho(&config.Configuration{})
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultReadTimeout, e.Server.ReadTimeout)
+	assert.Equal(t, DefaultReadHeaderTimeout, e.Server.ReadHeaderTimeout)
+	assert.Equal(t, DefaultWriteTimeout, e.Server.WriteTimeout)
+	assert.Equal(t, DefaultIdleTimeout, e.Server.IdleTimeout)
+	assert.Equal(t, DefaultMaxHeaderBytes, e.Server.MaxHeaderBytes)
+}
+
+func TestServerOptions(t *testing.T) {
+	options, err := ServerOptions(&config.Configuration{})
+	require.NoError(t, err)
+	assert.Empty(t, options, "h2c is off by default")
+
+	options, err = ServerOptions(&config.Configuration{Server: config.Server{
+		HTTP2: config.HTTP2{H2C: true},
+	}})
+	require.NoError(t, err)
+	assert.Len(t, options, 1)
+
+	_, err = ServerOptions(&config.Configuration{Server: config.Server{
+		HTTP2: config.HTTP2{H2C: true, IdleTimeout: "later"},
+	}})
+	assert.ErrorContains(t, err, "server.http2.idle_timeout")
+}
+
+func TestServerOptions_TLS(t *testing.T) {
+	options, err := ServerOptions(&config.Configuration{Server: config.Server{
+		HTTP2: config.HTTP2{H2C: true},
+		TLS:   config.TLS{CertFile: "cert.pem", KeyFile: "key.pem", RedirectPort: 8080},
+	}})
+	require.NoError(t, err)
+	assert.Len(t, options, 2, "TLS replaces h2c, plus the redirect listener")
+
+	e, err := newEcho(&config.Configuration{Server: config.Server{
+		TLS: config.TLS{Autocert: config.Autocert{Enabled: true, Hosts: []string{"api.example.com"}, CacheDir: t.TempDir()}},
+	}})
+	require.NoError(t, err)
+	assert.NotNil(t, e.AutoTLSManager.Cache)
+	assert.NoError(t, e.AutoTLSManager.HostPolicy(t.Context(), "api.example.com"))
+	assert.Error(t, e.AutoTLSManager.HostPolicy(t.Context(), "evil.example.com"))
+	assert.Equal(t, DefaultWriteTimeout, e.TLSServer.WriteTimeout)
+}
+
+func TestNewHTTP2Server(t *testing.T) {
+	h2s, err := newHTTP2Server(config.Server{IdleTimeout: "90s"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(DefaultHTTP2MaxConcurrentStreams), h2s.MaxConcurrentStreams)
+	assert.Equal(t, uint32(DefaultHTTP2MaxReadFrameSize), h2s.MaxReadFrameSize)
+	assert.Equal(t, 90*time.Second, h2s.IdleTimeout, "falls back to server.idle_timeout")
+
+	h2s, err = newHTTP2Server(config.Server{HTTP2: config.HTTP2{
+		MaxConcurrentStreams: 100,
+		MaxReadFrameSize:     "64KiB",
+		IdleTimeout:          "30s",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100), h2s.MaxConcurrentStreams)
+	assert.Equal(t, uint32(64<<10), h2s.MaxReadFrameSize)
+	assert.Equal(t, 30*time.Second, h2s.IdleTimeout)
+}
+
+func TestEchoLogger(t *testing.T) {
+	test := logger.NewTestLogger()
+	var l echo.Logger = &echoLogger{log: test}
+
+	l.Warnf("slow %s", "client")
+	l.Errorj(log.JSON{"status": 500, "path": "/"})
+	_, _ = l.Output().Write([]byte("http: TLS handshake error\n"))
+	assert.Panics(t, func() { l.Panic("boom") })
+
+	events := test.Events()
+	require.Len(t, events, 4)
+	assert.Equal(t, "warn", events[0].Level)
+	assert.Equal(t, "slow client", events[0].Message)
+	assert.Equal(t, "echo", events[0].Fields["component"])
+	assert.EqualValues(t, 500, events[1].Fields["status"])
+	assert.Equal(t, "http: TLS handshake error", events[2].Message)
+	assert.Equal(t, "error", events[3].Level)
+
+	// The level follows logger.level
+	require.NoError(t, logger.SetLevel("warn"))
+	t.Cleanup(func() { _ = logger.SetLevel("info") })
+	assert.Equal(t, log.WARN, l.Level())
+}
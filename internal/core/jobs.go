@@ -0,0 +1,142 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/graceful"
+	"go-echo-boilerplate/internal/pkg/leader"
+	"go-echo-boilerplate/internal/pkg/lock"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/service"
+	"time"
+)
+
+// defaultDeletionInterval is used when account_deletion.interval is not configured
+const defaultDeletionInterval = time.Hour
+
+var jobs map[string]graceful.Process
+
+// Jobs returns the background processes of the application built by Setup, to run next to
+// the HTTP server.
+func Jobs() map[string]graceful.Process {
+	return jobs
+}
+
+// newJobs creates the background processes enabled in the configuration
+func newJobs(configuration *config.Configuration, services *service.Service) (map[string]graceful.Process, error) {
+	processes := map[string]graceful.Process{}
+
+	elector, err := newElector(configuration.Leader, lock.Default())
+	if err != nil {
+		return nil, err
+	}
+	if elector != nil {
+		processes["leader-election"] = elector
+	}
+	leader.SetDefault(elector)
+
+	sweep, err := newDeletionSweep(configuration.Deletion, services.User)
+	if err != nil {
+		return nil, err
+	}
+	if sweep != nil {
+		processes["account-deletion"] = sweep
+	}
+
+	usage, err := newUsageFlush(configuration.Quota, quota.Default())
+	if err != nil {
+		return nil, err
+	}
+	if usage != nil {
+		processes["api-usage"] = usage
+	}
+
+	return processes, nil
+}
+
+// newElector campaigns for the leadership of the singleton background processes with
+// locker, nil without a locker or when leader.retry_interval is "0"
+func newElector(cfg config.Leader, locker lock.Locker) (*leader.Elector, error) {
+	if locker == nil || cfg.RetryInterval == "0" {
+		return nil, nil
+	}
+
+	var retryInterval time.Duration
+	if cfg.RetryInterval != "" {
+		parsed, err := time.ParseDuration(cfg.RetryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid leader.retry_interval: %w", err)
+		}
+		retryInterval = parsed
+	}
+	return leader.New(locker, "jobs", retryInterval), nil
+}
+
+// newDeletionSweep anonymizes the accounts whose deletion is due every
+// account_deletion.interval, nil when the interval is "0"
+func newDeletionSweep(cfg config.Deletion, users service.UserService) (*graceful.TickerProcess, error) {
+	interval := defaultDeletionInterval
+	switch cfg.Interval {
+	case "":
+	case "0":
+		return nil, nil
+	default:
+		parsed, err := time.ParseDuration(cfg.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account_deletion.interval: %w", err)
+		}
+		interval = parsed
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = service.DefaultDeletionBatchSize
+	}
+
+	// The leader sweeps; the lock keeps a former leader that has not noticed its loss yet
+	// from sweeping alongside the new one
+	return graceful.NewTickerProcess(interval, leader.OnlyLeader(func(ctx context.Context) {
+		err := lock.WithLock(ctx, "jobs:account-deletion", func(ctx context.Context) error {
+			anonymized, err := users.AnonymizeDueAccounts(ctx, batchSize)
+			if err != nil {
+				logger.L().Error(ctx, "account deletion sweep failed", logger.Int("anonymized", anonymized), logger.Error(err))
+				return nil
+			}
+			if anonymized > 0 {
+				logger.L().Info(ctx, "account deletion sweep", logger.Int("anonymized", anonymized))
+			}
+			return nil
+		})
+		if errors.Is(err, lock.ErrNotAcquired) {
+			logger.L().Debug(ctx, "account deletion sweep running on another replica")
+		} else if err != nil {
+			logger.L().Error(ctx, "account deletion sweep lock failed", logger.Error(err))
+		}
+	})), nil
+}
+
+// newUsageFlush persists the API client usage counted by this instance every
+// quota.flush_interval, nil without quotas. Every replica flushes its own counts.
+func newUsageFlush(cfg config.Quota, quotas *quota.Quotas) (*graceful.TickerProcess, error) {
+	if quotas == nil {
+		return nil, nil
+	}
+
+	interval := quota.DefaultFlushInterval
+	if cfg.FlushInterval != "" {
+		parsed, err := time.ParseDuration(cfg.FlushInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quota.flush_interval: %w", err)
+		}
+		interval = parsed
+	}
+
+	return graceful.NewTickerProcess(interval, func(ctx context.Context) {
+		if err := quotas.Flush(ctx); err != nil {
+			logger.L().Error(ctx, "api usage flush failed", logger.Error(err))
+		}
+	}), nil
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/lock"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJobs(t *testing.T) {
+	services := &service.Service{}
+
+	processes, err := newJobs(&config.Configuration{}, services)
+	require.NoError(t, err)
+	assert.Contains(t, processes, "account-deletion", "the sweep runs by default")
+
+	processes, err = newJobs(&config.Configuration{Deletion: config.Deletion{Interval: "0"}}, services)
+	require.NoError(t, err)
+	assert.NotContains(t, processes, "account-deletion")
+
+	_, err = newJobs(&config.Configuration{Deletion: config.Deletion{Interval: "hourly"}}, services)
+	assert.ErrorContains(t, err, "account_deletion.interval")
+}
+
+func TestNewElector(t *testing.T) {
+	locker := lock.NewRedisLocker(0)
+
+	elector, err := newElector(config.Leader{}, locker)
+	require.NoError(t, err)
+	assert.NotNil(t, elector, "elected by default")
+
+	elector, err = newElector(config.Leader{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, elector, "every replica leads without locks")
+
+	elector, err = newElector(config.Leader{RetryInterval: "0"}, locker)
+	require.NoError(t, err)
+	assert.Nil(t, elector)
+
+	_, err = newElector(config.Leader{RetryInterval: "often"}, locker)
+	assert.ErrorContains(t, err, "leader.retry_interval")
+}
+
+func TestNewUsageFlush(t *testing.T) {
+	quotas, err := quota.FromConfig(config.Authorization{Clients: []config.APIClient{{Name: "partner-a", Key: "key-a"}}}, memory.NewAPIUsageRepository())
+	require.NoError(t, err)
+
+	flush, err := newUsageFlush(config.Quota{}, quotas)
+	require.NoError(t, err)
+	assert.NotNil(t, flush)
+
+	flush, err = newUsageFlush(config.Quota{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, flush, "no client")
+
+	_, err = newUsageFlush(config.Quota{FlushInterval: "often"}, quotas)
+	assert.ErrorContains(t, err, "quota.flush_interval")
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"fmt"
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/pkg/di"
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Module is a self-contained feature: it registers its repositories and services in the
+// container, adds its handlers to the API versions, and ships its SQL migrations. A module
+// registers itself from the init of its package with RegisterModule, and is enabled by a
+// blank import in internal/modules, so a new feature does not touch core, the router, or
+// the service and repository aggregates.
+//
+// Usage:
+//
+//	func init() { core.RegisterModule(Module{}) }
+//
+//	func (Module) RegisterServices(c *di.Container) { di.Provide(c, NewOrderService) }
+//
+//	func (Module) RegisterRoutes(routes *api.Routes) error {
+//		orders, err := di.Resolve[OrderService](routes.Container)
+//		if err != nil {
+//			return err
+//		}
+//		NewOrderV1(routes.Version("v1"), orders, routes.JWTConfig)
+//		return nil
+//	}
+type Module interface {
+	// Name identifies the module, e.g. "users"
+	Name() string
+
+	// RegisterRepositories provides the module's repositories to the container
+	RegisterRepositories(c *di.Container)
+
+	// RegisterServices provides the module's services to the container
+	RegisterServices(c *di.Container)
+
+	// RegisterRoutes adds the module's handlers to the mounted API versions
+	api.RouteRegistrar
+
+	// Migrations returns the module's goose SQL migrations (at the root of the FS), nil when it has none
+	Migrations() fs.FS
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   []Module
+)
+
+// RegisterModule adds a module to the application. It panics when a module with the same
+// name is already registered.
+func RegisterModule(module Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	for _, registered := range modules {
+		if registered.Name() == module.Name() {
+			panic(fmt.Sprintf("core: module %q is already registered", module.Name()))
+		}
+	}
+	modules = append(modules, module)
+}
+
+// Modules returns the registered modules in registration order.
+func Modules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	return append([]Module(nil), modules...)
+}
+
+// routeRegistrars returns the modules as the route registrars of handler.New
+func routeRegistrars(modules []Module) []api.RouteRegistrar {
+	registrars := make([]api.RouteRegistrar, len(modules))
+	for i, module := range modules {
+		registrars[i] = module
+	}
+	return registrars
+}
+
+// Migrations merges the SQL migrations of the modules into one directory for goose.
+// Migration files are versioned by their name prefix, so two modules shipping the same
+// file name is an error.
+func Migrations(modules ...Module) (fs.FS, error) {
+	merged := &migrationFS{files: make(map[string]migrationFile)}
+	for _, module := range modules {
+		fsys := module.Migrations()
+		if fsys == nil {
+			continue
+		}
+
+		names, err := fs.Glob(fsys, "*.sql")
+		if err != nil {
+			return nil, fmt.Errorf("module %s migrations: %w", module.Name(), err)
+		}
+		for _, name := range names {
+			if owner, ok := merged.files[name]; ok {
+				return nil, fmt.Errorf("migration %s is shipped by modules %s and %s", name, owner.module, module.Name())
+			}
+			merged.files[name] = migrationFile{module: module.Name(), fsys: fsys}
+		}
+	}
+	return merged, nil
+}
+
+// migrationFS is a flat, read-only directory of the migration files of several modules
+type migrationFS struct {
+	files map[string]migrationFile
+}
+
+type migrationFile struct {
+	module string
+	fsys   fs.FS
+}
+
+// Open implements fs.FS.
+func (m *migrationFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		entries, err := m.ReadDir(".")
+		if err != nil {
+			return nil, err
+		}
+		return &migrationDir{entries: entries}, nil
+	}
+	if file, ok := m.files[name]; ok {
+		return file.fsys.Open(name)
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (m *migrationFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(m.files))
+	for name, file := range m.files {
+		info, err := fs.Stat(file.fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// migrationDir is the opened root directory of a migrationFS
+type migrationDir struct {
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *migrationDir) Stat() (fs.FileInfo, error) { return migrationDirInfo{}, nil }
+func (d *migrationDir) Close() error               { return nil }
+
+func (d *migrationDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: fs.ErrInvalid}
+}
+
+func (d *migrationDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
+
+type migrationDirInfo struct{}
+
+func (migrationDirInfo) Name() string       { return "." }
+func (migrationDirInfo) Size() int64        { return 0 }
+func (migrationDirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (migrationDirInfo) ModTime() time.Time { return time.Time{} }
+func (migrationDirInfo) IsDir() bool        { return true }
+func (migrationDirInfo) Sys() any           { return nil }
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/pkg/di"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testModule struct {
+	name       string
+	migrations fs.FS
+}
+
+func (m testModule) Name() string                       { return m.name }
+func (m testModule) RegisterRepositories(*di.Container) {}
+func (m testModule) RegisterServices(*di.Container)     {}
+func (m testModule) RegisterRoutes(*api.Routes) error   { return nil }
+func (m testModule) Migrations() fs.FS                  { return m.migrations }
+
+func TestRegisterModule(t *testing.T) {
+	RegisterModule(testModule{name: "test-registry"})
+
+	names := make([]string, 0)
+	for _, module := range Modules() {
+		names = append(names, module.Name())
+	}
+	assert.Contains(t, names, "test-registry")
+
+	assert.PanicsWithValue(t, `core: module "test-registry" is already registered`, func() {
+		RegisterModule(testModule{name: "test-registry"})
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	users := testModule{name: "users", migrations: fstest.MapFS{
+		"20260114144004_users.sql": {Data: []byte("-- +goose Up")},
+		"README.md":                {Data: []byte("not a migration")},
+	}}
+	orders := testModule{name: "orders", migrations: fstest.MapFS{
+		"20260201090000_orders.sql": {Data: []byte("-- +goose Up")},
+	}}
+
+	merged, err := Migrations(orders, users, testModule{name: "empty"})
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(merged, "20260114144004_users.sql", "20260201090000_orders.sql"))
+
+	entries, err := fs.ReadDir(merged, ".")
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "only the SQL files are merged")
+	assert.Equal(t, "20260114144004_users.sql", entries[0].Name(), "in version order")
+
+	data, err := fs.ReadFile(merged, "20260201090000_orders.sql")
+	require.NoError(t, err)
+	assert.Equal(t, "-- +goose Up", string(data))
+}
+
+func TestMigrations_Duplicate(t *testing.T) {
+	migrations := fstest.MapFS{"20260114144004_users.sql": {Data: []byte("-- +goose Up")}}
+
+	_, err := Migrations(testModule{name: "users", migrations: migrations}, testModule{name: "accounts", migrations: migrations})
+	assert.EqualError(t, err, "migration 20260114144004_users.sql is shipped by modules users and accounts")
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/captcha"
+	"go-echo-boilerplate/internal/pkg/capture"
+	"go-echo-boilerplate/internal/pkg/clientinfo"
+	"go-echo-boilerplate/internal/pkg/database"
+	"go-echo-boilerplate/internal/pkg/di"
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/hashc"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/limiter"
+	"go-echo-boilerplate/internal/pkg/lock"
+	"go-echo-boilerplate/internal/pkg/loginguard"
+	"go-echo-boilerplate/internal/pkg/notify"
+	"go-echo-boilerplate/internal/pkg/pii"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/pkg/session"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"go-echo-boilerplate/internal/service"
+	"os"
+	"time"
+)
+
+// provide registers the infrastructure every layer builds on: database connections,
+// token and hashing configurations, and the package-level defaults installed by installDefaults.
+func provide(c *di.Container) {
+	di.Provide(c, func(configuration *config.Configuration) (*database.Database, error) {
+		db, err := database.Connect(configuration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		setDB(db)
+		return db, nil
+	})
+
+	di.Provide(c, jwtc.FromConfig)
+
+	di.Provide(c, func(configuration *config.Configuration) (*hashc.Configuration, error) {
+		hashConfig := hashc.DefaultConfig(configuration)
+		if err := hashConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid password hashing configuration: %w", err)
+		}
+		return hashConfig, nil
+	})
+
+	// nil when password.breach_check is disabled
+	di.Provide(c, func(configuration *config.Configuration) (validator.PasswordBreachChecker, error) {
+		if !configuration.Password.BreachCheck.Enabled {
+			return nil, nil
+		}
+		checker, err := newPasswordBreachChecker(configuration)
+		if err != nil {
+			return nil, err
+		}
+		return checker, nil
+	})
+
+	// nil when email.disposable_domains and email.disposable_domains_file are empty
+	di.Provide(c, newDisposableDomains)
+
+	di.Provide(c, func(configuration *config.Configuration, db *database.Database) (*cache.Responses, error) {
+		responses, err := cache.FromConfig(configuration.ResponseCache, db.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response cache configuration: %w", err)
+		}
+		return responses, nil
+	})
+
+	di.Provide(c, func(configuration *config.Configuration) (*pii.Tokenizer, error) {
+		return pii.FromConfig(configuration.PII)
+	})
+
+	di.Provide(c, newRevocations)
+
+	// nil when client_info enables nothing
+	di.Provide(c, func(configuration *config.Configuration) (*clientinfo.Resolver, error) {
+		return clientinfo.FromConfig(configuration.ClientInfo)
+	})
+
+	// nil when captcha.provider is empty
+	di.Provide(c, func(configuration *config.Configuration) (*captcha.SiteVerifier, error) {
+		return captcha.FromConfig(configuration.Captcha)
+	})
+
+	// nil when login_throttle is disabled; challenges are enforced with the CAPTCHA verifier
+	di.Provide(c, func(configuration *config.Configuration, db *database.Database, verifier *captcha.SiteVerifier) (*loginguard.Guard, error) {
+		guard, err := loginguard.FromConfig(configuration.LoginThrottle, db.Redis)
+		if err != nil || guard == nil || verifier == nil {
+			return guard, err
+		}
+		return guard.WithChallenger(verifier), nil
+	})
+
+	// nil when no notification channel is configured
+	di.Provide(c, func(configuration *config.Configuration, repository *pgsql.PostgreRepository) (*notify.Queue, error) {
+		queue, err := newNotifications(configuration, repository.Notification)
+		if err != nil {
+			return nil, err
+		}
+		setNotifications(queue)
+		return queue, nil
+	})
+
+	di.Provide(c, newLocker)
+
+	// nil when concurrency caps nothing
+	di.Provide(c, func(configuration *config.Configuration) (*limiter.Limits, error) {
+		return limiter.FromConfig(configuration.Concurrency)
+	})
+
+	// nil without authorization.clients; usage is shared through the api_usage table
+	di.Provide(c, newQuotas)
+
+	// nil when debug_capture is disabled; captures are kept in the debug_captures table
+	di.Provide(c, func(configuration *config.Configuration, repository *pgsql.PostgreRepository) (*capture.Capturer, error) {
+		return capture.FromConfig(configuration.DebugCapture, configuration.Authorization.SigningSecret, repository.DebugCapture)
+	})
+
+	// oa, err := openauth.Initialize(configuration)
+	// if err != nil {
+	// 	return nil, fmt.Errorf("failed to initialize google auth: %w", err)
+	// }
+}
+
+// installDefaults installs the package-level defaults used outside of the container
+// (middleware.ResponseCache, the log masker, middleware.BearerAuthMiddleware,
+// middleware.ClientInfoMiddleware, middleware.LoginThrottle, middleware.CaptchaMiddleware,
+// middleware.ConcurrencyMiddleware, middleware.ApiKeyMiddleware, middleware.DebugCaptureMiddleware,
+// lock.WithLock) and the account number profiles.
+func installDefaults(
+	configuration *config.Configuration,
+	responses *cache.Responses,
+	tokenizer *pii.Tokenizer,
+	revocations *session.Revocations,
+	clients *clientinfo.Resolver,
+	guard *loginguard.Guard,
+	verifier *captcha.SiteVerifier,
+	locker lock.Locker,
+	limits *limiter.Limits,
+	quotas *quota.Quotas,
+	capturer *capture.Capturer,
+) error {
+	cache.SetDefault(responses)
+	pii.SetDefault(tokenizer)
+	session.SetDefault(revocations)
+	clientinfo.SetDefault(clients)
+	loginguard.SetDefault(guard)
+	captcha.SetDefault(verifier)
+	lock.SetDefault(locker)
+	limiter.SetDefault(limits)
+	quota.SetDefault(quotas)
+	capture.SetDefault(capturer)
+
+	if err := registerAccountNumberProfiles(configuration); err != nil {
+		return fmt.Errorf("invalid account number configuration: %w", err)
+	}
+	return nil
+}
+
+// newRevocations keeps session revocations in Redis when configured, so every instance and
+// the admin CLI share them, and in memory otherwise. They live as long as a refresh token.
+func newRevocations(db *database.Database, jwtConfig *jwtc.Configuration) *session.Revocations {
+	var store cache.Store = cache.NewMemoryStore(session.DefaultMaxAccounts)
+	if db.Redis != nil {
+		store = cache.NewRedisStore(db.Redis)
+	}
+	return session.NewRevocations(store, max(jwtConfig.AccessTokenDuration, jwtConfig.RefreshTokenDuration))
+}
+
+// newLocker holds the locks of lock.store: in Redis, or as PostgreSQL advisory locks on a
+// pool of their own, closed by Teardown
+func newLocker(configuration *config.Configuration, db *database.Database) (lock.Locker, error) {
+	if configuration.Lock.Store == "redis" {
+		if db.Redis == nil {
+			return nil, fmt.Errorf("lock.store redis requires redis.addr")
+		}
+		var ttl time.Duration
+		if configuration.Lock.TTL != "" {
+			parsed, err := time.ParseDuration(configuration.Lock.TTL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid lock.ttl: %w", err)
+			}
+			ttl = parsed
+		}
+		return lock.NewRedisLocker(ttl, db.Redis), nil
+	}
+
+	lockDB, err := database.OpenPostgreSQLDB(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock pool: %w", err)
+	}
+	locker := lock.NewPostgresLocker(lockDB)
+	setLocker(locker)
+	return locker, nil
+}
+
+// newNotifications creates the queue delivering notifications on the configured channels,
+// nil when none is configured. It renders the templates of the services, follows the channel
+// preferences of the users, and keeps the deliveries that fail every attempt as dead letters.
+func newNotifications(configuration *config.Configuration, repository pgsql.NotificationRepository) (*notify.Queue, error) {
+	cfg := configuration.Notifications
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	templates, err := service.NotificationTemplates()
+	if err != nil {
+		return nil, err
+	}
+	options := notify.QueueOptions{
+		MaxAttempts: cfg.MaxAttempts,
+		Templates:   templates,
+		Preferences: service.NotificationPreferences(repository),
+		DeadLetters: service.NotificationDeadLetters(repository),
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifications.timeout: %w", err)
+		}
+		options.Timeout = timeout
+	}
+
+	// The queue retries failed deliveries, the client only bounds each attempt
+	clientConfig := httpclient.DefaultConfig()
+	clientConfig.Timeout = 0
+	client := httpclient.New(clientConfig)
+
+	channels := notify.Channels{}
+	if cfg.Webhook.URL != "" {
+		channels[notify.ChannelWebhook] = notify.NewWebhook(client, cfg.Webhook.URL, cfg.Webhook.Secret)
+	}
+	if cfg.SMTP.Addr != "" {
+		channels[notify.ChannelEmail] = notify.NewEmail(cfg.SMTP.Addr, cfg.SMTP.From, cfg.SMTP.Username, cfg.SMTP.Password)
+	}
+	if cfg.SMS.URL != "" {
+		channels[notify.ChannelSMS] = notify.NewSMS(client, cfg.SMS.URL, cfg.SMS.Token)
+	}
+
+	return notify.NewQueue(channels, options), nil
+}
+
+// newQuotas counts the requests of authorization.clients in the api_usage table of
+// repository and applies the overrides of quota_overrides, nil without clients
+func newQuotas(configuration *config.Configuration, repository *pgsql.PostgreRepository) (*quota.Quotas, error) {
+	quotas, err := quota.FromConfig(configuration.Authorization, repository.APIUsage)
+	if err != nil || quotas == nil {
+		return nil, err
+	}
+
+	var ttl time.Duration
+	if configuration.Quota.OverridesTTL != "" {
+		parsed, err := time.ParseDuration(configuration.Quota.OverridesTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quota.overrides_ttl: %w", err)
+		}
+		ttl = parsed
+	}
+	return quotas.WithOverrides(service.QuotaOverrides(repository.QuotaOverride), ttl), nil
+}
+
+func newPasswordBreachChecker(configuration *config.Configuration) (*validator.BreachChecker, error) {
+	cfg := configuration.Password.BreachCheck
+
+	clientConfig := httpclient.DefaultConfig()
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid password.breach_check.timeout: %w", err)
+		}
+		clientConfig.Timeout = timeout
+	}
+	if cfg.MaxRetries > 0 {
+		clientConfig.MaxRetries = cfg.MaxRetries
+	}
+
+	return validator.NewBreachChecker(httpclient.New(clientConfig), cfg.APIURL), nil
+}
+
+func newDisposableDomains(configuration *config.Configuration) (*validator.DomainBlocklist, error) {
+	cfg := configuration.Email
+	if len(cfg.DisposableDomains) == 0 && cfg.DisposableDomainsFile == "" {
+		return nil, nil
+	}
+
+	blocklist := validator.NewDomainBlocklist(cfg.DisposableDomains...)
+	if cfg.DisposableDomainsFile != "" {
+		file, err := os.Open(cfg.DisposableDomainsFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email.disposable_domains_file: %w", err)
+		}
+		defer file.Close()
+
+		if err := blocklist.Load(file); err != nil {
+			return nil, fmt.Errorf("invalid email.disposable_domains_file: %w", err)
+		}
+	}
+	return blocklist, nil
+}
+
+// registerAccountNumberProfiles registers the configured profiles and checks the selected one exists
+func registerAccountNumberProfiles(configuration *config.Configuration) error {
+	for name, profile := range configuration.AccountNumber.Profiles {
+		if err := generator.RegisterAccountNumberProfile(models.AccountNumberProfile{
+			Name:       name,
+			Length:     profile.Length,
+			Prefixes:   profile.Prefixes,
+			CheckDigit: profile.CheckDigit,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := generator.GetAccountNumberProfile(configuration.AccountNumber.Profile); !ok {
+		return fmt.Errorf("unknown account_number.profile %q", configuration.AccountNumber.Profile)
+	}
+
+	return nil
+}
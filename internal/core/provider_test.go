@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/database"
+	"go-echo-boilerplate/internal/pkg/di"
+	"go-echo-boilerplate/internal/pkg/lock"
+	"go-echo-boilerplate/internal/pkg/session"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContainer(t *testing.T) {
+	c := newContainer(validConfiguration())
+	di.Supply(c, &database.Database{})    // instead of connecting
+	di.Provide(c, service.NewUserService) // the users module, which imports core
+
+	services, err := di.Resolve[*service.Service](c)
+	require.NoError(t, err, "every dependency of the services is provided")
+	assert.NotNil(t, services.User)
+	assert.NotNil(t, services.Health)
+
+	again, err := di.Resolve[*service.Service](c)
+	require.NoError(t, err)
+	assert.Same(t, services, again, "components are built once")
+
+	breach, err := di.Resolve[validator.PasswordBreachChecker](c)
+	require.NoError(t, err)
+	assert.Nil(t, breach, "the breach check is disabled")
+
+	responses, err := di.Resolve[*cache.Responses](c)
+	require.NoError(t, err)
+	assert.NotNil(t, responses)
+
+	revocations, err := di.Resolve[*session.Revocations](c)
+	require.NoError(t, err)
+	assert.NotNil(t, revocations)
+}
+
+func TestNewContainer_InvalidConfiguration(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Authorization.Access.Secret = "change-me"
+	c := newContainer(configuration)
+	di.Supply(c, &database.Database{})
+
+	_, err := di.Resolve[*service.Service](c)
+	assert.ErrorContains(t, err, "*jwtc.Configuration: invalid jwt configuration")
+}
+
+func TestNewContainer_InMemory(t *testing.T) {
+	c := newContainer(validConfiguration())
+	memory.Provide(c)
+	di.Provide(c, service.NewUserService)
+
+	services, err := di.Resolve[*service.Service](c)
+	require.NoError(t, err, "the services resolve without a database")
+
+	health, err := services.Health.Check(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, health.Dependencies)
+}
+
+func TestNewContainer_Mock(t *testing.T) {
+	configuration := validConfiguration()
+	configuration.Application.Mode = config.ModeMock
+	c := newContainer(configuration)
+	di.Provide(c, service.NewUserService)
+
+	services, err := di.Resolve[*service.Service](c)
+	require.NoError(t, err, "mock mode connects to nothing")
+	_, err = services.Health.Check(context.Background())
+	require.NoError(t, err)
+
+	locker, err := di.Resolve[lock.Locker](c)
+	require.NoError(t, err)
+	assert.Nil(t, locker)
+}
+
+func TestAbandonSetup(t *testing.T) {
+	t.Cleanup(func() { setLocker(nil) })
+	configuration := validConfiguration()
+	configuration.PostgreSQL.Host = "localhost"
+	configuration.PostgreSQL.Port = 5432
+	configuration.PostgreSQL.SSLMode = "disable"
+
+	locker, err := newLocker(configuration, &database.Database{})
+	require.NoError(t, err)
+
+	abandonSetup()
+
+	_, err = locker.TryLock(context.Background(), "jobs")
+	assert.ErrorContains(t, err, "closed", "the lock pool is closed")
+	assert.NoError(t, Teardown(context.Background()), "nothing is left to close")
+}
+
+func TestNewLocker(t *testing.T) {
+	t.Cleanup(func() { setLocker(nil) })
+	configuration := validConfiguration()
+	configuration.PostgreSQL.Host = "localhost"
+	configuration.PostgreSQL.Port = 5432
+	configuration.PostgreSQL.SSLMode = "disable"
+
+	locker, err := newLocker(configuration, &database.Database{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = locker.(*lock.PostgresLocker).Close() })
+	assert.IsType(t, &lock.PostgresLocker{}, locker, "advisory locks by default")
+
+	configuration.Lock.Store = "redis"
+	_, err = newLocker(configuration, &database.Database{})
+	assert.ErrorContains(t, err, "redis.addr")
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/graceful"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// ReadinessChecks returns the graceful options gating the start of the server built by Setup
+// on its dependencies: PostgreSQL and Redis reachable and, with startup.require_migrations,
+// every migration of the modules applied. The configuration was validated by
+// config.Initialize already. Mock mode connects to nothing and checks nothing.
+func ReadinessChecks(configuration *config.Configuration) ([]graceful.Option, error) {
+	var options []graceful.Option
+	if configuration.Startup.ReadinessTimeout != "" {
+		timeout, err := time.ParseDuration(configuration.Startup.ReadinessTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid startup.readiness_timeout: %w", err)
+		}
+		options = append(options, graceful.WithReadinessTimeout(timeout))
+	}
+	if db == nil {
+		return options, nil
+	}
+
+	sqlDB, err := db.PostgreDatabase.DB()
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, graceful.WithReadinessCheck("postgresql", sqlDB.PingContext))
+
+	if db.Redis != nil {
+		options = append(options, graceful.WithReadinessCheck("redis", func(ctx context.Context) error {
+			return db.Redis.Ping(ctx).Err()
+		}))
+	}
+
+	if configuration.Startup.RequireMigrations {
+		migrations, err := Migrations(Modules()...)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := goose.NewProvider(goose.DialectPostgres, sqlDB, migrations)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, graceful.WithReadinessCheck("migrations", func(ctx context.Context) error {
+			pending, err := provider.HasPending(ctx)
+			if err != nil {
+				return err
+			}
+			if pending {
+				return errors.New("migrations are pending, apply them with the cli migrate up command")
+			}
+			return nil
+		}))
+	}
+	return options, nil
+}
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessChecks_WithoutDatabase(t *testing.T) {
+	setDB(nil)
+	configuration := validConfiguration()
+
+	options, err := ReadinessChecks(configuration)
+	require.NoError(t, err)
+	assert.Empty(t, options, "nothing to check without a connection, e.g. in mock mode")
+
+	configuration.Startup.ReadinessTimeout = "1m"
+	options, err = ReadinessChecks(configuration)
+	require.NoError(t, err)
+	assert.Len(t, options, 1)
+
+	configuration.Startup.ReadinessTimeout = "soon"
+	_, err = ReadinessChecks(configuration)
+	assert.ErrorContains(t, err, "startup.readiness_timeout")
+}
@@ -4,10 +4,16 @@ import (
 	"context"
 	"go-echo-boilerplate/internal/config"
 	handler "go-echo-boilerplate/internal/deliveries/http"
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/pkg/capture"
 	"go-echo-boilerplate/internal/pkg/database"
+	"go-echo-boilerplate/internal/pkg/di"
 	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/lock"
 	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/maintenance"
 	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
 	"go-echo-boilerplate/internal/service"
 
 	"github.com/labstack/echo/v4"
@@ -15,34 +21,119 @@ import (
 
 func Setup(configuration *config.Configuration) (*echo.Echo, error) {
 	logger.Initialize(configuration)
+	if _, err := maintenance.Configure(configuration.Maintenance); err != nil {
+		logger.L().Error(context.Background(), "invalid maintenance configuration", logger.Error(err))
+		return nil, err
+	}
+	watchConfiguration()
 
-	e := echo.New()
-	e.HideBanner = true
-	e.HidePort = true
+	e, err := newEcho(configuration)
+	if err != nil {
+		logger.L().Error(context.Background(), "invalid server configuration", logger.Error(err))
+		return nil, err
+	}
 
-	db, err := database.Connect(configuration)
+	app, err := NewApp(configuration)
 	if err != nil {
-		logger.Instance.Error(context.Background(), "failed to connect to database", logger.Error(err))
 		return nil, err
 	}
 
-	// oa, err := openauth.Initialize(configuration)
-	// if err != nil {
-	// 	logger.Instance.Error(context.Background(), "failed to initialize google auth", logger.Error(err))
-	// 	return nil, err
-	// }
+	if jobs, err = newJobs(configuration, app.Service); err != nil {
+		logger.L().Error(context.Background(), "invalid background job configuration", logger.Error(err))
+		abandonSetup()
+		return nil, err
+	}
+
+	deps := api.Dependencies{Service: app.Service, Config: configuration, JWTConfig: app.JWTConfig, Container: app.Container}
+	if err := handler.New(e, deps, routeRegistrars(Modules())...); err != nil {
+		logger.L().Error(context.Background(), "failed to register routes", logger.Error(err))
+		abandonSetup()
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// App is the service layer built from the configuration. It is shared by the HTTP
+// server (Setup) and the admin CLI (cmd/cli).
+type App struct {
+	// Container builds every component, resolve the ones App does not expose from it
+	Container *di.Container
 
-	jwtConfig := jwtc.DefaultConfig(configuration)
+	DB        *database.Database
+	JWTConfig *jwtc.Configuration
+	Service   *service.Service
+}
 
-	repository := repository.New(db)
-	service := service.New(service.Dependencies{
-		Repository: *repository,
-		// OAuth:      *oa,
-		Config:    configuration,
-		JWTConfig: jwtConfig,
+// NewApp registers the providers of every layer, connects to the databases, installs the
+// package-level defaults (response cache, pii tokenizer, session revocations), and builds
+// the services. Teardown disconnects; when it fails, what it connected is disconnected.
+func NewApp(configuration *config.Configuration) (*App, error) {
+	c := newContainer(configuration)
+	if err := di.Invoke(c, installDefaults); err != nil {
+		logger.L().Error(context.Background(), "failed to setup application", logger.Error(err))
+		abandonSetup()
+		return nil, err
+	}
+
+	app := &App{Container: c}
+	err := di.Invoke(c, func(db *database.Database, jwtConfig *jwtc.Configuration, service *service.Service) {
+		app.DB, app.JWTConfig, app.Service = db, jwtConfig, service
 	})
+	if err != nil {
+		logger.L().Error(context.Background(), "failed to setup application", logger.Error(err))
+		abandonSetup()
+		return nil, err
+	}
+	return app, nil
+}
 
-	handler.New(e, service, configuration, jwtConfig)
+// newContainer registers the providers of every layer and of the registered modules
+func newContainer(configuration *config.Configuration) *di.Container {
+	c := di.New()
+	di.Supply(c, configuration)
+	provide(c)
+	repository.Provide(c)
+	service.Provide(c)
 
-	return e, nil
+	for _, module := range Modules() {
+		module.RegisterRepositories(c)
+		module.RegisterServices(c)
+	}
+
+	// Mock mode connects to nothing: the repositories are in memory, and no lock is held
+	if configuration.Application.Mock() {
+		di.Supply(c, &database.Database{})
+		di.Supply[lock.Locker](c, nil)
+		memory.Provide(c)
+	}
+	return c
+}
+
+// watchConfiguration reloads the config file on change and applies the settings that can change at runtime.
+func watchConfiguration() {
+	config.OnChange(func(new config.Configuration) {
+		if err := logger.SetLevel(new.Logger.Level); err != nil {
+			logger.L().Warn(context.Background(), "invalid logger.level on reload", logger.Error(err))
+		}
+		logger.SetLimits(logger.NewLimits(new.Logger.Limits))
+		if masker, err := logger.NewMasker(new.Logger.Masking); err != nil {
+			logger.L().Warn(context.Background(), "invalid logger.masking on reload", logger.Error(err))
+		} else {
+			logger.SetMasker(masker)
+		}
+		if capturer := capture.Default(); capturer != nil {
+			capturer.SetAccounts(new.DebugCapture.Accounts)
+		}
+		if changed, err := maintenance.Configure(new.Maintenance); err != nil {
+			logger.L().Warn(context.Background(), "invalid maintenance on reload", logger.Error(err))
+		} else if changed {
+			logger.L().Warn(context.Background(), "maintenance mode changed", logger.Bool("maintenance", new.Maintenance.Enabled))
+		}
+		logger.L().Info(context.Background(), "configuration reloaded", logger.String("log_level", logger.GetLevel()))
+	})
+
+	config.Watch(func(err error) {
+		logger.L().Warn(context.Background(), "configuration reload", logger.Error(err))
+	})
 }
@@ -2,18 +2,61 @@ package core
 
 import (
 	"context"
-	"database/sql"
+	"go-echo-boilerplate/internal/pkg/database"
+	"go-echo-boilerplate/internal/pkg/lock"
+	"go-echo-boilerplate/internal/pkg/notify"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"time"
 )
 
-var db *sql.DB
+// abandonTimeout bounds closing what a failed setup opened
+const abandonTimeout = 5 * time.Second
 
-func setDB(database *sql.DB) {
+var (
+	db            *database.Database
+	notifications *notify.Queue
+	locker        *lock.PostgresLocker
+)
+
+func setDB(database *database.Database) {
 	db = database
 }
 
+func setNotifications(queue *notify.Queue) {
+	notifications = queue
+}
+
+func setLocker(postgresLocker *lock.PostgresLocker) {
+	locker = postgresLocker
+}
+
+// Teardown delivers the queued notifications and persists the API usage counted since the
+// last flush, until ctx is done, and disconnects.
 func Teardown(ctx context.Context) error {
+	if quotas := quota.Default(); quotas != nil {
+		_ = quotas.Flush(ctx)
+	}
+	if notifications != nil {
+		_ = notifications.Close(ctx)
+	}
+	if locker != nil {
+		_ = locker.Close()
+	}
 	if db != nil {
-		return db.Close()
+		return database.Disconnect(db)
 	}
 	return nil
 }
+
+// abandonSetup disconnects what the providers opened before the setup failed, since the
+// caller gets no application to tear down, and forgets it so a later Teardown doesn't close
+// it again.
+func abandonSetup() {
+	ctx, cancel := context.WithTimeout(context.Background(), abandonTimeout)
+	defer cancel()
+	_ = Teardown(ctx)
+
+	setDB(nil)
+	setNotifications(nil)
+	setLocker(nil)
+}
@@ -0,0 +1,239 @@
+package admin
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/maintenance"
+	"go-echo-boilerplate/internal/pkg/pii"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type adminHandler struct {
+	config *config.Configuration
+}
+
+func New(admin *echo.Group, config *config.Configuration) {
+	h := &adminHandler{
+		config: config,
+	}
+
+	admin.GET("/loglevel", h.GetLogLevel)
+	admin.PUT("/loglevel", h.UpdateLogLevel)
+	admin.GET("/logstats", h.GetLogStats)
+	admin.POST("/pii/resolve", h.ResolvePIIToken)
+	admin.GET("/maintenance", h.GetMaintenance)
+	admin.PUT("/maintenance", h.UpdateMaintenance)
+}
+
+// GetLogLevel returns the current log level
+// @Summary Get Log Level
+// @Description Get the global log level and the per-path overrides from logger.path_levels
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} models.Response{data=models.LogLevelResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/loglevel [get]
+func (h *adminHandler) GetLogLevel(ctx echo.Context) error {
+	return response.Success(ctx, http.StatusOK, h.logLevelResponse())
+}
+
+// UpdateLogLevel changes the global log level at runtime
+// @Summary Update Log Level
+// @Description Change the global log level without a restart. The change lasts until the next restart or config reload.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body models.UpdateLogLevelRequest true "New log level"
+// @Success 200 {object} models.Response{data=models.LogLevelResponse}
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/loglevel [put]
+func (h *adminHandler) UpdateLogLevel(ctx echo.Context) error {
+	var request models.UpdateLogLevelRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Input(request, i18n.FromContext(ctx.Request().Context())); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	previous := logger.GetLevel()
+	if err := logger.SetLevel(request.Level); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"log_level_previous": previous,
+		"log_level":          request.Level,
+	})
+
+	return response.Success(ctx, http.StatusOK, h.logLevelResponse())
+}
+
+// GetLogStats returns the async log queue and wide event truncation counters
+// @Summary Get Log Stats
+// @Description Get the queue depth and written/dropped counts of the async logger (logger.async), and the business data cut by logger.limits
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} models.Response{data=models.LogStatsResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/logstats [get]
+func (h *adminHandler) GetLogStats(ctx echo.Context) error {
+	return response.Success(ctx, http.StatusOK, models.LogStatsResponse{
+		Async:      logger.GetAsyncStats(),
+		Truncation: logger.GetTruncationStats(),
+	})
+}
+
+// ResolvePIIToken returns the value behind a PII token found in the logs
+// @Summary Resolve PII Token
+// @Description Resolve a token of the pii masking strategy back to the logged value, for incident investigation. Every attempt is written to the audit log with its reason; the value itself is never logged. Tokens are resolvable for pii.ttl after they were last logged, and not after a restart.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body models.ResolvePIITokenRequest true "Token and reason"
+// @Success 200 {object} models.Response{data=models.ResolvePIITokenResponse}
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Unknown or expired token, or pii.key is not configured"
+// @Router /admin/pii/resolve [post]
+func (h *adminHandler) ResolvePIIToken(ctx echo.Context) error {
+	tokenizer := pii.Default()
+	if tokenizer == nil {
+		return response.Error(ctx, errorc.ErrorDataNotFound)
+	}
+
+	var request models.ResolvePIITokenRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Input(request, i18n.FromContext(ctx.Request().Context())); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	value, found := tokenizer.Resolve(request.Token)
+
+	// Audit trail: who asked for which token and why, never the value
+	logger.FromContext(ctx.Request().Context()).Warn(ctx.Request().Context(), "audit: pii token resolve",
+		logger.String("audit_action", "pii_resolve"),
+		logger.String("pii_ref", request.Token),
+		logger.String("reason", request.Reason),
+		logger.String("remote_ip", ctx.RealIP()),
+		logger.Bool("found", found),
+	)
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"audit_action": "pii_resolve",
+		"pii_ref":      request.Token,
+		"pii_found":    found,
+	})
+
+	if !found {
+		return response.Error(ctx, errorc.ErrorDataNotFound)
+	}
+
+	return response.Success(ctx, http.StatusOK, models.ResolvePIITokenResponse{
+		Token:    request.Token,
+		PIIValue: value,
+	})
+}
+
+// GetMaintenance returns the maintenance mode
+// @Summary Get Maintenance Mode
+// @Description Get whether the instance answers 503 to every route but /health, /version, /admin, and /debug, as set by maintenance in the config or with PUT /admin/maintenance
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} models.Response{data=models.MaintenanceResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/maintenance [get]
+func (h *adminHandler) GetMaintenance(ctx echo.Context) error {
+	return response.Success(ctx, http.StatusOK, maintenanceResponse(maintenance.Current()))
+}
+
+// UpdateMaintenance turns the maintenance mode on or off at runtime
+// @Summary Update Maintenance Mode
+// @Description Turn the maintenance mode on or off on this instance without a restart, e.g. around a migration. It lasts until the next restart or a config reload changing maintenance; other instances are changed through the config.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body models.UpdateMaintenanceRequest true "Maintenance mode"
+// @Success 200 {object} models.Response{data=models.MaintenanceResponse}
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/maintenance [put]
+func (h *adminHandler) UpdateMaintenance(ctx echo.Context) error {
+	var request models.UpdateMaintenanceRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Input(request, i18n.FromContext(ctx.Request().Context())); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	var retryAfter time.Duration
+	if request.RetryAfter != "" {
+		parsed, err := time.ParseDuration(request.RetryAfter)
+		if err != nil || parsed <= 0 {
+			return response.Error(ctx, errorc.Error(errorc.ErrorInvalidInput, "retry_after must be a positive duration, e.g. 10m"))
+		}
+		retryAfter = parsed
+	}
+
+	mode := maintenance.Set(maintenance.Mode{
+		Enabled:    *request.Enabled,
+		RetryAfter: retryAfter,
+		Message:    request.Message,
+		Source:     maintenance.SourceAdmin,
+	})
+
+	// Audit trail: the whole instance stops serving
+	logger.FromContext(ctx.Request().Context()).Warn(ctx.Request().Context(), "audit: maintenance mode changed",
+		logger.String("audit_action", "maintenance"),
+		logger.Bool("maintenance", mode.Enabled),
+		logger.String("remote_ip", ctx.RealIP()),
+	)
+	logger.Add(ctx.Request().Context(), "maintenance", mode.Enabled)
+
+	return response.Success(ctx, http.StatusOK, maintenanceResponse(mode))
+}
+
+func maintenanceResponse(mode maintenance.Mode) models.MaintenanceResponse {
+	resp := models.MaintenanceResponse{
+		Enabled:    mode.Enabled,
+		RetryAfter: mode.RetryAfter.String(),
+		Message:    mode.Message,
+		Source:     mode.Source,
+	}
+	if !mode.Since.IsZero() {
+		resp.Since = &mode.Since
+	}
+	return resp
+}
+
+func (h *adminHandler) logLevelResponse() models.LogLevelResponse {
+	current := config.Current()
+	if current == nil {
+		current = h.config
+	}
+
+	return models.LogLevelResponse{
+		Level:      logger.GetLevel(),
+		PathLevels: current.Logger.PathLevels,
+	}
+}
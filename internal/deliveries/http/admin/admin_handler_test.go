@@ -0,0 +1,238 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/maintenance"
+	"go-echo-boilerplate/internal/pkg/pii"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const adminKey = "admin-key-0123456789abcdef0123456789"
+
+func setup(cfg *config.Configuration) *echo.Echo {
+	e := echo.New()
+	m := middleware.New(e, cfg)
+	group := e.Group("/admin")
+	group.Use(m.AdminKeyMiddleware(cfg))
+	admin.New(group, cfg)
+	return e
+}
+
+func request(method, body, key string) *http.Request {
+	req := httptest.NewRequest(method, "/admin/loglevel", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set("X-Admin-Key", key)
+	}
+	return req
+}
+
+func TestAdminHandler_LogLevel(t *testing.T) {
+	cfg := &config.Configuration{
+		Authorization: config.Authorization{AdminAPIKey: adminKey},
+		Logger:        config.Logger{PathLevels: map[string]string{"/api/v1/users/*": "debug"}},
+	}
+	e := setup(cfg)
+
+	require.NoError(t, logger.SetLevel("info"))
+	t.Cleanup(func() { _ = logger.SetLevel("info") })
+
+	t.Run("Update", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request(http.MethodPut, `{"level":"warn"}`, adminKey))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "warn", logger.GetLevel())
+
+		var resp struct {
+			Data struct {
+				Level      string            `json:"level"`
+				PathLevels map[string]string `json:"pathLevels"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "warn", resp.Data.Level)
+		assert.Equal(t, "debug", resp.Data.PathLevels["/api/v1/users/*"])
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request(http.MethodGet, "", adminKey))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"level":"warn"`)
+	})
+
+	t.Run("Invalid Level", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request(http.MethodPut, `{"level":"verbose"}`, adminKey))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "warn", logger.GetLevel())
+	})
+
+	t.Run("Wrong Key", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request(http.MethodPut, `{"level":"debug"}`, "wrong"))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "warn", logger.GetLevel())
+	})
+}
+
+func TestAdminHandler_Disabled(t *testing.T) {
+	e := setup(&config.Configuration{})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request(http.MethodGet, "", ""))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdminHandler_LogStats(t *testing.T) {
+	e := setup(&config.Configuration{Authorization: config.Authorization{AdminAPIKey: adminKey}})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/logstats", nil)
+	req.Header.Set("X-Admin-Key", adminKey)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Data models.LogStatsResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Data.Async.Enabled)
+}
+
+func TestAdminHandler_ResolvePIIToken(t *testing.T) {
+	e := setup(&config.Configuration{Authorization: config.Authorization{AdminAPIKey: adminKey}})
+
+	resolve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/pii/resolve", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Admin-Key", adminKey)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		pii.SetDefault(nil)
+		rec := resolve(`{"token":"pii_0123","reason":"INC-1234 refund dispute"}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	tokenizer := pii.New("key", pii.NewMemoryVault(10, time.Hour))
+	pii.SetDefault(tokenizer)
+	t.Cleanup(func() { pii.SetDefault(nil) })
+	token := tokenizer.Tokenize("jane@example.com")
+
+	core, audit := observer.New(zapcore.DebugLevel)
+	previous := logger.Instance
+	logger.Instance = logger.NewZapLogger(zap.New(core))
+	t.Cleanup(func() { logger.Instance = previous })
+
+	t.Run("Resolve", func(t *testing.T) {
+		rec := resolve(`{"token":"` + token + `","reason":"INC-1234 refund dispute"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Data models.ResolvePIITokenResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "jane@example.com", resp.Data.PIIValue)
+
+		entries := audit.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, token, fields["pii_ref"])
+		assert.Equal(t, "INC-1234 refund dispute", fields["reason"])
+		assert.Equal(t, true, fields["found"])
+		assert.NotContains(t, fmt.Sprint(fields), "jane@example.com")
+	})
+
+	t.Run("Missing Reason", func(t *testing.T) {
+		rec := resolve(`{"token":"` + token + `"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		rec := resolve(`{"token":"pii_000000000000000000000000","reason":"INC-1234 refund dispute"}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		entries := audit.TakeAll()
+		require.Len(t, entries, 1, "failed attempts are audited too")
+		assert.Equal(t, false, entries[0].ContextMap()["found"])
+	})
+}
+
+func TestAdminHandler_Maintenance(t *testing.T) {
+	e := setup(&config.Configuration{Authorization: config.Authorization{AdminAPIKey: adminKey}})
+	t.Cleanup(func() { maintenance.Set(maintenance.Mode{}) })
+
+	serve := func(method, body string) (*httptest.ResponseRecorder, models.MaintenanceResponse) {
+		req := httptest.NewRequest(method, "/admin/maintenance", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Admin-Key", adminKey)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var resp struct {
+			Data models.MaintenanceResponse `json:"data"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp.Data
+	}
+
+	t.Run("Enable", func(t *testing.T) {
+		rec, resp := serve(http.MethodPut, `{"enabled":true,"retry_after":"10m","message":"Migrating the database"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, resp.Enabled)
+		assert.Equal(t, "10m0s", resp.RetryAfter)
+		assert.Equal(t, maintenance.SourceAdmin, resp.Source)
+		assert.NotNil(t, resp.Since)
+		assert.True(t, maintenance.Current().Enabled)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		rec, resp := serve(http.MethodGet, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Migrating the database", resp.Message)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		rec, resp := serve(http.MethodPut, `{"enabled":false}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, resp.Enabled)
+		assert.Equal(t, maintenance.DefaultMessage, resp.Message)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		rec, _ := serve(http.MethodPut, `{"retry_after":"10m"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "enabled is required")
+
+		rec, _ = serve(http.MethodPut, `{"enabled":true,"retry_after":"soon"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, maintenance.Current().Enabled)
+	})
+}
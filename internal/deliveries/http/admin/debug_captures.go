@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxDebugCaptureLimit caps the limit of GET /admin/captures
+const maxDebugCaptureLimit = 200
+
+type debugCapturesHandler struct {
+	captures service.DebugCaptureService
+}
+
+// DebugCaptures serves the debug captures on admin, the /admin group: the tokens enabling the
+// capture of a request, and the captures to replay locally with the cli replay-capture command.
+func DebugCaptures(admin *echo.Group, captures service.DebugCaptureService) {
+	h := &debugCapturesHandler{captures: captures}
+
+	admin.POST("/captures/tokens", h.CreateToken)
+	admin.GET("/captures", h.List)
+	admin.GET("/captures/:id", h.Get)
+}
+
+// CreateToken returns a token capturing the requests sending it
+// @Summary Create Debug Capture Token
+// @Description Create a token capturing the sanitized request and response of every request sending it in the X-Debug-Capture header, until it expires after debug_capture.token_ttl. Requires debug_capture.enabled.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body models.CreateDebugCaptureTokenRequest true "Label of the captures"
+// @Success 201 {object} models.Response{data=models.DebugCaptureTokenResponse}
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Debug Capture Not Enabled"
+// @Router /admin/captures/tokens [post]
+func (h *debugCapturesHandler) CreateToken(ctx echo.Context) error {
+	var request models.CreateDebugCaptureTokenRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Input(request, i18n.FromContext(ctx.Request().Context())); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	token, err := h.captures.CreateToken(ctx.Request().Context(), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	// Audit trail: the requests sending the token are stored
+	logger.FromContext(ctx.Request().Context()).Warn(ctx.Request().Context(), "audit: debug capture token created",
+		logger.String("audit_action", "debug_capture_token"),
+		logger.String("label", request.Label),
+		logger.String("remote_ip", ctx.RealIP()),
+	)
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"audit_action": "debug_capture_token",
+		"label":        request.Label,
+	})
+
+	return response.Success(ctx, http.StatusCreated, token)
+}
+
+// List returns the latest debug captures
+// @Summary List Debug Captures
+// @Description List the debug captures that did not expire, the latest first, without their request and response
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param limit query int false "Most captures to list (default 50, at most 200)"
+// @Success 200 {object} models.Response{data=[]models.DebugCaptureSummaryResponse}
+// @Failure 400 {object} models.ErrorResponse "Invalid Limit"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/captures [get]
+func (h *debugCapturesHandler) List(ctx echo.Context) error {
+	limit := service.DefaultDebugCaptureLimit
+	if value := ctx.QueryParam("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxDebugCaptureLimit {
+			return response.Error(ctx, errorc.Error(errorc.ErrorInvalidInput, "limit must be between 1 and 200"))
+		}
+		limit = parsed
+	}
+
+	captures, err := h.captures.ListCaptures(ctx.Request().Context(), limit)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	data := make([]models.DebugCaptureSummaryResponse, 0, len(captures))
+	for _, capture := range captures {
+		data = append(data, capture.DebugCaptureSummaryResponse())
+	}
+	return response.Success(ctx, http.StatusOK, data)
+}
+
+// Get returns a debug capture
+// @Summary Get Debug Capture
+// @Description Get a debug capture with its sanitized request and response; saved to a file, it is replayed locally with the cli replay-capture command
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param id path int true "Capture ID"
+// @Success 200 {object} models.Response{data=models.DebugCaptureResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Capture Not Found"
+// @Router /admin/captures/{id} [get]
+func (h *debugCapturesHandler) Get(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(ctx, errorc.Error(errorc.ErrorDataNotFound, "Debug capture not found"))
+	}
+
+	capture, err := h.captures.GetCapture(ctx.Request().Context(), id)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+	return response.Success(ctx, http.StatusOK, capture)
+}
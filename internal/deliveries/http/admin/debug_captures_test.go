@@ -0,0 +1,88 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/capture"
+	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugCaptures(t *testing.T) {
+	t.Cleanup(func() { capture.SetDefault(nil) })
+
+	cfg := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: adminKey}}
+	repo := memory.New()
+
+	e := echo.New()
+	m := middleware.New(e, cfg)
+	group := e.Group("/admin")
+	group.Use(m.AdminKeyMiddleware(cfg))
+	admin.DebugCaptures(group, service.NewDebugCaptureService(&service.Dependencies{Repository: repository.Repository{Postgre: repo}, Config: cfg}))
+	e.Group("/api", m.DebugCaptureMiddleware()).GET("/v1/ping", func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusOK, map[string]string{"message": "pong"})
+	})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Admin-Key", adminKey)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, serve(http.MethodPost, "/admin/captures/tokens", `{"label":"SUP-1234"}`).Code, "disabled")
+
+	capturer, err := capture.FromConfig(config.DebugCapture{Enabled: true}, "debug-capture-signing-secret-0123456789", repo.DebugCapture)
+	require.NoError(t, err)
+	capture.SetDefault(capturer)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/admin/captures/tokens", `{}`).Code)
+	rec := serve(http.MethodPost, "/admin/captures/tokens", `{"label":"SUP-1234"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var token struct {
+		Data models.DebugCaptureTokenResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &token))
+	assert.Equal(t, capture.Header, token.Data.Header)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil)
+	req.Header.Set(capture.Header, token.Data.Token)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec = serve(http.MethodGet, "/admin/captures", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data []models.DebugCaptureSummaryResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "SUP-1234", list.Data[0].Label)
+	assert.Equal(t, "/api/v1/ping", list.Data[0].Path)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/admin/captures?limit=500", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/admin/captures/99", "").Code)
+
+	rec = serve(http.MethodGet, "/admin/captures/1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		Data models.DebugCaptureResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, http.MethodGet, got.Data.Request.Method)
+	assert.Equal(t, `{"message":"pong"}`, got.Data.Response.Body)
+}
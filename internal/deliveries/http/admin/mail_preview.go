@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/mail/templates"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MailPreview serves the emails of internal/pkg/mail/templates on preview, the /dev/emails
+// group of the development environments, rendered with their sample variables:
+//
+//	/dev/emails                                     links to every email
+//	/dev/emails/login.suspicious?locale=id           the HTML version, in Indonesian
+//	/dev/emails/login.suspicious?format=text        the subject and plain text version
+//	/dev/emails/email_change.confirm?name=Jane      any other parameter replaces a variable
+func MailPreview(preview *echo.Group, emails *templates.Templates) {
+	preview.GET("", func(ctx echo.Context) error {
+		var index strings.Builder
+		index.WriteString("<!DOCTYPE html>\n<html>\n<head><title>Emails</title></head>\n<body>\n<h1>Emails</h1>\n<ul>\n")
+		for _, event := range emails.Events() {
+			link := html.EscapeString(ctx.Request().URL.Path + "/" + event)
+			fmt.Fprintf(&index, "<li>%s: <a href=\"%s?locale=%s\">%s</a> <a href=\"%s?locale=%s\">%s</a> <a href=\"%s?format=text\">text</a></li>\n",
+				html.EscapeString(event), link, i18n.English, i18n.English, link, i18n.Indonesian, i18n.Indonesian, link)
+		}
+		index.WriteString("</ul>\n</body>\n</html>\n")
+		return ctx.HTML(http.StatusOK, index.String())
+	})
+
+	preview.GET("/:event", func(ctx echo.Context) error {
+		query := ctx.QueryParams()
+		locale := i18n.Locale(query.Get("locale"))
+		if locale == "" {
+			locale = i18n.DefaultLocale
+		}
+		format := query.Get("format")
+
+		vars := make(map[string]string, len(query))
+		for key := range query {
+			if key != "locale" && key != "format" {
+				vars[key] = query.Get(key)
+			}
+		}
+
+		email, ok, err := emails.Preview(locale, ctx.Param("event"), vars)
+		if !ok {
+			return ctx.String(http.StatusNotFound, "no email for this event\n")
+		}
+		if err != nil {
+			// A missing variable, set it with a query parameter
+			return ctx.String(http.StatusUnprocessableEntity, err.Error()+"\n")
+		}
+
+		if format == "text" {
+			return ctx.String(http.StatusOK, "Subject: "+email.Subject+"\n\n"+email.Text)
+		}
+		return ctx.HTML(http.StatusOK, email.HTML)
+	})
+}
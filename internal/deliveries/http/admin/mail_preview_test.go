@@ -0,0 +1,50 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	"go-echo-boilerplate/internal/pkg/mail/templates"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailPreview(t *testing.T) {
+	emails, err := templates.New()
+	require.NoError(t, err)
+
+	e := echo.New()
+	admin.MailPreview(e.Group("/dev/emails"), emails)
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	t.Run("Index", func(t *testing.T) {
+		rec := serve("/dev/emails")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `<a href="/dev/emails/login.suspicious?locale=id">id</a>`)
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		rec := serve("/dev/emails/email_change.completed?locale=id&name=Budi")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+		assert.Contains(t, rec.Body.String(), "<p>Halo Budi,</p>")
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		rec := serve("/dev/emails/email_change.completed?format=text")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Subject: The email of your account changed\n\nHi Jane Doe,")
+	})
+
+	t.Run("Unknown Event", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve("/dev/emails/unknown").Code)
+	})
+}
@@ -0,0 +1,29 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Profiling serves the net/http/pprof profiles on debug, the /debug/pprof group guarded by
+// the admin key. Profiles are fetched with the header and read offline, e.g.
+//
+//	curl -H "X-Admin-Key: $KEY" "localhost:8080/debug/pprof/profile?seconds=10" > cpu.out
+//	go tool pprof -http=:6060 cpu.out
+//
+// CPU profiles and traces must be shorter than server.write_timeout.
+func Profiling(debug *echo.Group) {
+	debug.GET("", func(ctx echo.Context) error {
+		return ctx.Redirect(http.StatusMovedPermanently, ctx.Request().URL.Path+"/")
+	})
+
+	// Index also serves the named profiles: heap, goroutine, allocs, block, mutex, ...
+	debug.GET("/*", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	debug.GET("/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	debug.GET("/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+	debug.GET("/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	debug.POST("/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	debug.GET("/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+}
@@ -0,0 +1,59 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiling(t *testing.T) {
+	serve := func(cfg *config.Configuration, path, key string) *httptest.ResponseRecorder {
+		e := echo.New()
+		m := middleware.New(e, cfg)
+		debug := e.Group("/debug/pprof")
+		debug.Use(m.AdminKeyMiddleware(cfg))
+		admin.Profiling(debug)
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if key != "" {
+			req.Header.Set("X-Admin-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	cfg := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: adminKey}}
+
+	t.Run("Index", func(t *testing.T) {
+		rec := serve(cfg, "/debug/pprof/", adminKey)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "goroutine")
+	})
+
+	t.Run("Named Profile", func(t *testing.T) {
+		rec := serve(cfg, "/debug/pprof/goroutine?debug=1", adminKey)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "goroutine profile")
+	})
+
+	t.Run("Redirect", func(t *testing.T) {
+		rec := serve(cfg, "/debug/pprof", adminKey)
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, "/debug/pprof/", rec.Header().Get(echo.HeaderLocation))
+	})
+
+	t.Run("Without Admin Key", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve(cfg, "/debug/pprof/heap", "wrong").Code)
+	})
+
+	t.Run("Admin Disabled", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve(&config.Configuration{}, "/debug/pprof/heap", adminKey).Code)
+	})
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type quotaOverridesHandler struct {
+	quotas service.QuotaService
+}
+
+// QuotaOverrides serves the overrides of the quotas of authorization.clients on admin, the
+// /admin group, so support can lift or change a client's quota without a deploy.
+func QuotaOverrides(admin *echo.Group, quotas service.QuotaService) {
+	h := &quotaOverridesHandler{quotas: quotas}
+
+	admin.GET("/quotas/overrides", h.List)
+	admin.GET("/quotas/overrides/:client", h.Get)
+	admin.PUT("/quotas/overrides/:client", h.Save)
+	admin.DELETE("/quotas/overrides/:client", h.Delete)
+}
+
+// List returns every quota override
+// @Summary List Quota Overrides
+// @Description List the overrides of the quotas of authorization.clients, expired ones included
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} models.Response{data=[]models.QuotaOverrideResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/quotas/overrides [get]
+func (h *quotaOverridesHandler) List(ctx echo.Context) error {
+	overrides, err := h.quotas.ListOverrides(ctx.Request().Context())
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	now := time.Now()
+	data := make([]models.QuotaOverrideResponse, 0, len(overrides))
+	for _, override := range overrides {
+		data = append(data, override.QuotaOverrideResponse(now))
+	}
+	return response.Success(ctx, http.StatusOK, data)
+}
+
+// Get returns the quota override of a client
+// @Summary Get Quota Override
+// @Description Get the override of the quotas of a client of authorization.clients
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param client path string true "Client name"
+// @Success 200 {object} models.Response{data=models.QuotaOverrideResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Unknown Client or No Override"
+// @Router /admin/quotas/overrides/{client} [get]
+func (h *quotaOverridesHandler) Get(ctx echo.Context) error {
+	override, err := h.quotas.GetOverride(ctx.Request().Context(), ctx.Param("client"))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+	return response.Success(ctx, http.StatusOK, override.QuotaOverrideResponse(time.Now()))
+}
+
+// Save creates or replaces the quota override of a client
+// @Summary Save Quota Override
+// @Description Replace the daily or monthly quota of a client of authorization.clients, e.g. to unblock it; a null quota keeps the configured one and 0 is unlimited. It applies at once on this instance and within quota.overrides_ttl on the others, until expires_at.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param client path string true "Client name"
+// @Param request body models.SaveQuotaOverrideRequest true "Quota override"
+// @Success 200 {object} models.Response{data=models.QuotaOverrideResponse}
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Unknown Client"
+// @Router /admin/quotas/overrides/{client} [put]
+func (h *quotaOverridesHandler) Save(ctx echo.Context) error {
+	var request models.SaveQuotaOverrideRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Input(request, i18n.FromContext(ctx.Request().Context())); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	client := ctx.Param("client")
+	override, err := h.quotas.SaveOverride(ctx.Request().Context(), client, &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	// Audit trail: a client may now send more requests
+	logger.FromContext(ctx.Request().Context()).Warn(ctx.Request().Context(), "audit: quota override saved",
+		logger.String("audit_action", "quota_override"),
+		logger.String("api_client", client),
+		logger.String("reason", request.Reason),
+		logger.String("remote_ip", ctx.RealIP()),
+	)
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"audit_action": "quota_override",
+		"api_client":   client,
+	})
+
+	return response.Success(ctx, http.StatusOK, override.QuotaOverrideResponse(time.Now()))
+}
+
+// Delete deletes the quota override of a client
+// @Summary Delete Quota Override
+// @Description Delete the override of the quotas of a client of authorization.clients, so its configured quotas apply again
+// @Tags Admin
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param client path string true "Client name"
+// @Success 204 "Deleted"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Unknown Client or No Override"
+// @Router /admin/quotas/overrides/{client} [delete]
+func (h *quotaOverridesHandler) Delete(ctx echo.Context) error {
+	client := ctx.Param("client")
+	if err := h.quotas.DeleteOverride(ctx.Request().Context(), client); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	logger.FromContext(ctx.Request().Context()).Warn(ctx.Request().Context(), "audit: quota override deleted",
+		logger.String("audit_action", "quota_override_delete"),
+		logger.String("api_client", client),
+		logger.String("remote_ip", ctx.RealIP()),
+	)
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"audit_action": "quota_override_delete",
+		"api_client":   client,
+	})
+
+	return ctx.NoContent(http.StatusNoContent)
+}
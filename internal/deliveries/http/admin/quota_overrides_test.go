@@ -0,0 +1,83 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaOverrides(t *testing.T) {
+	t.Cleanup(func() { quota.SetDefault(nil) })
+
+	cfg := &config.Configuration{Authorization: config.Authorization{
+		AdminAPIKey: adminKey,
+		Clients:     []config.APIClient{{Name: "partner-a", Key: "partner-a-key", DailyQuota: 1}},
+	}}
+	repo := memory.New()
+	quotas, err := quota.FromConfig(cfg.Authorization, repo.APIUsage)
+	require.NoError(t, err)
+	quota.SetDefault(quotas.WithOverrides(service.QuotaOverrides(repo.QuotaOverride), 0))
+
+	e := echo.New()
+	m := middleware.New(e, cfg)
+	group := e.Group("/admin")
+	group.Use(m.AdminKeyMiddleware(cfg))
+	admin.QuotaOverrides(group, service.NewQuotaService(&service.Dependencies{Repository: repository.Repository{Postgre: repo}, Config: cfg}))
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Admin-Key", adminKey)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	_, ok := quotas.Consume(t.Context(), "partner-a")
+	require.True(t, ok)
+	_, ok = quotas.Consume(t.Context(), "partner-a")
+	require.False(t, ok, "blocked")
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/admin/quotas/overrides/partner-a", "").Code, "no override")
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPut, "/admin/quotas/overrides/partner-b", `{"daily_quota":0,"reason":"SUP-1234"}`).Code, "unknown client")
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/admin/quotas/overrides/partner-a", `{"daily_quota":-1}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/admin/quotas/overrides/partner-a", `{"reason":"SUP-1234","expires_at":"2020-01-01T00:00:00Z"}`).Code, "expired")
+
+	rec := serve(http.MethodPut, "/admin/quotas/overrides/partner-a", `{"daily_quota":0,"reason":"SUP-1234"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var saved struct {
+		Data models.QuotaOverrideResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &saved))
+	assert.Equal(t, "partner-a", saved.Data.Client)
+	assert.Equal(t, int64(0), *saved.Data.DailyQuota)
+	assert.Nil(t, saved.Data.MonthlyQuota)
+	assert.True(t, saved.Data.Active)
+
+	_, ok = quotas.Consume(t.Context(), "partner-a")
+	assert.True(t, ok, "unblocked at once")
+
+	rec = serve(http.MethodGet, "/admin/quotas/overrides", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"SUP-1234"`)
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/admin/quotas/overrides/partner-a", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/admin/quotas/overrides/partner-a", "").Code)
+	_, ok = quotas.Consume(t.Context(), "partner-a")
+	assert.False(t, ok, "the configured quota applies again")
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"go-echo-boilerplate/internal/pkg/diagnostics"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type runtimeHandler struct {
+	storage diagnostics.Storage
+}
+
+// Runtime serves the runtime diagnostics on admin, the /admin group: the goroutines, memory,
+// and GC of the instance, and heap dumps written to storage, nil when diagnostics.dump_dir is
+// unset. Behind a load balancer, each request reports the instance answering it.
+func Runtime(admin *echo.Group, storage diagnostics.Storage) {
+	h := &runtimeHandler{storage: storage}
+
+	admin.GET("/runtime", h.Get)
+	admin.POST("/runtime/heap-dumps", h.HeapDump)
+}
+
+// Get reports the runtime of the instance
+// @Summary Get Runtime
+// @Description Get the goroutine count, memory statistics, and GC statistics of the instance answering, to diagnose a leak or GC pressure without a shell on the host. Reading them stops the world briefly.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} models.Response{data=models.RuntimeResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /admin/runtime [get]
+func (h *runtimeHandler) Get(ctx echo.Context) error {
+	return response.Success(ctx, http.StatusOK, diagnostics.Runtime())
+}
+
+// HeapDump writes a heap profile of the instance to diagnostics.dump_dir
+// @Summary Create Heap Dump
+// @Description Run a GC and write the heap profile of the instance answering to diagnostics.dump_dir, read offline with go tool pprof. Requires diagnostics.dump_dir.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 201 {object} models.Response{data=models.HeapDumpResponse}
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Heap Dumps Not Enabled"
+// @Router /admin/runtime/heap-dumps [post]
+func (h *runtimeHandler) HeapDump(ctx echo.Context) error {
+	dump, err := diagnostics.HeapDump(ctx.Request().Context(), h.storage)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"heap_dump":      dump.Location,
+		"heap_dump_size": dump.Size,
+	})
+	return response.Success(ctx, http.StatusCreated, dump)
+}
@@ -0,0 +1,74 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/diagnostics"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntime(t *testing.T) {
+	serve := func(storage diagnostics.Storage, method, path, key string) *httptest.ResponseRecorder {
+		cfg := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: adminKey}}
+		e := echo.New()
+		m := middleware.New(e, cfg)
+		group := e.Group("/admin")
+		group.Use(m.AdminKeyMiddleware(cfg))
+		admin.Runtime(group, storage)
+
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set("X-Admin-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		rec := serve(nil, http.MethodGet, "/admin/runtime", adminKey)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Data models.RuntimeResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Positive(t, resp.Data.Goroutines)
+		assert.Positive(t, resp.Data.Memory.HeapAlloc)
+	})
+
+	t.Run("Heap Dump", func(t *testing.T) {
+		storage := diagnostics.NewDirStorage(t.TempDir())
+		rec := serve(storage, http.MethodPost, "/admin/runtime/heap-dumps", adminKey)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var resp struct {
+			Data models.HeapDumpResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		info, err := os.Stat(resp.Data.Location)
+		require.NoError(t, err)
+		assert.Equal(t, int64(resp.Data.Size), info.Size())
+	})
+
+	t.Run("Heap Dump Not Enabled", func(t *testing.T) {
+		rec := serve(nil, http.MethodPost, "/admin/runtime/heap-dumps", adminKey)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("Without Admin Key", func(t *testing.T) {
+		rec := serve(nil, http.MethodGet, "/admin/runtime", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AccountNumber returns the account number authenticated by middleware.BearerAuthMiddleware,
+// empty on routes without it
+func AccountNumber(ctx echo.Context) string {
+	accountNumber, _ := ctx.Get("accountNumber").(string)
+	return accountNumber
+}
+
+// Bind binds the request body into request and validates it in the request locale.
+// Errors are meant for response.ErrorBinding.
+func Bind(ctx echo.Context, request interface{}) error {
+	if err := ctx.Bind(request); err != nil {
+		return err
+	}
+	return validator.Input(request, i18n.FromContext(ctx.Request().Context()))
+}
+
+// Pagination builds the prev/next links of a list response by rewriting the page parameter
+// of the current URL
+func Pagination(ctx echo.Context, page, limit, total int) models.PaginationOutput {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = models.DefaultListLimit
+	}
+
+	link := func(page int) string {
+		u := *ctx.Request().URL
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	pagination := models.PaginationOutput{Total: total, Limit: limit}
+	if page > 1 {
+		pagination.Prev = link(page - 1)
+	}
+	if page*limit < total {
+		pagination.Next = link(page + 1)
+	}
+	return pagination
+}
+
+// CursorPagination builds the next link of a list response paged by cursor (see
+// models.ListQuery), setting the cursor parameter of the current URL to next; next is empty
+// on the last page. total is the count of the rows after the cursor of the request.
+func CursorPagination(ctx echo.Context, limit, total int, next string) models.PaginationOutput {
+	pagination := models.PaginationOutput{Total: total, Limit: limit}
+	if next == "" {
+		return pagination
+	}
+
+	u := *ctx.Request().URL
+	query := u.Query()
+	query.Del("page")
+	query.Set("cursor", next)
+	u.RawQuery = query.Encode()
+	pagination.Next = u.RequestURI()
+	return pagination
+}
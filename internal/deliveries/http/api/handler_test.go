@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/models"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountNumber(t *testing.T) {
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Empty(t, api.AccountNumber(ctx))
+
+	ctx.Set("accountNumber", "1234567890")
+	assert.Equal(t, "1234567890", api.AccountNumber(ctx))
+}
+
+func TestBind(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+	bind := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		var r request
+		return api.Bind(echo.New().NewContext(req, httptest.NewRecorder()), &r)
+	}
+
+	assert.NoError(t, bind(`{"name":"John"}`))
+
+	var validationErrs *multierror.Error
+	assert.ErrorAs(t, bind(`{}`), &validationErrs)
+
+	err := bind(`{`)
+	assert.Error(t, err)
+	assert.NotErrorAs(t, err, &validationErrs)
+}
+
+func TestPagination(t *testing.T) {
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/users?name=jo&page=2&limit=10", nil), httptest.NewRecorder())
+
+	pagination := api.Pagination(ctx, 2, 10, 35)
+	assert.Equal(t, "/api/v1/users?limit=10&name=jo&page=1", pagination.Prev)
+	assert.Equal(t, "/api/v1/users?limit=10&name=jo&page=3", pagination.Next)
+	assert.Equal(t, 10, pagination.Limit)
+
+	pagination = api.Pagination(ctx, 0, 0, 15)
+	assert.Empty(t, pagination.Prev)
+	assert.Empty(t, pagination.Next)
+	assert.Equal(t, models.DefaultListLimit, pagination.Limit)
+}
+
+func TestCursorPagination(t *testing.T) {
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/items?limit=10&cursor=abc", nil), httptest.NewRecorder())
+
+	pagination := api.CursorPagination(ctx, 10, 35, "def")
+	assert.Equal(t, "/api/v1/items?cursor=def&limit=10", pagination.Next)
+	assert.Empty(t, pagination.Prev)
+	assert.Equal(t, 35, pagination.Total)
+
+	pagination = api.CursorPagination(ctx, 10, 5, "")
+	assert.Empty(t, pagination.Next)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/pkg/response"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIClient returns the name of the client of authorization.clients authenticated by
+// middleware.ApiKeyMiddleware, empty for authorization.api_key
+func APIClient(ctx echo.Context) string {
+	client, _ := ctx.Get("apiClient").(string)
+	return client
+}
+
+// Quota returns the quotas of the API key of the request and what is left of them
+// @Summary Get API Key Quota
+// @Description Get the daily and monthly quotas of the API key of the request (authorization.clients), with the requests used and remaining in the current period, this one included. Periods are UTC calendar days and months. The shared authorization.api_key has no quota.
+// @Tags API
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Success 200 {object} models.Response{data=models.QuotaResponse}
+// @Failure 401 {object} models.ErrorResponse "Missing or Unknown API Key"
+// @Failure 429 {object} models.ErrorResponse "Quota Exceeded"
+// @Router /api/quota [get]
+func Quota(ctx echo.Context) error {
+	client := APIClient(ctx)
+	data := models.QuotaResponse{Type: models.TYPE_QUOTA, Client: client, Quotas: []models.QuotaUsageResponse{}}
+	if quotas := quota.Default(); quotas != nil && client != "" {
+		for _, usage := range quotas.Usage(ctx.Request().Context(), client) {
+			data.Quotas = append(data.Quotas, models.QuotaUsageResponse{
+				Period:    usage.Period,
+				Limit:     usage.Limit,
+				Used:      usage.Used,
+				Remaining: usage.Remaining,
+				ResetAt:   usage.Reset,
+			})
+		}
+	}
+	return response.Success(ctx, http.StatusOK, data)
+}
@@ -2,11 +2,13 @@ package v1
 
 import (
 	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/api"
 	"go-echo-boilerplate/internal/deliveries/http/middleware"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/binder"
+	"go-echo-boilerplate/internal/pkg/i18n"
 	"go-echo-boilerplate/internal/pkg/jwtc"
 	"go-echo-boilerplate/internal/pkg/response"
-	"go-echo-boilerplate/internal/pkg/validator"
 	"go-echo-boilerplate/internal/service"
 	"net/http"
 	"time"
@@ -29,33 +31,52 @@ func NewUserV1(v1 *echo.Group, service *service.Service, config *config.Configur
 
 	noBearerRoute := v1.Group("/users")
 	noBearerRoute.POST("", h.Create)
-	noBearerRoute.POST("/tokens", h.GetTokens)
+	noBearerRoute.POST("/tokens", h.GetTokens, middleware.LoginThrottle())
+	noBearerRoute.POST("/email/confirm", h.ConfirmEmailChange)
+	noBearerRoute.GET("/availability", h.CheckAvailability)
 
 	bearerRoute := v1.Group("/users")
 	bearerRoute.Use(middleware.BearerAuthMiddleware(h.jwtConfig))
-	bearerRoute.GET("/me", h.GetUserByAccessToken)
+	bearerRoute.GET("/me", h.GetUserByAccessToken, middleware.ResponseCache(0))
+	bearerRoute.PATCH("/me", h.Update)
+	bearerRoute.DELETE("/me", h.Delete)
+	bearerRoute.POST("/me/email", h.ChangeEmail)
+	bearerRoute.PUT("/me/phone", h.ChangePhone)
+	bearerRoute.POST("/me/phone/verify", h.VerifyPhoneChange)
+	bearerRoute.GET("/me/logins", h.ListLogins)
+	bearerRoute.GET("/me/consents", h.GetConsent)
+	bearerRoute.PUT("/me/consents", h.UpdateConsent)
+	bearerRoute.GET("/me/notifications", h.GetNotificationPreferences)
+	bearerRoute.PUT("/me/notifications", h.UpdateNotificationPreferences)
+
+	// Support tooling, behind the admin key like /admin: listing accounts would let any user
+	// enumerate the others
+	adminRoute := v1.Group("/admin/users")
+	adminRoute.Use(middleware.AdminKey(h.config))
+	adminRoute.GET("", h.List)
+	adminRoute.GET("/search", h.Search)
+	middleware.AcceptMediaTypes(adminRoute.POST("/import", h.Import), mimeTextCSV, mimeApplicationNDJSON)
+	adminRoute.GET("/export", h.Export)
 }
 
 // Create registers a new user
 // @Summary Create New User
-// @Description Register a new user with email, phone number, and password. Auto-generates account number.
+// @Description Register a new user with email, phone number, and password, and optionally a username. Auto-generates account number.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param request body models.CreateUserRequest true "User Registration Details"
+// @Param X-Captcha-Token header string false "CAPTCHA answer, required when captcha.routes lists this route"
 // @Success 201 {object} models.Response{data=models.CreateUserResponse} "User Created Successfully"
-// @Failure 400 {object} models.Response "Invalid Input / Validation Error"
-// @Failure 409 {object} models.Response "User Already Exists (Email or Phone)"
-// @Failure 500 {object} models.Response "Internal Server Error"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 409 {object} models.ErrorResponse "User Already Exists (Email, Phone, or Username)"
+// @Failure 428 {object} models.ErrorResponse "Captcha Response Required"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /api/v1/users [post]
 func (h *userV1Handler) Create(ctx echo.Context) error {
 	var request models.CreateUserRequest
-	if err := ctx.Bind(&request); err != nil {
-		return response.Error(ctx, err)
-	}
-
-	if err := validator.Input(request); err != nil {
-		return response.ErrorValidation(ctx, err)
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
 	}
 
 	user, err := h.service.User.Create(ctx.Request().Context(), &request)
@@ -68,24 +89,23 @@ func (h *userV1Handler) Create(ctx echo.Context) error {
 
 // GetTokens retrieves tokens for a user
 // @Summary Get Tokens
-// @Description Get tokens for a user
+// @Description Get tokens for a user, identified by email, phone number, or username
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param request body models.GetUserTokenRequest true "User Token Request"
+// @Param X-Captcha-Token header string false "CAPTCHA answer, required after login_throttle.challenge_after failures"
 // @Success 200 {object} models.Response{data=models.GetUserTokenResponse} "User Tokens Retrieved Successfully"
-// @Failure 400 {object} models.Response "Invalid Input / Validation Error"
-// @Failure 404 {object} models.Response "User Not Found"
-// @Failure 500 {object} models.Response "Internal Server Error"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 428 {object} models.ErrorResponse "Captcha Response Required"
+// @Failure 429 {object} models.ErrorResponse "Too Many Failed Attempts"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /api/v1/users/tokens [post]
 func (h *userV1Handler) GetTokens(ctx echo.Context) error {
 	var request models.GetUserTokenRequest
-	if err := ctx.Bind(&request); err != nil {
-		return response.Error(ctx, err)
-	}
-
-	if err := validator.Input(request); err != nil {
-		return response.ErrorValidation(ctx, err)
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
 	}
 
 	user, err := h.service.User.GetTokens(ctx.Request().Context(), &request)
@@ -96,7 +116,7 @@ func (h *userV1Handler) GetTokens(ctx echo.Context) error {
 	ExpiresAt := time.Now().Add(time.Second * time.Duration(user.Tokens[1].ExpiredIn))
 
 	ctx.SetCookie(&http.Cookie{
-		Name:     "refresh_token",
+		Name:     middleware.RefreshTokenCookie,
 		Value:    user.Tokens[1].Token,
 		Expires:  ExpiresAt,
 		HttpOnly: true,
@@ -110,23 +130,487 @@ func (h *userV1Handler) GetTokens(ctx echo.Context) error {
 
 // GetUserByAccessToken retrieves user information by access token
 // @Summary Get User By Access Token
-// @Description Get user information by access token
+// @Description Get user information by access token. Responses carry an ETag; send it back in If-None-Match to get 304 when nothing changed.
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag of the cached response"
 // @Success 200 {object} models.Response{data=models.GetUserByAccountNumberResponse} "User Information Retrieved Successfully"
-// @Failure 400 {object} models.Response "Invalid Input / Validation Error"
-// @Failure 404 {object} models.Response "User Not Found"
-// @Failure 500 {object} models.Response "Internal Server Error"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /api/v1/users/me [get]
 // @Security BearerAuth
 func (h *userV1Handler) GetUserByAccessToken(ctx echo.Context) error {
-	accountNumber := ctx.Get("accountNumber").(string)
+	user, err := h.service.User.GetByAccountNumber(ctx.Request().Context(), api.AccountNumber(ctx))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessWithETag(ctx, user.GetUserByAccountNumberResponse())
+}
 
-	user, err := h.service.User.GetByAccountNumber(ctx.Request().Context(), accountNumber)
+// Update changes the profile of the authenticated user
+// @Summary Update User
+// @Description Rename the authenticated user. The request carries the version of GET /api/v1/users/me it was based on; when the user changed since, it fails with 409 VERSION_CONFLICT and nothing is updated, so reload the user and retry.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateUserRequest true "Profile Changes"
+// @Success 200 {object} models.Response{data=models.GetUserByAccountNumberResponse} "User Updated Successfully"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 409 {object} models.ErrorResponse "User Changed Since The Version Of The Request"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me [patch]
+// @Security BearerAuth
+func (h *userV1Handler) Update(ctx echo.Context) error {
+	var request models.UpdateUserRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	user, err := h.service.User.UpdateProfile(ctx.Request().Context(), api.AccountNumber(ctx), &request)
 	if err != nil {
 		return response.Error(ctx, err)
 	}
 
 	return response.Success(ctx, http.StatusOK, user.GetUserByAccountNumberResponse())
 }
+
+// Delete schedules the deletion of the authenticated user
+// @Summary Delete Account
+// @Description Schedule the deletion of the authenticated user after the grace period (account_deletion.grace_period) and sign them out everywhere. Signing in before then cancels the deletion; after it, the name, email, phone number, password, and logins of the account are erased.
+// @Tags Users
+// @Produce json
+// @Success 202 {object} models.Response{data=models.DeleteUserResponse} "Deletion Scheduled"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me [delete]
+// @Security BearerAuth
+func (h *userV1Handler) Delete(ctx echo.Context) error {
+	user, err := h.service.User.ScheduleDeletion(ctx.Request().Context(), api.AccountNumber(ctx))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusAccepted, user.DeleteUserResponse())
+}
+
+// ChangeEmail starts changing the email of the authenticated user
+// @Summary Change Email
+// @Description Start changing the email of the authenticated user. A confirmation token is emailed to the current and to the new address; the email changes once both were confirmed with POST /api/v1/users/email/confirm, within email_change.ttl. A newer request cancels the pending one. Accounts without an email only confirm the new address.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.ChangeEmailRequest true "New Email"
+// @Success 202 {object} models.Response{data=models.EmailChangeResponse} "Confirmation Emails Sent"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 409 {object} models.ErrorResponse "Email Already Exists"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/email [post]
+// @Security BearerAuth
+func (h *userV1Handler) ChangeEmail(ctx echo.Context) error {
+	var request models.ChangeEmailRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	change, err := h.service.User.RequestEmailChange(ctx.Request().Context(), api.AccountNumber(ctx), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusAccepted, change.EmailChangeResponse())
+}
+
+// ConfirmEmailChange confirms an email change with a token emailed to the old or new address
+// @Summary Confirm Email Change
+// @Description Confirm an email change with the token emailed to the current or to the new address. The email changes once both addresses confirmed, which signs the user out everywhere and notifies the old address.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.ConfirmEmailChangeRequest true "Confirmation Token"
+// @Success 200 {object} models.Response{data=models.EmailChangeResponse} "Email Change Confirmed"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid, Expired, or Cancelled Token"
+// @Failure 409 {object} models.ErrorResponse "Email Already Exists"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/email/confirm [post]
+func (h *userV1Handler) ConfirmEmailChange(ctx echo.Context) error {
+	var request models.ConfirmEmailChangeRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	change, err := h.service.User.ConfirmEmailChange(ctx.Request().Context(), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, change.EmailChangeResponse())
+}
+
+// CheckAvailability tells whether a username can be registered
+// @Summary Check Username Availability
+// @Description Tell whether a username can be registered. Usernames are compared lowercased; unavailable ones come with the reason: invalid (3 to 30 letters, digits, dots, or underscores), reserved, or taken.
+// @Tags Users
+// @Produce json
+// @Param username query string true "Username to check"
+// @Success 200 {object} models.Response{data=models.UsernameAvailabilityResponse} "Availability Checked"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/availability [get]
+func (h *userV1Handler) CheckAvailability(ctx echo.Context) error {
+	var request models.UsernameAvailabilityRequest
+	if err := binder.Query(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	availability, err := h.service.User.CheckUsernameAvailability(ctx.Request().Context(), request.Username)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, availability)
+}
+
+// ChangePhone starts changing the phone number of the authenticated user
+// @Summary Change Phone Number
+// @Description Start changing the phone number of the authenticated user. The number is formatted to E.164 and a 6-digit code is texted to it; the number changes once the code is verified with POST /api/v1/users/me/phone/verify, within phone_change.ttl. A newer request cancels the pending one.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.ChangePhoneRequest true "New Phone Number"
+// @Success 202 {object} models.Response{data=models.PhoneChangeResponse} "Verification Code Sent"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 409 {object} models.ErrorResponse "Phone Number Already Exists"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/phone [put]
+// @Security BearerAuth
+func (h *userV1Handler) ChangePhone(ctx echo.Context) error {
+	var request models.ChangePhoneRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	change, err := h.service.User.RequestPhoneChange(ctx.Request().Context(), api.AccountNumber(ctx), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusAccepted, change.PhoneChangeResponse())
+}
+
+// VerifyPhoneChange verifies the code texted to the new phone number of the authenticated user
+// @Summary Verify Phone Change
+// @Description Verify the code texted to the new phone number of the authenticated user, which switches the number. After phone_change.max_attempts wrong codes a new one must be requested.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.VerifyPhoneChangeRequest true "Verification Code"
+// @Success 200 {object} models.Response{data=models.PhoneChangeResponse} "Phone Number Changed"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid or Expired Code"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 409 {object} models.ErrorResponse "Phone Number Already Exists"
+// @Failure 429 {object} models.ErrorResponse "Too Many Wrong Codes"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/phone/verify [post]
+// @Security BearerAuth
+func (h *userV1Handler) VerifyPhoneChange(ctx echo.Context) error {
+	var request models.VerifyPhoneChangeRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	change, err := h.service.User.VerifyPhoneChange(ctx.Request().Context(), api.AccountNumber(ctx), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, change.PhoneChangeResponse())
+}
+
+// ListLogins retrieves the recent logins of the authenticated user
+// @Summary List Recent Logins
+// @Description List the most recent logins of the authenticated user, newest first. Logins from a country or device the account never used before are flagged as suspicious. Recorded when login_alerts is enabled.
+// @Tags Users
+// @Produce json
+// @Param limit query int false "Number of logins" minimum(1) maximum(100) default(20)
+// @Success 200 {object} models.Response{data=[]models.LoginEventResponse} "Logins Retrieved Successfully"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/logins [get]
+// @Security BearerAuth
+func (h *userV1Handler) ListLogins(ctx echo.Context) error {
+	var request models.ListLoginEventRequest
+	if err := binder.Query(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	events, err := h.service.User.ListLogins(ctx.Request().Context(), api.AccountNumber(ctx), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	data := make([]*models.LoginEventResponse, 0, len(events))
+	for i := range events {
+		data = append(data, events[i].LoginEventResponse())
+	}
+
+	return response.Success(ctx, http.StatusOK, data)
+}
+
+// GetConsent retrieves the consents of the authenticated user
+// @Summary Get Consents
+// @Description Get the terms version the authenticated user accepted, whether it is the current one, and their marketing choice
+// @Tags Users
+// @Produce json
+// @Success 200 {object} models.Response{data=models.ConsentResponse} "Consents Retrieved Successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/consents [get]
+// @Security BearerAuth
+func (h *userV1Handler) GetConsent(ctx echo.Context) error {
+	consent, err := h.service.User.GetConsent(ctx.Request().Context(), api.AccountNumber(ctx))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, consent.ConsentResponse(h.termsVersion()))
+}
+
+// UpdateConsent updates the consents of the authenticated user
+// @Summary Update Consents
+// @Description Accept the current terms version and/or change the marketing choice of the authenticated user. Every change is recorded with its time and client.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateConsentRequest true "Consent Changes"
+// @Success 200 {object} models.Response{data=models.ConsentResponse} "Consents Updated Successfully"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/consents [put]
+// @Security BearerAuth
+func (h *userV1Handler) UpdateConsent(ctx echo.Context) error {
+	var request models.UpdateConsentRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	consent, err := h.service.User.UpdateConsent(ctx.Request().Context(), api.AccountNumber(ctx), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, consent.ConsentResponse(h.termsVersion()))
+}
+
+// GetNotificationPreferences retrieves the notification preferences of the authenticated user
+// @Summary Get Notification Preferences
+// @Description Get the channels the authenticated user gets optional notifications (e.g. sign-in alerts) on. Verification codes and confirmations are sent whatever they are.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} models.Response{data=models.NotificationPreferencesResponse} "Notification Preferences Retrieved Successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/notifications [get]
+// @Security BearerAuth
+func (h *userV1Handler) GetNotificationPreferences(ctx echo.Context) error {
+	preferences, err := h.service.Notification.GetPreferences(ctx.Request().Context(), api.AccountNumber(ctx))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, preferences.NotificationPreferencesResponse())
+}
+
+// UpdateNotificationPreferences updates the notification preferences of the authenticated user
+// @Summary Update Notification Preferences
+// @Description Turn the email and/or SMS channel on or off for the optional notifications of the authenticated user; the channels left out keep their preference.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateNotificationPreferencesRequest true "Notification Preference Changes"
+// @Success 200 {object} models.Response{data=models.NotificationPreferencesResponse} "Notification Preferences Updated Successfully"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/users/me/notifications [put]
+// @Security BearerAuth
+func (h *userV1Handler) UpdateNotificationPreferences(ctx echo.Context) error {
+	var request models.UpdateNotificationPreferencesRequest
+	if err := api.Bind(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	preferences, err := h.service.Notification.UpdatePreferences(ctx.Request().Context(), api.AccountNumber(ctx), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, preferences.NotificationPreferencesResponse())
+}
+
+// termsVersion returns consent.terms_version, empty without a configuration
+func (h *userV1Handler) termsVersion() string {
+	if h.config == nil {
+		return ""
+	}
+	return h.config.Consent.TermsVersion
+}
+
+// List retrieves a paginated list of users for admin and support tooling
+// @Summary List Users
+// @Description List users with optional name and creation date filters. Answers 404 unless authorization.admin_api_key is set.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param name query string false "Partial, case-insensitive name match"
+// @Param createdFrom query string false "Created on or after (YYYY-MM-DD)"
+// @Param createdTo query string false "Created on or before (YYYY-MM-DD)"
+// @Param sort query string false "Sort order" Enums(name, -name, createdAt, -createdAt)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Success 200 {object} models.Response{data=[]models.UserSummaryResponse} "Users Retrieved Successfully"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Admin API Disabled"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/admin/users [get]
+func (h *userV1Handler) List(ctx echo.Context) error {
+	var request models.ListUserRequest
+	if err := binder.Query(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	users, total, err := h.service.User.List(ctx.Request().Context(), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	data := make([]*models.UserSummaryResponse, 0, len(users))
+	for i := range users {
+		data = append(data, users[i].UserSummaryResponse())
+	}
+
+	return response.SuccessPagination(ctx, http.StatusOK, "", api.Pagination(ctx, request.Page, request.Limit, total), data)
+}
+
+// Search finds users for admin and support tooling
+// @Summary Search Users
+// @Description Search users by name, username, email, or phone number, tolerating typos, or by exact account number. Results are ranked best match first. Answers 404 unless authorization.admin_api_key is set.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param q query string true "Search" minLength(2) maxLength(100)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Success 200 {object} models.Response{data=[]models.UserSearchResponse} "Users Found Successfully"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Admin API Disabled"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/admin/users/search [get]
+func (h *userV1Handler) Search(ctx echo.Context) error {
+	var request models.SearchUserRequest
+	if err := binder.Query(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	results, total, err := h.service.User.Search(ctx.Request().Context(), &request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	data := make([]*models.UserSearchResponse, 0, len(results))
+	for i := range results {
+		data = append(data, results[i].UserSearchResponse())
+	}
+
+	return response.SuccessPagination(ctx, http.StatusOK, "", api.Pagination(ctx, request.Page, request.Limit, total), data)
+}
+
+// Import creates users in bulk for admin and support tooling
+// @Summary Import Users
+// @Description Create up to 200 users from a CSV file (a header row naming the columns name, username, email, phone_number, phone_country_code, and password) or NDJSON (a user registration per line). Each row is validated like a signup and the valid ones are inserted in transactions of 100 rows; the report has the outcome of every row, by line. Answers 404 unless authorization.admin_api_key is set.
+// @Tags Admin
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body string true "Users to import"
+// @Success 200 {object} models.Response{data=models.UserImportResponse} "Users Imported"
+// @Failure 400 {object} models.ErrorResponse "Unreadable File / Too Many Rows"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Admin API Disabled"
+// @Failure 413 {object} models.ErrorResponse "File Too Large"
+// @Failure 415 {object} models.ErrorResponse "Neither CSV Nor NDJSON"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/admin/users/import [post]
+func (h *userV1Handler) Import(ctx echo.Context) error {
+	// Hashing the passwords of every row outlasts server.write_timeout
+	if err := response.ExtendWriteDeadline(ctx, service.UserImportWriteTimeout); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	req := ctx.Request()
+	rows, err := decodeUserImport(req.Header.Get(echo.HeaderContentType), req.Body, i18n.FromContext(req.Context()))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	report, err := h.service.User.Import(req.Context(), rows)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, report)
+}
+
+// Export streams every user for admin and support tooling
+// @Summary Export Users
+// @Description Download every user that is not deleted as CSV (the columns name, username, email, phone_number, phone_country_code, account_number, created_at, and deletion_scheduled_at, the first five as an import takes them) or NDJSON. The file is streamed as the users are read, in pages of 500. Answers 404 unless authorization.admin_api_key is set.
+// @Tags Admin
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param format query string false "File format" Enums(csv, ndjson) default(csv)
+// @Success 200 {array} models.UserExportResponse "Users, a line each"
+// @Failure 400 {object} models.ErrorResponse{errors=[]models.ErrorValidationResponse} "Invalid Input / Validation Error"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Admin API Disabled"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v1/admin/users/export [get]
+func (h *userV1Handler) Export(ctx echo.Context) error {
+	var request models.ExportUserRequest
+	if err := binder.Query(ctx, &request); err != nil {
+		return response.ErrorBinding(ctx, err)
+	}
+
+	requestCtx := ctx.Request().Context()
+	filename := "users-" + time.Now().UTC().Format("20060102-150405")
+
+	if request.Format == models.UserExportNDJSON {
+		return response.StreamNDJSON(ctx, filename+".ndjson", func(write func(v any) error) error {
+			return h.service.User.Export(requestCtx, func(user *models.User) error {
+				return write(user.UserExportResponse())
+			})
+		})
+	}
+
+	return response.StreamCSV(ctx, filename+".csv", models.UserExportColumns, func(write func(record []string) error) error {
+		return h.service.User.Export(requestCtx, func(user *models.User) error {
+			return write(user.UserExportRecord())
+		})
+	})
+}
@@ -1,20 +1,24 @@
 package v1_test
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"go-echo-boilerplate/internal/config"
 	v1 "go-echo-boilerplate/internal/deliveries/http/api/v1"
 	"go-echo-boilerplate/internal/models"
 	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/testutil"
 	"go-echo-boilerplate/internal/service"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func strPtr(s string) *string {
@@ -49,9 +53,151 @@ func (m *MockUserService) GetByAccountNumber(ctx context.Context, accountNumber
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserService) UpdateProfile(ctx context.Context, accountNumber string, request *models.UpdateUserRequest) (*models.User, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserService) List(ctx context.Context, request *models.ListUserRequest) ([]models.User, int, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserService) Search(ctx context.Context, request *models.SearchUserRequest) ([]models.UserSearchResult, int, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.UserSearchResult), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserService) Import(ctx context.Context, rows []models.UserImportRow) (*models.UserImportResponse, error) {
+	args := m.Called(ctx, rows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserImportResponse), args.Error(1)
+}
+
+func (m *MockUserService) Export(ctx context.Context, fn func(user *models.User) error) error {
+	args := m.Called(ctx, fn)
+	if users, ok := args.Get(0).([]models.User); ok {
+		for i := range users {
+			if err := fn(&users[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockUserService) ResetPassword(ctx context.Context, accountNumber, password string) error {
+	args := m.Called(ctx, accountNumber, password)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RevokeSessions(ctx context.Context, accountNumber string) error {
+	args := m.Called(ctx, accountNumber)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ListLogins(ctx context.Context, accountNumber string, request *models.ListLoginEventRequest) ([]models.LoginEvent, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LoginEvent), args.Error(1)
+}
+
+func (m *MockUserService) GetConsent(ctx context.Context, accountNumber string) (*models.Consent, error) {
+	args := m.Called(ctx, accountNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Consent), args.Error(1)
+}
+
+func (m *MockUserService) UpdateConsent(ctx context.Context, accountNumber string, request *models.UpdateConsentRequest) (*models.Consent, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Consent), args.Error(1)
+}
+
+func (m *MockUserService) HasAcceptedTerms(ctx context.Context, accountNumber string) (bool, error) {
+	args := m.Called(ctx, accountNumber)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserService) ScheduleDeletion(ctx context.Context, accountNumber string) (*models.User, error) {
+	args := m.Called(ctx, accountNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserService) AnonymizeDueAccounts(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserService) RequestEmailChange(ctx context.Context, accountNumber string, request *models.ChangeEmailRequest) (*models.EmailChange, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailChange), args.Error(1)
+}
+
+func (m *MockUserService) RequestPhoneChange(ctx context.Context, accountNumber string, request *models.ChangePhoneRequest) (*models.PhoneChange, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PhoneChange), args.Error(1)
+}
+
+func (m *MockUserService) VerifyPhoneChange(ctx context.Context, accountNumber string, request *models.VerifyPhoneChangeRequest) (*models.PhoneChange, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PhoneChange), args.Error(1)
+}
+
+func (m *MockUserService) CheckUsernameAvailability(ctx context.Context, username string) (*models.UsernameAvailabilityResponse, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UsernameAvailabilityResponse), args.Error(1)
+}
+
+func (m *MockUserService) ConfirmEmailChange(ctx context.Context, request *models.ConfirmEmailChangeRequest) (*models.EmailChange, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailChange), args.Error(1)
+}
+
+// newClient serves the v1 user routes with the mocked service
+func newClient(t *testing.T, mockSvc *MockUserService) *testutil.APIClient {
+	e := echo.New()
+	v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, nil)
+	return testutil.NewAPIClient(t, e, nil)
+}
+
 func TestUserV1Handler_Create(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		e := echo.New()
 		reqBody := models.CreateUserRequest{
 			Name:     "Test User",
 			Email:    "test@example.com",
@@ -61,10 +207,6 @@ func TestUserV1Handler_Create(t *testing.T) {
 				CountryCode: "ID",
 			},
 		}
-		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(jsonBody))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
 
 		mockSvc := new(MockUserService)
 		mockSvc.On("Create", mock.Anything, mock.MatchedBy(func(r *models.CreateUserRequest) bool {
@@ -75,89 +217,44 @@ func TestUserV1Handler_Create(t *testing.T) {
 			Name:  reqBody.Name,
 		}, nil)
 
-		svc := &service.Service{
-			User: mockSvc,
-		}
-
-		// Since NewUserV1 registers routes, we can't test handler method directly nicely unless we export it or use the router.
-		// Use reflect or just checking the registered route?
-		// Actually, standard way is to create the handler struct manually or call the constructor and then rely on Echo router matching.
-		// However, NewUserV1 takes a Group.
-		// Let's create a wrapper to assert the handler logic.
-		// But wait, the handler struct `userV1Handler` is private.
-		// So we must test via route dispatch or refactor to make handler testable.
-		// Given I cannot easily refactor to export handler, testing via router is best.
-
-		// Setup Group
-		g := e.Group("/v1")
-		v1.NewUserV1(g, svc, nil, nil)
+		res := newClient(t, mockSvc).PostJSON("/v1/users", reqBody)
 
-		// ServeRequest
-		e.ServeHTTP(rec, req)
+		res.AssertStatus(http.StatusCreated)
+		assert.Equal(t, "Request has been successfully processed.", res.Response.Message)
 
-		assert.Equal(t, http.StatusCreated, rec.Code)
-		assert.Contains(t, rec.Body.String(), "Request has been successfully processed.")
+		var created models.CreateUserResponse
+		res.DecodeData(&created)
+		assert.Equal(t, reqBody.Email, created.Email)
 
 		mockSvc.AssertExpectations(t)
 	})
 
 	t.Run("Validation Error", func(t *testing.T) {
-		e := echo.New()
-		reqBody := models.CreateUserRequest{
-			// Missing required fields
-		}
-		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(jsonBody))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
-
-		// Setup Service
-		svc := &service.Service{User: new(MockUserService)}
-		g := e.Group("/v1")
-		v1.NewUserV1(g, svc, nil, nil)
-
-		e.ServeHTTP(rec, req)
+		res := newClient(t, new(MockUserService)).PostJSON("/v1/users", models.CreateUserRequest{})
 
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), "Validation failed")
+		res.AssertValidationError("name")
 	})
 
 	t.Run("Service Error - Already Exist", func(t *testing.T) {
-		e := echo.New()
-		reqBody := models.CreateUserRequest{
-			Name:     "Test",
-			Email:    "exist@example.com",
-			Password: "pass",
-		}
-		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(jsonBody))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
-
 		mockSvc := new(MockUserService)
 		mockSvc.On("Create", mock.Anything, mock.Anything).Return(nil, errorc.Error(errorc.ErrorAlreadyExist))
 
-		svc := &service.Service{User: mockSvc}
-		g := e.Group("/v1")
-		v1.NewUserV1(g, svc, nil, nil)
-
-		e.ServeHTTP(rec, req)
+		res := newClient(t, mockSvc).PostJSON("/v1/users", models.CreateUserRequest{
+			Name:     "Test",
+			Email:    "exist@example.com",
+			Password: "pass",
+		})
 
-		assert.Equal(t, http.StatusConflict, rec.Code)
+		res.AssertError(errorc.ErrorAlreadyExist)
 	})
 }
 
 func TestUserV1Handler_GetTokens(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		e := echo.New()
 		reqBody := models.GetUserTokenRequest{
 			Email:    "test@example.com",
 			Password: "password123",
 		}
-		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/v1/users/tokens", bytes.NewReader(jsonBody))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
 
 		mockSvc := new(MockUserService)
 		mockSvc.On("GetTokens", mock.Anything, mock.MatchedBy(func(r *models.GetUserTokenRequest) bool {
@@ -170,59 +267,701 @@ func TestUserV1Handler_GetTokens(t *testing.T) {
 			},
 		}, nil)
 
-		svc := &service.Service{User: mockSvc}
-		g := e.Group("/v1")
-		v1.NewUserV1(g, svc, nil, nil)
+		res := newClient(t, mockSvc).PostJSON("/v1/users/tokens", reqBody)
 
-		e.ServeHTTP(rec, req)
+		res.AssertStatus(http.StatusOK)
+		var tokens models.GetUserTokenResponse
+		res.DecodeData(&tokens)
+		if assert.Len(t, tokens.Tokens, 2) {
+			assert.Equal(t, "access_token_mock", tokens.Tokens[0].Token)
+		}
+
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		res := newClient(t, new(MockUserService)).PostJSON("/v1/users/tokens", models.GetUserTokenRequest{})
 
-		assert.Equal(t, http.StatusOK, rec.Code)
-		assert.Contains(t, rec.Body.String(), "Request has been successfully processed.")
-		assert.Contains(t, rec.Body.String(), "access_token_mock")
+		res.AssertValidationError("password")
+	})
+
+	t.Run("Invalid Credentials", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("GetTokens", mock.Anything, mock.Anything).Return(nil, errorc.Error(errorc.ErrorInvalidInput))
 
+		res := newClient(t, mockSvc).PostJSON("/v1/users/tokens", models.GetUserTokenRequest{
+			Email:    "test@example.com",
+			Password: "wrongpassword",
+		})
+
+		res.AssertError(errorc.ErrorInvalidInput)
+	})
+}
+
+func TestUserV1Handler_ListLogins(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newAuthClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig).AuthAs(user)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("ListLogins", mock.Anything, user.AccountNumber, &models.ListLoginEventRequest{Limit: 5}).Return([]models.LoginEvent{
+			{AccountNumber: user.AccountNumber, IP: "81.2.69.142", Country: "GB", Device: "mobile", Suspicious: true, Reasons: "new_country,new_device"},
+			{AccountNumber: user.AccountNumber, IP: "103.10.20.30", Country: "ID", Device: "desktop"},
+		}, nil)
+
+		res := newAuthClient(mockSvc).Get("/v1/users/me/logins?limit=5")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var logins []models.LoginEventResponse
+		res.DecodeData(&logins)
+		if assert.Len(t, logins, 2) {
+			assert.True(t, logins[0].Suspicious)
+			assert.Equal(t, []string{"new_country", "new_device"}, logins[0].Reasons)
+			assert.Equal(t, []string{}, logins[1].Reasons)
+		}
 		mockSvc.AssertExpectations(t)
 	})
 
 	t.Run("Validation Error", func(t *testing.T) {
+		newAuthClient(new(MockUserService)).Get("/v1/users/me/logins?limit=500").AssertValidationError("limit")
+	})
+}
+
+func TestUserV1Handler_Consents(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	configuration := &config.Configuration{Consent: config.Consent{TermsVersion: "2026-10-01"}}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newAuthClient := func(mockSvc *MockUserService) *testutil.APIClient {
 		e := echo.New()
-		reqBody := models.GetUserTokenRequest{} // Empty request
-		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/v1/users/tokens", bytes.NewReader(jsonBody))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, configuration, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig).AuthAs(user)
+	}
+
+	t.Run("Get Outdated Consent", func(t *testing.T) {
+		acceptedAt := time.Now().Add(-24 * time.Hour)
+		mockSvc := new(MockUserService)
+		mockSvc.On("GetConsent", mock.Anything, user.AccountNumber).
+			Return(&models.Consent{AccountNumber: user.AccountNumber, TermsVersion: "2026-01-01", TermsAcceptedAt: &acceptedAt}, nil)
+
+		res := newAuthClient(mockSvc).Get("/v1/users/me/consents")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var consent models.ConsentResponse
+		res.DecodeData(&consent)
+		assert.Equal(t, "2026-01-01", consent.TermsVersion)
+		assert.Equal(t, "2026-10-01", consent.CurrentTermsVersion)
+		assert.False(t, consent.TermsAccepted)
+		mockSvc.AssertExpectations(t)
+	})
 
+	t.Run("Get Without Consent", func(t *testing.T) {
 		mockSvc := new(MockUserService)
-		svc := &service.Service{User: mockSvc}
-		g := e.Group("/v1")
-		v1.NewUserV1(g, svc, nil, nil)
+		mockSvc.On("GetConsent", mock.Anything, user.AccountNumber).Return(nil, nil)
 
-		e.ServeHTTP(rec, req)
+		res := newAuthClient(mockSvc).Get("/v1/users/me/consents")
 
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), "Validation failed")
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var consent models.ConsentResponse
+		res.DecodeData(&consent)
+		assert.Empty(t, consent.TermsVersion)
+		assert.False(t, consent.TermsAccepted)
 	})
 
-	t.Run("Invalid Credentials", func(t *testing.T) {
+	t.Run("Update Success", func(t *testing.T) {
+		optIn := true
+		request := &models.UpdateConsentRequest{TermsVersion: "2026-10-01", MarketingOptIn: &optIn}
+		mockSvc := new(MockUserService)
+		mockSvc.On("UpdateConsent", mock.Anything, user.AccountNumber, request).
+			Return(&models.Consent{AccountNumber: user.AccountNumber, TermsVersion: "2026-10-01", MarketingOptIn: true}, nil)
+
+		res := newAuthClient(mockSvc).PutJSON("/v1/users/me/consents", request)
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var consent models.ConsentResponse
+		res.DecodeData(&consent)
+		assert.True(t, consent.TermsAccepted)
+		assert.True(t, consent.MarketingOptIn)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) GetPreferences(ctx context.Context, accountNumber string) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, accountNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationService) UpdatePreferences(ctx context.Context, accountNumber string, request *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, accountNumber, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationService) ListDeadLetters(ctx context.Context, limit int) ([]models.NotificationDeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NotificationDeadLetter), args.Error(1)
+}
+
+func (m *MockNotificationService) RetryDeadLetter(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func TestUserV1Handler_NotificationPreferences(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newAuthClient := func(mockSvc *MockNotificationService) *testutil.APIClient {
 		e := echo.New()
-		reqBody := models.GetUserTokenRequest{
-			Email:    "test@example.com",
-			Password: "wrongpassword",
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: new(MockUserService), Notification: mockSvc}, &config.Configuration{}, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig).AuthAs(user)
+	}
+
+	t.Run("Get Defaults", func(t *testing.T) {
+		mockSvc := new(MockNotificationService)
+		mockSvc.On("GetPreferences", mock.Anything, user.AccountNumber).
+			Return(models.DefaultNotificationPreferences(user.AccountNumber), nil)
+
+		res := newAuthClient(mockSvc).Get("/v1/users/me/notifications")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var preferences models.NotificationPreferencesResponse
+		res.DecodeData(&preferences)
+		assert.Equal(t, models.NotificationPreferencesResponse{Type: "notificationPreferences", Email: true, SMS: true}, preferences)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Update Success", func(t *testing.T) {
+		off := false
+		request := &models.UpdateNotificationPreferencesRequest{SMS: &off}
+		mockSvc := new(MockNotificationService)
+		mockSvc.On("UpdatePreferences", mock.Anything, user.AccountNumber, request).
+			Return(&models.NotificationPreferences{AccountNumber: user.AccountNumber, Email: true, UpdatedAt: time.Now()}, nil)
+
+		res := newAuthClient(mockSvc).PutJSON("/v1/users/me/notifications", request)
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var preferences models.NotificationPreferencesResponse
+		res.DecodeData(&preferences)
+		assert.True(t, preferences.Email)
+		assert.False(t, preferences.SMS)
+		assert.NotNil(t, preferences.UpdatedAt)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mockSvc := new(MockNotificationService)
+		mockSvc.On("GetPreferences", mock.Anything, user.AccountNumber).
+			Return(nil, errorc.Error(errorc.ErrorDatabase, "Failed to get notification preferences"))
+
+		res := newAuthClient(mockSvc).Get("/v1/users/me/notifications")
+
+		res.AssertError(errorc.ErrorDatabase)
+	})
+}
+
+func TestUserV1Handler_Delete(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newAuthClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig).AuthAs(user)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		scheduledAt := time.Date(2026, 11, 15, 9, 0, 0, 0, time.UTC)
+		mockSvc := new(MockUserService)
+		mockSvc.On("ScheduleDeletion", mock.Anything, user.AccountNumber).
+			Return(&models.User{AccountNumber: user.AccountNumber, DeletionScheduledAt: &scheduledAt}, nil)
+
+		res := newAuthClient(mockSvc).Delete("/v1/users/me")
+
+		require.True(t, res.AssertStatus(http.StatusAccepted))
+		var deletion models.DeleteUserResponse
+		res.DecodeData(&deletion)
+		assert.Equal(t, user.AccountNumber, deletion.AccountNumber)
+		assert.True(t, scheduledAt.Equal(deletion.DeletionScheduledAt))
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("ScheduleDeletion", mock.Anything, user.AccountNumber).
+			Return(nil, errorc.Error(errorc.ErrorDatabase, "Failed to schedule deletion"))
+
+		newAuthClient(mockSvc).Delete("/v1/users/me").AssertError(errorc.ErrorDatabase)
+	})
+}
+
+func TestUserV1Handler_Update(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newAuthClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig).AuthAs(user)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("UpdateProfile", mock.Anything, user.AccountNumber, &models.UpdateUserRequest{Name: "John Doe", Version: 3}).
+			Return(&models.User{AccountNumber: user.AccountNumber, Name: "John Doe", Version: 4}, nil)
+
+		res := newAuthClient(mockSvc).PatchJSON("/v1/users/me", map[string]any{"name": "John Doe", "version": 3})
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var updated models.GetUserByAccountNumberResponse
+		res.DecodeData(&updated)
+		assert.Equal(t, "John Doe", updated.Name)
+		assert.Equal(t, 4, updated.Version)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Version Required", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+
+		newAuthClient(mockSvc).PatchJSON("/v1/users/me", map[string]any{"name": "John Doe"}).AssertValidationError("version")
+		mockSvc.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Version Conflict", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("UpdateProfile", mock.Anything, user.AccountNumber, mock.Anything).
+			Return(nil, errorc.Error(errorc.ErrorVersionConflict))
+
+		newAuthClient(mockSvc).PatchJSON("/v1/users/me", map[string]any{"name": "John Doe", "version": 1}).
+			AssertError(errorc.ErrorVersionConflict)
+	})
+}
+
+func TestUserV1Handler_EmailChange(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig)
+	}
+
+	t.Run("Request Success", func(t *testing.T) {
+		confirmedAt := time.Now()
+		mockSvc := new(MockUserService)
+		mockSvc.On("RequestEmailChange", mock.Anything, user.AccountNumber, &models.ChangeEmailRequest{Email: "johnny@example.com"}).
+			Return(&models.EmailChange{NewEmail: "johnny@example.com", OldConfirmedAt: &confirmedAt, ExpiresAt: confirmedAt.Add(time.Hour)}, nil)
+
+		res := newClient(mockSvc).AuthAs(user).PostJSON("/v1/users/me/email", map[string]string{"email": "johnny@example.com"})
+
+		require.True(t, res.AssertStatus(http.StatusAccepted))
+		var change models.EmailChangeResponse
+		res.DecodeData(&change)
+		assert.Equal(t, "johnny@example.com", change.NewEmail)
+		assert.True(t, change.OldConfirmed)
+		assert.False(t, change.NewConfirmed)
+		assert.False(t, change.Completed)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Request Invalid Email", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+
+		res := newClient(mockSvc).AuthAs(user).PostJSON("/v1/users/me/email", map[string]string{"email": "johnny"})
+
+		res.AssertStatus(http.StatusBadRequest)
+		mockSvc.AssertNotCalled(t, "RequestEmailChange")
+	})
+
+	t.Run("Request Email Exists", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("RequestEmailChange", mock.Anything, user.AccountNumber, mock.Anything).
+			Return(nil, errorc.Error(errorc.ErrorEmailExists))
+
+		newClient(mockSvc).AuthAs(user).PostJSON("/v1/users/me/email", map[string]string{"email": "johnny@example.com"}).
+			AssertError(errorc.ErrorEmailExists)
+	})
+
+	t.Run("Request Requires Authentication", func(t *testing.T) {
+		newClient(new(MockUserService)).PostJSON("/v1/users/me/email", map[string]string{"email": "johnny@example.com"}).
+			AssertStatus(http.StatusUnauthorized)
+	})
+
+	t.Run("Confirm Success", func(t *testing.T) {
+		now := time.Now()
+		mockSvc := new(MockUserService)
+		mockSvc.On("ConfirmEmailChange", mock.Anything, &models.ConfirmEmailChangeRequest{Token: "token"}).
+			Return(&models.EmailChange{NewEmail: "johnny@example.com", OldConfirmedAt: &now, NewConfirmedAt: &now, CompletedAt: &now}, nil)
+
+		res := newClient(mockSvc).PostJSON("/v1/users/email/confirm", map[string]string{"token": "token"})
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var change models.EmailChangeResponse
+		res.DecodeData(&change)
+		assert.True(t, change.Completed)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Confirm Invalid Token", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("ConfirmEmailChange", mock.Anything, mock.Anything).
+			Return(nil, errorc.Error(errorc.ErrorInvalidData, "Invalid confirmation token"))
+
+		newClient(mockSvc).PostJSON("/v1/users/email/confirm", map[string]string{"token": "forged"}).
+			AssertError(errorc.ErrorInvalidData)
+	})
+}
+
+func TestUserV1Handler_PhoneChange(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John"}
+
+	newAuthClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig).AuthAs(user)
+	}
+
+	t.Run("Request Success", func(t *testing.T) {
+		request := &models.ChangePhoneRequest{PhoneNumber: models.PhoneNumber{Number: "081234567890", CountryCode: "ID"}}
+		mockSvc := new(MockUserService)
+		mockSvc.On("RequestPhoneChange", mock.Anything, user.AccountNumber, request).
+			Return(&models.PhoneChange{PhoneNumber: "+6281234567890", PhoneCountryCode: "ID", ExpiresAt: time.Now().Add(10 * time.Minute)}, nil)
+
+		res := newAuthClient(mockSvc).PutJSON("/v1/users/me/phone", request)
+
+		require.True(t, res.AssertStatus(http.StatusAccepted))
+		var change models.PhoneChangeResponse
+		res.DecodeData(&change)
+		assert.Equal(t, "+6281234567890", change.PhoneNumber.Number)
+		assert.False(t, change.Completed)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Request Invalid Number", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+
+		newAuthClient(mockSvc).PutJSON("/v1/users/me/phone", map[string]any{"phoneNumber": map[string]string{"number": "call me"}}).
+			AssertStatus(http.StatusBadRequest)
+		mockSvc.AssertNotCalled(t, "RequestPhoneChange")
+	})
+
+	t.Run("Verify Success", func(t *testing.T) {
+		now := time.Now()
+		mockSvc := new(MockUserService)
+		mockSvc.On("VerifyPhoneChange", mock.Anything, user.AccountNumber, &models.VerifyPhoneChangeRequest{Code: "042917"}).
+			Return(&models.PhoneChange{PhoneNumber: "+6281234567890", PhoneCountryCode: "ID", CompletedAt: &now}, nil)
+
+		res := newAuthClient(mockSvc).PostJSON("/v1/users/me/phone/verify", map[string]string{"code": "042917"})
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var change models.PhoneChangeResponse
+		res.DecodeData(&change)
+		assert.True(t, change.Completed)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Verify Malformed Code", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+
+		newAuthClient(mockSvc).PostJSON("/v1/users/me/phone/verify", map[string]string{"code": "12ab"}).AssertStatus(http.StatusBadRequest)
+		mockSvc.AssertNotCalled(t, "VerifyPhoneChange")
+	})
+
+	t.Run("Verify Too Many Attempts", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("VerifyPhoneChange", mock.Anything, user.AccountNumber, mock.Anything).
+			Return(nil, errorc.Error(errorc.ErrorTooManyAttempts, "Too many wrong codes, request a new one"))
+
+		newAuthClient(mockSvc).PostJSON("/v1/users/me/phone/verify", map[string]string{"code": "000000"}).
+			AssertError(errorc.ErrorTooManyAttempts)
+	})
+}
+
+func TestUserV1Handler_CheckAvailability(t *testing.T) {
+	newClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, nil, &jwtc.Configuration{})
+		return testutil.NewAPIClient(t, e, nil)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("CheckUsernameAvailability", mock.Anything, "John.Doe").Return(&models.UsernameAvailabilityResponse{
+			Type:     models.TYPE_USERNAME_AVAILABILITY,
+			Username: "john.doe",
+			Reason:   models.UsernameTaken,
+		}, nil)
+
+		res := newClient(mockSvc).Get("/v1/users/availability?username=John.Doe")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var availability models.UsernameAvailabilityResponse
+		res.DecodeData(&availability)
+		assert.False(t, availability.Available)
+		assert.Equal(t, models.UsernameTaken, availability.Reason)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		newClient(new(MockUserService)).Get("/v1/users/availability").AssertValidationError("username")
+	})
+}
+
+func TestUserV1Handler_List(t *testing.T) {
+	configuration := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: "admin-secret"}}
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+
+	newClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, configuration, jwtConfig)
+		return testutil.NewAPIClient(t, e, jwtConfig)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("List", mock.Anything, &models.ListUserRequest{Name: "john", Page: 2, Limit: 1}).
+			Return([]models.User{{AccountNumber: "1234567890", Name: "John Doe"}}, 3, nil)
+
+		res := newClient(mockSvc).WithHeader("X-Admin-Key", "admin-secret").Get("/v1/admin/users?name=john&page=2&limit=1")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var users []models.UserSummaryResponse
+		res.DecodeData(&users)
+		if assert.Len(t, users, 1) {
+			assert.Equal(t, "1234567890", users[0].AccountNumber)
 		}
-		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/v1/users/tokens", bytes.NewReader(jsonBody))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
+		mockSvc.AssertExpectations(t)
+	})
 
+	t.Run("Not For Users", func(t *testing.T) {
 		mockSvc := new(MockUserService)
-		mockSvc.On("GetTokens", mock.Anything, mock.Anything).Return(nil, errorc.Error(errorc.ErrorInvalidInput))
+		client := newClient(mockSvc).AuthAs(&models.User{ID: 1, AccountNumber: "1234567890", Name: "John"})
+
+		client.Get("/v1/admin/users").AssertError(errorc.ErrorUnauthorized)
+		assert.NotEqual(t, http.StatusOK, client.Get("/v1/users").Code, "no longer listed to any bearer")
+		mockSvc.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserV1Handler_Search(t *testing.T) {
+	configuration := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: "admin-secret"}}
+
+	newClient := func(mockSvc *MockUserService, configuration *config.Configuration) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, configuration, &jwtc.Configuration{})
+		return testutil.NewAPIClient(t, e, nil)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		email := "john.doe@example.com"
+		mockSvc := new(MockUserService)
+		mockSvc.On("Search", mock.Anything, &models.SearchUserRequest{Q: "jon", Page: 2, Limit: 1}).Return([]models.UserSearchResult{
+			{User: models.User{AccountNumber: "1234567890", Name: "John Doe", Email: &email}, Rank: 0.5},
+		}, 3, nil)
+
+		res := newClient(mockSvc, configuration).WithHeader("X-Admin-Key", "admin-secret").Get("/v1/admin/users/search?q=jon&page=2&limit=1")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var results []models.UserSearchResponse
+		res.DecodeData(&results)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "1234567890", results[0].AccountNumber)
+			assert.Equal(t, email, results[0].Email)
+			assert.Nil(t, results[0].PhoneNumber)
+			assert.Equal(t, 0.5, results[0].Rank)
+		}
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		newClient(new(MockUserService), configuration).WithHeader("X-Admin-Key", "admin-secret").
+			Get("/v1/admin/users/search?q=j").AssertValidationError("q")
+	})
+
+	t.Run("Requires The Admin Key", func(t *testing.T) {
+		newClient(new(MockUserService), configuration).Get("/v1/admin/users/search?q=jon").AssertError(errorc.ErrorUnauthorized)
+		newClient(new(MockUserService), nil).WithHeader("X-Admin-Key", "admin-secret").
+			Get("/v1/admin/users/search?q=jon").AssertError(errorc.ErrorDataNotFound)
+	})
+}
+
+func TestUserV1Handler_Import(t *testing.T) {
+	configuration := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: "admin-secret"}}
+
+	newClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, configuration, &jwtc.Configuration{})
+		return testutil.NewAPIClient(t, e, nil).WithHeader("X-Admin-Key", "admin-secret")
+	}
+	upload := func(client *testutil.APIClient, contentType, body string) *testutil.APIResponse {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/users/import", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		return client.Do(req)
+	}
+	report := &models.UserImportResponse{Type: models.TYPE_USER_IMPORT, Total: 1, Imported: 1}
+
+	t.Run("CSV", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("Import", mock.Anything, mock.MatchedBy(func(rows []models.UserImportRow) bool {
+			return len(rows) == 3 &&
+				rows[0].Line == 2 && rows[0].User.Email == "john@example.com" && rows[0].User.PhoneNumber.CountryCode == "ID" && len(rows[0].Errors) == 0 &&
+				rows[1].Line == 3 && len(rows[1].Errors) > 0 && rows[1].Errors[0].Field == "email" &&
+				rows[2].Line == 4 && len(rows[2].Errors) == 1
+		})).Return(report, nil)
+
+		res := upload(newClient(mockSvc), "text/csv; charset=utf-8",
+			"\ufeffName,Email,Phone_Number,Phone_Country_Code,Password\n"+
+				"John Doe, john@example.com,,ID,Str0ng!Passw0rd\n"+
+				"Jane Doe,not-an-email,,,Str0ng!Passw0rd\n"+
+				"Too Short\n")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		mockSvc.AssertExpectations(t)
+	})
 
-		svc := &service.Service{User: mockSvc}
-		g := e.Group("/v1")
-		v1.NewUserV1(g, svc, nil, nil)
+	t.Run("NDJSON", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("Import", mock.Anything, mock.MatchedBy(func(rows []models.UserImportRow) bool {
+			return len(rows) == 2 &&
+				rows[0].Line == 1 && rows[0].User.Username == "john.doe" && len(rows[0].Errors) == 0 &&
+				rows[1].Line == 3 && len(rows[1].Errors) == 1
+		})).Return(report, nil)
+
+		res := upload(newClient(mockSvc), "application/x-ndjson",
+			`{"name":"John Doe","username":"john.doe","email":"john@example.com","password":"Str0ng!Passw0rd"}`+"\n\n{oops\n")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		var imported models.UserImportResponse
+		res.DecodeData(&imported)
+		assert.Equal(t, 1, imported.Imported)
+		mockSvc.AssertExpectations(t)
+	})
 
-		e.ServeHTTP(rec, req)
+	t.Run("Unknown Column", func(t *testing.T) {
+		upload(newClient(new(MockUserService)), "text/csv", "name,role\nJohn,admin\n").AssertError(errorc.ErrorInvalidInput)
+	})
 
-		// ErrorInvalidInput typically maps to 400 Bad Request
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	t.Run("Unsupported Media Type", func(t *testing.T) {
+		upload(newClient(new(MockUserService)), "application/json", `[]`).AssertError(errorc.ErrorUnsupportedMediaType)
+	})
+}
+
+func TestUserV1Handler_Export(t *testing.T) {
+	configuration := &config.Configuration{Authorization: config.Authorization{AdminAPIKey: "admin-secret"}}
+	users := []models.User{
+		{AccountNumber: "1234567890", Name: "John Doe", Email: strPtr("john@example.com"), CreatedAt: time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)},
+		{AccountNumber: "2345678901", Name: "Jane Doe", PhoneNumber: strPtr("+6281234567890"), PhoneCountryCode: "ID"},
+	}
+
+	newClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v1.NewUserV1(e.Group("/v1"), &service.Service{User: mockSvc}, configuration, &jwtc.Configuration{})
+		return testutil.NewAPIClient(t, e, nil).WithHeader("X-Admin-Key", "admin-secret")
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("Export", mock.Anything, mock.Anything).Return(users, nil)
+
+		res := newClient(mockSvc).Get("/v1/admin/users/export")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		assert.Equal(t, "text/csv; charset=utf-8", res.Header().Get(echo.HeaderContentType))
+		assert.Regexp(t, `^attachment; filename=users-\d{8}-\d{6}\.csv$`, res.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, "name,username,email,phone_number,phone_country_code,account_number,created_at,deletion_scheduled_at\n"+
+			"John Doe,,john@example.com,,,1234567890,2026-01-24T08:00:00Z,\n"+
+			"Jane Doe,,,+6281234567890,ID,2345678901,0001-01-01T00:00:00Z,\n", res.Body.String())
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("Export", mock.Anything, mock.Anything).Return(users, nil)
+
+		res := newClient(mockSvc).Get("/v1/admin/users/export?format=ndjson")
+
+		require.True(t, res.AssertStatus(http.StatusOK))
+		lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+		if assert.Len(t, lines, 2) {
+			assert.Contains(t, lines[0], `"email":"john@example.com"`)
+			assert.Contains(t, lines[1], `"phoneNumber":{"countryCode":"ID","number":"+6281234567890"}`)
+		}
+	})
+
+	t.Run("Database Error Before Any Row", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("Export", mock.Anything, mock.Anything).Return(nil, errorc.Error(errorc.ErrorDatabase))
+
+		newClient(mockSvc).Get("/v1/admin/users/export").AssertError(errorc.ErrorDatabase)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		newClient(new(MockUserService)).Get("/v1/admin/users/export?format=xlsx").AssertValidationError("format")
 	})
 }
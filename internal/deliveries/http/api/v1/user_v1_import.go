@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/service"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Media types of a user import
+const (
+	mimeTextCSV           = "text/csv"
+	mimeApplicationNDJSON = "application/x-ndjson"
+)
+
+// userImportColumns sets the CreateUserRequest field of each CSV column
+var userImportColumns = map[string]func(user *models.CreateUserRequest, value string){
+	"name":               func(user *models.CreateUserRequest, value string) { user.Name = value },
+	"username":           func(user *models.CreateUserRequest, value string) { user.Username = value },
+	"email":              func(user *models.CreateUserRequest, value string) { user.Email = value },
+	"phone_number":       func(user *models.CreateUserRequest, value string) { user.PhoneNumber.Number = value },
+	"phone_country_code": func(user *models.CreateUserRequest, value string) { user.PhoneNumber.CountryCode = value },
+	"password":           func(user *models.CreateUserRequest, value string) { user.Password = value },
+}
+
+// decodeUserImport reads the rows of a CSV (with a header row naming the columns of
+// userImportColumns) or NDJSON (a CreateUserRequest per line) import and validates each
+// like a signup. Rows that do not decode or validate carry their errors; a file that cannot
+// be read at all, or has more than service.MaxUserImportRows rows, is an error.
+func decodeUserImport(contentType string, body io.Reader, locale i18n.Locale) ([]models.UserImportRow, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorUnsupportedMediaType)
+	}
+
+	var rows []models.UserImportRow
+	switch mediaType {
+	case mimeTextCSV:
+		rows, err = decodeUserImportCSV(body)
+	case mimeApplicationNDJSON:
+		rows, err = decodeUserImportNDJSON(body)
+	default:
+		return nil, errorc.Error(errorc.ErrorUnsupportedMediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		if len(rows[i].Errors) > 0 {
+			continue
+		}
+		if err := validator.Input(&rows[i].User, locale); err != nil {
+			rows[i].Errors = validationErrors(err)
+		}
+	}
+	return rows, nil
+}
+
+func decodeUserImportCSV(body io.Reader) ([]models.UserImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, invalidUserImport("CSV", err)
+	}
+
+	setters := make([]func(user *models.CreateUserRequest, value string), len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		setter, ok := userImportColumns[column]
+		if !ok {
+			return nil, errorc.Error(errorc.ErrorInvalidInput, fmt.Sprintf("Unknown CSV column %q", column))
+		}
+		setters[i] = setter
+	}
+
+	var rows []models.UserImportRow
+	for len(rows) <= service.MaxUserImportRows {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, invalidUserImport("CSV", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := models.UserImportRow{Line: line}
+		if len(record) != len(header) {
+			row.Errors = []models.ErrorValidationResponse{{
+				Code:    validator.ErrorCodeInvalidField,
+				Message: fmt.Sprintf("Expected %d columns, got %d", len(header), len(record)),
+			}}
+		} else {
+			for i, value := range record {
+				setters[i](&row.User, strings.TrimSpace(value))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func decodeUserImportNDJSON(body io.Reader) ([]models.UserImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var rows []models.UserImportRow
+	for line := 1; len(rows) <= service.MaxUserImportRows && scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := models.UserImportRow{Line: line}
+		if err := json.Unmarshal([]byte(text), &row.User); err != nil {
+			row.Errors = []models.ErrorValidationResponse{{
+				Code:    validator.ErrorCodeInvalidField,
+				Message: "Invalid JSON: " + err.Error(),
+			}}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, invalidUserImport("NDJSON", err)
+	}
+	return rows, nil
+}
+
+// invalidUserImport is the error of an import that cannot be read; a body past the limit
+// of the BodyLimitMiddleware is kept so it answers 413
+func invalidUserImport(format string, err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+	return errorc.Error(errorc.ErrorInvalidInput, "Invalid "+format+": "+err.Error())
+}
+
+// validationErrors lists the field errors of validator.Input
+func validationErrors(err error) []models.ErrorValidationResponse {
+	var errs []models.ErrorValidationResponse
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		for _, e := range merr.Errors {
+			var validationErr models.ErrorValidationResponse
+			if errors.As(e, &validationErr) {
+				errs = append(errs, validationErr)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs, models.ErrorValidationResponse{Code: validator.ErrorCodeInvalidField, Message: err.Error()})
+	}
+	return errs
+}
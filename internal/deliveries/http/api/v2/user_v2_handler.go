@@ -0,0 +1,47 @@
+package v2
+
+import (
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type userV2Handler struct {
+	service *service.Service
+}
+
+func NewUserV2(v2 *echo.Group, service *service.Service, jwtConfig *jwtc.Configuration) {
+	h := &userV2Handler{
+		service: service,
+	}
+
+	bearerRoute := v2.Group("/users")
+	bearerRoute.Use(middleware.BearerAuthMiddleware(jwtConfig))
+	bearerRoute.GET("/me", h.GetUserByAccessToken, middleware.ResponseCache(0))
+}
+
+// GetUserByAccessToken retrieves user information by access token
+// @Summary Get User By Access Token (v2)
+// @Description Get user information by access token. Unlike v1, contact details are grouped under contact and missing ones are null instead of empty strings. Responses carry an ETag; send it back in If-None-Match to get 304 when nothing changed.
+// @Tags Users
+// @Produce json
+// @Param If-None-Match header string false "ETag of the cached response"
+// @Success 200 {object} models.Response{data=models.UserV2Response} "User Information Retrieved Successfully"
+// @Success 304 "Not Modified"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "User Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /api/v2/users/me [get]
+// @Security BearerAuth
+func (h *userV2Handler) GetUserByAccessToken(ctx echo.Context) error {
+	user, err := h.service.User.GetByAccountNumber(ctx.Request().Context(), api.AccountNumber(ctx))
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessWithETag(ctx, user.UserV2Response())
+}
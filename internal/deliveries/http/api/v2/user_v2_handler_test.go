@@ -0,0 +1,78 @@
+package v2_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	v2 "go-echo-boilerplate/internal/deliveries/http/api/v2"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/testutil"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserService struct {
+	mock.Mock
+	service.UserService
+}
+
+func (m *MockUserService) GetByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
+	args := m.Called(ctx, accountNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+var testJWTConfig = &jwtc.Configuration{
+	AccessTokenSecret:    "test-secret-key",
+	AccessTokenDuration:  15 * time.Minute,
+	RefreshTokenSecret:   "test-secret-key",
+	RefreshTokenDuration: time.Hour,
+	Issuer:               "test-issuer",
+}
+
+func TestUserV2Handler_GetUserByAccessToken(t *testing.T) {
+	email := "john@example.com"
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John", Email: &email}
+
+	newClient := func(mockSvc *MockUserService) *testutil.APIClient {
+		e := echo.New()
+		v2.NewUserV2(e.Group("/v2"), &service.Service{User: mockSvc}, testJWTConfig)
+		return testutil.NewAPIClient(t, e, testJWTConfig)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("GetByAccountNumber", mock.Anything, user.AccountNumber).Return(user, nil)
+
+		res := newClient(mockSvc).AuthAs(user).Get("/v2/users/me")
+		require.True(t, res.AssertStatus(http.StatusOK))
+		assert.NotEmpty(t, res.Header().Get("ETag"))
+
+		var data map[string]any
+		res.DecodeData(&data)
+		assert.Equal(t, map[string]any{"email": email, "phoneNumber": nil}, data["contact"])
+		assert.NotContains(t, data, "email", "contact details are grouped in v2")
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		mockSvc.On("GetByAccountNumber", mock.Anything, user.AccountNumber).Return(nil, errorc.Error(errorc.ErrorUserNotFound))
+
+		newClient(mockSvc).AuthAs(user).Get("/v2/users/me").AssertError(errorc.ErrorUserNotFound)
+	})
+
+	t.Run("Without Token", func(t *testing.T) {
+		newClient(new(MockUserService)).Get("/v2/users/me").AssertStatus(http.StatusUnauthorized)
+	})
+}
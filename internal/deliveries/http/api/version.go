@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/di"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/service"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Dependencies are passed to every version and module when its routes are registered
+type Dependencies struct {
+	Service   *service.Service
+	Config    *config.Configuration
+	JWTConfig *jwtc.Configuration
+
+	// Container resolves the services registered by modules (see core.Module)
+	Container *di.Container
+}
+
+// Version is an API version mounted at /api/<Name>. Versions coexist: each registers its own
+// handlers on top of the same services, so a new version only has to cover what changed.
+//
+// To retire a version, set Deprecated (and Sunset once the removal date is known) and point
+// Successor at its replacement; clients then get the headers set by VersionMiddleware.
+type Version struct {
+	Name string // path segment, e.g. "v1"
+
+	// Register adds the routes of the version itself; optional, modules add theirs through Routes
+	Register func(group *echo.Group, deps Dependencies)
+
+	Deprecated time.Time // when the version was (or will be) deprecated; zero while supported
+	Sunset     time.Time // when the version will be removed; zero when not scheduled
+	Successor  string    // the version replacing a deprecated one, e.g. "v2"
+}
+
+// IsDeprecated reports whether a deprecation date is set
+func (v Version) IsDeprecated() bool {
+	return !v.Deprecated.IsZero()
+}
+
+// New mounts every version under group, lists them at GET <group>/versions, and returns
+// the mounted groups for the modules to add their routes to
+func New(group *echo.Group, deps Dependencies, versions ...Version) *Routes {
+	routes := &Routes{Dependencies: deps, versions: make(map[string]*echo.Group, len(versions))}
+	for _, version := range versions {
+		versionGroup := group.Group("/"+version.Name, VersionMiddleware(version))
+		routes.versions[version.Name] = versionGroup
+		if version.Register != nil {
+			version.Register(versionGroup, deps)
+		}
+	}
+
+	group.GET("/versions", listVersions(versions))
+	return routes
+}
+
+// Routes are the version groups mounted by New, passed to RouteRegistrar.RegisterRoutes
+type Routes struct {
+	Dependencies
+	versions map[string]*echo.Group
+}
+
+// Version returns the group mounted at /api/<name>, nil when the version is not mounted
+func (r *Routes) Version(name string) *echo.Group {
+	return r.versions[name]
+}
+
+// RouteRegistrar adds its endpoints to the mounted versions, see core.Module
+type RouteRegistrar interface {
+	RegisterRoutes(routes *Routes) error
+}
+
+// VersionMiddleware adds api_version to the wide event. For deprecated versions it also
+// adds api_deprecated and sets the Deprecation (RFC 9745), Sunset (RFC 8594), and
+// successor-version Link headers.
+func VersionMiddleware(version Version) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			reqCtx := ctx.Request().Context()
+			logger.Add(reqCtx, "api_version", version.Name)
+			if !version.IsDeprecated() {
+				return next(ctx)
+			}
+
+			logger.Add(reqCtx, "api_deprecated", true)
+			header := ctx.Response().Header()
+			header.Set("Deprecation", fmt.Sprintf("@%d", version.Deprecated.Unix()))
+			if !version.Sunset.IsZero() {
+				header.Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if version.Successor != "" {
+				successor := successorPath(ctx.Request().URL.Path, version.Name, version.Successor)
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// successorPath replaces the version segment of path with the successor and drops the rest,
+// e.g. /api/v1/users/me becomes /api/v2
+func successorPath(path, name, successor string) string {
+	if i := strings.Index(path+"/", "/"+name+"/"); i >= 0 {
+		return path[:i] + "/" + successor
+	}
+	return successor
+}
+
+// listVersions returns the registered versions and their deprecation schedule
+// @Summary List API Versions
+// @Description List the API versions with their status, deprecation and sunset dates, and successor
+// @Tags API
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.APIVersionResponse}
+// @Router /api/versions [get]
+func listVersions(versions []Version) echo.HandlerFunc {
+	data := make([]models.APIVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		item := models.APIVersionResponse{Name: version.Name, Status: "supported"}
+		if version.IsDeprecated() {
+			deprecated := version.Deprecated.UTC()
+			item.Status, item.Deprecated, item.Successor = "deprecated", &deprecated, version.Successor
+		}
+		if !version.Sunset.IsZero() {
+			sunset := version.Sunset.UTC()
+			item.Sunset = &sunset
+		}
+		data = append(data, item)
+	}
+
+	return func(ctx echo.Context) error {
+		return response.Success(ctx, http.StatusOK, data)
+	}
+}
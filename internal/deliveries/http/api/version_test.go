@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersions(t *testing.T) {
+	deprecated := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	register := func(group *echo.Group, deps api.Dependencies) {
+		group.GET("/users/me", func(ctx echo.Context) error { return ctx.NoContent(http.StatusNoContent) })
+	}
+
+	e := echo.New()
+	api.New(e.Group("/api"), api.Dependencies{},
+		api.Version{Name: "v1", Register: register, Deprecated: deprecated, Sunset: sunset, Successor: "v2"},
+		api.Version{Name: "v2", Register: register},
+	)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	t.Run("Deprecated", func(t *testing.T) {
+		rec := get("/api/v1/users/me")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "@1780272000", rec.Header().Get("Deprecation"))
+		assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v2>; rel="successor-version"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("Supported", func(t *testing.T) {
+		rec := get("/api/v2/users/me")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Header().Get("Deprecation"))
+		assert.Empty(t, rec.Header().Get("Sunset"))
+		assert.Empty(t, rec.Header().Get("Link"))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		rec := get("/api/versions")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body struct {
+			Data []models.APIVersionResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "deprecated", body.Data[0].Status)
+		assert.Equal(t, deprecated, *body.Data[0].Deprecated)
+		assert.Equal(t, sunset, *body.Data[0].Sunset)
+		assert.Equal(t, "v2", body.Data[0].Successor)
+		assert.Equal(t, models.APIVersionResponse{Name: "v2", Status: "supported"}, body.Data[1])
+	})
+}
@@ -0,0 +1,180 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	handler "go-echo-boilerplate/internal/deliveries/http"
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/models"
+	usersmodule "go-echo-boilerplate/internal/modules/users"
+	"go-echo-boilerplate/internal/pkg/contract"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserService struct {
+	mock.Mock
+	service.UserService
+}
+
+func (m *mockUserService) Create(ctx context.Context, request *models.CreateUserRequest) (*models.User, error) {
+	args := m.Called(ctx, request)
+	user, _ := args.Get(0).(*models.User)
+	return user, args.Error(1)
+}
+
+func (m *mockUserService) GetTokens(ctx context.Context, request *models.GetUserTokenRequest) (*models.GetUserTokenResponse, error) {
+	args := m.Called(ctx, request)
+	tokens, _ := args.Get(0).(*models.GetUserTokenResponse)
+	return tokens, args.Error(1)
+}
+
+func (m *mockUserService) GetByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
+	args := m.Called(ctx, accountNumber)
+	user, _ := args.Get(0).(*models.User)
+	return user, args.Error(1)
+}
+
+func (m *mockUserService) List(ctx context.Context, request *models.ListUserRequest) ([]models.User, int, error) {
+	args := m.Called(ctx, request)
+	users, _ := args.Get(0).([]models.User)
+	return users, args.Int(1), args.Error(2)
+}
+
+// HasAcceptedTerms accepts every account, like the service without consent.terms_version
+func (m *mockUserService) HasAcceptedTerms(ctx context.Context, accountNumber string) (bool, error) {
+	return true, nil
+}
+
+type mockHealthService struct {
+	mock.Mock
+}
+
+func (m *mockHealthService) Check(ctx context.Context) (*models.HealthResponse, error) {
+	args := m.Called(ctx)
+	health, _ := args.Get(0).(*models.HealthResponse)
+	return health, args.Error(1)
+}
+
+// TestContract serves requests through the real router and validates every response
+// against the generated OpenAPI spec. A failure means a handler and its annotations
+// disagree: fix the one that is wrong and run make docs.
+func TestContract(t *testing.T) {
+	validator, err := contract.New()
+	require.NoError(t, err)
+
+	email, phone := "john@example.com", "6281234567890"
+	createdAt := time.Date(2026, 1, 24, 15, 57, 37, 0, time.UTC)
+	user := &models.User{ID: 1, AccountNumber: "1234567890", Name: "John Doe", Email: &email, PhoneNumber: &phone, PhoneCountryCode: "ID", CreatedAt: createdAt, UpdatedAt: createdAt}
+	withoutPhone := &models.User{ID: 2, AccountNumber: "1234567891", Name: "Jane Doe", Email: &email, CreatedAt: createdAt, UpdatedAt: createdAt}
+	missing := &models.User{ID: 3, AccountNumber: "1234567892"}
+
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-issuer",
+	}
+	token, err := generator.AccessToken(user, jwtConfig)
+	require.NoError(t, err)
+	withoutPhoneToken, err := generator.AccessToken(withoutPhone, jwtConfig)
+	require.NoError(t, err)
+	missingToken, err := generator.AccessToken(missing, jwtConfig)
+	require.NoError(t, err)
+
+	users := new(mockUserService)
+	users.On("Create", mock.Anything, mock.MatchedBy(func(r *models.CreateUserRequest) bool { return r.Email == email })).Return(user, nil)
+	users.On("Create", mock.Anything, mock.Anything).Return(nil, errorc.Error(errorc.ErrorAlreadyExist))
+	users.On("GetTokens", mock.Anything, mock.Anything).Return(&models.GetUserTokenResponse{
+		Email:  email,
+		Tokens: []models.Token{{Type: "access", Token: "access", ExpiredIn: 900}, {Type: "refresh", Token: "refresh", ExpiredIn: 3600}},
+	}, nil)
+	users.On("GetByAccountNumber", mock.Anything, user.AccountNumber).Return(user, nil)
+	users.On("GetByAccountNumber", mock.Anything, withoutPhone.AccountNumber).Return(withoutPhone, nil)
+	users.On("GetByAccountNumber", mock.Anything, missing.AccountNumber).Return(nil, errorc.Error(errorc.ErrorUserNotFound))
+	users.On("List", mock.Anything, mock.Anything).Return([]models.User{*user, *withoutPhone}, 45, nil)
+
+	health := new(mockHealthService)
+	health.On("Check", mock.Anything).Return(&models.HealthResponse{
+		Description:  "Service is healthy",
+		Dependencies: []models.HealthDetailResponse{{Type: "database", Component: "postgresql", Status: "UP"}},
+	}, nil)
+
+	configuration := &config.Configuration{
+		Application:   config.Application{Environment: "local"},
+		Authorization: config.Authorization{APIKey: "api-key", AdminAPIKey: "admin-key"},
+	}
+	e := echo.New()
+	deps := api.Dependencies{
+		Service:   &service.Service{User: users, Health: health},
+		Config:    configuration,
+		JWTConfig: jwtConfig,
+	}
+	require.NoError(t, handler.New(e, deps, usersmodule.Module{}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		header map[string]string
+		status int
+	}{
+		{"Health", http.MethodGet, "/health", "", nil, http.StatusOK},
+		{"API Versions", http.MethodGet, "/api/versions", "", nil, http.StatusOK},
+		{"Create User", http.MethodPost, "/api/v1/users", `{"name":"John Doe","email":"john@example.com","password":"password123"}`, nil, http.StatusCreated},
+		{"Create User Validation Error", http.MethodPost, "/api/v1/users", `{}`, nil, http.StatusBadRequest},
+		{"Create User Conflict", http.MethodPost, "/api/v1/users", `{"name":"Jane Doe","email":"jane@example.com","password":"password123"}`, nil, http.StatusConflict},
+		{"Get Tokens", http.MethodPost, "/api/v1/users/tokens", `{"email":"john@example.com","password":"password123"}`, nil, http.StatusOK},
+		{"List Users", http.MethodGet, "/api/v1/admin/users?page=2&limit=10", "", nil, http.StatusOK},
+		{"List Users Validation Error", http.MethodGet, "/api/v1/admin/users?limit=1000", "", nil, http.StatusBadRequest},
+		{"List Users Unauthorized", http.MethodGet, "/api/v1/admin/users", "", map[string]string{"X-Admin-Key": "wrong", "Authorization": "Bearer " + token.Token}, http.StatusUnauthorized},
+		{"Get Me", http.MethodGet, "/api/v1/users/me", "", map[string]string{"Authorization": "Bearer " + token.Token}, http.StatusOK},
+		{"Get Me Not Modified", http.MethodGet, "/api/v1/users/me", "", map[string]string{"Authorization": "Bearer " + token.Token, "If-None-Match": "*"}, http.StatusNotModified},
+		{"Get Me Without Phone", http.MethodGet, "/api/v1/users/me", "", map[string]string{"Authorization": "Bearer " + withoutPhoneToken.Token}, http.StatusOK},
+		{"Get Me Not Found", http.MethodGet, "/api/v1/users/me", "", map[string]string{"Authorization": "Bearer " + missingToken.Token}, http.StatusNotFound},
+		{"Get Me V2", http.MethodGet, "/api/v2/users/me", "", map[string]string{"Authorization": "Bearer " + token.Token}, http.StatusOK},
+		{"Get Me V2 Without Phone", http.MethodGet, "/api/v2/users/me", "", map[string]string{"Authorization": "Bearer " + withoutPhoneToken.Token}, http.StatusOK},
+		{"Get Me V2 Unauthorized", http.MethodGet, "/api/v2/users/me", "", map[string]string{"Authorization": "Bearer invalid"}, http.StatusUnauthorized},
+		{"Get Log Level", http.MethodGet, "/admin/loglevel", "", nil, http.StatusOK},
+		{"Update Log Level Validation Error", http.MethodPut, "/admin/loglevel", `{"level":"verbose"}`, nil, http.StatusBadRequest},
+		{"Get Log Stats", http.MethodGet, "/admin/logstats", "", nil, http.StatusOK},
+		{"Get Log Stats Unauthorized", http.MethodGet, "/admin/logstats", "", map[string]string{"X-Admin-Key": "wrong"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, body)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("X-API-Key", "api-key")
+			req.Header.Set("X-Admin-Key", "admin-key")
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.NoError(t, validator.ValidateRecorder(req, rec))
+		})
+	}
+}
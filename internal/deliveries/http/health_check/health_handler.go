@@ -23,7 +23,7 @@ func New(api *echo.Group, service *service.Service) {
 
 // Check godoc
 // @Summary Check health status
-// @Description Check the health status of the service and its dependencies
+// @Description Check the health status of the service and its dependencies. PostgreSQL reports its connection pool and, on a primary with streaming replicas, their replay lag; it is WARN past the health.* thresholds. Leader tells whether the instance runs the singleton background jobs.
 // @Tags Health
 // @Accept json
 // @Produce json
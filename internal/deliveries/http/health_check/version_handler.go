@@ -0,0 +1,42 @@
+package healthcheck
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/response"
+	"net/http"
+	"runtime"
+
+	"github.com/labstack/echo/v4"
+)
+
+type versionHandler struct {
+	build models.BuildResponse
+}
+
+// Version serves the build of the binary, stamped on application by config.Initialize
+func Version(group *echo.Group, application config.Application) {
+	vh := versionHandler{
+		build: models.BuildResponse{
+			Version:   application.Version,
+			Commit:    application.Commit,
+			BuildTime: application.BuildTime,
+			GoVersion: application.GoVersion,
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		},
+	}
+
+	group.GET("", vh.Get)
+}
+
+// Get godoc
+// @Summary Get the build version
+// @Description Get the version, git commit, and build time of the running binary, injected at build time (see make build-prod), and its Go runtime. Without an injected version, it is application.version; without an injected commit and build time, the VCS stamp of go build.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.Response{data=models.BuildResponse}
+// @Router /version [get]
+func (h *versionHandler) Get(ctx echo.Context) error {
+	return response.Success(ctx, http.StatusOK, h.build)
+}
@@ -0,0 +1,43 @@
+package healthcheck_test
+
+import (
+	"encoding/json"
+	"go-echo-boilerplate/internal/config"
+	healthcheck "go-echo-boilerplate/internal/deliveries/http/health_check"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHandler_Get(t *testing.T) {
+	e := echo.New()
+	healthcheck.Version(e.Group("/version"), config.Application{
+		Version:   "v1.2.0",
+		Commit:    "abc123",
+		BuildTime: "2026-01-01T00:00:00Z",
+		GoVersion: "go1.24.4",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Data map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]string{
+		"version":   "v1.2.0",
+		"commit":    "abc123",
+		"buildTime": "2026-01-01T00:00:00Z",
+		"goVersion": "go1.24.4",
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+	}, resp.Data)
+}
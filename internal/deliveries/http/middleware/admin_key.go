@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/actor"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminKeyMiddleware guards admin endpoints with the X-Admin-Key header, performing the
+// requests as actor.Admin. Admin endpoints answer 404 when authorization.admin_api_key is not
+// configured.
+func (m *Middleware) AdminKeyMiddleware(config *config.Configuration) echo.MiddlewareFunc {
+	return AdminKey(config)
+}
+
+// AdminKey is AdminKeyMiddleware for route groups outside the router, e.g. the admin
+// endpoints of an API version. A nil config disables the endpoints like an unset key.
+func AdminKey(config *config.Configuration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if config == nil || config.Authorization.AdminAPIKey == "" {
+				return response.Error(ctx, errorc.ErrorDataNotFound)
+			}
+			expected := config.Authorization.AdminAPIKey
+
+			adminKey := ctx.Request().Header.Get("X-Admin-Key")
+			if subtle.ConstantTimeCompare([]byte(adminKey), []byte(expected)) != 1 {
+				return response.Error(ctx, errorc.ErrorUnauthorized)
+			}
+
+			req := ctx.Request()
+			ctx.SetRequest(req.WithContext(actor.WithActor(req.Context(), actor.Admin)))
+			return next(ctx)
+		}
+	}
+}
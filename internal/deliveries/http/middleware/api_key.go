@@ -3,11 +3,21 @@ package middleware
 import (
 	"go-echo-boilerplate/internal/config"
 	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/quota"
 	"go-echo-boilerplate/internal/pkg/response"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// ApiKeyMiddleware accepts the X-API-Key of authorization.api_key, or of one of
+// authorization.clients. A client's requests are counted against its quotas (quota.Default())
+// and its responses carry the X-RateLimit-Limit, X-RateLimit-Remaining, and X-RateLimit-Reset
+// (seconds until it resets) of its tightest quota. Once one is exhausted, requests are refused
+// with 429 and Retry-After until it resets.
 func (m *Middleware) ApiKeyMiddleware(config *config.Configuration) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
@@ -16,11 +26,56 @@ func (m *Middleware) ApiKeyMiddleware(config *config.Configuration) echo.Middlew
 				return response.Error(ctx, errorc.ErrorUnauthorized)
 			}
 
-			if apiKey != config.Authorization.APIKey {
+			if apiKey == config.Authorization.APIKey {
+				return next(ctx)
+			}
+
+			quotas := quota.Default()
+			if quotas == nil {
+				return response.Error(ctx, errorc.ErrorUnauthorized)
+			}
+			client, ok := quotas.Client(apiKey)
+			if !ok {
 				return response.Error(ctx, errorc.ErrorUnauthorized)
 			}
 
+			requestCtx := ctx.Request().Context()
+			ctx.Set("apiClient", client)
+			logger.Add(requestCtx, "api_client", client)
+
+			usages, ok := quotas.Consume(requestCtx, client)
+			if len(usages) == 0 {
+				return next(ctx)
+			}
+			tightest := tightestQuota(usages)
+			header := ctx.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.FormatInt(tightest.Limit, 10))
+			header.Set("X-RateLimit-Remaining", strconv.FormatInt(tightest.Remaining, 10))
+			reset := strconv.Itoa(max(int(math.Ceil(time.Until(tightest.Reset).Seconds())), 1))
+			header.Set("X-RateLimit-Reset", reset)
+			if !ok {
+				logger.AddMap(requestCtx, map[string]any{
+					"rejection_reason": "quota_exceeded",
+					"quota_period":     tightest.Period,
+				})
+				header.Set(echo.HeaderRetryAfter, reset)
+				return response.Error(ctx, errorc.ErrorQuotaExceeded)
+			}
+
 			return next(ctx)
 		}
 	}
 }
+
+// tightestQuota is the quota with the fewest requests remaining, the one resetting last
+// among those exhausted
+func tightestQuota(usages []quota.Usage) quota.Usage {
+	tightest := usages[0]
+	for _, usage := range usages[1:] {
+		if usage.Remaining < tightest.Remaining ||
+			usage.Remaining == tightest.Remaining && usage.Reset.After(tightest.Reset) {
+			tightest = usage
+		}
+	}
+	return tightest
+}
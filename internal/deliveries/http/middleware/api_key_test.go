@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyMiddleware(t *testing.T) {
+	t.Cleanup(func() { quota.SetDefault(nil) })
+
+	authorization := config.Authorization{
+		APIKey: "shared-key",
+		Clients: []config.APIClient{
+			{Name: "partner-a", Key: "partner-a-key", DailyQuota: 2, MonthlyQuota: 100},
+			{Name: "partner-b", Key: "partner-b-key"},
+		},
+	}
+	quotas, err := quota.FromConfig(authorization, memory.NewAPIUsageRepository())
+	require.NoError(t, err)
+	quota.SetDefault(quotas)
+
+	e := echo.New()
+	configuration := &config.Configuration{Authorization: authorization}
+	m := middleware.New(e, configuration)
+	e.Use(m.ApiKeyMiddleware(configuration))
+	e.GET("/api/quota", api.Quota)
+	serve := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/quota", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("unknown-key").Code)
+
+	rec := serve("shared-key")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"), "the shared key has no quota")
+
+	rec = serve("partner-a-key")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"), "the tightest quota")
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, rec.Header().Get("X-RateLimit-Reset"))
+
+	var body struct {
+		Data models.QuotaResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "partner-a", body.Data.Client)
+	if assert.Len(t, body.Data.Quotas, 2) {
+		assert.Equal(t, quota.Daily, body.Data.Quotas[0].Period)
+		assert.Equal(t, int64(1), body.Data.Quotas[0].Used, "this request included")
+		assert.Equal(t, int64(99), body.Data.Quotas[1].Remaining)
+	}
+
+	assert.Equal(t, http.StatusOK, serve("partner-a-key").Code)
+	rec = serve("partner-a-key")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"QUOTA_EXCEEDED"`)
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, rec.Header().Get("X-RateLimit-Reset"), rec.Header().Get(echo.HeaderRetryAfter))
+
+	rec = serve("partner-b-key")
+	assert.Equal(t, http.StatusOK, rec.Code, "no quota")
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+	assert.Contains(t, rec.Body.String(), `"client":"partner-b"`)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/captcha"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CaptchaMiddleware requires an answered CAPTCHA on the routes of captcha.routes, matched by
+// method and registered path (e.g. "POST /api/v1/users"), so bot protection is turned on
+// per route from the configuration. The answer is read from the X-Captcha-Token header and
+// checked with captcha.Default(); missing or invalid answers are rejected with 428.
+//
+// Verifier errors (the provider is unreachable) let the request through, like LoginThrottle.
+// Other routes, and every route when no verifier is installed, pass through.
+func (m *Middleware) CaptchaMiddleware(config *config.Configuration) echo.MiddlewareFunc {
+	routes := make(map[string]struct{}, len(config.Captcha.Routes))
+	for _, route := range config.Captcha.Routes {
+		routes[route] = struct{}{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			verifier := captcha.Default()
+			req := ctx.Request()
+			if verifier == nil {
+				return next(ctx)
+			}
+			if _, ok := routes[req.Method+" "+ctx.Path()]; !ok {
+				return next(ctx)
+			}
+
+			requestCtx := req.Context()
+			passed, err := verifier.Verify(requestCtx, req.Header.Get(HeaderCaptchaToken), ctx.RealIP())
+			if err != nil {
+				logger.FromContext(requestCtx).Warn(requestCtx, "captcha verification unavailable", logger.Error(err))
+				return next(ctx)
+			}
+			logger.Add(requestCtx, "captcha_passed", passed)
+			if !passed {
+				return response.Error(ctx, errorc.ErrorChallengeRequired)
+			}
+			return next(ctx)
+		}
+	}
+}
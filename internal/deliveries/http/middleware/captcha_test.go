@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/captcha"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptchaMiddleware(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("response") {
+		case "solved":
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case "outage":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+		}
+	}))
+	t.Cleanup(provider.Close)
+
+	setup := func(t *testing.T, verifier *captcha.SiteVerifier) *echo.Echo {
+		captcha.SetDefault(verifier)
+		t.Cleanup(func() { captcha.SetDefault(nil) })
+
+		e := echo.New()
+		m := middleware.New(e, &config.Configuration{})
+		e.Use(m.CaptchaMiddleware(&config.Configuration{Captcha: config.Captcha{
+			Provider: "hcaptcha",
+			Routes:   []string{"POST /api/v1/users"},
+		}}))
+		ok := func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }
+		e.POST("/api/v1/users", ok)
+		e.POST("/api/v1/users/tokens", ok)
+		e.GET("/api/v1/users", ok)
+		return e
+	}
+
+	serve := func(e *echo.Echo, method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set(middleware.HeaderCaptchaToken, token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Requires An Answer On Listed Routes", func(t *testing.T) {
+		e := setup(t, captcha.NewHCaptcha(httpclient.New(nil), "test-secret").WithURL(provider.URL))
+
+		rec := serve(e, http.MethodPost, "/api/v1/users", "")
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+		assert.Contains(t, rec.Body.String(), "CHALLENGE_REQUIRED")
+
+		assert.Equal(t, http.StatusPreconditionRequired, serve(e, http.MethodPost, "/api/v1/users", "wrong").Code)
+		assert.Equal(t, http.StatusOK, serve(e, http.MethodPost, "/api/v1/users", "solved").Code)
+	})
+
+	t.Run("Passes Other Routes", func(t *testing.T) {
+		e := setup(t, captcha.NewHCaptcha(httpclient.New(nil), "test-secret").WithURL(provider.URL))
+
+		assert.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/api/v1/users", "").Code)
+		assert.Equal(t, http.StatusOK, serve(e, http.MethodPost, "/api/v1/users/tokens", "").Code)
+	})
+
+	t.Run("Lets Requests Through When The Provider Is Down", func(t *testing.T) {
+		e := setup(t, captcha.NewHCaptcha(httpclient.New(nil), "test-secret").WithURL(provider.URL))
+
+		assert.Equal(t, http.StatusOK, serve(e, http.MethodPost, "/api/v1/users", "outage").Code)
+	})
+
+	t.Run("Passes Through Without A Verifier", func(t *testing.T) {
+		e := setup(t, nil)
+
+		assert.Equal(t, http.StatusOK, serve(e, http.MethodPost, "/api/v1/users", "").Code)
+	})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/pkg/clientinfo"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ClientInfoMiddleware resolves the location and device of the client with
+// clientinfo.Default(), following client_info. The result is added to the wide event under
+// "client" and stored in the request context, where login audit records pick it up.
+// It passes requests through when no resolver is installed.
+func (m *Middleware) ClientInfoMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			resolver := clientinfo.Default()
+			if resolver == nil {
+				return next(ctx)
+			}
+
+			info := resolver.Resolve(ctx.RealIP(), ctx.Request().UserAgent())
+			requestCtx := clientinfo.WithInfo(ctx.Request().Context(), info)
+			logger.SetExtension(requestCtx, info)
+			ctx.SetRequest(ctx.Request().WithContext(requestCtx))
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/clientinfo"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientInfoMiddleware(t *testing.T) {
+	setup := func(t *testing.T, resolver *clientinfo.Resolver) (*echo.Echo, *logger.TestLogger, *clientinfo.Info) {
+		clientinfo.SetDefault(resolver)
+		t.Cleanup(func() { clientinfo.SetDefault(nil) })
+
+		log := logger.NewTestLogger()
+		e := echo.New()
+		m := middleware.New(e, &config.Configuration{})
+		e.Use(m.LoggingMiddleware(log), m.ClientInfoMiddleware())
+
+		var seen clientinfo.Info
+		e.GET("/", func(ctx echo.Context) error {
+			seen, _ = clientinfo.FromContext(ctx.Request().Context())
+			return ctx.NoContent(http.StatusOK)
+		})
+		return e, log, &seen
+	}
+
+	serve := func(e *echo.Echo) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36")
+		req.RemoteAddr = "81.2.69.142:51234"
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("Enriches The Wide Event And Context", func(t *testing.T) {
+		e, log, seen := setup(t, clientinfo.New(nil, true))
+		serve(e)
+
+		assert.Equal(t, "81.2.69.142", seen.IP)
+		assert.Equal(t, "desktop", seen.Device)
+
+		events := log.EventsWithMessage("Request completed")
+		require.Len(t, events, 1)
+		client, ok := events[0].Fields["client"].(clientinfo.Info)
+		require.True(t, ok)
+		assert.Equal(t, "Chrome", client.Browser)
+		assert.Equal(t, "Windows", client.OS)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		e, log, seen := setup(t, nil)
+		serve(e)
+
+		assert.Equal(t, clientinfo.Info{}, *seen)
+		events := log.EventsWithMessage("Request completed")
+		require.Len(t, events, 1)
+		assert.NotContains(t, events[0].Fields, "client")
+	})
+}
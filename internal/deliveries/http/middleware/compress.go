@@ -0,0 +1,301 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"go-echo-boilerplate/internal/config"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+	gbytes "github.com/labstack/gommon/bytes"
+)
+
+// Defaults for the server.compression.* settings left unset
+const (
+	DefaultCompressionMinSize     = 1024
+	DefaultCompressionLevel       = gzip.DefaultCompression
+	DefaultCompressionBrotliLevel = 4
+)
+
+// DefaultCompressionContentTypes are compressed when server.compression.content_types is empty
+var DefaultCompressionContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// CompressMiddleware compresses responses with gzip, or Brotli when server.compression.brotli
+// is on and the client accepts it. Responses smaller than min_size, of other content types,
+// or already encoded are sent as is.
+//
+// It must run inside LoggingMiddleware: the wide event then logs the compressed size as
+// bytes_out, the uncompressed one as response_size, and captures the uncompressed body.
+func (m *Middleware) CompressMiddleware(config *config.Configuration) echo.MiddlewareFunc {
+	c := newCompressor(config.Server.Compression)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if req.Header.Get(echo.HeaderUpgrade) != "" || req.Header.Get("Range") != "" {
+				return next(ctx)
+			}
+			encoding := c.negotiate(req.Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == "" {
+				return next(ctx)
+			}
+
+			res := ctx.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			original := res.Writer
+			cw := &compressWriter{ResponseWriter: original, compressor: c, encoding: encoding, status: http.StatusOK}
+			cw.capture, _ = original.(*bodyCapturingWriter)
+			res.Writer = cw
+			defer func() {
+				cw.close()
+				res.Writer = original
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+type compressor struct {
+	minSize      int
+	contentTypes []string
+	brotli       bool
+	gzipPool     sync.Pool
+	brotliPool   sync.Pool
+}
+
+func newCompressor(cfg config.Compression) *compressor {
+	c := &compressor{
+		minSize:      DefaultCompressionMinSize,
+		contentTypes: DefaultCompressionContentTypes,
+		brotli:       cfg.Brotli,
+	}
+	// Validated at startup
+	if size, err := gbytes.Parse(cfg.MinSize); cfg.MinSize != "" && err == nil {
+		c.minSize = int(size)
+	}
+	if len(cfg.ContentTypes) > 0 {
+		c.contentTypes = make([]string, len(cfg.ContentTypes))
+		for i, contentType := range cfg.ContentTypes {
+			c.contentTypes[i] = strings.ToLower(contentType)
+		}
+	}
+
+	level := cfg.Level
+	if level == 0 {
+		level = DefaultCompressionLevel
+	}
+	brotliLevel := cfg.BrotliLevel
+	if brotliLevel == 0 {
+		brotliLevel = DefaultCompressionBrotliLevel
+	}
+	c.gzipPool.New = func() any {
+		w, err := gzip.NewWriterLevel(io.Discard, level)
+		if err != nil {
+			w = gzip.NewWriter(io.Discard)
+		}
+		return w
+	}
+	c.brotliPool.New = func() any {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}
+	return c
+}
+
+// negotiate picks br or gzip from Accept-Encoding, "" when neither is accepted
+func (c *compressor) negotiate(acceptEncoding string) string {
+	var gzipOK, brotliOK bool
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				continue
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "br":
+			brotliOK = true
+		case "gzip", "*":
+			gzipOK = true
+		}
+	}
+
+	switch {
+	case brotliOK && c.brotli:
+		return "br"
+	case gzipOK:
+		return "gzip"
+	default:
+		return ""
+	}
+}
+
+// compressible reports whether the media type of contentType is in the configured list
+func (c *compressor) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range c.contentTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter holds back the status and the first min_size bytes, then either starts
+// compressing or passes everything through unchanged.
+type compressWriter struct {
+	http.ResponseWriter
+	compressor *compressor
+	encoding   string
+	capture    *bodyCapturingWriter // nil without LoggingMiddleware
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	enc         io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	if !w.decided {
+		w.buf.Write(b)
+		if w.buf.Len() < w.compressor.minSize {
+			return len(b), nil
+		}
+		if err := w.decide(false); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if w.enc != nil {
+		if w.capture != nil {
+			w.capture.capture(b)
+		}
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decide writes the held back status and bytes, compressed or not. force ignores min_size.
+func (w *compressWriter) decide(force bool) error {
+	w.decided = true
+
+	header := w.Header()
+	if header.Get(echo.HeaderContentType) == "" && w.buf.Len() > 0 {
+		header.Set(echo.HeaderContentType, http.DetectContentType(w.buf.Bytes()))
+	}
+
+	compress := (force || w.buf.Len() >= w.compressor.minSize) &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified && w.status >= http.StatusOK &&
+		header.Get(echo.HeaderContentEncoding) == "" &&
+		w.compressor.compressible(header.Get(echo.HeaderContentType))
+	if !compress {
+		w.ResponseWriter.WriteHeader(w.status)
+		if w.buf.Len() == 0 {
+			return nil
+		}
+		_, err := w.ResponseWriter.Write(w.buf.Bytes())
+		return err
+	}
+
+	header.Set(echo.HeaderContentEncoding, w.encoding)
+	header.Del(echo.HeaderContentLength)
+	if w.capture != nil {
+		w.capture.capture(w.buf.Bytes())
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	switch w.encoding {
+	case "br":
+		bw := w.compressor.brotliPool.Get().(*brotli.Writer)
+		bw.Reset(w.ResponseWriter)
+		w.enc = bw
+	default:
+		gw := w.compressor.gzipPool.Get().(*gzip.Writer)
+		gw.Reset(w.ResponseWriter)
+		w.enc = gw
+	}
+	_, err := w.enc.Write(w.buf.Bytes())
+	return err
+}
+
+// Flush sends what has been written so far, e.g. for server-sent events. A response flushed
+// before min_size is reached is compressed if its content type allows.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close writes what is still held back and finishes the compressed stream
+func (w *compressWriter) close() {
+	if !w.decided {
+		if !w.wroteHeader {
+			return // nothing was written, e.g. the handler hijacked the connection
+		}
+		_ = w.decide(false)
+	}
+	if w.enc == nil {
+		return
+	}
+	_ = w.enc.Close()
+	switch enc := w.enc.(type) {
+	case *brotli.Writer:
+		enc.Reset(io.Discard)
+		w.compressor.brotliPool.Put(enc)
+	case *gzip.Writer:
+		enc.Reset(io.Discard)
+		w.compressor.gzipPool.Put(enc)
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("compress: underlying writer does not support hijacking")
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
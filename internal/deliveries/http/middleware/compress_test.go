@@ -0,0 +1,133 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressMiddleware(t *testing.T) {
+	payload := strings.Repeat(`{"name":"user"},`, 200)
+
+	setup := func(cfg config.Compression) (*echo.Echo, *logger.TestLogger) {
+		cfg.Enabled = true
+		log := logger.NewTestLogger()
+		e := echo.New()
+		configuration := &config.Configuration{Server: config.Server{Compression: cfg}}
+		m := middleware.New(e, configuration)
+		e.Use(m.LoggingMiddleware(log))
+		e.Use(m.CompressMiddleware(configuration))
+		e.GET("/json", func(ctx echo.Context) error {
+			return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(payload))
+		})
+		e.GET("/small", func(ctx echo.Context) error { return ctx.JSON(http.StatusOK, map[string]string{"a": "b"}) })
+		e.GET("/png", func(ctx echo.Context) error {
+			return ctx.Blob(http.StatusOK, "image/png", []byte(payload))
+		})
+		e.GET("/empty", func(ctx echo.Context) error { return ctx.NoContent(http.StatusNoContent) })
+		return e, log
+	}
+	get := func(e *echo.Echo, path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Gzip", func(t *testing.T) {
+		e, logs := setup(config.Compression{})
+		rec := get(e, "/json", "gzip, deflate")
+
+		require.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, echo.HeaderAcceptEncoding, rec.Header().Get(echo.HeaderVary))
+		gr, err := gzip.NewReader(strings.NewReader(rec.Body.String()))
+		require.NoError(t, err)
+		body, err := io.ReadAll(gr)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(body))
+
+		events := logs.EventsWithMessage("Request completed")
+		require.Len(t, events, 1)
+		fields := events[0].Fields
+		assert.EqualValues(t, rec.Body.Len(), fields["bytes_out"], "bytes_out is the compressed size")
+		assert.EqualValues(t, len(payload), fields["response_size"])
+		assert.Equal(t, "gzip", fields["response_encoding"])
+		assert.Equal(t, payload, fields["response_body"].(map[string]any)["raw"], "the uncompressed body is captured")
+	})
+
+	t.Run("Brotli", func(t *testing.T) {
+		e, _ := setup(config.Compression{Brotli: true})
+		rec := get(e, "/json", "gzip;q=0.8, br")
+
+		require.Equal(t, "br", rec.Header().Get(echo.HeaderContentEncoding))
+		body, err := io.ReadAll(brotli.NewReader(rec.Body))
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(body))
+
+		rec = get(e, "/json", "gzip, br;q=0")
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+	})
+
+	t.Run("Sent As Is", func(t *testing.T) {
+		e, _ := setup(config.Compression{})
+
+		tests := []struct {
+			name, path, acceptEncoding string
+		}{
+			{"Not Accepted", "/json", ""},
+			{"Identity Only", "/json", "identity"},
+			{"Below Min Size", "/small", "gzip"},
+			{"Other Content Type", "/png", "gzip"},
+			{"No Content", "/empty", "gzip"},
+		}
+		for _, tt := range tests {
+			rec := get(e, tt.path, tt.acceptEncoding)
+			assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding), tt.name)
+		}
+		assert.Equal(t, payload, get(e, "/png", "gzip").Body.String())
+	})
+}
+
+func TestMiddlewareWriters_ExtendWriteDeadline(t *testing.T) {
+	e := echo.New()
+	configuration := &config.Configuration{Server: config.Server{Compression: config.Compression{Enabled: true}}}
+	m := middleware.New(e, configuration)
+	e.Use(m.LoggingMiddleware(logger.NewTestLogger()))
+	e.Use(m.CompressMiddleware(configuration))
+	e.GET("/slow", func(ctx echo.Context) error {
+		if err := response.ExtendWriteDeadline(ctx, time.Second); err != nil {
+			return err
+		}
+		time.Sleep(150 * time.Millisecond)
+		return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(strings.Repeat(`{"name":"user"},`, 200)))
+	}, middleware.ResponseCache(0))
+
+	server := httptest.NewUnstartedServer(e)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	t.Cleanup(server.Close)
+
+	res, err := server.Client().Get(server.URL + "/slow")
+	require.NoError(t, err, "the deadline reaches the connection through the writers of the middleware")
+	defer res.Body.Close()
+	_, err = io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/limiter"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"math"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ConcurrencyMiddleware caps the requests served at once with limiter.Default(), following
+// concurrency: requests past a cap wait for a slot, and are shed with 503 and Retry-After
+// when the queue is full or they waited concurrency.queue_timeout. The wait is added to the
+// wide event as queue_wait_ms. /health, /version, /admin, and /debug are never limited.
+//
+// It is a no-op when no limits are installed.
+func (m *Middleware) ConcurrencyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			limits := limiter.Default()
+			req := ctx.Request()
+			if limits == nil || operationalRoute(req.URL.Path) {
+				return next(ctx)
+			}
+
+			requestCtx := req.Context()
+			release, waited, limit, err := limits.Acquire(requestCtx, req.Method+" "+ctx.Path())
+			if waited > 0 {
+				logger.Add(requestCtx, "queue_wait_ms", waited.Milliseconds())
+			}
+			if err != nil {
+				reason := "overloaded"
+				if !errors.Is(err, limiter.ErrSaturated) {
+					reason = "canceled" // the client went away while queued
+				}
+				logger.AddMap(requestCtx, map[string]any{
+					"rejection_reason":  reason,
+					"concurrency_limit": limit,
+				})
+				seconds := int(math.Ceil(limits.RetryAfter().Seconds()))
+				ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+				return response.Error(ctx, errorc.ErrorOverloaded)
+			}
+			defer release()
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/limiter"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyMiddleware(t *testing.T) {
+	t.Cleanup(func() { limiter.SetDefault(nil) })
+
+	limits, err := limiter.FromConfig(config.Concurrency{
+		MaxInFlight:  10,
+		QueueTimeout: "1ms",
+		RetryAfter:   "1500ms",
+		Routes:       []config.ConcurrencyRoute{{Route: "GET /api/v1/export", MaxInFlight: 1, MaxQueue: 1}},
+	})
+	require.NoError(t, err)
+	limiter.SetDefault(limits)
+
+	e := echo.New()
+	m := middleware.New(e, &config.Configuration{})
+	e.Use(m.ConcurrencyMiddleware())
+	for _, path := range []string{"/api/v1/export", "/api/v1/users", "/health"} {
+		e.GET(path, func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusOK)
+		})
+	}
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/api/v1/export").Code)
+
+	// Hold the only slot of the export route
+	release, _, _, err := limits.Acquire(t.Context(), "GET /api/v1/export")
+	require.NoError(t, err)
+
+	rec := serve("/api/v1/export")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(echo.HeaderRetryAfter), "rounded up")
+	assert.Contains(t, rec.Body.String(), `"OVERLOADED"`)
+
+	assert.Equal(t, http.StatusOK, serve("/api/v1/users").Code, "other routes have slots left")
+	assert.Equal(t, http.StatusOK, serve("/health").Code)
+
+	release()
+	assert.Equal(t, http.StatusOK, serve("/api/v1/export").Code)
+	assert.Equal(t, uint64(1), limits.Stats()["GET /api/v1/export"].Shed)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ConsentChecker tells whether an account accepted the current terms, see
+// service.UserService.HasAcceptedTerms.
+type ConsentChecker interface {
+	HasAcceptedTerms(ctx context.Context, accountNumber string) (bool, error)
+}
+
+// RequireConsent rejects requests of accounts that have not accepted consent.terms_version
+// with 403 CONSENT_REQUIRED; they accept it with PUT /api/v1/users/me/consents. It reads the
+// "accountNumber" set by BearerAuthMiddleware, so it goes after it.
+//
+// Usage:
+//
+//	bearerRoute.GET("/orders", h.ListOrders, middleware.RequireConsent(h.service.User))
+func RequireConsent(checker ConsentChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			accountNumber, _ := ctx.Get("accountNumber").(string)
+			if accountNumber == "" {
+				return response.Error(ctx, errorc.ErrorUnauthorized)
+			}
+
+			accepted, err := checker.HasAcceptedTerms(ctx.Request().Context(), accountNumber)
+			if err != nil {
+				return response.Error(ctx, err)
+			}
+			if !accepted {
+				return response.Error(ctx, errorc.ErrorConsentRequired)
+			}
+			return next(ctx)
+		}
+	}
+}
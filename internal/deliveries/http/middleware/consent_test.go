@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubConsentChecker map[string]bool
+
+func (c stubConsentChecker) HasAcceptedTerms(_ context.Context, accountNumber string) (bool, error) {
+	accepted, ok := c[accountNumber]
+	if !ok {
+		return false, errors.New("database unavailable")
+	}
+	return accepted, nil
+}
+
+func TestRequireConsent(t *testing.T) {
+	e := echo.New()
+	authenticate := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if account := ctx.Request().Header.Get("X-Account"); account != "" {
+				ctx.Set("accountNumber", account)
+			}
+			return next(ctx)
+		}
+	}
+	checker := stubConsentChecker{"accepted": true, "outdated": false}
+	e.GET("/", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	}, authenticate, middleware.RequireConsent(checker))
+
+	serve := func(account string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Account", account)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve("accepted").Code)
+
+	rec := serve("outdated")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "CONSENT_REQUIRED")
+
+	assert.Equal(t, http.StatusUnauthorized, serve("").Code)
+	assert.Equal(t, http.StatusInternalServerError, serve("unknown").Code, "checker errors are not consent")
+}
@@ -3,19 +3,109 @@ package middleware
 import (
 	"go-echo-boilerplate/internal/config"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
-func (m *Middleware) corsMiddleware(config *config.Configuration) echo.MiddlewareFunc {
-	echoHeaders := []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization}
+// defaultCORSMethods are allowed when cors.methods is empty
+var defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+func (m *Middleware) corsMiddleware(cfg *config.Configuration) echo.MiddlewareFunc {
+	var current atomic.Pointer[echo.MiddlewareFunc]
+	current.Store(newCORSMiddleware(cfg))
+
+	// Rebuild the CORS policy when the configuration is reloaded
+	config.OnChange(func(new config.Configuration) {
+		current.Store(newCORSMiddleware(&new))
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			return (*current.Load())(next)(ctx)
+		}
+	}
+}
+
+func newCORSMiddleware(config *config.Configuration) *echo.MiddlewareFunc {
+	echoHeaders := []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, HeaderCaptchaToken}
 	headersAllowed := append(echoHeaders, config.CORS.HeadersAllowed...)
 
-	return middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{http.MethodDelete, http.MethodGet, http.MethodOptions, http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodPatch},
+	methods := config.CORS.Methods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
+	// Validated at startup; an invalid value on reload sends no Access-Control-Max-Age
+	var maxAge int
+	if d, err := time.ParseDuration(config.CORS.MaxAge); err == nil {
+		maxAge = int(d.Seconds())
+	}
+
+	cors := middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOriginFunc:  newOriginMatcher(config.CORS.Origins).allowed,
+		AllowMethods:     methods,
 		AllowHeaders:     headersAllowed,
-		AllowCredentials: true,
+		ExposeHeaders:    config.CORS.HeadersExposed,
+		AllowCredentials: config.CORS.AllowCredentials,
+		MaxAge:           maxAge,
 	})
+	return &cors
+}
+
+// originMatcher matches request origins against cors.origins: "*" allows any origin,
+// "https://*.example.com" any subdomain of example.com over https (but not example.com itself),
+// anything else must match exactly (case-insensitive).
+type originMatcher struct {
+	any      bool
+	exact    map[string]bool
+	suffixes []originSuffix
+}
+
+type originSuffix struct {
+	scheme string
+	suffix string // ".example.com", optionally with ":port"
+}
+
+func newOriginMatcher(origins []string) *originMatcher {
+	m := &originMatcher{any: len(origins) == 0, exact: make(map[string]bool, len(origins))}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		scheme, host, ok := strings.Cut(origin, "://*.")
+		switch {
+		case origin == "*":
+			m.any = true
+		case ok:
+			m.suffixes = append(m.suffixes, originSuffix{scheme: scheme, suffix: "." + host})
+		default:
+			m.exact[origin] = true
+		}
+	}
+	return m
+}
+
+// allowed implements middleware.CORSConfig.AllowOriginFunc
+func (m *originMatcher) allowed(origin string) (bool, error) {
+	if m.any {
+		return true, nil
+	}
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true, nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || u.Path != "" {
+		return false, nil
+	}
+	for _, s := range m.suffixes {
+		if u.Scheme == s.scheme && len(u.Host) > len(s.suffix) && strings.HasSuffix(u.Host, s.suffix) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	setup := func(cors config.CORS) *echo.Echo {
+		e := echo.New()
+		cfg := &config.Configuration{CORS: cors}
+		m := middleware.New(e, cfg)
+		m.Default(cfg)
+		e.GET("/users", func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusOK)
+		})
+		return e
+	}
+	request := func(e *echo.Echo, method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		if method == http.MethodOptions {
+			req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Any Origin By Default", func(t *testing.T) {
+		rec := request(setup(config.CORS{}), http.MethodGet, "https://anywhere.dev")
+
+		assert.Equal(t, "https://anywhere.dev", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	})
+
+	t.Run("Origins", func(t *testing.T) {
+		e := setup(config.CORS{
+			Origins:          []string{"https://app.example.com", "https://*.example.org"},
+			AllowCredentials: true,
+		})
+
+		tests := []struct {
+			origin  string
+			allowed bool
+		}{
+			{"https://app.example.com", true},
+			{"https://APP.example.com", true},
+			{"https://admin.example.com", false},
+			{"https://a.b.example.org", true},
+			{"https://example.org", false},
+			{"http://a.example.org", false},
+			{"https://evilexample.org", false},
+			{"https://a.example.org.evil.com", false},
+		}
+		for _, tt := range tests {
+			rec := request(e, http.MethodGet, tt.origin)
+			if tt.allowed {
+				assert.Equal(t, tt.origin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin), tt.origin)
+				assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials), tt.origin)
+			} else {
+				assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin), tt.origin)
+			}
+		}
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		e := setup(config.CORS{
+			Origins:        []string{"https://app.example.com"},
+			Methods:        []string{"GET", "POST"},
+			HeadersAllowed: []string{"X-API-Key"},
+			MaxAge:         "10m",
+		})
+		rec := request(e, http.MethodOptions, "https://app.example.com")
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "GET,POST", rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+		assert.Contains(t, rec.Header().Get(echo.HeaderAccessControlAllowHeaders), "X-API-Key")
+		assert.Equal(t, "600", rec.Header().Get(echo.HeaderAccessControlMaxAge))
+	})
+
+	t.Run("Exposed Headers", func(t *testing.T) {
+		e := setup(config.CORS{HeadersExposed: []string{"X-Request-ID"}})
+		rec := request(e, http.MethodGet, "https://app.example.com")
+
+		assert.Equal(t, "X-Request-ID", rec.Header().Get(echo.HeaderAccessControlExposeHeaders))
+	})
+}
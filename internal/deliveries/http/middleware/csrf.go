@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/response"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Defaults for the csrf.* settings left unset
+const (
+	DefaultCSRFCookieName = "_csrf"
+	DefaultCSRFHeaderName = echo.HeaderXCSRFToken
+)
+
+// RefreshTokenCookie is the cookie GetTokens sets, the only credential the API takes from a
+// cookie
+const RefreshTokenCookie = "refresh_token"
+
+// CSRFMiddleware implements double-submit-cookie CSRF protection. Safe requests (GET, HEAD,
+// OPTIONS, TRACE) get the token in a cookie readable by JavaScript; state-changing requests
+// authenticated by the RefreshTokenCookie must echo it in the csrf.header_name header.
+//
+// Only those requests are checked: a request with an Authorization or X-API-Key header is
+// authenticated by it, which a forged request cannot set, even when the browser of a Bearer
+// client also sends the refresh token cookie; other cookies are no credential. Paths under
+// csrf.exempt_paths are never checked.
+func (m *Middleware) CSRFMiddleware(config *config.Configuration) echo.MiddlewareFunc {
+	cfg := config.CSRF
+	cookieName := cfg.CookieName
+	if cookieName == "" {
+		cookieName = DefaultCSRFCookieName
+	}
+	headerName := cfg.HeaderName
+	if headerName == "" {
+		headerName = DefaultCSRFHeaderName
+	}
+
+	return middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper: func(ctx echo.Context) bool {
+			if exemptPath(ctx.Request().URL.Path, cfg.ExemptPaths) {
+				return true
+			}
+			return !safeMethod(ctx.Request().Method) && !cookieAuthenticated(ctx.Request())
+		},
+		TokenLookup:    "header:" + headerName,
+		CookieName:     cookieName,
+		CookiePath:     "/",
+		CookieSecure:   cfg.Secure,
+		CookieSameSite: sameSite(cfg.SameSite), // echo forces Secure with SameSite=None
+		ErrorHandler: func(err error, ctx echo.Context) error {
+			return response.Error(ctx, errorc.ErrorCSRFToken)
+		},
+	})
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// cookieAuthenticated reports whether the browser sent the RefreshTokenCookie and no header
+// credential
+func cookieAuthenticated(req *http.Request) bool {
+	if req.Header.Get(echo.HeaderAuthorization) != "" || req.Header.Get("X-API-Key") != "" {
+		return false
+	}
+	_, err := req.Cookie(RefreshTokenCookie)
+	return err == nil
+}
+
+// exemptPath matches path prefixes at segment boundaries: "/webhooks" covers "/webhooks/stripe"
+// but not "/webhooks-admin"
+func exemptPath(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSite(mode string) http.SameSite {
+	switch mode {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
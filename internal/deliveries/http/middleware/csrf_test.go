@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	cfg := &config.Configuration{CSRF: config.CSRF{
+		Enabled:     true,
+		SameSite:    "strict",
+		ExemptPaths: []string{"/webhooks"},
+	}}
+	e := echo.New()
+	m := middleware.New(e, cfg)
+	e.Use(m.CSRFMiddleware(cfg))
+	ok := func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }
+	e.GET("/session", ok)
+	e.POST("/session/refresh", ok)
+	e.POST("/webhooks/stripe", ok)
+	e.POST("/webhooks-admin", ok)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A safe request issues the token cookie
+	rec := serve(httptest.NewRequest(http.MethodGet, "/session", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	csrf := cookies[0]
+	assert.Equal(t, middleware.DefaultCSRFCookieName, csrf.Name)
+	assert.Equal(t, http.SameSiteStrictMode, csrf.SameSite)
+	assert.False(t, csrf.HttpOnly, "the client must be able to read the token")
+
+	refresh := func(token string, withSession bool) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/session/refresh", nil)
+		req.AddCookie(csrf)
+		if withSession {
+			req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "opaque"})
+		}
+		if token != "" {
+			req.Header.Set(middleware.DefaultCSRFHeaderName, token)
+		}
+		return req
+	}
+
+	t.Run("Valid Token", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(refresh(csrf.Value, true)).Code)
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		rec := serve(refresh("", true))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), "CSRF_TOKEN_INVALID")
+	})
+
+	t.Run("Wrong Token", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(refresh("forged", true)).Code)
+	})
+
+	t.Run("No Ambient Cookies", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(refresh("", false)).Code)
+	})
+
+	t.Run("Other Cookies", func(t *testing.T) {
+		req := refresh("", false)
+		req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+		assert.Equal(t, http.StatusOK, serve(req).Code)
+	})
+
+	t.Run("Header Credentials", func(t *testing.T) {
+		// The browser of a Bearer client sends the refresh token cookie along
+		bearer := refresh("", true)
+		bearer.Header.Set(echo.HeaderAuthorization, "Bearer access-token")
+		assert.Equal(t, http.StatusOK, serve(bearer).Code)
+
+		apiKey := refresh("", true)
+		apiKey.Header.Set("X-API-Key", "key")
+		assert.Equal(t, http.StatusOK, serve(apiKey).Code)
+	})
+
+	t.Run("Exempt Paths", func(t *testing.T) {
+		withSession := func(path string) *http.Request {
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+			req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "opaque"})
+			return req
+		}
+		assert.Equal(t, http.StatusOK, serve(withSession("/webhooks/stripe")).Code)
+		assert.Equal(t, http.StatusForbidden, serve(withSession("/webhooks-admin")).Code)
+	})
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/capture"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DebugCaptureMiddleware stores, with capture.Default(), the sanitized request and response
+// of the requests carrying a valid X-Debug-Capture token or made by an account of
+// debug_capture.accounts. Bodies are kept up to debug_capture.max_body_size; binary and
+// multipart ones are left out. The capture is stored before the request completes, which
+// only delays the captured requests. /health, /version, /admin, and /debug are never captured.
+//
+// An invalid or expired token doesn't fail the request, it is only not captured: the wide
+// event gets debug_capture "invalid_token", or "stored" with debug_capture_id once captured.
+//
+// It is a no-op when no capturer is installed.
+func (m *Middleware) DebugCaptureMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			capturer := capture.Default()
+			req := ctx.Request()
+			if capturer == nil || operationalRoute(req.URL.Path) {
+				return next(ctx)
+			}
+
+			requestCtx := req.Context()
+			var trigger, label string
+			if token := req.Header.Get(capture.Header); token != "" {
+				if tokenLabel, err := capturer.Label(token); err != nil {
+					logger.Add(requestCtx, "debug_capture", "invalid_token")
+				} else {
+					trigger, label = models.DebugCaptureTriggerToken, tokenLabel
+				}
+			}
+			// The account is only known once the handler authenticated the request
+			if trigger == "" && !capturer.Watching() {
+				return next(ctx)
+			}
+
+			start := time.Now()
+			request := models.CapturedRequest{
+				Method: req.Method,
+				URL:    req.URL.RequestURI(),
+				Header: capture.SanitizeHeader(req.Header),
+			}
+			captureRequestBody(req, capturer.MaxBodySize(), &request)
+
+			writer := &captureWriter{ResponseWriter: ctx.Response().Writer, limit: capturer.MaxBodySize()}
+			ctx.Response().Writer = writer
+
+			err := next(ctx)
+			if err != nil && !ctx.Response().Committed {
+				ctx.Error(err) // write the error response now, so it is captured too
+			}
+
+			accountNumber, _ := ctx.Get("accountNumber").(string)
+			if trigger == "" {
+				if !capturer.Flagged(accountNumber) {
+					return err
+				}
+				trigger = models.DebugCaptureTriggerAccount
+			}
+
+			res := ctx.Response()
+			response := models.CapturedResponse{
+				Status:     res.Status,
+				Header:     capture.SanitizeHeader(res.Header()),
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			writer.body(res.Header().Get(echo.HeaderContentType), &response)
+
+			requestID, _ := ctx.Get("X-Request-ID").(string)
+			debugCapture := &models.DebugCapture{
+				RequestID:     requestID,
+				Trigger:       trigger,
+				Label:         label,
+				AccountNumber: accountNumber,
+				Method:        req.Method,
+				Path:          req.URL.Path,
+				Status:        res.Status,
+				Request:       marshalCaptured(request),
+				Response:      marshalCaptured(response),
+			}
+			// The client may be gone, the capture is stored regardless
+			if err := capturer.Save(context.WithoutCancel(requestCtx), debugCapture); err != nil {
+				logger.Add(requestCtx, "debug_capture", "failed")
+				logger.L().Warn(requestCtx, "debug capture failed", logger.Error(err))
+			} else {
+				logger.AddMap(requestCtx, map[string]any{
+					"debug_capture":    "stored",
+					"debug_capture_id": debugCapture.ID,
+				})
+			}
+			return err
+		}
+	}
+}
+
+// captureRequestBody keeps the start of a textual request body, sanitized, restoring it for
+// the handler; binary and multipart bodies are left out
+func captureRequestBody(req *http.Request, limit int, captured *models.CapturedRequest) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	mediaType := mediaType(req.Header.Get(echo.HeaderContentType))
+	if !isTextual(mediaType) {
+		captured.BodyOmitted = true
+		return
+	}
+
+	// One byte past the limit tells a body cut short from one of exactly limit bytes
+	data, err := io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), req.Body), req.Body}
+	truncated := len(data) > limit
+	if truncated {
+		data = data[:limit]
+	}
+	if err != nil || !isText(data, truncated) {
+		captured.BodyOmitted = true
+		return
+	}
+	captured.Body = capture.SanitizeBody(mediaType, data)
+	captured.BodyTruncated = truncated
+}
+
+// marshalCaptured encodes a captured request or response; they always encode
+func marshalCaptured(captured any) string {
+	data, _ := json.Marshal(captured)
+	return string(data)
+}
+
+// captureWriter tees the first limit bytes of the response body. It sits inside
+// CompressMiddleware, so it sees the uncompressed body.
+type captureWriter struct {
+	http.ResponseWriter
+	limit int
+
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	if remaining := w.limit - w.buf.Len(); remaining < len(b) {
+		w.buf.Write(b[:max(remaining, 0)])
+		w.truncated = true
+	} else {
+		w.buf.Write(b)
+	}
+	w.mu.Unlock()
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, forwarding the call when the underlying writer supports it.
+func (w *captureWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// body sets the sanitized body of a response of contentType on captured
+func (w *captureWriter) body(contentType string, captured *models.CapturedResponse) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() == 0 {
+		return
+	}
+	mediaType := mediaType(contentType)
+	if !isTextual(mediaType) || !isText(w.buf.Bytes(), w.truncated) {
+		captured.BodyOmitted = true
+		return
+	}
+	captured.Body = capture.SanitizeBody(mediaType, w.buf.Bytes())
+	captured.BodyTruncated = w.truncated
+}
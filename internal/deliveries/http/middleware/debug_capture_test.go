@@ -0,0 +1,108 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/capture"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugCaptureMiddleware(t *testing.T) {
+	t.Cleanup(func() { capture.SetDefault(nil) })
+
+	repo := memory.NewDebugCaptureRepository()
+	capturer, err := capture.FromConfig(config.DebugCapture{Enabled: true, MaxBodySize: "64B", Accounts: []string{"1234567890"}},
+		"debug-capture-signing-secret-0123456789", repo)
+	require.NoError(t, err)
+	capture.SetDefault(capturer)
+	token, _, err := capturer.Token("SUP-1234")
+	require.NoError(t, err)
+
+	e := echo.New()
+	m := middleware.New(e, &config.Configuration{})
+	e.Use(m.DebugCaptureMiddleware())
+	e.POST("/api/v1/users", func(ctx echo.Context) error {
+		var body map[string]any
+		if err := ctx.Bind(&body); err != nil {
+			return err
+		}
+		ctx.Set("accountNumber", ctx.Request().Header.Get("X-Test-Account"))
+		return ctx.JSON(http.StatusUnprocessableEntity, map[string]any{"error": "invalid", "token": "secret-token"})
+	})
+	e.GET("/api/v1/forbidden", func(ctx echo.Context) error {
+		return echo.ErrForbidden
+	})
+	serve := func(header http.Header, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users?include=profile", strings.NewReader(body))
+		req.Header = header
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	list := func() []models.DebugCapture {
+		captures, err := repo.List(context.Background(), 50)
+		require.NoError(t, err)
+		return captures
+	}
+
+	rec := serve(http.Header{}, `{"name":"Jane"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	rec = serve(http.Header{capture.Header: {"forged"}}, `{"name":"Jane"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, "an invalid token doesn't fail the request")
+	assert.Empty(t, list())
+
+	rec = serve(http.Header{capture.Header: {token}, "Authorization": {"Bearer abc"}}, `{"name":"Jane","password":"hunter22"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "secret-token", "the response is sent as is")
+	captures := list()
+	require.Len(t, captures, 1)
+	stored, err := repo.Get(context.Background(), captures[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DebugCaptureTriggerToken, stored.Trigger)
+	assert.Equal(t, "SUP-1234", stored.Label)
+	assert.Equal(t, http.StatusUnprocessableEntity, stored.Status)
+
+	captured, err := stored.DebugCaptureResponse()
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/users?include=profile", captured.Request.URL)
+	assert.Equal(t, logger.MaskString, captured.Request.Header["Authorization"])
+	assert.Contains(t, captured.Request.Body, `"name":"Jane"`)
+	assert.NotContains(t, captured.Request.Body, "hunter22")
+	assert.Contains(t, captured.Response.Body, `"error":"invalid"`)
+	assert.NotContains(t, captured.Response.Body, "secret-token")
+
+	rec = serve(http.Header{"X-Test-Account": {"1234567890"}}, `{"name":"`+strings.Repeat("x", 100)+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, "the whole body reaches the handler")
+	captures = list()
+	require.Len(t, captures, 2)
+	assert.Equal(t, models.DebugCaptureTriggerAccount, captures[0].Trigger)
+	assert.Equal(t, "1234567890", captures[0].AccountNumber)
+	stored, err = repo.Get(context.Background(), captures[0].ID)
+	require.NoError(t, err)
+	captured, err = stored.DebugCaptureResponse()
+	require.NoError(t, err)
+	assert.True(t, captured.Request.BodyTruncated)
+	assert.Len(t, captured.Request.Body, 64)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/forbidden", nil)
+	req.Header.Set(capture.Header, token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	captures = list()
+	require.Len(t, captures, 3)
+	assert.Equal(t, http.StatusForbidden, captures[0].Status, "the error response is captured")
+}
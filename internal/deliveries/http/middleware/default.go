@@ -6,7 +6,29 @@ import (
 )
 
 func (m *Middleware) Default(config *config.Configuration) {
-	m.e.Use(m.RecoverMiddleware(logger.Instance))
-	m.e.Use(m.LoggingMiddleware(logger.Instance))
+	if config.Server.TLS.Enabled() && config.Server.TLS.RedirectPort != 0 {
+		m.e.Pre(m.HTTPSRedirectMiddleware(config))
+	}
+	m.e.Use(m.RecoverMiddleware(logger.L()))
+	m.e.Use(m.LoggingMiddleware(logger.L()))
+	if config.ClientInfo.Enabled() {
+		m.e.Use(m.ClientInfoMiddleware())
+	}
+	if config.Server.Compression.Enabled {
+		m.e.Use(m.CompressMiddleware(config)) // inside logging, see CompressMiddleware
+	}
 	m.e.Use(m.corsMiddleware(config))
+	m.e.Use(m.MaintenanceMiddleware()) // after CORS, so browsers can read the 503
+	m.e.Use(m.ConcurrencyMiddleware()) // before reading the body of a request it may shed
+	m.e.Use(m.BodyLimitMiddleware(config))
+	if config.DebugCapture.Enabled {
+		m.e.Use(m.DebugCaptureMiddleware()) // inside compression, so it sees the uncompressed bodies
+	}
+	if config.CSRF.Enabled {
+		m.e.Use(m.CSRFMiddleware(config))
+	}
+	m.e.Use(m.LocaleMiddleware())
+	if config.Captcha.Enabled() && len(config.Captcha.Routes) > 0 {
+		m.e.Use(m.CaptchaMiddleware(config))
+	}
 }
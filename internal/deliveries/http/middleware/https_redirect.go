@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPSRedirectMiddleware redirects plain HTTP requests, i.e. those on the
+// server.tls.redirect_port listener, to HTTPS on application.port. Requests over TLS pass through.
+// GET and HEAD get a 301; other methods a 308, so clients repeat the method and body.
+func (m *Middleware) HTTPSRedirectMiddleware(config *config.Configuration) echo.MiddlewareFunc {
+	tlsPort := config.Application.Port
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if ctx.IsTLS() {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			host := req.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			host = strings.Trim(host, "[]")
+			if tlsPort != 443 {
+				host = net.JoinHostPort(host, strconv.Itoa(tlsPort))
+			} else if strings.Contains(host, ":") {
+				host = "[" + host + "]"
+			}
+
+			code := http.StatusPermanentRedirect
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				code = http.StatusMovedPermanently
+			}
+			return ctx.Redirect(code, "https://"+host+req.URL.RequestURI())
+		}
+	}
+}
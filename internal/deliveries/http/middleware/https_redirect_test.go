@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirectMiddleware(t *testing.T) {
+	setup := func(port int) *echo.Echo {
+		e := echo.New()
+		cfg := &config.Configuration{Application: config.Application{Port: port}}
+		m := middleware.New(e, cfg)
+		e.Pre(m.HTTPSRedirectMiddleware(cfg))
+		e.Any("/users", func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusOK)
+		})
+		return e
+	}
+
+	tests := []struct {
+		name     string
+		port     int
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{"Default Port", 443, http.MethodGet, "http://example.com/users?page=2", http.StatusMovedPermanently, "https://example.com/users?page=2"},
+		{"Custom Port", 8443, http.MethodGet, "http://example.com:8080/users", http.StatusMovedPermanently, "https://example.com:8443/users"},
+		{"IPv6", 443, http.MethodGet, "http://[::1]:8080/users", http.StatusMovedPermanently, "https://[::1]/users"},
+		{"POST Keeps Method", 443, http.MethodPost, "http://example.com/users", http.StatusPermanentRedirect, "https://example.com/users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			setup(tt.port).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.location, rec.Header().Get(echo.HeaderLocation))
+		})
+	}
+
+	t.Run("TLS Passes Through", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "https://example.com/users", nil)
+		req.TLS = &tls.ConnectionState{}
+		rec := httptest.NewRecorder()
+		setup(443).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LocaleMiddleware negotiates the response language from the Accept-Language header
+// and stores it in the request context, where validator and response helpers pick it up.
+// The negotiated language is echoed back in the Content-Language header.
+func (m *Middleware) LocaleMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			locale := i18n.Parse(ctx.Request().Header.Get("Accept-Language"))
+
+			ctx.SetRequest(ctx.Request().WithContext(i18n.WithLocale(ctx.Request().Context(), locale)))
+			ctx.Response().Header().Set("Content-Language", string(locale))
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/pkg/schema"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ValidateSchema validates the JSON request body against s before the handler binds it and
+// responds with the standard validation error list when it doesn't match, for schema-first
+// routes. It complements go-playground/validator: the handler still binds and runs
+// validator.Input, which covers the rules a schema can't express (e.g. phoneFormat).
+// Only POST, PUT, and PATCH bodies are validated, an empty one counting as {}.
+//
+// Usage:
+//
+//	createUser := schema.Must(schema.FromModel("models.CreateUserRequest"))
+//	noBearerRoute.POST("", h.Create, middleware.ValidateSchema(createUser))
+func ValidateSchema(s *schema.Schema) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			switch req.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch:
+			default:
+				return next(ctx)
+			}
+
+			var body []byte
+			if req.Body != nil {
+				read, err := io.ReadAll(req.Body)
+				if err != nil {
+					// Past the limit of the BodyLimitMiddleware, answers 413
+					var tooLarge *http.MaxBytesError
+					if errors.As(err, &tooLarge) {
+						return response.Error(ctx, err)
+					}
+					return response.Error(ctx, errorc.Error(errorc.ErrorInvalidInput, "failed to read request body"))
+				}
+				body = read
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			if err := s.Validate(body, i18n.FromContext(req.Context())); err != nil {
+				logger.Add(req.Context(), "rejection_reason", "schema_validation")
+				return response.ErrorBinding(ctx, err)
+			}
+			return next(ctx)
+		}
+	}
+}
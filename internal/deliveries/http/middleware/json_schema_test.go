@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/schema"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchema(t *testing.T) {
+	s := schema.Must(schema.Parse([]byte(`{
+		"type": "object",
+		"required": ["level"],
+		"properties": {"level": {"type": "string", "enum": ["debug", "info"]}}
+	}`)))
+
+	e := echo.New()
+	handler := func(ctx echo.Context) error {
+		var request models.UpdateLogLevelRequest
+		if err := ctx.Bind(&request); err != nil {
+			return err
+		}
+		return ctx.String(http.StatusOK, request.Level)
+	}
+	e.POST("/loglevel", handler, middleware.ValidateSchema(s))
+	e.GET("/loglevel", handler, middleware.ValidateSchema(s))
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/loglevel", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		rec := serve(http.MethodPost, `{"level":"debug"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "debug", rec.Body.String(), "the handler still reads the body")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		rec := serve(http.MethodPost, `{"level":"verbose"}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body struct {
+			Status string                           `json:"status"`
+			Errors []models.ErrorValidationResponse `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "VALIDATION_ERROR", body.Status)
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "level", body.Errors[0].Field)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		rec := serve(http.MethodPost, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "MISSING_FIELD")
+	})
+
+	t.Run("Not JSON", func(t *testing.T) {
+		rec := serve(http.MethodPost, `{"level":`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "BAD_REQUEST")
+	})
+
+	t.Run("GET Is Not Validated", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, "").Code)
+	})
+}
@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"go-echo-boilerplate/internal/pkg/actor"
 	"go-echo-boilerplate/internal/pkg/errorc"
 	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/logger"
 	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/pkg/session"
 	"go-echo-boilerplate/internal/pkg/validator"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -16,7 +20,10 @@ import (
 // The middleware:
 //   - Extracts the Bearer token from the Authorization header
 //   - Validates the token signature, expiration, and type via validator.AccessToken
-//   - Injects user claims into the context for downstream handlers
+//   - Rejects tokens issued before the account's sessions were revoked (session.Default()).
+//     A failed revocation lookup is logged and lets the request through
+//   - Injects user claims into the context for downstream handlers, and the account number
+//     into the request context as its actor (actor.WithActor)
 //
 // Context keys set:
 //   - "user_id": int - The authenticated user's ID
@@ -46,11 +53,26 @@ func BearerAuthMiddleware(config *jwtc.Configuration) echo.MiddlewareFunc {
 				return response.Error(ctx, errorc.Error(errorc.ErrorUnauthorized, err.Error()))
 			}
 
+			// A token without iat counts as issued before any revocation
+			var issuedAt time.Time
+			if claims.IssuedAt != nil {
+				issuedAt = claims.IssuedAt.Time
+			}
+			reqCtx := ctx.Request().Context()
+			revoked, err := session.Default().IsRevoked(reqCtx, claims.AccountNumber, issuedAt)
+			if err != nil {
+				logger.FromContext(reqCtx).Warn(reqCtx, "session revocation lookup failed", logger.Error(err))
+			}
+			if revoked {
+				return response.Error(ctx, errorc.Error(errorc.ErrorUnauthorized, "session has been revoked"))
+			}
+
 			// Inject claims into context for downstream handlers
 			ctx.Set("userID", claims.UserID)
 			ctx.Set("accountNumber", claims.AccountNumber)
 			ctx.Set("email", claims.Email)
 			ctx.Set("phoneNumber", claims.PhoneNumber)
+			ctx.SetRequest(ctx.Request().WithContext(actor.WithActor(reqCtx, claims.AccountNumber)))
 
 			return next(ctx)
 		}
@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/actor"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/session"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBearerAuthMiddleware(t *testing.T) {
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "test-secret-key-0123456789abcdef",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "test-secret-key-0123456789abcdef",
+		RefreshTokenDuration: 7 * 24 * time.Hour,
+		Issuer:               "test-issuer",
+	}
+	revocations := session.NewRevocations(cache.NewMemoryStore(10), time.Hour)
+	session.SetDefault(revocations)
+	t.Cleanup(func() { session.SetDefault(nil) })
+
+	e := echo.New()
+	e.GET("/me", func(ctx echo.Context) error {
+		id, _ := actor.FromContext(ctx.Request().Context())
+		ctx.Response().Header().Set("X-Actor", id)
+		return ctx.String(http.StatusOK, ctx.Get("accountNumber").(string))
+	}, middleware.BearerAuthMiddleware(jwtConfig))
+
+	token, err := generator.AccessToken(&models.User{ID: 1, AccountNumber: "acc-1"}, jwtConfig)
+	require.NoError(t, err)
+
+	get := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Missing Header", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get("").Code)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get("Bearer not-a-token").Code)
+	})
+
+	t.Run("Valid Token", func(t *testing.T) {
+		rec := get("Bearer " + token.Token)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "acc-1", rec.Body.String())
+		assert.Equal(t, "acc-1", rec.Header().Get("X-Actor"), "the account performs the request")
+	})
+
+	t.Run("Revoked Session", func(t *testing.T) {
+		require.NoError(t, revocations.Revoke(context.Background(), "acc-1", time.Now()))
+
+		rec := get("Bearer " + token.Token)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "session has been revoked")
+	})
+
+	t.Run("Token Issued After The Revocation", func(t *testing.T) {
+		require.NoError(t, revocations.Revoke(context.Background(), "acc-1", time.Now()))
+		time.Sleep(2 * time.Millisecond) // issue times round down to the millisecond
+
+		signedIn, err := generator.AccessToken(&models.User{ID: 1, AccountNumber: "acc-1"}, jwtConfig)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, get("Bearer "+signedIn.Token).Code, "a sign-in in the same second is accepted")
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"sync/atomic"
+	"time"
+)
+
+// accessLog emits a compact access log line per request next to the wide event,
+// following logger.access_log
+type accessLog struct {
+	enabled atomic.Bool
+}
+
+// newAccessLog reads logger.access_log and updates it on reload
+func newAccessLog(cfg *config.Configuration) *accessLog {
+	a := &accessLog{}
+	a.enabled.Store(cfg.Logger.AccessLog)
+
+	config.OnChange(func(new config.Configuration) {
+		a.enabled.Store(new.Logger.AccessLog)
+	})
+
+	return a
+}
+
+// emit writes the access log line at INFO. Unlike the wide event it is never sampled.
+func (a *accessLog) emit(log logger.Logger, ctx context.Context, wideEvent *logger.WideEvent, statusCode int, duration time.Duration) {
+	if !a.enabled.Load() {
+		return
+	}
+
+	log.Info(ctx, "access",
+		logger.String(logger.FieldMethod, wideEvent.Method),
+		logger.String(logger.FieldPath, wideEvent.Path),
+		logger.Int(logger.FieldStatusCode, statusCode),
+		logger.Int64(logger.FieldDurationMS, duration.Milliseconds()),
+		logger.String(logger.FieldRequestID, wideEvent.RequestID),
+	)
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddlewareAccessLog(t *testing.T) {
+	setup := func(cfg config.Logger) (*echo.Echo, *logger.TestLogger) {
+		log := logger.NewTestLogger()
+		e := echo.New()
+		m := middleware.New(e, &config.Configuration{Logger: cfg})
+		e.Use(m.LoggingMiddleware(log))
+		e.GET("/users/:id", func(ctx echo.Context) error { return ctx.String(http.StatusOK, "ok") })
+		return e, log
+	}
+
+	t.Run("Dual Emit", func(t *testing.T) {
+		e, logs := setup(config.Logger{AccessLog: true})
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		access := logs.EventsWithMessage("access")
+		require.Len(t, access, 1)
+		assert.Equal(t, "info", access[0].Level)
+
+		fields := access[0].Fields
+		assert.Equal(t, "GET", fields["method"])
+		assert.Equal(t, "/users/42", fields["path"])
+		assert.Equal(t, int64(http.StatusOK), fields["status_code"])
+		assert.Equal(t, "req-1", fields["request_id"])
+		assert.Contains(t, fields, "duration_ms")
+		assert.NotContains(t, fields, "request_headers", "the access line stays compact")
+
+		assert.Len(t, logs.EventsWithMessage("Request completed"), 1, "the wide event is still emitted")
+	})
+
+	t.Run("Not Sampled", func(t *testing.T) {
+		e, logs := setup(config.Logger{AccessLog: true, SuccessSampleRate: 1e-12})
+		for i := 0; i < 5; i++ {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+		}
+
+		assert.Len(t, logs.EventsWithMessage("access"), 5)
+		assert.Empty(t, logs.EventsWithMessage("Request completed"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		e, logs := setup(config.Logger{})
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		assert.Empty(t, logs.EventsWithMessage("access"))
+		assert.Len(t, logs.Events(), 1)
+	})
+}
@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-echo-boilerplate/internal/config"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Body capture modes of logger.body_capture
+const (
+	bodyCaptureAlways = "always"
+	bodyCaptureErrors = "errors"
+	bodyCaptureOff    = "off"
+)
+
+// maxRequestCapture is the maximum number of request body bytes we buffer
+const maxRequestCapture = 10 * 1024 // 10 KB
+
+// bodyCapture decides, following logger.body_capture, whether a request body is buffered
+// for the wide event
+type bodyCapture struct {
+	mode  atomic.Pointer[string] // the environment's mode, or logger.body_capture.mode
+	paths *pathPatterns
+}
+
+// newBodyCapture reads logger.body_capture and updates the modes on reload
+func newBodyCapture(cfg *config.Configuration) *bodyCapture {
+	b := &bodyCapture{paths: &pathPatterns{}}
+	b.store(cfg)
+
+	config.OnChange(func(new config.Configuration) {
+		b.store(&new)
+	})
+
+	return b
+}
+
+func (b *bodyCapture) store(cfg *config.Configuration) {
+	capture := cfg.Logger.BodyCapture
+	mode := capture.Mode
+	if environmentMode, ok := capture.Environments[cfg.Application.Environment]; ok {
+		mode = environmentMode
+	}
+	if mode == "" {
+		mode = bodyCaptureAlways
+	}
+
+	b.mode.Store(&mode)
+	b.paths.store(capture.Paths)
+}
+
+// modeFor returns the capture mode of a request path
+func (b *bodyCapture) modeFor(path string) string {
+	if mode := b.paths.match(path); mode != "" {
+		return mode
+	}
+	return *b.mode.Load()
+}
+
+// read prepares the capture of the request body unless capture is off for its path.
+// Textual bodies (JSON, forms, text) have their start buffered and restored for the
+// handler; multipart and binary bodies are never buffered, only the bytes the handler
+// reads are counted. The body is only parsed and masked when the wide event is emitted,
+// see pendingBody.attach.
+func (b *bodyCapture) read(c echo.Context) pendingBody {
+	req := c.Request()
+	mode := b.modeFor(req.URL.Path)
+	if mode == bodyCaptureOff || req.Body == nil || req.Body == http.NoBody {
+		return pendingBody{}
+	}
+
+	body := pendingBody{mediaType: mediaType(req.Header.Get(echo.HeaderContentType)), errorsOnly: mode == bodyCaptureErrors}
+	if !isTextual(body.mediaType) {
+		body.counter = &countingReader{ReadCloser: req.Body}
+		req.Body = body.counter
+		return body
+	}
+
+	// Restore body for handler, keeping anything past the capture limit (or the read error)
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxRequestCapture))
+	restored := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), req.Body), req.Body}
+	if err != nil {
+		req.Body = restored
+		return pendingBody{}
+	}
+
+	// A body declared (or defaulted) as text that isn't is summarized like a binary one
+	if !isText(data, len(data) == maxRequestCapture) {
+		body.counter = &countingReader{ReadCloser: restored}
+		req.Body = body.counter
+		return body
+	}
+
+	req.Body = restored
+	body.data = data
+	return body
+}
+
+// pendingBody is a captured request body waiting for the outcome of the request
+type pendingBody struct {
+	data       []byte          // buffered start of a textual body
+	counter    *countingReader // a multipart or binary body, summarized instead of logged
+	mediaType  string
+	errorsOnly bool
+}
+
+// attach adds the masked body to the wide event as request_body, unless it is only captured
+// for errors and the request succeeded. Textual bodies are parsed, multipart and binary
+// bodies are summarized from req.
+func (p pendingBody) attach(ctx context.Context, masker *requestMasker, req *http.Request, statusCode int) {
+	if (len(p.data) == 0 && p.counter == nil) || (p.errorsOnly && statusCode < http.StatusBadRequest) {
+		return
+	}
+
+	if p.counter != nil {
+		masker.add(ctx, "request_body", summarizeRequestBody(req, p.mediaType, p.counter.count()))
+		return
+	}
+	masker.add(ctx, "request_body", parseRequestBody(p.mediaType, p.data))
+}
+
+// parseRequestBody parses a JSON object or form body, or keeps the start of any other
+// textual body as raw
+func parseRequestBody(mediaType string, data []byte) map[string]any {
+	if mediaType == echo.MIMEApplicationForm {
+		if values, err := url.ParseQuery(string(data)); err == nil {
+			form := make(map[string]any, len(values))
+			for key, value := range values {
+				form[key] = value[0]
+			}
+			return form
+		}
+	}
+
+	var bodyData map[string]any
+	if err := json.Unmarshal(data, &bodyData); err == nil {
+		return bodyData
+	}
+
+	// If not JSON, return as string (truncated if too long)
+	bodyStr := string(data)
+	if len(bodyStr) > 1000 {
+		bodyStr = bodyStr[:1000] + "...(truncated)"
+	}
+
+	return map[string]any{
+		"raw": bodyStr,
+	}
+}
+
+// summarizeRequestBody describes a multipart or binary body without its content: its type,
+// its size, and for a multipart form parsed by the handler the name and size of each part
+func summarizeRequestBody(req *http.Request, mediaType string, read int64) map[string]any {
+	if mediaType == "" {
+		mediaType = echo.MIMEOctetStream
+	}
+	size := req.ContentLength
+	if size < 0 {
+		size = read // chunked upload, only what the handler read is known
+	}
+	summary := map[string]any{
+		"content_type": mediaType,
+		"size":         size,
+	}
+
+	if form := req.MultipartForm; form != nil && strings.HasPrefix(mediaType, "multipart/") {
+		parts := make([]any, 0, len(form.Value)+len(form.File))
+		for _, name := range slices.Sorted(maps.Keys(form.Value)) {
+			for _, value := range form.Value[name] {
+				parts = append(parts, map[string]any{"name": name, "size": len(value)})
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(form.File)) {
+			for _, file := range form.File[name] {
+				parts = append(parts, map[string]any{
+					"name":         name,
+					"size":         file.Size,
+					"content_type": file.Header.Get(echo.HeaderContentType),
+				})
+			}
+		}
+		summary["parts"] = parts
+	}
+
+	return summary
+}
+
+// mediaType returns the lowercased media type of a Content-Type header, "" when it is
+// missing and application/octet-stream when it can't be parsed
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return echo.MIMEOctetStream
+	}
+	return parsed
+}
+
+// isTextual reports whether a media type is logged as text; a missing one is sniffed
+func isTextual(mediaType string) bool {
+	switch {
+	case mediaType == "", strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == echo.MIMEApplicationJSON, mediaType == echo.MIMEApplicationForm, mediaType == echo.MIMEApplicationXML:
+		return true
+	default:
+		return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+	}
+}
+
+// isText reports whether data is valid UTF-8 without control characters other than
+// whitespace; truncated data may end in the middle of a character
+func isText(data []byte, truncated bool) bool {
+	if truncated {
+		for i := 0; i < utf8.UTFMax-1 && len(data) > 0; i++ {
+			if r, _ := utf8.DecodeLastRune(data); r != utf8.RuneError {
+				break
+			}
+			data = data[:len(data)-1]
+		}
+	}
+	if !utf8.Valid(data) {
+		return false
+	}
+	return !bytes.ContainsFunc(data, func(r rune) bool {
+		return unicode.IsControl(r) && !unicode.IsSpace(r)
+	})
+}
+
+// countingReader counts the bytes the handler reads of a body that isn't buffered
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
+
+func (r *countingReader) count() int64 {
+	return r.n.Load()
+}
@@ -0,0 +1,203 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddlewareBodyCapture(t *testing.T) {
+	setup := func(capture config.LogBodyCapture, environment string) (*echo.Echo, *logger.TestLogger) {
+		log := logger.NewTestLogger()
+		e := echo.New()
+		cfg := &config.Configuration{
+			Application: config.Application{Environment: environment},
+			Logger:      config.Logger{BodyCapture: capture},
+		}
+		m := middleware.New(e, cfg)
+		e.Use(m.LoggingMiddleware(log))
+
+		// Echoes the body, so a skipped capture must still leave it readable
+		echoBody := func(status int) echo.HandlerFunc {
+			return func(ctx echo.Context) error {
+				body, err := io.ReadAll(ctx.Request().Body)
+				if err != nil {
+					return err
+				}
+				return ctx.Blob(status, echo.MIMEApplicationJSON, body)
+			}
+		}
+		e.POST("/api/v1/ok", echoBody(http.StatusOK))
+		e.POST("/api/v1/bad", echoBody(http.StatusBadRequest))
+		e.POST("/api/v1/uploads/ok", echoBody(http.StatusOK))
+		return e, log
+	}
+
+	// post returns the request_body of the wide event, nil when it has none
+	post := func(t *testing.T, e *echo.Echo, log *logger.TestLogger, path string) any {
+		t.Helper()
+		log.Reset()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"jane","password":"hunter2"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(rec, req)
+		assert.JSONEq(t, `{"name":"jane","password":"hunter2"}`, rec.Body.String())
+
+		events := log.EventsWithMessage("Request completed")
+		require.Len(t, events, 1)
+		return events[0].Fields["request_body"]
+	}
+
+	t.Run("Always By Default", func(t *testing.T) {
+		e, log := setup(config.LogBodyCapture{}, "local")
+
+		body, ok := post(t, e, log, "/api/v1/ok").(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "jane", body["name"])
+		assert.NotEqual(t, "hunter2", body["password"], "the body is masked")
+	})
+
+	t.Run("Errors Only", func(t *testing.T) {
+		e, log := setup(config.LogBodyCapture{Mode: "errors"}, "local")
+
+		assert.Nil(t, post(t, e, log, "/api/v1/ok"))
+		assert.NotNil(t, post(t, e, log, "/api/v1/bad"))
+	})
+
+	t.Run("Off", func(t *testing.T) {
+		e, log := setup(config.LogBodyCapture{Mode: "off"}, "local")
+
+		assert.Nil(t, post(t, e, log, "/api/v1/ok"))
+		assert.Nil(t, post(t, e, log, "/api/v1/bad"))
+	})
+
+	t.Run("Per Environment", func(t *testing.T) {
+		capture := config.LogBodyCapture{Environments: map[string]string{"prod": "off"}}
+
+		e, log := setup(capture, "prod")
+		assert.Nil(t, post(t, e, log, "/api/v1/ok"))
+
+		e, log = setup(capture, "staging")
+		assert.NotNil(t, post(t, e, log, "/api/v1/ok"))
+	})
+
+	t.Run("Per Path", func(t *testing.T) {
+		e, log := setup(config.LogBodyCapture{
+			Environments: map[string]string{"prod": "off"},
+			Paths:        map[string]string{"/api/v1/uploads/*": "always"},
+		}, "prod")
+
+		assert.Nil(t, post(t, e, log, "/api/v1/ok"))
+		assert.NotNil(t, post(t, e, log, "/api/v1/uploads/ok"), "the path overrides the environment")
+	})
+}
+
+func TestLoggingMiddlewareBodyCaptureContentTypes(t *testing.T) {
+	log := logger.NewTestLogger()
+	e := echo.New()
+	m := middleware.New(e, &config.Configuration{})
+	e.Use(m.LoggingMiddleware(log))
+
+	var received []byte
+	e.POST("/upload", func(ctx echo.Context) error {
+		file, err := ctx.FormFile("avatar")
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "jane", ctx.FormValue("name"))
+		return ctx.String(http.StatusOK, file.Filename)
+	})
+	e.POST("/echo", func(ctx echo.Context) error {
+		var err error
+		received, err = io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return err
+		}
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	// send returns the request_body of the wide event
+	send := func(t *testing.T, path, contentType string, body []byte) map[string]any {
+		t.Helper()
+		log.Reset()
+
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		if contentType != "" {
+			req.Header.Set(echo.HeaderContentType, contentType)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Less(t, rec.Code, http.StatusBadRequest)
+
+		events := log.EventsWithMessage("Request completed")
+		require.Len(t, events, 1)
+		captured, ok := events[0].Fields["request_body"].(map[string]any)
+		require.True(t, ok)
+		return captured
+	}
+
+	t.Run("Multipart Summarized", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField("name", "jane"))
+		part, err := writer.CreateFormFile("avatar", "avatar.png")
+		require.NoError(t, err)
+		_, err = part.Write(bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 100))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		captured := send(t, "/upload", writer.FormDataContentType(), body.Bytes())
+		assert.Equal(t, "multipart/form-data", captured["content_type"])
+		assert.EqualValues(t, body.Len(), captured["size"])
+		require.Len(t, captured["parts"], 2)
+		parts := captured["parts"].([]any)
+		assert.Equal(t, map[string]any{"name": "name", "size": 4}, parts[0])
+		avatar := parts[1].(map[string]any)
+		assert.Equal(t, "avatar", avatar["name"])
+		assert.EqualValues(t, 400, avatar["size"])
+		assert.NotContains(t, captured, "raw", "part contents are never logged")
+	})
+
+	t.Run("Binary Summarized And Left Unread", func(t *testing.T) {
+		payload := bytes.Repeat([]byte{0x00, 0xff}, 20_000)
+
+		captured := send(t, "/echo", echo.MIMEOctetStream, payload)
+		assert.Equal(t, map[string]any{"content_type": echo.MIMEOctetStream, "size": int64(len(payload))}, captured)
+		assert.Equal(t, payload, received)
+	})
+
+	t.Run("Untyped Binary Sniffed", func(t *testing.T) {
+		payload := append([]byte("GIF89a"), bytes.Repeat([]byte{0x01, 0x02}, 10_000)...)
+
+		captured := send(t, "/echo", "", payload)
+		assert.Equal(t, echo.MIMEOctetStream, captured["content_type"])
+		assert.Equal(t, payload, received, "the sniffed start is restored")
+	})
+
+	t.Run("Form Parsed And Masked", func(t *testing.T) {
+		captured := send(t, "/echo", echo.MIMEApplicationForm, []byte("name=jane&password=hunter2"))
+		assert.Equal(t, "jane", captured["name"])
+		assert.NotEqual(t, "hunter2", captured["password"])
+	})
+
+	t.Run("Truncated Text Kept As Text", func(t *testing.T) {
+		payload := []byte(strings.Repeat("€", 5_000)) // the capture limit splits a character
+
+		captured := send(t, "/echo", echo.MIMETextPlain, payload)
+		assert.Contains(t, captured, "raw")
+		assert.Equal(t, payload, received)
+	})
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Header capture modes of logger.headers
+const (
+	headerCaptureDeny  = "deny"
+	headerCaptureAllow = "allow"
+)
+
+// defaultAllowedHeaders are recorded in allow mode when logger.headers.allow is empty
+var defaultAllowedHeaders = []string{
+	echo.HeaderAccept,
+	echo.HeaderContentType,
+	echo.HeaderContentLength,
+	echo.HeaderContentEncoding,
+	echo.HeaderXRequestID,
+	"User-Agent",
+}
+
+// headerCapture picks the request and response headers added to wide events, following
+// logger.headers: every header (deny mode, the sensitive ones are masked) or only the
+// allowed ones
+type headerCapture struct {
+	allowed atomic.Pointer[map[string]struct{}] // canonical names, nil in deny mode
+}
+
+// newHeaderCapture reads logger.headers and updates the mode on reload
+func newHeaderCapture(cfg *config.Configuration) *headerCapture {
+	h := &headerCapture{}
+	h.store(cfg.Logger.Headers)
+
+	config.OnChange(func(new config.Configuration) {
+		h.store(new.Logger.Headers)
+	})
+
+	return h
+}
+
+func (h *headerCapture) store(cfg config.LogHeaders) {
+	if cfg.Mode != headerCaptureAllow {
+		h.allowed.Store(nil)
+		return
+	}
+
+	names := cfg.Allow
+	if len(names) == 0 {
+		names = defaultAllowedHeaders
+	}
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+	h.allowed.Store(&allowed)
+}
+
+// capture converts the headers to the map logged on the wide event, with the first value of each
+func (h *headerCapture) capture(headers http.Header) map[string]string {
+	allowed := h.allowed.Load()
+	if allowed == nil {
+		return captureHeaders(headers)
+	}
+
+	result := make(map[string]string, len(*allowed))
+	for name := range *allowed {
+		if values := headers[name]; len(values) > 0 {
+			result[name] = values[0]
+		}
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddlewareHeaders(t *testing.T) {
+	// serve returns the request and response headers of the wide event
+	serve := func(t *testing.T, headers config.LogHeaders) (map[string]string, map[string]string) {
+		t.Helper()
+
+		log := logger.NewTestLogger()
+		e := echo.New()
+		m := middleware.New(e, &config.Configuration{Logger: config.Logger{Headers: headers}})
+		e.Use(m.LoggingMiddleware(log))
+		e.GET("/", func(ctx echo.Context) error {
+			ctx.Response().Header().Set("X-Internal-Trace", "abc")
+			return ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer secret-token")
+		req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Customer-Email", "jane@example.com")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		events := log.EventsWithMessage("Request completed")
+		require.Len(t, events, 1)
+		request, ok := events[0].Fields["request_headers"].(map[string]string)
+		require.True(t, ok)
+		response, ok := events[0].Fields["response_headers"].(map[string]string)
+		require.True(t, ok)
+		return request, response
+	}
+
+	t.Run("Deny List By Default", func(t *testing.T) {
+		request, response := serve(t, config.LogHeaders{})
+
+		assert.Contains(t, request, "X-Customer-Email")
+		assert.NotEqual(t, "Bearer secret-token", request[echo.HeaderAuthorization], "sensitive headers are masked")
+		assert.Contains(t, response, "X-Internal-Trace")
+	})
+
+	t.Run("Allow List", func(t *testing.T) {
+		request, response := serve(t, config.LogHeaders{Mode: "allow", Allow: []string{"accept", "content-type"}})
+
+		assert.Equal(t, map[string]string{echo.HeaderAccept: echo.MIMEApplicationJSON}, request)
+		assert.Equal(t, map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON}, response)
+	})
+
+	t.Run("Default Allow List", func(t *testing.T) {
+		request, response := serve(t, config.LogHeaders{Mode: "allow"})
+
+		assert.Contains(t, request, echo.HeaderAccept)
+		assert.NotContains(t, request, echo.HeaderAuthorization)
+		assert.NotContains(t, request, "X-Customer-Email")
+		assert.Contains(t, response, echo.HeaderXRequestID)
+		assert.NotContains(t, response, "X-Internal-Trace")
+	})
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"cmp"
+	"go-echo-boilerplate/internal/config"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// pathPattern is an entry of a path pattern map such as logger.path_levels
+type pathPattern struct {
+	pattern string
+	prefix  bool // pattern ended with *
+	value   string
+}
+
+// pathPatterns resolves the value configured for a request path, e.g. the log level override
+type pathPatterns struct {
+	current atomic.Pointer[[]pathPattern]
+}
+
+// newPathLevels builds the overrides from logger.path_levels and rebuilds them on reload
+func newPathLevels(cfg *config.Configuration) *pathPatterns {
+	p := &pathPatterns{}
+	p.store(cfg.Logger.PathLevels)
+
+	config.OnChange(func(new config.Configuration) {
+		p.store(new.Logger.PathLevels)
+	})
+
+	return p
+}
+
+// store compiles the patterns, most specific (longest) pattern first
+func (p *pathPatterns) store(values map[string]string) {
+	compiled := make([]pathPattern, 0, len(values))
+	for pattern, value := range values {
+		entry := pathPattern{pattern: pattern, value: value}
+		if trimmed, ok := strings.CutSuffix(pattern, "*"); ok {
+			entry.pattern = trimmed
+			entry.prefix = true
+		}
+		compiled = append(compiled, entry)
+	}
+
+	slices.SortFunc(compiled, func(a, b pathPattern) int {
+		return cmp.Compare(len(b.pattern), len(a.pattern))
+	})
+	p.current.Store(&compiled)
+}
+
+// match returns the value of the most specific pattern matching path, or "" when none matches
+func (p *pathPatterns) match(path string) string {
+	for _, entry := range *p.current.Load() {
+		if entry.prefix && strings.HasPrefix(path, entry.pattern) {
+			return entry.value
+		}
+		if !entry.prefix && path == entry.pattern {
+			return entry.value
+		}
+	}
+	return ""
+}
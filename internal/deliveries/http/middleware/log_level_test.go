@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathLevels(t *testing.T) {
+	levels := newPathLevels(&config.Configuration{Logger: config.Logger{PathLevels: map[string]string{
+		"/api/v1/users/*":      "debug",
+		"/api/v1/users/tokens": "warn",
+		"/api/*":               "info",
+	}}})
+
+	assert.Equal(t, "warn", levels.match("/api/v1/users/tokens"), "exact match is more specific")
+	assert.Equal(t, "debug", levels.match("/api/v1/users/me"))
+	assert.Equal(t, "info", levels.match("/api/v1/orders"))
+	assert.Equal(t, "", levels.match("/health"))
+}
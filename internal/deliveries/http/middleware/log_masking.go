@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"sync/atomic"
+)
+
+// requestMasker masks the captured request and response data, following logger.masking
+type requestMasker struct {
+	current atomic.Pointer[logger.Masker]
+}
+
+// newRequestMasker builds the masker from logger.masking and rebuilds it on reload.
+// An invalid configuration (rejected at startup) keeps the previous masker.
+func newRequestMasker(cfg *config.Configuration) *requestMasker {
+	m := &requestMasker{}
+	m.current.Store(logger.DefaultMasker())
+	m.store(cfg)
+
+	config.OnChange(func(new config.Configuration) {
+		m.store(&new)
+	})
+
+	return m
+}
+
+func (m *requestMasker) store(cfg *config.Configuration) {
+	if masker, err := logger.NewMasker(cfg.Logger.Masking); err == nil {
+		m.current.Store(masker)
+	}
+}
+
+// add masks value and adds it to the wide event under key
+func (m *requestMasker) add(ctx context.Context, key string, value any) {
+	logger.Add(ctx, key, m.current.Load().Mask(value))
+}
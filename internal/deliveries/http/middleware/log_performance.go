@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"sync/atomic"
+	"time"
+
+	gbytes "github.com/labstack/gommon/bytes"
+)
+
+// perfThresholds flags slow requests and large responses, following logger.performance
+type perfThresholds struct {
+	current atomic.Pointer[thresholds]
+}
+
+type thresholds struct {
+	slowRequest   time.Duration // 0 disables
+	largeResponse int64         // 0 disables
+	warn          bool
+}
+
+// perfFlags are the thresholds a request exceeded
+type perfFlags struct {
+	slowRequest   bool
+	largeResponse bool
+}
+
+func (f perfFlags) any() bool {
+	return f.slowRequest || f.largeResponse
+}
+
+// newPerfThresholds reads logger.performance and updates the thresholds on reload.
+// Invalid values (rejected at startup) disable their threshold.
+func newPerfThresholds(cfg *config.Configuration) *perfThresholds {
+	p := &perfThresholds{}
+	p.store(cfg.Logger.Performance)
+
+	config.OnChange(func(new config.Configuration) {
+		p.store(new.Logger.Performance)
+	})
+
+	return p
+}
+
+func (p *perfThresholds) store(cfg config.LogPerformance) {
+	t := &thresholds{warn: cfg.Warn}
+	if duration, err := time.ParseDuration(cfg.SlowRequest); err == nil && duration > 0 {
+		t.slowRequest = duration
+	}
+	if size, err := gbytes.Parse(cfg.LargeResponse); err == nil && size > 0 {
+		t.largeResponse = size
+	}
+	p.current.Store(t)
+}
+
+// check returns the thresholds exceeded by a request
+func (p *perfThresholds) check(duration time.Duration, bytesOut int64) perfFlags {
+	t := p.current.Load()
+	return perfFlags{
+		slowRequest:   t.slowRequest > 0 && duration > t.slowRequest,
+		largeResponse: t.largeResponse > 0 && bytesOut > t.largeResponse,
+	}
+}
+
+// warn emits the separate WARN event of a flagged request when logger.performance.warn is set
+func (p *perfThresholds) warn(log logger.Logger, ctx context.Context, wideEvent *logger.WideEvent, flags perfFlags, duration time.Duration, bytesOut int64) {
+	t := p.current.Load()
+	if !t.warn || !flags.any() {
+		return
+	}
+
+	fields := []logger.Field{
+		logger.String(logger.FieldRequestID, wideEvent.RequestID),
+		logger.String(logger.FieldMethod, wideEvent.Method),
+		logger.String(logger.FieldPath, wideEvent.Path),
+		logger.Int64(logger.FieldDurationMS, duration.Milliseconds()),
+		logger.Int64(logger.FieldBytesOut, bytesOut),
+	}
+	if flags.slowRequest {
+		fields = append(fields,
+			logger.Bool(logger.FieldSlowRequest, true),
+			logger.Int64("slow_request_threshold_ms", t.slowRequest.Milliseconds()),
+		)
+	}
+	if flags.largeResponse {
+		fields = append(fields,
+			logger.Bool(logger.FieldLargeResponse, true),
+			logger.Int64("large_response_threshold_bytes", t.largeResponse),
+		)
+	}
+
+	log.Warn(ctx, "Request exceeded performance thresholds", fields...)
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoggingMiddlewarePerformanceFlags(t *testing.T) {
+	setup := func(performance config.LogPerformance) (*echo.Echo, *observer.ObservedLogs) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		e := echo.New()
+		cfg := &config.Configuration{Logger: config.Logger{SuccessSampleRate: 1e-12, Performance: performance}}
+		m := middleware.New(e, cfg)
+		e.Use(m.LoggingMiddleware(logger.NewZapLogger(zap.New(core))))
+		e.GET("/fast", func(ctx echo.Context) error { return ctx.String(http.StatusOK, "ok") })
+		e.GET("/slow", func(ctx echo.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return ctx.String(http.StatusOK, "ok")
+		})
+		e.GET("/large", func(ctx echo.Context) error { return ctx.String(http.StatusOK, strings.Repeat("x", 2048)) })
+		return e, logs
+	}
+
+	serve := func(e *echo.Echo, path string) {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	t.Run("Flags And Keeps Events", func(t *testing.T) {
+		e, logs := setup(config.LogPerformance{SlowRequest: "10ms", LargeResponse: "1KB"})
+		serve(e, "/fast")
+		serve(e, "/slow")
+		serve(e, "/large")
+
+		assert.Equal(t, 0, logs.FilterField(zap.String("path", "/fast")).Len(), "unflagged events are still sampled")
+
+		slow := logs.FilterField(zap.Bool("slow_request", true))
+		require.Equal(t, 1, slow.Len(), "flagged events are never sampled out")
+		assert.Equal(t, "/slow", slow.All()[0].ContextMap()["path"])
+		assert.NotContains(t, slow.All()[0].ContextMap(), "large_response")
+
+		large := logs.FilterField(zap.Bool("large_response", true))
+		require.Equal(t, 1, large.Len())
+		assert.Equal(t, "/large", large.All()[0].ContextMap()["path"])
+
+		assert.Equal(t, 0, logs.FilterMessage("Request exceeded performance thresholds").Len())
+	})
+
+	t.Run("Warn Event", func(t *testing.T) {
+		e, logs := setup(config.LogPerformance{SlowRequest: "10ms", Warn: true})
+		serve(e, "/slow")
+		serve(e, "/large")
+
+		warnings := logs.FilterMessage("Request exceeded performance thresholds")
+		require.Equal(t, 1, warnings.Len())
+		assert.Equal(t, zapcore.WarnLevel, warnings.All()[0].Level)
+		assert.Equal(t, "/slow", warnings.All()[0].ContextMap()["path"])
+		assert.Equal(t, int64(10), warnings.All()[0].ContextMap()["slow_request_threshold_ms"])
+	})
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// successSampler decides which successful wide events are emitted, following logger.success_sample_rate
+type successSampler struct {
+	rate atomic.Uint64 // math.Float64bits of the rate
+}
+
+// newSuccessSampler reads the rate from logger.success_sample_rate and updates it on reload
+func newSuccessSampler(cfg *config.Configuration) *successSampler {
+	s := &successSampler{}
+	s.store(cfg.Logger.SuccessSampleRate)
+
+	config.OnChange(func(new config.Configuration) {
+		s.store(new.Logger.SuccessSampleRate)
+	})
+
+	return s
+}
+
+// store sets the rate; values outside (0, 1) emit every event
+func (s *successSampler) store(rate float64) {
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+	s.rate.Store(math.Float64bits(rate))
+}
+
+// sample reports whether a successful event is emitted, and the rate it was sampled at
+// (1 when sampling is off)
+func (s *successSampler) sample() (bool, float64) {
+	rate := math.Float64frombits(s.rate.Load())
+	if rate >= 1 {
+		return true, 1
+	}
+	return rand.Float64() < rate, rate
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoggingMiddlewareSampling(t *testing.T) {
+	setup := func(rate float64) (*echo.Echo, *observer.ObservedLogs) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		e := echo.New()
+		cfg := &config.Configuration{Logger: config.Logger{SuccessSampleRate: rate}}
+		m := middleware.New(e, cfg)
+		e.Use(m.LoggingMiddleware(logger.NewZapLogger(zap.New(core))))
+		e.GET("/ok", func(ctx echo.Context) error { return ctx.String(http.StatusOK, "ok") })
+		e.GET("/bad", func(ctx echo.Context) error { return ctx.String(http.StatusBadRequest, "bad") })
+		e.GET("/fail", func(ctx echo.Context) error { return ctx.String(http.StatusInternalServerError, "fail") })
+		return e, logs
+	}
+
+	serve := func(e *echo.Echo, path string, times int) {
+		for i := 0; i < times; i++ {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+	}
+
+	t.Run("Drops Successful Events", func(t *testing.T) {
+		e, logs := setup(1e-12)
+		serve(e, "/ok", 50)
+		serve(e, "/bad", 3)
+		serve(e, "/fail", 2)
+
+		assert.Equal(t, 0, logs.FilterField(zap.Int("status_code", http.StatusOK)).Len())
+		assert.Equal(t, 3, logs.FilterField(zap.Int("status_code", http.StatusBadRequest)).Len())
+		assert.Equal(t, 2, logs.FilterField(zap.Int("status_code", http.StatusInternalServerError)).Len())
+	})
+
+	t.Run("Marks Sampled Events", func(t *testing.T) {
+		e, logs := setup(0.999999999)
+		serve(e, "/ok", 5)
+
+		sampled := logs.FilterField(zap.Bool("sampled", true))
+		assert.Equal(t, 5, sampled.Len())
+		assert.Equal(t, 0.999999999, sampled.All()[0].ContextMap()["sample_rate"])
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		e, logs := setup(0)
+		serve(e, "/ok", 5)
+
+		assert.Equal(t, 5, logs.FilterField(zap.Int("status_code", http.StatusOK)).Len())
+		assert.Equal(t, 0, logs.FilterFieldKey("sampled").Len())
+	})
+}
@@ -11,7 +11,6 @@ import (
 	"context"
 	"encoding/json"
 	"go-echo-boilerplate/internal/pkg/logger"
-	"io"
 	"net/http"
 	"os"
 	"reflect"
@@ -39,7 +38,23 @@ import (
 // Thread Safety:
 // The WideEvent is stored in context.Context with internal mutex protection,
 // allowing handlers to safely enrich it from multiple goroutines.
+//
+// Captured data is masked with logger.masking. logger.headers can restrict the captured
+// headers to an allow-list, and logger.body_capture when request bodies are captured.
+// Requests matching logger.path_levels are logged with that level instead of the global one.
+// Successful requests are sampled at logger.success_sample_rate; errors and warnings are always logged.
+// Requests over the logger.performance thresholds are flagged, and never sampled out.
+// With logger.access_log, a compact access log line is emitted as well.
+// Handlers and services get a request-scoped logger with logger.FromContext.
 func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
+	levels := newPathLevels(m.config)
+	sampler := newSuccessSampler(m.config)
+	masker := newRequestMasker(m.config)
+	perf := newPerfThresholds(m.config)
+	access := newAccessLog(m.config)
+	bodies := newBodyCapture(m.config)
+	headers := newHeaderCapture(m.config)
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ectx echo.Context) error {
 			start := time.Now()
@@ -48,8 +63,11 @@ func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 			bcw := &bodyCapturingWriter{ResponseWriter: ectx.Response().Writer}
 			ectx.Response().Writer = bcw
 
+			// Correlation context of an upstream service, if any (see logger.InjectHeaders)
+			correlation := logger.ExtractHeaders(ectx.Request().Header)
+
 			// Generate or get request ID
-			requestID := ectx.Request().Header.Get("X-Request-ID")
+			requestID := correlation.RequestID
 			if requestID == "" {
 				requestID = uuid.New().String()
 			}
@@ -72,6 +90,21 @@ func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 			ctx = logger.WithWideEvent(ctx, wideEvent)
 			ctx = logger.WithRequestID(ctx, requestID)
 
+			// Keep the upstream trace ID and tenant, so outbound calls propagate them further
+			if correlation.TraceID != "" {
+				ctx = logger.WithTraceID(ctx, correlation.TraceID)
+				wideEvent.SetTraceID(correlation.TraceID)
+			}
+			if correlation.Tenant != "" {
+				ctx = logger.WithTenant(ctx, correlation.Tenant)
+				logger.Add(ctx, "tenant", correlation.Tenant)
+			}
+
+			// Apply the per-path log level override (validated at startup)
+			if override := levels.match(ectx.Request().URL.Path); override != "" {
+				ctx, _ = logger.WithLevel(ctx, override)
+			}
+
 			// Extract and set user context if available
 			if userID := extractUserID(ectx); userID != "" {
 				ctx = logger.WithUserID(ctx, userID)
@@ -80,6 +113,9 @@ func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 				})
 			}
 
+			// Request-scoped logger preset with the request metadata, see logger.FromContext
+			ctx = logger.WithLogger(ctx, log.WithContext(ctx))
+
 			// Update request with enriched context
 			ectx.SetRequest(ectx.Request().WithContext(ctx))
 
@@ -87,40 +123,36 @@ func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 			// Capture Request Data (with masking)
 			// ================================================================
 
-			// Capture request headers (masked)
-			reqHeaders := captureHeaders(ectx.Request().Header)
-			logger.AddSafe(ctx, "request_headers", reqHeaders)
+			// Capture request headers (all of them masked, or the allowed ones)
+			reqHeaders := headers.capture(ectx.Request().Header)
+			masker.add(ctx, "request_headers", reqHeaders)
 
-			// Capture request body (masked)
-			reqBody := captureRequestBody(ectx)
-			if reqBody != nil {
-				logger.AddSafe(ctx, "request_body", reqBody)
-			}
+			// Buffer the request body; it is parsed and masked only if the event is emitted
+			reqBody := bodies.read(ectx)
 
 			// Capture path parameters (masked)
 			pathParams := capturePathParams(ectx)
 			if len(pathParams) > 0 {
-				logger.AddSafe(ctx, "request_params", pathParams)
+				masker.add(ctx, "request_params", pathParams)
 			}
 
 			// Capture query parameters (masked)
 			queryParams := captureQueryParams(ectx)
 			if len(queryParams) > 0 {
-				logger.AddSafe(ctx, "request_query", queryParams)
+				masker.add(ctx, "request_query", queryParams)
 			}
 
 			// Capture cookies (masked)
 			cookies := captureCookies(ectx)
 			if len(cookies) > 0 {
-				logger.AddSafe(ctx, "request_cookies", cookies)
+				masker.add(ctx, "request_cookies", cookies)
 			}
 
 			// Capture system metadata
 			logger.AddMap(ctx, map[string]any{
-				"host":       ectx.Request().Host,
-				"ip":         ectx.RealIP(),
-				"pid":        os.Getpid(),
-				"user_agent": ectx.Request().UserAgent(),
+				"host": ectx.Request().Host,
+				"ip":   ectx.RealIP(),
+				"pid":  os.Getpid(),
 			})
 
 			// Capture traceparent if available (W3C Trace Context)
@@ -128,16 +160,13 @@ func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 				logger.Add(ctx, "traceparent", traceparent)
 			}
 
-			// Capture trace ID (X-Trace-ID or traceparent)
-			if traceID := ectx.Request().Header.Get("X-Trace-ID"); traceID != "" {
-				wideEvent.SetTraceID(traceID)
-				logger.Add(ctx, "trace_id", traceID)
-			}
-
 			// Set infrastructure metadata
 			logger.AddMap(ctx, map[string]any{
 				"service":     m.config.Application.Name,
 				"version":     m.config.Application.Version,
+				"commit":      m.config.Application.Commit,
+				"build_time":  m.config.Application.BuildTime,
+				"go_version":  m.config.Application.GoVersion,
 				"environment": m.config.Application.Environment,
 			})
 
@@ -172,34 +201,38 @@ func (m *Middleware) LoggingMiddleware(log logger.Logger) echo.MiddlewareFunc {
 			// Capture Response Data (with masking)
 			// ================================================================
 
-			// Capture response headers (masked)
-			respHeaders := captureHeaders(ectx.Response().Header())
-			logger.AddSafe(ctx, "response_headers", respHeaders)
+			// Capture response headers (all of them masked, or the allowed ones)
+			respHeaders := headers.capture(ectx.Response().Header())
+			masker.add(ctx, "response_headers", respHeaders)
 
 			// Capture response body
 			respBody := captureResponseBody(bcw)
 			if respBody != nil {
-				logger.AddSafe(ctx, "response_body", respBody)
+				masker.add(ctx, "response_body", respBody)
 			}
 
 			logger.AddMap(ctx, map[string]any{
 				"response_status": ectx.Response().Status,
 				"response_size":   ectx.Response().Size,
 			})
+			if encoding := ectx.Response().Header().Get(echo.HeaderContentEncoding); encoding != "" {
+				logger.Add(ctx, "response_encoding", encoding)
+			}
 
 			// Calculate duration and severity
 			duration := time.Since(start)
 			severity := determineSeverity(ectx.Response().Status)
-			logger.AddMap(ctx, map[string]any{
-				// "duration_ms": duration.Milliseconds(),
-				"severity": severity,
-			})
 
 			// ================================================================
-			// Emit Canonical Log Line
+			// Emit Access Log and Canonical Log Line
 			// ================================================================
 
-			emitWideEvent(log, ctx, wideEvent, ectx, duration, severity, err)
+			access.emit(log, ctx, wideEvent, ectx.Response().Status, duration)
+
+			emitWideEvent(log, sampler, perf, masker, ctx, wideEvent, ectx, reqBody, bcw.size(), duration, severity, err)
+
+			// Recycle the event's maps, late writes from goroutines holding ctx are dropped
+			wideEvent.Release()
 
 			return err
 		}
@@ -221,40 +254,6 @@ func captureHeaders(headers map[string][]string) map[string]string {
 	return result
 }
 
-// captureRequestBody reads and captures request body (with size limit)
-func captureRequestBody(c echo.Context) map[string]any {
-	const maxBodySize = 10 * 1024 // 10KB limit
-
-	if c.Request().Body == nil {
-		return nil
-	}
-
-	// Read body
-	bodyBytes, err := io.ReadAll(io.LimitReader(c.Request().Body, maxBodySize))
-	if err != nil {
-		return nil
-	}
-
-	// Restore body for handler
-	c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-	// Try to parse as JSON
-	var bodyData map[string]any
-	if err := json.Unmarshal(bodyBytes, &bodyData); err == nil {
-		return bodyData
-	}
-
-	// If not JSON, return as string (truncated if too long)
-	bodyStr := string(bodyBytes)
-	if len(bodyStr) > 1000 {
-		bodyStr = bodyStr[:1000] + "...(truncated)"
-	}
-
-	return map[string]any{
-		"raw": bodyStr,
-	}
-}
-
 // capturePathParams captures URL path parameters
 func capturePathParams(c echo.Context) map[string]string {
 	params := make(map[string]string)
@@ -296,20 +295,48 @@ func captureCookies(c echo.Context) map[string]string {
 const maxResponseCapture = 10 * 1024 // 10 KB
 
 // bodyCapturingWriter is a thread-safe response writer that tees the first
-// maxResponseCapture bytes of every write into an internal buffer. It also
-// implements http.Flusher so that middleware like gzip/compress continues to
-// work correctly.
+// maxResponseCapture bytes of every write into an internal buffer, and counts
+// the bytes sent to the client. It also implements http.Flusher so that
+// middleware like gzip/compress continues to work correctly.
+//
+// When the response is compressed (see CompressMiddleware), the writes it sees
+// are encoded, so the compressor feeds it the uncompressed bytes with capture
+// instead and the size stays the compressed one.
 type bodyCapturingWriter struct {
 	http.ResponseWriter
 	mu       sync.Mutex
 	buf      bytes.Buffer
 	captured int
+	encoded  bool
+	written  int64
 }
 
 // Write tees up to maxResponseCapture bytes into the internal buffer, then
 // forwards the full slice to the underlying ResponseWriter.
 func (w *bodyCapturingWriter) Write(b []byte) (int, error) {
 	w.mu.Lock()
+	if !w.encoded {
+		w.teeLocked(b)
+	}
+	w.mu.Unlock()
+
+	n, err := w.ResponseWriter.Write(b)
+
+	w.mu.Lock()
+	w.written += int64(n)
+	w.mu.Unlock()
+	return n, err
+}
+
+// capture tees the uncompressed bytes of an encoded response
+func (w *bodyCapturingWriter) capture(b []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.encoded = true
+	w.teeLocked(b)
+}
+
+func (w *bodyCapturingWriter) teeLocked(b []byte) {
 	remaining := maxResponseCapture - w.captured
 	if remaining > 0 {
 		if remaining > len(b) {
@@ -318,9 +345,13 @@ func (w *bodyCapturingWriter) Write(b []byte) (int, error) {
 		w.buf.Write(b[:remaining])
 		w.captured += remaining
 	}
-	w.mu.Unlock()
+}
 
-	return w.ResponseWriter.Write(b)
+// size returns the number of bytes sent to the client
+func (w *bodyCapturingWriter) size() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written
 }
 
 // Flush implements http.Flusher, forwarding the call when the underlying
@@ -331,6 +362,12 @@ func (w *bodyCapturingWriter) Flush() {
 	}
 }
 
+// Unwrap lets http.ResponseController reach the connection, e.g. for
+// response.ExtendWriteDeadline
+func (w *bodyCapturingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // bytes returns a safe copy of the captured buffer contents.
 func (w *bodyCapturingWriter) bytes() []byte {
 	w.mu.Lock()
@@ -391,18 +428,29 @@ func determineSeverity(statusCode int) string {
 
 // emitWideEvent emits a single canonical log line with all request context.
 // This is called once per request at the end of the middleware chain.
+//
+// Successful events may be dropped by the sampler; emitted ones then carry
+// sampled=true and sample_rate so counts can be scaled back up. The buffered request
+// body is only parsed once the event is known to be emitted.
+//
+// bytesOut is the size sent on the wire, i.e. after compression; response_size in
+// the business context is the uncompressed size.
 func emitWideEvent(
 	log logger.Logger,
+	sampler *successSampler,
+	perf *perfThresholds,
+	masker *requestMasker,
 	ctx context.Context,
 	wideEvent *logger.WideEvent,
 	c echo.Context,
+	reqBody pendingBody,
+	bytesOut int64,
 	duration time.Duration,
 	severity string,
 	handlerErr error,
 ) {
 	// Determine outcome and status
 	statusCode := c.Response().Status
-	bytesOut := c.Response().Size
 
 	// Get error from wide event (may have been set by handler/service)
 	errCtx := wideEvent.GetError()
@@ -422,46 +470,42 @@ func emitWideEvent(
 		outcome = "error"
 	}
 
-	// Build log message
-	msg := "Request completed"
+	// Flag performance regressions
+	flags := perf.check(duration, bytesOut)
+	perf.warn(log, ctx, wideEvent, flags, duration, bytesOut)
 
-	// Pre-allocate fields slice with estimated capacity
-	fields := make([]logger.Field, 0, 20)
-
-	// Add core request/response fields
-	fields = append(fields,
-		logger.String("request_id", wideEvent.RequestID),
-		logger.String("method", wideEvent.Method),
-		logger.String("path", wideEvent.Path),
-		logger.Int("status_code", statusCode),
-		logger.Int64("duration_ms", duration.Milliseconds()),
-		logger.Int64("bytes_out", bytesOut),
-		logger.String("outcome", outcome),
-		logger.String("severity", severity),
-		logger.String("remote_ip", wideEvent.RemoteIP),
-		logger.String("user_agent", wideEvent.UserAgent),
-	)
-
-	// Add optional fields
-	if wideEvent.TraceID != "" {
-		fields = append(fields, logger.String("trace_id", wideEvent.TraceID))
+	// Sample successful requests only, never errors, warnings, or flagged requests
+	sampleRate := 1.0
+	if outcome == "success" && severity == "INFO" && !flags.any() {
+		var keep bool
+		if keep, sampleRate = sampler.sample(); !keep {
+			return
+		}
 	}
 
-	if user := wideEvent.GetUser(); user != nil {
-		fields = append(fields, logger.Any("user", user))
-	}
+	reqBody.attach(ctx, masker, c.Request(), statusCode)
 
-	// Add business data fields directly (thread-safe copy)
-	// Merge business data directly instead of nesting under "business_data"
-	if businessData := wideEvent.GetBusinessData(); len(businessData) > 0 {
-		for key, value := range businessData {
-			fields = append(fields, logger.Any(key, value))
-		}
-	}
+	// Build the canonical log line from the versioned schema, in a recycled buffer: loggers
+	// don't keep the fields once they return
+	buffer := fieldBuffers.Get().(*[]logger.Field)
+	defer func() {
+		clear(*buffer) // drop the references to the request's values
+		*buffer = (*buffer)[:0]
+		fieldBuffers.Put(buffer)
+	}()
 
-	if errCtx != nil {
-		fields = append(fields, logger.Any("error", errCtx))
-	}
+	msg := "Request completed"
+	fields := wideEvent.AppendFields((*buffer)[:0], logger.WideEventCore{
+		StatusCode:    statusCode,
+		DurationMS:    duration.Milliseconds(),
+		BytesOut:      bytesOut,
+		Outcome:       outcome,
+		Severity:      severity,
+		SampleRate:    sampleRate,
+		SlowRequest:   flags.slowRequest,
+		LargeResponse: flags.largeResponse,
+	}, errCtx)
+	*buffer = fields
 
 	// Log at appropriate level based on severity
 	switch severity {
@@ -474,6 +518,14 @@ func emitWideEvent(
 	}
 }
 
+// fieldBuffers recycles the field slices of the canonical log lines, see emitWideEvent
+var fieldBuffers = sync.Pool{
+	New: func() any {
+		buffer := make([]logger.Field, 0, 64)
+		return &buffer
+	},
+}
+
 // extractUserID extracts user ID from context or JWT.
 // Customize this based on your authentication setup.
 func extractUserID(c echo.Context) string {
@@ -486,7 +538,7 @@ func extractUserID(c echo.Context) string {
 	// }
 
 	// Example: from custom header
-	if userID := c.Request().Header.Get("X-User-ID"); userID != "" {
+	if userID := c.Request().Header.Get(logger.HeaderUserID); userID != "" {
 		return userID
 	}
 
@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddlewareRequestLogger(t *testing.T) {
+	log := logger.NewTestLogger()
+	e := echo.New()
+	m := middleware.New(e, &config.Configuration{})
+	e.Use(m.LoggingMiddleware(log))
+	e.GET("/", func(ctx echo.Context) error {
+		// e.g. a goroutine that outlives the request context
+		logger.FromContext(ctx.Request().Context()).Info(context.Background(), "from handler")
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.HeaderRequestID, "req-1")
+	req.Header.Set(logger.HeaderUserID, "user-1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := log.EventsWithMessage("from handler")
+	require.Len(t, events, 1)
+	assert.Equal(t, "req-1", events[0].Fields["request_id"])
+	assert.Equal(t, "user-1", events[0].Fields["user_id"])
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/loginguard"
+	"go-echo-boilerplate/internal/pkg/response"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderCaptchaToken carries the answer to the challenge LoginThrottle requires
+const HeaderCaptchaToken = "X-Captcha-Token"
+
+// loginIdentifier is the part of a login request LoginThrottle counts failures for
+type loginIdentifier struct {
+	Email       string `json:"email"`
+	PhoneNumber struct {
+		Number string `json:"number"`
+	} `json:"phoneNumber"`
+	Username string `json:"username"`
+}
+
+// LoginThrottle slows down credential guessing on a login route with loginguard.Default(),
+// following login_throttle. Attempts for an identifier (the email, phone number, or username
+// of the JSON body) or from an IP with too many recent failures are rejected with 429 and
+// Retry-After; past login_throttle.challenge_after failures the X-Captcha-Token header must
+// answer the challenge, or the attempt is rejected with 428. The handler reports the outcome
+// with loginguard.Failed and loginguard.Succeeded.
+//
+// Store errors let the attempt through. It is a no-op when no guard is installed.
+//
+// Usage:
+//
+//	noBearerRoute.POST("/tokens", h.GetTokens, middleware.LoginThrottle())
+func LoginThrottle() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			guard := loginguard.Default()
+			if guard == nil {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			requestCtx := req.Context()
+			identifier := peekLoginIdentifier(req)
+			ip := ctx.RealIP()
+
+			decision, err := guard.Check(requestCtx, ip, identifier)
+			if err != nil {
+				logger.FromContext(requestCtx).Warn(requestCtx, "login throttle unavailable", logger.Error(err))
+				return next(ctx)
+			}
+
+			if !decision.Allowed {
+				logger.Add(requestCtx, "login_throttled", decision.Reason)
+				seconds := int(math.Ceil(decision.RetryAfter.Seconds()))
+				ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+				return response.Error(ctx, errorc.ErrorTooManyAttempts)
+			}
+
+			if decision.ChallengeRequired {
+				logger.Add(requestCtx, "login_challenge_required", true)
+				passed, err := guard.VerifyChallenge(requestCtx, req.Header.Get(HeaderCaptchaToken), ip)
+				if err != nil {
+					logger.FromContext(requestCtx).Warn(requestCtx, "login challenge unavailable", logger.Error(err))
+				} else if !passed {
+					return response.Error(ctx, errorc.ErrorChallengeRequired)
+				}
+			}
+
+			attempt := guard.NewAttempt(ip, identifier)
+			ctx.SetRequest(req.WithContext(loginguard.WithAttempt(requestCtx, attempt)))
+			return next(ctx)
+		}
+	}
+}
+
+// peekLoginIdentifier reads the identifier of a JSON login request and restores the body
+// for the handler. Bodies it cannot read count for the IP only.
+func peekLoginIdentifier(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var login loginIdentifier
+	if err := json.Unmarshal(body, &login); err != nil {
+		return ""
+	}
+	if identifier := loginguard.NormalizeIdentifier(login.Email, login.PhoneNumber.Number); identifier != "" {
+		return identifier
+	}
+	if username := strings.ToLower(strings.TrimSpace(login.Username)); username != "" {
+		// Prefixed, so a numeric username never shares the failures of a phone number
+		return "@" + username
+	}
+	return ""
+}
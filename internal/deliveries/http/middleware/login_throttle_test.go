@@ -0,0 +1,126 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/loginguard"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubChallenger struct{ answer string }
+
+func (c stubChallenger) Verify(_ context.Context, response, _ string) (bool, error) {
+	return response == c.answer, nil
+}
+
+func TestLoginThrottle(t *testing.T) {
+	newServer := func(guard *loginguard.Guard) *echo.Echo {
+		loginguard.SetDefault(guard)
+		t.Cleanup(func() { loginguard.SetDefault(nil) })
+
+		e := echo.New()
+		e.POST("/tokens", func(ctx echo.Context) error {
+			var body struct {
+				Password string `json:"password"`
+			}
+			if err := ctx.Bind(&body); err != nil {
+				return err
+			}
+			if body.Password != "secret" {
+				require.NoError(t, loginguard.Failed(ctx.Request().Context()))
+				return ctx.NoContent(http.StatusUnauthorized)
+			}
+			require.NoError(t, loginguard.Succeeded(ctx.Request().Context()))
+			return ctx.NoContent(http.StatusOK)
+		}, middleware.LoginThrottle())
+		return e
+	}
+
+	login := func(e *echo.Echo, ip, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Passes Through Without A Guard", func(t *testing.T) {
+		e := newServer(nil)
+		for range 5 {
+			assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"email":"a@example.com","password":"x"}`).Code)
+		}
+	})
+
+	t.Run("Delays The Identifier From Any IP", func(t *testing.T) {
+		e := newServer(loginguard.New(cache.NewMemoryStore(100), loginguard.Policy{FreeFailures: 1, BaseDelay: time.Minute}, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"email":"a@example.com","password":"x"}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"email":"a@example.com","password":"x"}`).Code)
+
+		rec := login(e, "10.0.0.2", `{"email":" A@Example.com ","password":"secret"}`)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "60", rec.Header().Get(echo.HeaderRetryAfter))
+		assert.Contains(t, rec.Body.String(), "TOO_MANY_ATTEMPTS")
+
+		// Other identifiers from another IP are unaffected
+		assert.Equal(t, http.StatusOK, login(e, "10.0.0.2", `{"email":"b@example.com","password":"secret"}`).Code)
+	})
+
+	t.Run("Counts Usernames As Identifiers", func(t *testing.T) {
+		e := newServer(loginguard.New(cache.NewMemoryStore(100), loginguard.Policy{FreeFailures: 1, BaseDelay: time.Minute}, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"username":"john.doe","password":"x"}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.2", `{"username":"John.Doe","password":"x"}`).Code)
+
+		assert.Equal(t, http.StatusTooManyRequests, login(e, "10.0.0.3", `{"username":"john.doe","password":"secret"}`).Code)
+		assert.Equal(t, http.StatusOK, login(e, "10.0.0.3", `{"username":"jane.doe","password":"secret"}`).Code)
+	})
+
+	t.Run("Delays The IP For Any Identifier", func(t *testing.T) {
+		e := newServer(loginguard.New(cache.NewMemoryStore(100), loginguard.Policy{FreeFailures: 1, BaseDelay: time.Minute}, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"email":"a@example.com","password":"x"}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"phoneNumber":{"number":"+62 812"},"password":"x"}`).Code)
+
+		assert.Equal(t, http.StatusTooManyRequests, login(e, "10.0.0.1", `{"email":"c@example.com","password":"secret"}`).Code)
+	})
+
+	t.Run("Success Forgets The Identifier", func(t *testing.T) {
+		e := newServer(loginguard.New(cache.NewMemoryStore(100), loginguard.Policy{FreeFailures: 1, BaseDelay: time.Minute}, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"email":"a@example.com","password":"x"}`).Code)
+		assert.Equal(t, http.StatusOK, login(e, "10.0.0.2", `{"email":"a@example.com","password":"secret"}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.3", `{"email":"a@example.com","password":"x"}`).Code)
+		assert.Equal(t, http.StatusOK, login(e, "10.0.0.4", `{"email":"a@example.com","password":"secret"}`).Code)
+	})
+
+	t.Run("Requires The Challenge", func(t *testing.T) {
+		policy := loginguard.Policy{FreeFailures: 5, ChallengeAfter: 1}
+		e := newServer(loginguard.New(cache.NewMemoryStore(100), policy, stubChallenger{answer: "solved"}))
+
+		assert.Equal(t, http.StatusUnauthorized, login(e, "10.0.0.1", `{"email":"a@example.com","password":"x"}`).Code)
+
+		rec := login(e, "10.0.0.1", `{"email":"a@example.com","password":"secret"}`)
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+		assert.Contains(t, rec.Body.String(), "CHALLENGE_REQUIRED")
+
+		assert.Equal(t, http.StatusPreconditionRequired,
+			login(e, "10.0.0.1", `{"email":"a@example.com","password":"secret"}`, middleware.HeaderCaptchaToken, "wrong").Code)
+		assert.Equal(t, http.StatusOK,
+			login(e, "10.0.0.1", `{"email":"a@example.com","password":"secret"}`, middleware.HeaderCaptchaToken, "solved").Code)
+	})
+}
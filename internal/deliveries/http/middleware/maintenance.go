@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/maintenance"
+	"go-echo-boilerplate/internal/pkg/response"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// operationalRoutes are the route prefixes served in maintenance mode and never shed: the
+// health checks keep the instance in the load balancer, /version tells the deploys what runs,
+// the admin routes operate it
+var operationalRoutes = []string{"/health", "/version", "/admin", "/debug"}
+
+// MaintenanceMiddleware answers 503 with Retry-After and the message of maintenance.Current()
+// while it is enabled, on every route but /health, /version, /admin, and /debug.
+func (m *Middleware) MaintenanceMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			mode := maintenance.Current()
+			if !mode.Enabled || operationalRoute(ctx.Request().URL.Path) {
+				return next(ctx)
+			}
+
+			logger.Add(ctx.Request().Context(), "maintenance", true)
+			seconds := int(math.Ceil(mode.RetryAfter.Seconds()))
+			ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+			return response.Error(ctx, errorc.Error(errorc.ErrorMaintenance, mode.Message))
+		}
+	}
+}
+
+// operationalRoute reports whether path is one of operationalRoutes or below it
+func operationalRoute(path string) bool {
+	for _, prefix := range operationalRoutes {
+		if rest, ok := strings.CutPrefix(path, prefix); ok && (rest == "" || rest[0] == '/') {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/maintenance"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	t.Cleanup(func() { maintenance.Set(maintenance.Mode{}) })
+
+	e := echo.New()
+	m := middleware.New(e, &config.Configuration{})
+	e.Use(m.MaintenanceMiddleware())
+	for _, path := range []string{"/api/v1/users", "/health", "/health/detail", "/healthz", "/admin/maintenance"} {
+		e.GET(path, func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusOK)
+		})
+	}
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	maintenance.Set(maintenance.Mode{})
+	assert.Equal(t, http.StatusOK, serve("/api/v1/users").Code)
+
+	maintenance.Set(maintenance.Mode{Enabled: true, RetryAfter: 90 * time.Second, Message: "Migrating the database"})
+	rec := serve("/api/v1/users")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), `"MAINTENANCE"`)
+	assert.Contains(t, rec.Body.String(), "Migrating the database")
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve("/healthz").Code, "not a health route")
+	for _, path := range []string{"/health", "/health/detail", "/admin/maintenance"} {
+		assert.Equal(t, http.StatusOK, serve(path).Code, path)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/mockapi"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderPreferenceApplied tells the client which preference of its Prefer header was applied
+const HeaderPreferenceApplied = "Preference-Applied"
+
+// MockMiddleware answers the documented operations with their example response, for
+// application.mode mock. It runs after routing and before the group middlewares, so the
+// examples are served without the API key or an access token; the routes without a
+// documented JSON response, and /health, /version, /admin, and /debug, reach their handlers.
+//
+// The lowest documented 2xx status is answered, another documented status is asked for with
+// the Prefer header, e.g. "Prefer: code=404", confirmed by the Preference-Applied header.
+func (m *Middleware) MockMiddleware(responses *mockapi.Responses) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if operationalRoute(req.URL.Path) {
+				return next(ctx)
+			}
+
+			preferred := preferredStatus(req.Header.Values("Prefer"))
+			response, ok := responses.Find(req.Method, ctx.Path(), preferred)
+			if !ok {
+				return next(ctx)
+			}
+
+			logger.Add(req.Context(), "mock", true)
+			if preferred != 0 {
+				ctx.Response().Header().Set(HeaderPreferenceApplied, "code="+strconv.Itoa(preferred))
+			}
+			if response.Body == nil {
+				return ctx.NoContent(response.Status)
+			}
+			return ctx.JSONBlob(response.Status, response.Body)
+		}
+	}
+}
+
+// preferredStatus is the status of the code preference of the Prefer headers, 0 without one
+func preferredStatus(headers []string) int {
+	for _, header := range headers {
+		for preference := range strings.SplitSeq(header, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(preference), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "code") {
+				continue
+			}
+			status, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+			if err == nil && status >= http.StatusContinue && status <= 599 {
+				return status
+			}
+		}
+	}
+	return 0
+}
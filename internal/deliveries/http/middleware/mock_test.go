@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/mockapi"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockMiddleware(t *testing.T) {
+	responses, err := mockapi.New()
+	require.NoError(t, err)
+
+	e := echo.New()
+	m := middleware.New(e, &config.Configuration{})
+	e.Use(m.MockMiddleware(responses))
+	handler := func(ctx echo.Context) error {
+		return ctx.String(http.StatusTeapot, "handler")
+	}
+	e.POST("/api/v1/users", handler)
+	e.GET("/admin/quotas/overrides/:client", handler)
+	e.GET("/api/v1/users/undocumented", handler)
+
+	serve := func(method, path, prefer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if prefer != "" {
+			req.Header.Set("Prefer", prefer)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/api/v1/users", "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	assert.Contains(t, rec.Body.String(), `"data"`)
+	assert.Empty(t, rec.Header().Get(middleware.HeaderPreferenceApplied))
+
+	rec = serve(http.MethodPost, "/api/v1/users", `respond-async, code="409"`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "code=409", rec.Header().Get(middleware.HeaderPreferenceApplied))
+
+	assert.Equal(t, http.StatusTeapot, serve(http.MethodPost, "/api/v1/users", "code=418").Code, "undocumented status")
+	assert.Equal(t, http.StatusTeapot, serve(http.MethodGet, "/api/v1/users/undocumented", "").Code, "undocumented route")
+	assert.Equal(t, http.StatusTeapot, serve(http.MethodGet, "/admin/quotas/overrides/partner", "").Code, "operational route")
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	gbytes "github.com/labstack/gommon/bytes"
+)
+
+// DefaultMaxBodySize is applied when server.max_body_size is not configured.
+const DefaultMaxBodySize int64 = 1 << 20 // 1MB
+
+// BodyLimitMiddleware rejects requests whose body exceeds server.max_body_size with 413.
+// Requests announcing a Content-Length are rejected before reading; other bodies (chunked, or
+// with an understated length) are streamed to the handler through http.MaxBytesReader, whose
+// *http.MaxBytesError past the limit answers 413 too (see errorc.GetResponse).
+func (m *Middleware) BodyLimitMiddleware(config *config.Configuration) echo.MiddlewareFunc {
+	limit := DefaultMaxBodySize
+	if config != nil && config.Server.MaxBodySize != "" {
+		// Validated at startup
+		if parsed, err := gbytes.Parse(config.Server.MaxBodySize); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if req.Body == nil || req.Body == http.NoBody {
+				return next(ctx)
+			}
+
+			if req.ContentLength > limit {
+				return rejectBodyTooLarge(ctx, limit)
+			}
+
+			req.Body = http.MaxBytesReader(ctx.Response(), req.Body, limit)
+			return next(ctx)
+		}
+	}
+}
+
+func rejectBodyTooLarge(ctx echo.Context, limit int64) error {
+	logger.AddMap(ctx.Request().Context(), map[string]any{
+		"rejection_reason": "body_too_large",
+		"body_limit_bytes": limit,
+		"content_length":   ctx.Request().ContentLength,
+	})
+	return response.Error(ctx, errorc.ErrorPayloadTooLarge)
+}
+
+// JSONContentTypeMiddleware rejects requests that carry a body with a Content-Type
+// other than application/json (or a +json suffix type) with 415.
+// Requests without a body (GET, DELETE, empty POST) pass through.
+func (m *Middleware) JSONContentTypeMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if req.ContentLength == 0 || req.Body == nil || req.Body == http.NoBody {
+				return next(ctx)
+			}
+
+			contentType := req.Header.Get(echo.HeaderContentType)
+			if !isJSONMediaType(contentType) && !acceptsMediaType(req.Method, ctx.Path(), contentType) {
+				logger.AddMap(req.Context(), map[string]any{
+					"rejection_reason": "unsupported_media_type",
+					"content_type":     contentType,
+				})
+				return response.Error(ctx, errorc.ErrorUnsupportedMediaType)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// mediaTypeRoutes holds the media types routes accept besides JSON, by method and path
+var mediaTypeRoutes sync.Map
+
+// AcceptMediaTypes lets the route take bodies of the given media types besides JSON through
+// JSONContentTypeMiddleware, e.g. a CSV upload.
+//
+// Usage:
+//
+//	middleware.AcceptMediaTypes(adminRoute.POST("/import", h.Import), "text/csv")
+func AcceptMediaTypes(route *echo.Route, mediaTypes ...string) {
+	mediaTypeRoutes.Store(route.Method+" "+route.Path, mediaTypes)
+}
+
+// acceptsMediaType reports whether the route registered contentType with AcceptMediaTypes
+func acceptsMediaType(method, path, contentType string) bool {
+	accepted, ok := mediaTypeRoutes.Load(method + " " + path)
+	if !ok {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(accepted.([]string), mediaType)
+}
+
+// isJSONMediaType reports whether contentType is application/json or a structured +json type,
+// ignoring parameters such as charset.
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	setup := func() *echo.Echo {
+		e := echo.New()
+		cfg := &config.Configuration{Server: config.Server{MaxBodySize: "16B"}}
+		m := middleware.New(e, cfg)
+		e.Use(m.BodyLimitMiddleware(cfg))
+		e.POST("/", func(ctx echo.Context) error {
+			body, err := io.ReadAll(ctx.Request().Body)
+			if err != nil {
+				return response.Error(ctx, err)
+			}
+			return ctx.String(http.StatusOK, string(body))
+		})
+		e.POST("/bind", func(ctx echo.Context) error {
+			var body map[string]any
+			if err := ctx.Bind(&body); err != nil {
+				return response.ErrorBinding(ctx, err)
+			}
+			return ctx.NoContent(http.StatusNoContent)
+		})
+		return e
+	}
+
+	t.Run("Within Limit", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		setup().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":1}`)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"a":1}`, rec.Body.String())
+	})
+
+	t.Run("Content-Length Too Large", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		setup().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 17))))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), "PAYLOAD_TOO_LARGE")
+	})
+
+	t.Run("Chunked Body Too Large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 64)))
+		req.ContentLength = -1 // unknown length, as with chunked encoding
+
+		rec := httptest.NewRecorder()
+		setup().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), "PAYLOAD_TOO_LARGE")
+	})
+
+	t.Run("Chunked Body Too Large To Bind", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/bind", strings.NewReader(`{"a":"`+strings.Repeat("a", 64)+`"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.ContentLength = -1
+
+		rec := httptest.NewRecorder()
+		setup().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), "PAYLOAD_TOO_LARGE")
+	})
+}
+
+func TestJSONContentTypeMiddleware(t *testing.T) {
+	e := echo.New()
+	m := middleware.New(e, nil)
+	e.Use(m.JSONContentTypeMiddleware())
+	e.Any("/", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	})
+	middleware.AcceptMediaTypes(e.POST("/upload", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	}), "text/csv")
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		contentType string
+		want        int
+	}{
+		{"JSON", http.MethodPost, "/", `{}`, "application/json", http.StatusNoContent},
+		{"JSON With Charset", http.MethodPost, "/", `{}`, "application/json; charset=utf-8", http.StatusNoContent},
+		{"Problem JSON", http.MethodPost, "/", `{}`, "application/problem+json", http.StatusNoContent},
+		{"Form", http.MethodPost, "/", `a=1`, "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"Missing Content-Type", http.MethodPost, "/", `{}`, "", http.StatusUnsupportedMediaType},
+		{"No Body", http.MethodGet, "/", "", "", http.StatusNoContent},
+		{"Accepted Media Type", http.MethodPost, "/upload", "a,b", "text/csv; charset=utf-8", http.StatusNoContent},
+		{"Media Type Of Another Route", http.MethodPost, "/", "a,b", "text/csv", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(echo.HeaderContentType, tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
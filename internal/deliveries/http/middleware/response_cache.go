@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/response"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxCachedResponse is the largest body ResponseCache stores; larger responses are not cached
+const maxCachedResponse = 1 << 20 // 1MiB
+
+// headers that belong to a single response and are never replayed from the cache. The
+// encoding ones are set by CompressMiddleware, which runs outside and sees the body
+// uncompressed: a replay is compressed again for the client at hand.
+var uncachedHeaders = []string{
+	echo.HeaderContentLength, echo.HeaderSetCookie, echo.HeaderXRequestID,
+	echo.HeaderContentEncoding, echo.HeaderVary,
+}
+
+// ResponseCache caches successful GET responses of a route in cache.Default(), per auth
+// subject (the "accountNumber" set by BearerAuthMiddleware, shared when absent), path,
+// query, and negotiated locale. ttl zero uses response_cache.ttl. It is a no-op when no cache
+// is installed.
+//
+// Hits replay the stored status, headers, and body as is (including the metadata of the
+// response envelope), carry X-Cache: HIT, and honor If-None-Match against the cached ETag.
+// Services drop the stale entries of an account with cache.Default().Invalidate. Clients can
+// skip the lookup with Cache-Control: no-cache; responses with Cache-Control: no-store are
+// never stored.
+//
+// Usage:
+//
+//	bearerRoute.GET("/me", h.GetUserByAccessToken, middleware.ResponseCache(time.Minute))
+func ResponseCache(ttl time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			responses := cache.Default()
+			req := ctx.Request()
+			if responses == nil || req.Method != http.MethodGet {
+				return next(ctx)
+			}
+
+			reqCtx := req.Context()
+			subject, _ := ctx.Get("accountNumber").(string)
+			key := responseKey(req.URL.Path, req.URL.Query().Encode(), i18n.FromContext(reqCtx))
+
+			if !strings.Contains(req.Header.Get(echo.HeaderCacheControl), "no-cache") {
+				cached, ok, err := responses.Get(reqCtx, subject, key)
+				if err != nil {
+					logger.FromContext(reqCtx).Warn(reqCtx, "response cache lookup failed", logger.Error(err))
+				}
+				if ok {
+					logger.Add(reqCtx, "response_cache", "hit")
+					return replayResponse(ctx, cached)
+				}
+			}
+			logger.Add(reqCtx, "response_cache", "miss")
+
+			res := ctx.Response()
+			res.Header().Set("X-Cache", "MISS")
+			original := res.Writer
+			recorder := &responseRecorder{ResponseWriter: original}
+			res.Writer = recorder
+			defer func() { res.Writer = original }()
+
+			if err := next(ctx); err != nil {
+				return err
+			}
+
+			if res.Status != http.StatusOK || recorder.overflow ||
+				strings.Contains(res.Header().Get(echo.HeaderCacheControl), "no-store") {
+				return nil
+			}
+
+			header := res.Header().Clone()
+			for _, name := range uncachedHeaders {
+				header.Del(name)
+			}
+			err := responses.Set(reqCtx, subject, key, cache.Response{
+				Status: res.Status,
+				Header: header,
+				Body:   recorder.body.Bytes(),
+			}, ttl)
+			if err != nil {
+				logger.FromContext(reqCtx).Warn(reqCtx, "response cache store failed", logger.Error(err))
+			}
+			return nil
+		}
+	}
+}
+
+// responseKey is the key of a response among those of its subject: the normalized query
+// keeps ?a=1&b=2 and ?b=2&a=1 together, the locale keeps the messages of each language apart
+func responseKey(path, query string, locale i18n.Locale) string {
+	return string(locale) + " " + path + "?" + query
+}
+
+// replayResponse writes a cached response, or 304 when it matches If-None-Match
+func replayResponse(ctx echo.Context, cached *cache.Response) error {
+	header := ctx.Response().Header()
+	for name, values := range cached.Header {
+		header[name] = values
+	}
+	header.Set("X-Cache", "HIT")
+
+	if etag := cached.Header.Get("ETag"); etag != "" && response.NotModified(ctx, etag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
+	ctx.Response().WriteHeader(cached.Status)
+	_, err := ctx.Response().Write(cached.Body)
+	return err
+}
+
+// responseRecorder tees the body of a response up to maxCachedResponse
+type responseRecorder struct {
+	http.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(b) > maxCachedResponse {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
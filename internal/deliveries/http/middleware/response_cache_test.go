@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/deliveries/http/middleware"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	responses := cache.NewResponses(cache.NewMemoryStore(100), time.Minute)
+	cache.SetDefault(responses)
+	t.Cleanup(func() { cache.SetDefault(nil) })
+
+	calls := 0
+	e := echo.New()
+	authenticate := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			ctx.Set("accountNumber", ctx.Request().Header.Get("X-Account"))
+			return next(ctx)
+		}
+	}
+	e.GET("/me", func(ctx echo.Context) error {
+		calls++
+		return response.SuccessWithETag(ctx, map[string]any{"account": ctx.Get("accountNumber"), "calls": calls})
+	}, authenticate, middleware.ResponseCache(0))
+	e.GET("/fail", func(ctx echo.Context) error {
+		calls++
+		return ctx.NoContent(http.StatusServiceUnavailable)
+	}, middleware.ResponseCache(0))
+
+	get := func(path, account string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Account", account)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/me", "acc-1")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+
+	second := get("/me", "acc-1")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+	assert.Equal(t, 1, calls)
+
+	t.Run("Conditional Hit", func(t *testing.T) {
+		rec := get("/me", "acc-1", "If-None-Match", first.Header().Get("ETag"))
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Per Subject And Query", func(t *testing.T) {
+		assert.Equal(t, "MISS", get("/me", "acc-2").Header().Get("X-Cache"))
+		assert.Equal(t, "MISS", get("/me?b=2&a=1", "acc-1").Header().Get("X-Cache"))
+		assert.Equal(t, "HIT", get("/me?a=1&b=2", "acc-1").Header().Get("X-Cache"), "the query is normalized")
+	})
+
+	t.Run("Client No-Cache", func(t *testing.T) {
+		before := calls
+		assert.Equal(t, "MISS", get("/me", "acc-1", echo.HeaderCacheControl, "no-cache").Header().Get("X-Cache"))
+		assert.Equal(t, before+1, calls)
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		require.NoError(t, cache.Default().Invalidate(context.Background(), "acc-1"))
+		assert.Equal(t, "MISS", get("/me", "acc-1").Header().Get("X-Cache"))
+		assert.Equal(t, "HIT", get("/me", "acc-2").Header().Get("X-Cache"), "other subjects are kept")
+	})
+
+	t.Run("Errors Are Not Cached", func(t *testing.T) {
+		before := calls
+		get("/fail", "")
+		assert.Equal(t, "MISS", get("/fail", "").Header().Get("X-Cache"))
+		assert.Equal(t, before+2, calls)
+	})
+}
+
+func TestResponseCache_CompressionAndLocale(t *testing.T) {
+	cache.SetDefault(cache.NewResponses(cache.NewMemoryStore(100), time.Minute))
+	t.Cleanup(func() { cache.SetDefault(nil) })
+
+	payload := strings.Repeat(`{"name":"user"},`, 200)
+	e := echo.New()
+	configuration := &config.Configuration{Server: config.Server{Compression: config.Compression{Enabled: true}}}
+	m := middleware.New(e, configuration)
+	e.Use(m.LocaleMiddleware())
+	e.Use(m.CompressMiddleware(configuration))
+	e.GET("/me", func(ctx echo.Context) error {
+		return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(string(i18n.FromContext(ctx.Request().Context()))+payload))
+	}, middleware.ResponseCache(0))
+
+	get := func(acceptEncoding, acceptLanguage string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("gzip", "en")
+	require.Equal(t, "MISS", first.Header().Get("X-Cache"))
+	require.Equal(t, "gzip", first.Header().Get(echo.HeaderContentEncoding))
+
+	t.Run("Replayed Uncompressed", func(t *testing.T) {
+		rec := get("", "en")
+		assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, "en"+payload, rec.Body.String())
+	})
+
+	t.Run("Replayed Compressed", func(t *testing.T) {
+		rec := get("gzip", "en")
+		assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+		require.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, []string{echo.HeaderAcceptEncoding}, rec.Header().Values(echo.HeaderVary))
+		gr, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gr)
+		require.NoError(t, err)
+		assert.Equal(t, "en"+payload, string(body))
+	})
+
+	t.Run("Per Locale", func(t *testing.T) {
+		rec := get("", "id")
+		assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+		assert.Equal(t, "id"+payload, rec.Body.String())
+	})
+}
@@ -0,0 +1,130 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	handler "go-echo-boilerplate/internal/deliveries/http"
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	v1 "go-echo-boilerplate/internal/deliveries/http/api/v1"
+	"go-echo-boilerplate/internal/models"
+	usersmodule "go-echo-boilerplate/internal/modules/users"
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/hashc"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// BenchmarkPipeline measures the overhead of the middleware chain (wide event logging,
+// masking, API key, bearer auth, validation) over the bare handlers, on the real services
+// and in-memory repositories. Compare the "bare" and "full" results of each request:
+//
+//	go test ./internal/deliveries/http/ -run '^$' -bench Pipeline -benchmem
+func BenchmarkPipeline(b *testing.B) {
+	// Wide events are encoded like in production, then discarded
+	previous := logger.Instance
+	logger.Instance = logger.NewZapLogger(zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		zapcore.InfoLevel,
+	)))
+	b.Cleanup(func() { logger.Instance = previous })
+
+	jwtConfig := &jwtc.Configuration{
+		AccessTokenSecret:    "bench-secret-0123456789abcdef0123456789",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenSecret:   "bench-secret-0123456789abcdef0123456789",
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "bench",
+	}
+	configuration := &config.Configuration{
+		Application:   config.Application{Environment: "prod"},
+		Authorization: config.Authorization{APIKey: "api-key", AdminAPIKey: "admin-key"},
+	}
+	services := newBenchServices(b, configuration, jwtConfig)
+
+	user, err := services.User.Create(context.Background(), &models.CreateUserRequest{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	token, err := generator.AccessToken(user, jwtConfig)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	bare := echo.New()
+	v1.NewUserV1(bare.Group("/api/v1"), services, configuration, jwtConfig)
+
+	full := echo.New()
+	deps := api.Dependencies{Service: services, Config: configuration, JWTConfig: jwtConfig}
+	if err := handler.New(full, deps, usersmodule.Module{}); err != nil {
+		b.Fatal(err)
+	}
+
+	requests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"GetMe", http.MethodGet, "/api/v1/users/me", "", http.StatusOK},
+		{"List", http.MethodGet, "/api/v1/admin/users?limit=20", "", http.StatusOK},
+		{"CreateInvalid", http.MethodPost, "/api/v1/users", `{"name":"Jane Doe","email":"not-an-email","password":"secret"}`, http.StatusBadRequest},
+	}
+
+	for _, rr := range requests {
+		for _, pipeline := range []struct {
+			name string
+			e    *echo.Echo
+		}{{"bare", bare}, {"full", full}} {
+			b.Run(rr.name+"/"+pipeline.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					req := httptest.NewRequest(rr.method, rr.path, strings.NewReader(rr.body))
+					req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+					req.Header.Set(echo.HeaderAuthorization, "Bearer "+token.Token)
+					req.Header.Set("X-API-Key", "api-key")
+					req.Header.Set("X-Admin-Key", "admin-key")
+					rec := httptest.NewRecorder()
+					pipeline.e.ServeHTTP(rec, req)
+					if rec.Code != rr.status {
+						b.Fatalf("%s %s: status %d, want %d: %s", rr.method, rr.path, rec.Code, rr.status, rec.Body.String())
+					}
+				}
+			})
+		}
+	}
+}
+
+// newBenchServices builds the services on in-memory repositories
+func newBenchServices(b *testing.B, configuration *config.Configuration, jwtConfig *jwtc.Configuration) *service.Service {
+	b.Helper()
+	deps := &service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		Config:     configuration,
+		JWTConfig:  jwtConfig,
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+	}
+	return &service.Service{
+		Health: service.NewHealthService(deps),
+		User:   service.NewUserService(deps),
+	}
+}
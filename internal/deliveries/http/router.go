@@ -2,12 +2,15 @@ package http
 
 import (
 	"go-echo-boilerplate/internal/config"
-	v1 "go-echo-boilerplate/internal/deliveries/http/api/v1"
+	"go-echo-boilerplate/internal/deliveries/http/admin"
+	apiversion "go-echo-boilerplate/internal/deliveries/http/api"
 	healthcheck "go-echo-boilerplate/internal/deliveries/http/health_check"
 	"go-echo-boilerplate/internal/deliveries/http/middleware"
-	"go-echo-boilerplate/internal/pkg/jwtc"
-	"go-echo-boilerplate/internal/service"
+	"go-echo-boilerplate/internal/pkg/diagnostics"
+	"go-echo-boilerplate/internal/pkg/mail/templates"
+	"go-echo-boilerplate/internal/pkg/mockapi"
 	"net/http"
+	"slices"
 
 	_ "go-echo-boilerplate/docs"
 
@@ -28,32 +31,108 @@ import (
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host localhost:8080
-// @BasePath /api
+// @BasePath /
 // @schemes http https
-func New(eco *echo.Echo, service *service.Service, config *config.Configuration, jwtConfig *jwtc.Configuration) {
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from POST /api/v1/users/tokens, sent as "Bearer <token>"
+func New(eco *echo.Echo, deps apiversion.Dependencies, modules ...apiversion.RouteRegistrar) error {
+	config, service := deps.Config, deps.Service
+
 	// Middleware for Recover and Logging
 	middleware := middleware.New(eco, config)
 	middleware.Default(config)
 
+	// Mock mode, the documented operations answer their OpenAPI examples
+	if config.Application.Mock() {
+		responses, err := mockapi.New()
+		if err != nil {
+			return err
+		}
+		eco.Use(middleware.MockMiddleware(responses))
+	}
+
 	eco.GET("/", func(ctx echo.Context) error {
 		message := "This is your Go Echo Boilerplate"
 		return ctx.String(http.StatusOK, message)
 	})
 
-	eco.GET("/docs", func(ectx echo.Context) error {
-		return ectx.File("api-docs.html")
-	})
+	// API Documentation, regenerated with make docs
+	if docsEnabled(config) {
+		eco.GET("/docs", func(ectx echo.Context) error {
+			return ectx.File("api-docs.html")
+		})
 
-	eco.GET("/swagger/*", echoSwagger.WrapHandler)
+		eco.GET("/swagger/*", echoSwagger.WrapHandler)
+	}
+
+	// Email previews, in the development environments only
+	if mailPreviewEnabled(config) {
+		emails, err := templates.New()
+		if err != nil {
+			return err
+		}
+		admin.MailPreview(eco.Group("/dev/emails"), emails)
+	}
 
 	// Health Grouping
 	health := eco.Group("/health")
 	healthcheck.New(health, service)
+	healthcheck.Version(eco.Group("/version"), config.Application)
+
+	// Admin Grouping (disabled unless authorization.admin_api_key is set)
+	adminGroup := eco.Group("/admin")
+	adminGroup.Use(middleware.AdminKeyMiddleware(config))
+	adminGroup.Use(middleware.JSONContentTypeMiddleware())
+	admin.New(adminGroup, config)
+	admin.QuotaOverrides(adminGroup, service.Quota)
+	admin.DebugCaptures(adminGroup, service.DebugCapture)
+	admin.Runtime(adminGroup, diagnostics.NewStorage(config.Diagnostics))
+
+	// Profiling, behind the admin key like /admin
+	debugGroup := eco.Group("/debug/pprof")
+	debugGroup.Use(middleware.AdminKeyMiddleware(config))
+	admin.Profiling(debugGroup)
 
 	// API Grouping
 	api := eco.Group("/api")
 	api.Use(middleware.ApiKeyMiddleware(config))
+	api.Use(middleware.JSONContentTypeMiddleware())
+	api.GET("/quota", apiversion.Quota)
+
+	// Versions, see api.Version for deprecating one
+	routes := apiversion.New(api, deps,
+		apiversion.Version{Name: "v1"},
+		apiversion.Version{Name: "v2"},
+	)
+
+	// Feature modules add their handlers to the versions, see core.Module
+	for _, module := range modules {
+		if err := module.RegisterRoutes(routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultDocsEnvironments serve the API documentation when docs.environments is unset
+var DefaultDocsEnvironments = []string{"local", "dev", "staging"}
+
+// docsEnabled reports whether application.environment serves the API documentation
+func docsEnabled(config *config.Configuration) bool {
+	environments := config.Docs.Environments
+	if len(environments) == 0 {
+		environments = DefaultDocsEnvironments
+	}
+	return slices.Contains(environments, config.Application.Environment)
+}
+
+// MailPreviewEnvironments serve the email previews at /dev/emails
+var MailPreviewEnvironments = []string{"local", "dev"}
 
-	// Initialize V1 Handlers
-	v1.New(api, service, config, jwtConfig)
+// mailPreviewEnabled reports whether application.environment serves the email previews
+func mailPreviewEnabled(config *config.Configuration) bool {
+	return slices.Contains(MailPreviewEnvironments, config.Application.Environment)
 }
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsEnabled(t *testing.T) {
+	configuration := func(environment string, environments ...string) *config.Configuration {
+		return &config.Configuration{
+			Application: config.Application{Environment: environment},
+			Docs:        config.Docs{Environments: environments},
+		}
+	}
+
+	assert.True(t, docsEnabled(configuration("local")))
+	assert.True(t, docsEnabled(configuration("staging")))
+	assert.False(t, docsEnabled(configuration("prod")))
+	assert.False(t, docsEnabled(configuration("production")))
+
+	assert.True(t, docsEnabled(configuration("production", "production")))
+	assert.False(t, docsEnabled(configuration("local", "dev")))
+}
+
+func TestMailPreviewEnabled(t *testing.T) {
+	configuration := func(environment string) *config.Configuration {
+		return &config.Configuration{Application: config.Application{Environment: environment}}
+	}
+
+	assert.True(t, mailPreviewEnabled(configuration("local")))
+	assert.True(t, mailPreviewEnabled(configuration("dev")))
+	assert.False(t, mailPreviewEnabled(configuration("staging")))
+	assert.False(t, mailPreviewEnabled(configuration("production")))
+}
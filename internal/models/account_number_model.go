@@ -0,0 +1,23 @@
+package models
+
+// Check digit algorithms for account numbers
+const (
+	CheckDigitLuhn  = "luhn"  // 1 trailing check digit (credit card style)
+	CheckDigitMod97 = "mod97" // 2 trailing check digits, ISO 7064 MOD 97-10 (IBAN style)
+)
+
+// AccountNumberProfile describes the format of generated account numbers
+type AccountNumberProfile struct {
+	Name       string
+	Length     int      // total length including check digits
+	Prefixes   []string // one is picked at random when no explicit prefix is given; empty means fully random
+	CheckDigit string   // CheckDigitLuhn or CheckDigitMod97
+}
+
+// CheckDigits returns how many trailing digits the profile's check digit algorithm uses
+func (p AccountNumberProfile) CheckDigits() int {
+	if p.CheckDigit == CheckDigitMod97 {
+		return 2
+	}
+	return 1
+}
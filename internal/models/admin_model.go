@@ -0,0 +1,85 @@
+package models
+
+import (
+	"go-echo-boilerplate/internal/pkg/logger"
+	"time"
+)
+
+type (
+	UpdateLogLevelRequest struct {
+		Level string `json:"level" validate:"required,oneof=debug info warn error"`
+	}
+
+	LogLevelResponse struct {
+		Level      string            `json:"level"`
+		PathLevels map[string]string `json:"pathLevels,omitempty"`
+	}
+
+	ResolvePIITokenRequest struct {
+		Token  string `json:"token" validate:"required"`
+		Reason string `json:"reason" validate:"required,min=10"` // e.g. the incident ticket, recorded in the audit log
+	}
+
+	ResolvePIITokenResponse struct {
+		Token    string `json:"token"`
+		PIIValue string `json:"pii_value"` // masked by name in the request log
+	}
+
+	UpdateMaintenanceRequest struct {
+		Enabled    *bool  `json:"enabled" validate:"required"`
+		RetryAfter string `json:"retry_after,omitempty"` // e.g. "10m", sent in Retry-After; defaults to 5m
+		Message    string `json:"message,omitempty" validate:"max=255"`
+	}
+
+	MaintenanceResponse struct {
+		Enabled    bool       `json:"enabled"`
+		RetryAfter string     `json:"retry_after"`
+		Message    string     `json:"message"`
+		Since      *time.Time `json:"since,omitempty"` // when it was last turned on or off
+		Source     string     `json:"source,omitempty" enums:"config,admin"`
+	}
+
+	RuntimeResponse struct {
+		Goroutines int                   `json:"goroutines" example:"42"`
+		CPUs       int                   `json:"cpus" example:"4"` // usable by the process, GOMAXPROCS
+		GoVersion  string                `json:"goVersion" example:"go1.24.4"`
+		Uptime     string                `json:"uptime" example:"72h3m0s"`
+		Memory     RuntimeMemoryResponse `json:"memory"`
+		GC         RuntimeGCResponse     `json:"gc"`
+	}
+
+	// RuntimeMemoryResponse is the memory of runtime.MemStats, in bytes
+	RuntimeMemoryResponse struct {
+		Sys         uint64 `json:"sys" example:"25165824"`         // obtained from the OS
+		HeapAlloc   uint64 `json:"heapAlloc" example:"8388608"`    // of the live objects, and the unswept dead ones
+		HeapInuse   uint64 `json:"heapInuse" example:"10485760"`   // of the spans in use
+		HeapIdle    uint64 `json:"heapIdle" example:"4194304"`     // of the idle spans, returnable to the OS
+		HeapObjects uint64 `json:"heapObjects" example:"51200"`    // allocated
+		StackInuse  uint64 `json:"stackInuse" example:"1048576"`   // of the goroutine stacks
+		TotalAlloc  uint64 `json:"totalAlloc" example:"536870912"` // allocated since startup
+		Mallocs     uint64 `json:"mallocs" example:"1200000"`      // since startup
+		Frees       uint64 `json:"frees" example:"1148800"`        // since startup
+	}
+
+	RuntimeGCResponse struct {
+		NumGC        uint32     `json:"numGC" example:"120"`
+		NumForcedGC  uint32     `json:"numForcedGC" example:"1"` // by runtime.GC, e.g. a heap dump
+		LastGC       *time.Time `json:"lastGC,omitempty" example:"2026-01-01T00:00:00Z"`
+		NextGC       uint64     `json:"nextGC" example:"16777216"` // heap size triggering the next cycle, in bytes
+		PauseTotalMs float64    `json:"pauseTotalMs" example:"35.2"`
+		LastPauseMs  float64    `json:"lastPauseMs" example:"0.3"`
+		CPUFraction  float64    `json:"cpuFraction" example:"0.001"` // of the CPU time used by the GC since startup
+	}
+
+	HeapDumpResponse struct {
+		Name      string    `json:"name" example:"heap-api-1-1234-20260101T000000Z.pb.gz"`
+		Location  string    `json:"location" example:"/var/dumps/heap-api-1-1234-20260101T000000Z.pb.gz"`
+		Size      int       `json:"size" example:"204800"` // in bytes
+		CreatedAt time.Time `json:"createdAt" example:"2026-01-01T00:00:00Z"`
+	}
+
+	LogStatsResponse struct {
+		Async      logger.AsyncStats      `json:"async"`
+		Truncation logger.TruncationStats `json:"truncation"`
+	}
+)
@@ -4,3 +4,12 @@ type PhoneNumber struct {
 	CountryCode string `json:"countryCode" validate:"omitempty" example:"ID"`
 	Number      string `json:"number" validate:"omitempty,phoneFormat" example:"6281234567890"`
 }
+
+// Audit records who created and last updated a row. Entities embed it next to their
+// timestamps, with created_by and updated_by columns; gorm fills them with the actor of the
+// request (see database.RegisterAuditCallbacks). Both are nil for writes without one, e.g.
+// a signup or a background job.
+type Audit struct {
+	CreatedBy *string `json:"created_by" gorm:"<-:create"`
+	UpdatedBy *string `json:"updated_by"`
+}
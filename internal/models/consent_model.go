@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+var TYPE_CONSENT = "consent"
+
+// Consent is the state of the consents of an account after a change: the terms version it
+// accepted and its marketing choice, with when and where each was given
+type Consent struct {
+	ID                 int64      `json:"id"`
+	AccountNumber      string     `json:"account_number"`
+	TermsVersion       string     `json:"terms_version"` // empty until terms are accepted
+	TermsAcceptedAt    *time.Time `json:"terms_accepted_at"`
+	MarketingOptIn     bool       `json:"marketing_opt_in"`
+	MarketingUpdatedAt *time.Time `json:"marketing_updated_at"`
+	IP                 string     `json:"ip"`
+	UserAgent          string     `json:"user_agent"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+type (
+	UpdateConsentRequest struct {
+		TermsVersion   string `json:"termsVersion" validate:"omitempty,max=32" example:"2026-10-01"`
+		MarketingOptIn *bool  `json:"marketingOptIn" example:"false"`
+	}
+
+	ConsentResponse struct {
+		Type                string     `json:"type" example:"consent"`
+		TermsVersion        string     `json:"termsVersion" example:"2026-10-01"`
+		TermsAcceptedAt     *time.Time `json:"termsAcceptedAt" example:"2026-01-24T15:57:37+07:00"`
+		CurrentTermsVersion string     `json:"currentTermsVersion" example:"2026-10-01"`
+		TermsAccepted       bool       `json:"termsAccepted" example:"true"` // the current version is accepted
+		MarketingOptIn      bool       `json:"marketingOptIn" example:"false"`
+		MarketingUpdatedAt  *time.Time `json:"marketingUpdatedAt" example:"2026-01-24T15:57:37+07:00"`
+	}
+)
+
+// AcceptedTerms reports whether the consent covers the terms version current; with no
+// current version there is nothing to accept.
+func (c *Consent) AcceptedTerms(current string) bool {
+	return current == "" || (c != nil && c.TermsVersion == current)
+}
+
+// ConsentResponse describes the consent against the current terms version. A nil consent
+// is an account that never gave one.
+func (c *Consent) ConsentResponse(currentTermsVersion string) *ConsentResponse {
+	response := &ConsentResponse{
+		Type:                TYPE_CONSENT,
+		CurrentTermsVersion: currentTermsVersion,
+		TermsAccepted:       c.AcceptedTerms(currentTermsVersion),
+	}
+	if c != nil {
+		response.TermsVersion = c.TermsVersion
+		response.TermsAcceptedAt = c.TermsAcceptedAt
+		response.MarketingOptIn = c.MarketingOptIn
+		response.MarketingUpdatedAt = c.MarketingUpdatedAt
+	}
+	return response
+}
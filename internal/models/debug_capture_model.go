@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Triggers of a DebugCapture
+const (
+	DebugCaptureTriggerToken   = "token"   // the request carried an X-Debug-Capture token
+	DebugCaptureTriggerAccount = "account" // the account is in debug_capture.accounts
+)
+
+// DebugCapture is a sanitized request and its response, kept for a support investigation
+// until ExpiresAt and replayed locally with the cli replay-capture command.
+type DebugCapture struct {
+	ID            int64     `json:"id"`
+	RequestID     string    `json:"request_id"`
+	Trigger       string    `json:"trigger"`
+	Label         string    `json:"label"`          // of the token, e.g. the support ticket; empty for an account
+	AccountNumber string    `json:"account_number"` // empty for requests without an access token
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	Status        int       `json:"status"`
+	Request       string    `json:"request"`  // JSON of the CapturedRequest
+	Response      string    `json:"response"` // JSON of the CapturedResponse
+	CapturedAt    time.Time `json:"captured_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+type (
+	// CapturedRequest is a request as received, with the credentials and sensitive fields masked
+	CapturedRequest struct {
+		Method        string            `json:"method" example:"POST"`
+		URL           string            `json:"url" example:"/api/v1/users?include=profile"` // path and query
+		Header        map[string]string `json:"header"`
+		Body          string            `json:"body,omitempty"`
+		BodyTruncated bool              `json:"body_truncated,omitempty"` // cut at debug_capture.max_body_size
+		BodyOmitted   bool              `json:"body_omitted,omitempty"`   // binary or multipart, not kept
+	}
+
+	// CapturedResponse is the response to a CapturedRequest, masked the same way
+	CapturedResponse struct {
+		Status        int               `json:"status" example:"422"`
+		Header        map[string]string `json:"header"`
+		Body          string            `json:"body,omitempty"`
+		BodyTruncated bool              `json:"body_truncated,omitempty"`
+		BodyOmitted   bool              `json:"body_omitted,omitempty"`
+		DurationMs    int64             `json:"duration_ms" example:"42"`
+	}
+)
+
+type (
+	CreateDebugCaptureTokenRequest struct {
+		Label string `json:"label" validate:"required,max=64" example:"SUP-1234"` // e.g. the support ticket, stored with each capture
+	}
+
+	DebugCaptureTokenResponse struct {
+		Header    string    `json:"header" example:"X-Debug-Capture"`
+		Token     string    `json:"token"`
+		Label     string    `json:"label" example:"SUP-1234"`
+		ExpiresAt time.Time `json:"expires_at" example:"2026-01-24T16:57:37Z"`
+	}
+
+	DebugCaptureSummaryResponse struct {
+		ID            int64     `json:"id" example:"42"`
+		RequestID     string    `json:"request_id" example:"0d6f2ab4-5c1e-4d0e-9a57-8f3b2c1d4e5f"`
+		Trigger       string    `json:"trigger" enums:"token,account" example:"token"`
+		Label         string    `json:"label" example:"SUP-1234"`
+		AccountNumber string    `json:"account_number" example:"1234567890"`
+		Method        string    `json:"method" example:"POST"`
+		Path          string    `json:"path" example:"/api/v1/users"`
+		Status        int       `json:"status" example:"422"`
+		CapturedAt    time.Time `json:"captured_at" example:"2026-01-24T15:57:37Z"`
+		ExpiresAt     time.Time `json:"expires_at" example:"2026-01-27T15:57:37Z"`
+	}
+
+	DebugCaptureResponse struct {
+		DebugCaptureSummaryResponse
+		Request  CapturedRequest  `json:"request"`
+		Response CapturedResponse `json:"response"`
+	}
+)
+
+func (c *DebugCapture) DebugCaptureSummaryResponse() DebugCaptureSummaryResponse {
+	return DebugCaptureSummaryResponse{
+		ID:            c.ID,
+		RequestID:     c.RequestID,
+		Trigger:       c.Trigger,
+		Label:         c.Label,
+		AccountNumber: c.AccountNumber,
+		Method:        c.Method,
+		Path:          c.Path,
+		Status:        c.Status,
+		CapturedAt:    c.CapturedAt,
+		ExpiresAt:     c.ExpiresAt,
+	}
+}
+
+// DebugCaptureResponse decodes the captured request and response
+func (c *DebugCapture) DebugCaptureResponse() (DebugCaptureResponse, error) {
+	response := DebugCaptureResponse{DebugCaptureSummaryResponse: c.DebugCaptureSummaryResponse()}
+	if err := json.Unmarshal([]byte(c.Request), &response.Request); err != nil {
+		return DebugCaptureResponse{}, err
+	}
+	if err := json.Unmarshal([]byte(c.Response), &response.Response); err != nil {
+		return DebugCaptureResponse{}, err
+	}
+	return response, nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+var TYPE_EMAIL_CHANGE = "emailChange"
+
+// Sides of an email change, each confirmed from its own address
+const (
+	EmailChangeOld = "old"
+	EmailChangeNew = "new"
+)
+
+// EmailChange is a request to change the email of an account. The switch happens once both
+// the current address (when the account has one) and the new address confirmed it.
+type EmailChange struct {
+	ID             int64      `json:"id"`
+	AccountNumber  string     `json:"account_number"`
+	OldEmail       *string    `json:"old_email"` // nil when the account had no email, nothing to confirm
+	NewEmail       string     `json:"new_email"`
+	OldConfirmedAt *time.Time `json:"old_confirmed_at"`
+	NewConfirmedAt *time.Time `json:"new_confirmed_at"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CancelledAt    *time.Time `json:"cancelled_at"` // superseded by a newer request
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type (
+	ChangeEmailRequest struct {
+		Email string `json:"email" validate:"required,emailFormat" example:"john.new@example.com"`
+	}
+
+	ConfirmEmailChangeRequest struct {
+		Token string `json:"token" validate:"required" example:"AAAAAGkX3w1lbWFpbF9jaGFuZ2U6bmV3OjE.n8J2..."`
+	}
+
+	EmailChangeResponse struct {
+		Type         string    `json:"type" example:"emailChange"`
+		NewEmail     string    `json:"newEmail" example:"john.new@example.com"`
+		OldConfirmed bool      `json:"oldConfirmed" example:"true"`
+		NewConfirmed bool      `json:"newConfirmed" example:"false"`
+		Completed    bool      `json:"completed" example:"false"` // the account uses the new email
+		ExpiresAt    time.Time `json:"expiresAt" example:"2026-01-25T15:57:37+07:00"`
+	}
+)
+
+// Confirmed reports whether both addresses confirmed the change
+func (c *EmailChange) Confirmed() bool {
+	return c.OldConfirmedAt != nil && c.NewConfirmedAt != nil
+}
+
+func (c *EmailChange) EmailChangeResponse() *EmailChangeResponse {
+	return &EmailChangeResponse{
+		Type:         TYPE_EMAIL_CHANGE,
+		NewEmail:     c.NewEmail,
+		OldConfirmed: c.OldConfirmedAt != nil,
+		NewConfirmed: c.NewConfirmedAt != nil,
+		Completed:    c.CompletedAt != nil,
+		ExpiresAt:    c.ExpiresAt,
+	}
+}
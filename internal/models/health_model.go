@@ -1,7 +1,19 @@
 package models
 
+import (
+	"database/sql"
+	"time"
+)
+
 var TYPE_HEALTH = "HEALTH"
 
+// Statuses of a dependency in the health check
+const (
+	HEALTH_OK    = "OK"
+	HEALTH_WARN  = "WARN"
+	HEALTH_ERROR = "ERROR"
+)
+
 type HealthResponse struct {
 	Description  string                 `json:"description"`
 	Dependencies []HealthDetailResponse `json:"dependencies"`
@@ -13,5 +25,87 @@ type (
 		Component   string `json:"component"`
 		Status      string `json:"status"`
 		Description string `json:"description,omitempty"`
+
+		// Pool and Replication are reported by PostgreSQL
+		Pool        *HealthPoolResponse        `json:"pool,omitempty"`
+		Replication *HealthReplicationResponse `json:"replication,omitempty"`
+		// Leader is reported by the leader election
+		Leader *HealthLeaderResponse `json:"leader,omitempty"`
+		// Concurrency is reported by the concurrency limits
+		Concurrency []HealthConcurrencyResponse `json:"concurrency,omitempty"`
+	}
+
+	// HealthPoolResponse describes the connection pool
+	HealthPoolResponse struct {
+		MaxOpen        int   `json:"maxOpen" example:"100"` // 0 is unlimited
+		Open           int   `json:"open" example:"12"`
+		Idle           int   `json:"idle" example:"10"`
+		InUse          int   `json:"inUse" example:"2"`
+		WaitCount      int64 `json:"waitCount" example:"3"`        // connections waited for since startup
+		WaitDurationMs int64 `json:"waitDurationMs" example:"150"` // total time waited for them
+	}
+
+	// HealthReplicationResponse describes the streaming replicas of the primary
+	HealthReplicationResponse struct {
+		Replicas int   `json:"replicas" example:"2"`
+		LagMs    int64 `json:"lagMs" example:"40"` // replay lag of the slowest replica
+	}
+
+	// HealthLeaderResponse describes the leadership of the instance
+	HealthLeaderResponse struct {
+		Election string    `json:"election" example:"jobs"`
+		Leading  bool      `json:"leading" example:"true"`               // runs the singleton background jobs
+		Since    time.Time `json:"since" example:"2025-01-01T00:00:00Z"` // when it became leader or follower
+	}
+
+	// HealthConcurrencyResponse describes the saturation of a concurrency limit
+	HealthConcurrencyResponse struct {
+		Route     string `json:"route" example:"GET /api/v1/admin/users/export"` // "*" for every request
+		Limit     int    `json:"limit" example:"100"`
+		InFlight  int    `json:"inFlight" example:"12"`
+		Waiting   int    `json:"waiting" example:"0"`
+		Admitted  uint64 `json:"admitted" example:"120345"` // requests served since startup
+		Shed      uint64 `json:"shed" example:"17"`         // requests turned away with 503 since startup
+		AvgWaitMs int64  `json:"avgWaitMs" example:"3"`     // queue wait of the admitted requests, on average
 	}
 )
+
+// PostgreSQLStats are the figures of the PostgreSQL health check
+type PostgreSQLStats struct {
+	Pool sql.DBStats
+
+	// Replicas streaming from the database, none on a standalone database or a replica
+	Replicas int
+	// ReplicationLag is the replay lag of the slowest replica
+	ReplicationLag time.Duration
+}
+
+// HealthPoolResponse returns the pool figures of the stats
+func (s *PostgreSQLStats) HealthPoolResponse() *HealthPoolResponse {
+	return &HealthPoolResponse{
+		MaxOpen:        s.Pool.MaxOpenConnections,
+		Open:           s.Pool.OpenConnections,
+		Idle:           s.Pool.Idle,
+		InUse:          s.Pool.InUse,
+		WaitCount:      s.Pool.WaitCount,
+		WaitDurationMs: s.Pool.WaitDuration.Milliseconds(),
+	}
+}
+
+// HealthReplicationResponse returns the replication figures of the stats, nil without replicas
+func (s *PostgreSQLStats) HealthReplicationResponse() *HealthReplicationResponse {
+	if s.Replicas == 0 {
+		return nil
+	}
+	return &HealthReplicationResponse{Replicas: s.Replicas, LagMs: s.ReplicationLag.Milliseconds()}
+}
+
+// BuildResponse is the build of the running binary, see GET /version
+type BuildResponse struct {
+	Version   string `json:"version" example:"v1.2.0"`
+	Commit    string `json:"commit" example:"4f9c2d1e8b7a6c5d4e3f2a1b0c9d8e7f6a5b4c3d"` // suffixed with -dirty for uncommitted changes
+	BuildTime string `json:"buildTime" example:"2026-01-01T00:00:00Z"`
+	GoVersion string `json:"goVersion" example:"go1.24.4"`
+	OS        string `json:"os" example:"linux"`
+	Arch      string `json:"arch" example:"amd64"`
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Page sizes of the list endpoints taking a ListQuery
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// Operators of a ListFilter, the operators of pgsql.Query.Filter
+const (
+	FilterEq       = "eq"
+	FilterContains = "contains"
+	FilterGt       = "gt"
+	FilterGte      = "gte"
+	FilterLt       = "lt"
+	FilterLte      = "lte"
+	FilterIn       = "in" // the values are comma-separated
+)
+
+type (
+	// ListQuery is the query of a list endpoint: a page (page and limit) or a cursor, the sort,
+	// and filters, e.g. ?limit=50&sort=-createdAt,name&filter[name][contains]=john. It is bound
+	// with binder.List, which checks it against the ListSpec of the endpoint, and converted to
+	// the ListOptions of the repository with Options.
+	ListQuery struct {
+		Page    int          `query:"page" json:"page" validate:"omitempty,min=1" example:"1"`
+		Limit   int          `query:"limit" json:"limit" validate:"omitempty,min=1" example:"20"`
+		Cursor  string       `query:"cursor" json:"cursor" validate:"omitempty,max=512"`                  // the next cursor of the previous page, instead of page
+		Sort    string       `query:"sort" json:"sort" validate:"omitempty,max=255" example:"-createdAt"` // comma-separated fields, - for descending
+		Filters []ListFilter `query:"-" json:"-"`                                                         // filter[field]=value, or filter[field][operator]=value
+	}
+
+	// ListFilter is a filter of a ListQuery, on a field of the ListSpec
+	ListFilter struct {
+		Field    string
+		Operator string // FilterEq when the query names none
+		Value    string
+	}
+
+	// ListSpec whitelists what the clients of a list endpoint may ask for; the fields are the
+	// names clients send, mapped to columns by the repository (see pgsql.ListColumns)
+	ListSpec struct {
+		MaxLimit    int                 // MaxListLimit when 0
+		Sorts       []string            // the sortable fields, e.g. "name", "createdAt"
+		DefaultSort string              // e.g. "-createdAt"
+		Filters     map[string][]string // the operators of each filterable field, e.g. "name": {FilterContains}
+		Cursor      bool                // accepts a cursor instead of page, for a single sort field
+	}
+
+	// ListOptions is the repository-level form of a ListQuery, see pgsql.Query.List
+	ListOptions struct {
+		Filters []ListFilter
+		Sorts   []ListSort
+		Limit   int
+		Offset  int
+		After   *Cursor // the position of a cursor; Offset is 0 then
+	}
+
+	// ListSort is a field of the sort of a ListQuery
+	ListSort struct {
+		Field string
+		Desc  bool
+	}
+
+	// Cursor is the position after the last row of a page, for keyset pagination on a single
+	// sort field with the id as tie-breaker. It travels base64-encoded, see Encode.
+	Cursor struct {
+		Sort  string `json:"s"` // the sort it was issued for, e.g. "-createdAt"
+		Value string `json:"v"` // of the sort field on the last row
+		ID    int    `json:"i"` // of the last row
+	}
+)
+
+// Options converts the query, validated by binder.List against spec, to the options of the
+// repository: the limit defaults to DefaultListLimit and the sort to spec.DefaultSort.
+func (q ListQuery) Options(spec ListSpec) ListOptions {
+	options := ListOptions{
+		Filters: q.Filters,
+		Sorts:   ParseListSort(q.Sort),
+		Limit:   q.Limit,
+	}
+	if options.Limit <= 0 {
+		options.Limit = DefaultListLimit
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = ParseListSort(spec.DefaultSort)
+	}
+
+	if q.Cursor != "" {
+		if cursor, err := DecodeCursor(q.Cursor); err == nil {
+			options.After = &cursor
+		}
+		return options
+	}
+	if q.Page > 1 {
+		options.Offset = (q.Page - 1) * options.Limit
+	}
+	return options
+}
+
+// NextCursor returns the cursor of the page after the one ending with the row of id, whose
+// sort field is value. Callers pass the last row of a full page only: a shorter page is the
+// last one.
+func (o ListOptions) NextCursor(value any, id int) string {
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339Nano)
+	}
+	return Cursor{Sort: FormatListSort(o.Sorts), Value: fmt.Sprint(value), ID: id}.Encode()
+}
+
+// ParseListSort parses a sort, comma-separated fields each prefixed with - for descending
+func ParseListSort(sort string) []ListSort {
+	var sorts []ListSort
+	for field := range strings.SplitSeq(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, desc := strings.CutPrefix(field, "-")
+		sorts = append(sorts, ListSort{Field: name, Desc: desc})
+	}
+	return sorts
+}
+
+// FormatListSort is the inverse of ParseListSort
+func FormatListSort(sorts []ListSort) string {
+	fields := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		if sort.Desc {
+			fields = append(fields, "-"+sort.Field)
+			continue
+		}
+		fields = append(fields, sort.Field)
+	}
+	return strings.Join(fields, ",")
+}
+
+// Encode returns the cursor as sent to clients, opaque to them
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor of Encode
+func DecodeCursor(s string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if cursor.Sort == "" {
+		return cursor, fmt.Errorf("invalid cursor: no sort")
+	}
+	return cursor, nil
+}
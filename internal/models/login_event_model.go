@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+var TYPE_LOGIN_EVENT = "loginEvent"
+
+// Reasons a login is flagged as suspicious
+const (
+	LoginReasonNewCountry = "new_country"
+	LoginReasonNewDevice  = "new_device"
+)
+
+// LoginEvent is a successful login of an account, with the client it came from
+type LoginEvent struct {
+	ID            int64     `json:"id"`
+	AccountNumber string    `json:"account_number"`
+	IP            string    `json:"ip"`
+	Country       string    `json:"country"`
+	City          string    `json:"city"`
+	Device        string    `json:"device"`
+	OS            string    `json:"os"`
+	Browser       string    `json:"browser"`
+	Suspicious    bool      `json:"suspicious"`
+	Reasons       string    `json:"reasons"` // comma-separated LoginReason* values
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LoginHistory summarizes the earlier logins of an account, compared to a new one
+type LoginHistory struct {
+	Logins      int  // number of earlier logins
+	CountrySeen bool // an earlier login came from the same country
+	DeviceSeen  bool // an earlier login came from the same device, OS, and browser
+}
+
+type (
+	ListLoginEventRequest struct {
+		Limit int `query:"limit" json:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	}
+
+	LoginEventResponse struct {
+		Type       string    `json:"type" example:"loginEvent"`
+		IP         string    `json:"ip" example:"81.2.69.142"`
+		Country    string    `json:"country,omitempty" example:"GB"`
+		City       string    `json:"city,omitempty" example:"London"`
+		Device     string    `json:"device,omitempty" example:"mobile"`
+		OS         string    `json:"os,omitempty" example:"iOS"`
+		Browser    string    `json:"browser,omitempty" example:"Safari"`
+		Suspicious bool      `json:"suspicious" example:"true"`
+		Reasons    []string  `json:"reasons" example:"new_country"`
+		CreatedAt  time.Time `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
+	}
+)
+
+func (e *LoginEvent) LoginEventResponse() *LoginEventResponse {
+	reasons := []string{}
+	if e.Reasons != "" {
+		reasons = strings.Split(e.Reasons, ",")
+	}
+
+	return &LoginEventResponse{
+		Type:       TYPE_LOGIN_EVENT,
+		IP:         e.IP,
+		Country:    e.Country,
+		City:       e.City,
+		Device:     e.Device,
+		OS:         e.OS,
+		Browser:    e.Browser,
+		Suspicious: e.Suspicious,
+		Reasons:    reasons,
+		CreatedAt:  e.CreatedAt,
+	}
+}
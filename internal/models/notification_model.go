@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+var TYPE_NOTIFICATION_PREFERENCES = "notificationPreferences"
+
+// NotificationPreferences are the channels an account gets its optional notifications on
+// (e.g. sign-in alerts); verification codes and confirmations are sent whatever they are.
+type NotificationPreferences struct {
+	AccountNumber string    `json:"account_number"`
+	Email         bool      `json:"email"`
+	SMS           bool      `json:"sms"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreferences are the preferences of an account that never changed them:
+// every channel is on.
+func DefaultNotificationPreferences(accountNumber string) *NotificationPreferences {
+	return &NotificationPreferences{AccountNumber: accountNumber, Email: true, SMS: true}
+}
+
+// NotificationDeadLetter is a notification delivery that failed every attempt
+type NotificationDeadLetter struct {
+	ID            int64     `json:"id"`
+	AccountNumber string    `json:"account_number"` // empty for notifications to no user
+	Event         string    `json:"event"`
+	Channel       string    `json:"channel"`
+	Message       string    `json:"message"` // JSON of the notify.Message as rendered
+	Attempts      int       `json:"attempts"`
+	Error         string    `json:"error"`
+	FailedAt      time.Time `json:"failed_at"`
+}
+
+type (
+	UpdateNotificationPreferencesRequest struct {
+		Email *bool `json:"email" example:"true"`
+		SMS   *bool `json:"sms" example:"false"`
+	}
+
+	NotificationPreferencesResponse struct {
+		Type      string     `json:"type" example:"notificationPreferences"`
+		Email     bool       `json:"email" example:"true"`
+		SMS       bool       `json:"sms" example:"false"`
+		UpdatedAt *time.Time `json:"updatedAt" example:"2026-01-24T15:57:37+07:00"` // null until changed
+	}
+)
+
+// Channels returns the preferences per notification channel.
+func (p *NotificationPreferences) Channels() map[string]bool {
+	return map[string]bool{"email": p.Email, "sms": p.SMS}
+}
+
+func (p *NotificationPreferences) NotificationPreferencesResponse() *NotificationPreferencesResponse {
+	response := &NotificationPreferencesResponse{
+		Type:  TYPE_NOTIFICATION_PREFERENCES,
+		Email: p.Email,
+		SMS:   p.SMS,
+	}
+	if !p.UpdatedAt.IsZero() {
+		response.UpdatedAt = &p.UpdatedAt
+	}
+	return response
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+var TYPE_PHONE_CHANGE = "phoneChange"
+
+// PhoneChange is a request to change the phone number of an account. The number is switched
+// once the code sent to it by SMS is verified.
+type PhoneChange struct {
+	ID               int64      `json:"id"`
+	AccountNumber    string     `json:"account_number"`
+	PhoneNumber      string     `json:"phone_number"` // E.164
+	PhoneCountryCode string     `json:"phone_country_code"`
+	CodeHash         string     `json:"-"`        // HMAC-SHA256 of the code, keyed with authorization.signing_secret
+	Attempts         int        `json:"attempts"` // wrong codes entered so far
+	ExpiresAt        time.Time  `json:"expires_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	CancelledAt      *time.Time `json:"cancelled_at"` // superseded by a newer request
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type (
+	ChangePhoneRequest struct {
+		PhoneNumber PhoneNumber `json:"phoneNumber"` // an empty number is rejected by the service
+	}
+
+	VerifyPhoneChangeRequest struct {
+		Code string `json:"code" validate:"required,numeric,len=6" example:"042917"`
+	}
+
+	PhoneChangeResponse struct {
+		Type        string      `json:"type" example:"phoneChange"`
+		PhoneNumber PhoneNumber `json:"phoneNumber"`
+		Completed   bool        `json:"completed" example:"false"` // the account uses the new number
+		ExpiresAt   time.Time   `json:"expiresAt" example:"2026-01-24T16:07:37+07:00"`
+	}
+)
+
+func (c *PhoneChange) PhoneChangeResponse() *PhoneChangeResponse {
+	return &PhoneChangeResponse{
+		Type:        TYPE_PHONE_CHANGE,
+		PhoneNumber: PhoneNumber{Number: c.PhoneNumber, CountryCode: c.PhoneCountryCode},
+		Completed:   c.CompletedAt != nil,
+		ExpiresAt:   c.ExpiresAt,
+	}
+}
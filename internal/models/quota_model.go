@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+var TYPE_QUOTA = "quota"
+
+type (
+	QuotaResponse struct {
+		Type   string               `json:"type" example:"quota"`
+		Client string               `json:"client" example:"partner-a"` // empty for authorization.api_key, which has no quota
+		Quotas []QuotaUsageResponse `json:"quotas"`
+	}
+
+	QuotaUsageResponse struct {
+		Period    string    `json:"period" enums:"daily,monthly" example:"daily"`
+		Limit     int64     `json:"limit" example:"10000"`
+		Used      int64     `json:"used" example:"1250"`
+		Remaining int64     `json:"remaining" example:"8750"`
+		ResetAt   time.Time `json:"resetAt" example:"2026-01-25T00:00:00Z"`
+	}
+)
+
+// QuotaOverride replaces the quotas of authorization.clients for a client, e.g. to unblock it
+// without a deploy. A nil quota keeps the configured one; 0 is unlimited.
+type QuotaOverride struct {
+	Client       string     `json:"client"`
+	DailyQuota   *int64     `json:"daily_quota"`
+	MonthlyQuota *int64     `json:"monthly_quota"`
+	Reason       string     `json:"reason"`
+	ExpiresAt    *time.Time `json:"expires_at"` // nil never expires
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the override applies at now
+func (o *QuotaOverride) Active(now time.Time) bool {
+	return o.ExpiresAt == nil || now.Before(*o.ExpiresAt)
+}
+
+type (
+	SaveQuotaOverrideRequest struct {
+		DailyQuota   *int64     `json:"daily_quota" validate:"omitempty,min=0"` // null keeps the configured quota, 0 is unlimited
+		MonthlyQuota *int64     `json:"monthly_quota" validate:"omitempty,min=0"`
+		Reason       string     `json:"reason" validate:"required,max=255"` // e.g. the support ticket, recorded in the audit log
+		ExpiresAt    *time.Time `json:"expires_at"`                         // the configured quotas apply again after; null never expires
+	}
+
+	QuotaOverrideResponse struct {
+		Client       string     `json:"client" example:"partner-a"`
+		DailyQuota   *int64     `json:"daily_quota" extensions:"x-nullable" example:"20000"`
+		MonthlyQuota *int64     `json:"monthly_quota" extensions:"x-nullable"`
+		Reason       string     `json:"reason" example:"SUP-1234 launch week"`
+		ExpiresAt    *time.Time `json:"expires_at" extensions:"x-nullable" example:"2026-02-01T00:00:00Z"`
+		Active       bool       `json:"active" example:"true"` // false once expired
+		UpdatedAt    time.Time  `json:"updated_at" example:"2026-01-24T15:57:37+07:00"`
+	}
+)
+
+func (o *QuotaOverride) QuotaOverrideResponse(now time.Time) QuotaOverrideResponse {
+	return QuotaOverrideResponse{
+		Client:       o.Client,
+		DailyQuota:   o.DailyQuota,
+		MonthlyQuota: o.MonthlyQuota,
+		Reason:       o.Reason,
+		ExpiresAt:    o.ExpiresAt,
+		Active:       o.Active(now),
+		UpdatedAt:    o.UpdatedAt,
+	}
+}
@@ -9,10 +9,11 @@ type (
 		Total int
 		Limit int
 	}
+	// ErrorResponse is the body of every error response: models.Response without data and pagination
 	ErrorResponse struct {
-		Code     int         `json:"code"`
-		Status   string      `json:"status"`
-		Message  string      `json:"message"`
+		Code     int         `json:"code" example:"404"`
+		Status   string      `json:"status" example:"DATA_NOT_FOUND"`
+		Message  string      `json:"message" example:"user not found"`
 		Errors   interface{} `json:"errors,omitempty"`
 		Metadata Metadata    `json:"metadata"`
 	}
@@ -23,9 +24,17 @@ type (
 		Message    string            `json:"message" example:"Request has been successfully processed."`
 		Data       interface{}       `json:"data,omitempty"`
 		Pagination *PaginationOutput `json:"pagination,omitempty"`
+		Links      *Links            `json:"links,omitempty"`
 		Errors     interface{}       `json:"errors,omitempty"`
 		Metadata   Metadata          `json:"metadata"`
 	}
+	// Links navigate from a response: the request itself and, for a page of a list, its
+	// neighbours; the paths keep the query of the request
+	Links struct {
+		Self string `json:"self" example:"/api/v1/users?page=2"`
+		Next string `json:"next,omitempty" example:"/api/v1/users?page=3"`
+		Prev string `json:"prev,omitempty" example:"/api/v1/users?page=1"`
+	}
 	Metadata struct {
 		RequestId string `json:"requestId"`
 		Timestamp string `json:"timestamp"`
@@ -34,9 +43,9 @@ type (
 )
 
 type ErrorValidationResponse struct {
-	Code    string `json:"code"`
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Code    string `json:"code" example:"MISSING_FIELD"`
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"email is required"`
 }
 
 func (e ErrorValidationResponse) Error() string {
@@ -0,0 +1,117 @@
+package models
+
+import "time"
+
+var TYPE_USER_IMPORT = "userImport"
+
+// Outcomes of an imported row
+const (
+	UserImportImported = "imported"
+	UserImportFailed   = "failed"
+)
+
+type (
+	// UserImportRow is a user to import, decoded from line Line of the file. Errors holds the
+	// problems found while decoding and validating it; rows with errors are not imported.
+	UserImportRow struct {
+		Line   int
+		User   CreateUserRequest
+		Errors []ErrorValidationResponse
+	}
+
+	UserImportResponse struct {
+		Type     string                  `json:"type" example:"userImport"`
+		Total    int                     `json:"total" example:"3"`
+		Imported int                     `json:"imported" example:"2"`
+		Failed   int                     `json:"failed" example:"1"`
+		Rows     []UserImportRowResponse `json:"rows"`
+	}
+
+	// UserImportRowResponse is the outcome of a row; AccountNumber is set when it was
+	// imported, Errors when it failed
+	UserImportRowResponse struct {
+		Line          int                       `json:"line" example:"2"`
+		Status        string                    `json:"status" enums:"imported,failed" example:"failed"`
+		AccountNumber string                    `json:"accountNumber,omitempty" example:"1234567890"`
+		Errors        []ErrorValidationResponse `json:"errors,omitempty"`
+	}
+)
+
+// Fail records that row i of the import failed with errs
+func (r *UserImportResponse) Fail(i int, errs ...ErrorValidationResponse) {
+	r.Rows[i].Status = UserImportFailed
+	r.Rows[i].Errors = errs
+	r.Failed++
+}
+
+// Succeed records that row i of the import created the account accountNumber
+func (r *UserImportResponse) Succeed(i int, accountNumber string) {
+	r.Rows[i].Status = UserImportImported
+	r.Rows[i].AccountNumber = accountNumber
+	r.Imported++
+}
+
+// Export formats
+const (
+	UserExportCSV    = "csv"
+	UserExportNDJSON = "ndjson"
+)
+
+// UserExportColumns is the header of a CSV export, the columns of UserExportRecord. Those an
+// import knows come first under the same names, so an export can be edited and imported.
+var UserExportColumns = []string{
+	"name", "username", "email", "phone_number", "phone_country_code",
+	"account_number", "created_at", "deletion_scheduled_at",
+}
+
+type (
+	ExportUserRequest struct {
+		Format string `query:"format" json:"format" validate:"omitempty,oneof=csv ndjson" example:"csv"`
+	}
+
+	// UserExportResponse is a line of an NDJSON export
+	UserExportResponse struct {
+		AccountNumber       string       `json:"accountNumber" example:"1234567890"`
+		Name                string       `json:"name" example:"John Doe"`
+		Username            string       `json:"username,omitempty" example:"john.doe"`
+		Email               string       `json:"email,omitempty" example:"john.doe@example.com"`
+		PhoneNumber         *PhoneNumber `json:"phoneNumber,omitempty"`
+		CreatedAt           time.Time    `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
+		DeletionScheduledAt *time.Time   `json:"deletionScheduledAt,omitempty" example:"2026-02-23T15:57:37+07:00"`
+	}
+)
+
+// UserExportRecord is the CSV row of the user, in the order of UserExportColumns
+func (u *User) UserExportRecord() []string {
+	record := []string{u.Name, u.username(), "", "", "", u.AccountNumber, u.CreatedAt.Format(time.RFC3339), ""}
+	if u.Email != nil {
+		record[2] = *u.Email
+	}
+	if u.PhoneNumber != nil {
+		record[3], record[4] = *u.PhoneNumber, u.PhoneCountryCode
+	}
+	if u.DeletionScheduledAt != nil {
+		record[7] = u.DeletionScheduledAt.Format(time.RFC3339)
+	}
+	return record
+}
+
+func (u *User) UserExportResponse() *UserExportResponse {
+	response := &UserExportResponse{
+		AccountNumber:       u.AccountNumber,
+		Name:                u.Name,
+		Username:            u.username(),
+		CreatedAt:           u.CreatedAt,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+	}
+	if u.Email != nil {
+		response.Email = *u.Email
+	}
+	if u.PhoneNumber != nil {
+		response.PhoneNumber = &PhoneNumber{
+			Number:      *u.PhoneNumber,
+			CountryCode: u.PhoneCountryCode,
+		}
+	}
+	return response
+}
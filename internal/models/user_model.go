@@ -11,6 +11,7 @@ type User struct {
 	ID               int          `json:"id"`
 	AccountNumber    string       `json:"account_number"`
 	Name             string       `json:"name"`
+	Username         *string      `json:"username"`
 	Email            *string      `json:"email"`
 	PhoneNumber      *string      `json:"phone_number"`
 	PhoneCountryCode string       `json:"phone_country_code"`
@@ -18,20 +19,35 @@ type User struct {
 	CreatedAt        time.Time    `json:"created_at"`
 	UpdatedAt        time.Time    `json:"updated_at"`
 	DeletedAt        sql.NullTime `json:"deleted_at"`
+
+	// DeletionScheduledAt is when the account is anonymized, nil unless deletion was requested
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+
+	// Version is bumped by every update, for optimistic locking (see pgsql.UpdateVersioned)
+	Version int `json:"version" gorm:"default:1"`
+
+	Audit
 }
 
 type (
 	CreateUserRequest struct {
 		Name        string      `json:"name" validate:"required" example:"John Doe"`
+		Username    string      `json:"username" validate:"omitempty,username" example:"john.doe"` // optional, unique regardless of case
 		Email       string      `json:"email" validate:"required_without=PhoneNumber,omitempty,emailFormat" example:"john.doe@example.com"`
 		PhoneNumber PhoneNumber `json:"phoneNumber" validate:"required_without=Email,omitempty"`
 		Password    string      `json:"password" validate:"required" example:"password123"`
+
+		// TermsVersion is the terms version the user accepted, required to be the current one
+		// when consent.terms_version is set
+		TermsVersion   string `json:"termsVersion" validate:"omitempty,max=32" example:"2026-10-01"`
+		MarketingOptIn bool   `json:"marketingOptIn" example:"false"`
 	}
 
 	CreateUserResponse struct {
 		Type          string      `json:"type" example:"user"`
 		AccountNumber string      `json:"accountNumber" example:"1234567890"`
 		Name          string      `json:"name" example:"John Doe"`
+		Username      string      `json:"username,omitempty" example:"john.doe"`
 		Email         string      `json:"email" example:"john.doe@example.com"`
 		PhoneNumber   PhoneNumber `json:"phoneNumber"`
 		CreatedAt     time.Time   `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
@@ -54,6 +70,7 @@ func (u *User) CreateUserResponse() *CreateUserResponse {
 		Type:          TYPE_USER,
 		AccountNumber: u.AccountNumber,
 		Name:          u.Name,
+		Username:      u.username(),
 		Email:         email,
 		PhoneNumber:   PhoneNumber{Number: phoneNumber, CountryCode: u.PhoneCountryCode},
 		CreatedAt:     u.CreatedAt,
@@ -61,10 +78,27 @@ func (u *User) CreateUserResponse() *CreateUserResponse {
 	}
 }
 
+// DeleteUserResponse confirms a deletion request
+type DeleteUserResponse struct {
+	Type                string    `json:"type" example:"user"`
+	AccountNumber       string    `json:"accountNumber" example:"1234567890"`
+	DeletionScheduledAt time.Time `json:"deletionScheduledAt" example:"2026-11-15T15:57:37+07:00"`
+}
+
+func (u *User) DeleteUserResponse() *DeleteUserResponse {
+	response := &DeleteUserResponse{Type: TYPE_USER, AccountNumber: u.AccountNumber}
+	if u.DeletionScheduledAt != nil {
+		response.DeletionScheduledAt = *u.DeletionScheduledAt
+	}
+	return response
+}
+
 type (
+	// GetUserTokenRequest signs in with one of the email, the phone number, or the username
 	GetUserTokenRequest struct {
-		Email       string      `json:"email" validate:"required_without=PhoneNumber,omitempty,emailFormat" example:"john.doe@example.com"`
-		PhoneNumber PhoneNumber `json:"phoneNumber" validate:"required_without=Email,omitempty"`
+		Email       string      `json:"email" validate:"required_without_all=PhoneNumber Username,omitempty,emailFormat" example:"john.doe@example.com"`
+		PhoneNumber PhoneNumber `json:"phoneNumber" validate:"required_without_all=Email Username,omitempty"`
+		Username    string      `json:"username" validate:"omitempty,max=64" example:"john.doe"`
 		Password    string      `json:"password" validate:"required" example:"password123"`
 	}
 
@@ -72,6 +106,7 @@ type (
 		Type          string      `json:"type" example:"user"`
 		AccountNumber string      `json:"accountNumber" example:"1234567890"`
 		Name          string      `json:"name" example:"John Doe"`
+		Username      string      `json:"username,omitempty" example:"john.doe"`
 		Email         string      `json:"email" example:"john.doe@example.com"`
 		PhoneNumber   PhoneNumber `json:"phoneNumber"`
 		Tokens        []Token     `json:"tokens"`
@@ -83,10 +118,18 @@ type (
 		Type          string      `json:"type" example:"user"`
 		AccountNumber string      `json:"accountNumber" example:"1234567890"`
 		Name          string      `json:"name" example:"John Doe"`
+		Username      string      `json:"username,omitempty" example:"john.doe"`
 		Email         string      `json:"email" example:"john.doe@example.com"`
 		PhoneNumber   PhoneNumber `json:"phoneNumber"`
 		CreatedAt     time.Time   `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
 		UpdatedAt     time.Time   `json:"updatedAt" example:"2026-01-24T15:57:37+07:00"`
+		Version       int         `json:"version" example:"1"` // send back in UpdateUserRequest
+	}
+
+	// UpdateUserRequest changes the profile of a user at the version it was read at
+	UpdateUserRequest struct {
+		Name    string `json:"name" validate:"required,max=255" example:"John Doe"`
+		Version int    `json:"version" validate:"required,min=1" example:"1"`
 	}
 )
 
@@ -105,6 +148,7 @@ func (u *User) GetUserByAccountNumberResponse() *GetUserByAccountNumberResponse
 		Type:          TYPE_USER,
 		AccountNumber: u.AccountNumber,
 		Name:          u.Name,
+		Username:      u.username(),
 		Email:         email,
 		PhoneNumber: PhoneNumber{
 			Number:      phoneNumber,
@@ -112,5 +156,161 @@ func (u *User) GetUserByAccountNumberResponse() *GetUserByAccountNumberResponse
 		},
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
+		Version:   u.Version,
+	}
+}
+
+type (
+	ListUserRequest struct {
+		Name        string `query:"name" json:"name" validate:"omitempty,max=255" example:"John"`
+		CreatedFrom string `query:"createdFrom" json:"createdFrom" validate:"omitempty,yyymmddFormat" example:"2026-01-01"`
+		CreatedTo   string `query:"createdTo" json:"createdTo" validate:"omitempty,yyymmddFormat" example:"2026-01-31"`
+		Sort        string `query:"sort" json:"sort" validate:"omitempty,oneof=name -name createdAt -createdAt" example:"-createdAt"`
+		Page        int    `query:"page" json:"page" validate:"omitempty,min=1" example:"1"`
+		Limit       int    `query:"limit" json:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	}
+
+	// UserFilter is the repository-level filter built from ListUserRequest; the where tags
+	// are the conditions of pgsql.Query.Filter
+	UserFilter struct {
+		Name        string     `where:"name,contains"`
+		CreatedFrom *time.Time `where:"created_at,gte"`
+		CreatedTo   *time.Time `where:"created_at,lt"` // exclusive upper bound
+		SortBy      string     // "name" or "created_at"
+		SortDesc    bool
+		Limit       int
+		Offset      int
+	}
+
+	// UserIterationFilter selects the users of UserRepository.Iterate; zero fields select all
+	UserIterationFilter struct {
+		Name           string     // partial, case-insensitive
+		CreatedFrom    *time.Time // inclusive
+		CreatedTo      *time.Time // exclusive
+		IncludeDeleted bool
+	}
+
+	UserSummaryResponse struct {
+		Type          string    `json:"type" example:"user"`
+		AccountNumber string    `json:"accountNumber" example:"1234567890"`
+		Name          string    `json:"name" example:"John Doe"`
+		CreatedAt     time.Time `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
+	}
+)
+
+func (u *User) UserSummaryResponse() *UserSummaryResponse {
+	return &UserSummaryResponse{
+		Type:          TYPE_USER,
+		AccountNumber: u.AccountNumber,
+		Name:          u.Name,
+		CreatedAt:     u.CreatedAt,
+	}
+}
+
+type (
+	// SearchUserRequest is the query of the admin user search
+	SearchUserRequest struct {
+		Q     string `query:"q" json:"q" validate:"required,min=2,max=100" example:"john"`
+		Page  int    `query:"page" json:"page" validate:"omitempty,min=1" example:"1"`
+		Limit int    `query:"limit" json:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	}
+
+	// UserSearchFilter is the repository-level filter built from SearchUserRequest
+	UserSearchFilter struct {
+		Query  string // trimmed and lowercased
+		Limit  int
+		Offset int
+	}
+
+	// UserSearchResult is a user matching a search; Rank is how well it matches, from 0 to 1
+	UserSearchResult struct {
+		User
+		Rank float64
+	}
+
+	UserSearchResponse struct {
+		Type                string       `json:"type" example:"user"`
+		AccountNumber       string       `json:"accountNumber" example:"1234567890"`
+		Name                string       `json:"name" example:"John Doe"`
+		Username            string       `json:"username,omitempty" example:"john.doe"`
+		Email               string       `json:"email,omitempty" example:"john.doe@example.com"`
+		PhoneNumber         *PhoneNumber `json:"phoneNumber,omitempty"`
+		Rank                float64      `json:"rank" example:"0.8"`
+		CreatedAt           time.Time    `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
+		DeletionScheduledAt *time.Time   `json:"deletionScheduledAt,omitempty" example:"2026-02-23T15:57:37+07:00"`
+	}
+)
+
+func (r *UserSearchResult) UserSearchResponse() *UserSearchResponse {
+	response := &UserSearchResponse{
+		Type:                TYPE_USER,
+		AccountNumber:       r.AccountNumber,
+		Name:                r.Name,
+		Username:            r.username(),
+		Rank:                r.Rank,
+		CreatedAt:           r.CreatedAt,
+		DeletionScheduledAt: r.DeletionScheduledAt,
+	}
+	if r.Email != nil {
+		response.Email = *r.Email
+	}
+	if r.PhoneNumber != nil {
+		response.PhoneNumber = &PhoneNumber{
+			Number:      *r.PhoneNumber,
+			CountryCode: r.PhoneCountryCode,
+		}
+	}
+	return response
+}
+
+// username returns the username of the user, empty when it has none
+func (u *User) username() string {
+	if u.Username == nil {
+		return ""
+	}
+	return *u.Username
+}
+
+type (
+	UsernameAvailabilityRequest struct {
+		Username string `query:"username" json:"username" validate:"required,max=64" example:"john.doe"`
+	}
+
+	// UsernameAvailabilityResponse tells whether a username can be registered; Reason is set
+	// when it cannot
+	UsernameAvailabilityResponse struct {
+		Type      string `json:"type" example:"username_availability"`
+		Username  string `json:"username" example:"john.doe"`
+		Available bool   `json:"available" example:"false"`
+		Reason    string `json:"reason,omitempty" enums:"invalid,reserved,taken" example:"taken"`
+	}
+)
+
+var TYPE_USERNAME_AVAILABILITY = "username_availability"
+
+// Reasons a username is not available
+const (
+	UsernameInvalid  = "invalid"
+	UsernameReserved = "reserved"
+	UsernameTaken    = "taken"
+)
+
+// UserV2Response is the /api/v2 user shape: contact details are grouped and absent ones are null
+func (u *User) UserV2Response() *UserV2Response {
+	response := &UserV2Response{
+		Type:          TYPE_USER,
+		AccountNumber: u.AccountNumber,
+		Name:          u.Name,
+		Username:      u.Username,
+		Contact:       UserContact{Email: u.Email},
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	}
+	if u.PhoneNumber != nil {
+		response.Contact.PhoneNumber = &PhoneNumber{
+			Number:      *u.PhoneNumber,
+			CountryCode: u.PhoneCountryCode,
+		}
 	}
+	return response
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type (
+	APIVersionResponse struct {
+		Name       string     `json:"name" example:"v1"`
+		Status     string     `json:"status" example:"supported"` // supported or deprecated
+		Deprecated *time.Time `json:"deprecated,omitempty" example:"2026-06-01T00:00:00Z"`
+		Sunset     *time.Time `json:"sunset,omitempty" example:"2027-01-01T00:00:00Z"`
+		Successor  string     `json:"successor,omitempty" example:"v2"`
+	}
+
+	UserV2Response struct {
+		Type          string      `json:"type" example:"user"`
+		AccountNumber string      `json:"accountNumber" example:"1234567890"`
+		Name          string      `json:"name" example:"John Doe"`
+		Username      *string     `json:"username" extensions:"x-nullable" example:"john.doe"`
+		Contact       UserContact `json:"contact"`
+		CreatedAt     time.Time   `json:"createdAt" example:"2026-01-24T15:57:37+07:00"`
+		UpdatedAt     time.Time   `json:"updatedAt" example:"2026-01-24T15:57:37+07:00"`
+	}
+
+	UserContact struct {
+		Email       *string      `json:"email" extensions:"x-nullable" example:"john.doe@example.com"`
+		PhoneNumber *PhoneNumber `json:"phoneNumber" extensions:"x-nullable"`
+	}
+)
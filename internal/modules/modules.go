@@ -0,0 +1,7 @@
+// Package modules enables the feature modules: importing it registers each of them with
+// core.RegisterModule. A new feature adds its blank import here.
+package modules
+
+import (
+	_ "go-echo-boilerplate/internal/modules/users"
+)
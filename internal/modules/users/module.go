@@ -0,0 +1,53 @@
+// Package users is the user accounts feature as a core.Module: registration, tokens, and
+// profile endpoints. Its handlers, service, and repository predate modules and stay in their
+// layer packages, which other code (transactions, the admin CLI) builds on directly.
+package users
+
+import (
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/deliveries/http/api"
+	v1 "go-echo-boilerplate/internal/deliveries/http/api/v1"
+	v2 "go-echo-boilerplate/internal/deliveries/http/api/v2"
+	"go-echo-boilerplate/internal/pkg/di"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"go-echo-boilerplate/internal/service"
+	"go-echo-boilerplate/migration/db/postgre"
+	"io/fs"
+)
+
+func init() {
+	core.RegisterModule(Module{})
+}
+
+// Module registers the users feature
+type Module struct{}
+
+// Name implements core.Module.
+func (Module) Name() string {
+	return "users"
+}
+
+// RegisterRepositories implements core.Module.
+func (Module) RegisterRepositories(c *di.Container) {
+	di.Provide(c, func(repository *pgsql.PostgreRepository) pgsql.UserRepository {
+		return repository.User
+	})
+}
+
+// RegisterServices implements core.Module.
+func (Module) RegisterServices(c *di.Container) {
+	di.Provide(c, service.NewUserService)
+}
+
+// RegisterRoutes implements core.Module. v2 only serves the endpoints whose contract
+// changed; the rest stay on v1.
+func (Module) RegisterRoutes(routes *api.Routes) error {
+	v1.NewUserV1(routes.Version("v1"), routes.Service, routes.Config, routes.JWTConfig)
+	v2.NewUserV2(routes.Version("v2"), routes.Service, routes.JWTConfig)
+	return nil
+}
+
+// Migrations implements core.Module.
+func (Module) Migrations() fs.FS {
+	return postgre.FS
+}
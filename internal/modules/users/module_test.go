@@ -0,0 +1,26 @@
+package users_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"go-echo-boilerplate/internal/core"
+	"go-echo-boilerplate/internal/modules/users"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule(t *testing.T) {
+	registered := false
+	for _, module := range core.Modules() {
+		registered = registered || module.Name() == "users"
+	}
+	assert.True(t, registered, "the module registers itself on import")
+
+	migrations, err := core.Migrations(users.Module{})
+	require.NoError(t, err)
+	names, err := fs.Glob(migrations, "*.sql")
+	require.NoError(t, err)
+	assert.Contains(t, names, "20260114144004_users.sql")
+}
@@ -0,0 +1,26 @@
+// Package actor carries who performs a request through its context: the account number of
+// a bearer token (middleware.BearerAuthMiddleware) or Admin behind the admin key
+// (middleware.AdminKey). The database callbacks record it in the created_by and updated_by
+// columns of models.Audit.
+package actor
+
+import "context"
+
+// Admin is the actor of requests authorized with the admin key
+const Admin = "admin"
+
+type contextKey struct{}
+
+// WithActor returns a copy of ctx performed by id; an empty id leaves ctx as is.
+func WithActor(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the actor of ctx, and false for anonymous requests and background jobs.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
@@ -0,0 +1,22 @@
+package actor_test
+
+import (
+	"context"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/actor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor(t *testing.T) {
+	_, ok := actor.FromContext(context.Background())
+	assert.False(t, ok)
+
+	id, ok := actor.FromContext(actor.WithActor(context.Background(), "1234567890"))
+	assert.True(t, ok)
+	assert.Equal(t, "1234567890", id)
+
+	_, ok = actor.FromContext(actor.WithActor(context.Background(), ""))
+	assert.False(t, ok, "an empty actor is anonymous")
+}
@@ -0,0 +1,97 @@
+// Package binder binds query, path, and header parameters into tagged structs
+// and validates them with validator.Input, mirroring what handlers already do
+// for JSON bodies.
+//
+// Struct fields use Echo's binding tags (`query`, `param`, `header`) and should
+// also carry a `json` tag so validation errors report the same field name the
+// client sent.
+//
+// Example:
+//
+//	type ListRequest struct {
+//	    Page  int    `query:"page" json:"page" validate:"omitempty,min=1"`
+//	    Name  string `query:"name" json:"name" validate:"omitempty,max=255"`
+//	}
+//
+//	var request ListRequest
+//	if err := binder.Query(ctx, &request); err != nil {
+//	    return response.ErrorBinding(ctx, err)
+//	}
+package binder
+
+import (
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+)
+
+// defaultBinder is the Echo binder used for parameter binding.
+var defaultBinder = &echo.DefaultBinder{}
+
+// Query binds URL query parameters (`query` tags) into dst and validates it.
+func Query(ctx echo.Context, dst interface{}) error {
+	return bindAndValidate(ctx, dst, "query", defaultBinder.BindQueryParams)
+}
+
+// Path binds path parameters (`param` tags) into dst and validates it.
+func Path(ctx echo.Context, dst interface{}) error {
+	return bindAndValidate(ctx, dst, "path", defaultBinder.BindPathParams)
+}
+
+// Header binds request headers (`header` tags) into dst and validates it.
+func Header(ctx echo.Context, dst interface{}) error {
+	return bindAndValidate(ctx, dst, "header", defaultBinder.BindHeaders)
+}
+
+// Params binds path, query, and header parameters (in that order) into dst
+// and validates it once all sources are bound.
+func Params(ctx echo.Context, dst interface{}) error {
+	sources := []struct {
+		name string
+		bind func(echo.Context, interface{}) error
+	}{
+		{"path", defaultBinder.BindPathParams},
+		{"query", defaultBinder.BindQueryParams},
+		{"header", defaultBinder.BindHeaders},
+	}
+
+	for _, source := range sources {
+		if err := bind(ctx, dst, source.name, source.bind); err != nil {
+			return err
+		}
+	}
+
+	return validator.Input(dst, i18n.FromContext(ctx.Request().Context()))
+}
+
+// bindAndValidate binds a single source and validates the result.
+func bindAndValidate(ctx echo.Context, dst interface{}, source string, fn func(echo.Context, interface{}) error) error {
+	if err := bind(ctx, dst, source, fn); err != nil {
+		return err
+	}
+
+	return validator.Input(dst, i18n.FromContext(ctx.Request().Context()))
+}
+
+// bind runs the binder and converts Echo's binding errors (e.g. "abc" into an int)
+// into a 400 errorc.HTTPError.
+func bind(ctx echo.Context, dst interface{}, source string, fn func(echo.Context, interface{}) error) error {
+	if err := fn(ctx, dst); err != nil {
+		message := err.Error()
+
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			if msg, ok := httpErr.Message.(string); ok {
+				message = msg
+			}
+		}
+
+		return errorc.Error(errorc.ErrorInvalidInput, fmt.Sprintf("invalid %s parameter: %s", source, message))
+	}
+
+	return nil
+}
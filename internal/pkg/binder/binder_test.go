@@ -0,0 +1,162 @@
+package binder_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/binder"
+	"go-echo-boilerplate/internal/pkg/errorc"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listRequest struct {
+	ID    string `param:"id" json:"id" validate:"required"`
+	Page  int    `query:"page" json:"page" validate:"omitempty,min=1"`
+	Limit int    `query:"limit" json:"limit" validate:"omitempty,max=100"`
+	Trace string `header:"X-Trace" json:"trace"`
+}
+
+func newContext(target string) echo.Context {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return e.NewContext(req, httptest.NewRecorder())
+}
+
+func TestQuery(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var request models.ListUserRequest
+		err := binder.Query(newContext("/users?page=2&limit=10&sort=-name"), &request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, request.Page)
+		assert.Equal(t, 10, request.Limit)
+		assert.Equal(t, "-name", request.Sort)
+	})
+
+	t.Run("Type Mismatch", func(t *testing.T) {
+		var request models.ListUserRequest
+		err := binder.Query(newContext("/users?page=abc"), &request)
+
+		httpErr, ok := err.(*errorc.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusBadRequest, httpErr.Response.Code)
+			assert.Contains(t, httpErr.Response.Message, "invalid query parameter")
+		}
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		var request models.ListUserRequest
+		err := binder.Query(newContext("/users?limit=500&sort=password"), &request)
+
+		merr, ok := err.(*multierror.Error)
+		if assert.True(t, ok) {
+			assert.Len(t, merr.Errors, 2)
+		}
+	})
+}
+
+func TestParams(t *testing.T) {
+	ctx := newContext("/items/abc?page=3")
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("abc")
+	ctx.Request().Header.Set("X-Trace", "t-1")
+
+	var request listRequest
+	err := binder.Params(ctx, &request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", request.ID)
+	assert.Equal(t, 3, request.Page)
+	assert.Equal(t, "t-1", request.Trace)
+}
+
+func TestList(t *testing.T) {
+	spec := models.ListSpec{
+		MaxLimit:    50,
+		Sorts:       []string{"name", "createdAt"},
+		DefaultSort: "-createdAt",
+		Filters: map[string][]string{
+			"name":      {models.FilterEq, models.FilterContains},
+			"createdAt": {models.FilterGte, models.FilterLt},
+		},
+		Cursor: true,
+	}
+	fields := func(err error) []string {
+		merr, ok := err.(*multierror.Error)
+		if !assert.True(t, ok, "%v", err) {
+			return nil
+		}
+		var fields []string
+		for _, e := range merr.Errors {
+			fields = append(fields, e.(models.ErrorValidationResponse).Field)
+		}
+		return fields
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var query models.ListQuery
+		err := binder.List(newContext("/items?page=3&limit=10&sort=name,-createdAt&filter[name][contains]=jo&filter[createdAt][gte]=2026-01-01"), spec, &query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.ListFilter{
+			{Field: "createdAt", Operator: models.FilterGte, Value: "2026-01-01"},
+			{Field: "name", Operator: models.FilterContains, Value: "jo"},
+		}, query.Filters)
+
+		options := query.Options(spec)
+		assert.Equal(t, []models.ListSort{{Field: "name"}, {Field: "createdAt", Desc: true}}, options.Sorts)
+		assert.Equal(t, 10, options.Limit)
+		assert.Equal(t, 20, options.Offset)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		var query models.ListQuery
+		assert.NoError(t, binder.List(newContext("/items?filter[name]=john"), spec, &query))
+
+		options := query.Options(spec)
+		assert.Equal(t, []models.ListFilter{{Field: "name", Operator: models.FilterEq, Value: "john"}}, options.Filters)
+		assert.Equal(t, []models.ListSort{{Field: "createdAt", Desc: true}}, options.Sorts)
+		assert.Equal(t, models.DefaultListLimit, options.Limit)
+		assert.Zero(t, options.Offset)
+	})
+
+	t.Run("Rejects What The Spec Doesn't Allow", func(t *testing.T) {
+		var query models.ListQuery
+		err := binder.List(newContext("/items?limit=51&sort=password&filter[email]=x&filter[name][gt]=a&filter[name=b"), spec, &query)
+
+		assert.ElementsMatch(t, []string{"limit", "sort", "filter[email]", "filter[name][gt]", "filter[name"}, fields(err))
+		assert.Empty(t, query.Filters)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		var first models.ListQuery
+		require.NoError(t, binder.List(newContext("/items?limit=2"), spec, &first))
+		next := first.Options(spec).NextCursor(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 7)
+
+		var query models.ListQuery
+		require.NoError(t, binder.List(newContext("/items?limit=2&cursor="+next), spec, &query))
+		options := query.Options(spec)
+		assert.Equal(t, &models.Cursor{Sort: "-createdAt", Value: "2026-01-01T00:00:00Z", ID: 7}, options.After)
+		assert.Zero(t, options.Offset)
+
+		for target, field := range map[string]string{
+			"/items?cursor=" + next + "&page=2":    "cursor",
+			"/items?cursor=" + next + "&sort=name": "cursor",
+			"/items?cursor=not-a-cursor":           "cursor",
+		} {
+			var query models.ListQuery
+			assert.Equal(t, []string{field}, fields(binder.List(newContext(target), spec, &query)), target)
+		}
+
+		pageOnly := spec
+		pageOnly.Cursor = false
+		assert.Equal(t, []string{"cursor"}, fields(binder.List(newContext("/items?cursor="+next), pageOnly, &query)))
+	})
+}
@@ -0,0 +1,141 @@
+package binder
+
+import (
+	"errors"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/labstack/echo/v4"
+)
+
+// maxFilterValueLength caps the value of a filter of a list query
+const maxFilterValueLength = 255
+
+// List binds the query of a list endpoint into query, its filters included, and validates it
+// against spec: the limit at most spec.MaxLimit, the sort and filter fields and the filter
+// operators whitelisted, and a cursor only where spec accepts one, for a single sort field.
+// The errors are a validation error per field, like Query.
+//
+//	var query models.ListQuery
+//	if err := binder.List(ctx, userListSpec, &query); err != nil {
+//	    return response.ErrorBinding(ctx, err)
+//	}
+//	users, total, err := h.service.User.Find(ctx.Request().Context(), query.Options(userListSpec))
+func List(ctx echo.Context, spec models.ListSpec, query *models.ListQuery) error {
+	if err := bind(ctx, query, "query", defaultBinder.BindQueryParams); err != nil {
+		return err
+	}
+	locale := i18n.FromContext(ctx.Request().Context())
+
+	var errs *multierror.Error
+	if err := validator.Input(query, locale); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+	add := func(field, key string, args ...any) {
+		errs = multierror.Append(errs, models.ErrorValidationResponse{
+			Code:    validator.ErrorCodeInvalidField,
+			Field:   field,
+			Message: i18n.T(locale, key, append([]any{field}, args...)...),
+		})
+	}
+
+	maxLimit := spec.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = models.MaxListLimit
+	}
+	if query.Limit > maxLimit {
+		add("limit", "validation.max.number", strconv.Itoa(maxLimit))
+	}
+
+	sorts := models.ParseListSort(query.Sort)
+	for i, s := range sorts {
+		if !slices.Contains(spec.Sorts, s.Field) || slices.ContainsFunc(sorts[:i], func(other models.ListSort) bool { return other.Field == s.Field }) {
+			add("sort", "validation.listSort", strings.Join(spec.Sorts, ", "))
+			break
+		}
+	}
+
+	params := ctx.QueryParams()
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	query.Filters = nil
+	for _, key := range keys {
+		field, operator, ok := parseFilterKey(key)
+		operators, known := spec.Filters[field]
+		switch {
+		case !ok || !known:
+			add(key, "validation.listFilter", strings.Join(filterFields(spec), ", "))
+			continue
+		case !slices.Contains(operators, operator):
+			add(key, "validation.listFilterOperator", operator, strings.Join(operators, ", "))
+			continue
+		}
+		for _, value := range params[key] {
+			if len(value) > maxFilterValueLength {
+				add(key, "validation.max", strconv.Itoa(maxFilterValueLength))
+				continue
+			}
+			query.Filters = append(query.Filters, models.ListFilter{Field: field, Operator: operator, Value: value})
+		}
+	}
+
+	if query.Cursor != "" {
+		sortKey := query.Sort
+		if sortKey == "" {
+			sortKey = spec.DefaultSort
+		}
+		switch cursor, err := models.DecodeCursor(query.Cursor); {
+		case !spec.Cursor:
+			add("cursor", "validation.cursorUnsupported")
+		case query.Page > 0:
+			add("cursor", "validation.cursorPage")
+		case err != nil || cursor.Sort != models.FormatListSort(models.ParseListSort(sortKey)) || strings.Contains(sortKey, ","):
+			add("cursor", "validation.cursor")
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// parseFilterKey parses filter[field] and filter[field][operator], the operator of the former
+// being models.FilterEq; ok is false for a malformed key
+func parseFilterKey(key string) (field, operator string, ok bool) {
+	rest, ok := strings.CutPrefix(key, "filter[")
+	if !ok {
+		return "", "", false
+	}
+	field, rest, ok = strings.Cut(rest, "]")
+	if !ok || field == "" {
+		return "", "", false
+	}
+	if rest == "" {
+		return field, models.FilterEq, true
+	}
+	operator, ok = strings.CutPrefix(rest, "[")
+	if !ok {
+		return "", "", false
+	}
+	operator, ok = strings.CutSuffix(operator, "]")
+	return field, operator, ok && operator != ""
+}
+
+// filterFields lists the filterable fields of spec, sorted
+func filterFields(spec models.ListSpec) []string {
+	fields := make([]string, 0, len(spec.Filters))
+	for field := range spec.Filters {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
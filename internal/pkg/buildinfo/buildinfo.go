@@ -0,0 +1,73 @@
+// Package buildinfo describes the build of the running binary. Version, Commit, and BuildTime
+// are injected at build time (see BUILD_FLAGS in the Makefile):
+//
+//	go build -ldflags "-X go-echo-boilerplate/internal/pkg/buildinfo.Version=v1.2.0 \
+//		-X go-echo-boilerplate/internal/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//		-X go-echo-boilerplate/internal/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them, Commit and BuildTime fall back to the VCS stamp of go build.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Injected with -ldflags -X, empty otherwise
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// Info is the build of the running binary
+type Info struct {
+	Version   string // empty unless injected
+	Commit    string // the revision, suffixed with -dirty for uncommitted changes
+	BuildTime string // RFC 3339; for the VCS stamp, the time of the commit
+	GoVersion string
+	OS        string
+	Arch      string
+}
+
+// Get returns the build of the running binary
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if info.Commit != "" && info.BuildTime != "" {
+		return info
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	var revision, time string
+	var modified bool
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			time = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if info.Commit == "" && revision != "" {
+		info.Commit = revision
+		if modified {
+			info.Commit += "-dirty"
+		}
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = time
+	}
+	return info
+}
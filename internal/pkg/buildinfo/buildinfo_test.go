@@ -0,0 +1,22 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Cleanup(func() { Version, Commit, BuildTime = "", "", "" })
+	Version, Commit, BuildTime = "v1.2.0", "abc123", "2026-01-01T00:00:00Z"
+
+	assert.Equal(t, Info{
+		Version:   "v1.2.0",
+		Commit:    "abc123",
+		BuildTime: "2026-01-01T00:00:00Z",
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}, Get())
+}
@@ -0,0 +1,256 @@
+package cache_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) cache.Store{
+		"Memory": func(t *testing.T) cache.Store { return cache.NewMemoryStore(10) },
+		"Redis": func(t *testing.T) cache.Store {
+			client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+			return cache.NewRedisStore(client)
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			require.NoError(t, store.Set(ctx, "group", "a", []byte("1"), time.Minute))
+			require.NoError(t, store.Set(ctx, "group", "b", []byte("2"), time.Minute))
+			require.NoError(t, store.Set(ctx, "other", "a", []byte("3"), time.Minute))
+
+			value, ok, err := store.Get(ctx, "group", "b")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("2"), value)
+
+			_, ok, err = store.Get(ctx, "group", "missing")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, store.Delete(ctx, "group"))
+			_, ok, _ = store.Get(ctx, "group", "a")
+			assert.False(t, ok, "the whole group is dropped")
+			_, ok, _ = store.Get(ctx, "other", "a")
+			assert.True(t, ok, "other groups are kept")
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Expiry", func(t *testing.T) {
+		store := cache.NewMemoryStore(10)
+		require.NoError(t, store.Set(ctx, "group", "a", []byte("1"), time.Millisecond))
+		time.Sleep(5 * time.Millisecond)
+
+		_, ok, _ := store.Get(ctx, "group", "a")
+		assert.False(t, ok)
+		assert.Zero(t, store.Len(), "empty groups are removed")
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		store := cache.NewMemoryStore(2)
+		require.NoError(t, store.Set(ctx, "a", "", []byte("1"), time.Minute))
+		require.NoError(t, store.Set(ctx, "b", "", []byte("2"), time.Minute))
+		_, _, _ = store.Get(ctx, "a", "") // a is now the most recently used
+		require.NoError(t, store.Set(ctx, "c", "", []byte("3"), time.Minute))
+
+		assert.Equal(t, 2, store.Len())
+		_, ok, _ := store.Get(ctx, "b", "")
+		assert.False(t, ok, "the least recently used group is evicted")
+		_, ok, _ = store.Get(ctx, "a", "")
+		assert.True(t, ok)
+	})
+}
+
+func TestResponses(t *testing.T) {
+	ctx := context.Background()
+	responses := cache.NewResponses(cache.NewMemoryStore(10), time.Minute)
+	resp := cache.Response{Status: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte(`{"a":1}`)}
+
+	require.NoError(t, responses.Set(ctx, "user-1", "/me", resp, 0))
+	require.NoError(t, responses.Set(ctx, "user-1", "/accounts?fields=name", resp, 0))
+
+	cached, ok, err := responses.Get(ctx, "user-1", "/me")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, resp.Body, cached.Body)
+	assert.Equal(t, "application/json", cached.Header.Get("Content-Type"))
+	assert.WithinDuration(t, time.Now().Add(time.Minute), cached.Expires, time.Second)
+
+	_, ok, _ = responses.Get(ctx, "user-2", "/me")
+	assert.False(t, ok, "entries are per subject")
+
+	require.NoError(t, responses.Invalidate(ctx, "user-1"))
+	_, ok, _ = responses.Get(ctx, "user-1", "/me")
+	assert.False(t, ok)
+	_, ok, _ = responses.Get(ctx, "user-1", "/accounts?fields=name")
+	assert.False(t, ok, "every response of the subject is dropped")
+
+	t.Run("Nil", func(t *testing.T) {
+		var responses *cache.Responses
+		assert.NoError(t, responses.Set(ctx, "user-1", "/me", resp, 0))
+		_, ok, err := responses.Get(ctx, "user-1", "/me")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.NoError(t, responses.Invalidate(ctx, "user-1"))
+	})
+}
+
+func TestFromConfig(t *testing.T) {
+	responses, err := cache.FromConfig(config.ResponseCache{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cache.DefaultTTL, responses.TTL())
+
+	responses, err = cache.FromConfig(config.ResponseCache{TTL: "5m"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, responses.TTL())
+
+	_, err = cache.FromConfig(config.ResponseCache{Store: "redis"}, nil)
+	assert.Error(t, err)
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	type user struct {
+		Name     string
+		Password string `json:"-"`
+	}
+
+	t.Run("Loads Once Then Serves The Cache", func(t *testing.T) {
+		loader := cache.NewLoader[*user](cache.NewMemoryStore(10), "users", time.Minute)
+		var loads int
+		load := func(ctx context.Context) (*user, error) {
+			loads++
+			return &user{Name: "John", Password: "hash"}, nil
+		}
+
+		first, err := loader.Get(ctx, "1", load)
+		require.NoError(t, err)
+		second, err := loader.Get(ctx, "1", load)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, loads)
+		assert.Equal(t, &user{Name: "John", Password: "hash"}, second, "fields without JSON are kept")
+		assert.NotSame(t, first, second, "every caller decodes its own copy")
+
+		require.NoError(t, loader.Invalidate(ctx, "1"))
+		_, err = loader.Get(ctx, "1", load)
+		require.NoError(t, err)
+		assert.Equal(t, 2, loads)
+	})
+
+	t.Run("Caches Missing Values But Not Errors", func(t *testing.T) {
+		loader := cache.NewLoader[*user](cache.NewMemoryStore(10), "users", time.Minute)
+		var loads int
+		missing := func(ctx context.Context) (*user, error) { loads++; return nil, nil }
+		failing := func(ctx context.Context) (*user, error) { loads++; return nil, assert.AnError }
+
+		for range 2 {
+			value, err := loader.Get(ctx, "missing", missing)
+			require.NoError(t, err)
+			assert.Nil(t, value)
+		}
+		for range 2 {
+			_, err := loader.Get(ctx, "failing", failing)
+			assert.ErrorIs(t, err, assert.AnError)
+		}
+		assert.Equal(t, 3, loads)
+	})
+
+	t.Run("Shares Concurrent Misses", func(t *testing.T) {
+		loader := cache.NewLoader[string](cache.NewMemoryStore(10), "names", time.Minute)
+		release := make(chan struct{})
+		var loads atomic.Int32
+		load := func(ctx context.Context) (string, error) {
+			loads.Add(1)
+			<-release
+			return "John", nil
+		}
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := loader.Get(ctx, "1", load)
+				assert.NoError(t, err)
+				assert.Equal(t, "John", value)
+			}()
+		}
+		assert.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond) // let the other callers join the load
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), loads.Load())
+	})
+
+	t.Run("Invalidate During A Load Discards Its Value", func(t *testing.T) {
+		loader := cache.NewLoader[string](cache.NewMemoryStore(10), "names", time.Minute)
+		started, release := make(chan struct{}), make(chan struct{})
+		stale := make(chan error, 1)
+		go func() {
+			_, err := loader.Get(ctx, "1", func(ctx context.Context) (string, error) {
+				close(started)
+				<-release
+				return "John", nil
+			})
+			stale <- err
+		}()
+
+		<-started // the load read John
+		require.NoError(t, loader.Invalidate(ctx, "1"))
+		close(release)
+		require.NoError(t, <-stale)
+
+		_, ok := loader.Peek(ctx, "1")
+		assert.False(t, ok, "the value read before the invalidation isn't cached")
+		value, err := loader.Get(ctx, "1", func(ctx context.Context) (string, error) { return "Jane", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", value)
+	})
+
+	t.Run("Caller Stops Waiting With Its Context", func(t *testing.T) {
+		loader := cache.NewLoader[string](cache.NewMemoryStore(10), "names", time.Minute)
+		release := make(chan struct{})
+		defer close(release)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := loader.Get(canceled, "1", func(ctx context.Context) (string, error) {
+			<-release
+			return "John", nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var loader *cache.Loader[string]
+		value, err := loader.Get(ctx, "1", func(ctx context.Context) (string, error) { return "John", nil })
+		assert.NoError(t, err)
+		assert.Equal(t, "John", value)
+		_, ok := loader.Peek(ctx, "1")
+		assert.False(t, ok)
+		assert.NoError(t, loader.Invalidate(ctx, "1"))
+	})
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loaderPrefix namespaces the loader groups in a shared store
+const loaderPrefix = "load:"
+
+// loaderEntry is a cached value; Expires is checked on read since a RedisStore group may
+// outlive the ttl of its fields
+type loaderEntry[T any] struct {
+	Value   T
+	Expires time.Time
+}
+
+// Loader reads values through a Store, for read-heavy lookups such as repository getters:
+// a value missing from the store is loaded once, cached for the ttl, and decoded afresh for
+// every caller, so callers never share it. Concurrent misses of a key on an instance wait for
+// a single load. Values are gob encoded, so every exported field is kept, whatever its JSON tag.
+//
+// The store only speeds reads up: its errors fall back to loading. Writers call Invalidate
+// after changing a value. Invalidate also bumps the generation of the key, kept in the store
+// next to the value, so a load that read the value before the change, on this instance or
+// another sharing the store, doesn't cache it after the change. A nil *Loader caches nothing.
+type Loader[T any] struct {
+	store   Store
+	name    string
+	ttl     time.Duration
+	flights singleflight.Group
+}
+
+// NewLoader creates a Loader keeping its values in store for ttl, under name (e.g. "users")
+// so loaders can share a store.
+func NewLoader[T any](store Store, name string, ttl time.Duration) *Loader[T] {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Loader[T]{store: store, name: name, ttl: ttl}
+}
+
+// Get returns the cached value of key, or the one load returns, which is then cached. Errors
+// of load are returned and not cached. A caller whose context ends stops waiting for a load
+// shared with others.
+func (l *Loader[T]) Get(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if l == nil {
+		return load(ctx)
+	}
+	if value, ok := l.Peek(ctx, key); ok {
+		return value, nil
+	}
+
+	flight := l.flights.DoChan(key, func() (any, error) {
+		// Shared by every waiter, so it does not end with the first one
+		loadCtx := context.WithoutCancel(ctx)
+		generation := l.generation(loadCtx, key)
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := encodeEntry(loaderEntry[T]{Value: value, Expires: time.Now().Add(l.ttl)})
+		if err != nil {
+			return nil, err
+		}
+		if l.store.Set(loadCtx, l.groupKey(key), "", data, l.ttl) == nil && l.generation(loadCtx, key) != generation {
+			// Invalidated during the load, which may have read the value before the change.
+			// Checked after the Set: an Invalidate bumping the generation later deletes it anyway.
+			_ = l.store.Delete(loadCtx, l.groupKey(key))
+		}
+		return data, nil
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case result := <-flight:
+		if result.Err != nil {
+			return zero, result.Err
+		}
+		entry, err := decodeEntry[T](result.Val.([]byte))
+		if err != nil {
+			return zero, err
+		}
+		return entry.Value, nil
+	}
+}
+
+// Peek returns the cached value of key without loading it; ok is false when it is missing,
+// expired, or the store failed.
+func (l *Loader[T]) Peek(ctx context.Context, key string) (value T, ok bool) {
+	if l == nil {
+		return value, false
+	}
+
+	data, ok, err := l.store.Get(ctx, l.groupKey(key), "")
+	if err != nil || !ok {
+		return value, false
+	}
+	entry, err := decodeEntry[T](data)
+	if err != nil || time.Now().After(entry.Expires) {
+		return value, false
+	}
+	return entry.Value, true
+}
+
+// Set caches value for key, e.g. one a writer already has.
+func (l *Loader[T]) Set(ctx context.Context, key string, value T) error {
+	if l == nil {
+		return nil
+	}
+
+	data, err := encodeEntry(loaderEntry[T]{Value: value, Expires: time.Now().Add(l.ttl)})
+	if err != nil {
+		return err
+	}
+	return l.store.Set(ctx, l.groupKey(key), "", data, l.ttl)
+}
+
+// Invalidate drops the cached values of keys, so the next Get loads them again, and bumps
+// their generation so the loads in flight don't cache what they read.
+func (l *Loader[T]) Invalidate(ctx context.Context, keys ...string) error {
+	if l == nil {
+		return nil
+	}
+
+	generation := []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	for _, key := range keys {
+		// Bumped before the delete, see Get
+		if err := l.store.Set(ctx, l.generationKey(key), "", generation, l.ttl); err != nil {
+			return err
+		}
+		if err := l.store.Delete(ctx, l.groupKey(key)); err != nil {
+			return err
+		}
+		// Later callers start a load of their own instead of joining one in flight
+		l.flights.Forget(key)
+	}
+	return nil
+}
+
+// generation returns the generation of key, empty when it was never invalidated within the
+// ttl or the store failed
+func (l *Loader[T]) generation(ctx context.Context, key string) string {
+	generation, _, _ := l.store.Get(ctx, l.generationKey(key), "")
+	return string(generation)
+}
+
+func (l *Loader[T]) groupKey(key string) string {
+	return loaderPrefix + l.name + ":" + key
+}
+
+func (l *Loader[T]) generationKey(key string) string {
+	return loaderPrefix + l.name + ":generation:" + key
+}
+
+func encodeEntry[T any](entry loaderEntry[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEntry[T any](data []byte) (loaderEntry[T], error) {
+	var entry loaderEntry[T]
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry)
+	return entry, err
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Defaults for the response_cache.* settings left unset
+const (
+	DefaultTTL        = 30 * time.Second
+	DefaultMaxEntries = 10_000
+)
+
+// keyPrefix namespaces the response groups in a shared store
+const keyPrefix = "resp:"
+
+// Response is a cached HTTP response.
+type Response struct {
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	Expires time.Time   `json:"expires"`
+}
+
+// Responses caches HTTP responses per auth subject, with one entry per key (the path, query,
+// and locale of a request, see middleware.ResponseCache). Invalidate drops every cached
+// response of a subject at once, e.g. after its profile changed, so services needn't know the
+// routes. A nil *Responses caches nothing.
+type Responses struct {
+	store Store
+	ttl   time.Duration
+}
+
+// NewResponses creates a Responses with ttl as the default lifetime of an entry.
+func NewResponses(store Store, ttl time.Duration) *Responses {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Responses{store: store, ttl: ttl}
+}
+
+// FromConfig creates the response cache of response_cache.*, in memory or in Redis with
+// client. Redis without a client is an error.
+func FromConfig(cfg config.ResponseCache, client *redis.Client) (*Responses, error) {
+	var ttl time.Duration
+	if cfg.TTL != "" {
+		parsed, err := time.ParseDuration(cfg.TTL)
+		if err != nil {
+			return nil, err
+		}
+		ttl = parsed
+	}
+
+	store, err := NewStore(cfg.Store, cfg.MaxEntries, client)
+	if err != nil {
+		return nil, fmt.Errorf("response_cache.store: %w", err)
+	}
+	return NewResponses(store, ttl), nil
+}
+
+// NewStore creates the store of a *.store setting: a MemoryStore of up to maxEntries groups
+// (DefaultMaxEntries when zero), or a RedisStore on client for "redis". Redis without a client
+// is an error.
+func NewStore(kind string, maxEntries int, client *redis.Client) (Store, error) {
+	switch kind {
+	case "redis":
+		if client == nil {
+			return nil, errors.New("redis requires redis.addr")
+		}
+		return NewRedisStore(client), nil
+	default:
+		if maxEntries <= 0 {
+			maxEntries = DefaultMaxEntries
+		}
+		return NewMemoryStore(maxEntries), nil
+	}
+}
+
+// TTL returns the default lifetime of an entry.
+func (r *Responses) TTL() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.ttl
+}
+
+// Get returns the cached response of subject for key, if any and not expired.
+func (r *Responses) Get(ctx context.Context, subject, key string) (*Response, bool, error) {
+	if r == nil {
+		return nil, false, nil
+	}
+
+	data, ok, err := r.store.Get(ctx, groupKey(subject), key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false, err
+	}
+	if time.Now().After(resp.Expires) {
+		return nil, false, nil
+	}
+	return &resp, true, nil
+}
+
+// Set caches resp for ttl, or the default TTL when ttl is zero.
+func (r *Responses) Set(ctx context.Context, subject, key string, resp Response, ttl time.Duration) error {
+	if r == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = r.ttl
+	}
+
+	resp.Expires = time.Now().Add(ttl)
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return r.store.Set(ctx, groupKey(subject), key, data, ttl)
+}
+
+// Invalidate drops the cached responses of each subject, whatever the path, query, or locale.
+// Services call it after changing what the subject is served:
+//
+//	cache.Default().Invalidate(ctx, accountNumber)
+func (r *Responses) Invalidate(ctx context.Context, subjects ...string) error {
+	if r == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, subject := range subjects {
+		errs = append(errs, r.store.Delete(ctx, groupKey(subject)))
+	}
+	return errors.Join(errs...)
+}
+
+func groupKey(subject string) string {
+	return keyPrefix + subject
+}
+
+var current atomic.Pointer[Responses]
+
+// SetDefault installs the response cache used by middleware.ResponseCache; nil disables it.
+func SetDefault(r *Responses) {
+	current.Store(r)
+}
+
+// Default returns the installed response cache, nil when none is installed.
+// The methods of a nil *Responses are no-ops, so the result can be used directly.
+func Default() *Responses {
+	return current.Load()
+}
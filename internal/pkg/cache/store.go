@@ -0,0 +1,120 @@
+// Package cache stores short-lived values in memory or Redis, and caches HTTP responses on
+// top of them (see Responses).
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps values in groups: a value is addressed by a group key and a field, and a whole
+// group can be dropped at once. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value of field in the group key; ok is false when it is missing or expired.
+	Get(ctx context.Context, key, field string) (value []byte, ok bool, err error)
+	// Set stores the value of field in the group key for ttl.
+	Set(ctx context.Context, key, field string, value []byte, ttl time.Duration) error
+	// Delete drops the group key with all its fields.
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore keeps up to maxGroups groups in memory. The least recently used group is
+// evicted first. Values do not survive a restart and are not shared between instances.
+type MemoryStore struct {
+	maxGroups int
+
+	mu     sync.Mutex
+	groups map[string]*list.Element
+	order  *list.List // front is the most recently used
+}
+
+type memoryGroup struct {
+	key    string
+	fields map[string]memoryValue
+}
+
+type memoryValue struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore creates a MemoryStore.
+func NewMemoryStore(maxGroups int) *MemoryStore {
+	return &MemoryStore{
+		maxGroups: maxGroups,
+		groups:    make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key, field string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.groups[key]
+	if !ok {
+		return nil, false, nil
+	}
+	group := elem.Value.(*memoryGroup)
+	value, ok := group.fields[field]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(value.expires) {
+		delete(group.fields, field)
+		if len(group.fields) == 0 {
+			s.remove(elem)
+		}
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(elem)
+	return value.value, true, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(_ context.Context, key, field string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := memoryValue{value: value, expires: time.Now().Add(ttl)}
+	if elem, ok := s.groups[key]; ok {
+		elem.Value.(*memoryGroup).fields[field] = entry
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	group := &memoryGroup{key: key, fields: map[string]memoryValue{field: entry}}
+	s.groups[key] = s.order.PushFront(group)
+	for s.order.Len() > s.maxGroups {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.groups[key]; ok {
+		s.remove(elem)
+	}
+	return nil
+}
+
+// Len returns the number of groups, including expired ones not yet evicted.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// remove MUST be called with s.mu held
+func (s *MemoryStore) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.groups, elem.Value.(*memoryGroup).key)
+}
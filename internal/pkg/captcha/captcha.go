@@ -0,0 +1,182 @@
+// Package captcha verifies CAPTCHA answers with the siteverify API of reCAPTCHA, hCaptcha, or
+// Cloudflare Turnstile. The three share the protocol: the client solves the widget and sends
+// its response token, which the server posts with its secret key to the provider.
+//
+// middleware.CaptchaMiddleware requires an answer on the routes of captcha.routes, and a
+// Verifier is the loginguard.Challenger of logins past login_throttle.challenge_after.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Providers
+const (
+	ProviderRecaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// Siteverify endpoints of the providers
+const (
+	RecaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// DefaultTimeout bounds a verification when captcha.timeout is unset
+const DefaultTimeout = 5 * time.Second
+
+// maxResponseLength is the longest response token sent to the provider; Turnstile documents
+// 2048 characters, the others stay well below
+const maxResponseLength = 4096
+
+// Verifier checks the answer to a CAPTCHA. It implements loginguard.Challenger.
+type Verifier interface {
+	// Verify reports whether response is a valid, unused answer. Errors mean the answer
+	// could not be checked (the provider is unreachable or rejects the secret).
+	Verify(ctx context.Context, response, remoteIP string) (bool, error)
+}
+
+// siteverifyResult is the JSON body of a siteverify response
+type siteverifyResult struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"` // reCAPTCHA v3 only
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// SiteVerifier verifies answers with a siteverify endpoint. It is safe for concurrent use.
+type SiteVerifier struct {
+	client   *httpclient.Client
+	url      string
+	secret   string
+	minScore float64
+}
+
+// NewRecaptcha creates a reCAPTCHA (v2 or v3) verifier. v3 answers scoring below minScore
+// fail; 0 accepts any score.
+func NewRecaptcha(client *httpclient.Client, secret string, minScore float64) *SiteVerifier {
+	return &SiteVerifier{client: client, url: RecaptchaVerifyURL, secret: secret, minScore: minScore}
+}
+
+// NewHCaptcha creates an hCaptcha verifier.
+func NewHCaptcha(client *httpclient.Client, secret string) *SiteVerifier {
+	return &SiteVerifier{client: client, url: HCaptchaVerifyURL, secret: secret}
+}
+
+// NewTurnstile creates a Cloudflare Turnstile verifier.
+func NewTurnstile(client *httpclient.Client, secret string) *SiteVerifier {
+	return &SiteVerifier{client: client, url: TurnstileVerifyURL, secret: secret}
+}
+
+// WithURL returns a copy of the verifier posting to verifyURL, e.g. a proxy or a test server.
+func (v *SiteVerifier) WithURL(verifyURL string) *SiteVerifier {
+	clone := *v
+	clone.url = verifyURL
+	return &clone
+}
+
+// FromConfig creates a verifier from the captcha configuration. It returns nil when
+// captcha.provider is empty.
+func FromConfig(cfg config.Captcha) (*SiteVerifier, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	clientConfig := httpclient.DefaultConfig()
+	clientConfig.Timeout = DefaultTimeout
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid captcha.timeout: %w", err)
+		}
+		clientConfig.Timeout = timeout
+	}
+	client := httpclient.New(clientConfig)
+
+	var verifier *SiteVerifier
+	switch cfg.Provider {
+	case ProviderRecaptcha:
+		verifier = NewRecaptcha(client, cfg.Secret, cfg.MinScore)
+	case ProviderHCaptcha:
+		verifier = NewHCaptcha(client, cfg.Secret)
+	case ProviderTurnstile:
+		verifier = NewTurnstile(client, cfg.Secret)
+	default:
+		return nil, fmt.Errorf("unknown captcha.provider %q", cfg.Provider)
+	}
+
+	if cfg.VerifyURL != "" {
+		verifier = verifier.WithURL(cfg.VerifyURL)
+	}
+	return verifier, nil
+}
+
+// Verify implements Verifier. Empty, oversized, invalid, and reused answers fail without an
+// error; a rejected secret is an error, so misconfiguration shows up in the logs.
+func (v *SiteVerifier) Verify(ctx context.Context, response, remoteIP string) (bool, error) {
+	if response == "" || len(response) > maxResponseLength {
+		return false, nil
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {response}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("captcha: failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("captcha: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, fmt.Errorf("captcha: %s returned status %d", v.url, resp.StatusCode)
+	}
+
+	var result siteverifyResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return false, fmt.Errorf("captcha: failed to decode response: %w", err)
+	}
+
+	if !result.Success {
+		for _, code := range result.ErrorCodes {
+			if strings.Contains(code, "secret") {
+				return false, fmt.Errorf("captcha: provider rejected the secret: %s", code)
+			}
+		}
+		return false, nil
+	}
+	if result.Score != nil && *result.Score < v.minScore {
+		return false, nil
+	}
+	return true, nil
+}
+
+var current atomic.Pointer[SiteVerifier]
+
+// SetDefault installs the verifier used by middleware.CaptchaMiddleware; nil disables it.
+func SetDefault(v *SiteVerifier) {
+	current.Store(v)
+}
+
+// Default returns the installed verifier, nil when none is installed.
+func Default() *SiteVerifier {
+	return current.Load()
+}
@@ -0,0 +1,97 @@
+package captcha_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/captcha"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSiteverify serves the siteverify API, answering each response token with its body
+func newSiteverify(t *testing.T, answers map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "test-secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+
+		answer, ok := answers[r.PostForm.Get("response")]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(answer))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSiteVerifier_Verify(t *testing.T) {
+	server := newSiteverify(t, map[string]string{
+		"valid":     `{"success": true, "hostname": "example.com"}`,
+		"invalid":   `{"success": false, "error-codes": ["invalid-input-response"]}`,
+		"reused":    `{"success": false, "error-codes": ["timeout-or-duplicate"]}`,
+		"bad-setup": `{"success": false, "error-codes": ["invalid-input-secret"]}`,
+		"human":     `{"success": true, "score": 0.9}`,
+		"bot":       `{"success": true, "score": 0.1}`,
+	})
+	client := httpclient.New(nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		verifier *captcha.SiteVerifier
+		response string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "Valid", verifier: captcha.NewHCaptcha(client, "test-secret"), response: "valid", want: true},
+		{name: "Invalid", verifier: captcha.NewTurnstile(client, "test-secret"), response: "invalid"},
+		{name: "Reused", verifier: captcha.NewTurnstile(client, "test-secret"), response: "reused"},
+		{name: "Empty Is Not Sent", verifier: captcha.NewHCaptcha(client, "test-secret"), response: ""},
+		{name: "Rejected Secret", verifier: captcha.NewHCaptcha(client, "test-secret"), response: "bad-setup", wantErr: true},
+		{name: "Provider Error", verifier: captcha.NewHCaptcha(client, "test-secret"), response: "unknown", wantErr: true},
+		{name: "Score Above Minimum", verifier: captcha.NewRecaptcha(client, "test-secret", 0.5), response: "human", want: true},
+		{name: "Score Below Minimum", verifier: captcha.NewRecaptcha(client, "test-secret", 0.5), response: "bot"},
+		{name: "Any Score Without Minimum", verifier: captcha.NewRecaptcha(client, "test-secret", 0), response: "bot", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, err := tt.verifier.WithURL(server.URL).Verify(ctx, tt.response, "203.0.113.7")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, passed)
+		})
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	verifier, err := captcha.FromConfig(config.Captcha{})
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	server := newSiteverify(t, map[string]string{"valid": `{"success": true}`})
+	verifier, err = captcha.FromConfig(config.Captcha{Provider: "turnstile", Secret: "test-secret", VerifyURL: server.URL})
+	require.NoError(t, err)
+	passed, err := verifier.Verify(context.Background(), "valid", "203.0.113.7")
+	require.NoError(t, err)
+	assert.True(t, passed)
+
+	_, err = captcha.FromConfig(config.Captcha{Provider: "recaptcha", Secret: "test-secret", Timeout: "soon"})
+	assert.ErrorContains(t, err, "captcha.timeout")
+
+	_, err = captcha.FromConfig(config.Captcha{Provider: "friendly", Secret: "test-secret"})
+	assert.Error(t, err)
+}
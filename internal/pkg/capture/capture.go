@@ -0,0 +1,188 @@
+// Package capture keeps sanitized request/response pairs for support investigations
+// (middleware.DebugCaptureMiddleware). A request is captured when it carries a token of
+// Token in its X-Debug-Capture header, e.g. handed to a customer reproducing an issue, or
+// when it is made by an account of debug_capture.accounts.
+//
+// Captures are masked like the wide events (see logger.Masker), with the credential headers
+// always redacted, so replaying one locally (the cli replay-capture command) needs local
+// credentials.
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gbytes "github.com/labstack/gommon/bytes"
+)
+
+const (
+	// Header carries the token enabling the capture of a request
+	Header = "X-Debug-Capture"
+
+	// DefaultMaxBodySize is the body size kept when debug_capture.max_body_size is unset
+	DefaultMaxBodySize = 64 * 1024
+
+	// DefaultRetention is how long a capture is kept when debug_capture.retention is unset
+	DefaultRetention = 72 * time.Hour
+
+	// DefaultTokenTTL is how long a token is valid when debug_capture.token_ttl is unset
+	DefaultTokenTTL = time.Hour
+)
+
+// tokenPrefix is the purpose of the signed tokens enabling a capture
+const tokenPrefix = "debug_capture:"
+
+// ErrInvalidToken is returned by Label for a token that is malformed, forged, or expired.
+var ErrInvalidToken = errors.New("capture: invalid token")
+
+// Store keeps the captures, e.g. pgsql.DebugCaptureRepository.
+type Store interface {
+	Create(ctx context.Context, capture *models.DebugCapture) error
+	// DeleteExpired deletes the captures expired at now, returning how many it deleted
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Capturer decides which requests are captured and stores them. It is safe for concurrent use.
+type Capturer struct {
+	store       Store
+	secret      string
+	maxBodySize int
+	retention   time.Duration
+	tokenTTL    time.Duration
+	now         func() time.Time
+
+	accounts atomic.Pointer[map[string]bool]
+}
+
+// FromConfig creates the capturer of debug_capture, signing its tokens with secret
+// (authorization.signing_secret) and storing the captures in store. It returns nil when
+// debug_capture is disabled.
+func FromConfig(cfg config.DebugCapture, secret string, store Store) (*Capturer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if secret == "" {
+		return nil, errors.New("debug_capture requires authorization.signing_secret")
+	}
+	if store == nil {
+		return nil, errors.New("capture: no store")
+	}
+
+	c := &Capturer{
+		store:       store,
+		secret:      secret,
+		maxBodySize: DefaultMaxBodySize,
+		retention:   DefaultRetention,
+		tokenTTL:    DefaultTokenTTL,
+		now:         time.Now,
+	}
+	if cfg.MaxBodySize != "" {
+		size, err := gbytes.Parse(cfg.MaxBodySize)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid debug_capture.max_body_size %q", cfg.MaxBodySize)
+		}
+		c.maxBodySize = int(size)
+	}
+	if cfg.Retention != "" {
+		parsed, err := time.ParseDuration(cfg.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid debug_capture.retention: %w", err)
+		}
+		c.retention = parsed
+	}
+	if cfg.TokenTTL != "" {
+		parsed, err := time.ParseDuration(cfg.TokenTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid debug_capture.token_ttl: %w", err)
+		}
+		c.tokenTTL = parsed
+	}
+	c.SetAccounts(cfg.Accounts)
+	return c, nil
+}
+
+// SetAccounts replaces the accounts whose requests are all captured, e.g. on config reload.
+func (c *Capturer) SetAccounts(accountNumbers []string) {
+	accounts := make(map[string]bool, len(accountNumbers))
+	for _, accountNumber := range accountNumbers {
+		accounts[accountNumber] = true
+	}
+	c.accounts.Store(&accounts)
+}
+
+// Flagged reports whether the requests of the account are all captured.
+func (c *Capturer) Flagged(accountNumber string) bool {
+	return accountNumber != "" && (*c.accounts.Load())[accountNumber]
+}
+
+// Watching reports whether any account is flagged, so a request without a token may still
+// be captured once its account is known.
+func (c *Capturer) Watching() bool {
+	return len(*c.accounts.Load()) > 0
+}
+
+// MaxBodySize is the number of bytes kept of a request or response body.
+func (c *Capturer) MaxBodySize() int {
+	return c.maxBodySize
+}
+
+// Token returns a token capturing the requests carrying it in the X-Debug-Capture header
+// until it expires, their captures labelled with label (e.g. the support ticket).
+func (c *Capturer) Token(label string) (string, time.Time, error) {
+	expiresAt := c.now().Add(c.tokenTTL)
+	token, err := generator.SignedPayload(tokenPrefix+label, c.tokenTTL, c.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Label returns the label of a token of Token, or ErrInvalidToken.
+func (c *Capturer) Label(token string) (string, error) {
+	data, err := validator.SignedPayload(token, c.secret)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	label, ok := strings.CutPrefix(data, tokenPrefix)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	return label, nil
+}
+
+// Save stores the capture, kept for debug_capture.retention, and deletes the expired ones:
+// captures are rare, so they are purged as new ones come in.
+func (c *Capturer) Save(ctx context.Context, capture *models.DebugCapture) error {
+	now := c.now()
+	capture.CapturedAt = now
+	capture.ExpiresAt = now.Add(c.retention)
+	if err := c.store.Create(ctx, capture); err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+
+	if _, err := c.store.DeleteExpired(ctx, now); err != nil {
+		logger.L().Warn(ctx, "expired debug captures purge failed", logger.Error(err))
+	}
+	return nil
+}
+
+var current atomic.Pointer[Capturer]
+
+// SetDefault installs the capturer used by middleware.DebugCaptureMiddleware. nil disables it.
+func SetDefault(c *Capturer) {
+	current.Store(c)
+}
+
+// Default returns the capturer set with SetDefault, nil when debug_capture is disabled.
+func Default() *Capturer {
+	return current.Load()
+}
@@ -0,0 +1,109 @@
+package capture
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "debug-capture-signing-secret-0123456789"
+
+func TestFromConfig(t *testing.T) {
+	c, err := FromConfig(config.DebugCapture{}, secret, nil)
+	require.NoError(t, err)
+	assert.Nil(t, c, "disabled")
+
+	_, err = FromConfig(config.DebugCapture{Enabled: true}, "", memory.NewDebugCaptureRepository())
+	assert.ErrorContains(t, err, "authorization.signing_secret")
+	_, err = FromConfig(config.DebugCapture{Enabled: true, MaxBodySize: "lots"}, secret, memory.NewDebugCaptureRepository())
+	assert.ErrorContains(t, err, "debug_capture.max_body_size")
+
+	c, err = FromConfig(config.DebugCapture{Enabled: true, MaxBodySize: "1KB", Accounts: []string{"1234567890"}}, secret, memory.NewDebugCaptureRepository())
+	require.NoError(t, err)
+	assert.Equal(t, 1000, c.MaxBodySize())
+	assert.True(t, c.Watching())
+	assert.True(t, c.Flagged("1234567890"))
+	assert.False(t, c.Flagged(""))
+
+	c.SetAccounts(nil)
+	assert.False(t, c.Watching(), "reloaded")
+	assert.False(t, c.Flagged("1234567890"))
+}
+
+func TestToken(t *testing.T) {
+	c, err := FromConfig(config.DebugCapture{Enabled: true, TokenTTL: "10m"}, secret, memory.NewDebugCaptureRepository())
+	require.NoError(t, err)
+
+	token, expiresAt, err := c.Token("SUP-1234")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), expiresAt, time.Second)
+
+	label, err := c.Label(token)
+	require.NoError(t, err)
+	assert.Equal(t, "SUP-1234", label)
+
+	_, err = c.Label(token + "x")
+	assert.ErrorIs(t, err, ErrInvalidToken, "forged")
+
+	other, err := FromConfig(config.DebugCapture{Enabled: true}, strings.Repeat("x", 32), memory.NewDebugCaptureRepository())
+	require.NoError(t, err)
+	_, err = other.Label(token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "signed with another secret")
+}
+
+func TestSave(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewDebugCaptureRepository()
+	c, err := FromConfig(config.DebugCapture{Enabled: true, Retention: "1h"}, secret, repo)
+	require.NoError(t, err)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	first := &models.DebugCapture{Path: "/api/v1/users", Request: "{}", Response: "{}"}
+	require.NoError(t, c.Save(ctx, first))
+	assert.Equal(t, now.Add(time.Hour), first.ExpiresAt)
+
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, c.Save(ctx, &models.DebugCapture{Path: "/api/v1/users/profile", Request: "{}", Response: "{}"}))
+
+	captures, err := repo.List(ctx, 50)
+	require.NoError(t, err)
+	if assert.Len(t, captures, 1, "the expired capture purged") {
+		assert.Equal(t, "/api/v1/users/profile", captures[0].Path)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	header := SanitizeHeader(http.Header{
+		"Content-Type":    {"application/json"},
+		"Authorization":   {"Bearer abc.def.ghi"},
+		"X-Admin-Key":     {"admin-key"},
+		"X-Debug-Capture": {"token"},
+		"Accept":          {"text/html", "application/json"},
+	})
+	assert.Equal(t, "application/json", header["Content-Type"])
+	assert.Equal(t, "text/html, application/json", header["Accept"])
+	assert.Equal(t, logger.MaskString, header["Authorization"])
+	assert.Equal(t, logger.MaskString, header["X-Admin-Key"])
+	assert.Equal(t, logger.MaskString, header["X-Debug-Capture"])
+
+	body := SanitizeBody("application/json", []byte(`{"email":"jane@example.com","password":"hunter22"}`))
+	assert.Contains(t, body, `"email":"jane@example.com"`)
+	assert.NotContains(t, body, "hunter22")
+
+	form := SanitizeBody("application/x-www-form-urlencoded", []byte("name=Jane&password=hunter22"))
+	assert.Contains(t, form, "name=Jane")
+	assert.NotContains(t, form, "hunter22")
+
+	assert.Equal(t, `{"name":"Ja`, SanitizeBody("application/json", []byte(`{"name":"Ja`)), "cut short, kept as text")
+}
@@ -0,0 +1,67 @@
+package capture
+
+import (
+	"encoding/json"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redactedHeaders carry credentials; they are always masked, whatever logger.masking says
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Proxy-Authorization",
+	"X-API-Key",
+	"X-Admin-Key",
+	Header,
+}
+
+// SanitizeHeader flattens header, joining repeated values with ", ", and masks it with the
+// log masker, the credential headers masked whole.
+func SanitizeHeader(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		flat[name] = strings.Join(values, ", ")
+	}
+	masked := logger.DefaultMasker().MaskHeaders(flat)
+
+	for _, name := range redactedHeaders {
+		if _, ok := masked[http.CanonicalHeaderKey(name)]; ok {
+			masked[http.CanonicalHeaderKey(name)] = logger.MaskString
+		}
+	}
+	return masked
+}
+
+// SanitizeBody masks a textual body of mediaType with the log masker: the sensitive fields of
+// a JSON or form body, and the sensitive patterns of any other text. A JSON body cut short
+// can't be parsed and is masked as text.
+func SanitizeBody(mediaType string, data []byte) string {
+	masker := logger.DefaultMasker()
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		if values, err := url.ParseQuery(string(data)); err == nil {
+			for name := range values {
+				if masker.IsSensitive(name) {
+					values[name] = []string{logger.MaskString}
+				}
+			}
+			return values.Encode()
+		}
+	}
+
+	var body any
+	if err := json.Unmarshal(data, &body); err == nil {
+		if masked, err := json.Marshal(masker.Mask(body)); err == nil {
+			return string(masked)
+		}
+	}
+
+	if masked, ok := masker.Mask(string(data)).(string); ok {
+		return masked
+	}
+	return logger.MaskString
+}
@@ -0,0 +1,134 @@
+// Package clientinfo describes the client of a request: its location, from its IP address
+// in a MaxMind GeoIP2/GeoLite2 database, and its device, OS, and browser, from its
+// User-Agent. middleware.ClientInfoMiddleware adds it to the wide event under "client" and
+// to the request context, where login audit records pick it up.
+package clientinfo
+
+import (
+	"context"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/geoip"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/useragent"
+	"net/netip"
+	"sync/atomic"
+)
+
+// Info is the client of a request. Fields that are disabled or unknown are empty.
+type Info struct {
+	IP             string `json:"-"`                 // already on the wide event as "ip"
+	Country        string `json:"country,omitempty"` // ISO 3166-1 alpha-2, e.g. "ID"
+	City           string `json:"city,omitempty"`
+	Device         string `json:"device,omitempty"` // desktop, mobile, tablet, or bot
+	OS             string `json:"os,omitempty"`
+	OSVersion      string `json:"os_version,omitempty"`
+	Browser        string `json:"browser,omitempty"`
+	BrowserVersion string `json:"browser_version,omitempty"`
+}
+
+// Namespace implements logger.Extension.
+func (Info) Namespace() string { return "client" }
+
+// Fields returns the info as log fields, for audit records.
+func (i Info) Fields() []logger.Field {
+	fields := []logger.Field{logger.String("remote_ip", i.IP)}
+	for _, field := range []struct{ key, value string }{
+		{"client_country", i.Country},
+		{"client_city", i.City},
+		{"client_device", i.Device},
+		{"client_os", i.OS},
+		{"client_browser", i.Browser},
+	} {
+		if field.value != "" {
+			fields = append(fields, logger.String(field.key, field.value))
+		}
+	}
+	return fields
+}
+
+// Locator finds the location of an IP address, see geoip.Reader.
+type Locator interface {
+	Lookup(ip netip.Addr) (geoip.Location, bool, error)
+}
+
+// Resolver builds the Info of requests. It is safe for concurrent use.
+type Resolver struct {
+	locator   Locator
+	userAgent bool
+}
+
+// New creates a Resolver that locates clients with locator (nil skips locations) and parses
+// their User-Agent when userAgent is set.
+func New(locator Locator, userAgent bool) *Resolver {
+	return &Resolver{locator: locator, userAgent: userAgent}
+}
+
+// FromConfig creates a Resolver from the client_info configuration, opening the GeoIP
+// database. It returns nil when client_info enables nothing.
+func FromConfig(cfg config.ClientInfo) (*Resolver, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	var locator Locator
+	if cfg.GeoIPDatabase != "" {
+		reader, err := geoip.Open(cfg.GeoIPDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client_info.geoip_database: %w", err)
+		}
+		locator = reader
+	}
+	return New(locator, cfg.UserAgent), nil
+}
+
+// Resolve returns the Info of a client. Addresses the database doesn't know (e.g. private
+// ones) and lookup errors leave the location empty.
+func (r *Resolver) Resolve(ip, userAgent string) Info {
+	info := Info{IP: ip}
+
+	if r.locator != nil {
+		if addr, err := netip.ParseAddr(ip); err == nil {
+			if location, found, err := r.locator.Lookup(addr); err == nil && found {
+				info.Country = location.CountryCode
+				info.City = location.City
+			}
+		}
+	}
+
+	if r.userAgent {
+		agent := useragent.Parse(userAgent)
+		info.Device = agent.Device
+		info.OS = agent.OS
+		info.OSVersion = agent.OSVersion
+		info.Browser = agent.Browser
+		info.BrowserVersion = agent.BrowserVersion
+	}
+
+	return info
+}
+
+type contextKey struct{}
+
+// WithInfo returns a copy of ctx carrying info.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the Info of the request, and false when none was resolved.
+func FromContext(ctx context.Context) (Info, bool) {
+	info, ok := ctx.Value(contextKey{}).(Info)
+	return info, ok
+}
+
+var current atomic.Pointer[Resolver]
+
+// SetDefault installs the resolver used by middleware.ClientInfoMiddleware; nil disables it.
+func SetDefault(r *Resolver) {
+	current.Store(r)
+}
+
+// Default returns the installed resolver, nil when none is installed.
+func Default() *Resolver {
+	return current.Load()
+}
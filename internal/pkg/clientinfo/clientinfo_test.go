@@ -0,0 +1,97 @@
+package clientinfo_test
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/clientinfo"
+	"go-echo-boilerplate/internal/pkg/geoip"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const iPhoneSafari = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+
+// fakeLocator locates 81.2.69.0/24 in London and fails for 192.0.2.0/24
+type fakeLocator struct{}
+
+func (fakeLocator) Lookup(ip netip.Addr) (geoip.Location, bool, error) {
+	switch {
+	case netip.MustParsePrefix("81.2.69.0/24").Contains(ip):
+		return geoip.Location{CountryCode: "GB", Country: "United Kingdom", City: "London"}, true, nil
+	case netip.MustParsePrefix("192.0.2.0/24").Contains(ip):
+		return geoip.Location{}, false, errors.New("corrupt database")
+	default:
+		return geoip.Location{}, false, nil
+	}
+}
+
+func TestResolver(t *testing.T) {
+	t.Run("Location And Device", func(t *testing.T) {
+		info := clientinfo.New(fakeLocator{}, true).Resolve("81.2.69.142", iPhoneSafari)
+
+		assert.Equal(t, clientinfo.Info{
+			IP:             "81.2.69.142",
+			Country:        "GB",
+			City:           "London",
+			Device:         "mobile",
+			OS:             "iOS",
+			OSVersion:      "17.4",
+			Browser:        "Safari",
+			BrowserVersion: "17.4",
+		}, info)
+	})
+
+	t.Run("Unknown Or Failing Lookups Leave The Location Empty", func(t *testing.T) {
+		resolver := clientinfo.New(fakeLocator{}, false)
+
+		for _, ip := range []string{"10.0.0.1", "192.0.2.1", "not-an-ip"} {
+			assert.Equal(t, clientinfo.Info{IP: ip}, resolver.Resolve(ip, iPhoneSafari), ip)
+		}
+	})
+
+	t.Run("User Agent Only", func(t *testing.T) {
+		info := clientinfo.New(nil, true).Resolve("81.2.69.142", iPhoneSafari)
+
+		assert.Empty(t, info.Country)
+		assert.Equal(t, "mobile", info.Device)
+	})
+}
+
+func TestInfoFields(t *testing.T) {
+	fields := clientinfo.Info{IP: "81.2.69.142", Country: "GB", Device: "desktop"}.Fields()
+
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = field.Key
+	}
+	assert.Equal(t, []string{"remote_ip", "client_country", "client_device"}, keys, "empty values are left out")
+}
+
+func TestContext(t *testing.T) {
+	_, ok := clientinfo.FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := clientinfo.WithInfo(context.Background(), clientinfo.Info{Country: "ID"})
+	info, ok := clientinfo.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "ID", info.Country)
+}
+
+func TestFromConfig(t *testing.T) {
+	resolver, err := clientinfo.FromConfig(config.ClientInfo{})
+	require.NoError(t, err)
+	assert.Nil(t, resolver, "nothing enabled")
+
+	resolver, err = clientinfo.FromConfig(config.ClientInfo{UserAgent: true})
+	require.NoError(t, err)
+	assert.NotNil(t, resolver)
+
+	_, err = clientinfo.FromConfig(config.ClientInfo{GeoIPDatabase: filepath.Join(t.TempDir(), "missing.mmdb")})
+	assert.ErrorContains(t, err, "client_info.geoip_database")
+}
@@ -0,0 +1,65 @@
+// Package contract checks HTTP responses against the generated OpenAPI spec (docs/swagger.json),
+// so the handlers and their annotations cannot drift apart. The spec is regenerated with make docs.
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/schema"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// Validator matches requests to the operations of the spec and validates their responses
+type Validator struct {
+	router routers.Router
+}
+
+// New loads the spec with schema.OpenAPI
+func New() (*Validator, error) {
+	doc, err := schema.OpenAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build router: %w", err)
+	}
+	return &Validator{router: router}, nil
+}
+
+// Validate checks that req matches a documented operation, that status is one of its
+// documented responses, and that header and body match that response
+func (v *Validator) Validate(req *http.Request, status int, header http.Header, body []byte) error {
+	route, pathParams, err := v.router.FindRoute(req)
+	if err != nil {
+		return fmt.Errorf("%s %s is not documented: %w", req.Method, req.URL.Path, err)
+	}
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status:  status,
+		Header:  header,
+		Options: &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+	}
+	input.SetBodyBytes(body)
+
+	if err := openapi3filter.ValidateResponse(req.Context(), input); err != nil {
+		return fmt.Errorf("%s %s responded %d against the spec: %w", req.Method, req.URL.Path, status, err)
+	}
+	return nil
+}
+
+// ValidateRecorder is Validate for a response recorded by httptest
+func (v *Validator) ValidateRecorder(req *http.Request, rec *httptest.ResponseRecorder) error {
+	return v.Validate(req, rec.Code, rec.Header(), bytes.Clone(rec.Body.Bytes()))
+}
@@ -0,0 +1,39 @@
+package contract_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/contract"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator(t *testing.T) {
+	validator, err := contract.New()
+	require.NoError(t, err)
+
+	header := http.Header{"Content-Type": {"application/json; charset=UTF-8"}}
+	validate := func(method, path string, status int, body string) error {
+		return validator.Validate(httptest.NewRequest(method, path, nil), status, header, []byte(body))
+	}
+
+	versions := `{"code":200,"status":"OK","message":"ok","data":[{"name":"v1","status":"supported"}],"metadata":{"requestId":"id","timestamp":"now"}}`
+	assert.NoError(t, validate(http.MethodGet, "/api/versions", http.StatusOK, versions))
+
+	t.Run("Undocumented Route", func(t *testing.T) {
+		assert.ErrorContains(t, validate(http.MethodGet, "/api/v3/users", http.StatusOK, versions), "not documented")
+		assert.ErrorContains(t, validate(http.MethodDelete, "/api/versions", http.StatusOK, versions), "not documented")
+	})
+
+	t.Run("Undocumented Status", func(t *testing.T) {
+		assert.Error(t, validate(http.MethodGet, "/api/versions", http.StatusTeapot, versions))
+	})
+
+	t.Run("Wrong Shape", func(t *testing.T) {
+		assert.Error(t, validate(http.MethodGet, "/api/versions", http.StatusOK, `{"code":"200","status":"OK","message":"ok","metadata":{}}`))
+		assert.Error(t, validate(http.MethodGet, "/api/versions", http.StatusOK, `{"code":200,"status":"OK","message":"ok","data":[{"name":1}],"metadata":{}}`))
+	})
+}
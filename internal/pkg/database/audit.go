@@ -0,0 +1,70 @@
+package database
+
+import (
+	"go-echo-boilerplate/internal/pkg/actor"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// RegisterAuditCallbacks makes gorm fill the CreatedBy and UpdatedBy fields of models embedding
+// models.Audit with the actor of the statement context (see actor.FromContext). Creates set
+// both unless the caller did, updates through a model (Save, Updates, Update) set UpdatedBy.
+// Writes without an actor leave them alone. Raw statements (Raw, Exec) are not tracked, they
+// set updated_by themselves, e.g. the UPDATE queries of pgsql.UserRepository pass the actor
+// and COALESCE it with the column.
+func RegisterAuditCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("audit:create", auditCreate); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("audit:update", auditUpdate)
+}
+
+func auditCreate(db *gorm.DB) {
+	id, ok := auditActor(db)
+	if !ok {
+		return
+	}
+
+	for _, name := range []string{"CreatedBy", "UpdatedBy"} {
+		field := db.Statement.Schema.LookUpField(name)
+		if field == nil {
+			continue
+		}
+
+		ctx, rv := db.Statement.Context, db.Statement.ReflectValue
+		setUnlessSet := func(value reflect.Value) {
+			if _, zero := field.ValueOf(ctx, value); zero {
+				if err := field.Set(ctx, value, &id); err != nil {
+					_ = db.AddError(err)
+				}
+			}
+		}
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := range rv.Len() {
+				setUnlessSet(reflect.Indirect(rv.Index(i)))
+			}
+		case reflect.Struct:
+			setUnlessSet(rv)
+		}
+	}
+}
+
+func auditUpdate(db *gorm.DB) {
+	id, ok := auditActor(db)
+	if !ok {
+		return
+	}
+	if field := db.Statement.Schema.LookUpField("UpdatedBy"); field != nil {
+		db.Statement.SetColumn(field.DBName, &id, true)
+	}
+}
+
+// auditActor returns the actor of a statement on a model, false when there is none
+func auditActor(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return "", false
+	}
+	return actor.FromContext(db.Statement.Context)
+}
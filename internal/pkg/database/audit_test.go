@@ -0,0 +1,90 @@
+package database_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/actor"
+	"go-echo-boilerplate/internal/pkg/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type note struct {
+	ID   int
+	Body string
+	models.Audit
+}
+
+func TestAuditCallbacks(t *testing.T) {
+	setup := func(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, database.RegisterAuditCallbacks(gormDB))
+		return gormDB, mock
+	}
+	insert := regexp.QuoteMeta(`INSERT INTO "notes" ("body","created_by","updated_by")`)
+	ctx := actor.WithActor(context.Background(), "acc-1")
+
+	t.Run("Create Records The Actor", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(insert).WithArgs("hello", "acc-1", "acc-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		n := note{Body: "hello"}
+		require.NoError(t, db.WithContext(ctx).Create(&n).Error)
+		assert.Equal(t, "acc-1", *n.CreatedBy)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create Keeps A Given Creator", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(insert).WithArgs("a", "importer", "acc-1", "b", "acc-1", "acc-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		mock.ExpectCommit()
+
+		importer := "importer"
+		notes := []note{{Body: "a", Audit: models.Audit{CreatedBy: &importer}}, {Body: "b"}}
+		require.NoError(t, db.WithContext(ctx).Create(&notes).Error)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create Without Actor", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(insert).WithArgs("hello", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		require.NoError(t, db.WithContext(context.Background()).Create(&note{Body: "hello"}).Error)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update Records The Actor", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "notes" SET "body"=$1,"updated_by"=$2 WHERE id = $3`)).
+			WithArgs("edited", "acc-1", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := db.WithContext(ctx).Model(&note{}).Where("id = ?", 1).Updates(map[string]any{"body": "edited"}).Error
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -3,11 +3,13 @@ package database
 import (
 	"go-echo-boilerplate/internal/config"
 
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 type Database struct {
 	PostgreDatabase *gorm.DB
+	Redis           *redis.Client // nil when redis.addr is not configured
 }
 
 // Connect to database, in case in future we will use another database, we can just add the Connect function
@@ -17,11 +19,21 @@ func Connect(config *config.Configuration) (*Database, error) {
 		return nil, err
 	}
 
+	redisClient, err := ConnectToRedis(config)
+	if err != nil {
+		_ = DisconnectFromPostgreSQL(postgreDatabase)
+		return nil, err
+	}
+
 	return &Database{
 		PostgreDatabase: postgreDatabase,
+		Redis:           redisClient,
 	}, nil
 }
 
 func Disconnect(db *Database) error {
+	if db.Redis != nil {
+		_ = db.Redis.Close()
+	}
 	return DisconnectFromPostgreSQL(db.PostgreDatabase)
 }
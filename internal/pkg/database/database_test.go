@@ -1,11 +1,15 @@
 package database_test
 
 import (
+	"go-echo-boilerplate/internal/config"
 	"go-echo-boilerplate/internal/pkg/database"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -41,6 +45,56 @@ func TestConnect(t *testing.T) {
 	})
 }
 
+func TestPostgreSQLDSN(t *testing.T) {
+	configuration := &config.Configuration{}
+	configuration.PostgreSQL.Host = "localhost"
+	configuration.PostgreSQL.Port = 5432
+
+	dsn := database.PostgreSQLDSN(configuration)
+	assert.Contains(t, dsn, "host=localhost")
+	assert.NotContains(t, dsn, "timeout", "PgBouncer rejects the session settings as startup parameters")
+}
+
+func TestSessionSettings(t *testing.T) {
+	configuration := &config.Configuration{}
+	assert.Equal(t, []string{
+		"SET statement_timeout = 30000",
+		"SET idle_in_transaction_session_timeout = 60000",
+	}, database.SessionSettings(configuration), "defaults to 30s and 1m")
+
+	configuration.PostgreSQL.StatementTimeout = "5s"
+	configuration.PostgreSQL.IdleInTransactionSessionTimeout = "0"
+	assert.Equal(t, []string{"SET statement_timeout = 5000"}, database.SessionSettings(configuration), "zero leaves the server default")
+}
+
+func TestPostgreSQLPoolConfig(t *testing.T) {
+	configuration := &config.Configuration{}
+	configuration.PostgreSQL.Host = "localhost"
+	configuration.PostgreSQL.Port = 5432
+	configuration.PostgreSQL.SSLMode = "disable"
+	configuration.PostgreSQL.MaxOpenConns = 20
+	configuration.PostgreSQL.ConnMaxLifetime = "1h"
+
+	poolConfig, err := database.PostgreSQLPoolConfig(configuration)
+	require.NoError(t, err)
+	assert.Equal(t, pgx.QueryExecModeCacheStatement, poolConfig.ConnConfig.DefaultQueryExecMode)
+	assert.Equal(t, 512, poolConfig.ConnConfig.StatementCacheCapacity, "defaults to 512")
+	assert.Equal(t, int32(20), poolConfig.MaxConns)
+	assert.Equal(t, time.Hour, poolConfig.MaxConnLifetime)
+	assert.NotNil(t, poolConfig.AfterConnect, "applies the session settings")
+	assert.NotContains(t, poolConfig.ConnConfig.RuntimeParams, "statement_timeout")
+
+	configuration.PostgreSQL.StatementCacheCapacity = 64
+	configuration.PostgreSQL.ConnMaxLifetime = "1 hour"
+	_, err = database.PostgreSQLPoolConfig(configuration)
+	assert.Error(t, err)
+
+	configuration.PostgreSQL.ConnMaxLifetime = ""
+	poolConfig, err = database.PostgreSQLPoolConfig(configuration)
+	require.NoError(t, err)
+	assert.Equal(t, 64, poolConfig.ConnConfig.StatementCacheCapacity)
+}
+
 func TestDisconnect(t *testing.T) {
 	// Initialize sqlmock
 	db, mock, err := sqlmock.New()
@@ -1,27 +1,181 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"go-echo-boilerplate/internal/config"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectToPostgreSQL(config *config.Configuration) (*gorm.DB, error) {
-	connection := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s timezone=Asia/Jakarta",
+// Drivers of postgresql.driver
+const (
+	DriverSimple = "simple" // simple protocol, no prepared statements; safe behind PgBouncer
+	DriverPgx    = "pgx"    // pgx pool with the extended protocol and a prepared statement cache
+)
+
+// Defaults for the postgresql.* session settings left unset
+const (
+	defaultStatementTimeout                = 30 * time.Second
+	defaultIdleInTransactionSessionTimeout = time.Minute
+	defaultStatementCacheCapacity          = 512
+)
+
+// PostgreSQLDSN returns the connection string of postgresql.*. It only has the startup
+// parameters PgBouncer accepts; the session settings are applied once connected, see
+// SessionSettings.
+func PostgreSQLDSN(config *config.Configuration) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s timezone=Asia/Jakarta",
 		config.PostgreSQL.Host,
 		config.PostgreSQL.User,
 		config.PostgreSQL.Password,
 		config.PostgreSQL.Name,
 		config.PostgreSQL.Port,
 		config.PostgreSQL.SSLMode)
+}
+
+// SessionSettings returns the SET statements every new connection runs: the server cancels
+// statements running past postgresql.statement_timeout and closes sessions idle in a
+// transaction for longer than postgresql.idle_in_transaction_session_timeout. A setting of
+// "0" is not sent, leaving the server default, e.g. that of ALTER ROLE ... SET, which is where
+// they belong behind PgBouncer in transaction mode: a SET sticks to the server connection, not
+// to this client. Queries given a context (gorm's WithContext) are also cancelled with it, e.g.
+// when the client of a request goes away.
+func SessionSettings(config *config.Configuration) []string {
+	var statements []string
+	for _, setting := range []struct {
+		name     string
+		value    string
+		fallback time.Duration
+	}{
+		{"statement_timeout", config.PostgreSQL.StatementTimeout, defaultStatementTimeout},
+		{"idle_in_transaction_session_timeout", config.PostgreSQL.IdleInTransactionSessionTimeout, defaultIdleInTransactionSessionTimeout},
+	} {
+		if timeout := sessionTimeout(setting.value, setting.fallback); timeout > 0 {
+			statements = append(statements, fmt.Sprintf("SET %s = %d", setting.name, timeout.Milliseconds()))
+		}
+	}
+	return statements
+}
+
+// afterConnect runs the SessionSettings of config on a new connection
+func afterConnect(config *config.Configuration) func(ctx context.Context, conn *pgx.Conn) error {
+	statements := SessionSettings(config)
+	return func(ctx context.Context, conn *pgx.Conn) error {
+		for _, statement := range statements {
+			if _, err := conn.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("failed to apply session setting %q: %w", statement, err)
+			}
+		}
+		return nil
+	}
+}
+
+// sessionTimeout parses a postgresql.* session timeout, fallback when unset
+func sessionTimeout(value string, fallback time.Duration) time.Duration {
+	// Validated at startup
+	timeout, err := time.ParseDuration(value)
+	if value == "" || err != nil {
+		return fallback
+	}
+	return timeout
+}
+
+// PostgreSQLPoolConfig returns the pgx pool of postgresql.driver "pgx": queries are prepared
+// once per connection and cached (up to postgresql.statement_cache_capacity statements), and
+// the pool holds up to postgresql.max_open_conns connections for postgresql.conn_max_lifetime.
+func PostgreSQLPoolConfig(config *config.Configuration) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(PostgreSQLDSN(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postgresql config: %w", err)
+	}
+
+	capacity := config.PostgreSQL.StatementCacheCapacity
+	if capacity == 0 {
+		capacity = defaultStatementCacheCapacity
+	}
+	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	poolConfig.ConnConfig.StatementCacheCapacity = capacity
+	poolConfig.AfterConnect = afterConnect(config)
+
+	if config.PostgreSQL.MaxOpenConns > 0 {
+		poolConfig.MaxConns = int32(config.PostgreSQL.MaxOpenConns)
+	}
+	if config.PostgreSQL.ConnMaxLifetime != "" {
+		lifetime, err := time.ParseDuration(config.PostgreSQL.ConnMaxLifetime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse conn_max_lifetime: %w", err)
+		}
+		poolConfig.MaxConnLifetime = lifetime
+	}
+	return poolConfig, nil
+}
+
+// poolConnector hands out connections of a pgx pool to database/sql and closes the pool
+// with the *sql.DB
+type poolConnector struct {
+	driver.Connector
+	pool *pgxpool.Pool
+}
+
+func (c poolConnector) Close() error {
+	c.pool.Close()
+	return nil
+}
 
-	db, err := gorm.Open(postgres.New(postgres.Config{
-		DSN:                  connection,
-		PreferSimpleProtocol: true, // disables implicit prepared statement usage
-	}), &gorm.Config{
+// postgreSQLDialector opens the connection of postgresql.driver
+func postgreSQLDialector(config *config.Configuration) (gorm.Dialector, error) {
+	if config.PostgreSQL.Driver != DriverPgx {
+		connConfig, err := pgx.ParseConfig(PostgreSQLDSN(config))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse postgresql config: %w", err)
+		}
+		// Disables implicit prepared statement usage
+		connConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+		return postgres.New(postgres.Config{
+			Conn: stdlib.OpenDB(*connConfig, stdlib.OptionAfterConnect(afterConnect(config))),
+		}), nil
+	}
+
+	poolConfig, err := PostgreSQLPoolConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pgx pool: %w", err)
+	}
+
+	// Idle connections are kept by the pool, not by database/sql
+	sqlDB := sql.OpenDB(poolConnector{Connector: stdlib.GetPoolConnector(pool), pool: pool})
+	sqlDB.SetMaxIdleConns(0)
+	return postgres.New(postgres.Config{Conn: sqlDB}), nil
+}
+
+// OpenPostgreSQLDB opens a *sql.DB on postgresql.* apart from the pool of gorm, e.g. for
+// lock.PostgresLocker, whose locks each keep a connection. Connections are opened on first use.
+func OpenPostgreSQLDB(config *config.Configuration) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(PostgreSQLDSN(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postgresql config: %w", err)
+	}
+	return stdlib.OpenDB(*connConfig, stdlib.OptionAfterConnect(afterConnect(config))), nil
+}
+
+func ConnectToPostgreSQL(config *config.Configuration) (*gorm.DB, error) {
+	dialector, err := postgreSQLDialector(config)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
 		NowFunc: func() time.Time {
 			loc, err := time.LoadLocation(config.Application.Timezone)
 			if err != nil {
@@ -35,21 +189,29 @@ func ConnectToPostgreSQL(config *config.Configuration) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := RegisterAuditCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register audit callbacks: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying *sql.DB: %w", err)
 	}
 
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(config.PostgreSQL.MaxIdleConns)
+	// Set connection pool settings; with the pgx driver the pool keeps the idle connections
+	// and retires them after conn_max_lifetime
 	sqlDB.SetMaxOpenConns(config.PostgreSQL.MaxOpenConns)
 
-	if config.PostgreSQL.ConnMaxLifetime != "" {
-		lifetime, err := time.ParseDuration(config.PostgreSQL.ConnMaxLifetime)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse conn_max_lifetime: %w", err)
+	if config.PostgreSQL.Driver != DriverPgx {
+		sqlDB.SetMaxIdleConns(config.PostgreSQL.MaxIdleConns)
+
+		if config.PostgreSQL.ConnMaxLifetime != "" {
+			lifetime, err := time.ParseDuration(config.PostgreSQL.ConnMaxLifetime)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse conn_max_lifetime: %w", err)
+			}
+			sqlDB.SetConnMaxLifetime(lifetime)
 		}
-		sqlDB.SetConnMaxLifetime(lifetime)
 	}
 
 	if err := sqlDB.Ping(); err != nil {
@@ -0,0 +1,65 @@
+package database_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/database"
+)
+
+// BenchmarkPostgreSQLDriver compares the postgresql.driver modes on a parameterized point
+// query against a real server, configured like the application with APP_POSTGRESQL_* (and
+// skipped without APP_POSTGRESQL_HOST):
+//
+//	APP_POSTGRESQL_HOST=localhost APP_POSTGRESQL_NAME=app APP_POSTGRESQL_USER=app \
+//	APP_POSTGRESQL_PASSWORD=secret go test ./internal/pkg/database/ -run '^$' -bench PostgreSQLDriver -benchmem
+func BenchmarkPostgreSQLDriver(b *testing.B) {
+	host := os.Getenv(config.EnvPrefix + "_POSTGRESQL_HOST")
+	if host == "" {
+		b.Skip(config.EnvPrefix + "_POSTGRESQL_HOST is not set")
+	}
+	port, err := strconv.Atoi(os.Getenv(config.EnvPrefix + "_POSTGRESQL_PORT"))
+	if err != nil {
+		port = 5432
+	}
+
+	for _, driver := range []string{database.DriverSimple, database.DriverPgx} {
+		b.Run(driver, func(b *testing.B) {
+			configuration := &config.Configuration{}
+			configuration.PostgreSQL.Host = host
+			configuration.PostgreSQL.Port = port
+			configuration.PostgreSQL.Name = os.Getenv(config.EnvPrefix + "_POSTGRESQL_NAME")
+			configuration.PostgreSQL.User = os.Getenv(config.EnvPrefix + "_POSTGRESQL_USER")
+			configuration.PostgreSQL.Password = os.Getenv(config.EnvPrefix + "_POSTGRESQL_PASSWORD")
+			configuration.PostgreSQL.SSLMode = "disable"
+			if sslMode := os.Getenv(config.EnvPrefix + "_POSTGRESQL_SSL_MODE"); sslMode != "" {
+				configuration.PostgreSQL.SSLMode = sslMode
+			}
+			configuration.PostgreSQL.MaxIdleConns = 10
+			configuration.PostgreSQL.MaxOpenConns = 10
+			configuration.PostgreSQL.Driver = driver
+
+			db, err := database.ConnectToPostgreSQL(configuration)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Cleanup(func() { _ = database.DisconnectFromPostgreSQL(db) })
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				var relation struct {
+					Relname string
+					Relkind string
+				}
+				for pb.Next() {
+					if err := db.Raw("SELECT relname, relkind FROM pg_catalog.pg_class WHERE relname = ? AND relnamespace = ?::regnamespace",
+						"pg_class", "pg_catalog").Scan(&relation).Error; err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
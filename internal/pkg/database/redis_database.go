@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ConnectToRedis creates the Redis client and pings it. It returns nil when redis.addr is empty.
+func ConnectToRedis(config *config.Configuration) (*redis.Client, error) {
+	if config.Redis.Addr == "" {
+		return nil, nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Redis.Addr,
+		Username: config.Redis.Username,
+		Password: config.Redis.Password,
+		DB:       config.Redis.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,171 @@
+// Package di is a small dependency injection container. Each layer registers the
+// constructors of its components with Provide, and the container calls them in dependency
+// order, once, when a component is first needed.
+//
+// A constructor is any function returning the component, optionally followed by an error.
+// Its parameters are resolved from the container by type:
+//
+//	di.Provide(c, pgsql.New)              // func(*gorm.DB) *pgsql.PostgreRepository
+//	di.Provide(c, service.NewUserService) // func(*service.Dependencies) service.UserService
+//
+//	users, err := di.Resolve[service.UserService](c)
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var errorType = reflect.TypeFor[error]()
+
+// Container holds the constructors and the components built from them.
+// It is safe for concurrent use.
+type Container struct {
+	mu        sync.Mutex
+	providers map[reflect.Type]reflect.Value
+	instances map[reflect.Type]reflect.Value
+}
+
+// New creates an empty Container.
+func New() *Container {
+	return &Container{
+		providers: make(map[reflect.Type]reflect.Value),
+		instances: make(map[reflect.Type]reflect.Value),
+	}
+}
+
+// Provide registers the constructor of the component T, the first result of constructor.
+// Providing T again replaces the constructor (and a component already built), so tests can
+// swap an implementation. It panics when constructor is not a function returning T or (T, error).
+func Provide(c *Container, constructor any) {
+	fn := reflect.ValueOf(constructor)
+	if err := checkConstructor(fn.Type()); err != nil {
+		panic(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	component := fn.Type().Out(0)
+	c.providers[component] = fn
+	delete(c.instances, component)
+}
+
+// Supply registers an already built component.
+func Supply[T any](c *Container, component T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	typ := reflect.TypeFor[T]()
+	delete(c.providers, typ)
+	c.instances[typ] = reflect.ValueOf(&component).Elem()
+}
+
+// Resolve returns the component T, building it and its dependencies first if needed.
+// The error names the chain of components that led to a missing or failing constructor.
+func Resolve[T any](c *Container) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var component T
+	value, err := c.resolve(reflect.TypeFor[T](), nil)
+	if err != nil {
+		return component, err
+	}
+	component, _ = value.Interface().(T) // a nil interface component stays the zero T
+	return component, nil
+}
+
+// Invoke calls fn with its parameters resolved from the container, for the setup steps that
+// use components without building one (e.g. installing package-level defaults). fn may
+// return an error, which Invoke returns.
+func Invoke(c *Container, fn any) error {
+	value := reflect.ValueOf(fn)
+	typ := value.Type()
+	if typ.Kind() != reflect.Func || typ.NumOut() > 1 || (typ.NumOut() == 1 && typ.Out(0) != errorType) {
+		panic(fmt.Errorf("di: Invoke needs a function returning nothing or an error, got %s", typ))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args, err := c.arguments(typ, nil)
+	if err != nil {
+		return err
+	}
+	if results := value.Call(args); len(results) == 1 && !results[0].IsNil() {
+		return results[0].Interface().(error)
+	}
+	return nil
+}
+
+// resolve builds typ; path is the chain of components being built, to report cycles
+func (c *Container) resolve(typ reflect.Type, path []reflect.Type) (reflect.Value, error) {
+	if value, ok := c.instances[typ]; ok {
+		return value, nil
+	}
+
+	path = append(path, typ)
+	for _, building := range path[:len(path)-1] {
+		if building == typ {
+			return reflect.Value{}, fmt.Errorf("di: dependency cycle %s", formatPath(path))
+		}
+	}
+
+	constructor, ok := c.providers[typ]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("di: no provider for %s", formatPath(path))
+	}
+
+	args, err := c.arguments(constructor.Type(), path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	results := constructor.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("%s: %w", formatPath(path), results[1].Interface().(error))
+	}
+
+	c.instances[typ] = results[0]
+	return results[0], nil
+}
+
+// arguments resolves the parameters of the function type fn
+func (c *Container) arguments(fn reflect.Type, path []reflect.Type) ([]reflect.Value, error) {
+	args := make([]reflect.Value, fn.NumIn())
+	for i := range args {
+		arg, err := c.resolve(fn.In(i), path)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+func checkConstructor(typ reflect.Type) error {
+	switch {
+	case typ.Kind() != reflect.Func:
+		return fmt.Errorf("di: constructor must be a function, got %s", typ)
+	case typ.IsVariadic():
+		return fmt.Errorf("di: constructor must not be variadic, got %s", typ)
+	case typ.NumOut() == 1 && typ.Out(0) != errorType:
+		return nil
+	case typ.NumOut() == 2 && typ.Out(0) != errorType && typ.Out(1) == errorType:
+		return nil
+	default:
+		return errors.New("di: constructor must return T or (T, error), got " + typ.String())
+	}
+}
+
+func formatPath(path []reflect.Type) string {
+	names := make([]string, len(path))
+	for i, typ := range path {
+		names[i] = typ.String()
+	}
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,115 @@
+package di_test
+
+import (
+	"errors"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/di"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	config     struct{ name string }
+	repository struct{ config *config }
+	service    interface{ Name() string }
+	greeter    struct{ repository *repository }
+)
+
+func (g *greeter) Name() string { return g.repository.config.name }
+
+func newRepository(config *config) *repository { return &repository{config: config} }
+
+func newGreeter(repository *repository) (service, error) {
+	return &greeter{repository: repository}, nil
+}
+
+func TestResolve(t *testing.T) {
+	c := di.New()
+	di.Supply(c, &config{name: "boilerplate"})
+	di.Provide(c, newRepository)
+	di.Provide(c, newGreeter)
+
+	svc, err := di.Resolve[service](c)
+	require.NoError(t, err)
+	assert.Equal(t, "boilerplate", svc.Name())
+
+	repo, err := di.Resolve[*repository](c)
+	require.NoError(t, err)
+	assert.Same(t, svc.(*greeter).repository, repo, "components are built once")
+
+	t.Run("Replace", func(t *testing.T) {
+		di.Provide(c, func() *config { return &config{name: "replaced"} })
+		di.Provide(c, newRepository)
+		di.Provide(c, newGreeter)
+
+		svc, err := di.Resolve[service](c)
+		require.NoError(t, err)
+		assert.Equal(t, "replaced", svc.Name())
+	})
+
+	t.Run("Nil Interface", func(t *testing.T) {
+		di.Provide(c, func() (service, error) { return nil, nil })
+
+		svc, err := di.Resolve[service](c)
+		assert.NoError(t, err)
+		assert.Nil(t, svc)
+	})
+}
+
+func TestResolve_Errors(t *testing.T) {
+	t.Run("Missing Provider", func(t *testing.T) {
+		c := di.New()
+		di.Provide(c, newRepository)
+		di.Provide(c, newGreeter)
+
+		_, err := di.Resolve[service](c)
+		assert.EqualError(t, err, "di: no provider for di_test.service -> *di_test.repository -> *di_test.config")
+	})
+
+	t.Run("Failing Constructor", func(t *testing.T) {
+		c := di.New()
+		di.Supply(c, &config{})
+		di.Provide(c, func(*config) (*repository, error) { return nil, errors.New("connection refused") })
+		di.Provide(c, newGreeter)
+
+		_, err := di.Resolve[service](c)
+		assert.EqualError(t, err, "di_test.service -> *di_test.repository: connection refused")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		c := di.New()
+		di.Provide(c, func(*repository) *config { return nil })
+		di.Provide(c, newRepository)
+
+		_, err := di.Resolve[*config](c)
+		assert.EqualError(t, err, "di: dependency cycle *di_test.config -> *di_test.repository -> *di_test.config")
+	})
+
+	t.Run("Invalid Constructor", func(t *testing.T) {
+		c := di.New()
+		assert.Panics(t, func() { di.Provide(c, &config{}) })
+		assert.Panics(t, func() { di.Provide(c, func() {}) })
+		assert.Panics(t, func() { di.Provide(c, func() (*config, *repository) { return nil, nil }) })
+	})
+}
+
+func TestInvoke(t *testing.T) {
+	c := di.New()
+	di.Supply(c, &config{name: "boilerplate"})
+	di.Provide(c, newRepository)
+
+	var name string
+	err := di.Invoke(c, func(repository *repository) {
+		name = repository.config.name
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "boilerplate", name)
+
+	err = di.Invoke(c, func(*repository) error { return errors.New("install failed") })
+	assert.EqualError(t, err, "install failed")
+
+	err = di.Invoke(c, func(service) {})
+	assert.EqualError(t, err, "di: no provider for di_test.service")
+}
@@ -0,0 +1,153 @@
+// Package diagnostics reports the runtime of the instance (goroutines, memory, GC) and dumps
+// its heap profile to a Storage, for /admin/runtime: production issues are diagnosed without
+// a shell on the host. The dumps are read offline with go tool pprof.
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+)
+
+// ErrNoStorage is returned by HeapDump without a Storage, diagnostics.dump_dir unset
+var ErrNoStorage = errors.New("diagnostics: heap dumps need diagnostics.dump_dir")
+
+// started approximates the start of the process
+var started = time.Now()
+
+// Storage keeps the dumps, e.g. DirStorage
+type Storage interface {
+	// Save stores data under name, returning where it is
+	Save(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// NewStorage returns the storage of cfg, nil when diagnostics.dump_dir is unset
+func NewStorage(cfg config.Diagnostics) Storage {
+	if cfg.DumpDir == "" {
+		return nil
+	}
+	return NewDirStorage(cfg.DumpDir)
+}
+
+// DirStorage writes the dumps to a directory, e.g. a volume collected off the host
+type DirStorage struct {
+	dir string
+}
+
+// NewDirStorage returns a storage writing to dir, created on the first Save
+func NewDirStorage(dir string) *DirStorage {
+	return &DirStorage{dir: dir}
+}
+
+// Save writes data to a file of the directory; a partial file is never left under name
+func (s *DirStorage) Save(_ context.Context, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+// Runtime reports the goroutines, memory, and GC of the process. It stops the world briefly
+// to read runtime.MemStats.
+func Runtime() models.RuntimeResponse {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	gc := models.RuntimeGCResponse{
+		NumGC:        stats.NumGC,
+		NumForcedGC:  stats.NumForcedGC,
+		NextGC:       stats.NextGC,
+		PauseTotalMs: milliseconds(stats.PauseTotalNs),
+		CPUFraction:  stats.GCCPUFraction,
+	}
+	if stats.NumGC > 0 {
+		last := time.Unix(0, int64(stats.LastGC))
+		gc.LastGC = &last
+		gc.LastPauseMs = milliseconds(stats.PauseNs[(stats.NumGC+255)%256])
+	}
+
+	return models.RuntimeResponse{
+		Goroutines: runtime.NumGoroutine(),
+		CPUs:       runtime.GOMAXPROCS(0),
+		GoVersion:  runtime.Version(),
+		Uptime:     time.Since(started).Round(time.Second).String(),
+		Memory: models.RuntimeMemoryResponse{
+			Sys:         stats.Sys,
+			HeapAlloc:   stats.HeapAlloc,
+			HeapInuse:   stats.HeapInuse,
+			HeapIdle:    stats.HeapIdle,
+			HeapObjects: stats.HeapObjects,
+			StackInuse:  stats.StackInuse,
+			TotalAlloc:  stats.TotalAlloc,
+			Mallocs:     stats.Mallocs,
+			Frees:       stats.Frees,
+		},
+		GC: gc,
+	}
+}
+
+func milliseconds(ns uint64) float64 {
+	return float64(ns) / float64(time.Millisecond)
+}
+
+// HeapDump saves the heap profile of the process to storage, after a GC so it reflects the
+// live objects, like /debug/pprof/heap?gc=1. It is named after the host, the pid, and the
+// time, so the dumps of the instances don't collide.
+func HeapDump(ctx context.Context, storage Storage) (models.HeapDumpResponse, error) {
+	if storage == nil {
+		return models.HeapDumpResponse{}, ErrNoStorage
+	}
+
+	runtime.GC()
+	var profile bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&profile, 0); err != nil {
+		return models.HeapDumpResponse{}, fmt.Errorf("diagnostics: heap profile: %w", err)
+	}
+
+	now := time.Now().UTC()
+	host, _ := os.Hostname()
+	name := fmt.Sprintf("heap-%s-%d-%s.pb.gz", fileSafe(host), os.Getpid(), now.Format("20060102T150405Z"))
+	location, err := storage.Save(ctx, name, profile.Bytes())
+	if err != nil {
+		return models.HeapDumpResponse{}, fmt.Errorf("diagnostics: save %s: %w", name, err)
+	}
+
+	return models.HeapDumpResponse{
+		Name:      name,
+		Location:  location,
+		Size:      profile.Len(),
+		CreatedAt: now,
+	}, nil
+}
+
+// fileSafe keeps the letters, digits, dots, and dashes of s
+func fileSafe(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
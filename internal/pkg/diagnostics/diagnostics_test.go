@@ -0,0 +1,57 @@
+package diagnostics
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/config"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntime(t *testing.T) {
+	runtime.GC()
+	report := Runtime()
+
+	assert.Positive(t, report.Goroutines)
+	assert.Equal(t, runtime.GOMAXPROCS(0), report.CPUs)
+	assert.Equal(t, runtime.Version(), report.GoVersion)
+	assert.Positive(t, report.Memory.Sys)
+	assert.Positive(t, report.Memory.HeapAlloc)
+	assert.Positive(t, report.GC.NumGC)
+	assert.NotNil(t, report.GC.LastGC)
+}
+
+func TestHeapDump(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dumps")
+	storage := NewStorage(config.Diagnostics{DumpDir: dir})
+
+	dump, err := HeapDump(context.Background(), storage)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dump.Name, "heap-"))
+	assert.Equal(t, filepath.Join(dir, dump.Name), dump.Location)
+
+	data, err := os.ReadFile(dump.Location)
+	require.NoError(t, err)
+	assert.Len(t, data, dump.Size)
+	assert.Equal(t, []byte{0x1f, 0x8b}, data[:2], "gzipped protobuf")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file left")
+
+	t.Run("Without Storage", func(t *testing.T) {
+		_, err := HeapDump(context.Background(), NewStorage(config.Diagnostics{}))
+		assert.ErrorIs(t, err, ErrNoStorage)
+	})
+}
+
+func TestFileSafe(t *testing.T) {
+	assert.Equal(t, "api-1.local", fileSafe("api-1.local"))
+	assert.Equal(t, "etcpasswd", fileSafe("etc/passwd"))
+	assert.Equal(t, "unknown", fileSafe("/"))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
 	"net/http"
 )
 
@@ -68,11 +69,26 @@ func Error(err error, message ...any) *HTTPError {
 	}
 }
 
+// Validation wraps field-level validation errors into a 400 VALIDATION_ERROR response,
+// for validation that happens outside of request binding (e.g. in services).
+func Validation(errs ...models.ErrorValidationResponse) *HTTPError {
+	resp := ErrorValidation.Response
+	resp.Errors = errs
+	return &HTTPError{Response: resp}
+}
+
 // ==== Fallback Extractor ====
 
 // GetResponse extracts the models.ErrorResponse from an error.
-// If it's not an HTTPError, returns a default 500 INTERNAL_SERVER_ERROR.
+// A request body read past its limit (*http.MaxBytesError, e.g. of a bind) is
+// ErrorPayloadTooLarge; other errors that aren't an HTTPError return a default
+// 500 INTERNAL_SERVER_ERROR.
 func GetResponse(err error) models.ErrorResponse {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return ErrorPayloadTooLarge.Response
+	}
+
 	var httpErr *HTTPError
 	if errors.As(err, &httpErr) {
 		return httpErr.Response
@@ -84,3 +100,13 @@ func GetResponse(err error) models.ErrorResponse {
 		Message: err.Error(),
 	}
 }
+
+// ==== Localization ====
+// Localize extracts the models.ErrorResponse from an error (see GetResponse) and
+// translates its message into the given locale. Messages without a catalog entry
+// are returned unchanged.
+func Localize(err error, locale i18n.Locale) models.ErrorResponse {
+	resp := GetResponse(err)
+	resp.Message = i18n.T(locale, resp.Message)
+	return resp
+}
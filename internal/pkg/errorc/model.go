@@ -11,17 +11,27 @@ func wrap(resp models.ErrorResponse) *HTTPError {
 
 // ==== Predefined ErrorResponses ====
 var (
-	ErrorUserNotFound   = wrap(models.ErrorResponse{Code: http.StatusNotFound, Status: "DATA_NOT_FOUND", Message: "user not found"})
-	ErrorUnauthorized   = wrap(models.ErrorResponse{Code: http.StatusUnauthorized, Status: "UNAUTHORIZED", Message: "unauthorized"})
-	ErrorForbidden      = wrap(models.ErrorResponse{Code: http.StatusForbidden, Status: "FORBIDDEN", Message: "forbidden"})
-	ErrorEmailExists    = wrap(models.ErrorResponse{Code: http.StatusConflict, Status: "CONFLICT", Message: "email already exists"})
-	ErrorTokenExpired   = wrap(models.ErrorResponse{Code: http.StatusUnauthorized, Status: "TOKEN_EXPIRED", Message: "token expired"})
-	ErrorAlreadyExist   = wrap(models.ErrorResponse{Code: http.StatusConflict, Status: "ALREADY_EXISTS", Message: "resource already exists"})
-	ErrorDataNotFound   = wrap(models.ErrorResponse{Code: http.StatusNotFound, Status: "DATA_NOT_FOUND", Message: "data not found"})
-	ErrorInvalidInput   = wrap(models.ErrorResponse{Code: http.StatusBadRequest, Status: "BAD_REQUEST", Message: "invalid input"})
-	ErrorInvalidData    = wrap(models.ErrorResponse{Code: http.StatusBadRequest, Status: "INVALID_DATA", Message: "invalid data"})
-	ErrorForbiddenRole  = wrap(models.ErrorResponse{Code: http.StatusForbidden, Status: "FORBIDDEN_ROLE", Message: "you are not allowed to access this feature"})
-	ErrorInternalServer = wrap(models.ErrorResponse{Code: http.StatusInternalServerError, Status: "INTERNAL_SERVER_ERROR", Message: "Unknown server error occurred."})
-	ErrorValidation     = wrap(models.ErrorResponse{Code: http.StatusBadRequest, Status: "VALIDATION_ERROR", Message: "Validation failed for one or more fields."})
-	ErrorDatabase       = wrap(models.ErrorResponse{Code: http.StatusInternalServerError, Status: "DATABASE_ERROR", Message: "Database error occurred."})
+	ErrorUserNotFound         = wrap(models.ErrorResponse{Code: http.StatusNotFound, Status: "DATA_NOT_FOUND", Message: "user not found"})
+	ErrorUnauthorized         = wrap(models.ErrorResponse{Code: http.StatusUnauthorized, Status: "UNAUTHORIZED", Message: "unauthorized"})
+	ErrorForbidden            = wrap(models.ErrorResponse{Code: http.StatusForbidden, Status: "FORBIDDEN", Message: "forbidden"})
+	ErrorEmailExists          = wrap(models.ErrorResponse{Code: http.StatusConflict, Status: "CONFLICT", Message: "email already exists"})
+	ErrorTokenExpired         = wrap(models.ErrorResponse{Code: http.StatusUnauthorized, Status: "TOKEN_EXPIRED", Message: "token expired"})
+	ErrorAlreadyExist         = wrap(models.ErrorResponse{Code: http.StatusConflict, Status: "ALREADY_EXISTS", Message: "resource already exists"})
+	ErrorDataNotFound         = wrap(models.ErrorResponse{Code: http.StatusNotFound, Status: "DATA_NOT_FOUND", Message: "data not found"})
+	ErrorInvalidInput         = wrap(models.ErrorResponse{Code: http.StatusBadRequest, Status: "BAD_REQUEST", Message: "invalid input"})
+	ErrorInvalidData          = wrap(models.ErrorResponse{Code: http.StatusBadRequest, Status: "INVALID_DATA", Message: "invalid data"})
+	ErrorForbiddenRole        = wrap(models.ErrorResponse{Code: http.StatusForbidden, Status: "FORBIDDEN_ROLE", Message: "you are not allowed to access this feature"})
+	ErrorInternalServer       = wrap(models.ErrorResponse{Code: http.StatusInternalServerError, Status: "INTERNAL_SERVER_ERROR", Message: "Unknown server error occurred."})
+	ErrorValidation           = wrap(models.ErrorResponse{Code: http.StatusBadRequest, Status: "VALIDATION_ERROR", Message: "Validation failed for one or more fields."})
+	ErrorPayloadTooLarge      = wrap(models.ErrorResponse{Code: http.StatusRequestEntityTooLarge, Status: "PAYLOAD_TOO_LARGE", Message: "request body is too large"})
+	ErrorUnsupportedMediaType = wrap(models.ErrorResponse{Code: http.StatusUnsupportedMediaType, Status: "UNSUPPORTED_MEDIA_TYPE", Message: "unsupported content type"})
+	ErrorDatabase             = wrap(models.ErrorResponse{Code: http.StatusInternalServerError, Status: "DATABASE_ERROR", Message: "Database error occurred."})
+	ErrorCSRFToken            = wrap(models.ErrorResponse{Code: http.StatusForbidden, Status: "CSRF_TOKEN_INVALID", Message: "missing or invalid csrf token"})
+	ErrorTooManyAttempts      = wrap(models.ErrorResponse{Code: http.StatusTooManyRequests, Status: "TOO_MANY_ATTEMPTS", Message: "too many failed attempts, try again later"})
+	ErrorConsentRequired      = wrap(models.ErrorResponse{Code: http.StatusForbidden, Status: "CONSENT_REQUIRED", Message: "the current terms must be accepted"})
+	ErrorChallengeRequired    = wrap(models.ErrorResponse{Code: http.StatusPreconditionRequired, Status: "CHALLENGE_REQUIRED", Message: "missing or invalid captcha response"})
+	ErrorVersionConflict      = wrap(models.ErrorResponse{Code: http.StatusConflict, Status: "VERSION_CONFLICT", Message: "the resource was changed by another request, reload it and retry"})
+	ErrorQuotaExceeded        = wrap(models.ErrorResponse{Code: http.StatusTooManyRequests, Status: "QUOTA_EXCEEDED", Message: "the request quota of this API key is exhausted"})
+	ErrorOverloaded           = wrap(models.ErrorResponse{Code: http.StatusServiceUnavailable, Status: "OVERLOADED", Message: "the server is busy, try again later"})
+	ErrorMaintenance          = wrap(models.ErrorResponse{Code: http.StatusServiceUnavailable, Status: "MAINTENANCE", Message: "the service is under maintenance, try again later"})
 )
@@ -0,0 +1,35 @@
+package formatter
+
+import "strings"
+
+// gmailDomains are the domains of Gmail addresses; googlemail.com is an alias of gmail.com
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// Email normalizes an email address for storage and uniqueness checks: it trims spaces and
+// lowercases the address. With foldGmail, Gmail addresses are also reduced to the mailbox
+// they deliver to, since Gmail ignores dots and "+tags" in the local part:
+// "John.Doe+shop@googlemail.com" becomes "johndoe@gmail.com".
+//
+// Addresses without an "@" are only trimmed and lowercased; validation rejects them.
+func Email(email string, foldGmail bool) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !foldGmail {
+		return email
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if !gmailDomains[domain] {
+		return email
+	}
+
+	local, _, _ = strings.Cut(local, "+")
+	local = strings.ReplaceAll(local, ".", "")
+	if local == "" {
+		return email
+	}
+	return local + "@gmail.com"
+}
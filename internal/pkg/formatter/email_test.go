@@ -0,0 +1,32 @@
+package formatter_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/formatter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		foldGmail bool
+		expected  string
+	}{
+		{"Trims And Lowercases", "  John.Doe@Example.COM ", false, "john.doe@example.com"},
+		{"Keeps Gmail Without Folding", "John.Doe+shop@gmail.com", false, "john.doe+shop@gmail.com"},
+		{"Folds Gmail Dots And Tags", "John.Doe+shop@Gmail.com", true, "johndoe@gmail.com"},
+		{"Folds Googlemail To Gmail", "j.doe@googlemail.com", true, "jdoe@gmail.com"},
+		{"Leaves Other Domains Alone", "john.doe+shop@example.com", true, "john.doe+shop@example.com"},
+		{"Keeps An Empty Mailbox", "+shop@gmail.com", true, "+shop@gmail.com"},
+		{"Leaves Invalid Addresses Alone", " Not-An-Email ", true, "not-an-email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatter.Email(tt.email, tt.foldGmail))
+		})
+	}
+}
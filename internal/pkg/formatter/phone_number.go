@@ -2,41 +2,60 @@ package formatter
 
 import (
 	"errors"
+	"fmt"
 	"go-echo-boilerplate/internal/models"
 	"go-echo-boilerplate/internal/pkg/stringc"
+	"strings"
 
 	"github.com/nyaruka/phonenumbers"
 )
 
-const (
-	DefaultCountryCode        = "ID"
-	IDPhonePrefix             = "08"
-	IDE164PhonePrefix         = "62"
-	IDE164PhonePrefixWithPlus = "+62"
-)
-
-func PhoneNumber(phoneNumber models.PhoneNumber) (formattedPhoneNumber *string, err error) {
+// DefaultCountryCode is the country of national numbers sent without one
+const DefaultCountryCode = "ID"
+
+// PhoneNumber normalizes a phone number to E.164 and detects the country it belongs to.
+//
+// Numbers in international format ("+44 7400 123456", or with the international prefix of the
+// country, e.g. "00 44 ...") are parsed on their own and may belong to any country. Other
+// numbers are national numbers of CountryCode (an ISO 3166-1 alpha-2 region, DefaultCountryCode
+// when empty), with or without its calling code ("081234567890" and "6281234567890" are both
+// Indonesian).
+//
+// The returned CountryCode is the region of the number, e.g. "US" for "+16502530000", or
+// "001" for non-geographic numbers.
+func PhoneNumber(phoneNumber models.PhoneNumber) (*models.PhoneNumber, error) {
 	if stringc.ContainsAlphabet(phoneNumber.Number) {
 		return nil, errors.New("phone number contains alphabet")
 	}
 
-	if phoneNumber.CountryCode == "" {
-		phoneNumber.CountryCode = DefaultCountryCode
+	region := strings.ToUpper(strings.TrimSpace(phoneNumber.CountryCode))
+	if region == "" {
+		region = DefaultCountryCode
+	}
+	if phonenumbers.GetCountryCodeForRegion(region) == 0 {
+		return nil, fmt.Errorf("unknown country code %q", phoneNumber.CountryCode)
 	}
 
 	// parse to phone struct format
-	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber.Number, phoneNumber.CountryCode)
+	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber.Number, region)
 	if err != nil {
 		return nil, err
 	}
 
-	// check if phone number valid
-	if !phonenumbers.IsValidNumberForRegion(parsedPhoneNumber, phoneNumber.CountryCode) {
+	// check if phone number valid for the country it belongs to
+	if !phonenumbers.IsValidNumber(parsedPhoneNumber) {
 		return nil, errors.New("invalid phone number")
 	}
 
-	// format to e164
-	e164PhoneNumber := phonenumbers.Format(parsedPhoneNumber, phonenumbers.E164)
+	// Regions sharing a calling code (e.g. GB, GG and JE on +44) may all accept the number,
+	// keep the given one when it does
+	detected := region
+	if !phonenumbers.IsValidNumberForRegion(parsedPhoneNumber, region) {
+		detected = phonenumbers.GetRegionCodeForNumber(parsedPhoneNumber)
+	}
 
-	return &e164PhoneNumber, nil
+	return &models.PhoneNumber{
+		Number:      phonenumbers.Format(parsedPhoneNumber, phonenumbers.E164),
+		CountryCode: detected,
+	}, nil
 }
@@ -0,0 +1,51 @@
+package formatter_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/formatter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneNumber(t *testing.T) {
+	t.Run("Normalizes And Detects The Country", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			input       models.PhoneNumber
+			number      string
+			countryCode string
+		}{
+			{"National Number Of The Default Country", models.PhoneNumber{Number: "0812-3456-7890"}, "+6281234567890", "ID"},
+			{"Default Country With Its Calling Code", models.PhoneNumber{Number: "6281234567890"}, "+6281234567890", "ID"},
+			{"National Number Of The Given Country", models.PhoneNumber{Number: "07400 123456", CountryCode: "gb"}, "+447400123456", "GB"},
+			{"International Number", models.PhoneNumber{Number: "+1 (650) 253-0000"}, "+16502530000", "US"},
+			{"International Number Wins Over The Country", models.PhoneNumber{Number: "+33 6 12 34 56 78", CountryCode: "ID"}, "+33612345678", "FR"},
+			{"International Prefix Of The Country", models.PhoneNumber{Number: "00 49 30 901820", CountryCode: "DE"}, "+4930901820", "DE"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				formatted, err := formatter.PhoneNumber(tt.input)
+				require.NoError(t, err)
+				assert.Equal(t, tt.number, formatted.Number)
+				assert.Equal(t, tt.countryCode, formatted.CountryCode)
+			})
+		}
+	})
+
+	t.Run("Rejects Invalid Numbers", func(t *testing.T) {
+		for _, input := range []models.PhoneNumber{
+			{Number: "call me"},
+			{Number: "12345"},
+			{Number: "07400 123456"}, // a GB number is not Indonesian
+			{Number: "+999 1234 5678"},
+			{Number: "0812-3456-7890", CountryCode: "XX"},
+		} {
+			_, err := formatter.PhoneNumber(input)
+			assert.Error(t, err, "Should reject: %+v", input)
+		}
+	})
+}
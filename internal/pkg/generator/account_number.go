@@ -3,31 +3,115 @@ package generator
 import (
 	"crypto/rand"
 	"fmt"
+	"go-echo-boilerplate/internal/models"
 	"math/big"
 	"strconv"
+	"strings"
+	"sync"
 )
 
-// AccountNumberLength defines the total length of the account number including check digit
+// AccountNumberLength defines the total length of the default (card16) account number including check digit
 const AccountNumberLength = 16
 
-// GenerateAccountNumber generates a random account number with Luhn check digit
-// The format is similar to credit card numbers (16 digits with Luhn validation)
+// Built-in account number profile names
+const (
+	AccountNumberProfileCard16   = "card16"
+	AccountNumberProfileIBANLike = "iban-like"
+
+	// DefaultAccountNumberProfile is used when no profile name is given
+	DefaultAccountNumberProfile = AccountNumberProfileCard16
+)
+
+var (
+	accountNumberProfilesMu sync.RWMutex
+	accountNumberProfiles   = map[string]models.AccountNumberProfile{
+		AccountNumberProfileCard16: {
+			Name:       AccountNumberProfileCard16,
+			Length:     AccountNumberLength,
+			CheckDigit: models.CheckDigitLuhn,
+		},
+		AccountNumberProfileIBANLike: {
+			Name:       AccountNumberProfileIBANLike,
+			Length:     18,
+			CheckDigit: models.CheckDigitMod97,
+		},
+	}
+)
+
+// RegisterAccountNumberProfile adds or replaces a named account number profile.
+// Profiles are usually registered from config at startup.
+//
+// Example:
+//
+//	err := generator.RegisterAccountNumberProfile(models.AccountNumberProfile{
+//	    Name:       "branch",
+//	    Length:     12,
+//	    Prefixes:   []string{"10", "20"},
+//	    CheckDigit: models.CheckDigitLuhn,
+//	})
+func RegisterAccountNumberProfile(profile models.AccountNumberProfile) error {
+	if profile.Name == "" {
+		return fmt.Errorf("account number profile name must not be empty")
+	}
+	if profile.Length <= profile.CheckDigits() {
+		return fmt.Errorf("account number profile %q: length must be greater than %d", profile.Name, profile.CheckDigits())
+	}
+	if profile.CheckDigit != models.CheckDigitLuhn && profile.CheckDigit != models.CheckDigitMod97 {
+		return fmt.Errorf("account number profile %q: unknown check digit algorithm %q", profile.Name, profile.CheckDigit)
+	}
+	for _, prefix := range profile.Prefixes {
+		if !isDigits(prefix) {
+			return fmt.Errorf("account number profile %q: prefix %q must contain only digits", profile.Name, prefix)
+		}
+		if len(prefix) > profile.Length-profile.CheckDigits() {
+			return fmt.Errorf("account number profile %q: prefix %q is too long", profile.Name, prefix)
+		}
+	}
+
+	accountNumberProfilesMu.Lock()
+	defer accountNumberProfilesMu.Unlock()
+	accountNumberProfiles[profile.Name] = profile
+
+	return nil
+}
+
+// GetAccountNumberProfile returns the named profile ("" returns the default profile).
+func GetAccountNumberProfile(name string) (models.AccountNumberProfile, bool) {
+	if name == "" {
+		name = DefaultAccountNumberProfile
+	}
+
+	accountNumberProfilesMu.RLock()
+	defer accountNumberProfilesMu.RUnlock()
+	profile, ok := accountNumberProfiles[name]
+	return profile, ok
+}
+
+// AccountNumber generates a random account number using the named profile
+// ("" uses DefaultAccountNumberProfile: 16 digits with a Luhn check digit).
 //
 // Parameters:
+//   - profile: Name of a built-in or registered profile
 //   - affix: Optional variadic parameters for prefix and/or suffix
-//   - affix[0]: Prefix - digits to prepend to the account number
-//   - affix[1]: Suffix - digits to append before the check digit
+//   - affix[0]: Prefix - digits to prepend, overrides the profile's prefix pool
+//   - affix[1]: Suffix - digits to append before the check digits
 //
 // Examples:
-//   - GenerateAccountNumber() -> "4532015112830366" (fully random)
-//   - GenerateAccountNumber("99") -> "9932015112830366" (with prefix "99")
-//   - GenerateAccountNumber("99", "88") -> "9932015112883066" (with prefix "99" and suffix "88")
-//   - GenerateAccountNumber("", "88") -> "4532015112883088" (with suffix "88" only)
+//   - AccountNumber("") -> "4532015112830366" (fully random)
+//   - AccountNumber("", "99") -> "9932015112830366" (with prefix "99")
+//   - AccountNumber("", "99", "88") -> "9932015112883066" (with prefix "99" and suffix "88")
+//   - AccountNumber("", "", "88") -> "4532015112883088" (with suffix "88" only)
+//   - AccountNumber("iban-like") -> "123456789012345617" (18 digits, mod-97 check digits)
 //
 // Returns:
-//   - string: A valid account number with Luhn check digit
-//   - error: If affix is invalid or random number generation fails
-func AccountNumber(affix ...string) (string, error) {
+//   - string: A valid account number with check digit(s)
+//   - error: If the profile is unknown, affix is invalid, or random number generation fails
+func AccountNumber(profileName string, affix ...string) (string, error) {
+	profile, ok := GetAccountNumberProfile(profileName)
+	if !ok {
+		return "", fmt.Errorf("unknown account number profile %q", profileName)
+	}
+
 	var prefix, suffix string
 
 	// Extract prefix and suffix from affix
@@ -38,33 +122,40 @@ func AccountNumber(affix ...string) (string, error) {
 		suffix = affix[1]
 	}
 
+	// Pick a prefix from the profile's pool unless one was given
+	if prefix == "" && len(profile.Prefixes) > 0 {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(profile.Prefixes))))
+		if err != nil {
+			return "", fmt.Errorf("failed to pick prefix: %w", err)
+		}
+		prefix = profile.Prefixes[n.Int64()]
+	}
+
+	checkDigits := profile.CheckDigits()
+
 	// Validate total length
-	if len(prefix)+len(suffix) >= AccountNumberLength {
-		return "", fmt.Errorf("combined prefix and suffix length must be less than %d", AccountNumberLength)
+	if maxLength := profile.Length - checkDigits + 1; len(prefix)+len(suffix) >= maxLength {
+		return "", fmt.Errorf("combined prefix and suffix length must be less than %d", maxLength)
 	}
 
 	// Validate prefix contains only digits
-	for _, char := range prefix {
-		if char < '0' || char > '9' {
-			return "", fmt.Errorf("prefix must contain only digits")
-		}
+	if !isDigits(prefix) {
+		return "", fmt.Errorf("prefix must contain only digits")
 	}
 
 	// Validate suffix contains only digits
-	for _, char := range suffix {
-		if char < '0' || char > '9' {
-			return "", fmt.Errorf("suffix must contain only digits")
-		}
+	if !isDigits(suffix) {
+		return "", fmt.Errorf("suffix must contain only digits")
 	}
 
 	// Convert prefix to digits
-	digits := make([]int, 0, AccountNumberLength)
+	digits := make([]int, 0, profile.Length)
 	for _, char := range prefix {
 		digits = append(digits, int(char-'0'))
 	}
 
-	// Calculate how many random digits we need (excluding check digit and suffix)
-	randomLength := AccountNumberLength - len(prefix) - len(suffix) - 1
+	// Calculate how many random digits we need (excluding check digits and suffix)
+	randomLength := profile.Length - len(prefix) - len(suffix) - checkDigits
 
 	// Generate random digits
 	for i := 0; i < randomLength; i++ {
@@ -80,17 +171,21 @@ func AccountNumber(affix ...string) (string, error) {
 		digits = append(digits, int(char-'0'))
 	}
 
-	// Calculate and append Luhn check digit
-	checkDigit := calculateLuhnCheckDigit(digits)
-	digits = append(digits, checkDigit)
+	// Calculate and append check digits
+	if profile.CheckDigit == models.CheckDigitMod97 {
+		checkDigit := calculateMod97CheckDigits(digits)
+		digits = append(digits, checkDigit/10, checkDigit%10)
+	} else {
+		digits = append(digits, calculateLuhnCheckDigit(digits))
+	}
 
 	// Convert to string
-	accountNumber := ""
+	var accountNumber strings.Builder
 	for _, digit := range digits {
-		accountNumber += strconv.Itoa(digit)
+		accountNumber.WriteString(strconv.Itoa(digit))
 	}
 
-	return accountNumber, nil
+	return accountNumber.String(), nil
 }
 
 // calculateLuhnCheckDigit calculates the Luhn check digit for a sequence of digits
@@ -121,3 +216,27 @@ func calculateLuhnCheckDigit(digits []int) int {
 	checkDigit := (10 - (sum % 10)) % 10
 	return checkDigit
 }
+
+// calculateMod97CheckDigits calculates the two ISO 7064 MOD 97-10 check digits (02-98)
+// for a sequence of digits, so that the number followed by the check digits is 1 mod 97
+// The input should NOT include the check digits
+func calculateMod97CheckDigits(digits []int) int {
+	remainder := 0
+	for _, digit := range digits {
+		remainder = (remainder*10 + digit) % 97
+	}
+
+	// Shift left by the two check digit positions
+	remainder = (remainder * 100) % 97
+
+	return 98 - remainder
+}
+
+func isDigits(s string) bool {
+	for _, char := range s {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"go-echo-boilerplate/internal/models"
 	"go-echo-boilerplate/internal/pkg/validator"
 	"testing"
 
@@ -9,7 +10,7 @@ import (
 
 func TestGenerateAccountNumber(t *testing.T) {
 	t.Run("Generate Valid Account Number", func(t *testing.T) {
-		accountNumber, err := AccountNumber()
+		accountNumber, err := AccountNumber("")
 		assert.NoError(t, err)
 		assert.NotEmpty(t, accountNumber)
 		assert.Len(t, accountNumber, AccountNumberLength)
@@ -23,7 +24,7 @@ func TestGenerateAccountNumber(t *testing.T) {
 
 		// Generate 100 account numbers
 		for i := 0; i < 100; i++ {
-			accountNumber, err := AccountNumber()
+			accountNumber, err := AccountNumber("")
 			assert.NoError(t, err)
 
 			// Check uniqueness (very high probability)
@@ -39,7 +40,7 @@ func TestGenerateAccountNumber(t *testing.T) {
 func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 	t.Run("Generate With Valid Prefix", func(t *testing.T) {
 		prefix := "45"
-		accountNumber, err := AccountNumber(prefix)
+		accountNumber, err := AccountNumber("", prefix)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, accountNumber)
 		assert.Len(t, accountNumber, AccountNumberLength)
@@ -53,7 +54,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 
 	t.Run("Generate With Longer Prefix", func(t *testing.T) {
 		prefix := "453201"
-		accountNumber, err := AccountNumber(prefix)
+		accountNumber, err := AccountNumber("", prefix)
 		assert.NoError(t, err)
 		assert.Equal(t, prefix, accountNumber[:len(prefix)])
 		assert.True(t, validator.AccountNumber(accountNumber))
@@ -61,7 +62,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 
 	t.Run("Generate With Suffix", func(t *testing.T) {
 		suffix := "88"
-		accountNumber, err := AccountNumber("", suffix)
+		accountNumber, err := AccountNumber("", "", suffix)
 		assert.NoError(t, err)
 		assert.Len(t, accountNumber, AccountNumberLength)
 
@@ -76,7 +77,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 	t.Run("Generate With Prefix And Suffix", func(t *testing.T) {
 		prefix := "99"
 		suffix := "88"
-		accountNumber, err := AccountNumber(prefix, suffix)
+		accountNumber, err := AccountNumber("", prefix, suffix)
 		assert.NoError(t, err)
 		assert.Len(t, accountNumber, AccountNumberLength)
 
@@ -93,7 +94,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 
 	t.Run("Invalid Prefix - Too Long", func(t *testing.T) {
 		prefix := "12345678901234567" // 17 digits
-		accountNumber, err := AccountNumber(prefix)
+		accountNumber, err := AccountNumber("", prefix)
 		assert.Error(t, err)
 		assert.Empty(t, accountNumber)
 		assert.Contains(t, err.Error(), "combined prefix and suffix length must be less than")
@@ -101,7 +102,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 
 	t.Run("Invalid Prefix - Contains Non-Digits", func(t *testing.T) {
 		prefix := "45a2"
-		accountNumber, err := AccountNumber(prefix)
+		accountNumber, err := AccountNumber("", prefix)
 		assert.Error(t, err)
 		assert.Empty(t, accountNumber)
 		assert.Contains(t, err.Error(), "prefix must contain only digits")
@@ -109,7 +110,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 
 	t.Run("Invalid Suffix - Contains Non-Digits", func(t *testing.T) {
 		suffix := "88x"
-		accountNumber, err := AccountNumber("", suffix)
+		accountNumber, err := AccountNumber("", "", suffix)
 		assert.Error(t, err)
 		assert.Empty(t, accountNumber)
 		assert.Contains(t, err.Error(), "suffix must contain only digits")
@@ -118,7 +119,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 	t.Run("Invalid Prefix And Suffix - Too Long Combined", func(t *testing.T) {
 		prefix := "123456789"
 		suffix := "123456789"
-		accountNumber, err := AccountNumber(prefix, suffix)
+		accountNumber, err := AccountNumber("", prefix, suffix)
 		assert.Error(t, err)
 		assert.Empty(t, accountNumber)
 		assert.Contains(t, err.Error(), "combined prefix and suffix length must be less than")
@@ -129,7 +130,7 @@ func TestGenerateAccountNumberWithPrefix(t *testing.T) {
 		numbers := make(map[string]bool)
 
 		for i := 0; i < 50; i++ {
-			accountNumber, err := AccountNumber(prefix)
+			accountNumber, err := AccountNumber("", prefix)
 			assert.NoError(t, err)
 			assert.Equal(t, prefix, accountNumber[:len(prefix)])
 
@@ -203,12 +204,66 @@ func TestValidateLuhn(t *testing.T) {
 // Benchmark tests
 func BenchmarkGenerateAccountNumber(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, _ = AccountNumber()
+		_, _ = AccountNumber("")
 	}
 }
 
 func BenchmarkGenerateAccountNumberWithPrefix(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, _ = AccountNumber("45")
+		_, _ = AccountNumber("", "45")
 	}
 }
+
+func TestGenerateAccountNumberWithProfile(t *testing.T) {
+	t.Run("IBAN-like Profile Uses Mod-97", func(t *testing.T) {
+		profile, ok := GetAccountNumberProfile(AccountNumberProfileIBANLike)
+		assert.True(t, ok)
+
+		for i := 0; i < 50; i++ {
+			accountNumber, err := AccountNumber(AccountNumberProfileIBANLike)
+			assert.NoError(t, err)
+			assert.Len(t, accountNumber, 18)
+			assert.True(t, validator.AccountNumber(accountNumber, profile), "Should validate: %s", accountNumber)
+		}
+	})
+
+	t.Run("Registered Profile With Prefix Pool", func(t *testing.T) {
+		err := RegisterAccountNumberProfile(models.AccountNumberProfile{
+			Name:       "test-branch",
+			Length:     12,
+			Prefixes:   []string{"10", "20"},
+			CheckDigit: models.CheckDigitLuhn,
+		})
+		assert.NoError(t, err)
+
+		profile, _ := GetAccountNumberProfile("test-branch")
+		for i := 0; i < 20; i++ {
+			accountNumber, err := AccountNumber("test-branch")
+			assert.NoError(t, err)
+			assert.Len(t, accountNumber, 12)
+			assert.Contains(t, []string{"10", "20"}, accountNumber[:2])
+			assert.True(t, validator.AccountNumber(accountNumber, profile))
+		}
+
+		// Prefix outside the pool is rejected by the profile-aware validator
+		assert.False(t, validator.AccountNumber("300000000000", profile))
+	})
+
+	t.Run("Unknown Profile", func(t *testing.T) {
+		_, err := AccountNumber("does-not-exist")
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid Profiles", func(t *testing.T) {
+		assert.Error(t, RegisterAccountNumberProfile(models.AccountNumberProfile{Name: "", Length: 10, CheckDigit: models.CheckDigitLuhn}))
+		assert.Error(t, RegisterAccountNumberProfile(models.AccountNumberProfile{Name: "x", Length: 10, CheckDigit: "crc32"}))
+		assert.Error(t, RegisterAccountNumberProfile(models.AccountNumberProfile{Name: "x", Length: 2, CheckDigit: models.CheckDigitMod97}))
+		assert.Error(t, RegisterAccountNumberProfile(models.AccountNumberProfile{Name: "x", Length: 10, Prefixes: []string{"1a"}, CheckDigit: models.CheckDigitLuhn}))
+	})
+
+	t.Run("Mod-97 Check Digits", func(t *testing.T) {
+		// 3214282912345698765432161182 is the numeric form of the IBAN example GB82WEST12345698765432
+		digits := []int{3, 2, 1, 4, 2, 8, 2, 9, 1, 2, 3, 4, 5, 6, 9, 8, 7, 6, 5, 4, 3, 2, 1, 6, 1, 1}
+		assert.Equal(t, 82, calculateMod97CheckDigits(digits))
+	})
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"go-echo-boilerplate/internal/pkg/hashc"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -19,49 +20,78 @@ const (
 	// Warning: Higher costs significantly increase computation time
 	MaxCost = bcrypt.MaxCost // 31
 
-	// RecommendedCost for production environments (2024+)
-	// Adjust based on your security requirements and server capacity
-	RecommendedCost = 12
+	// RecommendedCost for production environments (2024+), used when no configuration is given.
+	// Adjust hash.cost in config based on your security requirements and server capacity
+	RecommendedCost = hashc.DefaultCost
 )
 
-// Hash is a package-level convenience function that hashes a password using the default hasher.
-// This allows you to hash passwords without creating a hasher instance first.
+// Hash hashes a password with bcrypt using the configured cost.
+// When a pepper is configured, the password is peppered first and the hash is
+// prefixed with the pepper version (see hashc.Encode).
+// A nil config uses RecommendedCost without a pepper.
 //
 // Example:
 //
-//	hash, err := generator.Hash("MyPassword123")
+//	hash, err := generator.Hash("MyPassword123", hashConfig)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
-//
-// For custom cost factors, use NewBcryptHasherWithCost instead.
-func Hash(password string) (string, error) {
-	// Generate hash with embedded salt using recommended cost
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), RecommendedCost)
+func Hash(password string, config *hashc.Configuration) (string, error) {
+	version := pepperVersion(config)
+	if version != 0 {
+		peppered, err := config.Pepper(password, version)
+		if err != nil {
+			return "", fmt.Errorf("failed to pepper password: %w", err)
+		}
+		password = peppered
+	}
+
+	// Generate hash with embedded salt
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), cost(config))
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	return string(hashedBytes), nil
+	return hashc.Encode(version, string(hashedBytes)), nil
 }
 
-// NeedsRehash is a package-level convenience function that checks if a hash needs regeneration.
-// This allows you to check rehash status without creating a hasher instance first.
+// NeedsRehash checks if a hash was created with a different cost or pepper version than
+// the configured ones, so it can be upgraded the next time the plain password is available.
+// This is how pepper rotation migrates users to the current version.
+// A nil config compares against RecommendedCost.
 //
 // Example:
 //
-//	if generator.NeedsRehash(storedHash) {
-//	    newHash, _ := generator.Hash(password)
+//	if generator.NeedsRehash(storedHash, hashConfig) {
+//	    newHash, _ := generator.Hash(password, hashConfig)
 //	    // Update database with newHash
 //	}
-func NeedsRehash(hashedPassword string) bool {
+func NeedsRehash(hashedPassword string, config *hashc.Configuration) bool {
+	version, hash := hashc.Decode(hashedPassword)
+	if version != pepperVersion(config) {
+		return true
+	}
+
 	// Extract cost from the hash
-	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	hashCost, err := bcrypt.Cost([]byte(hash))
 	if err != nil {
 		// If we can't determine the cost, assume it needs rehashing
 		return true
 	}
 
-	// Check if the cost matches the recommended cost
-	return cost != RecommendedCost
+	return hashCost != cost(config)
+}
+
+func cost(config *hashc.Configuration) int {
+	if config == nil || config.Cost == 0 {
+		return RecommendedCost
+	}
+	return config.Cost
+}
+
+func pepperVersion(config *hashc.Configuration) int {
+	if config == nil {
+		return 0
+	}
+	return config.PepperVersion
 }
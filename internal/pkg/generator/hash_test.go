@@ -1,9 +1,12 @@
 package generator_test
 
 import (
+	"strings"
 	"testing"
 
 	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/hashc"
+	"go-echo-boilerplate/internal/pkg/validator"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -12,7 +15,7 @@ func TestHash(t *testing.T) {
 	password := "SecretPassword123!"
 
 	// Test Hashing
-	hashedPassword, err := generator.Hash(password)
+	hashedPassword, err := generator.Hash(password, nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, hashedPassword)
 	assert.NotEqual(t, password, hashedPassword)
@@ -24,20 +27,53 @@ func TestHash(t *testing.T) {
 
 func TestNeedsRehash(t *testing.T) {
 	password := "SecretPassword123!"
+	lowCost := &hashc.Configuration{Cost: generator.MinCost}
 
-	// Create a hash with the compiled-in RecommendedCost
-	// Since we can't easily generate a wrong-cost hash without the internal constants or a custom hasher,
-	// we'll test the happy path primarily.
-	// To fully test NeedsRehash returning true, we'd need to mock or manually create a hash with a different cost.
+	hashedPassword, err := generator.Hash(password, lowCost)
+	assert.NoError(t, err)
+
+	// Should not need rehash while the configured cost is unchanged
+	assert.False(t, generator.NeedsRehash(hashedPassword, lowCost), "Newly hashed password should not need rehash")
+
+	// Raising the configured cost flags existing hashes for upgrade
+	assert.True(t, generator.NeedsRehash(hashedPassword, &hashc.Configuration{Cost: generator.MinCost + 1}))
+
+	// A nil config compares against RecommendedCost
+	assert.True(t, generator.NeedsRehash(hashedPassword, nil))
+
+	// Unparseable hashes always need rehashing
+	assert.True(t, generator.NeedsRehash("not-a-bcrypt-hash", lowCost))
+}
+
+func TestHashPepperRotation(t *testing.T) {
+	password := "SecretPassword123!"
+	peppers := map[int][]byte{1: []byte("old-secret"), 2: []byte("new-secret")}
+	v1 := &hashc.Configuration{Cost: generator.MinCost, PepperVersion: 1, Peppers: peppers}
+	v2 := &hashc.Configuration{Cost: generator.MinCost, PepperVersion: 2, Peppers: peppers}
+
+	hashedPassword, err := generator.Hash(password, v1)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hashedPassword, "$p1$$2a$"))
 
-	hashedPassword, err := generator.Hash(password)
+	// After rotating to v2, v1 hashes still verify but are flagged for rehash
+	match, err := validator.Hash(password, hashedPassword, v2)
 	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, generator.NeedsRehash(hashedPassword, v2))
 
-	// Should not need rehash immediately
-	needsRehash := generator.NeedsRehash(hashedPassword)
-	assert.False(t, needsRehash, "Newly hashed password should not need rehash")
+	match, err = validator.Hash("wrong", hashedPassword, v2)
+	assert.NoError(t, err)
+	assert.False(t, match)
 
-	// Create a known hash with a lower cost (MinCost is 4)
-	// We can't import generator.MinCost because it might change, but we know standard bcrypt min is 4.
-	// But to avoid magic strings, we'll just trust the happy path for now or use a known legacy hash if we had one.
+	// Without the pepper the hash can't be verified
+	_, err = validator.Hash(password, hashedPassword, nil)
+	assert.Error(t, err)
+
+	// Legacy unpeppered hashes keep working and get migrated
+	legacy, err := generator.Hash(password, &hashc.Configuration{Cost: generator.MinCost})
+	assert.NoError(t, err)
+	match, err = validator.Hash(password, legacy, v2)
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, generator.NeedsRehash(legacy, v2))
 }
@@ -10,16 +10,10 @@ import (
 )
 
 // GenerateAccessToken generates a JWT access token for the given user
+// The configuration is required, build it with jwtc.FromConfig
 func AccessToken(user *models.User, config *jwtc.Configuration) (*models.Token, error) {
-	// Use default config if none provided
-	if config == nil {
-		config = &jwtc.Configuration{
-			AccessTokenSecret:    "default-secret-key-change-in-production",
-			AccessTokenDuration:  15 * time.Minute,
-			RefreshTokenSecret:   "default-secret-key-change-in-production",
-			RefreshTokenDuration: 7 * 24 * time.Hour,
-			Issuer:               "default-issuer",
-		}
+	if config == nil || config.AccessTokenSecret == "" {
+		return nil, fmt.Errorf("jwt configuration with an access token secret is required")
 	}
 
 	now := time.Now()
@@ -63,15 +57,8 @@ func AccessToken(user *models.User, config *jwtc.Configuration) (*models.Token,
 // GenerateRefreshToken generates a JWT refresh token for the given user
 // Refresh tokens are long-lived (default: 7 days) and used to obtain new access tokens
 func RefreshToken(user *models.User, config *jwtc.Configuration) (*models.Token, error) {
-	// Use default config if none provided
-	if config == nil {
-		config = &jwtc.Configuration{
-			AccessTokenSecret:    "default-secret-key-change-in-production",
-			AccessTokenDuration:  15 * time.Minute,
-			RefreshTokenSecret:   "default-secret-key-change-in-production",
-			RefreshTokenDuration: 7 * 24 * time.Hour,
-			Issuer:               "default-issuer",
-		}
+	if config == nil || config.RefreshTokenSecret == "" {
+		return nil, fmt.Errorf("jwt configuration with a refresh token secret is required")
 	}
 
 	now := time.Now()
@@ -46,10 +46,10 @@ func TestGenerateAccessToken(t *testing.T) {
 		assert.Equal(t, config.Issuer, claims.Issuer)
 	})
 
-	t.Run("Generate With Default Config", func(t *testing.T) {
+	t.Run("Reject Missing Config", func(t *testing.T) {
 		token, err := AccessToken(user, nil)
-		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.Error(t, err)
+		assert.Nil(t, token)
 	})
 
 	t.Run("Token Expiration", func(t *testing.T) {
@@ -106,10 +106,10 @@ func TestGenerateRefreshToken(t *testing.T) {
 		assert.Empty(t, claims.AccountNumber, "Account number should not be in refresh token")
 	})
 
-	t.Run("Generate With Default Config", func(t *testing.T) {
+	t.Run("Reject Missing Config", func(t *testing.T) {
 		token, err := RefreshToken(user, nil)
-		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.Error(t, err)
+		assert.Nil(t, token)
 	})
 }
 
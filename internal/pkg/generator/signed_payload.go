@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// SignedPayload creates a compact, expiring, HMAC-SHA256 signed token carrying data,
+// for email confirmation links, unsubscribe links, download URLs and the like.
+// Unlike JWTs the token has no header or claims, and nothing is stored server side;
+// verify it with validator.SignedPayload using the same secret.
+//
+// The token is URL safe: base64url(expiry || data) "." base64url(signature).
+// The data is signed, not encrypted, so don't put secrets in it. Prefix data with
+// its purpose (e.g. "unsubscribe:") so a token for one link can't be used for another.
+//
+// Example:
+//
+//	token, err := generator.SignedPayload("unsubscribe:"+user.AccountNumber, 7*24*time.Hour, secret)
+//	link := "https://example.com/unsubscribe?token=" + token
+func SignedPayload(data string, ttl time.Duration, secret string) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("signing secret must not be empty")
+	}
+	if ttl <= 0 {
+		return "", fmt.Errorf("ttl must be positive")
+	}
+
+	payload := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint64(payload, uint64(time.Now().Add(ttl).Unix()))
+	payload = append(payload, data...)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
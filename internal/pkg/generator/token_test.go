@@ -109,3 +109,14 @@ func TestAPIKey(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 64, len(decoded))
 }
+
+func TestCode(t *testing.T) {
+	code, err := generator.Code(6)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9]{6}$`, code)
+
+	_, err = generator.Code(3)
+	assert.Error(t, err)
+	_, err = generator.Code(19)
+	assert.Error(t, err)
+}
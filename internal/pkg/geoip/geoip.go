@@ -0,0 +1,92 @@
+// Package geoip looks up the country and city of IP addresses in a MaxMind DB file, such
+// as GeoLite2-City.mmdb or GeoIP2-Country.mmdb, with github.com/oschwald/maxminddb-golang.
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/oschwald/maxminddb-golang/v2"
+)
+
+// ErrInvalidDatabase is returned for content that is not a readable MaxMind DB
+var ErrInvalidDatabase = errors.New("geoip: invalid MaxMind DB")
+
+// Location is the place of an IP address. Fields the database doesn't have are empty.
+type Location struct {
+	CountryCode string // ISO 3166-1 alpha-2, e.g. "ID"
+	Country     string // English name, e.g. "Indonesia"
+	City        string // English name, e.g. "Jakarta"
+}
+
+// record has the fields of a GeoIP2 City or Country record that Location keeps
+type record struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// Reader looks up IP addresses in a MaxMind DB. It is safe for concurrent use.
+type Reader struct {
+	db *maxminddb.Reader
+}
+
+// Open opens a MaxMind DB file, memory-mapped until Close.
+func Open(path string) (*Reader, error) {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: %w", err)
+	}
+	return &Reader{db: db}, nil
+}
+
+// New reads a MaxMind DB from its content.
+func New(content []byte) (*Reader, error) {
+	db, err := maxminddb.OpenBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+	}
+	return &Reader{db: db}, nil
+}
+
+// Close releases the database; lookups fail afterwards.
+func (r *Reader) Close() error {
+	return r.db.Close()
+}
+
+// DatabaseType returns the type of the database, e.g. "GeoLite2-City".
+func (r *Reader) DatabaseType() string {
+	return r.db.Metadata.DatabaseType
+}
+
+// Lookup returns the location of ip, and false when the database has no entry for it
+// (e.g. private addresses, or IPv6 ones in an IPv4 database).
+func (r *Reader) Lookup(ip netip.Addr) (Location, bool, error) {
+	ip = ip.Unmap()
+	if !ip.IsValid() || (ip.Is6() && r.db.Metadata.IPVersion == 4) {
+		return Location{}, false, nil
+	}
+
+	result := r.db.Lookup(ip)
+	if err := result.Err(); err != nil {
+		return Location{}, false, fmt.Errorf("geoip: %w", err)
+	}
+	if !result.Found() {
+		return Location{}, false, nil
+	}
+
+	var found record
+	if err := result.Decode(&found); err != nil {
+		return Location{}, false, fmt.Errorf("geoip: %w", err)
+	}
+	return Location{
+		CountryCode: found.Country.ISOCode,
+		Country:     found.Country.Names["en"],
+		City:        found.City.Names["en"],
+	}, true, nil
+}
@@ -0,0 +1,116 @@
+package geoip_test
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/geoip"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cityRecord is a GeoIP2-City record with English names only
+func cityRecord(isoCode, country, city string) mmdbtype.Map {
+	return mmdbtype.Map{
+		"country": mmdbtype.Map{"iso_code": mmdbtype.String(isoCode), "names": mmdbtype.Map{"en": mmdbtype.String(country)}},
+		"city":    mmdbtype.Map{"names": mmdbtype.Map{"en": mmdbtype.String(city)}},
+	}
+}
+
+// buildDatabase writes a GeoIP2-City database of networks; an IPv4 database skips the IPv6 ones
+func buildDatabase(t *testing.T, ipVersion, recordSize int, networks map[string]mmdbtype.Map) []byte {
+	t.Helper()
+	tree, err := mmdbwriter.New(mmdbwriter.Options{
+		DatabaseType:            "GeoIP2-City",
+		IPVersion:               ipVersion,
+		RecordSize:              recordSize,
+		IncludeReservedNetworks: true,
+	})
+	require.NoError(t, err)
+
+	for cidr, value := range networks {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		if ipVersion == 4 && network.IP.To4() == nil {
+			continue
+		}
+		require.NoError(t, tree.Insert(network, value))
+	}
+
+	var buf bytes.Buffer
+	_, err = tree.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestReaderLookup(t *testing.T) {
+	networks := map[string]mmdbtype.Map{
+		"81.2.69.0/24":  cityRecord("GB", "United Kingdom", "London"),
+		"103.10.0.0/16": cityRecord("ID", "Indonesia", "Jakarta"),
+		"2001:db8::/32": {"country": mmdbtype.Map{"iso_code": mmdbtype.String("JP"), "names": mmdbtype.Map{"en": mmdbtype.String("Japan")}}},
+	}
+
+	for _, ipVersion := range []int{4, 6} {
+		for _, recordSize := range []int{24, 28, 32} {
+			t.Run(fmt.Sprintf("IPv%d Record Size %d", ipVersion, recordSize), func(t *testing.T) {
+				reader, err := geoip.New(buildDatabase(t, ipVersion, recordSize, networks))
+				require.NoError(t, err)
+				assert.Equal(t, "GeoIP2-City", reader.DatabaseType())
+
+				location, found, err := reader.Lookup(netip.MustParseAddr("81.2.69.142"))
+				require.NoError(t, err)
+				assert.True(t, found)
+				assert.Equal(t, geoip.Location{CountryCode: "GB", Country: "United Kingdom", City: "London"}, location)
+
+				location, found, err = reader.Lookup(netip.MustParseAddr("::ffff:103.10.20.30"))
+				require.NoError(t, err)
+				assert.True(t, found, "IPv4-mapped addresses are looked up as IPv4")
+				assert.Equal(t, "Jakarta", location.City)
+
+				_, found, err = reader.Lookup(netip.MustParseAddr("10.0.0.1"))
+				require.NoError(t, err)
+				assert.False(t, found)
+
+				location, found, err = reader.Lookup(netip.MustParseAddr("2001:db8::1"))
+				require.NoError(t, err)
+				if ipVersion == 4 {
+					assert.False(t, found, "an IPv4 database has no IPv6 entries")
+					return
+				}
+				assert.True(t, found)
+				assert.Equal(t, geoip.Location{CountryCode: "JP", Country: "Japan"}, location)
+			})
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	require.NoError(t, os.WriteFile(path, buildDatabase(t, 6, 24, map[string]mmdbtype.Map{
+		"81.2.69.0/24": cityRecord("GB", "United Kingdom", "London"),
+	}), 0o600))
+
+	reader, err := geoip.Open(path)
+	require.NoError(t, err)
+	location, found, err := reader.Lookup(netip.MustParseAddr("81.2.69.1"))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "GB", location.CountryCode)
+	assert.NoError(t, reader.Close())
+
+	_, err = geoip.Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
+
+func TestNewInvalidDatabase(t *testing.T) {
+	_, err := geoip.New([]byte("not a database"))
+	assert.ErrorIs(t, err, geoip.ErrInvalidDatabase)
+}
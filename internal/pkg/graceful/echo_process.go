@@ -4,32 +4,96 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sync"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/net/http2"
 )
 
 // EchoProcess wraps an Echo server to implement the Process interface.
 type EchoProcess struct {
 	server *echo.Echo
 	addr   string
+	h2c    *http2.Server
+
+	certFile, keyFile string
+	autoTLS           bool
+	redirectAddr      string
+}
+
+// EchoOption is a functional option for configuring an EchoProcess.
+type EchoOption func(*EchoProcess)
+
+// WithH2C serves HTTP/2 over cleartext (h2c) next to HTTP/1.1, with the given HTTP/2 settings.
+// A nil server keeps plain HTTP/1.1.
+func WithH2C(h2s *http2.Server) EchoOption {
+	return func(p *EchoProcess) {
+		p.h2c = h2s
+	}
+}
+
+// WithTLS serves HTTPS with the given PEM certificate and key files.
+func WithTLS(certFile, keyFile string) EchoOption {
+	return func(p *EchoProcess) {
+		p.certFile = certFile
+		p.keyFile = keyFile
+	}
+}
+
+// WithAutoTLS serves HTTPS with certificates from the server's AutoTLSManager (Let's Encrypt).
+func WithAutoTLS() EchoOption {
+	return func(p *EchoProcess) {
+		p.autoTLS = true
+	}
+}
+
+// WithHTTPRedirect also serves plain HTTP on addr with the same handler, which is expected
+// to redirect to HTTPS (see middleware.HTTPSRedirectMiddleware). Only used with TLS.
+func WithHTTPRedirect(addr string) EchoOption {
+	return func(p *EchoProcess) {
+		p.redirectAddr = addr
+	}
 }
 
 // NewEchoProcess creates a new Echo process wrapper.
-func NewEchoProcess(server *echo.Echo, addr string) *EchoProcess {
-	return &EchoProcess{
+func NewEchoProcess(server *echo.Echo, addr string, opts ...EchoOption) *EchoProcess {
+	p := &EchoProcess{
 		server: server,
 		addr:   addr,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-// Start starts the Echo server and blocks until it stops or context is cancelled.
+// Start starts the Echo server and blocks until it stops or context is cancelled. It listens
+// with Listen, so its listeners are handed over on a restart (see WithRestart).
 func (p *EchoProcess) Start(ctx context.Context) error {
-	errChan := make(chan error, 1)
+	if err := p.listen(); err != nil {
+		return err
+	}
+
+	errChan := make(chan error, 2)
+	var wg sync.WaitGroup
+
+	serve := func(start func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errChan <- err
+			}
+		}()
+	}
+
+	serve(p.start)
+	if p.redirectAddr != "" && p.tls() {
+		serve(func() error { return p.server.Start(p.redirectAddr) })
+	}
 
 	go func() {
-		if err := p.server.Start(p.addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			errChan <- err
-		}
+		wg.Wait()
 		close(errChan)
 	}()
 
@@ -45,6 +109,47 @@ func (p *EchoProcess) Start(ctx context.Context) error {
 	}
 }
 
+// start serves HTTPS when TLS is configured (HTTP/2 is negotiated there), otherwise h2c or HTTP/1.1
+func (p *EchoProcess) start() error {
+	switch {
+	case p.autoTLS:
+		return p.server.StartAutoTLS(p.addr)
+	case p.certFile != "":
+		return p.server.StartTLS(p.addr, p.certFile, p.keyFile)
+	case p.h2c != nil:
+		return p.server.StartH2CServer(p.addr, p.h2c)
+	default:
+		return p.server.Start(p.addr)
+	}
+}
+
+// listen sets the listeners of the server, which echo only creates when they are unset
+func (p *EchoProcess) listen() error {
+	ln, err := Listen(p.addr)
+	if err != nil {
+		return err
+	}
+	if !p.tls() {
+		p.server.Listener = ln
+		return nil
+	}
+	p.server.TLSListener = &tlsListener{Listener: ln, server: p.server.TLSServer}
+
+	if p.redirectAddr != "" {
+		redirect, err := Listen(p.redirectAddr)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		p.server.Listener = redirect
+	}
+	return nil
+}
+
+func (p *EchoProcess) tls() bool {
+	return p.autoTLS || p.certFile != ""
+}
+
 // Stop gracefully shuts down the Echo server.
 func (p *EchoProcess) Stop(ctx context.Context) error {
 	return p.server.Shutdown(ctx)
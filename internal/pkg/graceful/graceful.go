@@ -2,7 +2,10 @@ package graceful
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
 	"time"
@@ -13,10 +16,18 @@ import (
 // Graceful manages the lifecycle of multiple processes with graceful startup and shutdown.
 // It handles signal catching, concurrent process management, and proper cleanup.
 //
-// Processes are started concurrently and must all start successfully before the startup hook runs.
-// On receiving a termination signal (SIGINT/SIGTERM), all processes are stopped concurrently
-// within the configured timeout.
-func Graceful(processes map[string]Process, opts ...Option) {
+// The readiness checks (WithReadinessCheck) must all pass before any process starts.
+// Processes are started concurrently, then the startup hooks run in order; a failing hook or
+// process aborts the startup. Processes with a restart policy (WithRestartPolicy) are
+// restarted when they crash instead.
+// On receiving a termination signal (SIGINT/SIGTERM), the shutdown hooks run in order, then
+// all processes are stopped concurrently, all within the configured timeout. With
+// WithRestart, SIGHUP hands the listeners over to a new process, then stops this one the
+// same way.
+//
+// It returns the errors that aborted the startup or occurred on shutdown, nil after a clean
+// shutdown.
+func Graceful(processes map[string]Process, opts ...Option) error {
 	o := defaultOptions()
 	for _, opt := range opts {
 		opt(&o)
@@ -30,39 +41,55 @@ func Graceful(processes map[string]Process, opts ...Option) {
 	)
 	defer cancel()
 
+	// Wait for the dependencies, before any process accepts work
+	if err := awaitReadiness(ctx, o); err != nil {
+		o.logger.Errorf("error awaiting readiness: %v", err)
+		return err // Exit immediately, nothing was started
+	}
+
 	// Start all processes concurrently
-	startgroup, ctx := errgroup.WithContext(ctx)
+	startgroup, startCtx := errgroup.WithContext(ctx)
 
 	for name, process := range processes {
 		name, process := name, process // Capture loop variables
 		startgroup.Go(func() error {
 			o.logger.Infof("starting %s", name)
-			if err := process.Start(ctx); err != nil {
+			if policy, ok := o.restartPolicies[process]; ok {
+				return supervise(startCtx, name, process, policy, o.logger)
+			}
+			if err := process.Start(startCtx); err != nil {
 				return fmt.Errorf("failed to start %s: %w", name, err)
 			}
 			return nil
 		})
 	}
 
-	// Wait for all processes to start
-	if err := startgroup.Wait(); err != nil {
-		o.logger.Errorf("error starting processes: %v", err)
-		return // Exit immediately on startup failure
+	// An inherited listener queues the connections whether or not its process accepts them
+	// yet, so the process that restarted this one can stop now
+	notifyParent()
+	if o.restart {
+		go restartOnSIGHUP(ctx, cancel, o)
 	}
 
-	// Execute startup hook only if all processes started successfully
-	if hook := o.startupHook; hook != nil {
-		o.logger.Debugf("executing startup hook")
-		hook()
+	// The processes block while they run, so the startup hooks run once they are launched;
+	// one failing aborts the startup, and so does a process failing to start
+	var errs []error
+	if err := runHooks(startCtx, "startup", o.startupHooks, true, o.logger); err != nil {
+		o.logger.Errorf("error starting up: %v", err)
+		errs = append(errs, err)
+		cancel()
 	}
 
-	// Block until shutdown signal received
-	<-ctx.Done()
+	// Block until shutdown signal received, or a process failed
+	<-startCtx.Done()
+
+	// Create shutdown context with timeout, shared by the shutdown hooks and the processes
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), o.timeout)
+	defer stopCancel()
 
-	// Execute shutdown hook before beginning shutdown
-	if hook := o.shutdownHook; hook != nil {
-		o.logger.Debugf("executing shutdown hook")
-		hook()
+	// Execute shutdown hooks before beginning shutdown
+	if err := runHooks(stopCtx, "shutdown", o.shutdownHooks, false, o.logger); err != nil {
+		errs = append(errs, err)
 	}
 
 	o.logger.Infof("received termination signal, shutting down...")
@@ -85,10 +112,6 @@ func Graceful(processes map[string]Process, opts ...Option) {
 		}
 	}()
 
-	// Create shutdown context with timeout
-	stopCtx, stopCancel := context.WithTimeout(context.Background(), o.timeout)
-	defer stopCancel()
-
 	// Stop all processes concurrently
 	stopgroup, stopCtx := errgroup.WithContext(stopCtx)
 
@@ -107,7 +130,60 @@ func Graceful(processes map[string]Process, opts ...Option) {
 	// Wait for all processes to stop
 	if err := stopgroup.Wait(); err != nil {
 		o.logger.Errorf("error stopping processes: %v", err)
+		errs = append(errs, err)
+	}
+
+	// The processes returned once their context was cancelled, or failed
+	if err := startgroup.Wait(); err != nil {
+		o.logger.Errorf("error running processes: %v", err)
+		errs = append(errs, err)
 	}
 
 	o.logger.Infof("shutdown complete")
+	return errors.Join(errs...)
+}
+
+// runHooks runs hooks in order. A failing startup hook (stopOnError) skips the next ones,
+// while every shutdown hook runs.
+func runHooks(ctx context.Context, phase string, hooks []namedHook, stopOnError bool, logger Logger) error {
+	var errs []error
+	for _, hook := range hooks {
+		logger.Debugf("executing %s hook %s", phase, hook.name)
+		if err := hook.run(ctx); err != nil {
+			err = fmt.Errorf("%s hook %s: %w", phase, hook.name, err)
+			if stopOnError {
+				return err
+			}
+			logger.Errorf("%v", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restartOnSIGHUP restarts the process on SIGHUP until ctx is done, cancelling ctx to shut
+// the current one down once the new one started
+func restartOnSIGHUP(ctx context.Context, cancel context.CancelFunc, o options) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			o.logger.Infof("received SIGHUP, restarting...")
+			cmd := exec.Command(os.Args[0], os.Args[1:]...)
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			pid, err := restart(cmd, o.restartTimeout)
+			if err != nil {
+				o.logger.Errorf("restart failed, still serving: %v", err)
+				continue
+			}
+			o.logger.Infof("restarted as process %d, shutting down", pid)
+			cancel()
+			return
+		}
+	}
 }
@@ -0,0 +1,86 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingProcess runs until ctx is done, recording whether it was stopped
+type blockingProcess struct {
+	stopped atomic.Bool
+}
+
+func (p *blockingProcess) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (p *blockingProcess) Stop(ctx context.Context) error {
+	p.stopped.Store(true)
+	return nil
+}
+
+// failingProcess fails to start
+type failingProcess struct{}
+
+func (p *failingProcess) Start(ctx context.Context) error {
+	return errors.New("address already in use")
+}
+
+func (p *failingProcess) Stop(ctx context.Context) error {
+	return nil
+}
+
+func TestGraceful_Hooks(t *testing.T) {
+	p := &blockingProcess{}
+	var ran []string
+	hook := func(name string, err error) Hook {
+		return func(ctx context.Context) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+	var deadline bool
+
+	err := Graceful(map[string]Process{"server": p},
+		WithStartupHook("migrate", hook("migrate", nil)),
+		WithStartupHook("register", hook("register", errors.New("registry unreachable"))),
+		WithStartupHook("warm-up", hook("warm-up", nil)),
+		WithShutdownHook("deregister", hook("deregister", errors.New("already gone"))),
+		WithShutdownHook("flush", func(ctx context.Context) error {
+			_, deadline = ctx.Deadline()
+			return hook("flush", nil)(ctx)
+		}),
+		WithTimeout(time.Second),
+	)
+
+	assert.Equal(t, []string{"migrate", "register", "deregister", "flush"}, ran, "a failing startup hook skips the next ones, not the shutdown hooks")
+	assert.ErrorContains(t, err, "startup hook register: registry unreachable")
+	assert.ErrorContains(t, err, "shutdown hook deregister: already gone")
+	assert.True(t, deadline, "shutdown hooks have the shutdown timeout")
+	assert.True(t, p.stopped.Load(), "the processes are stopped")
+}
+
+func TestGraceful_ProcessFails(t *testing.T) {
+	p := &blockingProcess{}
+	var shutdown atomic.Bool
+
+	err := Graceful(map[string]Process{
+		"server": p,
+		"broken": &failingProcess{},
+	},
+		WithShutdownHook("log", func(ctx context.Context) error {
+			shutdown.Store(true)
+			return nil
+		}),
+	)
+
+	assert.ErrorContains(t, err, "failed to start broken: address already in use")
+	assert.True(t, shutdown.Load())
+	assert.True(t, p.stopped.Load(), "the other processes are stopped")
+}
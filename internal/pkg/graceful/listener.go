@@ -0,0 +1,225 @@
+package graceful
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// envListeners hands the listeners over to the process started by a restart, as
+// comma-separated addr=fd pairs, e.g. ":8080=3,:80=4"
+const envListeners = "GRACEFUL_LISTENERS"
+
+const (
+	// UnixPrefix prefixes the path of a unix socket address, e.g. "unix:/run/app/http.sock"
+	UnixPrefix = "unix:"
+
+	// Systemd is the address of the first socket passed by systemd socket activation;
+	// "systemd:<name>" selects one by its FileDescriptorName
+	Systemd = "systemd"
+)
+
+// unixSocketMode lets any local user connect to a unix socket, like to a TCP port; the
+// permissions of its directory restrict it
+const unixSocketMode = 0o666
+
+var listeners struct {
+	mu        sync.Mutex
+	once      sync.Once
+	inherited map[string]*os.File     // by address, handed over by the process that restarted this one
+	systemd   []systemdSocket         // passed by systemd socket activation
+	active    map[string]net.Listener // by address, handed over on the next restart
+}
+
+// systemdSocket is a socket passed by systemd with its FileDescriptorName
+type systemdSocket struct {
+	name string
+	file *os.File
+}
+
+// Listen returns a listener on addr: a TCP address (":8080"), a unix socket path prefixed
+// with UnixPrefix, or Systemd for a socket passed by systemd socket activation (LISTEN_FDS).
+// The listener handed over by the process that restarted this one (see WithRestart) on the
+// same addr is reused. The listener is handed over on the next restart, so the connections
+// it queues are accepted by the new process while the current one finishes its in-flight
+// requests.
+func Listen(addr string) (net.Listener, error) {
+	listeners.once.Do(inheritListeners)
+
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	var ln net.Listener
+	if file, ok := listeners.inherited[addr]; ok {
+		delete(listeners.inherited, addr)
+		inherited, err := fileListener(addr, file)
+		if err != nil {
+			return nil, err
+		}
+		ln = inherited
+	} else {
+		created, err := listen(addr)
+		if err != nil {
+			return nil, err
+		}
+		ln = created
+	}
+
+	if listeners.active == nil {
+		listeners.active = map[string]net.Listener{}
+	}
+	listeners.active[addr] = ln
+	return ln, nil
+}
+
+// listen creates the listener of addr
+func listen(addr string) (net.Listener, error) {
+	if addr == Systemd || strings.HasPrefix(addr, Systemd+":") {
+		name := strings.TrimPrefix(strings.TrimPrefix(addr, Systemd), ":")
+		for i, socket := range listeners.systemd {
+			if name == "" || socket.name == name {
+				listeners.systemd = slices.Delete(listeners.systemd, i, i+1)
+				return fileListener(addr, socket.file)
+			}
+		}
+		return nil, fmt.Errorf("no socket %s was passed by systemd (LISTEN_FDS)", addr)
+	}
+
+	path, ok := strings.CutPrefix(addr, UnixPrefix)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+
+	// The socket of a previous run is left behind, see below
+	if info, err := os.Stat(path); err == nil && info.Mode().Type() == os.ModeSocket {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove the previous socket %s: %w", path, err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// Closing it must not remove the path while a restarted process listens on it
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(path, unixSocketMode); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// fileListener returns the listener of an inherited file, closing the file
+func fileListener(addr string, file *os.File) (net.Listener, error) {
+	ln, err := net.FileListener(file)
+	file.Close() // FileListener holds a copy
+	if err != nil {
+		return nil, fmt.Errorf("inherited listener %s: %w", addr, err)
+	}
+	if unix, ok := ln.(*net.UnixListener); ok {
+		unix.SetUnlinkOnClose(false)
+	}
+	return ln, nil
+}
+
+// inheritListeners reads the listeners handed over in envListeners, and the sockets of
+// systemd socket activation. The variables are cleared so the processes this one starts
+// don't see them.
+func inheritListeners() {
+	value := os.Getenv(envListeners)
+	os.Unsetenv(envListeners)
+
+	listeners.inherited = map[string]*os.File{}
+	for pair := range strings.SplitSeq(value, ",") {
+		addr, fd, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(fd); err == nil && n > 2 {
+			listeners.inherited[addr] = os.NewFile(uintptr(n), "listener "+addr)
+		}
+	}
+
+	listeners.systemd = systemdSockets()
+}
+
+// systemdSockets returns the sockets passed by systemd, at fd 3 on, when LISTEN_PID is this
+// process (see sd_listen_fds(3))
+func systemdSockets() []systemdSocket {
+	pid, count, names := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES")
+	for _, variable := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+		os.Unsetenv(variable)
+	}
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return nil
+	}
+
+	fdNames := strings.Split(names, ":")
+	sockets := make([]systemdSocket, n)
+	for i := range sockets {
+		sockets[i].name = "unknown" // systemd's name of an unnamed socket
+		if i < len(fdNames) && fdNames[i] != "" {
+			sockets[i].name = fdNames[i]
+		}
+		sockets[i].file = os.NewFile(uintptr(3+i), "systemd "+sockets[i].name)
+	}
+	return sockets
+}
+
+// handover returns the value of envListeners and the files of the active listeners, the
+// file of each at the fd of its pair once passed in exec.Cmd.ExtraFiles from firstFD. The
+// caller closes the files.
+func handover(firstFD int) (string, []*os.File, error) {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	addrs := make([]string, 0, len(listeners.active))
+	for addr := range listeners.active {
+		addrs = append(addrs, addr)
+	}
+	slices.Sort(addrs)
+
+	pairs := make([]string, 0, len(addrs))
+	files := make([]*os.File, 0, len(addrs))
+	for _, addr := range addrs {
+		ln, ok := listeners.active[addr].(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+		file, err := ln.File()
+		if err != nil {
+			for _, file := range files {
+				file.Close()
+			}
+			return "", nil, fmt.Errorf("listener %s: %w", addr, err)
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%d", addr, firstFD+len(files)))
+		files = append(files, file)
+	}
+	return strings.Join(pairs, ","), files, nil
+}
+
+// tlsListener accepts TLS connections with the config of server, read on accept since echo
+// sets it when the server starts
+type tlsListener struct {
+	net.Listener
+	server *http.Server
+}
+
+func (l *tlsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return tls.Server(conn, l.server.TLSConfig), nil
+}
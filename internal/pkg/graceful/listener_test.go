@@ -0,0 +1,84 @@
+package graceful
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_Unix(t *testing.T) {
+	resetListeners()
+	t.Cleanup(func() { resetListeners() })
+
+	path := filepath.Join(t.TempDir(), "http.sock")
+	ln, err := Listen(UnixPrefix + path)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSocket, info.Mode().Type())
+	assert.Equal(t, os.FileMode(unixSocketMode), info.Mode().Perm())
+
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Write([]byte("unix"))
+			conn.Close()
+		}
+	}()
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	buf := make([]byte, 8)
+	n, _ := conn.Read(buf)
+	conn.Close()
+	assert.Equal(t, "unix", string(buf[:n]))
+
+	t.Run("Kept On Close For The Restarted Process", func(t *testing.T) {
+		require.NoError(t, ln.Close())
+		_, err := os.Stat(path)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Socket Of A Previous Run Replaced", func(t *testing.T) {
+		resetListeners()
+		ln, err := Listen(UnixPrefix + path)
+		require.NoError(t, err)
+		assert.NoError(t, ln.Close())
+	})
+
+	t.Run("Other File Kept", func(t *testing.T) {
+		resetListeners()
+		file := filepath.Join(t.TempDir(), "data")
+		require.NoError(t, os.WriteFile(file, []byte("data"), 0o600))
+		_, err := Listen(UnixPrefix + file)
+		assert.Error(t, err)
+	})
+}
+
+func TestListen_Systemd(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	file, err := ln.(*net.TCPListener).File()
+	require.NoError(t, err)
+	defer file.Close()
+
+	// The socket is at fd 3, as systemd passes it
+	cmd := helperCommand("systemd")
+	cmd.Env = append(cmd.Env, "LISTEN_FDS=1", "LISTEN_FDNAMES=http")
+	cmd.ExtraFiles = []*os.File{file}
+	require.NoError(t, cmd.Start())
+	defer cmd.Wait()
+
+	require.NoError(t, ln.Close())
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	buf := make([]byte, 32)
+	n, _ := conn.Read(buf)
+	assert.Equal(t, "new process", string(buf[:n]))
+}
@@ -1,46 +1,77 @@
 package graceful
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 const (
 	// DefaultTimeout is the default graceful shutdown timeout.
 	DefaultTimeout = 10 * time.Second
+
+	// DefaultReadinessTimeout is how long the readiness checks may take to pass by default.
+	DefaultReadinessTimeout = 30 * time.Second
+
+	// DefaultRestartTimeout is how long a restart waits for the new process to start by default.
+	DefaultRestartTimeout = time.Minute
+
+	// readinessInterval separates the attempts of a failing readiness check.
+	readinessInterval = time.Second
 )
 
+// Hook runs at a phase of the lifecycle, see WithStartupHook and WithShutdownHook.
+type Hook func(ctx context.Context) error
+
+// namedHook is a hook added with WithStartupHook or WithShutdownHook
+type namedHook struct {
+	name string
+	run  Hook
+}
+
 // options holds configuration for graceful shutdown behavior.
 type options struct {
-	startupHook  func()
-	shutdownHook func()
-	timeout      time.Duration
-	logger       Logger
+	startupHooks      []namedHook
+	shutdownHooks     []namedHook
+	timeout           time.Duration
+	logger            Logger
+	readinessChecks   []readinessCheck
+	readinessTimeout  time.Duration
+	readinessInterval time.Duration
+	restart           bool
+	restartTimeout    time.Duration
+	restartPolicies   map[Process]restartPolicy
 }
 
 // defaultOptions returns the default options configuration.
 func defaultOptions() options {
 	return options{
-		startupHook:  func() {},
-		shutdownHook: func() {},
-		timeout:      DefaultTimeout,
-		logger:       &noopLogger{},
+		timeout:           DefaultTimeout,
+		logger:            &noopLogger{},
+		readinessTimeout:  DefaultReadinessTimeout,
+		readinessInterval: readinessInterval,
 	}
 }
 
 // Option is a functional option for configuring graceful shutdown.
 type Option func(*options)
 
-// WithStartupHook sets a hook function to be called after all processes have started successfully.
-// This is useful for tasks like registering with service discovery or sending startup metrics.
-func WithStartupHook(hook func()) Option {
+// WithStartupHook adds a hook run once the processes are started, after the startup hooks
+// added before it, e.g. to register with service discovery. Its context is cancelled on a
+// termination signal. An error aborts the startup: the next hooks are skipped, and the
+// shutdown hooks run and the processes stop as on a termination signal.
+func WithStartupHook(name string, hook Hook) Option {
 	return func(o *options) {
-		o.startupHook = hook
+		o.startupHooks = append(o.startupHooks, namedHook{name: name, run: hook})
 	}
 }
 
-// WithShutdownHook sets a hook function to be called before shutdown begins.
-// This is useful for tasks like deregistering from service discovery or sending shutdown metrics.
-func WithShutdownHook(hook func()) Option {
+// WithShutdownHook adds a hook run before the processes stop, after the shutdown hooks added
+// before it, e.g. to deregister from service discovery. Its context expires with the shutdown
+// timeout, which the processes share. An error doesn't stop the shutdown: the next hooks
+// still run, and Graceful returns it.
+func WithShutdownHook(name string, hook Hook) Option {
 	return func(o *options) {
-		o.shutdownHook = hook
+		o.shutdownHooks = append(o.shutdownHooks, namedHook{name: name, run: hook})
 	}
 }
 
@@ -58,3 +89,51 @@ func WithLogger(logger Logger) Option {
 		o.logger = logger
 	}
 }
+
+// WithReadinessCheck adds a check that must pass before any process starts, so the HTTP
+// listener only accepts traffic and the startup hook only fires once the dependencies are
+// ready, e.g. the database reachable and migrated. A failing check is retried every second
+// until the readiness timeout.
+func WithReadinessCheck(name string, check func(ctx context.Context) error) Option {
+	return func(o *options) {
+		o.readinessChecks = append(o.readinessChecks, readinessCheck{name: name, check: check})
+	}
+}
+
+// WithReadinessTimeout sets how long the readiness checks may take to pass; past it, the
+// processes are not started.
+func WithReadinessTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readinessTimeout = timeout
+	}
+}
+
+// WithRestart restarts the process on SIGHUP without dropping a connection, e.g. to run a new
+// binary: a new process of the same executable and arguments is started with the listeners
+// of Listen, and once it started the current one shuts down, finishing its in-flight
+// requests while the new one accepts the connections. When the new process exits or doesn't
+// start within timeout (DefaultRestartTimeout when 0), it is killed and the current one keeps
+// serving. Unix only; the supervisor must not track the pid, as it changes.
+func WithRestart(timeout time.Duration) Option {
+	return func(o *options) {
+		o.restart = true
+		o.restartTimeout = timeout
+		if timeout <= 0 {
+			o.restartTimeout = DefaultRestartTimeout
+		}
+	}
+}
+
+// WithRestartPolicy restarts process, one of the processes passed to Graceful (compared by
+// pointer), when it crashes instead of taking the others down: its Start returns an error or
+// panics, or returns before shutdown. The restarts wait backoff, doubled after each
+// consecutive crash up to a minute. Past maxRestarts consecutive crashes (0 for no limit),
+// its error ends Graceful, as without a policy.
+func WithRestartPolicy(process Process, backoff time.Duration, maxRestarts int) Option {
+	return func(o *options) {
+		if o.restartPolicies == nil {
+			o.restartPolicies = map[Process]restartPolicy{}
+		}
+		o.restartPolicies[process] = restartPolicy{backoff: backoff, maxRestarts: maxRestarts}
+	}
+}
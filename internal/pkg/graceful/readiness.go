@@ -0,0 +1,50 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// readinessCheck is a check added with WithReadinessCheck
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// awaitReadiness runs the readiness checks concurrently, retrying each failing one until it
+// passes. It fails with the last error of a check still failing at the readiness timeout,
+// or when ctx is done.
+func awaitReadiness(ctx context.Context, o options) error {
+	if len(o.readinessChecks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.readinessTimeout)
+	defer cancel()
+
+	group, ctx := errgroup.WithContext(ctx)
+	for _, c := range o.readinessChecks {
+		group.Go(func() error {
+			for attempt := 1; ; attempt++ {
+				err := c.check(ctx)
+				if err == nil {
+					o.logger.Infof("readiness check %s passed", c.name)
+					return nil
+				}
+				if attempt == 1 {
+					o.logger.Infof("waiting for readiness check %s: %v", c.name, err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return fmt.Errorf("readiness check %s did not pass: %w", c.name, err)
+				case <-time.After(o.readinessInterval):
+				}
+			}
+		})
+	}
+	return group.Wait()
+}
@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwaitReadiness(t *testing.T) {
+	o := defaultOptions()
+	o.readinessInterval = time.Millisecond
+	require.NoError(t, awaitReadiness(context.Background(), o), "no checks")
+
+	var attempts atomic.Int32
+	WithReadinessCheck("database", func(ctx context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})(&o)
+	require.NoError(t, awaitReadiness(context.Background(), o))
+	assert.EqualValues(t, 3, attempts.Load(), "retried until it passes")
+
+	WithReadinessCheck("migrations", func(ctx context.Context) error {
+		return errors.New("2 pending")
+	})(&o)
+	WithReadinessTimeout(20 * time.Millisecond)(&o)
+	err := awaitReadiness(context.Background(), o)
+	assert.ErrorContains(t, err, "readiness check migrations did not pass: 2 pending")
+}
+
+// process records whether it was started
+type process struct {
+	started atomic.Bool
+}
+
+func (p *process) Start(ctx context.Context) error {
+	p.started.Store(true)
+	return nil
+}
+
+func (p *process) Stop(ctx context.Context) error {
+	return nil
+}
+
+func TestGraceful_NotReady(t *testing.T) {
+	p := &process{}
+	var hooked atomic.Bool
+
+	err := Graceful(map[string]Process{"server": p},
+		WithReadinessCheck("cache", func(ctx context.Context) error { return errors.New("unreachable") }),
+		WithReadinessTimeout(10*time.Millisecond),
+		WithStartupHook("register", func(ctx context.Context) error {
+			hooked.Store(true)
+			return nil
+		}),
+	)
+
+	assert.ErrorContains(t, err, "readiness check cache did not pass")
+	assert.False(t, p.started.Load(), "nothing starts before the checks pass")
+	assert.False(t, hooked.Load())
+}
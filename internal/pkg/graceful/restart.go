@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReadyFD is the fd the process started by a restart writes to once it started
+const envReadyFD = "GRACEFUL_READY_FD"
+
+// restart starts cmd, a new process of the executable, handing it the listeners of Listen,
+// and waits until it started (see notifyParent), at most timeout. It returns the pid of the
+// new process; on error, the new process is gone and the current one keeps serving.
+func restart(cmd *exec.Cmd, timeout time.Duration) (int, error) {
+	// The listeners are at fd 3 on, after stdin, stdout, and stderr, then the ready pipe
+	pairs, files, err := handover(3)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	ready, notify, err := os.Pipe()
+	if err != nil {
+		return 0, err
+	}
+	defer ready.Close()
+
+	env := slices.DeleteFunc(cmd.Environ(), func(variable string) bool {
+		return strings.HasPrefix(variable, envListeners+"=") || strings.HasPrefix(variable, envReadyFD+"=")
+	})
+	cmd.Env = append(env, envListeners+"="+pairs, envReadyFD+"="+strconv.Itoa(3+len(files)))
+	cmd.ExtraFiles = append(files, notify)
+	err = cmd.Start()
+	notify.Close() // only the new process holds it, so ready reads EOF once it exits
+	if err != nil {
+		return 0, fmt.Errorf("failed to start the new process: %w", err)
+	}
+
+	started := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		started <- err
+	}()
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-started:
+		if err == nil {
+			return cmd.Process.Pid, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("the new process exited before it started: %v", <-exited)
+		}
+		_ = cmd.Process.Kill()
+		return 0, err
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		return 0, fmt.Errorf("the new process did not start within %s", timeout)
+	}
+}
+
+// notifyParent tells the process that restarted this one, if any, that it started, so the
+// former stops
+func notifyParent() {
+	value := os.Getenv(envReadyFD)
+	os.Unsetenv(envReadyFD)
+	fd, err := strconv.Atoi(value)
+	if err != nil || fd < 3 {
+		return
+	}
+
+	file := os.NewFile(uintptr(fd), "ready")
+	_, _ = file.Write([]byte{1})
+	file.Close()
+}
@@ -0,0 +1,94 @@
+package graceful
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHelperProcess is the process started by the restarts of TestRestart, and by
+// TestListen_Systemd as systemd would
+func TestHelperProcess(t *testing.T) {
+	switch mode := os.Getenv("GRACEFUL_HELPER"); mode {
+	case "accept", "systemd":
+		addr := "app"
+		if mode == "systemd" {
+			// systemd sets it to the pid of the process it starts
+			os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+			addr = "systemd:http"
+		}
+		ln, err := Listen(addr)
+		if err != nil {
+			os.Exit(2)
+		}
+		notifyParent()
+		conn, err := ln.Accept()
+		if err != nil {
+			os.Exit(3)
+		}
+		fmt.Fprint(conn, "new process")
+		conn.Close()
+		os.Exit(0)
+	case "fail":
+		os.Exit(1)
+	}
+}
+
+func helperCommand(mode string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "GRACEFUL_HELPER="+mode)
+	return cmd
+}
+
+// resetListeners forgets the listeners of the previous tests
+func resetListeners() {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+	for _, ln := range listeners.active {
+		ln.Close()
+	}
+	listeners.active = nil
+}
+
+func TestRestart(t *testing.T) {
+	resetListeners()
+	ln, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	listeners.mu.Lock()
+	listeners.active = map[string]net.Listener{"app": ln}
+	listeners.mu.Unlock()
+	t.Cleanup(func() { resetListeners() })
+
+	pid, err := restart(helperCommand("accept"), 10*time.Second)
+	require.NoError(t, err)
+	assert.NotEqual(t, os.Getpid(), pid)
+
+	// The current process stops accepting; the new one gets the connection on the same socket
+	require.NoError(t, ln.Close())
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	buf := make([]byte, 32)
+	n, _ := conn.Read(buf)
+	assert.Equal(t, "new process", string(buf[:n]))
+
+	t.Run("New Process Fails", func(t *testing.T) {
+		resetListeners()
+		_, err := restart(helperCommand("fail"), 10*time.Second)
+		assert.ErrorContains(t, err, "exited before it started")
+	})
+
+	t.Run("New Process Too Slow", func(t *testing.T) {
+		resetListeners()
+		cmd := exec.Command("sleep", "10")
+		_, err := restart(cmd, 50*time.Millisecond)
+		assert.ErrorContains(t, err, "did not start within 50ms")
+	})
+}
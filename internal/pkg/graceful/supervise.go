@@ -0,0 +1,62 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// maxRestartDelay caps the delay between two restarts of a process; a process that ran at
+// least that long is considered recovered, its next crash restarting it after backoff again
+const maxRestartDelay = time.Minute
+
+// restartPolicy is the policy of WithRestartPolicy
+type restartPolicy struct {
+	backoff     time.Duration
+	maxRestarts int
+}
+
+// supervise starts process, restarting it with policy when it crashes: Start returns an
+// error or panics, or returns before ctx is done. Past policy.maxRestarts consecutive crashes,
+// it returns the last error.
+func supervise(ctx context.Context, name string, process Process, policy restartPolicy, logger Logger) error {
+	restarts := 0
+	for {
+		started := time.Now()
+		err := startProcess(ctx, process)
+		if ctx.Err() != nil {
+			return nil // shutting down
+		}
+		if err == nil {
+			err = errors.New("stopped")
+		}
+
+		if time.Since(started) >= maxRestartDelay {
+			restarts = 0
+		}
+		if policy.maxRestarts > 0 && restarts >= policy.maxRestarts {
+			return fmt.Errorf("%s crashed %d times in a row: %w", name, restarts+1, err)
+		}
+
+		delay := min(policy.backoff<<min(restarts, 30), maxRestartDelay)
+		restarts++
+		logger.Errorf("%s crashed, restarting in %s (restart %d): %v", name, delay, restarts, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// startProcess runs process.Start, returning a panic as an error
+func startProcess(ctx context.Context, process Process) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return process.Start(ctx)
+}
@@ -0,0 +1,74 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crashingProcess crashes on its first starts, then runs until ctx is done
+type crashingProcess struct {
+	crashes int32
+	starts  atomic.Int32
+}
+
+func (p *crashingProcess) Start(ctx context.Context) error {
+	switch n := p.starts.Add(1); {
+	case n > p.crashes:
+		<-ctx.Done()
+		return nil
+	case n%3 == 0:
+		return nil // stopped before shutdown
+	case n%2 == 0:
+		return errors.New("connection lost")
+	default:
+		panic("nil map")
+	}
+}
+
+func (p *crashingProcess) Stop(ctx context.Context) error {
+	return nil
+}
+
+func TestSupervise(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &crashingProcess{crashes: 3}
+	done := make(chan error, 1)
+	go func() {
+		done <- supervise(ctx, "consumer", p, restartPolicy{backoff: time.Millisecond}, &noopLogger{})
+	}()
+
+	require.Eventually(t, func() bool { return p.starts.Load() == 4 }, time.Second, time.Millisecond, "restarted after each crash")
+	cancel()
+	assert.NoError(t, <-done)
+
+	t.Run("Max Restarts", func(t *testing.T) {
+		p := &crashingProcess{crashes: 10}
+		err := supervise(context.Background(), "consumer", p, restartPolicy{backoff: time.Millisecond, maxRestarts: 2}, &noopLogger{})
+		assert.ErrorContains(t, err, "consumer crashed 3 times in a row: stopped")
+		assert.EqualValues(t, 3, p.starts.Load())
+	})
+}
+
+func TestTickerProcess_Restart(t *testing.T) {
+	var runs atomic.Int32
+	p := NewTickerProcess(time.Millisecond, func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			panic("first run")
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- supervise(context.Background(), "ticker", p, restartPolicy{backoff: time.Millisecond, maxRestarts: 1}, &noopLogger{})
+	}()
+	require.Eventually(t, func() bool { return runs.Load() > 1 }, time.Second, time.Millisecond, "started again after the panic")
+
+	require.NoError(t, p.Stop(context.Background()))
+	assert.ErrorContains(t, <-done, "stopped", "stopped before shutdown")
+}
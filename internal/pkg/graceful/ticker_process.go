@@ -0,0 +1,73 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TickerProcess runs a function every interval to implement the Process interface.
+// This is useful for periodic background jobs like sweeping expired records.
+type TickerProcess struct {
+	interval time.Duration
+	run      func(ctx context.Context)
+
+	stopOnce sync.Once
+	stop     chan struct{}
+
+	mu   sync.Mutex
+	done chan struct{} // of the running Start, nil before the first one
+}
+
+// NewTickerProcess creates a process calling run every interval, first after one interval.
+// run receives the context of Start, which is cancelled on shutdown.
+func NewTickerProcess(interval time.Duration, run func(ctx context.Context)) *TickerProcess {
+	return &TickerProcess{
+		interval: interval,
+		run:      run,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start calls the function every interval, blocking until ctx is cancelled or Stop is called.
+// It may be called again once it returned, e.g. by a restart policy after run panicked.
+func (p *TickerProcess) Start(ctx context.Context) error {
+	done := make(chan struct{})
+	p.mu.Lock()
+	p.done = done
+	p.mu.Unlock()
+	defer close(done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.stop:
+			return nil
+		case <-ticker.C:
+			p.run(ctx)
+		}
+	}
+}
+
+// Stop waits for a run in progress to return, until ctx is done.
+func (p *TickerProcess) Stop(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+
+	p.mu.Lock()
+	done := p.done
+	p.mu.Unlock()
+	if done == nil {
+		return nil // never started, Start returns at once now
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,123 @@
+// hashc (hashcustom) is a custom password hashing helper package
+package hashc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultCost is the bcrypt cost used when hash.cost is not configured
+const DefaultCost = 12
+
+// DefaultPepperEnvPrefix is the environment variable prefix peppers are read from,
+// followed by the version number (HASH_PEPPER_V1, HASH_PEPPER_V2, ...)
+const DefaultPepperEnvPrefix = "HASH_PEPPER_V"
+
+// pepperPrefix marks peppered hashes: "$p<version>$" followed by the bcrypt hash
+const pepperPrefix = "$p"
+
+// Configuration holds the configuration for password hashing
+type Configuration struct {
+	Cost int
+
+	// PepperVersion is the pepper new hashes are created with; 0 disables peppering
+	PepperVersion int
+	// Peppers holds every pepper version that may still appear in stored hashes
+	Peppers map[int][]byte
+}
+
+// DefaultConfig returns the hashing configuration from the application config.
+// An unset cost falls back to DefaultCost; out-of-range costs are clamped to bcrypt's limits.
+//
+// Peppers are read from the environment (populated by your secret manager) for every version
+// from 1 up to hash.pepper.current_version, so retired versions keep verifying until users log in
+// again and get rehashed with the current one.
+func DefaultConfig(config *config.Configuration) *Configuration {
+	cost := DefaultCost
+	if config != nil && config.Hash.Cost != 0 {
+		cost = min(max(config.Hash.Cost, bcrypt.MinCost), bcrypt.MaxCost)
+	}
+
+	configuration := &Configuration{
+		Cost: cost,
+	}
+
+	if config == nil || config.Hash.Pepper.CurrentVersion <= 0 {
+		return configuration
+	}
+
+	envPrefix := config.Hash.Pepper.EnvPrefix
+	if envPrefix == "" {
+		envPrefix = DefaultPepperEnvPrefix
+	}
+
+	configuration.PepperVersion = config.Hash.Pepper.CurrentVersion
+	configuration.Peppers = make(map[int][]byte)
+	for version := 1; version <= config.Hash.Pepper.CurrentVersion; version++ {
+		if pepper := os.Getenv(envPrefix + strconv.Itoa(version)); pepper != "" {
+			configuration.Peppers[version] = []byte(pepper)
+		}
+	}
+
+	return configuration
+}
+
+// Validate reports a missing secret for the current pepper version.
+func (c *Configuration) Validate() error {
+	if c.PepperVersion > 0 && len(c.Peppers[c.PepperVersion]) == 0 {
+		return fmt.Errorf("pepper version %d is not set", c.PepperVersion)
+	}
+	return nil
+}
+
+// Pepper returns HMAC-SHA256(pepper, password), base64 encoded so it stays within
+// bcrypt's 72 byte limit. Version 0 returns the password unchanged.
+func (c *Configuration) Pepper(password string, version int) (string, error) {
+	if version == 0 {
+		return password, nil
+	}
+
+	pepper, ok := c.Peppers[version]
+	if !ok || len(pepper) == 0 {
+		return "", fmt.Errorf("unknown pepper version %d", version)
+	}
+
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Encode prefixes a bcrypt hash with its pepper version. Version 0 returns the hash unchanged.
+func Encode(version int, hash string) string {
+	if version == 0 {
+		return hash
+	}
+	return fmt.Sprintf("%s%d$%s", pepperPrefix, version, hash)
+}
+
+// Decode splits a stored hash into its pepper version (0 when unpeppered) and the bcrypt hash.
+func Decode(stored string) (int, string) {
+	rest, ok := strings.CutPrefix(stored, pepperPrefix)
+	if !ok {
+		return 0, stored
+	}
+
+	version, hash, ok := strings.Cut(rest, "$")
+	if !ok {
+		return 0, stored
+	}
+
+	parsed, err := strconv.Atoi(version)
+	if err != nil || parsed <= 0 {
+		return 0, stored
+	}
+	return parsed, hash
+}
@@ -0,0 +1,77 @@
+package hashc_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/hashc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		cfg := hashc.DefaultConfig(&config.Configuration{})
+
+		assert.Equal(t, hashc.DefaultCost, cfg.Cost)
+		assert.Zero(t, cfg.PepperVersion)
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("Clamps Cost", func(t *testing.T) {
+		assert.Equal(t, 4, hashc.DefaultConfig(&config.Configuration{Hash: config.Hash{Cost: 1}}).Cost)
+		assert.Equal(t, 31, hashc.DefaultConfig(&config.Configuration{Hash: config.Hash{Cost: 99}}).Cost)
+	})
+
+	t.Run("Loads Peppers From Env", func(t *testing.T) {
+		t.Setenv("TEST_PEPPER_V1", "old-secret")
+		t.Setenv("TEST_PEPPER_V2", "new-secret")
+
+		cfg := hashc.DefaultConfig(&config.Configuration{Hash: config.Hash{
+			Pepper: config.Pepper{CurrentVersion: 2, EnvPrefix: "TEST_PEPPER_V"},
+		}})
+
+		assert.Equal(t, 2, cfg.PepperVersion)
+		assert.Equal(t, []byte("old-secret"), cfg.Peppers[1])
+		assert.Equal(t, []byte("new-secret"), cfg.Peppers[2])
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("Missing Current Pepper", func(t *testing.T) {
+		cfg := hashc.DefaultConfig(&config.Configuration{Hash: config.Hash{
+			Pepper: config.Pepper{CurrentVersion: 3, EnvPrefix: "TEST_MISSING_PEPPER_V"},
+		}})
+
+		assert.Error(t, cfg.Validate())
+	})
+}
+
+func TestEncodeDecode(t *testing.T) {
+	bcryptHash := "$2a$12$abcdefghijklmnopqrstuv"
+
+	version, hash := hashc.Decode(hashc.Encode(3, bcryptHash))
+	assert.Equal(t, 3, version)
+	assert.Equal(t, bcryptHash, hash)
+
+	// Unpeppered hashes pass through unchanged
+	assert.Equal(t, bcryptHash, hashc.Encode(0, bcryptHash))
+	version, hash = hashc.Decode(bcryptHash)
+	assert.Zero(t, version)
+	assert.Equal(t, bcryptHash, hash)
+}
+
+func TestPepper(t *testing.T) {
+	cfg := &hashc.Configuration{PepperVersion: 1, Peppers: map[int][]byte{1: []byte("secret")}}
+
+	peppered, err := cfg.Pepper("password", 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "password", peppered)
+	assert.LessOrEqual(t, len(peppered), 72) // bcrypt limit
+
+	_, err = cfg.Pepper("password", 2)
+	assert.Error(t, err)
+
+	unchanged, err := cfg.Pepper("password", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "password", unchanged)
+}
@@ -0,0 +1,183 @@
+// Package httpclient provides an outbound HTTP client with per-attempt timeouts
+// and retries with jittered exponential backoff, for calls to third-party APIs.
+//
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) are retried, on
+// transport errors and on 429/502/503/504 responses. A Retry-After header in
+// seconds is honored, capped at MaxBackoff.
+//
+// Set Propagate to logger.InjectHeaders to send the correlation context of the caller's
+// wide event (request ID, trace ID, user ID, tenant) to your own services.
+//
+// Example:
+//
+//	client := httpclient.New(httpclient.DefaultConfig())
+//	resp, err := client.Get(ctx, "https://api.example.com/items", nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer resp.Body.Close()
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Configuration holds the client timeouts and retry policy.
+type Configuration struct {
+	Timeout        time.Duration // per attempt
+	MaxRetries     int           // retries after the first attempt
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Propagate adds headers derived from the request context before sending, usually
+	// logger.InjectHeaders. Set it only for calls to your own services: it forwards the
+	// user ID and tenant.
+	Propagate func(ctx context.Context, header http.Header)
+}
+
+// DefaultConfig returns a configuration suitable for most third-party APIs.
+func DefaultConfig() *Configuration {
+	return &Configuration{
+		Timeout:        5 * time.Second,
+		MaxRetries:     2,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// Client is a retrying HTTP client. It is safe for concurrent use.
+type Client struct {
+	http   *http.Client
+	config *Configuration
+}
+
+// New creates a client with the given configuration (DefaultConfig when nil).
+func New(config *Configuration) *Client {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Client{
+		http:   &http.Client{Timeout: config.Timeout},
+		config: config,
+	}
+}
+
+// Get issues a GET request with optional headers.
+func (c *Client) Get(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return c.Do(req)
+}
+
+// Do sends the request, retrying idempotent requests on transient failures.
+// The last response or error is returned once retries are exhausted.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.config.Propagate != nil {
+		c.config.Propagate(req.Context(), req.Header)
+	}
+
+	attempts := 1
+	if isIdempotent(req.Method) && (req.Body == nil || req.GetBody != nil) {
+		attempts += c.config.MaxRetries
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if req.GetBody != nil {
+				body, bodyErr := req.GetBody()
+				if bodyErr != nil {
+					return nil, fmt.Errorf("failed to rewind request body: %w", bodyErr)
+				}
+				req.Body = body
+			}
+
+			if waitErr := sleep(req.Context(), c.backoff(attempt, resp)); waitErr != nil {
+				return nil, waitErr
+			}
+		}
+
+		resp, err = c.http.Do(req)
+		if !shouldRetry(resp, err) || attempt == attempts-1 {
+			break
+		}
+
+		// Drain and close the response we are about to discard so the connection can be reused
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+
+	return resp, err
+}
+
+// backoff returns the delay before the given retry attempt, preferring the server's Retry-After.
+func (c *Client) backoff(attempt int, previous *http.Response) time.Duration {
+	if previous != nil {
+		if seconds, err := strconv.Atoi(previous.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.config.MaxBackoff)
+		}
+	}
+
+	delay := c.config.InitialBackoff << (attempt - 1)
+	if delay <= 0 || delay > c.config.MaxBackoff {
+		delay = c.config.MaxBackoff
+	}
+
+	// Full jitter spreads out retries from concurrent callers
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(delay)) + 1)
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
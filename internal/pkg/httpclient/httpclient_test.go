@@ -0,0 +1,121 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() *httpclient.Configuration {
+	return &httpclient.Configuration{
+		Timeout:        time.Second,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := httpclient.New(testConfig()).Get(context.Background(), server.URL, nil)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	resp, err := httpclient.New(testConfig()).Get(context.Background(), server.URL, nil)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_DoesNotRetryNonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+	resp, err := httpclient.New(testConfig()).Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_StopsOnContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.MaxBackoff = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := httpclient.New(config).Get(ctx, server.URL, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_PropagatesCorrelation(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	ctx = logger.WithTenant(logger.WithUserID(ctx, "user-1"), "acme")
+
+	config := testConfig()
+	resp, err := httpclient.New(config).Get(ctx, server.URL, nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, received.Get(logger.HeaderRequestID), "not propagated by default")
+
+	config.Propagate = logger.InjectHeaders
+	resp, err = httpclient.New(config).Get(ctx, server.URL, map[string]string{logger.HeaderTenantID: "override"})
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "req-1", received.Get(logger.HeaderRequestID))
+	assert.Equal(t, "user-1", received.Get(logger.HeaderUserID))
+	assert.Equal(t, "override", received.Get(logger.HeaderTenantID), "caller headers are kept")
+	assert.Empty(t, received.Get(logger.HeaderTraceID))
+}
@@ -0,0 +1,52 @@
+package i18n
+
+// catalogEN is the English (source) catalog.
+// Error response messages are keyed by their English text, so they don't need an entry here.
+var catalogEN = map[string]string{
+	// Validation messages (args: field name, then tag params)
+	"validation.required":              "%s is required",
+	"validation.required_without":      "%s is required when %s is not inputted",
+	"validation.oneof":                 "%s should be inputted either %s",
+	"validation.min":                   "%s must be at least %s characters",
+	"validation.max":                   "%s must be at most %s characters",
+	"validation.min.number":            "%s must be at least %s",
+	"validation.max.number":            "%s must be at most %s",
+	"validation.email":                 "%s must be a valid email address",
+	"validation.nospecial":             "%s must not contain special characters",
+	"validation.noStartEndSpaces":      "%s must not start or end with spaces",
+	"validation.date":                  "%s must be a valid date in YYYY-MM-DD format",
+	"validation.datetime":              "%s must be a valid date in YYYY-MM-DD HH:MM:SS format",
+	"validation.phoneFormat":           "%s must be a valid phone number in format +628123456789",
+	"validation.emailPhoneFormat":      "%s must be a valid email or phone number",
+	"validation.numeric":               "%s must be a valid number",
+	"validation.daterange":             "Your date range should be between %s and %s",
+	"validation.yyyymmddNoExceedToday": "%s must be a valid date in YYYY-MM-DD format and not exceed today",
+	"validation.hhmmFormat":            "%s must be a valid time in HH:MM format",
+	"validation.emailOrPhoneField":     "Email or Phone Number cannot be empty",
+	"validation.username":              "%s must be 3 to 30 letters, digits, dots, or underscores and must not be a reserved name",
+	"validation.passwordMinLength":     "%s must be at least %d characters long",
+	"validation.passwordMaxLength":     "%s must not exceed %d characters (bcrypt limit)",
+	"validation.passwordStrength":      "%s must contain at least one uppercase letter, one lowercase letter, one number, and one special character",
+	"validation.passwordScore":         "%s is too weak: %s",
+	"validation.passwordBreached":      "%s has appeared in a known data breach, choose a different password",
+	"validation.default":               "invalid input on field %s: %s",
+	"validation.listSort":              "%s must be comma-separated fields of %s, each prefixed with - for descending",
+	"validation.listFilter":            "%s is not a filter of this list, the filters are %s",
+	"validation.listFilterOperator":    "%s does not support the %s operator, use one of %s",
+	"validation.cursor":                "%s is invalid or was issued for another sort, restart from the first page",
+	"validation.cursorPage":            "%s cannot be combined with page",
+	"validation.cursorUnsupported":     "%s is not supported by this list, use page",
+
+	// Password suggestions (used by validation.passwordScore)
+	"password.suggestion.avoidCommon":   "avoid common passwords and simple variations of them",
+	"password.suggestion.avoidPersonal": "avoid using your name, email, or phone number",
+	"password.suggestion.avoidSequence": "avoid sequences like abc, 123, or qwerty",
+	"password.suggestion.avoidRepeat":   "avoid repeated characters like aaa",
+	"password.suggestion.longer":        "use a longer password, a few unrelated words work well",
+	"password.suggestion.addVariety":    "mix uppercase letters, numbers, and symbols",
+
+	// Email layouts (internal/pkg/mail/templates)
+	"email.lang":   "en",
+	"email.action": "Open",
+	"email.footer": "You received this email about your account. If you don't have an account with us, you can ignore it.",
+}
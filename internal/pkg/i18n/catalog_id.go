@@ -0,0 +1,91 @@
+package i18n
+
+// catalogID is the Indonesian catalog.
+var catalogID = map[string]string{
+	// Validation messages
+	"validation.required":              "%s wajib diisi",
+	"validation.required_without":      "%s wajib diisi jika %s tidak diisi",
+	"validation.oneof":                 "%s harus diisi salah satu dari %s",
+	"validation.min":                   "%s minimal %s karakter",
+	"validation.max":                   "%s maksimal %s karakter",
+	"validation.min.number":            "%s minimal %s",
+	"validation.max.number":            "%s maksimal %s",
+	"validation.email":                 "%s harus berupa alamat email yang valid",
+	"validation.nospecial":             "%s tidak boleh mengandung karakter khusus",
+	"validation.noStartEndSpaces":      "%s tidak boleh diawali atau diakhiri spasi",
+	"validation.date":                  "%s harus berupa tanggal yang valid dengan format YYYY-MM-DD",
+	"validation.datetime":              "%s harus berupa tanggal yang valid dengan format YYYY-MM-DD HH:MM:SS",
+	"validation.phoneFormat":           "%s harus berupa nomor telepon yang valid dengan format +628123456789",
+	"validation.emailPhoneFormat":      "%s harus berupa email atau nomor telepon yang valid",
+	"validation.numeric":               "%s harus berupa angka yang valid",
+	"validation.daterange":             "Rentang tanggal harus di antara %s dan %s",
+	"validation.yyyymmddNoExceedToday": "%s harus berupa tanggal yang valid dengan format YYYY-MM-DD dan tidak melebihi hari ini",
+	"validation.hhmmFormat":            "%s harus berupa waktu yang valid dengan format HH:MM",
+	"validation.emailOrPhoneField":     "Email atau Nomor Telepon tidak boleh kosong",
+	"validation.username":              "%s harus terdiri dari 3 sampai 30 huruf, angka, titik, atau garis bawah dan bukan nama yang dicadangkan",
+	"validation.passwordMinLength":     "%s minimal %d karakter",
+	"validation.passwordMaxLength":     "%s tidak boleh melebihi %d karakter (batas bcrypt)",
+	"validation.passwordStrength":      "%s harus mengandung minimal satu huruf besar, satu huruf kecil, satu angka, dan satu karakter khusus",
+	"validation.passwordScore":         "%s terlalu lemah: %s",
+	"validation.passwordBreached":      "%s pernah muncul dalam kebocoran data, pilih kata sandi lain",
+	"validation.default":               "input tidak valid pada field %s: %s",
+	"validation.listSort":              "%s harus berupa field %s yang dipisahkan koma, diawali - untuk urutan menurun",
+	"validation.listFilter":            "%s bukan filter dari daftar ini, filternya adalah %s",
+	"validation.listFilterOperator":    "%s tidak mendukung operator %s, gunakan salah satu dari %s",
+	"validation.cursor":                "%s tidak valid atau diterbitkan untuk urutan lain, mulai lagi dari halaman pertama",
+	"validation.cursorPage":            "%s tidak dapat digabungkan dengan page",
+	"validation.cursorUnsupported":     "%s tidak didukung oleh daftar ini, gunakan page",
+
+	// Password suggestions
+	"password.suggestion.avoidCommon":   "hindari kata sandi umum dan variasinya",
+	"password.suggestion.avoidPersonal": "hindari penggunaan nama, email, atau nomor telepon anda",
+	"password.suggestion.avoidSequence": "hindari urutan seperti abc, 123, atau qwerty",
+	"password.suggestion.avoidRepeat":   "hindari karakter berulang seperti aaa",
+	"password.suggestion.longer":        "gunakan kata sandi yang lebih panjang, beberapa kata acak sudah cukup",
+	"password.suggestion.addVariety":    "gabungkan huruf besar, angka, dan simbol",
+
+	// Predefined error responses (keyed by English message)
+	"user not found":          "pengguna tidak ditemukan",
+	"unauthorized":            "tidak terotorisasi",
+	"forbidden":               "akses ditolak",
+	"email already exists":    "email sudah terdaftar",
+	"token expired":           "token kedaluwarsa",
+	"resource already exists": "data sudah ada",
+	"data not found":          "data tidak ditemukan",
+	"invalid input":           "input tidak valid",
+	"invalid data":            "data tidak valid",
+	"you are not allowed to access this feature":        "anda tidak diizinkan mengakses fitur ini",
+	"Unknown server error occurred.":                    "Terjadi kesalahan server yang tidak diketahui.",
+	"Validation failed for one or more fields.":         "Validasi gagal untuk satu atau lebih field.",
+	"Database error occurred.":                          "Terjadi kesalahan basis data.",
+	"request body is too large":                         "ukuran body permintaan terlalu besar",
+	"unsupported content type":                          "tipe konten tidak didukung",
+	"missing or invalid csrf token":                     "token csrf tidak ada atau tidak valid",
+	"too many failed attempts, try again later":         "terlalu banyak percobaan gagal, coba lagi nanti",
+	"missing or invalid captcha response":               "respons captcha tidak ada atau tidak valid",
+	"the current terms must be accepted":                "syarat dan ketentuan terbaru harus disetujui",
+	"the request quota of this API key is exhausted":    "kuota permintaan untuk API key ini telah habis",
+	"the server is busy, try again later":               "server sedang sibuk, coba lagi nanti",
+	"the service is under maintenance, try again later": "layanan sedang dalam pemeliharaan, coba lagi nanti",
+
+	// Common service messages
+	"User not found":                                          "Pengguna tidak ditemukan",
+	"Invalid password":                                        "Kata sandi salah",
+	"Invalid phone number format":                             "Format nomor telepon tidak valid",
+	"Failed to create user":                                   "Gagal membuat pengguna",
+	"Failed to get user":                                      "Gagal mengambil data pengguna",
+	"Failed to get user credentials":                          "Gagal mengambil kredensial pengguna",
+	"Failed to hash password":                                 "Gagal memproses kata sandi",
+	"Failed to generate account number":                       "Gagal membuat nomor akun",
+	"Failed to generate access token":                         "Gagal membuat access token",
+	"Failed to generate refresh token":                        "Gagal membuat refresh token",
+	"User with the same email or phone number already exists": "Pengguna dengan email atau nomor telepon yang sama sudah terdaftar",
+	"authorization header is required":                        "header authorization wajib diisi",
+	"invalid authorization format":                            "format authorization tidak valid",
+	"Request has been successfully processed.":                "Permintaan berhasil diproses.",
+
+	// Email layouts
+	"email.lang":   "id",
+	"email.action": "Buka",
+	"email.footer": "Anda menerima email ini terkait akun anda. Jika anda tidak memiliki akun di layanan kami, abaikan email ini.",
+}
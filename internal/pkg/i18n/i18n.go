@@ -0,0 +1,122 @@
+// Package i18n provides message catalogs and locale negotiation for
+// user-facing messages (validation errors, error responses).
+//
+// Catalog keys are either message IDs (e.g. "validation.required") or the
+// English source text itself, gettext style. A lookup falls back to the
+// English catalog and finally to the key, so untranslated messages are
+// always returned unchanged.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a supported language tag.
+type Locale string
+
+const (
+	English    Locale = "en"
+	Indonesian Locale = "id"
+
+	// DefaultLocale is used when the client does not request a supported language.
+	DefaultLocale = English
+)
+
+// catalogs maps every supported locale to its message catalog.
+var catalogs = map[Locale]map[string]string{
+	English:    catalogEN,
+	Indonesian: catalogID,
+}
+
+// contextKey is a private type for context keys to avoid collisions.
+type contextKey string
+
+const localeKey contextKey = "locale"
+
+// Supported reports whether a catalog exists for the locale.
+func Supported(locale Locale) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// T translates key into the given locale and formats it with args.
+//
+// Example:
+//
+//	i18n.T(i18n.Indonesian, "validation.required", "email") // "email wajib diisi"
+func T(locale Locale, key string, args ...any) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Parse negotiates the best supported locale from an Accept-Language header,
+// honoring q-values. Region subtags are ignored ("id-ID" matches "id").
+// Returns DefaultLocale when nothing matches.
+func Parse(acceptLanguage string) Locale {
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if locale := Locale(base); Supported(locale) && q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+
+	// Stable sort keeps header order for equal q-values
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
+
+// WithLocale stores the locale in the context.
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// FromContext extracts the locale from context, returning DefaultLocale if none is set.
+func FromContext(ctx context.Context) Locale {
+	if ctx == nil {
+		return DefaultLocale
+	}
+	if locale, ok := ctx.Value(localeKey).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
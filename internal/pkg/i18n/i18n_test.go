@@ -0,0 +1,51 @@
+package i18n_test
+
+import (
+	"context"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Empty Header Falls Back To Default", func(t *testing.T) {
+		assert.Equal(t, i18n.DefaultLocale, i18n.Parse(""))
+	})
+
+	t.Run("Region Subtag Is Ignored", func(t *testing.T) {
+		assert.Equal(t, i18n.Indonesian, i18n.Parse("id-ID"))
+	})
+
+	t.Run("Highest Q-Value Wins", func(t *testing.T) {
+		assert.Equal(t, i18n.Indonesian, i18n.Parse("en;q=0.5, id;q=0.9"))
+		assert.Equal(t, i18n.English, i18n.Parse("fr, en-US;q=0.8, id;q=0.7"))
+	})
+
+	t.Run("Unsupported Languages Fall Back To Default", func(t *testing.T) {
+		assert.Equal(t, i18n.DefaultLocale, i18n.Parse("fr, de;q=0.9"))
+	})
+}
+
+func TestT(t *testing.T) {
+	t.Run("Translates Message ID With Args", func(t *testing.T) {
+		assert.Equal(t, "email wajib diisi", i18n.T(i18n.Indonesian, "validation.required", "email"))
+		assert.Equal(t, "email is required", i18n.T(i18n.English, "validation.required", "email"))
+	})
+
+	t.Run("Translates English Source Text", func(t *testing.T) {
+		assert.Equal(t, "tidak terotorisasi", i18n.T(i18n.Indonesian, "unauthorized"))
+	})
+
+	t.Run("Unknown Key Is Returned Unchanged", func(t *testing.T) {
+		assert.Equal(t, "some custom message", i18n.T(i18n.Indonesian, "some custom message"))
+	})
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, i18n.DefaultLocale, i18n.FromContext(context.Background()))
+
+	ctx := i18n.WithLocale(context.Background(), i18n.Indonesian)
+	assert.Equal(t, i18n.Indonesian, i18n.FromContext(ctx))
+}
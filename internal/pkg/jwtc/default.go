@@ -2,10 +2,13 @@
 package jwtc
 
 import (
+	"fmt"
 	"go-echo-boilerplate/internal/config"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hashicorp/go-multierror"
 )
 
 // ============================================================================
@@ -22,6 +25,12 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+func init() {
+	// Issue times in milliseconds rather than seconds, so a session revocation (see package
+	// session) tells a token issued right after it from one issued before it in the same second
+	jwt.TimePrecision = time.Millisecond
+}
+
 // JWTConfig holds the configuration for JWT token generation
 type Configuration struct {
 	AccessTokenSecret    string
@@ -31,11 +40,47 @@ type Configuration struct {
 	Issuer               string
 }
 
-// DefaultJWTConfig returns a default JWT configuration
-// WARNING: You should override SecretKey from environment variables in production
-func DefaultConfig(config *config.Configuration) *Configuration {
-	accessTokenDuration, _ := time.ParseDuration(config.Authorization.Access.Duration)
-	refreshTokenDuration, _ := time.ParseDuration(config.Authorization.Refresh.Duration)
+// weakSecrets are placeholder secrets that must never be used to sign tokens
+var weakSecrets = []string{
+	"default-secret-key-change-in-production",
+	"change-me",
+	"changeme",
+	"secret",
+	"test-secret-key",
+}
+
+// FromConfig builds the JWT configuration from authorization.access/refresh,
+// parsing the durations and rejecting weak secrets (shorter than config.MinSecretLength
+// or a known placeholder). All problems are reported at once as a *multierror.Error.
+//
+// Example:
+//
+//	jwtConfig, err := jwtc.FromConfig(configuration)
+//	if err != nil {
+//	    return nil, err
+//	}
+func FromConfig(config *config.Configuration) (*Configuration, error) {
+	var errs *multierror.Error
+
+	accessTokenDuration, err := parseDuration(config.Authorization.Access.Duration)
+	if err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("authorization.access.duration: %w", err))
+	}
+	refreshTokenDuration, err := parseDuration(config.Authorization.Refresh.Duration)
+	if err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("authorization.refresh.duration: %w", err))
+	}
+
+	if err := checkSecret(config.Authorization.Access.Secret); err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("authorization.access.secret: %w", err))
+	}
+	if err := checkSecret(config.Authorization.Refresh.Secret); err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("authorization.refresh.secret: %w", err))
+	}
+
+	if err := errs.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("invalid jwt configuration: %w", err)
+	}
 
 	return &Configuration{
 		AccessTokenSecret:    config.Authorization.Access.Secret,
@@ -43,5 +88,38 @@ func DefaultConfig(config *config.Configuration) *Configuration {
 		RefreshTokenSecret:   config.Authorization.Refresh.Secret,
 		RefreshTokenDuration: refreshTokenDuration,
 		Issuer:               config.Authorization.Issuer,
+	}, nil
+}
+
+// parseDuration parses a required, positive token duration
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("is required")
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("must be positive, got %q", value)
+	}
+
+	return duration, nil
+}
+
+// checkSecret rejects missing, short, and placeholder signing secrets
+func checkSecret(secret string) error {
+	if secret == "" {
+		return fmt.Errorf("is required")
+	}
+	for _, weak := range weakSecrets {
+		if strings.EqualFold(secret, weak) {
+			return fmt.Errorf("must not be a placeholder value")
+		}
+	}
+	if len(secret) < config.MinSecretLength {
+		return fmt.Errorf("must be at least %d characters", config.MinSecretLength)
 	}
+	return nil
 }
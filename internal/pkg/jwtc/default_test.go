@@ -0,0 +1,50 @@
+package jwtc_test
+
+import (
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromConfig(t *testing.T) {
+	configuration := &config.Configuration{
+		Authorization: config.Authorization{
+			Issuer:  "issuer",
+			Access:  config.TokenConfiguration{Secret: "0123456789abcdef0123456789abcdef", Duration: "15m"},
+			Refresh: config.TokenConfiguration{Secret: "fedcba9876543210fedcba9876543210", Duration: "168h"},
+		},
+	}
+
+	jwtConfig, err := jwtc.FromConfig(configuration)
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, jwtConfig.AccessTokenDuration)
+	assert.Equal(t, 168*time.Hour, jwtConfig.RefreshTokenDuration)
+	assert.Equal(t, "issuer", jwtConfig.Issuer)
+}
+
+func TestFromConfigRejectsInvalid(t *testing.T) {
+	configuration := &config.Configuration{
+		Authorization: config.Authorization{
+			Access:  config.TokenConfiguration{Secret: "default-secret-key-change-in-production", Duration: "15 minutes"},
+			Refresh: config.TokenConfiguration{Secret: "short", Duration: ""},
+		},
+	}
+
+	jwtConfig, err := jwtc.FromConfig(configuration)
+	require.Error(t, err)
+	assert.Nil(t, jwtConfig)
+
+	for _, key := range []string{
+		"authorization.access.duration",
+		"authorization.refresh.duration",
+		"authorization.access.secret",
+		"authorization.refresh.secret",
+	} {
+		assert.Contains(t, err.Error(), key)
+	}
+}
@@ -0,0 +1,233 @@
+// Package leader elects one replica of the application to run the singleton background
+// processes (schedulers, relays). The leader holds a lock of internal/pkg/lock for as long as
+// it runs; the other replicas try to take it every retry interval, so one of them takes over
+// once the leader stops, crashes, or loses the lock.
+//
+//	elector := leader.New(lock.Default(), "jobs", leader.DefaultRetryInterval)
+//	go elector.Start(ctx)
+//	process := graceful.NewTickerProcess(time.Minute, leader.OnlyLeader(relay))
+package leader
+
+import (
+	"context"
+	"errors"
+	"go-echo-boilerplate/internal/pkg/lock"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRetryInterval is how often a follower tries to take the leadership
+const DefaultRetryInterval = 5 * time.Second
+
+// releaseTimeout bounds waiting for the processes of a term, then releasing the lock
+const releaseTimeout = 5 * time.Second
+
+// keyPrefix namespaces the locks of the elections
+const keyPrefix = "leader:"
+
+// Status is the leadership of an instance
+type Status struct {
+	Leading bool
+	Since   time.Time // when the instance became leader or follower
+	Err     error     // last campaign failure, nil once a campaign gets an answer again
+}
+
+// Elector campaigns for the leadership of an election. It is a graceful.Process: Start
+// campaigns until Stop or until its context is done, then steps down.
+type Elector struct {
+	locker        lock.Locker
+	name          string
+	retryInterval time.Duration
+
+	mu     sync.RWMutex
+	status Status
+	term   context.Context // done when the leadership ends, nil on a follower
+	runs   *sync.WaitGroup // the OnlyLeader runs of the term
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates an Elector for the election name held with locker, trying to take the
+// leadership every retryInterval (DefaultRetryInterval when zero).
+func New(locker lock.Locker, name string, retryInterval time.Duration) *Elector {
+	if retryInterval <= 0 {
+		retryInterval = DefaultRetryInterval
+	}
+	return &Elector{
+		locker:        locker,
+		name:          name,
+		retryInterval: retryInterval,
+		status:        Status{Since: time.Now()},
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start campaigns for the leadership until Stop is called or ctx is done.
+func (e *Elector) Start(ctx context.Context) error {
+	for {
+		held, err := e.locker.TryLock(ctx, keyPrefix+e.name)
+		switch {
+		case err == nil:
+			e.lead(ctx, held)
+		case errors.Is(err, lock.ErrNotAcquired):
+			e.setErr(nil)
+		default:
+			e.setErr(err)
+			logger.L().Warn(ctx, "leader election failed", logger.String("election", e.name), logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-e.stop:
+			return nil
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// lead holds the leadership until held is lost, Stop is called, or ctx is done
+func (e *Elector) lead(ctx context.Context, held lock.Lock) {
+	term, endTerm := context.WithCancel(ctx)
+	defer endTerm()
+	runs := &sync.WaitGroup{}
+	e.setLeading(true, term, runs)
+	logger.L().Info(ctx, "leadership acquired", logger.String("election", e.name))
+
+	select {
+	case <-held.Lost():
+		logger.L().Warn(ctx, "leadership lost", logger.String("election", e.name))
+	case <-ctx.Done():
+	case <-e.stop:
+	}
+
+	// Another replica can take over once the lock is released, so the processes run for the
+	// term are canceled and waited for first
+	e.setLeading(false, nil, nil)
+	endTerm()
+	waitCtx, cancelWait := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancelWait()
+	if !wait(waitCtx, runs) {
+		logger.L().Warn(ctx, "leadership released before the processes of the term returned", logger.String("election", e.name))
+	}
+
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+	if err := held.Unlock(releaseCtx); err != nil {
+		logger.L().Warn(ctx, "leadership release failed", logger.String("election", e.name), logger.Error(err))
+	}
+}
+
+// Stop ends the campaign, stepping down when leading.
+func (e *Elector) Stop(ctx context.Context) error {
+	e.stopOnce.Do(func() { close(e.stop) })
+	return nil
+}
+
+// IsLeader reports whether the instance leads the election.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status.Leading
+}
+
+// Term returns a context done when the current leadership of the instance ends; ok is
+// false on a follower.
+func (e *Elector) Term() (term context.Context, ok bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.term, e.term != nil
+}
+
+// join registers a run of the current term, which the leadership waits for before being
+// released; done ends the run and ok is false on a follower
+func (e *Elector) join() (term context.Context, done func(), ok bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.term == nil {
+		return nil, nil, false
+	}
+	e.runs.Add(1)
+	return e.term, e.runs.Done, true
+}
+
+// Status returns the leadership of the instance.
+func (e *Elector) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
+
+// Name returns the name of the election.
+func (e *Elector) Name() string {
+	return e.name
+}
+
+func (e *Elector) setLeading(leading bool, term context.Context, runs *sync.WaitGroup) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status = Status{Leading: leading, Since: time.Now()}
+	e.term, e.runs = term, runs
+}
+
+// wait waits for runs until ctx is done, reporting whether they all returned
+func wait(ctx context.Context, runs *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (e *Elector) setErr(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status.Err = err
+}
+
+// OnlyLeader wraps run so that it only runs on the leader of the Default() election, with a
+// context also canceled when the leadership ends. The leader waits for run to return (up to
+// releaseTimeout) before releasing the leadership, so a former leader stops before the next
+// one starts. Without a default Elector every instance runs it.
+func OnlyLeader(run func(ctx context.Context)) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		elector := Default()
+		if elector == nil {
+			run(ctx)
+			return
+		}
+
+		term, done, ok := elector.join()
+		if !ok {
+			return
+		}
+		defer done()
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(term, cancel)
+		defer stop()
+		run(ctx)
+	}
+}
+
+var current atomic.Pointer[Elector]
+
+// SetDefault installs the Elector of OnlyLeader and of the health check; nil makes every
+// instance run the singleton processes.
+func SetDefault(elector *Elector) {
+	current.Store(elector)
+}
+
+// Default returns the installed Elector, nil when none is installed.
+func Default() *Elector {
+	return current.Load()
+}
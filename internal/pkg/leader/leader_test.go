@@ -0,0 +1,155 @@
+package leader_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/leader"
+	"go-echo-boilerplate/internal/pkg/lock"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocker(t *testing.T, ttl time.Duration, server *miniredis.Miniredis) lock.Locker {
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return lock.NewRedisLocker(ttl, client)
+}
+
+// failingLocker cannot reach its store
+type failingLocker struct{}
+
+func (failingLocker) TryLock(context.Context, string) (lock.Lock, error) {
+	return nil, errors.New("connection refused")
+}
+
+func start(t *testing.T, elector *leader.Elector) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = elector.Start(context.Background())
+	}()
+	t.Cleanup(func() {
+		_ = elector.Stop(context.Background())
+		<-done
+	})
+}
+
+func TestElector(t *testing.T) {
+	t.Run("One Leader And Failover", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		first := leader.New(newLocker(t, time.Minute, server), "jobs", 20*time.Millisecond)
+		second := leader.New(newLocker(t, time.Minute, server), "jobs", 20*time.Millisecond)
+
+		start(t, first)
+		require.Eventually(t, first.IsLeader, time.Second, 5*time.Millisecond)
+		start(t, second)
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, second.IsLeader(), "the leadership is held")
+		assert.True(t, server.Exists("lock:leader:jobs"))
+
+		require.NoError(t, first.Stop(context.Background()))
+		require.Eventually(t, second.IsLeader, time.Second, 5*time.Millisecond, "a follower takes over")
+		assert.False(t, first.IsLeader())
+	})
+
+	t.Run("Steps Down When The Lock Is Lost", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		elector := leader.New(newLocker(t, 300*time.Millisecond, server), "jobs", time.Minute)
+
+		start(t, elector)
+		require.Eventually(t, elector.IsLeader, time.Second, 5*time.Millisecond)
+		since := elector.Status().Since
+
+		require.NoError(t, server.Set("lock:leader:jobs", "someone-else"))
+		require.Eventually(t, func() bool { return !elector.IsLeader() }, time.Second, 5*time.Millisecond)
+		assert.True(t, elector.Status().Since.After(since))
+	})
+
+	t.Run("Reports Campaign Failures", func(t *testing.T) {
+		elector := leader.New(failingLocker{}, "jobs", 20*time.Millisecond)
+
+		start(t, elector)
+		require.Eventually(t, func() bool { return elector.Status().Err != nil }, time.Second, 5*time.Millisecond)
+		assert.False(t, elector.IsLeader())
+	})
+}
+
+func TestOnlyLeader(t *testing.T) {
+	t.Cleanup(func() { leader.SetDefault(nil) })
+	ran := 0
+	run := leader.OnlyLeader(func(context.Context) { ran++ })
+
+	leader.SetDefault(nil)
+	run(context.Background())
+	assert.Equal(t, 1, ran, "runs everywhere without an election")
+
+	server := miniredis.RunT(t)
+	leader.SetDefault(leader.New(newLocker(t, time.Minute, server), "jobs", time.Minute))
+	run(context.Background())
+	assert.Equal(t, 1, ran, "skipped on a follower")
+
+	follower := leader.New(newLocker(t, time.Minute, server), "jobs", 10*time.Millisecond)
+	leader.SetDefault(follower)
+	start(t, follower)
+	require.Eventually(t, follower.IsLeader, time.Second, 5*time.Millisecond)
+	run(context.Background())
+	assert.Equal(t, 2, ran, "runs on the leader")
+}
+
+func TestOnlyLeaderEndsWithTheTerm(t *testing.T) {
+	t.Cleanup(func() { leader.SetDefault(nil) })
+	server := miniredis.RunT(t)
+	elector := leader.New(newLocker(t, time.Minute, server), "jobs", time.Minute)
+	leader.SetDefault(elector)
+	start(t, elector)
+	require.Eventually(t, elector.IsLeader, time.Second, 5*time.Millisecond)
+
+	running, done := make(chan struct{}), make(chan error)
+	go leader.OnlyLeader(func(ctx context.Context) {
+		close(running)
+		<-ctx.Done()
+		done <- ctx.Err()
+	})(context.Background())
+
+	<-running
+	require.NoError(t, elector.Stop(context.Background()))
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("the run outlived the leadership")
+	}
+}
+
+func TestLeadershipWaitsForTheRuns(t *testing.T) {
+	t.Cleanup(func() { leader.SetDefault(nil) })
+	server := miniredis.RunT(t)
+	elector := leader.New(newLocker(t, time.Minute, server), "jobs", time.Minute)
+	leader.SetDefault(elector)
+	start(t, elector)
+	require.Eventually(t, elector.IsLeader, time.Second, 5*time.Millisecond)
+
+	running, canceled, returned := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go leader.OnlyLeader(func(ctx context.Context) {
+		close(running)
+		<-ctx.Done()
+		close(canceled)
+		time.Sleep(200 * time.Millisecond) // ignores the cancellation for a moment
+		close(returned)
+	})(context.Background())
+
+	<-running
+	require.NoError(t, elector.Stop(context.Background()))
+	<-canceled
+	assert.False(t, elector.IsLeader())
+	assert.True(t, server.Exists("lock:leader:jobs"), "the lock is held until the run returns")
+
+	<-returned
+	assert.Eventually(t, func() bool { return !server.Exists("lock:leader:jobs") }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,223 @@
+// Package limiter caps the requests served at once (middleware.ConcurrencyMiddleware). Past
+// its cap, a request waits in a bounded queue for a slot, and is shed when the queue is full
+// or it waited too long, so an overloaded server answers quick 503s instead of slowing down
+// every request until they all time out.
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultQueueTimeout is the longest wait for a slot when concurrency.queue_timeout is unset
+	DefaultQueueTimeout = time.Second
+
+	// DefaultRetryAfter is sent with the shed requests when concurrency.retry_after is unset
+	DefaultRetryAfter = time.Second
+
+	// Global is the name of the limit of every request in Limits.Stats
+	Global = "*"
+)
+
+// ErrSaturated is returned by Acquire when the request is shed.
+var ErrSaturated = errors.New("limiter: saturated")
+
+// Limiter lets a fixed number of callers hold a slot at once. It is safe for concurrent use.
+type Limiter struct {
+	slots    chan struct{}
+	maxQueue int64
+	timeout  time.Duration
+
+	waiting  atomic.Int64
+	admitted atomic.Uint64
+	shed     atomic.Uint64
+	waited   atomic.Int64 // total wait of the admitted callers, in nanoseconds
+}
+
+// Stats reports the saturation of a Limiter.
+type Stats struct {
+	Limit    int    // slots
+	InFlight int    // slots held
+	Waiting  int    // callers queued for a slot
+	Admitted uint64 // callers given a slot since startup
+	Shed     uint64 // callers turned away since startup
+	Waited   time.Duration
+}
+
+// New creates a Limiter of maxInFlight slots, queueing up to maxQueue callers for at most
+// timeout when they are all held.
+func New(maxInFlight, maxQueue int, timeout time.Duration) *Limiter {
+	return &Limiter{
+		slots:    make(chan struct{}, maxInFlight),
+		maxQueue: int64(maxQueue),
+		timeout:  timeout,
+	}
+}
+
+// Acquire takes a slot, waiting for one when they are all held. It returns the release of the
+// slot and how long it waited, or ErrSaturated when the queue is full or the wait timed out,
+// and ctx's error when it is done first.
+func (l *Limiter) Acquire(ctx context.Context) (func(), time.Duration, error) {
+	select {
+	case l.slots <- struct{}{}:
+		l.admitted.Add(1)
+		return l.release, 0, nil
+	default:
+	}
+
+	if l.waiting.Add(1) > l.maxQueue {
+		l.waiting.Add(-1)
+		l.shed.Add(1)
+		return nil, 0, ErrSaturated
+	}
+	defer l.waiting.Add(-1)
+
+	start := time.Now()
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		waited := time.Since(start)
+		l.admitted.Add(1)
+		l.waited.Add(int64(waited))
+		return l.release, waited, nil
+	case <-timer.C:
+		l.shed.Add(1)
+		return nil, time.Since(start), ErrSaturated
+	case <-ctx.Done():
+		return nil, time.Since(start), ctx.Err()
+	}
+}
+
+func (l *Limiter) release() {
+	<-l.slots
+}
+
+// Stats returns the current saturation of the limiter.
+func (l *Limiter) Stats() Stats {
+	return Stats{
+		Limit:    cap(l.slots),
+		InFlight: len(l.slots),
+		Waiting:  int(l.waiting.Load()),
+		Admitted: l.admitted.Load(),
+		Shed:     l.shed.Load(),
+		Waited:   time.Duration(l.waited.Load()),
+	}
+}
+
+// Limits are the limiters of the concurrency configuration: one for every request, and one
+// per capped route.
+type Limits struct {
+	global     *Limiter            // nil without concurrency.max_in_flight
+	routes     map[string]*Limiter // by "METHOD /path"
+	retryAfter time.Duration
+}
+
+// FromConfig creates the limits of the concurrency configuration. It returns nil when no cap
+// is configured.
+func FromConfig(cfg config.Concurrency) (*Limits, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	timeout := DefaultQueueTimeout
+	if cfg.QueueTimeout != "" {
+		parsed, err := time.ParseDuration(cfg.QueueTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid concurrency.queue_timeout: %w", err)
+		}
+		timeout = parsed
+	}
+	retryAfter := DefaultRetryAfter
+	if cfg.RetryAfter != "" {
+		parsed, err := time.ParseDuration(cfg.RetryAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid concurrency.retry_after: %w", err)
+		}
+		retryAfter = parsed
+	}
+
+	limits := &Limits{routes: make(map[string]*Limiter, len(cfg.Routes)), retryAfter: retryAfter}
+	if cfg.MaxInFlight > 0 {
+		limits.global = New(cfg.MaxInFlight, queueSize(cfg.MaxQueue, cfg.MaxInFlight), timeout)
+	}
+	for _, route := range cfg.Routes {
+		limits.routes[route.Route] = New(route.MaxInFlight, queueSize(route.MaxQueue, route.MaxInFlight), timeout)
+	}
+	return limits, nil
+}
+
+// queueSize defaults the queue to as many requests as the slots
+func queueSize(maxQueue, maxInFlight int) int {
+	if maxQueue > 0 {
+		return maxQueue
+	}
+	return maxInFlight
+}
+
+// Acquire takes a slot of the route ("METHOD /path" as registered) and then a global one.
+// It returns their release and how long it waited for them, or the error of the first
+// Acquire failing along with the name of its limit (the route or Global).
+func (l *Limits) Acquire(ctx context.Context, route string) (release func(), waited time.Duration, limit string, err error) {
+	release = func() {}
+
+	if limiter, ok := l.routes[route]; ok {
+		releaseRoute, routeWaited, err := limiter.Acquire(ctx)
+		waited += routeWaited
+		if err != nil {
+			return nil, waited, route, err
+		}
+		release = releaseRoute
+	}
+
+	if l.global != nil {
+		releaseGlobal, globalWaited, err := l.global.Acquire(ctx)
+		waited += globalWaited
+		if err != nil {
+			release()
+			return nil, waited, Global, err
+		}
+		releaseRoute := release
+		release = func() {
+			releaseGlobal()
+			releaseRoute()
+		}
+	}
+	return release, waited, "", nil
+}
+
+// RetryAfter is how long the shed requests should wait before they retry.
+func (l *Limits) RetryAfter() time.Duration {
+	return l.retryAfter
+}
+
+// Stats returns the saturation of each limit, by route, or Global for every request.
+func (l *Limits) Stats() map[string]Stats {
+	stats := make(map[string]Stats, len(l.routes)+1)
+	if l.global != nil {
+		stats[Global] = l.global.Stats()
+	}
+	for route, limiter := range l.routes {
+		stats[route] = limiter.Stats()
+	}
+	return stats
+}
+
+var current atomic.Pointer[Limits]
+
+// SetDefault installs the limits used by middleware.ConcurrencyMiddleware and the health
+// checks. nil disables them.
+func SetDefault(l *Limits) {
+	current.Store(l)
+}
+
+// Default returns the limits set with SetDefault, nil when no cap is configured.
+func Default() *Limits {
+	return current.Load()
+}
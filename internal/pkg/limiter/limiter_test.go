@@ -0,0 +1,111 @@
+package limiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/limiter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("Queues And Sheds", func(t *testing.T) {
+		l := limiter.New(1, 1, time.Second)
+		release, waited, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, waited)
+
+		admitted := make(chan time.Duration)
+		go func() {
+			release, waited, err := l.Acquire(context.Background())
+			if err == nil {
+				release()
+			}
+			admitted <- waited
+		}()
+		require.Eventually(t, func() bool { return l.Stats().Waiting == 1 }, time.Second, time.Millisecond)
+
+		_, _, err = l.Acquire(context.Background())
+		assert.ErrorIs(t, err, limiter.ErrSaturated, "the queue is full")
+
+		time.Sleep(10 * time.Millisecond)
+		release()
+		assert.GreaterOrEqual(t, <-admitted, 10*time.Millisecond, "waited for the slot")
+
+		stats := l.Stats()
+		assert.Equal(t, 1, stats.Limit)
+		assert.Equal(t, 0, stats.InFlight)
+		assert.Equal(t, uint64(2), stats.Admitted)
+		assert.Equal(t, uint64(1), stats.Shed)
+		assert.GreaterOrEqual(t, stats.Waited, 10*time.Millisecond)
+	})
+
+	t.Run("Queue Timeout", func(t *testing.T) {
+		l := limiter.New(1, 1, 10*time.Millisecond)
+		release, _, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		_, waited, err := l.Acquire(context.Background())
+		assert.ErrorIs(t, err, limiter.ErrSaturated)
+		assert.GreaterOrEqual(t, waited, 10*time.Millisecond)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		l := limiter.New(1, 1, time.Minute)
+		release, _, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, _, err = l.Acquire(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Zero(t, l.Stats().Shed, "not shed")
+	})
+}
+
+func TestLimits(t *testing.T) {
+	limits, err := limiter.FromConfig(config.Concurrency{})
+	require.NoError(t, err)
+	assert.Nil(t, limits, "disabled")
+
+	limits, err = limiter.FromConfig(config.Concurrency{
+		MaxInFlight:  2,
+		MaxQueue:     -1,
+		QueueTimeout: "10ms",
+		RetryAfter:   "5s",
+		Routes:       []config.ConcurrencyRoute{{Route: "GET /export", MaxInFlight: 1, MaxQueue: 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, limits.RetryAfter())
+
+	export, _, _, err := limits.Acquire(context.Background(), "GET /export")
+	require.NoError(t, err)
+	_, _, limit, err := limits.Acquire(context.Background(), "GET /export")
+	assert.ErrorIs(t, err, limiter.ErrSaturated)
+	assert.Equal(t, "GET /export", limit)
+
+	other, _, _, err := limits.Acquire(context.Background(), "GET /users")
+	require.NoError(t, err, "other routes only share the global limit")
+	_, _, limit, err = limits.Acquire(context.Background(), "GET /users")
+	assert.ErrorIs(t, err, limiter.ErrSaturated)
+	assert.Equal(t, limiter.Global, limit)
+
+	stats := limits.Stats()
+	assert.Equal(t, 2, stats[limiter.Global].InFlight)
+	assert.Equal(t, 1, stats["GET /export"].InFlight)
+
+	export()
+	other()
+	stats = limits.Stats()
+	assert.Zero(t, stats[limiter.Global].InFlight)
+	assert.Zero(t, stats["GET /export"].InFlight)
+
+	_, err = limiter.FromConfig(config.Concurrency{MaxInFlight: 1, QueueTimeout: "soon"})
+	assert.ErrorContains(t, err, "concurrency.queue_timeout")
+}
@@ -0,0 +1,124 @@
+// Package lock provides exclusive locks shared by every replica of the application, so a
+// background job or a migration runs on one of them at a time. Locks are held in PostgreSQL
+// (advisory locks, see PostgresLocker) or Redis (see RedisLocker).
+//
+//	err := lock.WithLock(ctx, "jobs:account-deletion", func(ctx context.Context) error {
+//	    return sweep(ctx)
+//	})
+//	if errors.Is(err, lock.ErrNotAcquired) {
+//	    return nil // another replica is on it
+//	}
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotAcquired is returned when another holder has the lock
+var ErrNotAcquired = errors.New("lock: held by another holder")
+
+// Defaults of WithLockWait and of releasing a lock
+const (
+	DefaultRetryInterval = 500 * time.Millisecond
+	releaseTimeout       = 5 * time.Second
+)
+
+// Locker acquires locks by key. Implementations must be safe for concurrent use.
+type Locker interface {
+	// TryLock acquires the lock of key without waiting; it returns ErrNotAcquired when
+	// another holder has it.
+	TryLock(ctx context.Context, key string) (Lock, error)
+}
+
+// Lock is a held lock.
+type Lock interface {
+	// Lost is closed when the lock is lost before Unlock, e.g. when the connection holding
+	// it broke or its lease could not be renewed.
+	Lost() <-chan struct{}
+	// Unlock releases the lock.
+	Unlock(ctx context.Context) error
+}
+
+// WithLock runs fn while holding the lock of key with Default(), and returns its error. It
+// returns ErrNotAcquired without running fn when another holder has the lock. The context of
+// fn is cancelled when the lock is lost. Without a default Locker, fn runs unlocked.
+func WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	locker := Default()
+	if locker == nil {
+		return fn(ctx)
+	}
+
+	held, err := locker.TryLock(ctx, key)
+	if err != nil {
+		return err
+	}
+	return run(ctx, held, fn)
+}
+
+// WithLockWait is WithLock waiting for the lock, trying again every DefaultRetryInterval
+// until ctx is done.
+func WithLockWait(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	locker := Default()
+	if locker == nil {
+		return fn(ctx)
+	}
+
+	for {
+		held, err := locker.TryLock(ctx, key)
+		if err == nil {
+			return run(ctx, held, fn)
+		}
+		if !errors.Is(err, ErrNotAcquired) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(DefaultRetryInterval):
+		}
+	}
+}
+
+// run calls fn with a context cancelled when held is lost, then releases held
+func run(ctx context.Context, held Lock, fn func(ctx context.Context) error) (err error) {
+	lockCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-held.Lost():
+			cancel()
+		case <-lockCtx.Done():
+		}
+	}()
+
+	defer func() {
+		// Released even when ctx is already done
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancelRelease()
+		err = errors.Join(err, held.Unlock(releaseCtx))
+	}()
+	return fn(lockCtx)
+}
+
+var current atomic.Pointer[Locker]
+
+// SetDefault installs the Locker of WithLock and WithLockWait; nil disables locking.
+func SetDefault(locker Locker) {
+	if locker == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&locker)
+}
+
+// Default returns the installed Locker, nil when none is installed.
+func Default() Locker {
+	if locker := current.Load(); locker != nil {
+		return *locker
+	}
+	return nil
+}
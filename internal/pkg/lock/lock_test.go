@@ -0,0 +1,219 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/lock"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisLocker(t *testing.T, ttl time.Duration, servers ...*miniredis.Miniredis) *lock.RedisLocker {
+	var clients []redis.UniversalClient
+	for _, server := range servers {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		clients = append(clients, client)
+	}
+	return lock.NewRedisLocker(ttl, clients...)
+}
+
+func TestRedisLocker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Excludes Other Holders Until Unlocked", func(t *testing.T) {
+		locker := newRedisLocker(t, time.Minute, miniredis.RunT(t))
+
+		held, err := locker.TryLock(ctx, "jobs")
+		require.NoError(t, err)
+		_, err = locker.TryLock(ctx, "jobs")
+		assert.ErrorIs(t, err, lock.ErrNotAcquired)
+
+		other, err := locker.TryLock(ctx, "migrate")
+		require.NoError(t, err, "other keys are independent")
+		require.NoError(t, other.Unlock(ctx))
+
+		require.NoError(t, held.Unlock(ctx))
+		held, err = locker.TryLock(ctx, "jobs")
+		require.NoError(t, err)
+		require.NoError(t, held.Unlock(ctx))
+	})
+
+	t.Run("Renews The Lease While Held", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		locker := newRedisLocker(t, 300*time.Millisecond, server)
+
+		held, err := locker.TryLock(ctx, "jobs")
+		require.NoError(t, err)
+		time.Sleep(500 * time.Millisecond)
+
+		assert.True(t, server.Exists("lock:jobs"), "renewed past its first lease")
+		select {
+		case <-held.Lost():
+			t.Fatal("lock lost")
+		default:
+		}
+		require.NoError(t, held.Unlock(ctx))
+		assert.False(t, server.Exists("lock:jobs"))
+	})
+
+	t.Run("Lost When Taken Over", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		locker := newRedisLocker(t, 300*time.Millisecond, server)
+
+		held, err := locker.TryLock(ctx, "jobs")
+		require.NoError(t, err)
+		require.NoError(t, server.Set("lock:jobs", "someone-else"))
+
+		select {
+		case <-held.Lost():
+		case <-time.After(time.Second):
+			t.Fatal("lock not lost")
+		}
+		require.NoError(t, held.Unlock(ctx))
+		got, _ := server.Get("lock:jobs")
+		assert.Equal(t, "someone-else", got, "the new holder keeps its lock")
+	})
+
+	t.Run("Needs A Majority Of The Instances", func(t *testing.T) {
+		servers := []*miniredis.Miniredis{miniredis.RunT(t), miniredis.RunT(t), miniredis.RunT(t)}
+		locker := newRedisLocker(t, time.Minute, servers...)
+
+		require.NoError(t, servers[0].Set("lock:jobs", "someone-else"))
+		held, err := locker.TryLock(ctx, "jobs")
+		require.NoError(t, err, "two of three")
+		require.NoError(t, held.Unlock(ctx))
+
+		require.NoError(t, servers[1].Set("lock:jobs", "someone-else"))
+		_, err = locker.TryLock(ctx, "jobs")
+		assert.ErrorIs(t, err, lock.ErrNotAcquired)
+		assert.False(t, servers[2].Exists("lock:jobs"), "a failed attempt releases what it took")
+	})
+}
+
+func TestPostgresLocker(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*lock.PostgresLocker, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return lock.NewPostgresLocker(db), mock
+	}
+
+	t.Run("Locks And Unlocks On One Connection", func(t *testing.T) {
+		locker, mock := setup(t)
+		mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).WithArgs(sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).WithArgs(sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		held, err := locker.TryLock(ctx, "migrate")
+		require.NoError(t, err)
+		require.NoError(t, held.Unlock(ctx))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Held Elsewhere", func(t *testing.T) {
+		locker, mock := setup(t)
+		mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+		_, err := locker.TryLock(ctx, "migrate")
+		assert.ErrorIs(t, err, lock.ErrNotAcquired)
+	})
+
+	t.Run("Query Error", func(t *testing.T) {
+		locker, mock := setup(t)
+		mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).WillReturnError(errors.New("connection refused"))
+
+		_, err := locker.TryLock(ctx, "migrate")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, lock.ErrNotAcquired)
+	})
+
+	t.Run("Lost With Its Connection", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		locker := lock.NewPostgresLocker(db).WithCheckInterval(10 * time.Millisecond)
+
+		mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		mock.ExpectPing().WillReturnError(errors.New("connection reset"))
+
+		held, err := locker.TryLock(ctx, "migrate")
+		require.NoError(t, err)
+		select {
+		case <-held.Lost():
+		case <-time.After(time.Second):
+			t.Fatal("lock not lost")
+		}
+	})
+}
+
+func TestWithLock(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { lock.SetDefault(nil) })
+
+	t.Run("Runs Unlocked Without A Locker", func(t *testing.T) {
+		lock.SetDefault(nil)
+		ran := false
+		require.NoError(t, lock.WithLock(ctx, "jobs", func(ctx context.Context) error { ran = true; return nil }))
+		assert.True(t, ran)
+	})
+
+	t.Run("Skips When Held And Releases After", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		lock.SetDefault(newRedisLocker(t, time.Minute, server))
+
+		failure := errors.New("sweep failed")
+		err := lock.WithLock(ctx, "jobs", func(ctx context.Context) error {
+			assert.True(t, server.Exists("lock:jobs"))
+			assert.ErrorIs(t, lock.WithLock(ctx, "jobs", func(context.Context) error {
+				t.Fatal("ran while held")
+				return nil
+			}), lock.ErrNotAcquired)
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		assert.False(t, server.Exists("lock:jobs"), "released after an error too")
+	})
+
+	t.Run("Cancels When Lost", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		lock.SetDefault(newRedisLocker(t, 300*time.Millisecond, server))
+
+		err := lock.WithLock(ctx, "jobs", func(ctx context.Context) error {
+			require.NoError(t, server.Set("lock:jobs", "someone-else"))
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Waits For The Lock", func(t *testing.T) {
+		lock.SetDefault(newRedisLocker(t, time.Minute, miniredis.RunT(t)))
+
+		held, err := lock.Default().TryLock(ctx, "migrate")
+		require.NoError(t, err)
+		time.AfterFunc(100*time.Millisecond, func() { _ = held.Unlock(ctx) })
+
+		ran := false
+		require.NoError(t, lock.WithLockWait(ctx, "migrate", func(ctx context.Context) error { ran = true; return nil }))
+		assert.True(t, ran)
+
+		timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		held, err = lock.Default().TryLock(ctx, "migrate")
+		require.NoError(t, err)
+		defer held.Unlock(ctx)
+		assert.ErrorIs(t, lock.WithLockWait(timeout, "migrate", func(context.Context) error { return nil }), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,118 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// DefaultCheckInterval is how often a held PostgreSQL lock checks its connection
+const DefaultCheckInterval = 5 * time.Second
+
+// PostgresLocker holds locks as PostgreSQL session advisory locks. Every held lock keeps a
+// connection of db for itself, so db should be a pool of its own rather than the one of the
+// application; the locker owns it and Close closes it. Advisory locks need a session, so db
+// must not go through a pooler in transaction mode (e.g. PgBouncer).
+type PostgresLocker struct {
+	db            *sql.DB
+	checkInterval time.Duration
+}
+
+// NewPostgresLocker creates a PostgresLocker on db.
+func NewPostgresLocker(db *sql.DB) *PostgresLocker {
+	return &PostgresLocker{db: db, checkInterval: DefaultCheckInterval}
+}
+
+// WithCheckInterval sets how often a held lock pings its connection to notice it is lost.
+func (l *PostgresLocker) WithCheckInterval(interval time.Duration) *PostgresLocker {
+	l.checkInterval = interval
+	return l
+}
+
+// Close closes the connections of the locker, releasing the locks still held.
+func (l *PostgresLocker) Close() error {
+	return l.db.Close()
+}
+
+// TryLock implements Locker with pg_try_advisory_lock.
+func (l *PostgresLocker) TryLock(ctx context.Context, key string) (Lock, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lock: failed to get a connection: %w", err)
+	}
+
+	id := advisoryLockID(key)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("lock: failed to lock %q: %w", key, err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return nil, ErrNotAcquired
+	}
+
+	held := &postgresLock{conn: conn, id: id, lost: make(chan struct{}), stop: make(chan struct{}), done: make(chan struct{})}
+	go held.check(l.checkInterval)
+	return held, nil
+}
+
+// advisoryLockID maps key to the bigint of an advisory lock
+func advisoryLockID(key string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	return int64(hash.Sum64()) // #nosec G115 -- wrapping around is fine for a lock id
+}
+
+type postgresLock struct {
+	conn *sql.Conn
+	id   int64
+
+	lost     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// check pings the connection every interval, the lock goes with it
+func (l *postgresLock) check(interval time.Duration) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := l.conn.PingContext(ctx)
+			cancel()
+			if err != nil {
+				close(l.lost)
+				return
+			}
+		}
+	}
+}
+
+func (l *postgresLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *postgresLock) Unlock(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.id); err != nil {
+		// Discarding the connection ends its session, which releases the lock
+		_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		_ = l.conn.Close()
+		return fmt.Errorf("lock: failed to unlock: %w", err)
+	}
+	return l.conn.Close()
+}
@@ -0,0 +1,174 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTTL is the lease of a Redis lock; a held lock renews it every third of it
+const DefaultTTL = 30 * time.Second
+
+// keyPrefix namespaces the locks in a shared Redis
+const keyPrefix = "lock:"
+
+var (
+	// releaseScript deletes the lock only when it still holds the token of its holder
+	releaseScript = redis.NewScript(`
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		end
+		return 0
+	`)
+
+	// renewScript extends the lease of the lock only when it still holds the token of its holder
+	renewScript = redis.NewScript(`
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		end
+		return 0
+	`)
+)
+
+// RedisLocker holds locks in Redis following Redlock: a lock is a key set with a random token
+// and a lease on a majority of the (independent) instances, renewed while it is held. With a
+// single instance it is the usual SET NX lock, and a failover of that instance may lose it.
+type RedisLocker struct {
+	clients []redis.UniversalClient
+	ttl     time.Duration
+}
+
+// NewRedisLocker creates a RedisLocker with a lease of ttl (DefaultTTL when zero) over
+// the instances of clients.
+func NewRedisLocker(ttl time.Duration, clients ...redis.UniversalClient) *RedisLocker {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &RedisLocker{clients: clients, ttl: ttl}
+}
+
+// TryLock implements Locker.
+func (l *RedisLocker) TryLock(ctx context.Context, key string) (Lock, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	held := &redisLock{
+		locker: l,
+		key:    keyPrefix + key,
+		token:  token,
+		lost:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	start := time.Now()
+	var acquired int
+	var errs []error
+	for _, client := range l.clients {
+		ok, err := client.SetNX(ctx, held.key, token, l.ttl).Result()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ok {
+			acquired++
+		}
+	}
+
+	// The lease must still be valid once a majority has been reached, allowing for clock drift
+	drift := l.ttl/100 + 2*time.Millisecond
+	if acquired < l.quorum() || time.Since(start)+drift >= l.ttl {
+		held.release(context.WithoutCancel(ctx))
+		if len(errs) > 0 && acquired+len(errs) >= l.quorum() {
+			return nil, fmt.Errorf("lock: failed to lock %q: %w", key, errors.Join(errs...))
+		}
+		return nil, ErrNotAcquired
+	}
+
+	go held.renew()
+	return held, nil
+}
+
+// quorum is the number of instances a lock must be held on
+func (l *RedisLocker) quorum() int {
+	return len(l.clients)/2 + 1
+}
+
+func newToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("lock: failed to generate a token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+type redisLock struct {
+	locker *RedisLocker
+	key    string
+	token  string
+
+	lost     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// renew extends the lease every third of it; the lock is lost when a majority of the
+// instances no longer hold it
+func (l *redisLock) renew() {
+	defer close(l.done)
+
+	ttl := l.locker.ttl
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), ttl/3)
+			var renewed int
+			for _, client := range l.locker.clients {
+				if n, err := renewScript.Run(ctx, client, []string{l.key}, l.token, ttl.Milliseconds()).Int(); err == nil && n == 1 {
+					renewed++
+				}
+			}
+			cancel()
+			if renewed < l.locker.quorum() {
+				close(l.lost)
+				return
+			}
+		}
+	}
+}
+
+func (l *redisLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *redisLock) Unlock(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+	return l.release(ctx)
+}
+
+// release deletes the lock from every instance still holding its token
+func (l *redisLock) release(ctx context.Context) error {
+	var errs []error
+	for _, client := range l.locker.clients {
+		if err := releaseScript.Run(ctx, client, []string{l.key}, l.token).Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("lock: failed to unlock: %w", err)
+	}
+	return nil
+}
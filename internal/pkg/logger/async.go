@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultAsyncBufferSize is the queue size when logger.async.buffer_size is not set
+const defaultAsyncBufferSize = 4096
+
+// async is the queue of the current logger, nil when logger.async is disabled
+var async *asyncQueue
+
+// AsyncStats reports the state of the async log queue.
+type AsyncStats struct {
+	Enabled  bool   `json:"enabled"`
+	Policy   string `json:"policy,omitempty"`
+	Queued   int    `json:"queued"`
+	Capacity int    `json:"capacity"`
+	Written  uint64 `json:"written"`
+	Dropped  uint64 `json:"dropped"`
+}
+
+// GetAsyncStats returns the async queue counters. Enabled is false when logging is synchronous.
+func GetAsyncStats() AsyncStats {
+	q := async
+	if q == nil {
+		return AsyncStats{}
+	}
+
+	return AsyncStats{
+		Enabled:  true,
+		Policy:   q.policy,
+		Queued:   len(q.entries),
+		Capacity: cap(q.entries),
+		Written:  q.written.Load(),
+		Dropped:  q.dropped.Load(),
+	}
+}
+
+// Flush waits until the async queue is empty or ctx is done. It is a no-op when logging is
+// synchronous. Register it as a graceful process so queued entries are written on shutdown.
+func Flush(ctx context.Context) error {
+	if async == nil {
+		return nil
+	}
+	return async.flush(ctx)
+}
+
+// ============================================================================
+// Queue
+// ============================================================================
+
+type asyncEntry struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// asyncQueue is a bounded queue drained by one background goroutine.
+// When the queue is full, entries are dropped (counted in Dropped) or, with the block
+// policy, the caller waits for room.
+type asyncQueue struct {
+	policy  string
+	entries chan asyncEntry
+
+	pending atomic.Int64 // queued or being written
+	written atomic.Uint64
+	dropped atomic.Uint64
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func newAsyncQueue(cfg config.LogAsync) *asyncQueue {
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = defaultAsyncBufferSize
+	}
+	policy := cfg.Policy
+	if policy == "" {
+		policy = "drop"
+	}
+
+	q := &asyncQueue{
+		policy:  policy,
+		entries: make(chan asyncEntry, size),
+		done:    make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for e := range q.entries {
+		q.write(e)
+	}
+}
+
+func (q *asyncQueue) write(e asyncEntry) {
+	_ = e.core.Write(e.entry, e.fields)
+	q.written.Add(1)
+	q.pending.Add(-1)
+}
+
+func (q *asyncQueue) push(e asyncEntry) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	q.pending.Add(1)
+
+	// Entries logged after Close are written synchronously
+	if q.closed {
+		q.write(e)
+		return
+	}
+
+	if q.policy == "block" {
+		q.entries <- e
+		return
+	}
+
+	select {
+	case q.entries <- e:
+	default:
+		q.pending.Add(-1)
+		q.dropped.Add(1)
+	}
+}
+
+func (q *asyncQueue) flush(ctx context.Context) error {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	for q.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// close writes the remaining entries and stops the background goroutine
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.entries)
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+// ============================================================================
+// Core
+// ============================================================================
+
+// asyncCore hands entries to the queue instead of writing them. Fields are encoded on the
+// background goroutine, so values passed to the logger must not be modified afterwards.
+// Entries above ErrorLevel are written synchronously because zap may exit right after them.
+type asyncCore struct {
+	core  zapcore.Core
+	queue *asyncQueue
+}
+
+func newAsyncCore(core zapcore.Core, queue *asyncQueue) zapcore.Core {
+	return &asyncCore{core: core, queue: queue}
+}
+
+func (c *asyncCore) Enabled(lvl zapcore.Level) bool {
+	return c.core.Enabled(lvl)
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{core: c.core.With(fields), queue: c.queue}
+}
+
+func (c *asyncCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *asyncCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level > zapcore.ErrorLevel {
+		_ = c.queue.flush(context.Background())
+		return c.core.Write(entry, fields)
+	}
+
+	c.queue.push(asyncEntry{core: c.core, entry: entry, fields: fields})
+	return nil
+}
+
+func (c *asyncCore) Sync() error {
+	return c.core.Sync()
+}
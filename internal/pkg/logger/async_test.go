@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// gatedCore blocks every write until release is closed
+type gatedCore struct {
+	zapcore.Core
+	release chan struct{}
+}
+
+func (c *gatedCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+func (c *gatedCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	<-c.release
+	return c.Core.Write(entry, fields)
+}
+
+func TestAsyncCore(t *testing.T) {
+	t.Run("Flush", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		queue := newAsyncQueue(config.LogAsync{})
+		defer queue.close()
+
+		log := zap.New(newAsyncCore(core, queue)).With(zap.String("service", "app"))
+		log.Debug("filtered")
+		for range 10 {
+			log.Info("event")
+		}
+
+		require.NoError(t, queue.flush(context.Background()))
+		assert.Equal(t, 10, logs.Len())
+		assert.Equal(t, "app", logs.All()[0].ContextMap()["service"])
+		assert.Equal(t, uint64(10), queue.written.Load())
+	})
+
+	t.Run("Drop When Full", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		gate := &gatedCore{Core: core, release: make(chan struct{})}
+		queue := newAsyncQueue(config.LogAsync{BufferSize: 2})
+
+		log := zap.New(newAsyncCore(gate, queue))
+		log.Info("event")
+		require.Eventually(t, func() bool { return len(queue.entries) == 0 }, time.Second, time.Millisecond)
+		for range 9 {
+			log.Info("event")
+		}
+
+		// One entry is held by the writer and two are queued
+		assert.Equal(t, uint64(7), queue.dropped.Load())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, queue.flush(ctx), context.DeadlineExceeded)
+
+		close(gate.release)
+		queue.close()
+		assert.Equal(t, 3, logs.Len())
+	})
+
+	t.Run("Block When Full", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		gate := &gatedCore{Core: core, release: make(chan struct{})}
+		queue := newAsyncQueue(config.LogAsync{BufferSize: 1, Policy: "block"})
+
+		log := zap.New(newAsyncCore(gate, queue))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range 5 {
+				log.Info("event")
+			}
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("logging should block while the queue is full")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(gate.release)
+		<-done
+		queue.close()
+		assert.Equal(t, 5, logs.Len())
+		assert.Zero(t, queue.dropped.Load())
+	})
+
+	t.Run("Write After Close", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		queue := newAsyncQueue(config.LogAsync{})
+		queue.close()
+
+		zap.New(newAsyncCore(core, queue)).Info("late")
+		assert.Equal(t, 1, logs.Len())
+	})
+}
@@ -16,12 +16,14 @@ const (
 	UserIDKey    contextKey = "user_id"
 	TraceIDKey   contextKey = "trace_id"
 	SpanIDKey    contextKey = "span_id"
+	TenantKey    contextKey = "tenant"
+	loggerKey    contextKey = "logger"
 	wideEventKey contextKey = "wide_event"
 	errorCtxKey  contextKey = "error_context"
 
-	// MaxBusinessDataSize limits the number of entries in BusinessData map
+	// MaxBusinessDataSize is the default limit on the number of entries in BusinessData
 	// to prevent unbounded memory growth in long-running requests.
-	// This can be overridden by setting a custom value before initialization.
+	// Override it with logger.limits.max_keys (see SetLimits).
 	MaxBusinessDataSize = 100
 )
 
@@ -31,8 +33,13 @@ const (
 // This structure is stored in context.Context for thread-safe access across
 // goroutines, service layers, and repository layers.
 //
-// Memory Safety: BusinessData map is limited to MaxBusinessDataSize entries
-// to prevent unbounded growth. Additional entries beyond the limit are silently dropped.
+// Memory Safety: BusinessData is bounded by the Limits in effect when the event was
+// created (key count, value size, total size). Values over the size limit are truncated
+// and entries beyond the other limits are dropped; both are reported in the emitted
+// business_data_truncated field.
+//
+// Schema: the emitted line has stable core fields (see WideEventCore and schema.go),
+// typed extensions under their namespace, then the free-form BusinessData.
 type WideEvent struct {
 	// Immutable fields (set once at initialization)
 	RequestID string
@@ -47,7 +54,16 @@ type WideEvent struct {
 	BusinessData    map[string]interface{}
 	User            *UserContext
 	Error           *ErrorContext
-	businessDataLen int // Track size separately for performance
+	extensions      map[string]Extension // Typed fields by namespace, see SetExtension
+	businessDataLen int                  // Track size separately for performance
+
+	limits            Limits
+	sizes             map[string]int // Encoded size of each BusinessData entry
+	businessDataBytes int
+	truncated         truncation
+
+	pooled   *eventMaps // BusinessData and sizes, returned to the pool by Release
+	released bool
 }
 
 // UserContext contains user-specific information for logging.
@@ -70,15 +86,20 @@ type ErrorContext struct {
 }
 
 // NewWideEvent creates a new WideEvent with initialized fields.
-// This should be called once per request in the logging middleware.
+// This should be called once per request in the logging middleware, which calls Release
+// once the event is emitted.
 func NewWideEvent(requestID, method, path, remoteIP, userAgent string) *WideEvent {
+	maps := eventMapsPool.Get().(*eventMaps)
 	return &WideEvent{
 		RequestID:    requestID,
 		Method:       method,
 		Path:         path,
 		RemoteIP:     remoteIP,
 		UserAgent:    userAgent,
-		BusinessData: make(map[string]interface{}),
+		BusinessData: maps.businessData,
+		limits:       GetLimits(),
+		sizes:        maps.sizes,
+		pooled:       maps,
 	}
 }
 
@@ -93,34 +114,18 @@ func (w *WideEvent) SetTraceID(traceID string) {
 // add adds business context to the wide event.
 // Thread-safe: can be called concurrently from multiple goroutines.
 //
-// Memory Safety: If BusinessData exceeds MaxBusinessDataSize, additional
-// entries are silently dropped to prevent unbounded memory growth.
+// Memory Safety: Entries are bounded by the event Limits, see set.
 func (w *WideEvent) add(key string, value interface{}) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	// Check if key already exists (update doesn't count against limit)
-	if _, exists := w.BusinessData[key]; exists {
-		w.BusinessData[key] = value
-		return
-	}
-
-	// Enforce size limit for new entries
-	if w.businessDataLen >= MaxBusinessDataSize {
-		// Silently drop to prevent memory overflow
-		// In production, you might want to log this to metrics
-		return
-	}
-
-	w.BusinessData[key] = value
-	w.businessDataLen++
+	w.set(key, value)
 }
 
 // addMap adds multiple business context fields from a map.
 // Thread-safe: can be called concurrently from multiple goroutines.
 //
-// Memory Safety: Respects MaxBusinessDataSize limit. New entries beyond
-// the limit are silently dropped.
+// Memory Safety: Entries are bounded by the event Limits, see set.
 func (w *WideEvent) addMap(data map[string]any) {
 	if len(data) == 0 {
 		return
@@ -129,28 +134,14 @@ func (w *WideEvent) addMap(data map[string]any) {
 	defer w.mu.Unlock()
 
 	for k, v := range data {
-		// Check if key already exists (update doesn't count against limit)
-		if _, exists := w.BusinessData[k]; exists {
-			w.BusinessData[k] = v
-			continue
-		}
-
-		// Enforce size limit for new entries
-		if w.businessDataLen >= MaxBusinessDataSize {
-			// Stop adding new entries once limit is reached
-			return
-		}
-
-		w.BusinessData[k] = v
-		w.businessDataLen++
+		w.set(k, v)
 	}
 }
 
 // addMany adds multiple business context fields using variadic key-value pairs.
 // Thread-safe: can be called concurrently from multiple goroutines.
 //
-// Memory Safety: Respects MaxBusinessDataSize limit. New entries beyond
-// the limit are silently dropped.
+// Memory Safety: Entries are bounded by the event Limits, see set.
 func (w *WideEvent) addMany(keyValuePairs ...interface{}) {
 	if len(keyValuePairs)%2 != 0 {
 		// Odd number of arguments, ignore the last one
@@ -165,20 +156,7 @@ func (w *WideEvent) addMany(keyValuePairs ...interface{}) {
 
 	for i := 0; i < len(keyValuePairs); i += 2 {
 		if key, ok := keyValuePairs[i].(string); ok {
-			// Check if key already exists (update doesn't count against limit)
-			if _, exists := w.BusinessData[key]; exists {
-				w.BusinessData[key] = keyValuePairs[i+1]
-				continue
-			}
-
-			// Enforce size limit for new entries
-			if w.businessDataLen >= MaxBusinessDataSize {
-				// Stop adding new entries once limit is reached
-				return
-			}
-
-			w.BusinessData[key] = keyValuePairs[i+1]
-			w.businessDataLen++
+			w.set(key, keyValuePairs[i+1])
 		}
 	}
 }
@@ -346,15 +324,21 @@ func AddMap(ctx context.Context, data map[string]any) {
 // If a non-map value is already stored at groupKey it is replaced with a new map.
 //
 // MUST be called with w.mu held.
-// Returns nil if MaxBusinessDataSize has been reached and no slot is available.
+// Returns nil if the key limit has been reached and no slot is available.
 func (w *WideEvent) getOrCreateGroup(groupKey string) map[string]interface{} {
+	if w.released {
+		return nil
+	}
+
 	existing, exists := w.BusinessData[groupKey]
 	if !exists {
-		if w.businessDataLen >= MaxBusinessDataSize {
+		if w.businessDataLen >= w.limits.MaxKeys {
+			w.dropKey()
 			return nil
 		}
 		group := make(map[string]interface{})
 		w.BusinessData[groupKey] = group
+		w.sizes[groupKey] = 0
 		w.businessDataLen++
 		return group
 	}
@@ -367,6 +351,8 @@ func (w *WideEvent) getOrCreateGroup(groupKey string) map[string]interface{} {
 	// The slot was already counted, so businessDataLen stays the same.
 	group := make(map[string]interface{})
 	w.BusinessData[groupKey] = group
+	w.businessDataBytes -= w.sizes[groupKey]
+	w.sizes[groupKey] = 0
 	return group
 }
 
@@ -394,20 +380,20 @@ func (w *WideEvent) AddToKey(groupKey string, args ...interface{}) {
 
 	group := w.getOrCreateGroup(groupKey)
 	if group == nil {
-		return // MaxBusinessDataSize reached
+		return // Key limit reached
 	}
 
 	// Single map argument — bulk-merge all fields.
 	if len(args) == 1 {
 		if m, ok := args[0].(map[string]any); ok {
 			for k, v := range m {
-				group[k] = v
+				w.setInGroup(groupKey, group, k, v)
 			}
 			return
 		}
 		if m, ok := args[0].(map[string]interface{}); ok {
 			for k, v := range m {
-				group[k] = v
+				w.setInGroup(groupKey, group, k, v)
 			}
 			return
 		}
@@ -419,7 +405,7 @@ func (w *WideEvent) AddToKey(groupKey string, args ...interface{}) {
 	}
 	for i := 0; i < len(args); i += 2 {
 		if fieldKey, ok := args[i].(string); ok {
-			group[fieldKey] = args[i+1]
+			w.setInGroup(groupKey, group, fieldKey, args[i+1])
 		}
 	}
 }
@@ -592,3 +578,25 @@ func WithUserID(ctx context.Context, userID string) context.Context {
 func WithTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, TraceIDKey, traceID)
 }
+
+// WithLogger stores a request-scoped logger in context, see FromContext.
+func WithLogger(ctx context.Context, log Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, log)
+}
+
+// FromContext returns the request-scoped logger stored by WithLogger, or L() when there is
+// none. The logging middleware stores one preset with request_id, user_id, and trace_id, so
+// services log with request metadata even through a context.Background() call, and tests can
+// inject a logger (e.g. NewTestLogger) with WithLogger.
+//
+// Example usage:
+//
+//	logger.FromContext(ctx).Warn(ctx, "payment provider slow", logger.Int64("latency_ms", ms))
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if log, ok := ctx.Value(loggerKey).(Logger); ok && log != nil {
+			return log
+		}
+	}
+	return L()
+}
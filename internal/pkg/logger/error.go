@@ -44,10 +44,8 @@ func LogErrorWithMessage(ctx context.Context, msg string, errCtx ErrorContext) c
 	// The middleware will handle the actual logging
 	AddError(ctx, &errCtx)
 
-	// If you need immediate logging (not recommended), use Instance directly:
-	// if Instance != nil {
-	//     Instance.Error(ctx, msg, ...)
-	// }
+	// If you need immediate logging (not recommended), use L() directly:
+	// L().Error(ctx, msg, ...)
 
 	return ctx
 }
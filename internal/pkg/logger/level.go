@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levelKey stores a per-request minimum log level
+const levelKey contextKey = "log_level"
+
+// WithLevel returns a context whose log calls use name ("debug", "info", "warn", "error")
+// as the minimum level instead of the global level, e.g. to get debug logs for a single route.
+// An empty name returns ctx unchanged.
+//
+// Example:
+//
+//	ctx, err := logger.WithLevel(ctx, "debug")
+//	logger.L().Debug(ctx, "emitted even when the global level is info")
+func WithLevel(ctx context.Context, name string) (context.Context, error) {
+	if name == "" {
+		return ctx, nil
+	}
+
+	parsed, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, levelKey, parsed), nil
+}
+
+// levelFromContext returns the per-request level set by WithLevel, if any
+func levelFromContext(ctx context.Context) (zapcore.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	lvl, ok := ctx.Value(levelKey).(zapcore.Level)
+	return lvl, ok
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	global := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	log := newLeveledZapLogger(zap.New(core), global)
+
+	ctx := context.Background()
+	log.Debug(ctx, "filtered by the global level")
+	log.Info(ctx, "global info")
+
+	debugCtx, err := WithLevel(ctx, "debug")
+	require.NoError(t, err)
+	log.Debug(debugCtx, "request debug")
+
+	warnCtx, err := WithLevel(ctx, "warn")
+	require.NoError(t, err)
+	log.Info(warnCtx, "filtered by the request level")
+	log.With(String("k", "v")).Warn(warnCtx, "request warn")
+
+	_, err = WithLevel(ctx, "verbose")
+	assert.Error(t, err)
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"global info", "request debug", "request warn"}, messages)
+}
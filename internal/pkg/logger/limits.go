@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"go-echo-boilerplate/internal/config"
+)
+
+const (
+	// DefaultMaxValueBytes is the default limit on the encoded size of one business data value
+	DefaultMaxValueBytes = 16 * 1024
+
+	// DefaultMaxTotalBytes is the default limit on the encoded size of all business data of an event
+	DefaultMaxTotalBytes = 64 * 1024
+
+	// TruncatedSuffix marks a value that was cut to fit MaxValueBytes
+	TruncatedSuffix = "...(truncated)"
+)
+
+// Limits bound the business data of a wide event so a misbehaving handler can't blow up
+// the size of the log line. A zero field uses its default.
+type Limits struct {
+	MaxKeys       int // top-level keys, defaults to MaxBusinessDataSize; further keys are dropped
+	MaxValueBytes int // encoded size of one value, larger values are truncated
+	MaxTotalBytes int // encoded size of all values; entries that don't fit are dropped
+}
+
+var limits atomic.Pointer[Limits]
+
+// NewLimits converts logger.limits.
+func NewLimits(cfg config.LogLimits) Limits {
+	return Limits{
+		MaxKeys:       cfg.MaxKeys,
+		MaxValueBytes: cfg.MaxValueBytes,
+		MaxTotalBytes: cfg.MaxTotalBytes,
+	}
+}
+
+// truncation counters since startup, see GetTruncationStats
+var (
+	droppedKeys     atomic.Uint64
+	truncatedValues atomic.Uint64
+)
+
+// SetLimits changes the business data limits of wide events created afterwards.
+func SetLimits(l Limits) {
+	if l.MaxKeys <= 0 {
+		l.MaxKeys = MaxBusinessDataSize
+	}
+	if l.MaxValueBytes <= 0 {
+		l.MaxValueBytes = DefaultMaxValueBytes
+	}
+	if l.MaxTotalBytes <= 0 {
+		l.MaxTotalBytes = DefaultMaxTotalBytes
+	}
+	limits.Store(&l)
+}
+
+// GetLimits returns the current business data limits.
+func GetLimits() Limits {
+	if l := limits.Load(); l != nil {
+		return *l
+	}
+	return Limits{MaxKeys: MaxBusinessDataSize, MaxValueBytes: DefaultMaxValueBytes, MaxTotalBytes: DefaultMaxTotalBytes}
+}
+
+// TruncationStats counts business data cut by Limits since startup.
+type TruncationStats struct {
+	DroppedKeys     uint64 `json:"dropped_keys"`
+	TruncatedValues uint64 `json:"truncated_values"`
+}
+
+// GetTruncationStats returns the business data truncation counters.
+func GetTruncationStats() TruncationStats {
+	return TruncationStats{
+		DroppedKeys:     droppedKeys.Load(),
+		TruncatedValues: truncatedValues.Load(),
+	}
+}
+
+// truncation records what was cut from one wide event. It is emitted as
+// business_data_truncated so a truncated line can be told apart from a complete one.
+type truncation struct {
+	DroppedKeys     int `json:"dropped_keys,omitempty"`
+	TruncatedValues int `json:"truncated_values,omitempty"`
+}
+
+func (t truncation) empty() bool {
+	return t.DroppedKeys == 0 && t.TruncatedValues == 0
+}
+
+// fitValue truncates value to at most max encoded bytes.
+// It returns the value to store, its encoded size, and whether it was truncated.
+func fitValue(value any, max int) (any, int, bool) {
+	var encoded string
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value, 8, false
+	case string:
+		encoded = v
+	case []byte:
+		encoded = string(v)
+	case fmt.Stringer:
+		encoded = v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			encoded = fmt.Sprint(v)
+		} else {
+			encoded = string(data)
+		}
+	}
+
+	if len(encoded) <= max {
+		return value, len(encoded), false
+	}
+	return encoded[:max] + TruncatedSuffix, max + len(TruncatedSuffix), true
+}
+
+// set stores one top-level business data entry within the event limits.
+// MUST be called with w.mu held.
+func (w *WideEvent) set(key string, value any) {
+	if w.released {
+		return
+	}
+
+	previous, exists := w.sizes[key]
+	if !exists && w.businessDataLen >= w.limits.MaxKeys {
+		w.dropKey()
+		return
+	}
+
+	value, size, truncated := fitValue(value, w.limits.MaxValueBytes)
+	if w.businessDataBytes-previous+size > w.limits.MaxTotalBytes {
+		w.dropKey()
+		return
+	}
+	if truncated {
+		w.truncated.TruncatedValues++
+		truncatedValues.Add(1)
+	}
+
+	w.BusinessData[key] = value
+	w.sizes[key] = size
+	w.businessDataBytes += size - previous
+	if !exists {
+		w.businessDataLen++
+	}
+}
+
+// setInGroup stores one field of a group created by AddToKey, counting its size against the
+// group's key. MUST be called with w.mu held.
+func (w *WideEvent) setInGroup(groupKey string, group map[string]any, key string, value any) {
+	value, size, truncated := fitValue(value, w.limits.MaxValueBytes)
+	if w.businessDataBytes+size > w.limits.MaxTotalBytes {
+		w.dropKey()
+		return
+	}
+	if truncated {
+		w.truncated.TruncatedValues++
+		truncatedValues.Add(1)
+	}
+
+	group[key] = value
+	w.sizes[groupKey] += size
+	w.businessDataBytes += size
+}
+
+func (w *WideEvent) dropKey() {
+	w.truncated.DroppedKeys++
+	droppedKeys.Add(1)
+}
@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withLimits(t *testing.T, l logger.Limits) {
+	previous := logger.GetLimits()
+	logger.SetLimits(l)
+	t.Cleanup(func() { logger.SetLimits(previous) })
+}
+
+func TestWideEventLimits(t *testing.T) {
+	t.Run("Max Keys", func(t *testing.T) {
+		withLimits(t, logger.Limits{MaxKeys: 2})
+		event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+		before := logger.GetTruncationStats()
+
+		event.Add("a", 1, "b", 2, "c", 3)
+		event.Add("a", 10) // updating an existing key is always allowed
+		event.AddToKey("group", "k", "v")
+
+		assert.Equal(t, map[string]any{"a": 10, "b": 2}, event.GetBusinessData())
+		assert.Equal(t, before.DroppedKeys+2, logger.GetTruncationStats().DroppedKeys)
+	})
+
+	t.Run("Max Value Bytes", func(t *testing.T) {
+		withLimits(t, logger.Limits{MaxValueBytes: 10})
+		event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+		before := logger.GetTruncationStats()
+
+		event.Add("short", "0123456789")
+		event.Add("long", strings.Repeat("x", 100))
+		event.Add("body", map[string]any{"password": strings.Repeat("x", 100)})
+		event.AddToKey("group", "nested", strings.Repeat("y", 100))
+
+		data := event.GetBusinessData()
+		assert.Equal(t, "0123456789", data["short"])
+		assert.Equal(t, "xxxxxxxxxx"+logger.TruncatedSuffix, data["long"])
+		assert.Equal(t, `{"password`+logger.TruncatedSuffix, data["body"])
+		assert.Equal(t, "yyyyyyyyyy"+logger.TruncatedSuffix, data["group"].(map[string]any)["nested"])
+		assert.Equal(t, before.TruncatedValues+3, logger.GetTruncationStats().TruncatedValues)
+
+		var summary any
+		for _, field := range event.Fields(logger.WideEventCore{}, nil) {
+			if field.Key == logger.FieldTruncated {
+				summary = field.Value
+			}
+		}
+		assert.NotNil(t, summary)
+	})
+
+	t.Run("Max Total Bytes", func(t *testing.T) {
+		withLimits(t, logger.Limits{MaxTotalBytes: 20})
+		event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+
+		event.Add("a", "0123456789")
+		event.Add("b", "0123456789")
+		event.Add("c", "0123456789")
+		event.Add("a", "01234") // shrinking frees room
+		event.Add("d", "01234")
+
+		assert.ElementsMatch(t, []string{"a", "b", "d"}, keys(event.GetBusinessData()))
+	})
+
+	t.Run("Within Limits", func(t *testing.T) {
+		event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+		event.Add("a", 1)
+
+		for _, field := range event.Fields(logger.WideEventCore{}, nil) {
+			assert.NotEqual(t, logger.FieldTruncated, field.Key)
+		}
+	})
+}
+
+func keys(data map[string]any) []string {
+	result := make([]string, 0, len(data))
+	for key := range data {
+		result = append(result, key)
+	}
+	return result
+}
@@ -12,14 +12,19 @@ package logger
 
 import (
 	"context"
+	"math"
+	"os"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Logger defines the interface for structured logging.
 // All methods accept a context for automatic extraction of request metadata
 // (request_id, user_id, trace_id) which are automatically included in log output.
+// Implementations must not keep the fields slice after returning, callers may reuse it.
 type Logger interface {
 	// Core logging methods with structured fields and context extraction
 	Debug(ctx context.Context, msg string, fields ...Field)
@@ -39,6 +44,11 @@ type Field struct {
 	Key   string
 	Value interface{}
 	Type  FieldType
+
+	// integer holds the value of Duration, Float64 (IEEE 754 bits), Uint64, and Time
+	// (Unix nanoseconds, with the location in Value) fields, so they don't allocate
+	// by boxing the value in Value.
+	integer int64
 }
 
 // FieldType represents the type of a logging field for type-safe conversion.
@@ -52,6 +62,10 @@ const (
 	FieldTypeBool
 	FieldTypeDuration
 	FieldTypeError
+	FieldTypeTime
+	FieldTypeFloat64
+	FieldTypeUint64
+	FieldTypeStrings
 )
 
 // ============================================================================
@@ -89,9 +103,38 @@ func Error(err error) Field {
 	return Field{Key: "error", Value: err, Type: FieldTypeError}
 }
 
-// Duration creates a duration field.
-func Duration(key string, value interface{}) Field {
-	return Field{Key: key, Value: value, Type: FieldTypeDuration}
+// Duration creates a duration field, encoded like zap.Duration.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: FieldTypeDuration, integer: int64(value)}
+}
+
+// Time creates a time field, encoded like zap.Time.
+func Time(key string, value time.Time) Field {
+	// Times outside the range of Unix nanoseconds are kept as is
+	if value.Before(minUnixNanoTime) || value.After(maxUnixNanoTime) {
+		return Field{Key: key, Value: value, Type: FieldTypeTime}
+	}
+	return Field{Key: key, Value: value.Location(), Type: FieldTypeTime, integer: value.UnixNano()}
+}
+
+var (
+	minUnixNanoTime = time.Unix(0, math.MinInt64)
+	maxUnixNanoTime = time.Unix(0, math.MaxInt64)
+)
+
+// Float64 creates a float64 field.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FieldTypeFloat64, integer: int64(math.Float64bits(value))}
+}
+
+// Uint64 creates a uint64 field.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: FieldTypeUint64, integer: int64(value)}
+}
+
+// Strings creates a string slice field.
+func Strings(key string, value []string) Field {
+	return Field{Key: key, Value: value, Type: FieldTypeStrings}
 }
 
 // ============================================================================
@@ -115,31 +158,61 @@ var zapFieldPool = sync.Pool{
 // ZapLogger wraps zap.Logger to implement the Logger interface.
 type ZapLogger struct {
 	logger *zap.Logger
+
+	// level is the minimum level for contexts without a WithLevel override.
+	// The zap core must then be enabled for every level that can be overridden.
+	level zapcore.LevelEnabler
+
+	// preset are the context field keys already added by WithContext, not extracted again
+	preset map[string]bool
 }
 
 // NewZapLogger creates a new ZapLogger instance.
+// Levels are filtered by the zap core, so WithLevel can only raise its minimum level.
 func NewZapLogger(zapLog *zap.Logger) Logger {
-	return &ZapLogger{logger: zapLog}
+	return &ZapLogger{logger: zapLog, level: zapLog.Core()}
+}
+
+// newLeveledZapLogger creates a ZapLogger filtered by level, whose core accepts every level
+// so WithLevel can lower the minimum level per request.
+func newLeveledZapLogger(zapLog *zap.Logger, level zapcore.LevelEnabler) Logger {
+	return &ZapLogger{logger: zapLog, level: level}
+}
+
+// enabled reports whether lvl should be logged for ctx
+func (z *ZapLogger) enabled(ctx context.Context, lvl zapcore.Level) bool {
+	if override, ok := levelFromContext(ctx); ok {
+		return lvl >= override
+	}
+	return z.level.Enabled(lvl)
 }
 
 // Debug logs a debug message with automatic context extraction.
 func (z *ZapLogger) Debug(ctx context.Context, msg string, fields ...Field) {
-	z.logger.Debug(msg, z.buildFields(ctx, fields)...)
+	if z.enabled(ctx, zapcore.DebugLevel) {
+		z.logger.Debug(msg, z.buildFields(ctx, fields)...)
+	}
 }
 
 // Info logs an info message with automatic context extraction.
 func (z *ZapLogger) Info(ctx context.Context, msg string, fields ...Field) {
-	z.logger.Info(msg, z.buildFields(ctx, fields)...)
+	if z.enabled(ctx, zapcore.InfoLevel) {
+		z.logger.Info(msg, z.buildFields(ctx, fields)...)
+	}
 }
 
 // Warn logs a warning message with automatic context extraction.
 func (z *ZapLogger) Warn(ctx context.Context, msg string, fields ...Field) {
-	z.logger.Warn(msg, z.buildFields(ctx, fields)...)
+	if z.enabled(ctx, zapcore.WarnLevel) {
+		z.logger.Warn(msg, z.buildFields(ctx, fields)...)
+	}
 }
 
 // Error logs an error message with automatic context extraction.
 func (z *ZapLogger) Error(ctx context.Context, msg string, fields ...Field) {
-	z.logger.Error(msg, z.buildFields(ctx, fields)...)
+	if z.enabled(ctx, zapcore.ErrorLevel) {
+		z.logger.Error(msg, z.buildFields(ctx, fields)...)
+	}
 }
 
 // Fatal logs a fatal message and exits with automatic context extraction.
@@ -149,24 +222,48 @@ func (z *ZapLogger) Fatal(ctx context.Context, msg string, fields ...Field) {
 
 // With returns a logger with preset fields.
 func (z *ZapLogger) With(fields ...Field) Logger {
-	return &ZapLogger{logger: z.logger.With(convertFields(fields)...)}
+	return &ZapLogger{logger: z.logger.With(convertFields(fields)...), level: z.level, preset: z.preset}
 }
 
 // WithContext returns a logger with context values extracted and preset.
 // This is useful for creating a logger that always includes request metadata.
 func (z *ZapLogger) WithContext(ctx context.Context) Logger {
-	contextFields := extractContextFields(ctx)
+	contextFields := z.unsetContextFields(ctx)
 	if len(contextFields) == 0 {
 		return z
 	}
-	return &ZapLogger{logger: z.logger.With(contextFields...)}
+
+	preset := make(map[string]bool, len(z.preset)+len(contextFields))
+	for key := range z.preset {
+		preset[key] = true
+	}
+	for _, field := range contextFields {
+		preset[field.Key] = true
+	}
+	return &ZapLogger{logger: z.logger.With(contextFields...), level: z.level, preset: preset}
+}
+
+// unsetContextFields extracts the context fields not already preset by WithContext
+func (z *ZapLogger) unsetContextFields(ctx context.Context) []zap.Field {
+	fields := extractContextFields(ctx)
+	if len(z.preset) == 0 {
+		return fields
+	}
+
+	unset := fields[:0]
+	for _, field := range fields {
+		if !z.preset[field.Key] {
+			unset = append(unset, field)
+		}
+	}
+	return unset
 }
 
 // buildFields combines user-provided fields with context-extracted fields.
 // Uses sync.Pool to reduce allocations in high-throughput scenarios.
 func (z *ZapLogger) buildFields(ctx context.Context, fields []Field) []zap.Field {
 	// Extract context fields (request_id, user_id, trace_id)
-	contextFields := extractContextFields(ctx)
+	contextFields := z.unsetContextFields(ctx)
 
 	// Convert user fields
 	userFields := convertFields(fields)
@@ -246,7 +343,19 @@ func convertFields(fields []Field) []zap.Field {
 				zapFields[i] = zap.Any(f.Key, f.Value)
 			}
 		case FieldTypeDuration:
-			zapFields[i] = zap.Any(f.Key, f.Value)
+			zapFields[i] = zap.Duration(f.Key, time.Duration(f.integer))
+		case FieldTypeTime:
+			if t, ok := f.Value.(time.Time); ok {
+				zapFields[i] = zap.Time(f.Key, t)
+			} else {
+				zapFields[i] = zap.Time(f.Key, time.Unix(0, f.integer).In(f.Value.(*time.Location)))
+			}
+		case FieldTypeFloat64:
+			zapFields[i] = zap.Float64(f.Key, math.Float64frombits(uint64(f.integer)))
+		case FieldTypeUint64:
+			zapFields[i] = zap.Uint64(f.Key, uint64(f.integer))
+		case FieldTypeStrings:
+			zapFields[i] = zap.Strings(f.Key, f.Value.([]string))
 		default:
 			// FieldTypeAny or unknown
 			zapFields[i] = zap.Any(f.Key, f.Value)
@@ -256,5 +365,23 @@ func convertFields(fields []Field) []zap.Field {
 }
 
 // Instance is the global logger instance that implements Logger interface.
-// This is initialized by the Initialize function in zap.go.
-var Instance Logger
+// This is initialized by the Initialize function in zap.go; before that it writes JSON
+// to stderr at info level, so early startup errors (e.g. config errors) are not lost.
+// Prefer L(), which is never nil even if Instance was cleared.
+var Instance Logger = fallback
+
+// fallback is used until Initialize, see Instance
+var fallback = NewZapLogger(zap.New(zapcore.NewCore(
+	zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+	zapcore.Lock(os.Stderr),
+	zapcore.InfoLevel,
+)))
+
+// L returns the global logger. It never returns nil: before Initialize it returns
+// the stderr fallback.
+func L() Logger {
+	if Instance == nil {
+		return fallback
+	}
+	return Instance
+}
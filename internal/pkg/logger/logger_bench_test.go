@@ -2,11 +2,14 @@ package logger_test
 
 import (
 	"context"
+	"io"
 	"testing"
+	"time"
 
 	"go-echo-boilerplate/internal/pkg/logger"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // BenchmarkAdd benchmarks the enrichment operations
@@ -160,3 +163,76 @@ func BenchmarkGetBusinessDataEmpty(b *testing.B) {
 		_ = event.GetBusinessData()
 	}
 }
+
+// BenchmarkTypedFields benchmarks the typed constructors, which don't box their values
+func BenchmarkTypedFields(b *testing.B) {
+	log := logger.NewZapLogger(zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.InfoLevel,
+	)))
+	ctx := context.Background()
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info(ctx, "test message",
+			logger.Duration("elapsed", time.Duration(i)),
+			logger.Time("at", now),
+			logger.Float64("rate", float64(i)),
+			logger.Uint64("bytes", uint64(i)),
+		)
+	}
+}
+
+// BenchmarkAnyFields benchmarks the same fields through Any, for comparison with BenchmarkTypedFields
+func BenchmarkAnyFields(b *testing.B) {
+	log := logger.NewZapLogger(zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.InfoLevel,
+	)))
+	ctx := context.Background()
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info(ctx, "test message",
+			logger.Any("elapsed", time.Duration(i)),
+			logger.Any("at", now),
+			logger.Any("rate", float64(i)),
+			logger.Any("bytes", uint64(i)),
+		)
+	}
+}
+
+// BenchmarkWideEventLifecycle benchmarks one request's wide event: creation, enrichment, and
+// the canonical log line. "released" recycles the maps and the field buffer like the logging
+// middleware does, "unreleased" allocates them for every event.
+func BenchmarkWideEventLifecycle(b *testing.B) {
+	enrich := func(event *logger.WideEvent) {
+		event.Add("user_account_number", "1234567890")
+		event.Add(map[string]any{"response_status": 200, "response_size": 512})
+		event.Add("host", "localhost", "ip", "127.0.0.1")
+		event.AddToKey("db", "query", "users_by_account", "rows", 1)
+	}
+	core := logger.WideEventCore{StatusCode: 200, Outcome: "success", Severity: "INFO"}
+
+	b.Run("unreleased", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			event := logger.NewWideEvent("req-123", "GET", "/api/v1/users/me", "127.0.0.1", "test-agent")
+			enrich(event)
+			_ = event.Fields(core, nil)
+		}
+	})
+
+	b.Run("released", func(b *testing.B) {
+		buffer := make([]logger.Field, 0, 64)
+		b.ReportAllocs()
+		for b.Loop() {
+			event := logger.NewWideEvent("req-123", "GET", "/api/v1/users/me", "127.0.0.1", "test-agent")
+			enrich(event)
+			buffer = event.AppendFields(buffer[:0], core, nil)
+			event.Release()
+		}
+	})
+}
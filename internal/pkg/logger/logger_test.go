@@ -0,0 +1,97 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestL(t *testing.T) {
+	previous := logger.Instance
+	t.Cleanup(func() { logger.Instance = previous })
+
+	assert.NotNil(t, logger.Instance, "usable before Initialize")
+
+	logger.Instance = nil
+	assert.NotNil(t, logger.L())
+	assert.NotPanics(t, func() { logger.L().Debug(context.Background(), "before initialize") })
+
+	test := logger.NewTestLogger()
+	logger.Instance = test
+	logger.L().Info(context.Background(), "after initialize")
+	assert.Len(t, test.EventsWithMessage("after initialize"), 1)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, logger.L(), logger.FromContext(context.Background()), "falls back to the global logger")
+
+	test := logger.NewTestLogger()
+	ctx := logger.WithLogger(context.Background(), test)
+	logger.FromContext(ctx).Info(ctx, "injected")
+	assert.Len(t, test.EventsWithMessage("injected"), 1)
+}
+
+func TestWithContext_PresetFieldsNotDuplicated(t *testing.T) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zapcore.DebugLevel)
+
+	ctx := logger.WithUserID(logger.WithRequestID(context.Background(), "req-1"), "user-1")
+	log := logger.NewZapLogger(zap.New(core)).WithContext(ctx)
+
+	log.Info(ctx, "same context")
+	assert.Equal(t, 1, strings.Count(buf.String(), `"request_id"`))
+	assert.Equal(t, 1, strings.Count(buf.String(), `"user_id"`))
+
+	buf.Reset()
+	log.Info(context.Background(), "preset fields are kept")
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+
+	buf.Reset()
+	log.Info(logger.WithTraceID(ctx, "trace-1"), "new context fields are added")
+	assert.Contains(t, buf.String(), `"trace_id":"trace-1"`)
+	assert.Equal(t, 1, strings.Count(buf.String(), `"request_id"`))
+}
+
+func TestTypedFields(t *testing.T) {
+	encode := func(log func(*zap.Logger)) string {
+		var buf bytes.Buffer
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.TimeKey = "" // entry timestamps differ between the two calls
+		log(zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(&buf), zapcore.DebugLevel)))
+		return buf.String()
+	}
+
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 10, 16, 13, 5, 58, 123456789, jakarta)
+	ancient := time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		field logger.Field
+		want  zap.Field
+	}{
+		{"Duration", logger.Duration("elapsed", 1500*time.Millisecond), zap.Duration("elapsed", 1500*time.Millisecond)},
+		{"Time", logger.Time("at", now), zap.Time("at", now)},
+		{"Time Outside Unix Nanos", logger.Time("at", ancient), zap.Time("at", ancient)},
+		{"Float64", logger.Float64("rate", 0.25), zap.Float64("rate", 0.25)},
+		{"Uint64", logger.Uint64("bytes", math.MaxUint64), zap.Uint64("bytes", math.MaxUint64)},
+		{"Strings", logger.Strings("roles", []string{"admin", "user"}), zap.Strings("roles", []string{"admin", "user"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encode(func(z *zap.Logger) { logger.NewZapLogger(z).Info(context.Background(), "msg", tt.field) })
+			want := encode(func(z *zap.Logger) { z.Info("msg", tt.want) })
+			assert.Equal(t, want, got)
+		})
+	}
+}
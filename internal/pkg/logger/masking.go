@@ -3,14 +3,17 @@
 package logger
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
-	"sync"
+	"sync/atomic"
+
+	"go-echo-boilerplate/internal/config"
 )
 
-// SensitiveFields is a list of field names that should be masked in logs.
-// These are checked case-insensitively.
-var SensitiveFields = []string{
+// defaultSensitiveFields are the field names masked by every Masker.
+// These are checked case-insensitively; logger.masking.sensitive_fields adds to them.
+var defaultSensitiveFields = []string{
 	// Authentication & Authorization
 	"password",
 	"passwd",
@@ -49,6 +52,7 @@ var SensitiveFields = []string{
 	"cvc",
 	"ssn",
 	"social_security",
+	"pii_value", // resolved by POST /admin/pii/resolve
 
 	// AWS & Cloud
 	"aws_secret_access_key",
@@ -68,17 +72,9 @@ var SensitiveFields = []string{
 	"x-session-id",
 }
 
-// sensitiveFieldsMap is a map version of SensitiveFields for O(1) lookups.
-// This is built automatically from SensitiveFields on first use.
-var sensitiveFieldsMap map[string]bool
-var sensitiveFieldsMapInit sync.Once
-
-// initSensitiveFieldsMap builds the map from the slice (called once).
-func initSensitiveFieldsMap() {
-	sensitiveFieldsMap = make(map[string]bool, len(SensitiveFields))
-	for _, field := range SensitiveFields {
-		sensitiveFieldsMap[strings.ToLower(field)] = true
-	}
+// DefaultSensitiveFields returns a copy of the field names masked by default.
+func DefaultSensitiveFields() []string {
+	return append([]string(nil), defaultSensitiveFields...)
 }
 
 const (
@@ -97,21 +93,89 @@ const (
 	MaxMaskingDepth = 10
 )
 
-// isSensitiveField checks if a field name is sensitive (case-insensitive).
-// Uses a map for O(1) lookup performance instead of O(n) slice iteration.
-func isSensitiveField(fieldName string) bool {
-	// Initialize map on first use (thread-safe)
-	sensitiveFieldsMapInit.Do(initSensitiveFieldsMap)
+// Masker masks sensitive data before it is logged: fields by name, values matching
+// patterns, and values at configured paths, each with the strategy of its MaskingPolicy.
+//
+// A Masker is immutable after NewMasker and safe for concurrent use. To change the
+// rules (e.g. on config reload), build a new Masker and swap it in with SetMasker.
+type Masker struct {
+	fields     map[string]bool // lower-case sensitive field names, for exact matches
+	fieldsList []string        // the same names, for substring matches
+	patterns   []MaskPattern
+	paths      [][]string
+	policy     *MaskingPolicy
+	hashKey    string // for hash and tokenize struct tags
+}
+
+// NewMasker builds a Masker from logger.masking. The default sensitive fields are always masked.
+func NewMasker(cfg config.Masking) (*Masker, error) {
+	m := &Masker{fields: make(map[string]bool), hashKey: cfg.HashKey}
+	for _, field := range append(DefaultSensitiveFields(), cfg.SensitiveFields...) {
+		field = strings.ToLower(field)
+		if field != "" && !m.fields[field] {
+			m.fields[field] = true
+			m.fieldsList = append(m.fieldsList, field)
+		}
+	}
+
+	for _, p := range cfg.Patterns {
+		pattern, err := newMaskPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		m.patterns = append(m.patterns, pattern)
+	}
+
+	for _, path := range cfg.Paths {
+		segments, err := parseMaskPath(path)
+		if err != nil {
+			return nil, err
+		}
+		m.paths = append(m.paths, segments)
+	}
+
+	policy, err := newMaskingPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.policy = policy
+
+	return m, nil
+}
+
+// defaultMasker is used by MaskSensitiveData and the *Safe enrichment functions
+var defaultMasker atomic.Pointer[Masker]
+
+func init() {
+	m, _ := NewMasker(config.Masking{})
+	defaultMasker.Store(m)
+}
+
+// DefaultMasker returns the Masker used by the package-level masking functions.
+func DefaultMasker() *Masker {
+	return defaultMasker.Load()
+}
+
+// SetMasker replaces the Masker used by the package-level masking functions.
+// Initialize sets it from logger.masking.
+func SetMasker(m *Masker) {
+	if m != nil {
+		defaultMasker.Store(m)
+	}
+}
 
+// IsSensitive checks if a field name is sensitive (case-insensitive).
+// Uses a map for O(1) lookup performance instead of O(n) slice iteration.
+func (m *Masker) IsSensitive(fieldName string) bool {
 	lowerField := strings.ToLower(fieldName)
 
 	// Check for exact matches first (fastest path)
-	if sensitiveFieldsMap[lowerField] {
+	if m.fields[lowerField] {
 		return true
 	}
 
 	// Check for substring matches (for composite field names like "user_password")
-	for sensitive := range sensitiveFieldsMap {
+	for _, sensitive := range m.fieldsList {
 		if strings.Contains(lowerField, sensitive) {
 			return true
 		}
@@ -120,7 +184,12 @@ func isSensitiveField(fieldName string) bool {
 	return false
 }
 
-// MaskSensitiveData recursively masks sensitive fields in the provided data.
+// MaskSensitiveData masks data with the default Masker, see Masker.Mask.
+func MaskSensitiveData(data interface{}) interface{} {
+	return DefaultMasker().Mask(data)
+}
+
+// Mask recursively masks sensitive fields in the provided data.
 // Supports maps, structs, slices, and primitive types.
 //
 // Memory Safety: Data larger than MaxMaskingDataSize (1MB) will be replaced
@@ -135,14 +204,14 @@ func isSensitiveField(fieldName string) bool {
 //	        "api_key": "sk_live_123",  // Will be masked recursively
 //	    },
 //	}
-//	masked := logger.MaskSensitiveData(data)
-func MaskSensitiveData(data interface{}) interface{} {
+//	masked := masker.Mask(data)
+func (m *Masker) Mask(data interface{}) interface{} {
 	// Estimate size and skip masking if too large
 	if estimatedSize := estimateSize(data); estimatedSize > MaxMaskingDataSize {
 		return "[DATA_TOO_LARGE_TO_MASK]"
 	}
 
-	return maskRecursive(data, 0, MaxMaskingDepth)
+	return m.maskRecursive(data, nil, 0, MaxMaskingDepth)
 }
 
 // estimateSize provides a rough estimate of data size in bytes.
@@ -191,7 +260,8 @@ func estimateSize(data interface{}) int {
 }
 
 // maskRecursive is the internal recursive masking function.
-func maskRecursive(data interface{}, depth, maxDepth int) interface{} {
+// path is the location of data ($ is nil), tracked only when path rules are configured.
+func (m *Masker) maskRecursive(data interface{}, path []string, depth, maxDepth int) interface{} {
 	// Prevent infinite recursion
 	if depth > maxDepth {
 		return data
@@ -201,31 +271,37 @@ func maskRecursive(data interface{}, depth, maxDepth int) interface{} {
 		return nil
 	}
 
+	if m.matchesPath(path) {
+		return MaskString
+	}
+
 	// Handle different types
 	switch v := data.(type) {
+	case string:
+		return m.maskString(v)
 	case map[string]interface{}:
-		return maskMap(v, depth, maxDepth)
+		return m.maskMap(v, path, depth, maxDepth)
 	case map[string]string:
-		return maskStringMap(v)
+		return m.maskStringMap(v, path)
 	case []interface{}:
-		return maskSlice(v, depth, maxDepth)
+		return m.maskSlice(v, path, depth, maxDepth)
 	case []map[string]interface{}:
-		return maskMapSlice(v, depth, maxDepth)
+		return m.maskMapSlice(v, path, depth, maxDepth)
 	default:
 		// For structs and other types, use reflection
-		return maskWithReflection(data, depth, maxDepth)
+		return m.maskWithReflection(data, path, depth, maxDepth)
 	}
 }
 
 // maskMap masks sensitive fields in a map[string]interface{}.
-func maskMap(m map[string]interface{}, depth, maxDepth int) map[string]interface{} {
-	masked := make(map[string]interface{}, len(m))
+func (m *Masker) maskMap(data map[string]interface{}, path []string, depth, maxDepth int) map[string]interface{} {
+	masked := make(map[string]interface{}, len(data))
 
-	for key, value := range m {
-		if isSensitiveField(key) {
-			masked[key] = maskValue(value)
+	for key, value := range data {
+		if maskedValue, ok := m.maskField(key, value, maskValue); ok {
+			masked[key] = maskedValue
 		} else {
-			masked[key] = maskRecursive(value, depth+1, maxDepth)
+			masked[key] = m.maskRecursive(value, m.child(path, key), depth+1, maxDepth)
 		}
 	}
 
@@ -233,14 +309,16 @@ func maskMap(m map[string]interface{}, depth, maxDepth int) map[string]interface
 }
 
 // maskStringMap masks sensitive fields in a map[string]string.
-func maskStringMap(m map[string]string) map[string]string {
-	masked := make(map[string]string, len(m))
+func (m *Masker) maskStringMap(data map[string]string, path []string) map[string]string {
+	masked := make(map[string]string, len(data))
 
-	for key, value := range m {
-		if isSensitiveField(key) {
+	for key, value := range data {
+		if maskedValue, ok := m.maskField(key, value, maskFull); ok {
+			masked[key] = fmt.Sprint(maskedValue)
+		} else if m.matchesPath(m.child(path, key)) {
 			masked[key] = MaskString
 		} else {
-			masked[key] = value
+			masked[key] = m.maskString(value)
 		}
 	}
 
@@ -248,29 +326,31 @@ func maskStringMap(m map[string]string) map[string]string {
 }
 
 // maskSlice masks sensitive data in a slice.
-func maskSlice(s []interface{}, depth, maxDepth int) []interface{} {
+func (m *Masker) maskSlice(s []interface{}, path []string, depth, maxDepth int) []interface{} {
 	masked := make([]interface{}, len(s))
+	itemPath := m.child(path, "[*]")
 
 	for i, item := range s {
-		masked[i] = maskRecursive(item, depth+1, maxDepth)
+		masked[i] = m.maskRecursive(item, itemPath, depth+1, maxDepth)
 	}
 
 	return masked
 }
 
 // maskMapSlice masks sensitive data in a slice of maps.
-func maskMapSlice(s []map[string]interface{}, depth, maxDepth int) []map[string]interface{} {
+func (m *Masker) maskMapSlice(s []map[string]interface{}, path []string, depth, maxDepth int) []map[string]interface{} {
 	masked := make([]map[string]interface{}, len(s))
+	itemPath := m.child(path, "[*]")
 
 	for i, item := range s {
-		masked[i] = maskMap(item, depth+1, maxDepth)
+		masked[i] = m.maskMap(item, itemPath, depth+1, maxDepth)
 	}
 
 	return masked
 }
 
 // maskWithReflection uses reflection to mask struct fields.
-func maskWithReflection(data interface{}, depth, maxDepth int) interface{} {
+func (m *Masker) maskWithReflection(data interface{}, path []string, depth, maxDepth int) interface{} {
 	val := reflect.ValueOf(data)
 
 	// Dereference pointers
@@ -283,18 +363,18 @@ func maskWithReflection(data interface{}, depth, maxDepth int) interface{} {
 
 	switch val.Kind() {
 	case reflect.Struct:
-		return maskStruct(val, depth, maxDepth)
+		return m.maskStruct(val, path, depth, maxDepth)
 	case reflect.Map:
-		return maskReflectMap(val, depth, maxDepth)
+		return m.maskReflectMap(val, path, depth, maxDepth)
 	case reflect.Slice, reflect.Array:
-		return maskReflectSlice(val, depth, maxDepth)
+		return m.maskReflectSlice(val, path, depth, maxDepth)
 	default:
 		return data
 	}
 }
 
 // maskStruct masks sensitive fields in a struct using reflection.
-func maskStruct(val reflect.Value, depth, maxDepth int) map[string]interface{} {
+func (m *Masker) maskStruct(val reflect.Value, path []string, depth, maxDepth int) map[string]interface{} {
 	result := make(map[string]interface{})
 	typ := val.Type()
 
@@ -316,11 +396,21 @@ func maskStruct(val reflect.Value, depth, maxDepth int) map[string]interface{} {
 			}
 		}
 
+		// Struct tags declared by the model take precedence over field-name heuristics
+		omit, strategy := m.tagStrategy(fieldName, field.Tag)
+		if omit {
+			continue
+		}
+		if strategy != nil {
+			result[fieldName] = strategy.Mask(fieldValue.Interface())
+			continue
+		}
+
 		// Check if sensitive
-		if isSensitiveField(fieldName) {
-			result[fieldName] = MaskString
+		if maskedValue, ok := m.maskField(fieldName, fieldValue.Interface(), maskFull); ok {
+			result[fieldName] = maskedValue
 		} else {
-			result[fieldName] = maskRecursive(fieldValue.Interface(), depth+1, maxDepth)
+			result[fieldName] = m.maskRecursive(fieldValue.Interface(), m.child(path, fieldName), depth+1, maxDepth)
 		}
 	}
 
@@ -328,7 +418,7 @@ func maskStruct(val reflect.Value, depth, maxDepth int) map[string]interface{} {
 }
 
 // maskReflectMap masks a map using reflection.
-func maskReflectMap(val reflect.Value, depth, maxDepth int) interface{} {
+func (m *Masker) maskReflectMap(val reflect.Value, path []string, depth, maxDepth int) interface{} {
 	result := make(map[string]interface{})
 
 	iter := val.MapRange()
@@ -343,10 +433,10 @@ func maskReflectMap(val reflect.Value, depth, maxDepth int) interface{} {
 			keyStr = key.String() // Fallback
 		}
 
-		if isSensitiveField(keyStr) {
-			result[keyStr] = MaskString
+		if maskedValue, ok := m.maskField(keyStr, value.Interface(), maskFull); ok {
+			result[keyStr] = maskedValue
 		} else {
-			result[keyStr] = maskRecursive(value.Interface(), depth+1, maxDepth)
+			result[keyStr] = m.maskRecursive(value.Interface(), m.child(path, keyStr), depth+1, maxDepth)
 		}
 	}
 
@@ -354,11 +444,12 @@ func maskReflectMap(val reflect.Value, depth, maxDepth int) interface{} {
 }
 
 // maskReflectSlice masks a slice using reflection.
-func maskReflectSlice(val reflect.Value, depth, maxDepth int) interface{} {
+func (m *Masker) maskReflectSlice(val reflect.Value, path []string, depth, maxDepth int) interface{} {
 	result := make([]interface{}, val.Len())
+	itemPath := m.child(path, "[*]")
 
 	for i := 0; i < val.Len(); i++ {
-		result[i] = maskRecursive(val.Index(i).Interface(), depth+1, maxDepth)
+		result[i] = m.maskRecursive(val.Index(i).Interface(), itemPath, depth+1, maxDepth)
 	}
 
 	return result
@@ -378,29 +469,12 @@ func maskValue(value interface{}) interface{} {
 	return MaskString
 }
 
-// AddSensitiveField adds a custom field name to the sensitive fields list.
-// This allows you to add application-specific sensitive fields.
-//
-// Thread-safe: This function rebuilds the internal map after adding the field.
-//
-// Example:
-//
-//	logger.AddSensitiveField("internal_token")
-//	logger.AddSensitiveField("company_secret")
-func AddSensitiveField(fieldName string) {
-	SensitiveFields = append(SensitiveFields, strings.ToLower(fieldName))
-	// Rebuild the map to include the new field
-	initSensitiveFieldsMap()
-}
-
-// AddSensitiveFields adds multiple custom field names at once.
-func AddSensitiveFields(fieldNames ...string) {
-	for _, name := range fieldNames {
-		AddSensitiveField(name)
-	}
+// maskFull masks a whole value (for sensitive fields).
+func maskFull(value interface{}) interface{} {
+	return MaskString
 }
 
-// MaskHeaders masks sensitive HTTP headers.
+// MaskHeaders masks sensitive HTTP headers with the default Masker.
 // Commonly used for logging request/response headers.
 //
 // Example:
@@ -411,10 +485,15 @@ func AddSensitiveFields(fieldNames ...string) {
 //	}
 //	masked := logger.MaskHeaders(headers)
 func MaskHeaders(headers map[string]string) map[string]string {
-	return maskStringMap(headers)
+	return DefaultMasker().MaskHeaders(headers)
 }
 
-// MaskHeadersInterface masks sensitive HTTP headers (interface{} version).
+// MaskHeadersInterface masks sensitive HTTP headers with the default Masker (interface{} version).
 func MaskHeadersInterface(headers map[string]interface{}) map[string]interface{} {
-	return maskMap(headers, 0, 10)
+	return DefaultMasker().maskMap(headers, nil, 0, 10)
+}
+
+// MaskHeaders masks sensitive HTTP headers.
+func (m *Masker) MaskHeaders(headers map[string]string) map[string]string {
+	return m.maskStringMap(headers, nil)
 }
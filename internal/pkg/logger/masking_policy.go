@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/pii"
+)
+
+// MaskStrategy turns a sensitive value into what is logged.
+type MaskStrategy interface {
+	Mask(value any) any
+}
+
+// FullMask replaces the value with MaskString.
+type FullMask struct{}
+
+// Mask implements MaskStrategy.
+func (FullMask) Mask(value any) any {
+	return MaskString
+}
+
+// PartialMask keeps the first Prefix and last Suffix characters of string values,
+// e.g. Prefix 0, Suffix 4 logs "...***MASKED***...1111" for a card number.
+// Non-string values and strings too short to hide anything are fully masked.
+type PartialMask struct {
+	Prefix int
+	Suffix int
+}
+
+// Mask implements MaskStrategy.
+func (p PartialMask) Mask(value any) any {
+	str, ok := value.(string)
+	if !ok || len(str) <= p.Prefix+p.Suffix {
+		return MaskString
+	}
+
+	masked := str[:p.Prefix] + "..." + MaskString
+	if p.Suffix > 0 {
+		masked += "..." + str[len(str)-p.Suffix:]
+	}
+	return masked
+}
+
+// HashMask replaces the value with a keyed hash, so equal values stay correlatable
+// across log lines without being readable.
+type HashMask struct {
+	Key []byte
+}
+
+// Mask implements MaskStrategy.
+func (h HashMask) Mask(value any) any {
+	mac := hmac.New(sha256.New, h.Key)
+	mac.Write([]byte(fmt.Sprint(value)))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// TokenizeMask replaces every letter and digit with a pseudo-random one derived from a keyed
+// hash, keeping length, case, and separators. Equal values get equal tokens, and the token
+// still looks like the original (an email stays shaped like an email).
+type TokenizeMask struct {
+	Key []byte
+}
+
+// Mask implements MaskStrategy.
+func (t TokenizeMask) Mask(value any) any {
+	str := fmt.Sprint(value)
+
+	mac := hmac.New(sha256.New, t.Key)
+	mac.Write([]byte(str))
+	stream := mac.Sum(nil)
+
+	var b strings.Builder
+	b.Grow(len(str))
+	for i, r := range []rune(str) {
+		n := int(stream[i%len(stream)]) + i/len(stream)
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteByte(byte('0' + n%10))
+		case unicode.IsUpper(r):
+			b.WriteByte(byte('A' + n%26))
+		case unicode.IsLetter(r):
+			b.WriteByte(byte('a' + n%26))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// PIIMask replaces the value with a token of the pii tokenizer, which admins can resolve back
+// with POST /admin/pii/resolve. The value is fully masked when pii.key is not configured.
+type PIIMask struct{}
+
+// Mask implements MaskStrategy.
+func (PIIMask) Mask(value any) any {
+	if tokenizer := pii.Default(); tokenizer != nil {
+		return tokenizer.Mask(value)
+	}
+	return MaskString
+}
+
+// MaskingPolicy chooses the strategy per field name (case-insensitive).
+// Fields listed here are masked even if they are not sensitive by name; other sensitive
+// fields use Default, or the legacy partial/full mask when Default is nil.
+type MaskingPolicy struct {
+	Default MaskStrategy
+	Fields  map[string]MaskStrategy
+}
+
+// strategyFor returns the strategy for a field, nil when the field is not covered by the policy
+func (p *MaskingPolicy) strategyFor(field string, sensitive bool) MaskStrategy {
+	if p == nil {
+		return nil
+	}
+	if strategy, ok := p.Fields[strings.ToLower(field)]; ok {
+		return strategy
+	}
+	if sensitive {
+		return p.Default
+	}
+	return nil
+}
+
+// maskField masks the value of a field that is sensitive or covered by the masking policy.
+// legacy is the mask used for sensitive fields without a policy strategy.
+// ok is false when the field is neither, and the value should be masked recursively instead.
+func (m *Masker) maskField(field string, value any, legacy func(any) any) (masked any, ok bool) {
+	sensitive := m.IsSensitive(field)
+	if strategy := m.policy.strategyFor(field, sensitive); strategy != nil {
+		return strategy.Mask(value), true
+	}
+	if sensitive {
+		return legacy(value), true
+	}
+	return nil, false
+}
+
+// tagStrategy reads the masking struct tags of a field:
+//
+//	Password string `log:"-"`                 // never logged
+//	TaxID    string `log:"mask"`              // the policy strategy for the field (full by default)
+//	Phone    string `mask:"partial,suffix=4"` // the given strategy; plain "partial" keeps 4 leading characters
+//	Email    string `mask:"hash"`             // hash and tokenize use logger.masking.hash_key
+//	SSN      string `mask:"pii"`              // resolvable token, see PIIMask
+//
+// omit is true for log:"-"; strategy is nil when the tags don't mask the field. An invalid
+// mask tag, or hash/tokenize without a key, masks the field fully.
+func (m *Masker) tagStrategy(field string, tag reflect.StructTag) (omit bool, strategy MaskStrategy) {
+	logTag := tag.Get("log")
+	if logTag == "-" {
+		return true, nil
+	}
+
+	if maskTag, ok := tag.Lookup("mask"); ok {
+		cfg, err := parseMaskTag(maskTag)
+		if err != nil {
+			return false, FullMask{}
+		}
+		if strategy, err := NewMaskStrategy(cfg, m.hashKey); err == nil {
+			return false, strategy
+		}
+		return false, FullMask{}
+	}
+
+	if logTag == "mask" {
+		if strategy := m.policy.strategyFor(field, true); strategy != nil {
+			return false, strategy
+		}
+		return false, FullMask{}
+	}
+	return false, nil
+}
+
+// parseMaskTag parses "partial,prefix=2,suffix=4"
+func parseMaskTag(tag string) (config.MaskingStrategy, error) {
+	parts := strings.Split(tag, ",")
+	cfg := config.MaskingStrategy{Strategy: strings.TrimSpace(parts[0])}
+	if cfg.Strategy == "partial" && len(parts) == 1 {
+		cfg.Prefix = PartialMaskPrefix // same as the legacy partial mask
+	}
+
+	for _, option := range parts[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("invalid mask tag option %q", option)
+		}
+		switch name {
+		case "prefix":
+			cfg.Prefix = n
+		case "suffix":
+			cfg.Suffix = n
+		default:
+			return cfg, fmt.Errorf("unknown mask tag option %q", name)
+		}
+	}
+	return cfg, nil
+}
+
+// NewMaskStrategy builds a strategy by name: full, partial, hash, tokenize, or pii.
+// hash and tokenize require a key.
+func NewMaskStrategy(cfg config.MaskingStrategy, key string) (MaskStrategy, error) {
+	switch cfg.Strategy {
+	case "full":
+		return FullMask{}, nil
+	case "partial":
+		return PartialMask{Prefix: cfg.Prefix, Suffix: cfg.Suffix}, nil
+	case "hash", "tokenize":
+		if key == "" {
+			return nil, fmt.Errorf("masking strategy %q requires logger.masking.hash_key", cfg.Strategy)
+		}
+		if cfg.Strategy == "hash" {
+			return HashMask{Key: []byte(key)}, nil
+		}
+		return TokenizeMask{Key: []byte(key)}, nil
+	case "pii":
+		return PIIMask{}, nil
+	default:
+		return nil, fmt.Errorf("unknown masking strategy %q", cfg.Strategy)
+	}
+}
+
+// newMaskingPolicy builds the policy in logger.masking, nil when no strategy is configured
+func newMaskingPolicy(cfg config.Masking) (*MaskingPolicy, error) {
+	if cfg.Default.Strategy == "" && len(cfg.Fields) == 0 {
+		return nil, nil
+	}
+
+	policy := &MaskingPolicy{Fields: make(map[string]MaskStrategy, len(cfg.Fields))}
+	if cfg.Default.Strategy != "" {
+		strategy, err := NewMaskStrategy(cfg.Default, cfg.HashKey)
+		if err != nil {
+			return nil, fmt.Errorf("logger.masking.default: %w", err)
+		}
+		policy.Default = strategy
+	}
+	for field, fieldCfg := range cfg.Fields {
+		strategy, err := NewMaskStrategy(fieldCfg, cfg.HashKey)
+		if err != nil {
+			return nil, fmt.Errorf("logger.masking.fields.%s: %w", field, err)
+		}
+		policy.Fields[strings.ToLower(field)] = strategy
+	}
+	return policy, nil
+}
@@ -0,0 +1,114 @@
+package logger_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/pii"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskStrategies(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	t.Run("Full", func(t *testing.T) {
+		assert.Equal(t, logger.MaskString, logger.FullMask{}.Mask("secret"))
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		assert.Equal(t, "...***MASKED***...1111", logger.PartialMask{Suffix: 4}.Mask("4111111111111111"))
+		assert.Equal(t, "sk_l...***MASKED***", logger.PartialMask{Prefix: 4}.Mask("sk_live_123"))
+		assert.Equal(t, logger.MaskString, logger.PartialMask{Prefix: 2, Suffix: 2}.Mask("abcd"))
+		assert.Equal(t, logger.MaskString, logger.PartialMask{Prefix: 2}.Mask(12345))
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		hash := logger.HashMask{Key: key}
+		first := hash.Mask("alex@example.com")
+
+		assert.Regexp(t, regexp.MustCompile(`^sha256:[0-9a-f]{16}$`), first)
+		assert.Equal(t, first, hash.Mask("alex@example.com"))
+		assert.NotEqual(t, first, hash.Mask("sam@example.com"))
+		assert.NotEqual(t, first, logger.HashMask{Key: []byte("other")}.Mask("alex@example.com"))
+	})
+
+	t.Run("Tokenize", func(t *testing.T) {
+		tokenize := logger.TokenizeMask{Key: key}
+		token := tokenize.Mask("Alex.Doe@example.com").(string)
+
+		assert.Regexp(t, regexp.MustCompile(`^[A-Z][a-z]{3}\.[A-Z][a-z]{2}@[a-z]{7}\.[a-z]{3}$`), token)
+		assert.NotEqual(t, "Alex.Doe@example.com", token)
+		assert.Equal(t, token, tokenize.Mask("Alex.Doe@example.com"))
+		assert.Regexp(t, regexp.MustCompile(`^\d{4}-\d{4}$`), tokenize.Mask("1234-5678"))
+	})
+}
+
+func TestMaskingPolicy(t *testing.T) {
+	withMasker(t, config.Masking{
+		Default: config.MaskingStrategy{Strategy: "full"},
+		Fields: map[string]config.MaskingStrategy{
+			"Email":       {Strategy: "hash"},
+			"card_number": {Strategy: "partial", Suffix: 4},
+		},
+		HashKey: "0123456789abcdef0123456789abcdef",
+	})
+
+	type account struct {
+		Email string `json:"email"`
+	}
+
+	masked := logger.MaskSensitiveData(map[string]any{
+		"email":       "alex@example.com",
+		"card_number": "4111111111111111",
+		"password":    "secret123",
+		"name":        "alex",
+		"account":     account{Email: "alex@example.com"},
+	}).(map[string]any)
+
+	assert.Regexp(t, `^sha256:`, masked["email"])
+	assert.Equal(t, masked["email"], masked["account"].(map[string]any)["email"])
+	assert.Equal(t, "...***MASKED***...1111", masked["card_number"])
+	assert.Equal(t, logger.MaskString, masked["password"]) // default instead of the legacy "secr..." prefix
+	assert.Equal(t, "alex", masked["name"])
+
+	headers := logger.MaskHeaders(map[string]string{"Email": "alex@example.com"})
+	assert.Equal(t, masked["email"], headers["Email"])
+}
+
+func TestNewMaskStrategy(t *testing.T) {
+	_, err := logger.NewMaskStrategy(config.MaskingStrategy{Strategy: "hash"}, "")
+	assert.Error(t, err)
+
+	_, err = logger.NewMaskStrategy(config.MaskingStrategy{Strategy: "scramble"}, "key")
+	assert.Error(t, err)
+
+	strategy, err := logger.NewMaskStrategy(config.MaskingStrategy{Strategy: "tokenize"}, "key")
+	require.NoError(t, err)
+	assert.IsType(t, logger.TokenizeMask{}, strategy)
+}
+
+func TestPIIMask(t *testing.T) {
+	pii.SetDefault(nil)
+	assert.Equal(t, logger.MaskString, logger.PIIMask{}.Mask("jane@example.com"), "fully masked without pii.key")
+
+	tokenizer := pii.New("key", pii.NewMemoryVault(10, time.Hour))
+	pii.SetDefault(tokenizer)
+	t.Cleanup(func() { pii.SetDefault(nil) })
+
+	masker, err := logger.NewMasker(config.Masking{
+		Fields: map[string]config.MaskingStrategy{"email": {Strategy: "pii"}},
+	})
+	require.NoError(t, err)
+
+	masked := masker.Mask(map[string]any{"email": "jane@example.com"}).(map[string]any)
+	assert.Equal(t, tokenizer.Token("jane@example.com"), masked["email"])
+
+	value, ok := tokenizer.Resolve(masked["email"].(string))
+	assert.True(t, ok)
+	assert.Equal(t, "jane@example.com", value)
+}
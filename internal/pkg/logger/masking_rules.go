@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go-echo-boilerplate/internal/config"
+)
+
+// MaskPattern masks every match of Regex in string values, wherever the value is found.
+// Valid, when set, filters the matches (e.g. a Luhn check for card numbers).
+type MaskPattern struct {
+	Name  string
+	Regex *regexp.Regexp
+	Valid func(match string) bool
+}
+
+// builtinMaskPatterns can be enabled by name in logger.masking.patterns.
+var builtinMaskPatterns = map[string]MaskPattern{
+	"credit_card": {
+		Name:  "credit_card",
+		Regex: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Valid: luhnValid,
+	},
+	"jwt": {
+		Name:  "jwt",
+		Regex: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+	"email": {
+		Name:  "email",
+		Regex: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+}
+
+// newMaskPattern builds a pattern of logger.masking.patterns.
+// A pattern without a regex refers to a built-in pattern by name.
+func newMaskPattern(cfg config.MaskingPattern) (MaskPattern, error) {
+	if cfg.Regex == "" {
+		builtin, ok := builtinMaskPatterns[cfg.Name]
+		if !ok {
+			return MaskPattern{}, fmt.Errorf("unknown masking pattern %q", cfg.Name)
+		}
+		return builtin, nil
+	}
+
+	regex, err := regexp.Compile(cfg.Regex)
+	if err != nil {
+		return MaskPattern{}, fmt.Errorf("masking pattern %q: %w", cfg.Name, err)
+	}
+	return MaskPattern{Name: cfg.Name, Regex: regex}, nil
+}
+
+// parseMaskPath splits "$.items[*].card" into ["items", "[*]", "card"].
+// Paths look like $.user.address.* or $.items[*].card_number, where * matches any key
+// and [*] any array element.
+func parseMaskPath(path string) ([]string, error) {
+	if path != "$" && !strings.HasPrefix(path, "$.") {
+		return nil, fmt.Errorf("masking path %q must start with $.", path)
+	}
+
+	var segments []string
+	for _, part := range strings.Split(strings.TrimPrefix(path, "$"), ".")[1:] {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" && rest == "" {
+			return nil, fmt.Errorf("masking path %q has an empty segment", path)
+		}
+		if name != "" {
+			segments = append(segments, name)
+		}
+		if rest != "" {
+			if rest != "*]" {
+				return nil, fmt.Errorf("masking path %q: only [*] is supported for arrays", path)
+			}
+			segments = append(segments, "[*]")
+		}
+	}
+	return segments, nil
+}
+
+// child returns the path of a child value. Paths are only tracked when path rules are configured.
+func (m *Masker) child(path []string, segment string) []string {
+	if len(m.paths) == 0 {
+		return nil
+	}
+	return append(path[:len(path):len(path)], segment)
+}
+
+// matchesPath reports whether the value at path is masked by a path rule
+func (m *Masker) matchesPath(path []string) bool {
+	if path == nil {
+		return false
+	}
+	for _, rule := range m.paths {
+		if len(rule) != len(path) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range rule {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// maskString replaces the pattern matches in s
+func (m *Masker) maskString(s string) string {
+	for _, p := range m.patterns {
+		s = p.Regex.ReplaceAllStringFunc(s, func(match string) string {
+			if p.Valid != nil && !p.Valid(match) {
+				return match
+			}
+			return MaskString
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
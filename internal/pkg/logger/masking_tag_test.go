@@ -0,0 +1,65 @@
+package logger_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type customer struct {
+	Name     string  `json:"name"`
+	Password string  `json:"password" log:"-"`
+	TaxID    string  `json:"tax_id" log:"mask"`
+	Phone    string  `json:"phone" mask:"partial,suffix=4"`
+	Note     string  `json:"note" mask:"partial"`
+	Email    string  `json:"email" mask:"hash"`
+	Card     string  `json:"card" mask:"partial,middle=4"`
+	Address  address `json:"address"`
+}
+
+type address struct {
+	Street string `json:"street" log:"mask"`
+	City   string `json:"city"`
+}
+
+func TestMaskStructTags(t *testing.T) {
+	value := customer{
+		Name:     "alex",
+		Password: "secret123",
+		TaxID:    "12.345.678.9",
+		Phone:    "+628123456789",
+		Note:     "vip customer",
+		Email:    "alex@example.com",
+		Card:     "4111111111111111",
+		Address:  address{Street: "Jl. Sudirman", City: "Jakarta"},
+	}
+
+	t.Run("Without Hash Key", func(t *testing.T) {
+		masked := logger.MaskSensitiveData(&value).(map[string]any)
+
+		assert.Equal(t, "alex", masked["name"])
+		assert.NotContains(t, masked, "password")
+		assert.Equal(t, logger.MaskString, masked["tax_id"])
+		assert.Equal(t, "..."+logger.MaskString+"...6789", masked["phone"])
+		assert.Equal(t, "vip ..."+logger.MaskString, masked["note"])
+		assert.Equal(t, logger.MaskString, masked["email"]) // hash needs logger.masking.hash_key
+		assert.Equal(t, logger.MaskString, masked["card"])  // invalid tag
+		assert.Equal(t, map[string]any{"street": logger.MaskString, "city": "Jakarta"}, masked["address"])
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		masker, err := logger.NewMasker(config.Masking{
+			Fields:  map[string]config.MaskingStrategy{"tax_id": {Strategy: "partial", Suffix: 1}},
+			HashKey: "0123456789abcdef0123456789abcdef",
+		})
+		require.NoError(t, err)
+
+		masked := masker.Mask(value).(map[string]any)
+		assert.Equal(t, "..."+logger.MaskString+"...9", masked["tax_id"])
+		assert.Regexp(t, `^sha256:`, masked["email"])
+	})
+}
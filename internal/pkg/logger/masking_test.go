@@ -0,0 +1,51 @@
+package logger_test
+
+import (
+	"sync"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMasker(t *testing.T) {
+	masker, err := logger.NewMasker(config.Masking{SensitiveFields: []string{"Internal_Ref"}})
+	require.NoError(t, err)
+
+	assert.True(t, masker.IsSensitive("user_password"))
+	assert.True(t, masker.IsSensitive("internal_ref"))
+	assert.False(t, masker.IsSensitive("username"))
+
+	masked := masker.Mask(map[string]any{"internal_ref": "ref-123456", "username": "alex"}).(map[string]any)
+	assert.Equal(t, "ref-..."+logger.MaskString, masked["internal_ref"])
+	assert.Equal(t, "alex", masked["username"])
+
+	// The default masker is unaffected by other instances
+	assert.False(t, logger.DefaultMasker().IsSensitive("internal_ref"))
+	assert.NotContains(t, logger.DefaultSensitiveFields(), "internal_ref")
+}
+
+func TestSetMaskerConcurrent(t *testing.T) {
+	previous := logger.DefaultMasker()
+	t.Cleanup(func() { logger.SetMasker(previous) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			masker, err := logger.NewMasker(config.Masking{SensitiveFields: []string{"internal_ref"}})
+			assert.NoError(t, err)
+			logger.SetMasker(masker)
+		}()
+		go func() {
+			defer wg.Done()
+			masked := logger.MaskSensitiveData(map[string]any{"password": "secret123"}).(map[string]any)
+			assert.NotEqual(t, "secret123", masked["password"])
+		}()
+	}
+	wg.Wait()
+}
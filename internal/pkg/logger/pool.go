@@ -0,0 +1,53 @@
+// Package logger provides structured logging with wide events support.
+// This file recycles the maps of wide events between requests.
+package logger
+
+import "sync"
+
+// maxPooledKeys bounds the maps kept in the pool: a map that grew past it (a raised
+// logger.limits.max_keys) is left to the GC instead of pinning its buckets.
+const maxPooledKeys = 4 * MaxBusinessDataSize
+
+// eventMaps holds the BusinessData and sizes maps of a wide event
+type eventMaps struct {
+	businessData map[string]any
+	sizes        map[string]int
+}
+
+// eventMapsPool recycles the maps of released wide events. The WideEvent itself is never
+// reused: a goroutine still holding a request context after Release would otherwise write
+// into the event of another request.
+var eventMapsPool = sync.Pool{
+	New: func() any {
+		return &eventMaps{
+			businessData: make(map[string]any, 32),
+			sizes:        make(map[string]int, 32),
+		}
+	},
+}
+
+// Release returns the maps of the event to the pool once it has been emitted. The event
+// keeps its request metadata, user, and error; later Add, AddToKey, and SetExtension calls
+// are dropped and the business data reads as empty. Calling Release again does nothing.
+// Thread-safe: can be called concurrently with the other methods.
+func (w *WideEvent) Release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.released {
+		return
+	}
+	w.released = true
+
+	maps := w.pooled
+	w.BusinessData, w.sizes, w.extensions, w.pooled = nil, nil, nil, nil
+	w.businessDataLen, w.businessDataBytes = 0, 0
+
+	if maps == nil || len(maps.businessData) > maxPooledKeys {
+		return
+	}
+	// Clearing drops the references to the request's values, the emitted fields copied them
+	clear(maps.businessData)
+	clear(maps.sizes)
+	eventMapsPool.Put(maps)
+}
@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"sync"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type releaseExtension struct{}
+
+func (releaseExtension) Namespace() string { return "release" }
+
+func TestWideEventRelease(t *testing.T) {
+	t.Run("Late Writes Are Dropped", func(t *testing.T) {
+		event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+		event.Add("before", 1)
+		event.SetUser(&logger.UserContext{ID: "user-1"})
+
+		event.Release()
+		event.Release() // no-op
+		event.Add("after", 2)
+		event.AddToKey("group", "k", "v")
+		event.SetExtension(releaseExtension{})
+
+		assert.Empty(t, event.GetBusinessData())
+		assert.Empty(t, event.GetExtensions())
+		assert.Equal(t, "user-1", event.GetUser().ID, "request metadata is kept")
+
+		for _, field := range event.Fields(logger.WideEventCore{}, nil) {
+			assert.NotContains(t, []string{"before", "after", "group", "release"}, field.Key)
+		}
+	})
+
+	t.Run("Recycled Maps Start Empty", func(t *testing.T) {
+		for range 10 {
+			event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+			event.Add("leaked", true)
+			event.Release()
+		}
+
+		event := logger.NewWideEvent("req-2", "GET", "/", "127.0.0.1", "curl")
+		assert.Empty(t, event.GetBusinessData())
+
+		event.Add("fresh", 1)
+		assert.Equal(t, map[string]any{"fresh": 1}, event.GetBusinessData())
+	})
+
+	t.Run("Concurrent Writers", func(t *testing.T) {
+		event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 100 {
+					event.Add("key", i*j)
+					event.AddToKey("group", "key", j)
+				}
+			}()
+		}
+		event.Release()
+		wg.Wait()
+
+		assert.Empty(t, event.GetBusinessData())
+	})
+}
+
+func TestWideEventAppendFields(t *testing.T) {
+	event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+	event.Add("b", 2, "a", 1)
+	core := logger.WideEventCore{StatusCode: 200}
+
+	buffer := make([]logger.Field, 0, 64)
+	buffer = event.AppendFields(buffer, core, nil)
+
+	assert.Equal(t, event.Fields(core, nil), buffer, "same fields as Fields")
+}
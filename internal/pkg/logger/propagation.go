@@ -0,0 +1,102 @@
+// Package logger provides structured logging with wide events support.
+// This file propagates the correlation context of a wide event to downstream services.
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Correlation headers shared between services. A service receiving them logs the same
+// request_id, trace_id, user, and tenant as its caller, so one request can be followed
+// across services without full tracing.
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceID     = "X-Trace-ID"
+	HeaderUserID      = "X-User-ID"
+	HeaderTenantID    = "X-Tenant-ID"
+	HeaderTraceparent = "traceparent" // W3C Trace Context, read when X-Trace-ID is absent
+)
+
+// Correlation is the context received from an upstream service.
+type Correlation struct {
+	RequestID string
+	TraceID   string
+	UserID    string
+	Tenant    string
+}
+
+// ExtractHeaders reads the correlation headers of an inbound request.
+// The trace ID falls back to the trace-id of a W3C traceparent header.
+func ExtractHeaders(header http.Header) Correlation {
+	c := Correlation{
+		RequestID: header.Get(HeaderRequestID),
+		TraceID:   header.Get(HeaderTraceID),
+		UserID:    header.Get(HeaderUserID),
+		Tenant:    header.Get(HeaderTenantID),
+	}
+	if c.TraceID == "" {
+		c.TraceID = traceparentTraceID(header.Get(HeaderTraceparent))
+	}
+	return c
+}
+
+// InjectHeaders writes the correlation context of ctx to the headers of an outbound request.
+// Empty values are skipped, and headers already set by the caller are kept.
+//
+// Example usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	logger.InjectHeaders(ctx, req.Header)
+func InjectHeaders(ctx context.Context, header http.Header) {
+	traceID := GetTraceID(ctx)
+	if traceID == "" {
+		if event := GetWideEvent(ctx); event != nil {
+			event.mu.RLock()
+			traceID = event.TraceID
+			event.mu.RUnlock()
+		}
+	}
+
+	for key, value := range map[string]string{
+		HeaderRequestID: GetRequestID(ctx),
+		HeaderTraceID:   traceID,
+		HeaderUserID:    GetUserID(ctx),
+		HeaderTenantID:  GetTenant(ctx),
+	} {
+		if value != "" && header.Get(key) == "" {
+			header.Set(key, value)
+		}
+	}
+}
+
+// GetTenant extracts the tenant from context.
+func GetTenant(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenant, ok := ctx.Value(TenantKey).(string); ok {
+		return tenant
+	}
+	return ""
+}
+
+// WithTenant adds the tenant to context.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+
+// traceparentTraceID returns the trace-id of "00-<trace-id>-<parent-id>-<flags>", empty when invalid
+func traceparentTraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	for _, r := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return ""
+		}
+	}
+	return parts[1]
+}
@@ -0,0 +1,53 @@
+package logger_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set(logger.HeaderRequestID, "req-1")
+	header.Set(logger.HeaderUserID, "user-1")
+	header.Set(logger.HeaderTenantID, "acme")
+	header.Set(logger.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	assert.Equal(t, logger.Correlation{
+		RequestID: "req-1",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		UserID:    "user-1",
+		Tenant:    "acme",
+	}, logger.ExtractHeaders(header))
+
+	header.Set(logger.HeaderTraceID, "trace-1")
+	assert.Equal(t, "trace-1", logger.ExtractHeaders(header).TraceID, "X-Trace-ID wins over traceparent")
+
+	for _, invalid := range []string{"garbage", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"} {
+		assert.Empty(t, logger.ExtractHeaders(http.Header{"Traceparent": {invalid}}).TraceID, invalid)
+	}
+}
+
+func TestInjectHeaders(t *testing.T) {
+	event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "test")
+	event.SetTraceID("trace-1")
+
+	ctx := logger.WithWideEvent(context.Background(), event)
+	ctx = logger.WithRequestID(ctx, "req-1")
+	ctx = logger.WithTenant(ctx, "acme")
+
+	header := http.Header{}
+	logger.InjectHeaders(ctx, header)
+
+	assert.Equal(t, "req-1", header.Get(logger.HeaderRequestID))
+	assert.Equal(t, "trace-1", header.Get(logger.HeaderTraceID), "falls back to the wide event trace ID")
+	assert.Equal(t, "acme", header.Get(logger.HeaderTenantID))
+	assert.Empty(t, header.Values(logger.HeaderUserID), "empty values are skipped")
+
+	// A round trip keeps the correlation
+	assert.Equal(t, logger.Correlation{RequestID: "req-1", TraceID: "trace-1", Tenant: "acme"}, logger.ExtractHeaders(header))
+}
@@ -0,0 +1,216 @@
+// Package logger provides structured logging with wide events support.
+// This file defines the schema of the canonical log line emitted for each request.
+package logger
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// WideEventSchemaVersion is emitted as wide_event_schema_version on every canonical log line.
+// Bump it when a core field is renamed, removed, or changes type; adding a field is compatible.
+const WideEventSchemaVersion = 1
+
+// Core field keys of the canonical log line.
+// Business data and extensions cannot use these keys.
+const (
+	FieldSchemaVersion = "wide_event_schema_version"
+	FieldRequestID     = "request_id"
+	FieldTraceID       = "trace_id"
+	FieldMethod        = "method"
+	FieldPath          = "path"
+	FieldStatusCode    = "status_code"
+	FieldDurationMS    = "duration_ms"
+	FieldBytesOut      = "bytes_out"
+	FieldOutcome       = "outcome"
+	FieldSeverity      = "severity"
+	FieldRemoteIP      = "remote_ip"
+	FieldUserAgent     = "user_agent"
+	FieldSampled       = "sampled"
+	FieldSampleRate    = "sample_rate"
+	FieldUser          = "user"
+	FieldError         = "error"
+	FieldTruncated     = "business_data_truncated"
+	FieldSlowRequest   = "slow_request"
+	FieldLargeResponse = "large_response"
+)
+
+var coreFields = map[string]struct{}{
+	FieldSchemaVersion: {},
+	FieldRequestID:     {},
+	FieldTraceID:       {},
+	FieldMethod:        {},
+	FieldPath:          {},
+	FieldStatusCode:    {},
+	FieldDurationMS:    {},
+	FieldBytesOut:      {},
+	FieldOutcome:       {},
+	FieldSeverity:      {},
+	FieldRemoteIP:      {},
+	FieldUserAgent:     {},
+	FieldSampled:       {},
+	FieldSampleRate:    {},
+	FieldUser:          {},
+	FieldError:         {},
+	FieldTruncated:     {},
+	FieldSlowRequest:   {},
+	FieldLargeResponse: {},
+}
+
+// IsCoreField reports whether key is reserved for a core field of the canonical log line.
+func IsCoreField(key string) bool {
+	_, ok := coreFields[key]
+	return ok
+}
+
+// WideEventCore holds the core fields of the canonical log line, set by the logging middleware
+// once the response is written. Their keys and types are the stable contract for log pipelines.
+type WideEventCore struct {
+	StatusCode int
+	DurationMS int64
+	BytesOut   int64
+	Outcome    string // success or error
+	Severity   string // INFO, WARNING, or ERROR
+	SampleRate float64
+
+	// Performance flags, emitted only when set (see logger.performance)
+	SlowRequest   bool
+	LargeResponse bool
+}
+
+// Extension is a typed group of business fields emitted as one object under its namespace,
+// e.g. a PaymentEvent with Namespace() "payment" is emitted as "payment": {...}.
+// Use it instead of loose Add calls for data that dashboards depend on.
+type Extension interface {
+	Namespace() string
+}
+
+// SetExtension stores a typed extension on the wide event, replacing any extension with the
+// same namespace. Extensions whose namespace is a core field key are ignored.
+// Thread-safe: can be called concurrently.
+func (w *WideEvent) SetExtension(ext Extension) {
+	if ext == nil || ext.Namespace() == "" || IsCoreField(ext.Namespace()) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.released {
+		return
+	}
+	if w.extensions == nil {
+		w.extensions = make(map[string]Extension)
+	}
+	w.extensions[ext.Namespace()] = ext
+}
+
+// GetExtensions returns a copy of the typed extensions keyed by namespace.
+// Thread-safe: safe to call concurrently.
+func (w *WideEvent) GetExtensions() map[string]Extension {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	extensions := make(map[string]Extension, len(w.extensions))
+	for namespace, ext := range w.extensions {
+		extensions[namespace] = ext
+	}
+	return extensions
+}
+
+// SetExtension stores a typed extension on the wide event in context.
+// Thread-safe: can be called from any layer.
+//
+// Example usage:
+//
+//	type PaymentEvent struct {
+//	    Provider    string `json:"provider"`
+//	    AmountCents int64  `json:"amount_cents"`
+//	}
+//
+//	func (PaymentEvent) Namespace() string { return "payment" }
+//
+//	logger.SetExtension(ctx, PaymentEvent{Provider: "stripe", AmountCents: 15999})
+func SetExtension(ctx context.Context, ext Extension) {
+	if event := GetWideEvent(ctx); event != nil {
+		event.SetExtension(ext)
+	}
+}
+
+// Fields builds the canonical log line in schema order: version, core fields, user, typed
+// extensions, free-form business data (sorted by key), the truncation summary, and the error.
+// Business data under a core key or an extension namespace is not emitted.
+func (w *WideEvent) Fields(core WideEventCore, errCtx *ErrorContext) []Field {
+	w.mu.RLock()
+	capacity := 16 + len(w.extensions) + w.businessDataLen
+	w.mu.RUnlock()
+
+	return w.AppendFields(make([]Field, 0, capacity), core, errCtx)
+}
+
+// AppendFields appends the fields of Fields to dst and returns the extended slice, so the
+// logging middleware can build every canonical log line in a recycled buffer.
+func (w *WideEvent) AppendFields(dst []Field, core WideEventCore, errCtx *ErrorContext) []Field {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	fields := append(dst,
+		Int(FieldSchemaVersion, WideEventSchemaVersion),
+		String(FieldRequestID, w.RequestID),
+		String(FieldMethod, w.Method),
+		String(FieldPath, w.Path),
+		Int(FieldStatusCode, core.StatusCode),
+		Int64(FieldDurationMS, core.DurationMS),
+		Int64(FieldBytesOut, core.BytesOut),
+		String(FieldOutcome, core.Outcome),
+		String(FieldSeverity, core.Severity),
+		String(FieldRemoteIP, w.RemoteIP),
+		String(FieldUserAgent, w.UserAgent),
+	)
+
+	// Optional core fields
+	if core.SampleRate > 0 && core.SampleRate < 1 {
+		fields = append(fields, Bool(FieldSampled, true), Float64(FieldSampleRate, core.SampleRate))
+	}
+	if core.SlowRequest {
+		fields = append(fields, Bool(FieldSlowRequest, true))
+	}
+	if core.LargeResponse {
+		fields = append(fields, Bool(FieldLargeResponse, true))
+	}
+	if w.TraceID != "" {
+		fields = append(fields, String(FieldTraceID, w.TraceID))
+	}
+	if w.User != nil {
+		fields = append(fields, Any(FieldUser, w.User))
+	}
+
+	// Typed extensions, sorted by namespace in place
+	start := len(fields)
+	for namespace, ext := range w.extensions {
+		fields = append(fields, Any(namespace, ext))
+	}
+	slices.SortFunc(fields[start:], compareFieldKeys)
+
+	// Free-form business data, sorted by key in place
+	start = len(fields)
+	for key, value := range w.BusinessData {
+		if _, isExtension := w.extensions[key]; !isExtension && !IsCoreField(key) {
+			fields = append(fields, Any(key, value))
+		}
+	}
+	slices.SortFunc(fields[start:], compareFieldKeys)
+
+	if !w.truncated.empty() {
+		fields = append(fields, Any(FieldTruncated, w.truncated))
+	}
+	if errCtx != nil {
+		fields = append(fields, Any(FieldError, errCtx))
+	}
+	return fields
+}
+
+func compareFieldKeys(a, b Field) int {
+	return strings.Compare(a.Key, b.Key)
+}
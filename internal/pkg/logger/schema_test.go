@@ -0,0 +1,74 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type paymentEvent struct {
+	Provider    string `json:"provider"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+func (paymentEvent) Namespace() string { return "payment" }
+
+type reservedEvent struct{}
+
+func (reservedEvent) Namespace() string { return "status_code" }
+
+func TestWideEventFields(t *testing.T) {
+	event := logger.NewWideEvent("req-1", "POST", "/api/v1/payments", "127.0.0.1", "curl")
+	ctx := logger.WithWideEvent(context.Background(), event)
+
+	logger.Add(ctx, "zeta", 1, "alpha", 2, "status_code", 999, "payment", "loose")
+	logger.SetExtension(ctx, paymentEvent{Provider: "stripe", AmountCents: 15999})
+	logger.SetExtension(ctx, reservedEvent{})
+
+	fields := event.Fields(logger.WideEventCore{
+		StatusCode: 201,
+		DurationMS: 12,
+		Outcome:    "success",
+		Severity:   "INFO",
+		SampleRate: 1,
+	}, nil)
+
+	keys := make([]string, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, field.Key)
+	}
+	assert.Equal(t, []string{
+		"wide_event_schema_version", "request_id", "method", "path", "status_code", "duration_ms",
+		"bytes_out", "outcome", "severity", "remote_ip", "user_agent", "payment", "alpha", "zeta",
+	}, keys)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger.NewZapLogger(zap.New(core)).Info(context.Background(), "Request completed", fields...)
+
+	entry := logs.All()[0].ContextMap()
+	assert.Equal(t, int64(logger.WideEventSchemaVersion), entry["wide_event_schema_version"])
+	assert.Equal(t, int64(201), entry["status_code"])
+	assert.Equal(t, paymentEvent{Provider: "stripe", AmountCents: 15999}, entry["payment"])
+}
+
+func TestWideEventFieldsOptional(t *testing.T) {
+	event := logger.NewWideEvent("req-1", "GET", "/", "127.0.0.1", "curl")
+	event.SetTraceID("trace-1")
+	event.SetUser(&logger.UserContext{ID: "user-1"})
+
+	fields := event.Fields(logger.WideEventCore{SampleRate: 0.5}, &logger.ErrorContext{Type: "DatabaseError"})
+
+	keys := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keys[field.Key] = true
+	}
+	for _, key := range []string{"sampled", "sample_rate", "trace_id", "user", "error"} {
+		assert.True(t, keys[key], key)
+	}
+}
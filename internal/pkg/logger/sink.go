@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Sink is a log destination. Initialize writes every entry, including the
+// canonical wide events, to all configured sinks (logger.sinks).
+type Sink interface {
+	// Core returns a zap core that writes entries enabled by level to the sink.
+	// It may be called more than once; the cores share the sink's underlying writer.
+	Core(encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core
+
+	// Close flushes buffered entries and releases the sink.
+	Close() error
+}
+
+// sinks are the sinks of the current logger, closed by Close
+var sinks []Sink
+
+// Close writes the queued async entries, then flushes and closes the logger's sinks.
+// Call it once during shutdown.
+func Close() error {
+	if async != nil {
+		async.close()
+		async = nil
+	}
+
+	var firstErr error
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	sinks = nil
+	return firstErr
+}
+
+// NewSinks builds the sinks configured in logger.sinks.
+// console selects the human-readable encoder for stdout sinks.
+func NewSinks(configuration *config.Configuration, console bool) ([]Sink, error) {
+	result := make([]Sink, 0, len(configuration.Logger.Sinks))
+	for i, cfg := range configuration.Logger.Sinks {
+		switch cfg.Type {
+		case "stdout":
+			result = append(result, &StdoutSink{Console: console})
+		case "file":
+			result = append(result, NewFileSink(cfg))
+		case "otlp":
+			sink, err := NewOTLPSink(cfg, configuration.Application.Name, configuration.Application.Version)
+			if err != nil {
+				return nil, fmt.Errorf("logger.sinks[%d]: %w", i, err)
+			}
+			result = append(result, sink)
+		default:
+			return nil, fmt.Errorf("logger.sinks[%d]: unknown sink type %q", i, cfg.Type)
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Stdout
+// ============================================================================
+
+// StdoutSink writes entries to stdout, as JSON or (Console) colored text.
+type StdoutSink struct {
+	Console bool
+}
+
+// Core implements Sink.
+func (s *StdoutSink) Core(encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core {
+	if s.Console {
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		return zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), zapcore.AddSync(os.Stdout), level)
+	}
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(os.Stdout), level)
+}
+
+// Close implements Sink.
+func (s *StdoutSink) Close() error {
+	return nil
+}
+
+// ============================================================================
+// Rotating File
+// ============================================================================
+
+// FileSink writes JSON entries to a file rotated by size.
+type FileSink struct {
+	writer *lumberjack.Logger
+}
+
+// NewFileSink creates a file sink from a logger.sinks entry.
+func NewFileSink(cfg config.LogSink) *FileSink {
+	maxSize := cfg.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = 100
+	}
+
+	return &FileSink{writer: &lumberjack.Logger{
+		Filename:   cfg.Path,
+		MaxSize:    maxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+	}}
+}
+
+// Core implements Sink.
+func (s *FileSink) Core(encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core {
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(s.writer), level)
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	return s.writer.Close()
+}
+
+// defaultFlushInterval is used by batching sinks when flush_interval is not set
+const defaultFlushInterval = 2 * time.Second
@@ -0,0 +1,363 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// otlpMaxQueued caps buffered records (in batches) so a slow collector can't grow memory unbounded
+const otlpMaxQueued = 10
+
+// OTLPSink exports entries to an OpenTelemetry collector over OTLP/HTTP with JSON encoding.
+// Entries are batched and sent every flush interval or when a batch is full.
+// Entries are dropped (and reported on stderr) when the collector is unreachable.
+type OTLPSink struct {
+	endpoint  string
+	headers   map[string]string
+	batchSize int
+	client    *http.Client
+	resource  []otlpKeyValue
+
+	mu      sync.Mutex
+	records []otlpLogRecord
+	dropped int
+
+	flush chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewOTLPSink creates an OTLP sink from a logger.sinks entry and starts its exporter.
+func NewOTLPSink(cfg config.LogSink, serviceName, serviceVersion string) (*OTLPSink, error) {
+	interval := defaultFlushInterval
+	if cfg.FlushInterval != "" {
+		parsed, err := time.ParseDuration(cfg.FlushInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flush_interval %q: %w", cfg.FlushInterval, err)
+		}
+		interval = parsed
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	s := &OTLPSink{
+		endpoint:  cfg.Endpoint,
+		headers:   cfg.Headers,
+		batchSize: batchSize,
+		client:    &http.Client{Timeout: 5 * time.Second},
+		resource: []otlpKeyValue{
+			otlpAttribute("service.name", serviceName),
+			otlpAttribute("service.version", serviceVersion),
+		},
+		flush: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run(interval)
+
+	return s, nil
+}
+
+// Core implements Sink.
+func (s *OTLPSink) Core(_ zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core {
+	return &otlpCore{LevelEnabler: level, sink: s}
+}
+
+// Close stops the exporter after sending the buffered entries.
+func (s *OTLPSink) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		err = s.export()
+	})
+	return err
+}
+
+// enqueue buffers a record, dropping it when the queue is full
+func (s *OTLPSink) enqueue(record otlpLogRecord) {
+	s.mu.Lock()
+	if len(s.records) >= s.batchSize*otlpMaxQueued {
+		s.dropped++
+		s.mu.Unlock()
+		return
+	}
+	s.records = append(s.records, record)
+	full := len(s.records) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run exports batches until Close
+func (s *OTLPSink) run(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.flush:
+		}
+
+		if err := s.export(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: otlp export failed: %v\n", err)
+		}
+	}
+}
+
+// export sends every buffered record, one batch per request
+func (s *OTLPSink) export() error {
+	for {
+		s.mu.Lock()
+		n := min(len(s.records), s.batchSize)
+		batch := append([]otlpLogRecord(nil), s.records[:n]...)
+		s.records = s.records[n:]
+		dropped := s.dropped
+		s.dropped = 0
+		s.mu.Unlock()
+
+		if dropped > 0 {
+			fmt.Fprintf(os.Stderr, "logger: otlp queue full, dropped %d entries\n", dropped)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := s.send(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// send posts one batch to the collector
+func (s *OTLPSink) send(batch []otlpLogRecord) error {
+	payload := otlpExportRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: s.resource},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: "go-echo-boilerplate/logger"},
+			LogRecords: batch,
+		}},
+	}}}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ============================================================================
+// zap core
+// ============================================================================
+
+// otlpCore converts zap entries into OTLP log records
+type otlpCore struct {
+	zapcore.LevelEnabler
+	sink   *OTLPSink
+	fields []zapcore.Field
+}
+
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	return &otlpCore{
+		LevelEnabler: c.LevelEnabler,
+		sink:         c.sink,
+		fields:       append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+func (c *otlpCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *otlpCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	attributes := make([]otlpKeyValue, 0, len(encoder.Fields)+1)
+	for key, value := range encoder.Fields {
+		attributes = append(attributes, otlpKeyValue{Key: key, Value: otlpAnyValue(value)})
+	}
+	if entry.Caller.Defined {
+		attributes = append(attributes, otlpAttribute("code.caller", entry.Caller.TrimmedPath()))
+	}
+
+	c.sink.enqueue(otlpLogRecord{
+		TimeUnixNano:   strconv.FormatInt(entry.Time.UnixNano(), 10),
+		SeverityNumber: otlpSeverity(entry.Level),
+		SeverityText:   entry.Level.CapitalString(),
+		Body:           otlpValue{StringValue: &entry.Message},
+		Attributes:     attributes,
+	})
+	return nil
+}
+
+func (c *otlpCore) Sync() error {
+	return nil
+}
+
+// otlpSeverity maps zap levels to OTLP severity numbers
+func otlpSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel:
+		return 17
+	default:
+		return 21 // fatal and panic levels
+	}
+}
+
+// ============================================================================
+// OTLP/HTTP JSON payload
+// ============================================================================
+
+type (
+	otlpExportRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpLogRecord struct {
+		TimeUnixNano   string         `json:"timeUnixNano"`
+		SeverityNumber int            `json:"severityNumber"`
+		SeverityText   string         `json:"severityText"`
+		Body           otlpValue      `json:"body"`
+		Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+
+	// otlpValue is an OTLP AnyValue; exactly one field is set
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"` // int64 is a string in OTLP JSON
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+func otlpAttribute(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+// otlpAnyValue converts an encoded zap field value; maps, slices, and other
+// complex values are sent as JSON strings
+func otlpAnyValue(value any) otlpValue {
+	switch v := value.(type) {
+	case string:
+		return otlpValue{StringValue: &v}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int:
+		s := strconv.FormatInt(int64(v), 10)
+		return otlpValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpValue{IntValue: &s}
+	case int32:
+		s := strconv.FormatInt(int64(v), 10)
+		return otlpValue{IntValue: &s}
+	case uint64:
+		if v <= math.MaxInt64 {
+			s := strconv.FormatUint(v, 10)
+			return otlpValue{IntValue: &s}
+		}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	case time.Duration:
+		s := v.String()
+		return otlpValue{StringValue: &s}
+	case time.Time:
+		s := v.Format(time.RFC3339Nano)
+		return otlpValue{StringValue: &s}
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		s := fmt.Sprint(value)
+		return otlpValue{StringValue: &s}
+	}
+	s := string(encoded)
+	return otlpValue{StringValue: &s}
+}
@@ -0,0 +1,114 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewSinks(t *testing.T) {
+	sinks, err := logger.NewSinks(&config.Configuration{Logger: config.Logger{Sinks: []config.LogSink{
+		{Type: "stdout"},
+		{Type: "file", Path: filepath.Join(t.TempDir(), "app.log")},
+		{Type: "otlp", Endpoint: "http://localhost:4318/v1/logs"},
+	}}}, false)
+	require.NoError(t, err)
+	assert.Len(t, sinks, 3)
+	for _, sink := range sinks {
+		assert.NoError(t, sink.Close())
+	}
+
+	_, err = logger.NewSinks(&config.Configuration{Logger: config.Logger{Sinks: []config.LogSink{{Type: "kafka"}}}}, false)
+	assert.Error(t, err)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink := logger.NewFileSink(config.LogSink{Path: path})
+
+	log := zap.New(sink.Core(zap.NewProductionEncoderConfig(), zapcore.InfoLevel))
+	log.Debug("filtered")
+	log.Info("Request completed", zap.String("request_id", "req-1"))
+	require.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "req-1", entry["request_id"])
+}
+
+func TestOTLPSink(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		payloads []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]any
+		assert.NoError(t, json.Unmarshal(body, &payload))
+
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink, err := logger.NewOTLPSink(config.LogSink{
+		Endpoint:      server.URL,
+		Headers:       map[string]string{"Authorization": "token"},
+		BatchSize:     2,
+		FlushInterval: "1h",
+	}, "app", "1.0.0")
+	require.NoError(t, err)
+
+	log := logger.NewZapLogger(zap.New(sink.Core(zapcore.EncoderConfig{}, zapcore.InfoLevel)))
+	ctx := context.Background()
+	log.Info(ctx, "first", logger.Int("status_code", 200), logger.Any("user", map[string]string{"id": "1"}))
+	log.Warn(ctx, "second")
+	log.Error(ctx, "third")
+	require.NoError(t, sink.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var messages []string
+	for _, payload := range payloads {
+		resourceLogs := payload["resourceLogs"].([]any)[0].(map[string]any)
+		scopeLogs := resourceLogs["scopeLogs"].([]any)[0].(map[string]any)
+		for _, record := range scopeLogs["logRecords"].([]any) {
+			record := record.(map[string]any)
+			messages = append(messages, record["body"].(map[string]any)["stringValue"].(string))
+
+			if record["severityText"] == "INFO" {
+				assert.Equal(t, float64(9), record["severityNumber"])
+				assert.Contains(t, record["attributes"], map[string]any{"key": "status_code", "value": map[string]any{"intValue": "200"}})
+				assert.Contains(t, record["attributes"], map[string]any{"key": "user", "value": map[string]any{"stringValue": `{"id":"1"}`}})
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"first", "second", "third"}, messages)
+}
@@ -0,0 +1,109 @@
+// Package logger provides structured logging with wide events support.
+// This file provides an in-memory Logger for unit tests.
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestLogger is a Logger that keeps every emitted entry in memory, so tests can assert on
+// log and wide event contents without parsing zap output. It logs every level, and Fatal
+// panics instead of exiting.
+//
+// Example usage:
+//
+//	log := logger.NewTestLogger()
+//	e.Use(m.LoggingMiddleware(log))
+//	e.ServeHTTP(rec, req)
+//
+//	status, _ := log.FieldValue("status_code")
+//	assert.Equal(t, int64(http.StatusOK), status)
+type TestLogger struct {
+	Logger
+	logs *observer.ObservedLogs
+}
+
+// Event is one entry emitted to a TestLogger.
+type Event struct {
+	Level   string // debug, info, warn, error, ...
+	Message string
+	Fields  map[string]any // integers are int64, see zapcore.MapObjectEncoder
+}
+
+// FieldValue returns the value of a field of the event.
+func (e Event) FieldValue(key string) (any, bool) {
+	value, ok := e.Fields[key]
+	return value, ok
+}
+
+// NewTestLogger creates an empty TestLogger.
+func NewTestLogger() *TestLogger {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return &TestLogger{
+		Logger: NewZapLogger(zap.New(core, zap.WithFatalHook(zapcore.WriteThenPanic))),
+		logs:   logs,
+	}
+}
+
+// Events returns the emitted events, oldest first.
+func (t *TestLogger) Events() []Event {
+	entries := t.logs.All()
+	events := make([]Event, len(entries))
+	for i, entry := range entries {
+		events[i] = Event{
+			Level:   entry.Level.String(),
+			Message: entry.Message,
+			Fields:  entry.ContextMap(),
+		}
+	}
+	return events
+}
+
+// EventsWithMessage returns the emitted events with the given message, oldest first.
+func (t *TestLogger) EventsWithMessage(msg string) []Event {
+	var events []Event
+	for _, event := range t.Events() {
+		if event.Message == msg {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// LastEvent returns the most recent event, false when nothing was logged.
+func (t *TestLogger) LastEvent() (Event, bool) {
+	events := t.Events()
+	if len(events) == 0 {
+		return Event{}, false
+	}
+	return events[len(events)-1], true
+}
+
+// LastError returns the most recent event at error level or above, false when there is none.
+func (t *TestLogger) LastError() (Event, bool) {
+	events := t.Events()
+	for i := len(events) - 1; i >= 0; i-- {
+		if level, err := zapcore.ParseLevel(events[i].Level); err == nil && level >= zapcore.ErrorLevel {
+			return events[i], true
+		}
+	}
+	return Event{}, false
+}
+
+// FieldValue returns the value of key in the most recent event that has it.
+func (t *TestLogger) FieldValue(key string) (any, bool) {
+	events := t.Events()
+	for i := len(events) - 1; i >= 0; i-- {
+		if value, ok := events[i].Fields[key]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// Reset discards the emitted events.
+func (t *TestLogger) Reset() {
+	t.logs.TakeAll()
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestLogger(t *testing.T) {
+	log := logger.NewTestLogger()
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+
+	_, ok := log.LastEvent()
+	assert.False(t, ok)
+
+	log.Debug(ctx, "cache miss", logger.String("key", "user:1"))
+	log.Error(ctx, "charge failed", logger.Error(errors.New("card declined")), logger.Int("attempt", 1))
+	log.Info(ctx, "retrying", logger.Int("attempt", 2))
+
+	events := log.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "debug", events[0].Level)
+	assert.Equal(t, "req-1", events[0].Fields["request_id"], "context fields are captured")
+
+	last, ok := log.LastEvent()
+	require.True(t, ok)
+	assert.Equal(t, "retrying", last.Message)
+
+	lastError, ok := log.LastError()
+	require.True(t, ok)
+	assert.Equal(t, "charge failed", lastError.Message)
+	errValue, _ := lastError.FieldValue("error")
+	assert.Equal(t, "card declined", errValue)
+
+	attempt, ok := log.FieldValue("attempt")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), attempt, "the most recent value wins")
+
+	assert.Len(t, log.EventsWithMessage("cache miss"), 1)
+
+	log.Reset()
+	assert.Empty(t, log.Events())
+	_, ok = log.LastError()
+	assert.False(t, ok)
+}
+
+func TestTestLogger_Fatal(t *testing.T) {
+	log := logger.NewTestLogger()
+
+	assert.Panics(t, func() { log.Fatal(context.Background(), "boom") })
+	_, ok := log.LastError()
+	assert.True(t, ok, "fatal counts as an error")
+}
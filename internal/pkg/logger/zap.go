@@ -17,8 +17,6 @@
 package logger
 
 import (
-	"os"
-
 	"go-echo-boilerplate/internal/config"
 
 	"go.uber.org/zap"
@@ -26,9 +24,33 @@ import (
 )
 
 // Log is the raw zap.Logger instance.
-// Use logger.Instance (Logger interface) instead for better abstraction.
+// Use logger.L() (Logger interface) instead for better abstraction.
 var Log *zap.Logger
 
+// level is shared by the logger core so it can be changed at runtime with SetLevel.
+var level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+// SetLevel changes the minimum log level at runtime ("debug", "info", "warn", "error").
+// An empty level is ignored.
+func SetLevel(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	parsed, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+
+	level.SetLevel(parsed)
+	return nil
+}
+
+// GetLevel returns the current minimum log level.
+func GetLevel() string {
+	return level.Level().String()
+}
+
 // Initialize configures and initializes the global logger based on environment.
 // This should be called once at application startup.
 //
@@ -37,11 +59,10 @@ var Log *zap.Logger
 //   - Development: Console encoding with colors, Debug level, human-readable
 //
 // The initialized logger is available via:
-//   - logger.Instance (recommended - uses Logger interface)
+//   - logger.L() (recommended - uses Logger interface)
 //   - logger.Log (raw zap.Logger - for advanced use cases)
 func Initialize(configuration *config.Configuration) {
 	var zapConfig zap.Config
-	var encoder zapcore.Encoder
 
 	// Configure based on environment
 	isProduction := configuration.Application.Environment == "prod" ||
@@ -51,18 +72,26 @@ func Initialize(configuration *config.Configuration) {
 		// Production: JSON encoding for log aggregation systems
 		zapConfig = zap.NewProductionConfig()
 		zapConfig.Encoding = "json"
-		zapConfig.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
-
-		encoder = zapcore.NewJSONEncoder(zapConfig.EncoderConfig)
+		level.SetLevel(zapcore.InfoLevel)
 	} else {
 		// Development: Console encoding with colors for readability
 		zapConfig = zap.NewDevelopmentConfig()
 		zapConfig.Encoding = "console"
-		zapConfig.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
-		zapConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		level.SetLevel(zapcore.DebugLevel)
+	}
 
-		encoder = zapcore.NewConsoleEncoder(zapConfig.EncoderConfig)
+	// logger.level overrides the environment default; an invalid value keeps the default
+	levelErr := SetLevel(configuration.Logger.Level)
+	zapConfig.Level = level
+
+	SetLimits(NewLimits(configuration.Logger.Limits))
+
+	// logger.masking builds the Masker of the *Safe functions; an invalid config masks by the default field names
+	masker, maskingErr := NewMasker(configuration.Logger.Masking)
+	if maskingErr != nil {
+		masker, _ = NewMasker(config.Masking{})
 	}
+	SetMasker(masker)
 
 	// Common configuration for all environments
 	zapConfig.EncoderConfig.TimeKey = "timestamp"
@@ -72,39 +101,78 @@ func Initialize(configuration *config.Configuration) {
 	zapConfig.EncoderConfig.CallerKey = "caller"
 	zapConfig.EncoderConfig.StacktraceKey = "stacktrace"
 
-	// Create core with configured encoder
-	core := zapcore.NewCore(
-		encoder,
-		zapcore.AddSync(os.Stdout),
-		zapConfig.Level,
-	)
+	// Build the configured sinks (stdout by default); the same entry is written to every sink
+	configured, sinkErr := NewSinks(configuration, !isProduction)
+	if sinkErr != nil || len(configured) == 0 {
+		configured = []Sink{&StdoutSink{Console: !isProduction}}
+	}
+	_ = Close() // release the sinks of a previous Initialize
+	sinks = configured
 
-	// Build logger with options
-	Log = zap.New(
-		core,
+	core := teeSinks(zapConfig.EncoderConfig, zapConfig.Level)
+	instanceCore := teeSinks(zapConfig.EncoderConfig, zapcore.DebugLevel)
+
+	// logger.async moves the sink writes off the calling goroutine
+	if configuration.Logger.Async.Enabled {
+		async = newAsyncQueue(configuration.Logger.Async)
+		core = newAsyncCore(core, async)
+		instanceCore = newAsyncCore(instanceCore, async)
+	}
+
+	options := []zap.Option{
 		zap.AddCaller(),                       // Include caller information
 		zap.AddStacktrace(zapcore.ErrorLevel), // Stack traces for errors and above
 		zap.AddCallerSkip(0),                  // No skip for accurate caller info
-	)
+	}
+
+	// Build logger with options
+	Log = zap.New(core, options...)
 
 	// Replace global zap logger (for libraries using zap.L())
 	zap.ReplaceGlobals(Log)
 
-	// Initialize the global Logger interface instance
-	Instance = NewZapLogger(Log)
+	// Initialize the global Logger interface instance. Its core accepts every level and the
+	// level is checked per call instead, so WithLevel can lower it for a single request.
+	Instance = newLeveledZapLogger(
+		zap.New(instanceCore, options...),
+		level,
+	)
+
+	if levelErr != nil {
+		Log.Warn("Invalid logger.level, using environment default", zap.Error(levelErr))
+	}
+	if sinkErr != nil {
+		Log.Warn("Invalid logger.sinks, logging to stdout only", zap.Error(sinkErr))
+	}
+	if maskingErr != nil {
+		Log.Warn("Invalid logger.masking, masking by field name only", zap.Error(maskingErr))
+	}
 
 	// Log initialization message
 	if isProduction {
 		Log.Info("Logger initialized in production mode",
 			zap.String("environment", configuration.Application.Environment),
 			zap.String("encoding", "json"),
-			zap.String("level", "info"),
+			zap.String("level", GetLevel()),
+			zap.Int("sinks", len(sinks)),
+			zap.Bool("async", async != nil),
 		)
 	} else {
 		Log.Info("Logger initialized in development mode",
 			zap.String("environment", configuration.Application.Environment),
 			zap.String("encoding", "console"),
-			zap.String("level", "debug"),
+			zap.String("level", GetLevel()),
+			zap.Int("sinks", len(sinks)),
+			zap.Bool("async", async != nil),
 		)
 	}
 }
+
+// teeSinks combines the cores of every sink into one
+func teeSinks(encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core {
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		cores = append(cores, sink.Core(encoderConfig, level))
+	}
+	return zapcore.NewTee(cores...)
+}
@@ -0,0 +1,365 @@
+// Package loginguard slows down credential guessing on the token endpoint. Failed logins are
+// counted in a sliding window per identifier (email or phone number) and per client IP:
+// after a few free failures each attempt must wait longer than the previous one, past a
+// limit the identifier or IP is locked until the window slides, and a challenge (e.g. a
+// CAPTCHA) can be required in between. It is separate from any generic rate limit: it
+// counts failures, not requests, and keys on the account being guessed.
+//
+// middleware.LoginThrottle checks each attempt and adds an Attempt to the request context;
+// the service reports its outcome with Failed and Succeeded.
+package loginguard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Defaults for the login_throttle settings left unset
+const (
+	DefaultWindow        = 15 * time.Minute
+	DefaultMaxFailures   = 10
+	DefaultMaxIPFailures = 50
+	DefaultFreeFailures  = 3
+	DefaultBaseDelay     = time.Second
+	DefaultMaxDelay      = time.Minute
+
+	// DefaultMaxKeys is the number of identifiers and IPs an in-memory store keeps failures for
+	DefaultMaxKeys = 100_000
+)
+
+// Reasons an attempt is not allowed
+const (
+	ReasonLocked  = "locked"
+	ReasonDelayed = "delayed"
+)
+
+const (
+	// keyPrefix namespaces failures in the shared store
+	keyPrefix     = "loginguard:"
+	failuresField = "failures"
+)
+
+// Policy tunes a Guard. Zero values fall back to the defaults.
+type Policy struct {
+	Window         time.Duration // failures older than this are forgotten
+	MaxFailures    int           // per identifier within the window before it is locked
+	MaxIPFailures  int           // per IP within the window before it is locked
+	FreeFailures   int           // failures before delays start
+	BaseDelay      time.Duration // after the first delayed failure, doubling after each one
+	MaxDelay       time.Duration
+	ChallengeAfter int // failures before a challenge is required; 0 never requires one
+}
+
+// Challenger verifies the answer to a challenge, e.g. a CAPTCHA response token.
+type Challenger interface {
+	Verify(ctx context.Context, response, remoteIP string) (bool, error)
+}
+
+// Decision is the verdict on a login attempt.
+type Decision struct {
+	Allowed    bool
+	Reason     string        // ReasonLocked or ReasonDelayed when not allowed
+	RetryAfter time.Duration // until the next attempt is allowed
+	Failures   int           // the most failures of the identifier or IP within the window
+
+	// ChallengeRequired is set when the attempt must answer a challenge first
+	ChallengeRequired bool
+}
+
+// Guard counts failed logins in a cache.Store. Use a Redis store when more than one process
+// serves logins. Failures are read and written back, not incremented atomically, so
+// concurrent failures of the same key may be undercounted by a few.
+type Guard struct {
+	store      cache.Store
+	policy     Policy
+	challenger Challenger
+	now        func() time.Time
+}
+
+// New creates a Guard. challenger may be nil, in which case required challenges are only
+// reported (Decision.ChallengeRequired) and never enforced.
+func New(store cache.Store, policy Policy, challenger Challenger) *Guard {
+	if policy.Window <= 0 {
+		policy.Window = DefaultWindow
+	}
+	if policy.MaxFailures <= 0 {
+		policy.MaxFailures = DefaultMaxFailures
+	}
+	if policy.MaxIPFailures <= 0 {
+		policy.MaxIPFailures = DefaultMaxIPFailures
+	}
+	if policy.FreeFailures <= 0 {
+		policy.FreeFailures = DefaultFreeFailures
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultBaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultMaxDelay
+	}
+	return &Guard{store: store, policy: policy, challenger: challenger, now: time.Now}
+}
+
+// FromConfig creates a Guard from the login_throttle configuration. It returns nil when
+// login_throttle is disabled.
+func FromConfig(cfg config.LoginThrottle, client *redis.Client) (*Guard, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	policy := Policy{
+		MaxFailures:    cfg.MaxFailures,
+		MaxIPFailures:  cfg.MaxIPFailures,
+		FreeFailures:   cfg.FreeFailures,
+		ChallengeAfter: cfg.ChallengeAfter,
+	}
+	for _, setting := range []struct {
+		key   string
+		value string
+		into  *time.Duration
+	}{
+		{"window", cfg.Window, &policy.Window},
+		{"base_delay", cfg.BaseDelay, &policy.BaseDelay},
+		{"max_delay", cfg.MaxDelay, &policy.MaxDelay},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(setting.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid login_throttle.%s: %w", setting.key, err)
+		}
+		*setting.into = parsed
+	}
+
+	var store cache.Store = cache.NewMemoryStore(DefaultMaxKeys)
+	if cfg.Store == "redis" {
+		if client == nil {
+			return nil, errors.New("login_throttle.store redis requires redis.addr")
+		}
+		store = cache.NewRedisStore(client)
+	}
+	return New(store, policy, nil), nil
+}
+
+// WithChallenger returns a copy of the guard enforcing challenges with challenger.
+func (g *Guard) WithChallenger(challenger Challenger) *Guard {
+	clone := *g
+	clone.challenger = challenger
+	return &clone
+}
+
+// Check decides whether a login attempt for identifier from ip may proceed. An empty
+// identifier or ip is not counted.
+func (g *Guard) Check(ctx context.Context, ip, identifier string) (Decision, error) {
+	now := g.now()
+	decision := Decision{Allowed: true}
+
+	for _, key := range g.keys(ip, identifier) {
+		failures, err := g.failures(ctx, key.name, now)
+		if err != nil {
+			return Decision{Allowed: true}, err
+		}
+		decision.Failures = max(decision.Failures, len(failures))
+
+		if retryAfter, reason := g.wait(failures, key.max, now); retryAfter > decision.RetryAfter {
+			decision.Allowed, decision.Reason, decision.RetryAfter = false, reason, retryAfter
+		}
+	}
+
+	decision.ChallengeRequired = g.policy.ChallengeAfter > 0 && decision.Failures >= g.policy.ChallengeAfter
+	return decision, nil
+}
+
+// VerifyChallenge checks the answer to a required challenge. Without a Challenger every
+// answer passes.
+func (g *Guard) VerifyChallenge(ctx context.Context, response, remoteIP string) (bool, error) {
+	if g.challenger == nil {
+		return true, nil
+	}
+	if response == "" {
+		return false, nil
+	}
+	return g.challenger.Verify(ctx, response, remoteIP)
+}
+
+// Fail counts a failed login for identifier and ip.
+func (g *Guard) Fail(ctx context.Context, ip, identifier string) error {
+	now := g.now()
+	var errs []error
+	for _, key := range g.keys(ip, identifier) {
+		failures, err := g.failures(ctx, key.name, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Only the most recent failures matter, keep enough to decide the lock
+		failures = append(failures, now)
+		if len(failures) > key.max {
+			failures = failures[len(failures)-key.max:]
+		}
+		if err := g.store.Set(ctx, key.name, failuresField, encode(failures), g.policy.Window); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Succeed forgets the failures of identifier, whose owner just proved they know the password.
+// The failures of the IP are kept: one known account must not unlock guessing others.
+func (g *Guard) Succeed(ctx context.Context, identifier string) error {
+	if identifier == "" {
+		return nil
+	}
+	return g.store.Delete(ctx, identifierKey(identifier))
+}
+
+type guardKey struct {
+	name string
+	max  int
+}
+
+func (g *Guard) keys(ip, identifier string) []guardKey {
+	keys := make([]guardKey, 0, 2)
+	if identifier != "" {
+		keys = append(keys, guardKey{identifierKey(identifier), g.policy.MaxFailures})
+	}
+	if ip != "" {
+		keys = append(keys, guardKey{keyPrefix + "ip:" + ip, g.policy.MaxIPFailures})
+	}
+	return keys
+}
+
+// identifierKey hashes the identifier, so the store never holds email addresses or phone numbers
+func identifierKey(identifier string) string {
+	sum := sha256.Sum256([]byte(identifier))
+	return keyPrefix + "id:" + hex.EncodeToString(sum[:16])
+}
+
+// wait returns how long the next attempt must wait after failures, and why
+func (g *Guard) wait(failures []time.Time, limit int, now time.Time) (time.Duration, string) {
+	count := len(failures)
+	if count >= limit {
+		// Locked until enough failures leave the window to get below the limit
+		return failures[count-limit].Add(g.policy.Window).Sub(now), ReasonLocked
+	}
+	if count <= g.policy.FreeFailures {
+		return 0, ""
+	}
+
+	delay := g.policy.MaxDelay
+	if shift := count - g.policy.FreeFailures - 1; shift < 32 {
+		delay = min(g.policy.BaseDelay<<shift, g.policy.MaxDelay)
+	}
+	if remaining := failures[count-1].Add(delay).Sub(now); remaining > 0 {
+		return remaining, ReasonDelayed
+	}
+	return 0, ""
+}
+
+// failures returns the failures of key within the window, oldest first
+func (g *Guard) failures(ctx context.Context, key string, now time.Time) ([]time.Time, error) {
+	value, ok, err := g.store.Get(ctx, key, failuresField)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	cutoff := now.Add(-g.policy.Window)
+	var failures []time.Time
+	for _, field := range strings.Fields(string(value)) {
+		millis, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		if at := time.UnixMilli(millis); at.After(cutoff) {
+			failures = append(failures, at)
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Before(failures[j]) })
+	return failures, nil
+}
+
+func encode(failures []time.Time) []byte {
+	fields := make([]string, len(failures))
+	for i, at := range failures {
+		fields[i] = strconv.FormatInt(at.UnixMilli(), 10)
+	}
+	return []byte(strings.Join(fields, " "))
+}
+
+// NormalizeIdentifier returns the identifier failures are counted for: the trimmed,
+// lowercased email, or else the digits of the phone number.
+func NormalizeIdentifier(email, phoneNumber string) string {
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		return email
+	}
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, phoneNumber)
+}
+
+// Attempt is a login attempt checked by the guard, whose outcome is still unknown.
+type Attempt struct {
+	guard      *Guard
+	ip         string
+	identifier string
+}
+
+// NewAttempt creates the attempt for identifier (see NormalizeIdentifier) from ip.
+func (g *Guard) NewAttempt(ip, identifier string) *Attempt {
+	return &Attempt{guard: g, ip: ip, identifier: identifier}
+}
+
+type contextKey struct{}
+
+// WithAttempt returns a copy of ctx carrying the attempt.
+func WithAttempt(ctx context.Context, attempt *Attempt) context.Context {
+	return context.WithValue(ctx, contextKey{}, attempt)
+}
+
+// Failed counts the attempt of ctx as failed. It is a no-op when ctx has no attempt.
+func Failed(ctx context.Context) error {
+	attempt, ok := ctx.Value(contextKey{}).(*Attempt)
+	if !ok {
+		return nil
+	}
+	return attempt.guard.Fail(ctx, attempt.ip, attempt.identifier)
+}
+
+// Succeeded forgets the failures of the identifier of the attempt of ctx. It is a no-op
+// when ctx has no attempt.
+func Succeeded(ctx context.Context) error {
+	attempt, ok := ctx.Value(contextKey{}).(*Attempt)
+	if !ok {
+		return nil
+	}
+	return attempt.guard.Succeed(ctx, attempt.identifier)
+}
+
+var current atomic.Pointer[Guard]
+
+// SetDefault installs the guard used by middleware.LoginThrottle; nil disables it.
+func SetDefault(g *Guard) {
+	current.Store(g)
+}
+
+// Default returns the installed guard, nil when none is installed.
+func Default() *Guard {
+	return current.Load()
+}
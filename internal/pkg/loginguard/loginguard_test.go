@@ -0,0 +1,197 @@
+package loginguard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGuard(policy Policy) (*Guard, *time.Time) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	guard := New(cache.NewMemoryStore(100), policy, nil)
+	guard.now = func() time.Time { return now }
+	return guard, &now
+}
+
+func failTimes(t *testing.T, guard *Guard, ip, identifier string, times int) {
+	t.Helper()
+	for range times {
+		require.NoError(t, guard.Fail(context.Background(), ip, identifier))
+	}
+}
+
+func TestGuard_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Delays Double After The Free Failures", func(t *testing.T) {
+		guard, now := newTestGuard(Policy{FreeFailures: 2, BaseDelay: time.Second, MaxDelay: 3 * time.Second})
+
+		failTimes(t, guard, "10.0.0.1", "a@example.com", 2)
+		decision, err := guard.Check(ctx, "10.0.0.1", "a@example.com")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 2, decision.Failures)
+
+		for _, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+			failTimes(t, guard, "10.0.0.1", "a@example.com", 1)
+			decision, err = guard.Check(ctx, "10.0.0.1", "a@example.com")
+			require.NoError(t, err)
+			assert.False(t, decision.Allowed)
+			assert.Equal(t, ReasonDelayed, decision.Reason)
+			assert.Equal(t, want, decision.RetryAfter)
+
+			*now = now.Add(want)
+			decision, err = guard.Check(ctx, "10.0.0.1", "a@example.com")
+			require.NoError(t, err)
+			assert.True(t, decision.Allowed)
+		}
+	})
+
+	t.Run("Locks Until Failures Leave The Window", func(t *testing.T) {
+		guard, now := newTestGuard(Policy{Window: time.Minute, MaxFailures: 3, FreeFailures: 5})
+
+		failTimes(t, guard, "", "a@example.com", 1)
+		*now = now.Add(10 * time.Second)
+		failTimes(t, guard, "", "a@example.com", 2)
+
+		decision, err := guard.Check(ctx, "", "a@example.com")
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, ReasonLocked, decision.Reason)
+		assert.Equal(t, 50*time.Second, decision.RetryAfter)
+
+		*now = now.Add(50 * time.Second)
+		decision, err = guard.Check(ctx, "", "a@example.com")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 2, decision.Failures)
+	})
+
+	t.Run("Counts Identifier And IP Separately", func(t *testing.T) {
+		guard, _ := newTestGuard(Policy{MaxFailures: 2, MaxIPFailures: 3, FreeFailures: 5})
+
+		failTimes(t, guard, "10.0.0.1", "a@example.com", 1)
+		failTimes(t, guard, "10.0.0.1", "b@example.com", 1)
+
+		decision, err := guard.Check(ctx, "10.0.0.2", "a@example.com")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+
+		failTimes(t, guard, "10.0.0.1", "c@example.com", 1)
+		decision, err = guard.Check(ctx, "10.0.0.1", "d@example.com")
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed, "the IP reached its limit")
+
+		failTimes(t, guard, "10.0.0.2", "a@example.com", 1)
+		decision, err = guard.Check(ctx, "10.0.0.3", "a@example.com")
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed, "the identifier reached its limit")
+	})
+
+	t.Run("Requires A Challenge", func(t *testing.T) {
+		guard, _ := newTestGuard(Policy{FreeFailures: 5, ChallengeAfter: 2})
+
+		failTimes(t, guard, "10.0.0.1", "a@example.com", 1)
+		decision, err := guard.Check(ctx, "10.0.0.1", "a@example.com")
+		require.NoError(t, err)
+		assert.False(t, decision.ChallengeRequired)
+
+		failTimes(t, guard, "10.0.0.1", "a@example.com", 1)
+		decision, err = guard.Check(ctx, "10.0.0.1", "a@example.com")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.True(t, decision.ChallengeRequired)
+	})
+}
+
+func TestGuard_Succeed(t *testing.T) {
+	ctx := context.Background()
+	guard, _ := newTestGuard(Policy{MaxFailures: 2, MaxIPFailures: 2, FreeFailures: 5})
+
+	failTimes(t, guard, "10.0.0.1", "a@example.com", 2)
+	require.NoError(t, guard.Succeed(ctx, "a@example.com"))
+
+	decision, err := guard.Check(ctx, "10.0.0.2", "a@example.com")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Zero(t, decision.Failures)
+
+	decision, err = guard.Check(ctx, "10.0.0.1", "b@example.com")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "the IP keeps its failures")
+}
+
+type stubChallenger bool
+
+func (c stubChallenger) Verify(context.Context, string, string) (bool, error) {
+	return bool(c), nil
+}
+
+func TestGuard_VerifyChallenge(t *testing.T) {
+	ctx := context.Background()
+	guard, _ := newTestGuard(Policy{})
+
+	passed, err := guard.VerifyChallenge(ctx, "", "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, passed, "without a challenger every answer passes")
+
+	enforced := guard.WithChallenger(stubChallenger(true))
+	passed, err = enforced.VerifyChallenge(ctx, "", "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, passed, "an empty answer never passes")
+
+	passed, err = enforced.VerifyChallenge(ctx, "token", "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, passed)
+}
+
+func TestAttempt(t *testing.T) {
+	ctx := context.Background()
+	guard, _ := newTestGuard(Policy{})
+
+	require.NoError(t, Failed(ctx), "no attempt in ctx")
+	require.NoError(t, Succeeded(ctx), "no attempt in ctx")
+
+	ctx = WithAttempt(ctx, guard.NewAttempt("10.0.0.1", "a@example.com"))
+	require.NoError(t, Failed(ctx))
+
+	decision, err := guard.Check(ctx, "10.0.0.1", "a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, decision.Failures)
+
+	require.NoError(t, Succeeded(ctx))
+	decision, err = guard.Check(ctx, "", "a@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, decision.Failures)
+}
+
+func TestNormalizeIdentifier(t *testing.T) {
+	assert.Equal(t, "john.doe@example.com", NormalizeIdentifier(" John.Doe@Example.com ", "6281234567890"))
+	assert.Equal(t, "6281234567890", NormalizeIdentifier("", "+62 812-3456-7890"))
+	assert.Empty(t, NormalizeIdentifier("", ""))
+}
+
+func TestFromConfig(t *testing.T) {
+	guard, err := FromConfig(config.LoginThrottle{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, guard)
+
+	guard, err = FromConfig(config.LoginThrottle{Enabled: true, Window: "5m", MaxFailures: 4}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, guard)
+	assert.Equal(t, 5*time.Minute, guard.policy.Window)
+	assert.Equal(t, 4, guard.policy.MaxFailures)
+	assert.Equal(t, DefaultBaseDelay, guard.policy.BaseDelay)
+
+	_, err = FromConfig(config.LoginThrottle{Enabled: true, Store: "redis"}, nil)
+	assert.Error(t, err)
+
+	_, err = FromConfig(config.LoginThrottle{Enabled: true, MaxDelay: "soon"}, nil)
+	assert.ErrorContains(t, err, "login_throttle.max_delay")
+}
@@ -0,0 +1,229 @@
+// Package templates renders the HTML emails from html/template files embedded in the binary.
+//
+// The files are laid out as:
+//
+//	email/layouts/*.html   shared by every email: the "layout" it is rendered in, and partials
+//	email/<locale>/*.html  one email per event, e.g. en/login.suspicious.html
+//	email/previews.json    sample variables of each event, for Preview
+//
+// An email defines its "subject" and "content", and may define the plain "text" version and
+// redefine any template of the layouts. Emails missing in a locale fall back to the
+// i18n.DefaultLocale one, and the layouts translate their strings with {{t "key"}}.
+//
+// Example:
+//
+//	emails, _ := templates.New()
+//	queue := notify.NewQueue(channels, notify.QueueOptions{Templates: emails})
+//	queue.Enqueue(ctx, notify.Message{Event: "login.suspicious", Locale: "id", Vars: vars})
+package templates
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/notify"
+)
+
+//go:embed email
+var files embed.FS
+
+// layouts is the directory of the templates shared by every email
+const layouts = "layouts"
+
+// Email is a rendered email.
+type Email struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text,omitempty"` // empty when the email has no plain text version
+	HTML    string `json:"html"`
+}
+
+// Templates renders the emails of the events it has a template for.
+type Templates struct {
+	emails   map[i18n.Locale]map[string]*email // by locale, then event
+	previews map[string]map[string]any         // sample variables by event
+}
+
+// email is the template of an event in a locale, parsed twice: as HTML for its layout, and as
+// text for its subject and plain text, which must not be HTML-escaped
+type email struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// New parses the templates embedded in the binary.
+func New() (*Templates, error) {
+	root, err := fs.Sub(files, "email")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(root)
+}
+
+// Parse parses the templates of fsys, laid out like the embedded ones.
+func Parse(fsys fs.FS) (*Templates, error) {
+	t := &Templates{
+		emails:   make(map[i18n.Locale]map[string]*email),
+		previews: make(map[string]map[string]any),
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == layouts {
+			continue
+		}
+
+		locale := i18n.Locale(entry.Name())
+		names, err := fs.Glob(fsys, path.Join(entry.Name(), "*.html"))
+		if err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+
+		t.emails[locale] = make(map[string]*email, len(names))
+		for _, name := range names {
+			parsed, err := parse(fsys, locale, name)
+			if err != nil {
+				return nil, err
+			}
+			t.emails[locale][strings.TrimSuffix(path.Base(name), ".html")] = parsed
+		}
+	}
+
+	previews, err := fs.ReadFile(fsys, "previews.json")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(previews, &t.previews); err != nil {
+			return nil, fmt.Errorf("templates: invalid previews.json: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// parse parses the email at name with the layouts
+func parse(fsys fs.FS, locale i18n.Locale, name string) (*email, error) {
+	translate := func(key string, args ...any) string {
+		return i18n.T(locale, key, args...)
+	}
+
+	html := htmltemplate.New(path.Base(name)).
+		Option("missingkey=error").
+		Funcs(htmltemplate.FuncMap{"t": translate})
+	layoutFiles, err := fs.Glob(fsys, path.Join(layouts, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	// The email comes last so it can redefine the templates of the layouts
+	if _, err := html.ParseFS(fsys, append(layoutFiles, name)...); err != nil {
+		return nil, fmt.Errorf("templates: invalid %s: %w", name, err)
+	}
+	for _, required := range []string{"layout", "subject", "content"} {
+		if html.Lookup(required) == nil {
+			return nil, fmt.Errorf("templates: %s defines no %q template", name, required)
+		}
+	}
+
+	text, err := texttemplate.New(path.Base(name)).
+		Option("missingkey=error").
+		Funcs(texttemplate.FuncMap{"t": translate}).
+		ParseFS(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("templates: invalid %s: %w", name, err)
+	}
+	return &email{html: html, text: text}, nil
+}
+
+// Email renders the email of the event in locale with vars, reporting false when there is no
+// template for the event.
+func (t *Templates) Email(locale i18n.Locale, event string, vars map[string]any) (Email, bool, error) {
+	parsed, ok := t.emails[locale][event]
+	if !ok {
+		parsed, ok = t.emails[i18n.DefaultLocale][event]
+	}
+	if !ok {
+		return Email{}, false, nil
+	}
+
+	var rendered Email
+	var subject, text, html strings.Builder
+	if err := parsed.text.ExecuteTemplate(&subject, "subject", vars); err != nil {
+		return rendered, true, fmt.Errorf("templates: failed to render the subject of %q: %w", event, err)
+	}
+	if parsed.text.Lookup("text") != nil {
+		if err := parsed.text.ExecuteTemplate(&text, "text", vars); err != nil {
+			return rendered, true, fmt.Errorf("templates: failed to render the text of %q: %w", event, err)
+		}
+	}
+	if err := parsed.html.ExecuteTemplate(&html, "layout", vars); err != nil {
+		return rendered, true, fmt.Errorf("templates: failed to render the HTML of %q: %w", event, err)
+	}
+
+	// A header value stays on one line
+	rendered.Subject = strings.Join(strings.Fields(subject.String()), " ")
+	rendered.Text = text.String()
+	rendered.HTML = html.String()
+	return rendered, true, nil
+}
+
+// Render implements notify.Renderer, filling the parts left empty in an email message from the
+// template of its Event and Locale. Messages without a recipient or a template for their event
+// are returned as they are.
+func (t *Templates) Render(message notify.Message) (notify.Message, error) {
+	if message.Recipient == "" {
+		return message, nil
+	}
+
+	rendered, ok, err := t.Email(i18n.Locale(message.Locale), message.Event, message.Vars)
+	if err != nil || !ok {
+		return message, err
+	}
+	if message.Subject == "" {
+		message.Subject = rendered.Subject
+	}
+	if message.Text == "" {
+		message.Text = rendered.Text
+	}
+	if message.HTML == "" {
+		message.HTML = rendered.HTML
+	}
+	return message, nil
+}
+
+// Events returns the sorted events with a template in any locale.
+func (t *Templates) Events() []string {
+	var events []string
+	for _, emails := range t.emails {
+		for event := range emails {
+			if !slices.Contains(events, event) {
+				events = append(events, event)
+			}
+		}
+	}
+	slices.Sort(events)
+	return events
+}
+
+// Preview renders the email of the event in locale with the sample variables of
+// previews.json, replaced by those of vars.
+func (t *Templates) Preview(locale i18n.Locale, event string, vars map[string]string) (Email, bool, error) {
+	sample := make(map[string]any, len(t.previews[event])+len(vars))
+	for key, value := range t.previews[event] {
+		sample[key] = value
+	}
+	for key, value := range vars {
+		sample[key] = value
+	}
+	return t.Email(locale, event, sample)
+}
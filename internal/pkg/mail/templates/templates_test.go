@@ -0,0 +1,103 @@
+package templates_test
+
+import (
+	"html"
+	"testing"
+	"testing/fstest"
+
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/mail/templates"
+	"go-echo-boilerplate/internal/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedded(t *testing.T) {
+	emails, err := templates.New()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email_change.completed", "email_change.confirm", "login.suspicious"}, emails.Events())
+
+	for _, locale := range []i18n.Locale{i18n.English, i18n.Indonesian} {
+		for _, event := range emails.Events() {
+			email, ok, err := emails.Preview(locale, event, nil)
+			require.NoError(t, err, "%s %s", locale, event)
+			assert.True(t, ok)
+			assert.NotEmpty(t, email.Subject)
+			assert.NotEmpty(t, email.Text)
+			assert.Contains(t, email.HTML, `<html lang="`+string(locale)+`">`)
+			assert.Contains(t, email.HTML, html.EscapeString(i18n.T(locale, "email.footer")), "the layout is translated")
+		}
+	}
+
+	email, _, err := emails.Preview(i18n.Indonesian, "email_change.confirm", map[string]string{"link": "https://example.com/confirm?token=abc"})
+	require.NoError(t, err)
+	assert.Contains(t, email.HTML, `href="https://example.com/confirm?token=abc"`)
+	assert.Contains(t, email.HTML, "Konfirmasi perubahan", "the email redefines the action of the button")
+}
+
+func TestParse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/default.html": {Data: []byte(`{{define "layout"}}<h1>{{template "subject" .}}</h1>{{template "content" .}}{{end}}`)},
+		"en/welcome.html": {Data: []byte(`{{define "subject"}}Welcome {{.name}}{{end}}` +
+			`{{define "content"}}<p>Hi {{.name}}</p>{{end}}` +
+			`{{define "text"}}Hi {{.name}}{{end}}`)},
+		"id/welcome.html": {Data: []byte(`{{define "subject"}}Selamat datang {{.name}}{{end}}` +
+			`{{define "content"}}<p>Halo {{.name}}</p>{{end}}`)},
+		"en/other.html": {Data: []byte(`{{define "subject"}}Other{{end}}{{define "content"}}Other{{end}}`)},
+	}
+	emails, err := templates.Parse(fsys)
+	require.NoError(t, err)
+
+	vars := map[string]any{"name": "<Tom & Jerry>"}
+	email, ok, err := emails.Email(i18n.English, "welcome", vars)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, templates.Email{
+		Subject: "Welcome <Tom & Jerry>",
+		Text:    "Hi <Tom & Jerry>",
+		HTML:    "<h1>Welcome &lt;Tom &amp; Jerry&gt;</h1><p>Hi &lt;Tom &amp; Jerry&gt;</p>",
+	}, email, "only the HTML is escaped")
+
+	email, _, err = emails.Email(i18n.Indonesian, "welcome", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "Selamat datang <Tom & Jerry>", email.Subject)
+	assert.Empty(t, email.Text, "no plain text version")
+
+	email, ok, err = emails.Email(i18n.Indonesian, "other", nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Other", email.Subject, "falls back to the default locale")
+
+	_, ok, err = emails.Email(i18n.English, "unknown", nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = emails.Email(i18n.English, "welcome", map[string]any{})
+	assert.ErrorContains(t, err, "name", "missing variables are errors")
+
+	fsys["en/broken.html"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}Broken{{end}}`)}
+	_, err = templates.Parse(fsys)
+	assert.ErrorContains(t, err, `en/broken.html defines no "content" template`)
+}
+
+func TestRender(t *testing.T) {
+	emails, err := templates.New()
+	require.NoError(t, err)
+
+	vars := map[string]any{"name": "Jane", "new_email": "jane@example.com"}
+	message, err := emails.Render(notify.Message{Event: "email_change.completed", Recipient: "old@example.com", Locale: "id", Vars: vars})
+	require.NoError(t, err)
+	assert.Equal(t, "Email akun anda telah berubah", message.Subject)
+	assert.Contains(t, message.Text, "Halo Jane")
+	assert.Contains(t, message.HTML, "<strong>jane@example.com</strong>")
+
+	message, err = emails.Render(notify.Message{Event: "email_change.completed", Recipient: "old@example.com", Subject: "Custom", Vars: vars})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom", message.Subject, "a set subject is kept")
+	assert.Contains(t, message.Text, "Hi Jane", "the default locale when unset")
+
+	message, err = emails.Render(notify.Message{Event: "email_change.completed", Phone: "+447400123456", Vars: vars})
+	require.NoError(t, err)
+	assert.Empty(t, message.Text, "not an email")
+}
@@ -0,0 +1,96 @@
+// Package maintenance holds whether the server is in maintenance mode, answered with 503 by
+// middleware.MaintenanceMiddleware. It is turned on and off by maintenance in the config,
+// reloaded without restart, or at runtime with PUT /admin/maintenance.
+//
+// The mode lives in the process: the admin endpoint changes the instance it reaches, while a
+// config change reaches every instance reading the file.
+package maintenance
+
+import (
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultRetryAfter is sent in Retry-After when the mode sets none
+	DefaultRetryAfter = 5 * time.Minute
+
+	// DefaultMessage is the message of the 503 responses when the mode sets none
+	DefaultMessage = "the service is under maintenance, try again later"
+)
+
+// Sources of a Mode
+const (
+	SourceConfig = "config"
+	SourceAdmin  = "admin"
+)
+
+// Mode is the maintenance mode of the server.
+type Mode struct {
+	Enabled    bool
+	RetryAfter time.Duration // until clients should retry
+	Message    string
+	Since      time.Time // when it was last set; zero when it never was
+	Source     string    // SourceConfig or SourceAdmin
+}
+
+var (
+	current atomic.Pointer[Mode]
+
+	// configured is the config Configure applied last, so reloads leaving it as it was keep
+	// the mode set with the admin endpoint
+	mu         sync.Mutex
+	configured *config.Maintenance
+)
+
+// Set changes the maintenance mode, filling the RetryAfter and Message it leaves empty with
+// their defaults and Since with the current time. It returns the mode set.
+func Set(mode Mode) Mode {
+	if mode.RetryAfter <= 0 {
+		mode.RetryAfter = DefaultRetryAfter
+	}
+	if mode.Message == "" {
+		mode.Message = DefaultMessage
+	}
+	if mode.Since.IsZero() {
+		mode.Since = time.Now()
+	}
+	current.Store(&mode)
+	return mode
+}
+
+// Current returns the maintenance mode, disabled when it was never set.
+func Current() Mode {
+	if mode := current.Load(); mode != nil {
+		return *mode
+	}
+	return Mode{RetryAfter: DefaultRetryAfter, Message: DefaultMessage}
+}
+
+// Configure sets the mode of the maintenance configuration when it differs from the one it
+// applied last, reporting whether it did. Call it at startup and on every config reload: a
+// reload changing something else keeps the mode set with the admin endpoint.
+func Configure(cfg config.Maintenance) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if configured != nil && *configured == cfg {
+		return false, nil
+	}
+
+	var retryAfter time.Duration
+	if cfg.RetryAfter != "" {
+		parsed, err := time.ParseDuration(cfg.RetryAfter)
+		if err != nil {
+			return false, fmt.Errorf("invalid maintenance.retry_after: %w", err)
+		}
+		retryAfter = parsed
+	}
+
+	configured = &cfg
+	Set(Mode{Enabled: cfg.Enabled, RetryAfter: retryAfter, Message: cfg.Message, Source: SourceConfig})
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	t.Cleanup(func() { current.Store(nil) })
+
+	assert.Equal(t, Mode{RetryAfter: DefaultRetryAfter, Message: DefaultMessage}, Current(), "disabled when never set")
+
+	mode := Set(Mode{Enabled: true, Source: SourceAdmin})
+	assert.Equal(t, DefaultRetryAfter, mode.RetryAfter)
+	assert.Equal(t, DefaultMessage, mode.Message)
+	assert.WithinDuration(t, time.Now(), mode.Since, time.Second)
+	assert.Equal(t, mode, Current())
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() {
+		current.Store(nil)
+		configured = nil
+	})
+
+	changed, err := Configure(config.Maintenance{})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.False(t, Current().Enabled)
+
+	changed, err = Configure(config.Maintenance{Enabled: true, RetryAfter: "10m", Message: "Migrating"})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	mode := Current()
+	assert.True(t, mode.Enabled)
+	assert.Equal(t, 10*time.Minute, mode.RetryAfter)
+	assert.Equal(t, "Migrating", mode.Message)
+	assert.Equal(t, SourceConfig, mode.Source)
+
+	Set(Mode{Source: SourceAdmin})
+	changed, err = Configure(config.Maintenance{Enabled: true, RetryAfter: "10m", Message: "Migrating"})
+	require.NoError(t, err)
+	assert.False(t, changed, "a reload changing something else")
+	assert.False(t, Current().Enabled, "keeps the mode set with the admin endpoint")
+
+	_, err = Configure(config.Maintenance{RetryAfter: "soon"})
+	assert.ErrorContains(t, err, "maintenance.retry_after")
+	assert.Equal(t, SourceAdmin, Current().Source, "an invalid configuration changes nothing")
+}
@@ -0,0 +1,221 @@
+// Package mockapi answers the documented operations of the generated OpenAPI spec
+// (docs/swagger.json) with example responses, for application.mode mock: frontend teams
+// build against the documented API before its implementation is ready. The examples come
+// from the example values of the models (the example struct tags), and are derived from the
+// schemas for the fields without one. The spec is regenerated with make docs.
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/schema"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Response is the example response of one documented status of an operation
+type Response struct {
+	Status int
+	Body   []byte // JSON; nil for a response without a body, e.g. 204
+}
+
+// operation holds the example responses of a documented operation by status
+type operation struct {
+	success   int // the lowest documented 2xx status, 0 when there is none
+	responses map[int]Response
+}
+
+// Responses holds the example responses of every documented operation. It is safe for
+// concurrent use.
+type Responses struct {
+	operations map[string]*operation // by method and echo route, e.g. "GET /api/v1/users/:id"
+}
+
+// New builds the example responses of the spec registered by the docs package.
+func New() (*Responses, error) {
+	doc, err := schema.OpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	return FromSpec(doc)
+}
+
+// FromSpec builds the example responses of doc, its references resolved. Only the JSON
+// responses get an example: the operations answering another media type are left out.
+func FromSpec(doc *openapi3.T) (*Responses, error) {
+	r := &Responses{operations: map[string]*operation{}}
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			if op.Responses == nil {
+				continue
+			}
+			built, err := buildOperation(op.Responses)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if len(built.responses) > 0 {
+				r.operations[key(method, Route(path))] = built
+			}
+		}
+	}
+	return r, nil
+}
+
+// Find returns the example response of the operation of method on route, the echo route
+// (e.g. "/api/v1/users/:id"). status selects a documented status, 0 the lowest documented
+// 2xx one. ok is false when the operation or the status isn't documented.
+func (r *Responses) Find(method, route string, status int) (Response, bool) {
+	op, ok := r.operations[key(method, route)]
+	if !ok {
+		return Response{}, false
+	}
+	if status == 0 {
+		status = op.success
+	}
+	response, ok := op.responses[status]
+	return response, ok
+}
+
+// Route converts an OpenAPI path to its echo route, {id} becoming :id
+func Route(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			if name, ok := strings.CutSuffix(name, "}"); ok {
+				segments[i] = ":" + name
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func key(method, route string) string {
+	return strings.ToUpper(method) + " " + route
+}
+
+// buildOperation builds the example of every documented status of responses; "default"
+// and the ranges (2XX) have no status to answer and are left out
+func buildOperation(responses *openapi3.Responses) (*operation, error) {
+	op := &operation{responses: map[int]Response{}}
+	for code, ref := range responses.Map() {
+		status, err := strconv.Atoi(code)
+		if err != nil || ref == nil || ref.Value == nil {
+			continue
+		}
+
+		response := Response{Status: status}
+		if len(ref.Value.Content) > 0 {
+			media := ref.Value.Content.Get("application/json")
+			if media == nil {
+				continue
+			}
+			if media.Schema != nil && media.Schema.Value != nil {
+				body, err := json.Marshal(Example(media.Schema.Value))
+				if err != nil {
+					return nil, fmt.Errorf("example of %d: %w", status, err)
+				}
+				response.Body = body
+			}
+		}
+
+		op.responses[status] = response
+		if status >= http.StatusOK && status < http.StatusMultipleChoices && (op.success == 0 || status < op.success) {
+			op.success = status
+		}
+	}
+	return op, nil
+}
+
+// Example builds an example value of s: its example when it has one, the first of its enum,
+// or else a value of its type, objects and arrays built from their properties and items. The
+// schemas of allOf are merged; a schema that refers to itself stops at the second visit.
+func Example(s *openapi3.Schema) any {
+	return example(s, nil)
+}
+
+func example(s *openapi3.Schema, visiting []*openapi3.Schema) any {
+	if s == nil || slices.Contains(visiting, s) {
+		return nil
+	}
+	visiting = append(visiting, s)
+
+	if s.Example != nil {
+		return s.Example
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+	if len(s.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, ref := range s.AllOf {
+			if ref == nil {
+				continue
+			}
+			if object, ok := example(ref.Value, visiting).(map[string]any); ok {
+				for name, value := range object {
+					merged[name] = value
+				}
+			}
+		}
+		return merged
+	}
+	for _, alternatives := range []openapi3.SchemaRefs{s.OneOf, s.AnyOf} {
+		if len(alternatives) > 0 && alternatives[0] != nil {
+			return example(alternatives[0].Value, visiting)
+		}
+	}
+
+	switch {
+	case s.Type.Is(openapi3.TypeArray):
+		if s.Items == nil {
+			return []any{}
+		}
+		return []any{example(s.Items.Value, visiting)}
+	case s.Type.Is(openapi3.TypeString):
+		return stringExample(s.Format)
+	case s.Type.Is(openapi3.TypeInteger), s.Type.Is(openapi3.TypeNumber):
+		if s.Min != nil {
+			return *s.Min
+		}
+		return 0
+	case s.Type.Is(openapi3.TypeBoolean):
+		return false
+	}
+
+	object := map[string]any{}
+	for name, ref := range s.Properties {
+		// An untyped property (any, e.g. the data of models.Response) has nothing to show
+		if ref == nil || ref.Value == nil || untyped(ref.Value) {
+			continue
+		}
+		object[name] = example(ref.Value, visiting)
+	}
+	return object
+}
+
+// untyped reports whether s accepts any value, the schema of an interface{} field
+func untyped(s *openapi3.Schema) bool {
+	return s.Type == nil && s.Example == nil && len(s.Enum) == 0 && len(s.Properties) == 0 &&
+		len(s.AllOf) == 0 && len(s.OneOf) == 0 && len(s.AnyOf) == 0 && s.Items == nil
+}
+
+// stringExample is the example of a string of format
+func stringExample(format string) string {
+	switch format {
+	case "date-time":
+		return "2026-01-01T00:00:00Z"
+	case "date":
+		return "2026-01-01"
+	case "email":
+		return "john.doe@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	}
+	return "string"
+}
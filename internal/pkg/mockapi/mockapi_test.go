@@ -0,0 +1,105 @@
+package mockapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/contract"
+	"go-echo-boilerplate/internal/pkg/mockapi"
+	"go-echo-boilerplate/internal/pkg/schema"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute(t *testing.T) {
+	assert.Equal(t, "/api/v1/users", mockapi.Route("/api/v1/users"))
+	assert.Equal(t, "/admin/quotas/overrides/:client", mockapi.Route("/admin/quotas/overrides/{client}"))
+	assert.Equal(t, "/a/:id/b/:name", mockapi.Route("/a/{id}/b/{name}"))
+}
+
+func TestFind(t *testing.T) {
+	responses, err := mockapi.New()
+	require.NoError(t, err)
+
+	response, ok := responses.Find(http.MethodPost, "/api/v1/users", 0)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusCreated, response.Status, "the lowest documented 2xx")
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(response.Body, &body))
+	assert.Contains(t, body, "data")
+	assert.NotContains(t, body, "errors", "an untyped property has no example")
+
+	response, ok = responses.Find(http.MethodPost, "/api/v1/users", http.StatusConflict)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusConflict, response.Status)
+
+	response, ok = responses.Find(http.MethodDelete, "/admin/quotas/overrides/:client", 0)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNoContent, response.Status)
+	assert.Nil(t, response.Body)
+
+	_, ok = responses.Find(http.MethodPost, "/api/v1/users", http.StatusTeapot)
+	assert.False(t, ok, "undocumented status")
+	_, ok = responses.Find(http.MethodDelete, "/api/versions", 0)
+	assert.False(t, ok, "undocumented operation")
+	_, ok = responses.Find(http.MethodGet, "/api/v1/admin/users/export", 0)
+	assert.False(t, ok, "not a JSON response")
+}
+
+// Every example must match the spec it is built from
+func TestExamplesMatchTheSpec(t *testing.T) {
+	doc, err := schema.OpenAPI()
+	require.NoError(t, err)
+	responses, err := mockapi.FromSpec(doc)
+	require.NoError(t, err)
+	validator, err := contract.New()
+	require.NoError(t, err)
+
+	params := regexp.MustCompile(`\{[^}]+\}`)
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			for code := range op.Responses.Map() {
+				status, err := strconv.Atoi(code)
+				require.NoError(t, err)
+				response, ok := responses.Find(method, mockapi.Route(path), status)
+				if !ok {
+					continue
+				}
+
+				req := httptest.NewRequest(method, params.ReplaceAllString(path, "1"), nil)
+				header := http.Header{"Content-Type": {"application/json"}}
+				assert.NoError(t, validator.Validate(req, response.Status, header, response.Body), "%s %s %d", method, path, status)
+			}
+		}
+	}
+}
+
+func TestExample(t *testing.T) {
+	node := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeObject}}
+	node.Properties = openapi3.Schemas{
+		"name":     openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Example: "root"}),
+		"status":   openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Enum: []any{"active", "banned"}}),
+		"count":    openapi3.NewSchemaRef("", openapi3.NewIntegerSchema().WithMin(1)),
+		"at":       openapi3.NewSchemaRef("", openapi3.NewDateTimeSchema()),
+		"children": openapi3.NewSchemaRef("", openapi3.NewArraySchema().WithItems(node)),
+		"any":      openapi3.NewSchemaRef("", &openapi3.Schema{}),
+	}
+	merged := &openapi3.Schema{AllOf: openapi3.SchemaRefs{
+		openapi3.NewSchemaRef("", node),
+		openapi3.NewSchemaRef("", openapi3.NewObjectSchema().WithProperty("name", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Example: "merged"})),
+	}}
+
+	assert.Equal(t, map[string]any{
+		"name":     "merged",
+		"status":   "active",
+		"count":    float64(1),
+		"at":       "2026-01-01T00:00:00Z",
+		"children": []any{nil},
+	}, mockapi.Example(merged), "a schema refers to itself once")
+}
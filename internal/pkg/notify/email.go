@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Email sends messages as emails through an SMTP server: plain text, or multipart/alternative
+// with the HTML version when the message has one.
+type Email struct {
+	addr     string // host:port
+	from     string
+	username string
+	password string
+}
+
+// NewEmail creates an email notifier sending from the from address through the SMTP server
+// at addr (host:port). It authenticates with PLAIN when username is set, which net/smtp only
+// allows over TLS or to localhost.
+func NewEmail(addr, from, username, password string) *Email {
+	return &Email{addr: addr, from: from, username: username, password: password}
+}
+
+// Notify implements Notifier. Messages without a recipient are skipped.
+func (e *Email) Notify(ctx context.Context, message Message) error {
+	if message.Recipient == "" {
+		return nil
+	}
+
+	content, err := e.compose(message)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.username != "" {
+		host, _, _ := net.SplitHostPort(e.addr)
+		auth = smtp.PlainAuth("", e.username, e.password, host)
+	}
+
+	// smtp.SendMail takes no context, so a cancelled ctx only stops it before it starts
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := smtp.SendMail(e.addr, auth, e.from, []string{message.Recipient}, content); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
+
+// compose builds the email, rejecting addresses that would inject headers
+func (e *Email) compose(message Message) ([]byte, error) {
+	from, err := mail.ParseAddress(e.from)
+	if err != nil {
+		return nil, fmt.Errorf("email: invalid sender %q: %w", e.from, err)
+	}
+	to, err := mail.ParseAddress(message.Recipient)
+	if err != nil {
+		return nil, fmt.Errorf("email: invalid recipient: %w", err)
+	}
+
+	sent := message.Time
+	if sent.IsZero() {
+		sent = time.Now()
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", oneLine(message.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", sent.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	if message.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+		buf.WriteString("\r\n")
+		buf.WriteString(crlf(message.Text))
+		return buf.Bytes(), nil
+	}
+
+	// Clients show the last part they support, so the HTML comes after the plain text
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n", parts.Boundary())
+	buf.WriteString("\r\n")
+
+	text, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	io.WriteString(text, crlf(message.Text))
+
+	// HTML lines may exceed the 998 characters SMTP allows, quoted-printable wraps them
+	html, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := quotedprintable.NewWriter(html)
+	io.WriteString(encoded, crlf(message.HTML))
+	if err := encoded.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// crlf ends the lines of an email body with CRLF
+func crlf(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
+
+// oneLine keeps a header value on one line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
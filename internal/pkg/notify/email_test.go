@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailCompose(t *testing.T) {
+	email := NewEmail("localhost:25", "Security <security@example.com>", "", "")
+
+	content, err := email.compose(Message{
+		Recipient: "john@example.com",
+		Subject:   "New sign-in\r\nBcc: attacker@example.com",
+		Text:      "Hello,\nsomeone signed in.",
+		Time:      time.Date(2026, 1, 24, 8, 57, 37, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	headers, body, _ := strings.Cut(string(content), "\r\n\r\n")
+	assert.Contains(t, headers, "From: \"Security\" <security@example.com>\r\n")
+	assert.Contains(t, headers, "To: <john@example.com>\r\n")
+	assert.Contains(t, headers, "Subject: New sign-in Bcc: attacker@example.com\r\n", "the subject stays on one line")
+	assert.Contains(t, headers, "Date: Sat, 24 Jan 2026 08:57:37 +0000\r\n")
+	assert.NotContains(t, headers, "\r\nBcc:")
+	assert.Equal(t, "Hello,\r\nsomeone signed in.", body)
+
+	_, err = email.compose(Message{Recipient: "john@example.com\r\nBcc: attacker@example.com"})
+	assert.Error(t, err, "the recipient can't inject headers")
+}
+
+func TestEmailComposeHTML(t *testing.T) {
+	email := NewEmail("localhost:25", "security@example.com", "", "")
+
+	content, err := email.compose(Message{
+		Recipient: "john@example.com",
+		Subject:   "New sign-in",
+		Text:      "Hello,\nsomeone signed in.",
+		HTML:      "<p>Hello,</p>\n<p>someone signed in.</p>",
+	})
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(content))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart() // decodes quoted-printable
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	assert.Equal(t, []string{
+		"text/plain; charset=UTF-8: Hello,\r\nsomeone signed in.",
+		"text/html; charset=UTF-8: <p>Hello,</p>\r\n<p>someone signed in.</p>",
+	}, bodies, "the plain text first, the HTML last")
+}
+
+func TestEmailSkipsMissingRecipient(t *testing.T) {
+	// No SMTP server listens on this address: a message without recipient never connects
+	email := NewEmail("127.0.0.1:1", "security@example.com", "", "")
+	assert.NoError(t, email.Notify(context.Background(), Message{Event: "test"}))
+}
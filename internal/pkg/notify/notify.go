@@ -0,0 +1,95 @@
+// Package notify delivers notifications to users and to other systems: emails over SMTP, text
+// messages through an SMS gateway, and signed webhooks. Deliveries are slow and may fail, so callers enqueue them on a Queue,
+// which sends them in the background and retries failures.
+//
+// A Queue delivering on Channels renders the messages with a Renderer (e.g. Templates), leaves out the channels
+// the user turned off (Preferences), retries each channel on its own, and keeps the deliveries
+// that still fail in a DeadLetterStore.
+//
+// Example:
+//
+//	queue := notify.NewQueue(notify.Channels{notify.ChannelWebhook: notify.NewWebhook(client, url, secret)}, notify.QueueOptions{})
+//	defer queue.Close(ctx)
+//	queue.Enqueue(ctx, notify.Message{Event: "login.suspicious", Data: data})
+package notify
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Names of the channels in Channels
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+// Message is a notification. Channels use the parts they need: emails go to Recipient with
+// Subject and Text, text messages go to Phone with Text, webhooks post Event, Time, and Data.
+type Message struct {
+	Event     string         `json:"event"`               // e.g. "login.suspicious"
+	Recipient string         `json:"recipient,omitempty"` // email address; emails are skipped when empty
+	Phone     string         `json:"phone,omitempty"`     // E.164 phone number; text messages are skipped when empty
+	Subject   string         `json:"subject,omitempty"`   // email subject
+	Text      string         `json:"text,omitempty"`      // plain text email body
+	HTML      string         `json:"html,omitempty"`      // HTML email body, sent along with Text when set
+	Data      map[string]any `json:"data,omitempty"`      // webhook payload
+	Time      time.Time      `json:"time"`                // when it happened; defaults to when it was enqueued
+
+	User      string         `json:"user,omitempty"`      // account number of the user notified, whose Preferences apply
+	Mandatory bool           `json:"mandatory,omitempty"` // delivered whatever the Preferences, e.g. verification codes
+	Channels  []string       `json:"channels,omitempty"`  // channels of Channels to deliver on; all of them when empty
+	Locale    string         `json:"locale,omitempty"`    // language of the templates, e.g. "id"; their default when empty
+	Vars      map[string]any `json:"-"`                   // variables of the templates, rendered when enqueued
+}
+
+// Notifier delivers a message on one or more channels.
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// Multi delivers messages on every notifier, joining their errors.
+type Multi []Notifier
+
+// Notify implements Notifier.
+func (m Multi) Notify(ctx context.Context, message Message) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Channels delivers messages on named notifiers: the ones of Message.Channels, or all of them.
+// A Queue delivering on Channels retries each channel on its own, so a failing channel does
+// not deliver the message twice on the others.
+type Channels map[string]Notifier
+
+// Notify implements Notifier, joining the errors of the channels.
+func (c Channels) Notify(ctx context.Context, message Message) error {
+	var errs []error
+	for _, name := range c.names(message.Channels) {
+		if err := c[name].Notify(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// names returns the sorted names of the channels among selected, all of them when selected
+// is empty
+func (c Channels) names(selected []string) []string {
+	var names []string
+	for name := range c {
+		if len(selected) == 0 || slices.Contains(selected, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,315 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a Notifier failing its first failures deliveries
+type recorder struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	messages []notify.Message
+}
+
+func (r *recorder) Notify(ctx context.Context, message notify.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts++
+	if r.attempts <= r.failures {
+		return errors.New("unavailable")
+	}
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.DefaultConfig())
+	occurred := time.Date(2026, 1, 24, 8, 57, 37, 0, time.UTC)
+	message := notify.Message{Event: "login.suspicious", Recipient: "john@example.com", Data: map[string]any{"country": "GB"}, Time: occurred}
+
+	t.Run("Signed", func(t *testing.T) {
+		require.NoError(t, notify.NewWebhook(client, server.URL, "secret").Notify(context.Background(), message))
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "login.suspicious", payload["event"])
+		assert.Equal(t, "2026-01-24T08:57:37Z", payload["occurred_at"])
+		assert.Equal(t, map[string]any{"country": "GB"}, payload["data"])
+		assert.NotContains(t, string(body), "john@example.com", "the recipient is not posted")
+
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+		assert.Equal(t, "login.suspicious", headers.Get(notify.HeaderEvent))
+		assert.Equal(t, notify.Sign("secret", body), headers.Get(notify.HeaderSignature))
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		require.NoError(t, notify.NewWebhook(client, server.URL, "").Notify(context.Background(), message))
+		assert.Empty(t, headers.Get(notify.HeaderSignature))
+	})
+
+	t.Run("Error Status", func(t *testing.T) {
+		err := notify.NewWebhook(client, server.URL+"/down", "").Notify(context.Background(), message)
+		assert.ErrorContains(t, err, "returned status 500")
+	})
+}
+
+func TestSMS(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+		calls   int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.DefaultConfig())
+	message := notify.Message{Event: "phone_change.otp", Phone: "+6281234567890", Text: "Your code is 123456", Data: map[string]any{"account_number": "1"}}
+
+	require.NoError(t, notify.NewSMS(client, server.URL, "token").Notify(context.Background(), message))
+	assert.JSONEq(t, `{"to":"+6281234567890","text":"Your code is 123456","event":"phone_change.otp"}`, string(body))
+	assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+
+	require.NoError(t, notify.NewSMS(client, server.URL, "").Notify(context.Background(), notify.Message{Event: "login.suspicious"}))
+	assert.Equal(t, 1, calls, "messages without a phone number are skipped")
+
+	err := notify.NewSMS(client, server.URL+"/down", "").Notify(context.Background(), message)
+	assert.ErrorContains(t, err, "returned status 502")
+}
+
+func TestSign(t *testing.T) {
+	// echo -n 'body' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=dc46983557fea127b43af721467eb9b3fde2338fe3e14f51952aa8478c13d355", notify.Sign("secret", []byte("body")))
+}
+
+func TestMulti(t *testing.T) {
+	ok, failing := &recorder{}, &recorder{failures: 1}
+
+	err := notify.Multi{failing, ok}.Notify(context.Background(), notify.Message{Event: "test"})
+	assert.ErrorContains(t, err, "unavailable")
+	assert.Len(t, ok.messages, 1, "a failing notifier doesn't stop the others")
+}
+
+func TestChannels(t *testing.T) {
+	email, sms := &recorder{}, &recorder{failures: 1}
+	channels := notify.Channels{notify.ChannelEmail: email, notify.ChannelSMS: sms}
+
+	err := channels.Notify(context.Background(), notify.Message{Event: "test"})
+	assert.ErrorContains(t, err, "unavailable")
+	assert.Len(t, email.messages, 1, "a failing channel doesn't stop the others")
+
+	require.NoError(t, channels.Notify(context.Background(), notify.Message{Event: "test", Channels: []string{notify.ChannelSMS}}))
+	assert.Len(t, email.messages, 1, "only the selected channels")
+	assert.Len(t, sms.messages, 1)
+}
+
+func TestTemplates(t *testing.T) {
+	templates, err := notify.NewTemplates(map[string]notify.Template{
+		"login.suspicious": {Subject: "New sign-in\nfrom {{.country}}", Text: "Hi {{.name}},\nsomeone signed in from {{.country}}."},
+	})
+	require.NoError(t, err)
+
+	message, err := templates.Render(notify.Message{Event: "login.suspicious", Vars: map[string]any{"name": "John", "country": "GB"}})
+	require.NoError(t, err)
+	assert.Equal(t, "New sign-in from GB", message.Subject, "the subject stays on one line")
+	assert.Equal(t, "Hi John,\nsomeone signed in from GB.", message.Text)
+
+	message, err = templates.Render(notify.Message{Event: "login.suspicious", Subject: "Custom", Text: "Custom"})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom", message.Text, "a set text is kept")
+
+	message, err = templates.Render(notify.Message{Event: "other", Text: "as is"})
+	require.NoError(t, err)
+	assert.Equal(t, "as is", message.Text)
+
+	_, err = templates.Render(notify.Message{Event: "login.suspicious", Vars: map[string]any{"name": "John"}})
+	assert.ErrorContains(t, err, "country", "missing variables are errors")
+
+	_, err = notify.NewTemplates(map[string]notify.Template{"broken": {Text: "{{.name"}})
+	assert.ErrorContains(t, err, "broken")
+
+	fallback, err := notify.NewTemplates(map[string]notify.Template{"login.suspicious": {Subject: "Fallback", Text: "Fallback"}})
+	require.NoError(t, err)
+	message, err = notify.Renderers{templates, fallback}.Render(notify.Message{Event: "login.suspicious", Vars: map[string]any{"name": "John", "country": "GB"}})
+	require.NoError(t, err)
+	assert.Equal(t, "New sign-in from GB", message.Subject, "the first renderer wins")
+}
+
+func TestQueue(t *testing.T) {
+	options := notify.QueueOptions{Workers: 1, MaxAttempts: 3, Backoff: time.Millisecond}
+
+	t.Run("Retries", func(t *testing.T) {
+		notifier := &recorder{failures: 2}
+		queue := notify.NewQueue(notifier, options)
+
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "test"}))
+		require.NoError(t, queue.Close(context.Background()))
+
+		if assert.Len(t, notifier.messages, 1) {
+			assert.False(t, notifier.messages[0].Time.IsZero(), "the time defaults to when it was enqueued")
+		}
+		assert.Equal(t, notify.QueueStats{Sent: 1, Retried: 2}, queue.Stats())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		log := logger.NewTestLogger()
+		notifier := &recorder{failures: 3}
+		queue := notify.NewQueue(notifier, options)
+
+		require.NoError(t, queue.Enqueue(logger.WithLogger(context.Background(), log), notify.Message{Event: "test"}))
+		require.NoError(t, queue.Close(context.Background()))
+
+		assert.Equal(t, uint64(1), queue.Stats().Failed)
+		failed := log.EventsWithMessage("notification failed")
+		if assert.Len(t, failed, 1) {
+			assert.Equal(t, "test", failed[0].Fields["notification_event"])
+			assert.EqualValues(t, 3, failed[0].Fields["attempts"])
+		}
+	})
+
+	t.Run("Request Cancelled", func(t *testing.T) {
+		notifier := &recorder{}
+		queue := notify.NewQueue(notifier, options)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		require.NoError(t, queue.Enqueue(ctx, notify.Message{Event: "test"}))
+		cancel()
+		require.NoError(t, queue.Close(context.Background()))
+
+		assert.Len(t, notifier.messages, 1, "the delivery outlives the request")
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		queue := notify.NewQueue(&recorder{}, options)
+		require.NoError(t, queue.Close(context.Background()))
+
+		assert.ErrorIs(t, queue.Enqueue(context.Background(), notify.Message{}), notify.ErrQueueClosed)
+	})
+
+	t.Run("Close Timeout", func(t *testing.T) {
+		queue := notify.NewQueue(&recorder{failures: 3}, notify.QueueOptions{Workers: 1, MaxAttempts: 3, Backoff: time.Hour})
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "test"}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, queue.Close(ctx), context.DeadlineExceeded)
+
+		assert.Eventually(t, func() bool { return queue.Stats().Failed == 1 }, time.Second, time.Millisecond,
+			"the pending retry is given up")
+	})
+	t.Run("Renders Templates", func(t *testing.T) {
+		templates, err := notify.NewTemplates(map[string]notify.Template{"test": {Subject: "Hi {{.name}}", Text: "Hello {{.name}}"}})
+		require.NoError(t, err)
+		notifier := &recorder{}
+		queue := notify.NewQueue(notifier, notify.QueueOptions{Workers: 1, Templates: templates})
+
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "test", Vars: map[string]any{"name": "John"}}))
+		assert.Error(t, queue.Enqueue(context.Background(), notify.Message{Event: "test"}), "a message that can't be rendered is not queued")
+		require.NoError(t, queue.Close(context.Background()))
+
+		require.Len(t, notifier.messages, 1)
+		assert.Equal(t, "Hello John", notifier.messages[0].Text)
+		assert.Nil(t, notifier.messages[0].Vars, "variables are not delivered")
+	})
+
+	t.Run("Retries Each Channel", func(t *testing.T) {
+		email, sms := &recorder{}, &recorder{failures: 1}
+		queue := notify.NewQueue(notify.Channels{notify.ChannelEmail: email, notify.ChannelSMS: sms}, options)
+
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "test"}))
+		require.NoError(t, queue.Close(context.Background()))
+
+		assert.Len(t, email.messages, 1, "not sent again when the other channel fails")
+		assert.Len(t, sms.messages, 1)
+		assert.Equal(t, notify.QueueStats{Sent: 2, Retried: 1}, queue.Stats())
+	})
+
+	t.Run("Preferences", func(t *testing.T) {
+		email, sms := &recorder{}, &recorder{}
+		preferences := notify.PreferencesFunc(func(ctx context.Context, user string) (map[string]bool, error) {
+			switch user {
+			case "unavailable":
+				return nil, errors.New("connection refused")
+			case "muted":
+				return map[string]bool{notify.ChannelEmail: false, notify.ChannelSMS: false}, nil
+			}
+			return map[string]bool{notify.ChannelSMS: false}, nil
+		})
+		queue := notify.NewQueue(notify.Channels{notify.ChannelEmail: email, notify.ChannelSMS: sms},
+			notify.QueueOptions{Workers: 1, Preferences: preferences})
+
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "optional", User: "12345"}))
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "code", User: "12345", Mandatory: true}))
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "fallback", User: "unavailable"}))
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "muted", User: "muted"}))
+		require.NoError(t, queue.Close(context.Background()))
+
+		events := func(r *recorder) []string {
+			var events []string
+			for _, message := range r.messages {
+				events = append(events, message.Event)
+			}
+			return events
+		}
+		assert.Equal(t, []string{"optional", "code", "fallback"}, events(email))
+		assert.Equal(t, []string{"code", "fallback"}, events(sms), "turned off, unless mandatory or unreadable")
+		assert.Equal(t, uint64(1), queue.Stats().Muted)
+	})
+
+	t.Run("Dead Letters", func(t *testing.T) {
+		var letters []notify.DeadLetter
+		store := notify.DeadLetterFunc(func(ctx context.Context, letter notify.DeadLetter) error {
+			letters = append(letters, letter)
+			return nil
+		})
+		email, sms := &recorder{}, &recorder{failures: 3}
+		queue := notify.NewQueue(notify.Channels{notify.ChannelEmail: email, notify.ChannelSMS: sms},
+			notify.QueueOptions{Workers: 1, MaxAttempts: 3, Backoff: time.Millisecond, DeadLetters: store})
+
+		require.NoError(t, queue.Enqueue(context.Background(), notify.Message{Event: "test", Text: "Hello"}))
+		require.NoError(t, queue.Close(context.Background()))
+
+		require.Len(t, letters, 1)
+		assert.Equal(t, notify.ChannelSMS, letters[0].Channel)
+		assert.Equal(t, []string{notify.ChannelSMS}, letters[0].Message.Channels, "enqueued again, it goes to that channel only")
+		assert.Equal(t, "Hello", letters[0].Message.Text)
+		assert.Equal(t, 3, letters[0].Attempts)
+		assert.Equal(t, "unavailable", letters[0].Error)
+	})
+}
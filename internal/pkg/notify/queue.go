@@ -0,0 +1,302 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Queue defaults, used for the zero values of QueueOptions
+const (
+	DefaultWorkers     = 2
+	DefaultQueueSize   = 1000
+	DefaultMaxAttempts = 3
+	DefaultBackoff     = time.Second
+	DefaultTimeout     = 10 * time.Second
+)
+
+// ErrQueueClosed is returned by Enqueue after Close
+var ErrQueueClosed = errors.New("notify: queue closed")
+
+// ErrQueueFull is returned by Enqueue when the queue has no room left
+var ErrQueueFull = errors.New("notify: queue full")
+
+// QueueOptions tunes a Queue. Zero values fall back to the defaults.
+type QueueOptions struct {
+	Workers     int           // concurrent deliveries
+	Size        int           // messages waiting for a worker
+	MaxAttempts int           // deliveries of a message before it is given up
+	Backoff     time.Duration // before the first retry, doubling after each one
+	Timeout     time.Duration // per attempt
+
+	Templates   Renderer        // renders the messages enqueued, e.g. *Templates or Renderers
+	Preferences Preferences     // channels the users turned off; needs Channels, every channel is on without it
+	DeadLetters DeadLetterStore // keeps the deliveries that fail every attempt; they are only logged without it
+}
+
+// QueueStats reports the counters of a Queue.
+type QueueStats struct {
+	Queued  int    `json:"queued"`
+	Sent    uint64 `json:"sent"`
+	Retried uint64 `json:"retried"`
+	Failed  uint64 `json:"failed"`  // every attempt failed
+	Dropped uint64 `json:"dropped"` // the queue was full
+	Muted   uint64 `json:"muted"`   // the user turned off every channel of the message
+}
+
+// deadLetterTimeout bounds saving a dead letter
+const deadLetterTimeout = 5 * time.Second
+
+// job is the delivery of a message on one channel, or on the notifier when it is not Channels
+type job struct {
+	ctx     context.Context
+	message Message
+	channel string
+}
+
+// Queue delivers messages in the background, retrying failed deliveries with exponential
+// backoff. Deliveries that still fail are logged and kept in QueueOptions.DeadLetters. It is
+// safe for concurrent use.
+type Queue struct {
+	notifier Notifier
+	options  QueueOptions
+	jobs     chan job
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+	quit   chan struct{} // closed when Close gives up, cutting retries short
+
+	sent    atomic.Uint64
+	retried atomic.Uint64
+	failed  atomic.Uint64
+	dropped atomic.Uint64
+	muted   atomic.Uint64
+}
+
+// NewQueue creates a queue delivering with notifier and starts its workers.
+func NewQueue(notifier Notifier, options QueueOptions) *Queue {
+	if options.Workers <= 0 {
+		options.Workers = DefaultWorkers
+	}
+	if options.Size <= 0 {
+		options.Size = DefaultQueueSize
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = DefaultBackoff
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	q := &Queue{
+		notifier: notifier,
+		options:  options,
+		jobs:     make(chan job, options.Size),
+		quit:     make(chan struct{}),
+	}
+	for range options.Workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Enqueue renders a message and queues its delivery on each channel the user did not turn
+// off, without waiting for them. The deliveries keep the values of ctx (logger, request ID)
+// but not its cancellation, so they outlive the request.
+func (q *Queue) Enqueue(ctx context.Context, message Message) error {
+	if message.Time.IsZero() {
+		message.Time = time.Now()
+	}
+
+	if q.options.Templates != nil {
+		rendered, err := q.options.Templates.Render(message)
+		if err != nil {
+			return err
+		}
+		message = rendered
+	}
+	message.Vars = nil
+
+	jobs := q.split(ctx, message)
+	if len(jobs) == 0 {
+		q.muted.Add(1)
+		return nil
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	for _, job := range jobs {
+		select {
+		case q.jobs <- job:
+		default:
+			q.dropped.Add(1)
+			return ErrQueueFull
+		}
+	}
+	return nil
+}
+
+// split makes the deliveries of message: one per channel of Channels the user did not turn
+// off, or one on a notifier that is not Channels
+func (q *Queue) split(ctx context.Context, message Message) []job {
+	detached := context.WithoutCancel(ctx)
+	channels, ok := q.notifier.(Channels)
+	if !ok {
+		return []job{{ctx: detached, message: message}}
+	}
+
+	preferred := q.preferences(ctx, message)
+	var jobs []job
+	for _, name := range channels.names(message.Channels) {
+		if on, set := preferred[name]; set && !on {
+			continue
+		}
+		delivery := message
+		delivery.Channels = []string{name}
+		jobs = append(jobs, job{ctx: detached, message: delivery, channel: name})
+	}
+	return jobs
+}
+
+// preferences returns the channels the user of message turned on or off, none for mandatory
+// messages. When they can't be read the message goes on every channel: better an unwanted
+// notification than a lost security alert.
+func (q *Queue) preferences(ctx context.Context, message Message) map[string]bool {
+	if q.options.Preferences == nil || message.User == "" || message.Mandatory {
+		return nil
+	}
+
+	preferred, err := q.options.Preferences.Channels(ctx, message.User)
+	if err != nil {
+		logger.FromContext(ctx).Warn(ctx, "notification preferences unavailable",
+			logger.String("notification_event", message.Event),
+			logger.Error(err),
+		)
+		return nil
+	}
+	return preferred
+}
+
+// Close stops accepting messages and waits until the queued ones are delivered or ctx is
+// done. Register it as a graceful process so notifications are not lost on shutdown.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.stop()
+		return ctx.Err()
+	}
+}
+
+// Stats returns the counters of the queue.
+func (q *Queue) Stats() QueueStats {
+	return QueueStats{
+		Queued:  len(q.jobs),
+		Sent:    q.sent.Load(),
+		Retried: q.retried.Load(),
+		Failed:  q.failed.Load(),
+		Dropped: q.dropped.Load(),
+		Muted:   q.muted.Load(),
+	}
+}
+
+func (q *Queue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-q.quit:
+	default:
+		close(q.quit)
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		q.deliver(job)
+	}
+}
+
+// deliver sends a message, retrying until it succeeds, MaxAttempts is reached, or Close gives up
+func (q *Queue) deliver(job job) {
+	var err error
+	for attempt := 1; attempt <= q.options.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(q.options.Backoff << (attempt - 2))
+			select {
+			case <-timer.C:
+			case <-q.quit:
+				timer.Stop()
+				q.fail(job, attempt-1, err)
+				return
+			}
+			q.retried.Add(1)
+		}
+
+		ctx, cancel := context.WithTimeout(job.ctx, q.options.Timeout)
+		err = q.notifier.Notify(ctx, job.message)
+		cancel()
+		if err == nil {
+			q.sent.Add(1)
+			return
+		}
+	}
+
+	q.fail(job, q.options.MaxAttempts, err)
+}
+
+// fail logs a delivery that failed every attempt and keeps it in the dead letters
+func (q *Queue) fail(job job, attempts int, err error) {
+	q.failed.Add(1)
+	log := logger.FromContext(job.ctx)
+	log.Error(job.ctx, "notification failed",
+		logger.String("notification_event", job.message.Event),
+		logger.String("notification_channel", job.channel),
+		logger.Int("attempts", attempts),
+		logger.Error(err),
+	)
+
+	if q.options.DeadLetters == nil {
+		return
+	}
+	letter := DeadLetter{Message: job.message, Channel: job.channel, Attempts: attempts, FailedAt: time.Now()}
+	if err != nil {
+		letter.Error = err.Error()
+	}
+	ctx, cancel := context.WithTimeout(job.ctx, deadLetterTimeout)
+	defer cancel()
+	if err := q.options.DeadLetters.Save(ctx, letter); err != nil {
+		log.Error(job.ctx, "notification dead letter lost",
+			logger.String("notification_event", job.message.Event),
+			logger.Error(err),
+		)
+	}
+}
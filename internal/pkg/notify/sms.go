@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"io"
+	"net/http"
+)
+
+// smsPayload is the JSON body posted to the SMS gateway
+type smsPayload struct {
+	To    string `json:"to"` // E.164
+	Text  string `json:"text"`
+	Event string `json:"event"`
+}
+
+// SMS sends messages as text messages through an HTTP gateway: a JSON POST of the phone
+// number and text, which the gateway (or a thin adapter in front of the provider) sends.
+type SMS struct {
+	client *httpclient.Client
+	url    string
+	token  string
+}
+
+// NewSMS creates an SMS notifier posting to the gateway at url. Requests carry token as a
+// bearer token when it is set.
+func NewSMS(client *httpclient.Client, url, token string) *SMS {
+	return &SMS{client: client, url: url, token: token}
+}
+
+// Notify implements Notifier. Messages without a phone number are skipped; responses other
+// than 2xx are errors.
+func (s *SMS) Notify(ctx context.Context, message Message) error {
+	if message.Phone == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(smsPayload{To: message.Phone, Text: message.Text, Event: message.Event})
+	if err != nil {
+		return fmt.Errorf("sms: failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("sms: failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sms: %s returned status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Preferences tells the channels each user turned on or off.
+type Preferences interface {
+	// Channels returns the choice of the user per channel; channels missing from it are on.
+	Channels(ctx context.Context, user string) (map[string]bool, error)
+}
+
+// PreferencesFunc adapts a function to Preferences.
+type PreferencesFunc func(ctx context.Context, user string) (map[string]bool, error)
+
+// Channels implements Preferences.
+func (f PreferencesFunc) Channels(ctx context.Context, user string) (map[string]bool, error) {
+	return f(ctx, user)
+}
+
+// DeadLetter is a delivery that failed every attempt.
+type DeadLetter struct {
+	Message  Message   // as rendered, on the one channel of the delivery
+	Channel  string    // empty when the queue does not deliver on Channels
+	Attempts int       // deliveries tried
+	Error    string    // of the last attempt
+	FailedAt time.Time // when the queue gave up
+}
+
+// DeadLetterStore keeps the deliveries a Queue gave up on, to look into and enqueue again.
+type DeadLetterStore interface {
+	Save(ctx context.Context, letter DeadLetter) error
+}
+
+// DeadLetterFunc adapts a function to DeadLetterStore.
+type DeadLetterFunc func(ctx context.Context, letter DeadLetter) error
+
+// Save implements DeadLetterStore.
+func (f DeadLetterFunc) Save(ctx context.Context, letter DeadLetter) error {
+	return f(ctx, letter)
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Renderer fills the parts of a message left empty from its Event, Locale, and Vars.
+type Renderer interface {
+	Render(message Message) (Message, error)
+}
+
+// Renderers renders messages with each renderer in turn, so the first one filling a part wins.
+type Renderers []Renderer
+
+// Render implements Renderer.
+func (r Renderers) Render(message Message) (Message, error) {
+	for _, renderer := range r {
+		var err error
+		if message, err = renderer.Render(message); err != nil {
+			return message, err
+		}
+	}
+	return message, nil
+}
+
+// Template is the text/template source of the Subject and Text of the messages of an event,
+// executed with Message.Vars. Missing variables are errors.
+type Template struct {
+	Subject string
+	Text    string
+}
+
+// Templates renders the messages of the events it has a Template for, whatever their Locale.
+type Templates struct {
+	templates map[string]*template.Template // by event, defining "subject" and "text"
+}
+
+// NewTemplates parses the templates of each event.
+func NewTemplates(sources map[string]Template) (*Templates, error) {
+	t := &Templates{templates: make(map[string]*template.Template, len(sources))}
+	for event, source := range sources {
+		parsed := template.New(event).Option("missingkey=error")
+		if _, err := parsed.New("subject").Parse(source.Subject); err != nil {
+			return nil, fmt.Errorf("notify: invalid subject template of %q: %w", event, err)
+		}
+		if _, err := parsed.New("text").Parse(source.Text); err != nil {
+			return nil, fmt.Errorf("notify: invalid text template of %q: %w", event, err)
+		}
+		t.templates[event] = parsed
+	}
+	return t, nil
+}
+
+// Render fills the Subject and Text left empty in message from the template of its Event.
+// Messages of events without a template are returned as they are.
+func (t *Templates) Render(message Message) (Message, error) {
+	if t == nil {
+		return message, nil
+	}
+	parsed, ok := t.templates[message.Event]
+	if !ok {
+		return message, nil
+	}
+
+	var err error
+	if message.Subject == "" {
+		if message.Subject, err = execute(parsed, "subject", message.Vars); err != nil {
+			return message, err
+		}
+		// A header value stays on one line
+		message.Subject = oneLine(message.Subject)
+	}
+	if message.Text == "" {
+		if message.Text, err = execute(parsed, "text", message.Vars); err != nil {
+			return message, err
+		}
+	}
+	return message, nil
+}
+
+func execute(parsed *template.Template, name string, vars map[string]any) (string, error) {
+	var buf strings.Builder
+	if err := parsed.ExecuteTemplate(&buf, name, vars); err != nil {
+		return "", fmt.Errorf("notify: failed to render the %s of %q: %w", name, parsed.Name(), err)
+	}
+	return buf.String(), nil
+}
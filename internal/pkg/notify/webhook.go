@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook headers
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" and the hex HMAC-SHA256 of the body
+)
+
+// webhookPayload is the JSON body of a webhook
+type webhookPayload struct {
+	Event      string         `json:"event"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+// Webhook posts messages as JSON to a URL. Receivers verify them with the HMAC-SHA256
+// signature of the body, keyed with the shared secret.
+type Webhook struct {
+	client *httpclient.Client
+	url    string
+	secret string
+}
+
+// NewWebhook creates a webhook notifier. Bodies are signed when secret is set.
+func NewWebhook(client *httpclient.Client, url, secret string) *Webhook {
+	return &Webhook{client: client, url: url, secret: secret}
+}
+
+// Notify implements Notifier. Responses other than 2xx are errors.
+func (w *Webhook) Notify(ctx context.Context, message Message) error {
+	body, err := json.Marshal(webhookPayload{Event: message.Event, OccurredAt: message.Time, Data: message.Data})
+	if err != nil {
+		return fmt.Errorf("webhook: failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, message.Event)
+	if w.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s returned status %d", w.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the X-Webhook-Signature of a body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,107 @@
+// Package pii replaces personal data in logs with stable tokens that can be resolved back
+// during incident investigation (POST /admin/pii/resolve).
+//
+// A token is a keyed HMAC of the value, so the same value always gets the same token and
+// log lines stay correlatable. Resolving needs the value, which is kept in a Vault only for
+// as long as pii.ttl, and never written to the logs.
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// TokenPrefix starts every token, so tokens are easy to spot and search for in logs
+	TokenPrefix = "pii_"
+
+	// DefaultMaxEntries is the default number of tokens kept resolvable
+	DefaultMaxEntries = 100_000
+
+	// DefaultTTL is the default time a token stays resolvable after it was last logged
+	DefaultTTL = 24 * time.Hour
+)
+
+// Tokenizer replaces values with tokens and remembers them for Resolve.
+// It is safe for concurrent use.
+type Tokenizer struct {
+	key   []byte
+	vault Vault
+}
+
+// New creates a Tokenizer that stores the values of its tokens in vault.
+func New(key string, vault Vault) *Tokenizer {
+	return &Tokenizer{key: []byte(key), vault: vault}
+}
+
+// FromConfig creates a Tokenizer with an in-memory vault from the pii configuration.
+// It returns nil when pii.key is not set.
+func FromConfig(cfg config.PII) (*Tokenizer, error) {
+	if cfg.Key == "" {
+		return nil, nil
+	}
+
+	maxEntries := cfg.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	ttl := DefaultTTL
+	if cfg.TTL != "" {
+		parsed, err := time.ParseDuration(cfg.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pii.ttl: %w", err)
+		}
+		ttl = parsed
+	}
+
+	return New(cfg.Key, NewMemoryVault(maxEntries, ttl)), nil
+}
+
+// Token returns the token of value without storing it.
+func (t *Tokenizer) Token(value string) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(value))
+	return TokenPrefix + hex.EncodeToString(mac.Sum(nil))[:24]
+}
+
+// Tokenize returns the token of value and keeps value resolvable.
+func (t *Tokenizer) Tokenize(value string) string {
+	token := t.Token(value)
+	t.vault.Put(token, value)
+	return token
+}
+
+// Resolve returns the value of a token, false when the token is unknown or expired.
+func (t *Tokenizer) Resolve(token string) (string, bool) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return "", false
+	}
+	return t.vault.Get(token)
+}
+
+// Mask tokenizes value, so a Tokenizer can be used as a logger masking strategy.
+func (t *Tokenizer) Mask(value any) any {
+	if value == nil {
+		return nil
+	}
+	return t.Tokenize(fmt.Sprint(value))
+}
+
+var current atomic.Pointer[Tokenizer]
+
+// SetDefault sets the Tokenizer used by the pii masking strategy and the resolve endpoint.
+func SetDefault(t *Tokenizer) {
+	current.Store(t)
+}
+
+// Default returns the Tokenizer set with SetDefault, nil when PII tokenization is disabled.
+func Default() *Tokenizer {
+	return current.Load()
+}
@@ -0,0 +1,85 @@
+package pii_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/pii"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizer(t *testing.T) {
+	tokenizer := pii.New("key", pii.NewMemoryVault(10, time.Hour))
+
+	token := tokenizer.Tokenize("jane@example.com")
+	assert.True(t, strings.HasPrefix(token, pii.TokenPrefix))
+	assert.NotContains(t, token, "jane")
+	assert.Equal(t, token, tokenizer.Tokenize("jane@example.com"), "tokens are stable")
+	assert.NotEqual(t, token, tokenizer.Tokenize("john@example.com"))
+	assert.NotEqual(t, token, pii.New("other-key", pii.NewMemoryVault(10, time.Hour)).Token("jane@example.com"))
+
+	value, ok := tokenizer.Resolve(token)
+	assert.True(t, ok)
+	assert.Equal(t, "jane@example.com", value)
+
+	_, ok = tokenizer.Resolve(tokenizer.Token("never logged"))
+	assert.False(t, ok)
+	_, ok = tokenizer.Resolve("jane@example.com")
+	assert.False(t, ok)
+}
+
+func TestTokenizer_Mask(t *testing.T) {
+	tokenizer := pii.New("key", pii.NewMemoryVault(10, time.Hour))
+
+	masked := tokenizer.Mask(12345)
+	value, ok := tokenizer.Resolve(masked.(string))
+	assert.True(t, ok)
+	assert.Equal(t, "12345", value)
+	assert.Nil(t, tokenizer.Mask(nil))
+}
+
+func TestMemoryVault(t *testing.T) {
+	t.Run("Evicts Oldest", func(t *testing.T) {
+		vault := pii.NewMemoryVault(2, time.Hour)
+		vault.Put("a", "1")
+		vault.Put("b", "2")
+		vault.Put("a", "1") // refreshed, b is now the oldest
+		vault.Put("c", "3")
+
+		assert.Equal(t, 2, vault.Len())
+		_, ok := vault.Get("b")
+		assert.False(t, ok)
+		value, ok := vault.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "1", value)
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		vault := pii.NewMemoryVault(10, 10*time.Millisecond)
+		vault.Put("a", "1")
+
+		_, ok := vault.Get("a")
+		assert.True(t, ok)
+		time.Sleep(20 * time.Millisecond)
+		_, ok = vault.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, vault.Len())
+	})
+}
+
+func TestFromConfig(t *testing.T) {
+	tokenizer, err := pii.FromConfig(config.PII{})
+	require.NoError(t, err)
+	assert.Nil(t, tokenizer, "disabled without a key")
+
+	_, err = pii.FromConfig(config.PII{Key: "key", TTL: "forever"})
+	assert.Error(t, err)
+
+	tokenizer, err = pii.FromConfig(config.PII{Key: "key", TTL: "1h"})
+	require.NoError(t, err)
+	assert.NotNil(t, tokenizer)
+}
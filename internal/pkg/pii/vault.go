@@ -0,0 +1,92 @@
+package pii
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Vault stores the values of tokens for Resolve.
+// Implementations must be safe for concurrent use.
+type Vault interface {
+	Put(token, value string)
+	Get(token string) (string, bool)
+}
+
+// MemoryVault keeps up to maxEntries tokens in memory, each for ttl after it was last put.
+// The least recently put token is evicted first. Tokens do not survive a restart.
+type MemoryVault struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently put
+}
+
+type vaultEntry struct {
+	token   string
+	value   string
+	expires time.Time
+}
+
+// NewMemoryVault creates a MemoryVault.
+func NewMemoryVault(maxEntries int, ttl time.Duration) *MemoryVault {
+	return &MemoryVault{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Put implements Vault.
+func (v *MemoryVault) Put(token, value string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	expires := time.Now().Add(v.ttl)
+	if elem, ok := v.entries[token]; ok {
+		entry := elem.Value.(*vaultEntry)
+		entry.value = value
+		entry.expires = expires
+		v.order.MoveToFront(elem)
+		return
+	}
+
+	v.entries[token] = v.order.PushFront(&vaultEntry{token: token, value: value, expires: expires})
+	for v.order.Len() > v.maxEntries {
+		v.remove(v.order.Back())
+	}
+}
+
+// Get implements Vault.
+func (v *MemoryVault) Get(token string) (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	elem, ok := v.entries[token]
+	if !ok {
+		return "", false
+	}
+
+	entry := elem.Value.(*vaultEntry)
+	if time.Now().After(entry.expires) {
+		v.remove(elem)
+		return "", false
+	}
+	return entry.value, true
+}
+
+// Len returns the number of stored tokens, including expired ones not yet evicted.
+func (v *MemoryVault) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.order.Len()
+}
+
+// remove MUST be called with v.mu held
+func (v *MemoryVault) remove(elem *list.Element) {
+	v.order.Remove(elem)
+	delete(v.entries, elem.Value.(*vaultEntry).token)
+}
@@ -0,0 +1,371 @@
+// Package quota counts the requests of the API clients of authorization.clients against
+// their daily and monthly quotas (middleware.ApiKeyMiddleware). Each instance counts in
+// memory and, every quota.flush_interval, adds its counts to a shared Store and reads back
+// the totals of every instance, so a quota is enforced across instances within one interval.
+//
+// Periods are UTC calendar days and months: a daily quota resets at 00:00 UTC, a monthly
+// one on the first of the month.
+//
+// Overrides replace the configured quotas of a client without a deploy, e.g. to unblock it.
+// They are read from an OverrideSource (the quota_overrides table) and cached for a short
+// TTL, so every instance applies a change within it.
+package quota
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultFlushInterval is how often the counts are persisted when quota.flush_interval is unset
+	DefaultFlushInterval = time.Minute
+
+	// DefaultOverridesTTL is how long the overrides are cached when quota.overrides_ttl is unset
+	DefaultOverridesTTL = 30 * time.Second
+)
+
+// Periods of a quota
+const (
+	Daily   = "daily"
+	Monthly = "monthly"
+)
+
+// Store persists the usage shared by every instance, e.g. pgsql.APIUsageRepository.
+type Store interface {
+	// Add adds delta requests to the usage of client in the period starting at start, and
+	// returns its total
+	Add(ctx context.Context, client, period string, start time.Time, delta int64) (int64, error)
+}
+
+// Override replaces the quotas of a client; a nil quota keeps the configured one, and 0 is
+// unlimited.
+type Override struct {
+	Daily   *int64
+	Monthly *int64
+}
+
+// limit returns the quota of the period it sets, false when it keeps the configured one
+func (o Override) limit(period string) (int64, bool) {
+	quota := o.Daily
+	if period == Monthly {
+		quota = o.Monthly
+	}
+	if quota == nil {
+		return 0, false
+	}
+	return *quota, true
+}
+
+// OverrideSource returns the overrides in effect, by client name, e.g. service.QuotaOverrides.
+type OverrideSource func(ctx context.Context) (map[string]Override, error)
+
+// Usage is the state of a client's quota in the current period.
+type Usage struct {
+	Period    string
+	Limit     int64
+	Used      int64
+	Remaining int64
+	Reset     time.Time // the start of the next period
+}
+
+// counter counts the requests of a client in a period
+type counter struct {
+	client string
+	period string
+	limit  int64 // configured; 0 is unlimited
+
+	start    time.Time // of the period counted
+	shared   int64     // the total of every instance at the last flush
+	flushing int64     // counted before the flush in progress, being persisted
+	pending  int64     // counted since, not persisted yet
+}
+
+// used is the total count, as far as this instance knows
+func (c *counter) used() int64 {
+	return c.shared + c.flushing + c.pending
+}
+
+// roll starts counting the period of now when it differs from the one counted, returning
+// the requests of the previous period that were not persisted yet
+func (c *counter) roll(now time.Time) (stale *flush) {
+	start := periodStart(c.period, now)
+	if start.Equal(c.start) {
+		return nil
+	}
+	if c.pending > 0 {
+		stale = &flush{counter: c, start: c.start, delta: c.pending}
+	}
+	c.start, c.shared, c.flushing, c.pending = start, 0, 0, 0
+	return stale
+}
+
+func (c *counter) usage(limit int64) Usage {
+	return Usage{
+		Period:    c.period,
+		Limit:     limit,
+		Used:      c.used(),
+		Remaining: max(limit-c.used(), 0),
+		Reset:     periodEnd(c.period, c.start),
+	}
+}
+
+// flush is a count to add to the store
+type flush struct {
+	counter *counter
+	start   time.Time
+	delta   int64
+}
+
+// Quotas count the requests of the API clients. It is safe for concurrent use.
+type Quotas struct {
+	store Store
+	now   func() time.Time
+
+	clients map[[sha256.Size]byte]string // client names by the hash of their key
+	mu      sync.Mutex
+	// counters of each client, by name, for every period; unlimited ones count too, so an
+	// override limiting them starts from their usage
+	counters map[string][]*counter
+	// stale are the counts of past periods left to persist
+	stale []flush
+
+	source       OverrideSource
+	overridesTTL time.Duration
+	overrides    map[string]Override // by client name
+	overridesAt  time.Time           // when they were last read
+	refreshing   sync.Mutex
+}
+
+// FromConfig creates the quotas of authorization.clients, persisted in store. It returns
+// nil when there is no client.
+func FromConfig(cfg config.Authorization, store Store) (*Quotas, error) {
+	if len(cfg.Clients) == 0 {
+		return nil, nil
+	}
+	if store == nil {
+		return nil, errors.New("quota: no usage store")
+	}
+
+	q := &Quotas{
+		store:    store,
+		now:      time.Now,
+		clients:  make(map[[sha256.Size]byte]string, len(cfg.Clients)),
+		counters: make(map[string][]*counter, len(cfg.Clients)),
+	}
+	for _, client := range cfg.Clients {
+		hash := sha256.Sum256([]byte(client.Key))
+		if _, ok := q.clients[hash]; ok {
+			return nil, fmt.Errorf("quota: client %q reuses a key", client.Name)
+		}
+		q.clients[hash] = client.Name
+
+		q.counters[client.Name] = []*counter{
+			{client: client.Name, period: Daily, limit: client.DailyQuota},
+			{client: client.Name, period: Monthly, limit: client.MonthlyQuota},
+		}
+	}
+	return q, nil
+}
+
+// WithOverrides applies the overrides of source, read again once they are older than ttl.
+func (q *Quotas) WithOverrides(source OverrideSource, ttl time.Duration) *Quotas {
+	if ttl <= 0 {
+		ttl = DefaultOverridesTTL
+	}
+	q.source, q.overridesTTL = source, ttl
+	return q
+}
+
+// RefreshOverrides reads the overrides again, e.g. right after one changed. The overrides
+// read before are kept when it fails.
+func (q *Quotas) RefreshOverrides(ctx context.Context) error {
+	if q.source == nil {
+		return nil
+	}
+	q.refreshing.Lock()
+	defer q.refreshing.Unlock()
+
+	return q.refresh(ctx)
+}
+
+// refresh reads the overrides; q.refreshing must be held
+func (q *Quotas) refresh(ctx context.Context) error {
+	overrides, err := q.source(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.overridesAt = q.now()
+	if err != nil {
+		return fmt.Errorf("quota: overrides: %w", err)
+	}
+	q.overrides = overrides
+	return nil
+}
+
+// refreshStaleOverrides reads the overrides again once they are older than their TTL; a
+// refresh already in progress is not waited for
+func (q *Quotas) refreshStaleOverrides(ctx context.Context) {
+	if q.source == nil {
+		return
+	}
+	q.mu.Lock()
+	fresh := q.now().Sub(q.overridesAt) < q.overridesTTL
+	q.mu.Unlock()
+	if fresh || !q.refreshing.TryLock() {
+		return
+	}
+	defer q.refreshing.Unlock()
+
+	if err := q.refresh(ctx); err != nil {
+		logger.L().Warn(ctx, "quota overrides refresh failed", logger.Error(err))
+	}
+}
+
+// limit returns the quota of the counter, overridden or configured; q.mu must be held
+func (q *Quotas) limit(c *counter) int64 {
+	if limit, ok := q.overrides[c.client].limit(c.period); ok {
+		return limit
+	}
+	return c.limit
+}
+
+// Client returns the name of the client of key, false when no client has it. Keys are
+// compared by their SHA-256 hash, which leaks nothing about them through timing.
+func (q *Quotas) Client(key string) (string, bool) {
+	name, ok := q.clients[sha256.Sum256([]byte(key))]
+	return name, ok
+}
+
+// Consume counts a request of the client when none of its quotas is exhausted, and returns
+// the usage of every quota after it. It returns false, counting nothing, when one is.
+func (q *Quotas) Consume(ctx context.Context, client string) ([]Usage, bool) {
+	q.refreshStaleOverrides(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	counters := q.roll(client)
+	for _, c := range counters {
+		if limit := q.limit(c); limit > 0 && c.used() >= limit {
+			return q.usages(counters), false
+		}
+	}
+	for _, c := range counters {
+		c.pending++
+	}
+	return q.usages(counters), true
+}
+
+// Usage returns the usage of every quota of the client, none when it has no quota.
+func (q *Quotas) Usage(ctx context.Context, client string) []Usage {
+	q.refreshStaleOverrides(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.usages(q.roll(client))
+}
+
+// roll moves the counters of the client to the current periods; q.mu must be held
+func (q *Quotas) roll(client string) []*counter {
+	now := q.now()
+	counters := q.counters[client]
+	for _, c := range counters {
+		if stale := c.roll(now); stale != nil {
+			q.stale = append(q.stale, *stale)
+		}
+	}
+	return counters
+}
+
+// usages returns the usage of the limited counters; q.mu must be held
+func (q *Quotas) usages(counters []*counter) []Usage {
+	var usages []Usage
+	for _, c := range counters {
+		if limit := q.limit(c); limit > 0 {
+			usages = append(usages, c.usage(limit))
+		}
+	}
+	return usages
+}
+
+// Flush adds the requests counted since the last flush to the store and reads back the
+// totals of every instance, for every quota, so the counts of the other instances are seen
+// even without requests here. A count that fails to persist is kept for the next flush.
+func (q *Quotas) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	flushes := q.stale
+	q.stale = nil
+	now := q.now()
+	for _, counters := range q.counters {
+		for _, c := range counters {
+			if stale := c.roll(now); stale != nil {
+				flushes = append(flushes, *stale)
+			}
+			flushes = append(flushes, flush{counter: c, start: c.start, delta: c.pending})
+			c.flushing, c.pending = c.flushing+c.pending, 0
+		}
+	}
+	q.mu.Unlock()
+
+	var errs []error
+	for _, f := range flushes {
+		total, err := q.store.Add(ctx, f.counter.client, f.counter.period, f.start, f.delta)
+
+		q.mu.Lock()
+		current := f.start.Equal(f.counter.start)
+		if current {
+			f.counter.flushing -= f.delta
+		}
+		switch {
+		case err != nil && current:
+			f.counter.pending += f.delta
+		case err != nil && f.delta > 0:
+			q.stale = append(q.stale, f)
+		case err == nil && current:
+			f.counter.shared = total
+		}
+		q.mu.Unlock()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("quota: %s %s usage: %w", f.counter.client, f.counter.period, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// periodStart returns the start of the period of t, in UTC
+func periodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	if period == Monthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodEnd returns the start of the period following the one starting at start
+func periodEnd(period string, start time.Time) time.Time {
+	if period == Monthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+var current atomic.Pointer[Quotas]
+
+// SetDefault installs the quotas used by middleware.ApiKeyMiddleware. nil disables them.
+func SetDefault(q *Quotas) {
+	current.Store(q)
+}
+
+// Default returns the quotas set with SetDefault, nil when there is no client.
+func Default() *Quotas {
+	return current.Load()
+}
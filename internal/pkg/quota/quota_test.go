@@ -0,0 +1,164 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// store sums the usage in memory, failing while err is set
+type store struct {
+	totals map[string]int64
+	err    error
+}
+
+func (s *store) Add(_ context.Context, client, period string, start time.Time, delta int64) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	key := client + " " + period + " " + start.Format(time.DateOnly)
+	s.totals[key] += delta
+	return s.totals[key], nil
+}
+
+func newQuotas(t *testing.T, s *store, now *time.Time) *Quotas {
+	q, err := FromConfig(config.Authorization{Clients: []config.APIClient{
+		{Name: "partner-a", Key: "key-a", DailyQuota: 2, MonthlyQuota: 3},
+		{Name: "partner-b", Key: "key-b"},
+	}}, s)
+	require.NoError(t, err)
+	q.now = func() time.Time { return *now }
+	return q
+}
+
+func TestFromConfig(t *testing.T) {
+	q, err := FromConfig(config.Authorization{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, q, "no client")
+
+	now := time.Now()
+	q = newQuotas(t, &store{totals: map[string]int64{}}, &now)
+	name, ok := q.Client("key-b")
+	assert.True(t, ok)
+	assert.Equal(t, "partner-b", name)
+	_, ok = q.Client("key-c")
+	assert.False(t, ok)
+	assert.Empty(t, q.Usage(context.Background(), "partner-b"), "no quota")
+
+	_, err = FromConfig(config.Authorization{Clients: []config.APIClient{{Name: "a", Key: "k"}, {Name: "b", Key: "k"}}}, &store{})
+	assert.ErrorContains(t, err, "reuses a key")
+}
+
+func TestConsume(t *testing.T) {
+	now := time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+	q := newQuotas(t, &store{totals: map[string]int64{}}, &now)
+
+	usages, ok := q.Consume(context.Background(), "partner-a")
+	assert.True(t, ok)
+	assert.Equal(t, []Usage{
+		{Period: Daily, Limit: 2, Used: 1, Remaining: 1, Reset: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{Period: Monthly, Limit: 3, Used: 1, Remaining: 2, Reset: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+	}, usages)
+
+	_, ok = q.Consume(context.Background(), "partner-a")
+	assert.True(t, ok)
+	usages, ok = q.Consume(context.Background(), "partner-a")
+	assert.False(t, ok, "the daily quota is exhausted")
+	assert.Equal(t, int64(2), usages[0].Used, "not counted")
+	assert.Equal(t, int64(2), usages[1].Used)
+
+	now = now.Add(2 * time.Hour)
+	_, ok = q.Consume(context.Background(), "partner-a")
+	assert.True(t, ok, "a new day")
+	usages, ok = q.Consume(context.Background(), "partner-a")
+	assert.False(t, ok, "the monthly quota is exhausted")
+	assert.Equal(t, int64(1), usages[0].Used)
+	assert.Equal(t, int64(0), usages[1].Remaining)
+
+	usages, ok = q.Consume(context.Background(), "partner-b")
+	assert.True(t, ok, "no quota")
+	assert.Empty(t, usages)
+}
+
+func TestFlush(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := &store{totals: map[string]int64{}}
+	q := newQuotas(t, s, &now)
+	other := newQuotas(t, s, &now)
+
+	_, _ = q.Consume(context.Background(), "partner-a")
+	require.NoError(t, q.Flush(context.Background()))
+	assert.Equal(t, int64(1), s.totals["partner-a daily 2026-10-16"])
+	assert.Equal(t, int64(1), s.totals["partner-a monthly 2026-10-01"])
+
+	require.NoError(t, other.Flush(context.Background()))
+	assert.Equal(t, int64(1), other.Usage(context.Background(), "partner-a")[0].Used, "reads the counts of the other instances")
+	_, ok := other.Consume(context.Background(), "partner-a")
+	assert.True(t, ok)
+	_, ok = other.Consume(context.Background(), "partner-a")
+	assert.False(t, ok)
+
+	s.err = errors.New("connection refused")
+	_, _ = q.Consume(context.Background(), "partner-a")
+	assert.ErrorContains(t, q.Flush(context.Background()), "connection refused")
+	assert.Equal(t, int64(2), q.Usage(context.Background(), "partner-a")[0].Used, "kept")
+
+	// The day ends before the count is persisted
+	now = now.Add(24 * time.Hour)
+	s.err = nil
+	require.NoError(t, q.Flush(context.Background()))
+	assert.Equal(t, int64(2), s.totals["partner-a daily 2026-10-16"], "persisted in the day it was counted")
+	assert.Equal(t, int64(0), s.totals["partner-a daily 2026-10-17"])
+	assert.Equal(t, int64(2), s.totals["partner-a monthly 2026-10-01"])
+	assert.Equal(t, int64(0), q.Usage(context.Background(), "partner-a")[0].Used)
+	assert.Equal(t, int64(2), q.Usage(context.Background(), "partner-a")[1].Used)
+}
+
+func TestOverrides(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	q := newQuotas(t, &store{totals: map[string]int64{}}, &now)
+
+	reads := 0
+	var err error
+	unlimited, five := int64(0), int64(5)
+	overrides := map[string]Override{"partner-a": {Daily: &unlimited}, "partner-b": {Monthly: &five}}
+	q.WithOverrides(func(context.Context) (map[string]Override, error) {
+		reads++
+		return overrides, err
+	}, time.Minute)
+
+	for range 3 {
+		_, ok := q.Consume(context.Background(), "partner-a")
+		assert.True(t, ok)
+	}
+	usages := q.Usage(context.Background(), "partner-a")
+	if assert.Len(t, usages, 1, "the daily quota is lifted") {
+		assert.Equal(t, Monthly, usages[0].Period)
+		assert.Equal(t, int64(3), usages[0].Limit, "the monthly one is kept")
+	}
+	usages = q.Usage(context.Background(), "partner-b")
+	assert.Equal(t, []Usage{{Period: Monthly, Limit: 5, Remaining: 5, Reset: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}}, usages, "limited by the override")
+	assert.Equal(t, 1, reads, "cached")
+
+	overrides = nil
+	assert.Len(t, q.Usage(context.Background(), "partner-a"), 1, "until the TTL")
+	require.NoError(t, q.RefreshOverrides(context.Background()))
+	usages = q.Usage(context.Background(), "partner-a")
+	assert.Len(t, usages, 2, "removed")
+	_, ok := q.Consume(context.Background(), "partner-a")
+	assert.False(t, ok, "the daily quota was used up while lifted")
+
+	err = errors.New("connection refused")
+	overrides = map[string]Override{"partner-a": {Daily: &unlimited}}
+	now = now.Add(time.Minute)
+	assert.Len(t, q.Usage(context.Background(), "partner-a"), 2, "kept when the read fails")
+	assert.Equal(t, 3, reads)
+	assert.Len(t, q.Usage(context.Background(), "partner-a"), 2)
+	assert.Equal(t, 3, reads, "not read again until the TTL")
+}
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ExtendWriteDeadline gives the handler of ctx d from now to write its response, past
+// server.write_timeout, or lifts the deadline when d is zero. Streaming and long-running
+// handlers call it before the work, streams again per chunk so a stalled client is still cut
+// off. Writers without deadlines, e.g. httptest.ResponseRecorder, have nothing to extend.
+//
+// Usage:
+//
+//	if err := response.ExtendWriteDeadline(ctx, 5*time.Minute); err != nil {
+//	    return response.Error(ctx, err)
+//	}
+func ExtendWriteDeadline(ctx echo.Context, d time.Duration) error {
+	var deadline time.Time
+	if d > 0 {
+		deadline = time.Now().Add(d)
+	}
+	// echo.Response and the writers of the middleware unwrap down to the connection
+	err := http.NewResponseController(ctx.Response()).SetWriteDeadline(deadline)
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package response_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendWriteDeadline(t *testing.T) {
+	e := echo.New()
+	slow := func(extend time.Duration) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if extend >= 0 {
+				require.NoError(t, response.ExtendWriteDeadline(ctx, extend))
+			}
+			time.Sleep(150 * time.Millisecond)
+			return ctx.String(http.StatusOK, "done")
+		}
+	}
+	e.GET("/default", slow(-1))
+	e.GET("/extended", slow(time.Second))
+	e.GET("/lifted", slow(0))
+
+	server := httptest.NewUnstartedServer(e)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	t.Cleanup(server.Close)
+
+	get := func(path string) (string, error) {
+		res, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		return string(body), err
+	}
+
+	_, err := get("/default")
+	assert.Error(t, err, "cut off by the write timeout")
+
+	for _, path := range []string{"/extended", "/lifted"} {
+		body, err := get(path)
+		if assert.NoError(t, err, path) {
+			assert.Equal(t, "done", body)
+		}
+	}
+
+	t.Run("Without Deadlines", func(t *testing.T) {
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		assert.NoError(t, response.ExtendWriteDeadline(ctx, time.Minute))
+	})
+}
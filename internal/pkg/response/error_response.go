@@ -1,9 +1,10 @@
 package response
 
 import (
-	"fmt"
+	"errors"
 	"go-echo-boilerplate/internal/models"
 	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
 	"go-echo-boilerplate/internal/pkg/stringc"
 	"time"
 
@@ -14,31 +15,33 @@ import (
 
 func Error(ctx echo.Context, err error, message ...string) error {
 	var response models.Response
+	locale := i18n.FromContext(ctx.Request().Context())
 
 	if err == nil {
-		errorcResponse := errorc.GetResponse(errorc.ErrorInternalServer)
+		errorcResponse := errorc.Localize(errorc.ErrorInternalServer, locale)
 		response = models.Response{
 			Code:    errorcResponse.Code,
 			Status:  errorcResponse.Status,
 			Message: errorcResponse.Message,
 		}
 	} else {
-		errorcResponse := errorc.GetResponse(err)
+		errorcResponse := errorc.Localize(err, locale)
 
 		var finalMessage string
 		switch len(message) {
 		case 0:
 			finalMessage = errorcResponse.Message
 		case 1:
-			finalMessage = message[0]
+			finalMessage = i18n.T(locale, message[0])
 		default:
-			finalMessage = fmt.Sprintf(message[0], stringc.SlicesToInterfaces(message[1:])...)
+			finalMessage = i18n.T(locale, message[0], stringc.SlicesToInterfaces(message[1:])...)
 		}
 
 		response = models.Response{
 			Code:    errorcResponse.Code,
 			Status:  errorcResponse.Status,
 			Message: finalMessage,
+			Errors:  errorcResponse.Errors,
 		}
 	}
 
@@ -71,7 +74,7 @@ func ErrorValidation(ctx echo.Context, errors interface{}) error {
 		timestamp = time.Now().Format(time.RFC3339)
 	}
 
-	errorcResponse := errorc.GetResponse(errorc.ErrorValidation)
+	errorcResponse := errorc.Localize(errorc.ErrorValidation, i18n.FromContext(ctx.Request().Context()))
 	response := models.Response{
 		Code:    errorcResponse.Code,
 		Status:  errorcResponse.Status,
@@ -88,3 +91,14 @@ func ErrorValidation(ctx echo.Context, errors interface{}) error {
 
 	return ctx.JSON(response.Code, response)
 }
+
+// ErrorBinding writes the response for an error returned by the binder package:
+// validation errors use the ErrorValidation format, everything else goes through Error.
+func ErrorBinding(ctx echo.Context, err error) error {
+	var validationErrs *multierror.Error
+	if errors.As(err, &validationErrs) {
+		return ErrorValidation(ctx, validationErrs)
+	}
+
+	return Error(ctx, err)
+}
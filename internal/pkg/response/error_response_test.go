@@ -0,0 +1,84 @@
+package response_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/response"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorResponseShape keeps models.ErrorResponse, which documents every error in the
+// OpenAPI spec, in line with what the error helpers write
+func TestErrorResponseShape(t *testing.T) {
+	type request struct {
+		Email string `json:"email" validate:"required"`
+	}
+
+	write := map[string]func(ctx echo.Context) error{
+		"Error": func(ctx echo.Context) error {
+			return response.Error(ctx, errorc.Error(errorc.ErrorUserNotFound))
+		},
+		"ErrorValidation": func(ctx echo.Context) error {
+			return response.ErrorValidation(ctx, validator.Input(request{}))
+		},
+		"ErrorBinding": func(ctx echo.Context) error {
+			return response.ErrorBinding(ctx, errors.New("bad input"))
+		},
+	}
+
+	for name, fn := range write {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			require.NoError(t, fn(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+
+			var body models.ErrorResponse
+			decoder := json.NewDecoder(bytes.NewReader(rec.Body.Bytes()))
+			decoder.DisallowUnknownFields()
+			require.NoError(t, decoder.Decode(&body))
+			assert.Equal(t, rec.Code, body.Code)
+			assert.NotEmpty(t, body.Status)
+			assert.NotEmpty(t, body.Metadata.RequestId)
+		})
+	}
+
+	t.Run("Localized Message With Arguments", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(i18n.WithLocale(req.Context(), i18n.Indonesian))
+		ctx := echo.New().NewContext(req, rec)
+		require.NoError(t, response.Error(ctx, errorc.Error(errorc.ErrorInvalidInput), "validation.required", "email"))
+
+		var body models.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "email wajib diisi", body.Message)
+	})
+
+	t.Run("Validation Errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		require.NoError(t, response.ErrorValidation(ctx, validator.Input(request{})))
+
+		var body struct {
+			models.ErrorResponse
+			Errors []models.ErrorValidationResponse `json:"errors"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(rec.Body.Bytes()))
+		decoder.DisallowUnknownFields()
+		require.NoError(t, decoder.Decode(&body))
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "email", body.Errors[0].Field)
+		assert.NotEmpty(t, body.Errors[0].Code)
+	})
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ETag returns a weak entity tag for data, derived from its JSON encoding.
+// The response envelope is not part of it, since request_id and timestamp change every time.
+func ETag(data interface{}, vary ...string) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(encoded)
+	for _, v := range vary {
+		hash.Write([]byte{0})
+		hash.Write([]byte(v))
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`, nil
+}
+
+// NotModified sets the ETag header and reports whether the client copy is current, i.e.
+// If-None-Match lists etag (weak comparison) or "*". The handler then answers 304 with no body.
+func NotModified(ctx echo.Context, etag string) bool {
+	ctx.Response().Header().Set("ETag", etag)
+
+	ifNoneMatch := ctx.Request().Header.Get("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SuccessWithETag responds like Success with status 200 and an ETag of data, or with
+// 304 Not Modified when the client already has it. Use it for GET endpoints that clients poll.
+//
+// The response is private and must be revalidated, so shared caches never store it.
+func SuccessWithETag(ctx echo.Context, data interface{}, message ...string) error {
+	// The message is localized and the fields are selected, so the locale and the selection
+	// are part of the representation
+	locale := i18n.FromContext(ctx.Request().Context())
+	etag, err := ETag(data, string(locale), ctx.QueryParam(FieldsParam))
+	if err != nil {
+		return Success(ctx, http.StatusOK, data, message...)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "private, no-cache")
+	if NotModified(ctx, etag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+	return Success(ctx, http.StatusOK, data, message...)
+}
@@ -0,0 +1,79 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	a, err := response.ETag(map[string]string{"name": "Jane"})
+	require.NoError(t, err)
+	b, err := response.ETag(map[string]string{"name": "Jane"})
+	require.NoError(t, err)
+	c, err := response.ETag(map[string]string{"name": "John"})
+	require.NoError(t, err)
+	d, err := response.ETag(map[string]string{"name": "Jane"}, "id")
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, a)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.NotEqual(t, a, d, "vary values are part of the tag")
+}
+
+func TestSuccessWithETag(t *testing.T) {
+	data := map[string]string{"name": "Jane"}
+	e := echo.New()
+	e.GET("/me", func(ctx echo.Context) error {
+		return response.SuccessWithETag(ctx, data)
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", first.Header().Get(echo.HeaderCacheControl))
+	assert.Contains(t, first.Body.String(), "Jane")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		code        int
+	}{
+		{"Match", etag, http.StatusNotModified},
+		{"Match In List", `"other", ` + etag, http.StatusNotModified},
+		{"Strong Form", etag[2:], http.StatusNotModified},
+		{"Any", "*", http.StatusNotModified},
+		{"Stale", `W/"0123"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.ifNoneMatch)
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, etag, rec.Header().Get("ETag"))
+			if tt.code == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+
+	data["name"] = "John"
+	assert.Equal(t, http.StatusOK, get(etag).Code, "a changed resource is sent again")
+}
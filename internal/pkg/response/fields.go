@@ -0,0 +1,90 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FieldsParam is the query parameter selecting the top-level fields of the data of a success
+// response, e.g. ?fields=accountNumber,name, to cut the payloads of mobile clients
+const FieldsParam = "fields"
+
+// SelectFields keeps the fields of data named by the fields query parameter: the members of
+// data when it encodes to a JSON object, of each of its elements when it encodes to an array
+// of objects. Only top-level members are selected, in the order of data; the unknown names
+// are ignored. data is returned as is without the parameter, or when it is neither.
+func SelectFields(ctx echo.Context, data interface{}) (interface{}, error) {
+	fields := requestedFields(ctx)
+	if len(fields) == 0 || data == nil {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	encoded = bytes.TrimSpace(encoded)
+	switch {
+	case len(encoded) > 0 && encoded[0] == '{':
+		return selectMembers(encoded, fields)
+	case len(encoded) > 0 && encoded[0] == '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal(encoded, &elements); err != nil {
+			return nil, err
+		}
+		for i, element := range elements {
+			if len(element) == 0 || element[0] != '{' {
+				return data, nil
+			}
+			if elements[i], err = selectMembers(element, fields); err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	}
+	return data, nil
+}
+
+// selectFields is SelectFields for the success responses, data kept whole when it can't be
+// encoded, ctx.JSON reporting the error then
+func selectFields(ctx echo.Context, data interface{}) interface{} {
+	selected, err := SelectFields(ctx, data)
+	if err != nil {
+		return data
+	}
+	return selected
+}
+
+// requestedFields is the set of the names of the fields query parameter, empty without one
+func requestedFields(ctx echo.Context) map[string]bool {
+	fields := map[string]bool{}
+	for _, value := range ctx.QueryParams()[FieldsParam] {
+		for name := range strings.SplitSeq(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				fields[name] = true
+			}
+		}
+	}
+	return fields
+}
+
+// selectMembers keeps the members of the JSON object named in fields, in their order; the
+// snake_case names of the members are accepted too, for the clients of FormatSnakeCase
+func selectMembers(object json.RawMessage, fields map[string]bool) (json.RawMessage, error) {
+	var selected bytes.Buffer
+	selected.WriteByte('{')
+	err := eachMember(object, func(name string, value json.RawMessage) error {
+		if fields[name] || fields[snakeCase(name)] {
+			writeMember(&selected, name, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	selected.WriteByte('}')
+	return selected.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+package response_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldsItem struct {
+	AccountNumber string `json:"accountNumber"`
+	Name          string `json:"name"`
+	Email         string `json:"email,omitempty"`
+}
+
+func TestSelectFields(t *testing.T) {
+	selectFields := func(target string, data interface{}) string {
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), httptest.NewRecorder())
+		selected, err := response.SelectFields(ctx, data)
+		require.NoError(t, err)
+		encoded, err := json.Marshal(selected)
+		require.NoError(t, err)
+		return string(encoded)
+	}
+	item := fieldsItem{AccountNumber: "1234567890", Name: "Jane", Email: "jane@example.com"}
+
+	t.Run("Object", func(t *testing.T) {
+		assert.Equal(t, `{"accountNumber":"1234567890","name":"Jane"}`, selectFields("/me?fields=name,accountNumber,unknown", &item))
+	})
+
+	t.Run("Array", func(t *testing.T) {
+		assert.Equal(t, `[{"name":"Jane"},{"name":"John"}]`, selectFields("/users?fields=name", []fieldsItem{item, {Name: "John"}}))
+	})
+
+	t.Run("Untouched", func(t *testing.T) {
+		assert.Equal(t, `{"accountNumber":"1234567890","name":"Jane","email":"jane@example.com"}`, selectFields("/me", item))
+		assert.Equal(t, `["a","b"]`, selectFields("/tags?fields=name", []string{"a", "b"}))
+		assert.Equal(t, `42`, selectFields("/count?fields=name", 42))
+	})
+}
+
+func TestSuccess_Fields(t *testing.T) {
+	e := echo.New()
+	e.GET("/me", func(ctx echo.Context) error {
+		return response.Success(ctx, http.StatusOK, fieldsItem{AccountNumber: "1234567890", Name: "Jane"})
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me?fields=name", nil))
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{"name": "Jane"}, body.Data)
+}
@@ -0,0 +1,404 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Formats of NewSerializer, see response.format
+const (
+	FormatCamelCase = "camelCase"
+	FormatSnakeCase = "snake_case"
+	FormatJSONAPI   = "jsonapi"
+)
+
+// MIMEJSONAPI is the media type of the JSON:API documents
+const MIMEJSONAPI = "application/vnd.api+json"
+
+// NewSerializer returns the JSON serializer of echo for format: the DTOs as tagged (camelCase),
+// the fields of every struct rewritten in snake_case (map keys, e.g. the headers of a debug
+// capture, are data and kept), or the envelopes of models.Response and
+// models.ErrorResponse converted to JSON:API documents (see jsonAPIDocument). Request bodies
+// are decoded as tagged whatever the format. The blobs written as is (ctx.JSONBlob, the
+// streams of StreamNDJSON) aren't converted.
+func NewSerializer(format string) echo.JSONSerializer {
+	switch format {
+	case FormatSnakeCase:
+		return &serializer{convert: func(encoded json.RawMessage, i interface{}) (json.RawMessage, error) {
+			return renameFields(encoded, reflect.ValueOf(i), snakeCase)
+		}}
+	case FormatJSONAPI:
+		return &serializer{convert: func(encoded json.RawMessage, _ interface{}) (json.RawMessage, error) {
+			return jsonAPIDocument(encoded)
+		}, mime: MIMEJSONAPI}
+	}
+	return echo.DefaultJSONSerializer{}
+}
+
+// serializer converts the JSON of the default serializer
+type serializer struct {
+	echo.DefaultJSONSerializer // decodes the requests
+	convert                    func(encoded json.RawMessage, i interface{}) (json.RawMessage, error)
+	mime                       string // replaces application/json when set
+}
+
+func (s *serializer) Serialize(ctx echo.Context, i interface{}, indent string) error {
+	encoded, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	converted, err := s.convert(encoded, i)
+	if err != nil {
+		return err
+	}
+
+	// ctx.JSON set the type, the header isn't written before the body
+	header := ctx.Response().Header()
+	if s.mime != "" && header.Get(echo.HeaderContentType) == echo.MIMEApplicationJSON {
+		header.Set(echo.HeaderContentType, s.mime)
+	}
+
+	if indent != "" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, converted, "", indent); err != nil {
+			return err
+		}
+		converted = indented.Bytes()
+	}
+	// Ends with a newline like the json.Encoder of the default serializer
+	_, err = ctx.Response().Write(append(converted, '\n'))
+	return err
+}
+
+// snakeCase converts a camelCase or PascalCase name, e.g. requestId to request_id and
+// HTTPStatus to http_status; other names are kept, lowercased
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var converted []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || acronymEnd {
+				converted = append(converted, '_')
+			}
+			r = unicode.ToLower(r)
+		}
+		converted = append(converted, r)
+	}
+	return string(converted)
+}
+
+// jsonMarshaler is the type of the values encoding themselves
+var jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+
+// renameFields renames the members of encoded, the JSON of value, that are fields of a struct,
+// nested ones included, keeping their order. The keys of maps are kept, their values renamed,
+// and so is the JSON of the values encoding themselves (json.Marshaler).
+func renameFields(encoded json.RawMessage, value reflect.Value, rename func(string) string) (json.RawMessage, error) {
+	encoded = bytes.TrimSpace(encoded)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return encoded, nil
+		}
+		value = value.Elem()
+	}
+	if len(encoded) == 0 || !value.IsValid() || value.Type().Implements(jsonMarshaler) ||
+		(value.CanAddr() && reflect.PointerTo(value.Type()).Implements(jsonMarshaler)) {
+		return encoded, nil
+	}
+
+	switch {
+	case encoded[0] == '[' && (value.Kind() == reflect.Slice || value.Kind() == reflect.Array):
+		var elements []json.RawMessage
+		if err := json.Unmarshal(encoded, &elements); err != nil {
+			return nil, err
+		}
+		for i := range elements {
+			if i >= value.Len() {
+				break
+			}
+			renamed, err := renameFields(elements[i], value.Index(i), rename)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = renamed
+		}
+		return json.Marshal(elements)
+	case encoded[0] == '{' && (value.Kind() == reflect.Struct || value.Kind() == reflect.Map):
+		var fields map[string]reflect.Value
+		if value.Kind() == reflect.Struct {
+			fields = make(map[string]reflect.Value)
+			jsonFields(value, fields)
+		}
+
+		var renamed bytes.Buffer
+		renamed.WriteByte('{')
+		err := eachMember(encoded, func(name string, member json.RawMessage) error {
+			var field reflect.Value
+			if fields != nil {
+				field = fields[name]
+				name = rename(name)
+			} else if value.Type().Key().Kind() == reflect.String {
+				field = value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+			}
+			member, err := renameFields(member, field, rename)
+			if err != nil {
+				return err
+			}
+			writeMember(&renamed, name, member)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		renamed.WriteByte('}')
+		return renamed.Bytes(), nil
+	}
+	return encoded, nil
+}
+
+// jsonFields adds the fields of the struct value to fields by their JSON name, the fields of
+// embedded structs after the fields of value, which they can't shadow
+func jsonFields(value reflect.Value, fields map[string]reflect.Value) {
+	var embedded []reflect.Value
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			inner := value.Field(i)
+			if inner.Kind() == reflect.Pointer {
+				if inner.IsNil() {
+					continue
+				}
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				embedded = append(embedded, inner)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = value.Field(i)
+	}
+
+	for _, inner := range embedded {
+		innerFields := make(map[string]reflect.Value)
+		jsonFields(inner, innerFields)
+		for name, field := range innerFields {
+			if _, ok := fields[name]; !ok {
+				fields[name] = field
+			}
+		}
+	}
+}
+
+// eachMember calls fn with the members of the JSON object, in their order
+func eachMember(object json.RawMessage, fn func(name string, value json.RawMessage) error) error {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+	if _, err := decoder.Token(); err != nil { // {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		name, _ := token.(string)
+		if err := fn(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMember writes a member of an object to buf, after a comma unless it is the first
+func writeMember(buf *bytes.Buffer, name string, value json.RawMessage) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	key, _ := json.Marshal(name)
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(value)
+}
+
+// jsonAPIDocument converts the envelope of models.Response or models.ErrorResponse to a
+// JSON:API document (https://jsonapi.org/format/):
+//
+//   - data becomes resource objects: the type and id members of each object its type (or
+//     "resource") and id (or its accountNumber), the other members its attributes; data of
+//     another kind is kept
+//   - errors become error objects with the status, code, and message of the envelope, a
+//     validation error detailing its field in source.pointer
+//   - message, pagination, and metadata become meta, links stay links
+//
+// Other JSON, e.g. of a handler answering without the envelope, is kept.
+func jsonAPIDocument(encoded json.RawMessage) (json.RawMessage, error) {
+	var envelope struct {
+		Code       int             `json:"code"`
+		Status     string          `json:"status"`
+		Message    string          `json:"message"`
+		Data       json.RawMessage `json:"data"`
+		Pagination json.RawMessage `json:"pagination"`
+		Links      json.RawMessage `json:"links"`
+		Errors     json.RawMessage `json:"errors"`
+		Metadata   map[string]any  `json:"metadata"`
+	}
+	if len(encoded) == 0 || encoded[0] != '{' || json.Unmarshal(encoded, &envelope) != nil ||
+		envelope.Code == 0 || envelope.Status == "" || envelope.Metadata == nil {
+		return encoded, nil
+	}
+
+	meta := envelope.Metadata
+	if envelope.Message != "" {
+		meta["message"] = envelope.Message
+	}
+	if len(envelope.Pagination) > 0 {
+		meta["pagination"] = envelope.Pagination
+	}
+	document := map[string]any{"meta": meta}
+	if len(envelope.Links) > 0 {
+		document["links"] = envelope.Links
+	}
+
+	if envelope.Code >= 400 {
+		document["errors"] = jsonAPIErrors(envelope.Code, envelope.Status, envelope.Message, envelope.Errors)
+		return json.Marshal(document)
+	}
+
+	data, err := jsonAPIData(envelope.Data)
+	if err != nil {
+		return nil, err
+	}
+	document["data"] = data
+	return json.Marshal(document)
+}
+
+// jsonAPIData converts the data of an envelope to resource objects
+func jsonAPIData(data json.RawMessage) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	switch data[0] {
+	case '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return nil, err
+		}
+		for i := range elements {
+			resource, err := jsonAPIData(elements[i])
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = resource
+		}
+		return json.Marshal(elements)
+	case '{':
+		return jsonAPIResource(data)
+	}
+	return data, nil
+}
+
+// jsonAPIResource converts an object to a resource object
+func jsonAPIResource(object json.RawMessage) (json.RawMessage, error) {
+	resourceType, id, accountNumber := json.RawMessage(`"resource"`), json.RawMessage(nil), json.RawMessage(nil)
+	var attributes bytes.Buffer
+	attributes.WriteByte('{')
+	err := eachMember(object, func(name string, value json.RawMessage) error {
+		switch name {
+		case "type":
+			resourceType = value
+		case "id":
+			id = value
+		default:
+			if name == "accountNumber" {
+				accountNumber = value
+			}
+			writeMember(&attributes, name, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	attributes.WriteByte('}')
+
+	if id == nil {
+		id = accountNumber
+	}
+	var resource bytes.Buffer
+	resource.WriteByte('{')
+	writeMember(&resource, "type", resourceType)
+	if id != nil && string(id) != "null" {
+		writeMember(&resource, "id", jsonAPIID(id))
+	}
+	writeMember(&resource, "attributes", attributes.Bytes())
+	resource.WriteByte('}')
+	return resource.Bytes(), nil
+}
+
+// jsonAPIID is the id as a string, which JSON:API requires
+func jsonAPIID(id json.RawMessage) json.RawMessage {
+	if len(id) > 0 && id[0] == '"' {
+		return id
+	}
+	quoted, _ := json.Marshal(string(id))
+	return quoted
+}
+
+// jsonAPIError is an error object of a JSON:API document
+type jsonAPIError struct {
+	Status string `json:"status"`
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+	Source *struct {
+		Pointer string `json:"pointer"`
+	} `json:"source,omitempty"`
+}
+
+// jsonAPIErrors converts the error of an envelope, an error object per validation error
+func jsonAPIErrors(code int, status, message string, errs json.RawMessage) []jsonAPIError {
+	var validation []struct {
+		Code    string `json:"code"`
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+	if len(errs) == 0 || json.Unmarshal(errs, &validation) != nil || len(validation) == 0 {
+		return []jsonAPIError{{Status: strconv.Itoa(code), Code: status, Title: message}}
+	}
+
+	converted := make([]jsonAPIError, 0, len(validation))
+	for _, e := range validation {
+		jsonAPIErr := jsonAPIError{Status: strconv.Itoa(code), Code: e.Code, Title: message, Detail: e.Message}
+		if e.Field != "" {
+			jsonAPIErr.Source = &struct {
+				Pointer string `json:"pointer"`
+			}{Pointer: "/data/attributes/" + e.Field}
+		}
+		converted = append(converted, jsonAPIErr)
+	}
+	return converted
+}
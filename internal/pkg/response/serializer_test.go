@@ -0,0 +1,136 @@
+package response_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serializerItem struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	HTTPCode  int    `json:"HTTPCode"`
+	CreatedBy string `json:"createdBy"`
+}
+
+func serveFormat(t *testing.T, format string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	e.JSONSerializer = response.NewSerializer(format)
+	e.GET("/", handler)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec
+}
+
+func TestNewSerializer_CamelCase(t *testing.T) {
+	rec := serveFormat(t, response.FormatCamelCase, func(ctx echo.Context) error {
+		return response.Success(ctx, http.StatusOK, serializerItem{ID: 1, Name: "Jane", CreatedBy: "admin"})
+	})
+
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"requestId"`)
+	assert.Contains(t, rec.Body.String(), `"createdBy":"admin"`)
+}
+
+func TestNewSerializer_SnakeCase(t *testing.T) {
+	rec := serveFormat(t, response.FormatSnakeCase, func(ctx echo.Context) error {
+		return response.Success(ctx, http.StatusOK, []serializerItem{{ID: 1, Name: "Jane", HTTPCode: 200, CreatedBy: "admin"}})
+	})
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []any{map[string]any{"id": float64(1), "name": "Jane", "http_code": float64(200), "created_by": "admin"}}, body["data"])
+	assert.Contains(t, body["metadata"], "request_id")
+	assert.NotContains(t, body["metadata"], "requestId")
+}
+
+func TestNewSerializer_SnakeCaseKeepsMapKeys(t *testing.T) {
+	rec := serveFormat(t, response.FormatSnakeCase, func(ctx echo.Context) error {
+		return response.Success(ctx, http.StatusOK, map[string]any{
+			"X-Request-Id": "abc",
+			"ownerOf":      serializerItem{ID: 1, CreatedBy: "admin"},
+		})
+	})
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{
+		"X-Request-Id": "abc",
+		"ownerOf":      map[string]any{"id": float64(1), "name": "", "http_code": float64(0), "created_by": "admin"},
+	}, body["data"], "map keys are data, the fields of the structs in them are renamed")
+}
+
+func TestNewSerializer_JSONAPI(t *testing.T) {
+	t.Run("Data", func(t *testing.T) {
+		rec := serveFormat(t, response.FormatJSONAPI, func(ctx echo.Context) error {
+			return response.Success(ctx, http.StatusOK, serializerItem{ID: 7, Name: "Jane"})
+		})
+
+		assert.Equal(t, response.MIMEJSONAPI, rec.Header().Get(echo.HeaderContentType))
+		var document struct {
+			Data map[string]any `json:"data"`
+			Meta map[string]any `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+		assert.Equal(t, "resource", document.Data["type"])
+		assert.Equal(t, "7", document.Data["id"])
+		assert.Equal(t, map[string]any{"name": "Jane", "HTTPCode": float64(0), "createdBy": ""}, document.Data["attributes"])
+		assert.Contains(t, document.Meta, "requestId")
+		assert.Contains(t, document.Meta, "message")
+		assert.NotContains(t, rec.Body.String(), `"code"`)
+	})
+
+	t.Run("Validation Errors", func(t *testing.T) {
+		rec := serveFormat(t, response.FormatJSONAPI, func(ctx echo.Context) error {
+			return response.ErrorValidation(ctx, multierror.Append(nil,
+				models.ErrorValidationResponse{Code: "MISSING_FIELD", Field: "email", Message: "email is required"}))
+		})
+
+		var document struct {
+			Errors []map[string]any `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+		require.Len(t, document.Errors, 1)
+		assert.Equal(t, "MISSING_FIELD", document.Errors[0]["code"])
+		assert.Equal(t, "email is required", document.Errors[0]["detail"])
+		assert.Equal(t, map[string]any{"pointer": "/data/attributes/email"}, document.Errors[0]["source"])
+		assert.Equal(t, "400", document.Errors[0]["status"])
+	})
+
+	t.Run("Without The Envelope", func(t *testing.T) {
+		rec := serveFormat(t, response.FormatJSONAPI, func(ctx echo.Context) error {
+			return ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		})
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+}
+
+func TestNewSerializer_Deserialize(t *testing.T) {
+	e := echo.New()
+	e.JSONSerializer = response.NewSerializer(response.FormatSnakeCase)
+	e.POST("/", func(ctx echo.Context) error {
+		var item serializerItem
+		if err := ctx.Bind(&item); err != nil {
+			return err
+		}
+		return ctx.String(http.StatusOK, item.CreatedBy)
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"createdBy":"admin"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "admin", rec.Body.String(), "request bodies keep the tagged names")
+}
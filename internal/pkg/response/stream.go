@@ -0,0 +1,159 @@
+package response
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StreamChunkSize is the number of rows a streamed response writes between flushes
+const StreamChunkSize = 500
+
+// StreamWriteTimeout is the time each write of a streamed response has to reach the client.
+// It replaces server.write_timeout, which would cut the stream of a large export off.
+const StreamWriteTimeout = 30 * time.Second
+
+// MIMEApplicationNDJSON is the media type of newline-delimited JSON
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// numericCell matches the cells StreamCSV leaves alone although they start like a formula,
+// e.g. E.164 phone numbers
+var numericCell = regexp.MustCompile(`^[+-]?[0-9][0-9.]*$`)
+
+// StreamCSV answers 200 with the CSV attachment filename: the header row, then the records
+// produce writes. Rows are flushed to the client every StreamChunkSize rows, so an export of
+// any size never sits in memory, and each write extends the deadline by StreamWriteTimeout
+// (see ExtendWriteDeadline), so it isn't cut off either. Cells starting with =, +, -, or @
+// (numbers aside) are prefixed with ' so spreadsheets do not evaluate them.
+//
+// An error of produce before anything was sent answers with Error; once rows are on their way
+// the status is gone, the body just ends short and the error is returned for the logs.
+//
+// Usage:
+//
+//	return response.StreamCSV(ctx, "users.csv", []string{"name"}, func(write func([]string) error) error {
+//	    return h.service.User.Export(ctx.Request().Context(), func(user *models.User) error {
+//	        return write([]string{user.Name})
+//	    })
+//	})
+func StreamCSV(ctx echo.Context, filename string, header []string, produce func(write func(record []string) error) error) error {
+	w := csv.NewWriter(&streamWriter{ctx: ctx, contentType: "text/csv; charset=utf-8", filename: filename})
+
+	rows := 0
+	write := func(record []string) error {
+		escaped := make([]string, len(record))
+		for i, cell := range record {
+			escaped[i] = escapeFormula(cell)
+		}
+		if err := w.Write(escaped); err != nil {
+			return err
+		}
+		if rows++; rows%StreamChunkSize == 0 {
+			w.Flush()
+			if err := w.Error(); err != nil {
+				return err
+			}
+			flush(ctx)
+		}
+		return nil
+	}
+
+	if err := write(header); err != nil {
+		return streamFailed(ctx, err)
+	}
+	if err := produce(write); err != nil {
+		return streamFailed(ctx, err)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// StreamNDJSON answers 200 with the NDJSON attachment filename, a line per value produce
+// writes, flushed like StreamCSV. Errors are handled like those of StreamCSV.
+func StreamNDJSON(ctx echo.Context, filename string, produce func(write func(v any) error) error) error {
+	w := bufio.NewWriter(&streamWriter{ctx: ctx, contentType: MIMEApplicationNDJSON, filename: filename})
+	encoder := json.NewEncoder(w)
+
+	rows := 0
+	write := func(v any) error {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		if rows++; rows%StreamChunkSize == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			flush(ctx)
+		}
+		return nil
+	}
+
+	if err := produce(write); err != nil {
+		return streamFailed(ctx, err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !ctx.Response().Committed {
+		// Nothing to export, still an empty attachment
+		(&streamWriter{ctx: ctx, contentType: MIMEApplicationNDJSON, filename: filename}).commit()
+	}
+	return nil
+}
+
+// streamWriter sends the status and headers of a streamed attachment with its first bytes,
+// and gives each write StreamWriteTimeout
+type streamWriter struct {
+	ctx         echo.Context
+	contentType string
+	filename    string
+}
+
+func (w *streamWriter) Write(b []byte) (int, error) {
+	if err := ExtendWriteDeadline(w.ctx, StreamWriteTimeout); err != nil {
+		return 0, err
+	}
+	w.commit()
+	return w.ctx.Response().Write(b)
+}
+
+func (w *streamWriter) commit() {
+	response := w.ctx.Response()
+	if response.Committed {
+		return
+	}
+	response.Header().Set(echo.HeaderContentType, w.contentType)
+	response.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": w.filename}))
+	response.WriteHeader(http.StatusOK)
+}
+
+// flush sends the buffered rows to the client, when the writer supports it
+func flush(ctx echo.Context) {
+	_ = http.NewResponseController(ctx.Response().Writer).Flush()
+}
+
+// streamFailed answers with the error while nothing was sent yet
+func streamFailed(ctx echo.Context, err error) error {
+	if ctx.Response().Committed {
+		return err
+	}
+	return Error(ctx, err)
+}
+
+// escapeFormula keeps a cell from being evaluated as a spreadsheet formula
+func escapeFormula(cell string) string {
+	if cell == "" || numericCell.MatchString(cell) {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,141 @@
+package response_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(handler echo.HandlerFunc) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.GET("/export", handler)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+	return rec
+}
+
+func TestStreamCSV(t *testing.T) {
+	t.Run("Streams The Rows", func(t *testing.T) {
+		rec := serve(func(ctx echo.Context) error {
+			return response.StreamCSV(ctx, "users.csv", []string{"name", "phone"}, func(write func([]string) error) error {
+				for i := range response.StreamChunkSize + 1 {
+					if err := write([]string{fmt.Sprintf("User %d", i), "+6281234567890"}); err != nil {
+						return err
+					}
+				}
+				return write([]string{"=HYPERLINK(\"x\")", "-1"})
+			})
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, `attachment; filename=users.csv`, rec.Header().Get(echo.HeaderContentDisposition))
+		assert.True(t, rec.Flushed)
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		require.Len(t, lines, response.StreamChunkSize+3)
+		assert.Equal(t, "name,phone", lines[0])
+		assert.Equal(t, "User 0,+6281234567890", lines[1])
+		assert.Equal(t, `"'=HYPERLINK(""x"")",-1`, lines[len(lines)-1], "formulas are escaped, numbers are not")
+	})
+
+	t.Run("Outlasts The Write Timeout", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/export", func(ctx echo.Context) error {
+			return response.StreamCSV(ctx, "users.csv", []string{"name"}, func(write func([]string) error) error {
+				for i := range 3 * response.StreamChunkSize {
+					if i%response.StreamChunkSize == 0 {
+						time.Sleep(40 * time.Millisecond) // a slow page of the export
+					}
+					if err := write([]string{fmt.Sprintf("User %d", i)}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		server := httptest.NewUnstartedServer(e)
+		server.Config.WriteTimeout = 50 * time.Millisecond
+		server.Start()
+		defer server.Close()
+
+		res, err := server.Client().Get(server.URL + "/export")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err, "the stream isn't cut off")
+		assert.Len(t, strings.Split(strings.TrimSpace(string(body)), "\n"), 3*response.StreamChunkSize+1)
+	})
+
+	t.Run("Early Error Answers With It", func(t *testing.T) {
+		rec := serve(func(ctx echo.Context) error {
+			return response.StreamCSV(ctx, "users.csv", []string{"name"}, func(write func([]string) error) error {
+				return errorc.Error(errorc.ErrorDatabase)
+			})
+		})
+
+		assert.Equal(t, errorc.ErrorDatabase.Response.Code, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	})
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	t.Run("Streams The Values", func(t *testing.T) {
+		rec := serve(func(ctx echo.Context) error {
+			return response.StreamNDJSON(ctx, "users.ndjson", func(write func(any) error) error {
+				for i := range 3 {
+					if err := write(map[string]int{"id": i}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response.MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "{\"id\":0}\n{\"id\":1}\n{\"id\":2}\n", rec.Body.String())
+	})
+
+	t.Run("Empty Export", func(t *testing.T) {
+		rec := serve(func(ctx echo.Context) error {
+			return response.StreamNDJSON(ctx, "users.ndjson", func(write func(any) error) error { return nil })
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `attachment; filename=users.ndjson`, rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("Late Error Ends The Body", func(t *testing.T) {
+		failure := errors.New("connection lost")
+		var returned error
+		rec := serve(func(ctx echo.Context) error {
+			returned = response.StreamNDJSON(ctx, "users.ndjson", func(write func(any) error) error {
+				for i := range response.StreamChunkSize {
+					if err := write(i); err != nil {
+						return err
+					}
+				}
+				return failure
+			})
+			return returned
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.ErrorIs(t, returned, failure)
+		assert.Equal(t, response.StreamChunkSize, strings.Count(rec.Body.String(), "\n"))
+	})
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"fmt"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
 	"go-echo-boilerplate/internal/pkg/numberc"
 	"go-echo-boilerplate/internal/pkg/stringc"
 	"net/http"
@@ -23,16 +24,20 @@ func Success(ctx echo.Context, code int, data interface{}, message ...string) er
 		timestamp = time.Now().Format(time.RFC3339)
 	}
 
+	locale := i18n.FromContext(ctx.Request().Context())
+
 	var finalMessage string
 	switch len(message) {
 	case 0:
-		finalMessage = "Request has been successfully processed."
+		finalMessage = i18n.T(locale, "Request has been successfully processed.")
 	case 1:
-		finalMessage = message[0]
+		finalMessage = i18n.T(locale, message[0])
 	default:
 		finalMessage = fmt.Sprintf(message[0], stringc.SlicesToInterfaces(message[1:])...)
 	}
 
+	data = selectFields(ctx, data)
+
 	return ctx.JSON(code, models.Response{
 		Code:    code,
 		Status:  "OK",
@@ -59,6 +64,7 @@ func SuccessList(ctx echo.Context, code int, message string, data interface{}) e
 	if message == "" {
 		message = "Request has been successfully processed."
 	}
+	message = i18n.T(i18n.FromContext(ctx.Request().Context()), message)
 
 	length, err := numberc.LengthOf(data)
 	if err != nil {
@@ -73,6 +79,8 @@ func SuccessList(ctx echo.Context, code int, message string, data interface{}) e
 		})
 	}
 
+	data = selectFields(ctx, data)
+
 	return ctx.JSON(http.StatusOK, models.Response{
 		Code:    code,
 		Status:  "OK",
@@ -86,7 +94,26 @@ func SuccessList(ctx echo.Context, code int, message string, data interface{}) e
 	})
 }
 
-func SuccessPagination(ctx echo.Context, code int, message string, pagination models.PaginationOutput, data interface{}) error {
+// Option adjusts the envelope of a response, e.g. WithLinks
+type Option func(*models.Response)
+
+// WithLinks replaces the links of the response
+func WithLinks(links models.Links) Option {
+	return func(r *models.Response) {
+		r.Links = &links
+	}
+}
+
+// WithTotalRows sets metadata.totalRows, e.g. the rows of the page
+func WithTotalRows(rows int) Option {
+	return func(r *models.Response) {
+		r.Metadata.TotalRows = rows
+	}
+}
+
+// SuccessPagination answers a page of a list with its pagination and links: self is the
+// request, next and prev those of pagination. Options adjust the envelope.
+func SuccessPagination(ctx echo.Context, code int, message string, pagination models.PaginationOutput, data interface{}, options ...Option) error {
 	requestID, _ := ctx.Get("X-Request-ID").(string)
 	if requestID == "" {
 		requestID = uuid.New().String()
@@ -100,16 +127,28 @@ func SuccessPagination(ctx echo.Context, code int, message string, pagination mo
 	if message == "" {
 		message = "Request has been successfully processed."
 	}
+	message = i18n.T(i18n.FromContext(ctx.Request().Context()), message)
 
-	return ctx.JSON(http.StatusOK, models.Response{
+	data = selectFields(ctx, data)
+
+	body := models.Response{
 		Code:       code,
 		Status:     "OK",
 		Message:    message,
 		Data:       data,
 		Pagination: &pagination,
+		Links: &models.Links{
+			Self: ctx.Request().URL.RequestURI(),
+			Next: pagination.Next,
+			Prev: pagination.Prev,
+		},
 		Metadata: models.Metadata{
 			RequestId: requestID,
 			Timestamp: timestamp,
 		},
-	})
+	}
+	for _, option := range options {
+		option(&body)
+	}
+	return ctx.JSON(http.StatusOK, body)
 }
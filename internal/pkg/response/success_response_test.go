@@ -0,0 +1,44 @@
+package response_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuccessPagination(t *testing.T) {
+	serve := func(options ...response.Option) models.Response {
+		e := echo.New()
+		e.GET("/items", func(ctx echo.Context) error {
+			pagination := models.PaginationOutput{Prev: "/items?page=1", Next: "/items?page=3", Total: 50, Limit: 10}
+			return response.SuccessPagination(ctx, http.StatusOK, "", pagination, []string{"a"}, options...)
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?page=2", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body models.Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	t.Run("Links Of The Pagination", func(t *testing.T) {
+		body := serve()
+		assert.Equal(t, &models.Links{Self: "/items?page=2", Next: "/items?page=3", Prev: "/items?page=1"}, body.Links)
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		body := serve(response.WithLinks(models.Links{Self: "/items/page/2"}), response.WithTotalRows(1))
+		assert.Equal(t, &models.Links{Self: "/items/page/2"}, body.Links)
+		assert.Equal(t, 1, body.Metadata.TotalRows)
+	})
+}
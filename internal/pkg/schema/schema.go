@@ -0,0 +1,158 @@
+// Package schema validates JSON documents against schemas derived from the models in the
+// generated OpenAPI spec (docs/swagger.json) or written by hand.
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-echo-boilerplate/docs"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/hashicorp/go-multierror"
+)
+
+var (
+	specOnce sync.Once
+	spec     *openapi3.T
+	specErr  error
+)
+
+// OpenAPI returns the spec registered by the docs package, converted from Swagger 2.0 to
+// OpenAPI 3 with its references resolved and the servers dropped so any host matches.
+// It is loaded once; callers must not modify it.
+func OpenAPI() (*openapi3.T, error) {
+	specOnce.Do(func() {
+		spec, specErr = loadOpenAPI()
+	})
+	return spec, specErr
+}
+
+func loadOpenAPI() (*openapi3.T, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &doc2); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("convert spec: %w", err)
+	}
+	doc.Servers = openapi3.Servers{{URL: "/"}}
+	if err := openapi3.NewLoader().ResolveRefsIn(doc, nil); err != nil {
+		return nil, fmt.Errorf("resolve spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	return doc, nil
+}
+
+// Schema validates JSON documents against one schema
+type Schema struct {
+	schema *openapi3.Schema
+}
+
+// FromModel returns the schema generated for a model, e.g. "models.CreateUserRequest". swag
+// only includes models referenced by a handler annotation, and derives required fields and
+// bounds from the validate tags it understands (required, min, max, oneof).
+func FromModel(name string) (*Schema, error) {
+	doc, err := OpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	ref, ok := doc.Components.Schemas[name]
+	if !ok || ref.Value == nil {
+		return nil, fmt.Errorf("schema %q is not in the spec, reference it from a handler annotation and run make docs", name)
+	}
+	return &Schema{schema: ref.Value}, nil
+}
+
+// Parse reads a hand-written schema. Schemas are OpenAPI 3 schema objects, the JSON Schema
+// subset OpenAPI uses (type, properties, required, enum, pattern, minLength, items, ...).
+func Parse(data []byte) (*Schema, error) {
+	var schema openapi3.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	if err := schema.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &Schema{schema: &schema}, nil
+}
+
+// Must returns s and panics on err, for schemas loaded while registering routes
+func Must(s *Schema, err error) *Schema {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate checks a JSON document, an empty one counting as {}. Violations are returned as a
+// *multierror.Error of models.ErrorValidationResponse in locale, like validator.Input; a
+// document that isn't JSON is an errorc.ErrorInvalidInput.
+func (s *Schema) Validate(document []byte, locale i18n.Locale) error {
+	var value any = map[string]any{}
+	if len(strings.TrimSpace(string(document))) > 0 {
+		if err := json.Unmarshal(document, &value); err != nil {
+			return errorc.Error(errorc.ErrorInvalidInput, "request body is not valid JSON")
+		}
+	}
+
+	err := s.schema.VisitJSON(value, openapi3.MultiErrors())
+	if err == nil {
+		return nil
+	}
+
+	var errs *multierror.Error
+	for _, schemaErr := range schemaErrors(err) {
+		errs = multierror.Append(errs, validationError(schemaErr, locale))
+	}
+	return errs.ErrorOrNil()
+}
+
+// schemaErrors flattens the errors of VisitJSON
+func schemaErrors(err error) []*openapi3.SchemaError {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var all []*openapi3.SchemaError
+		for _, err := range multi {
+			all = append(all, schemaErrors(err)...)
+		}
+		return all
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []*openapi3.SchemaError{schemaErr}
+	}
+	return []*openapi3.SchemaError{{Reason: err.Error()}}
+}
+
+// validationError maps a schema violation to the validator.Input format, the field being
+// the dotted path of the value (e.g. "phoneNumber.number")
+func validationError(err *openapi3.SchemaError, locale i18n.Locale) models.ErrorValidationResponse {
+	field := strings.Join(err.JSONPointer(), ".")
+	if err.SchemaField == "required" {
+		return models.ErrorValidationResponse{
+			Code:    validator.ErrorCodeMissingField,
+			Field:   field,
+			Message: i18n.T(locale, "validation.required", field),
+		}
+	}
+	return models.ErrorValidationResponse{
+		Code:    validator.ErrorCodeInvalidField,
+		Field:   field,
+		Message: i18n.T(locale, "validation.default", field, err.Reason),
+	}
+}
@@ -0,0 +1,80 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/schema"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validationErrors returns the violations of a Validate error by field
+func validationErrors(t *testing.T, err error) map[string]models.ErrorValidationResponse {
+	t.Helper()
+	var errs *multierror.Error
+	require.ErrorAs(t, err, &errs)
+
+	byField := map[string]models.ErrorValidationResponse{}
+	for _, err := range errs.Errors {
+		validationErr, ok := err.(models.ErrorValidationResponse)
+		require.True(t, ok, "unexpected error %T", err)
+		byField[validationErr.Field] = validationErr
+	}
+	return byField
+}
+
+func TestFromModel(t *testing.T) {
+	s, err := schema.FromModel("models.CreateUserRequest")
+	require.NoError(t, err)
+
+	assert.NoError(t, s.Validate([]byte(`{"name":"John","email":"john@example.com","password":"secret"}`), i18n.English))
+
+	errs := validationErrors(t, s.Validate([]byte(`{"name":1,"phoneNumber":{"number":6281234567890}}`), i18n.English))
+	assert.Equal(t, validator.ErrorCodeMissingField, errs["password"].Code)
+	assert.Equal(t, "password is required", errs["password"].Message)
+	assert.Equal(t, validator.ErrorCodeInvalidField, errs["name"].Code)
+	assert.Equal(t, validator.ErrorCodeInvalidField, errs["phoneNumber.number"].Code)
+	assert.Len(t, errs, 3)
+
+	t.Run("Empty Body", func(t *testing.T) {
+		errs := validationErrors(t, s.Validate(nil, i18n.English))
+		assert.Contains(t, errs, "name")
+		assert.Contains(t, errs, "password")
+	})
+
+	t.Run("Not JSON", func(t *testing.T) {
+		err := s.Validate([]byte(`{"name":`), i18n.English)
+		assert.Equal(t, errorc.ErrorInvalidInput.Response.Status, errorc.GetResponse(err).Status)
+	})
+
+	t.Run("Unknown Model", func(t *testing.T) {
+		_, err := schema.FromModel("models.Unknown")
+		assert.ErrorContains(t, err, "models.Unknown")
+	})
+}
+
+func TestParse(t *testing.T) {
+	s, err := schema.Parse([]byte(`{
+		"type": "object",
+		"required": ["level"],
+		"properties": {"level": {"type": "string", "enum": ["debug", "info"]}}
+	}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, s.Validate([]byte(`{"level":"debug"}`), i18n.English))
+
+	errs := validationErrors(t, s.Validate([]byte(`{"level":"verbose"}`), i18n.English))
+	assert.Equal(t, validator.ErrorCodeInvalidField, errs["level"].Code)
+
+	errs = validationErrors(t, s.Validate([]byte(`{}`), i18n.Indonesian))
+	assert.Equal(t, i18n.T(i18n.Indonesian, "validation.required", "level"), errs["level"].Message)
+
+	_, err = schema.Parse([]byte(`{"type": "whatever"}`))
+	assert.Error(t, err)
+}
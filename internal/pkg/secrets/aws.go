@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSProvider reads secrets from AWS Secrets Manager using the default credential chain
+// (environment, shared config, IAM role). The path is the secret name or ARN.
+type AWSProvider struct {
+	once   sync.Once
+	client *secretsmanager.Client
+	err    error
+}
+
+// NewAWSProvider creates an AWS provider. Credentials are loaded on first use.
+func NewAWSProvider() *AWSProvider {
+	return &AWSProvider{}
+}
+
+// Get returns the current SecretString of the secret at path.
+func (p *AWSProvider) Get(ctx context.Context, path string) (string, error) {
+	p.once.Do(func() {
+		cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			p.err = err
+			return
+		}
+		p.client = secretsmanager.NewFromConfig(cfg)
+	})
+	if p.err != nil {
+		return "", fmt.Errorf("aws: failed to load credentials: %w", p.err)
+	}
+
+	output, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(path),
+	})
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+
+	if output.SecretString != nil {
+		return *output.SecretString, nil
+	}
+	return string(output.SecretBinary), nil
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcpSecretManagerURL is the Secret Manager REST endpoint
+const gcpSecretManagerURL = "https://secretmanager.googleapis.com/v1/"
+
+// GCPProvider reads secrets from GCP Secret Manager using Application Default Credentials.
+// The path is the secret's resource name, e.g. "projects/my-project/secrets/db-password",
+// optionally followed by "/versions/<version>" (default: latest).
+type GCPProvider struct {
+	once   sync.Once
+	client *http.Client
+	err    error
+}
+
+// NewGCPProvider creates a GCP provider. Credentials are loaded on first use.
+func NewGCPProvider() *GCPProvider {
+	return &GCPProvider{}
+}
+
+// Get accesses the secret version at path and returns its payload.
+func (p *GCPProvider) Get(ctx context.Context, path string) (string, error) {
+	p.once.Do(func() {
+		p.client, p.err = google.DefaultClient(context.Background(), "https://www.googleapis.com/auth/cloud-platform")
+	})
+	if p.err != nil {
+		return "", fmt.Errorf("gcp: failed to load credentials: %w", p.err)
+	}
+
+	name := strings.Trim(path, "/")
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpSecretManagerURL+name+":access", nil)
+	if err != nil {
+		return "", fmt.Errorf("gcp: failed to build request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gcp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gcp: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("gcp: failed to decode response: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("gcp: failed to decode payload: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,103 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver(t *testing.T) {
+	calls := 0
+	resolver := secrets.NewResolver(map[string]secrets.Provider{
+		"fake": secrets.ProviderFunc(func(ctx context.Context, path string) (string, error) {
+			calls++
+			switch path {
+			case "plain":
+				return "s3cret", nil
+			case "app":
+				return `{"db_password":"pw","port":5432}`, nil
+			}
+			return "", errors.New("not found")
+		}),
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		reference string
+		want      string
+		wantErr   bool
+	}{
+		{"plain value", "secret://fake/plain", "s3cret", false},
+		{"json key", "secret://fake/app#db_password", "pw", false},
+		{"json non-string key", "secret://fake/app#port", "5432", false},
+		{"missing key", "secret://fake/app#missing", "", true},
+		{"key on non-json secret", "secret://fake/plain#key", "", true},
+		{"provider error", "secret://fake/unknown", "", true},
+		{"unknown provider", "secret://other/app", "", true},
+		{"missing path", "secret://fake", "", true},
+		{"not a reference", "plaintext", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(ctx, tt.reference)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// plain, app, and unknown were each fetched once
+	assert.Equal(t, 3, calls)
+}
+
+func TestIsReference(t *testing.T) {
+	assert.True(t, secrets.IsReference("secret://aws/prod/app"))
+	assert.False(t, secrets.IsReference("secret"))
+	assert.False(t, secrets.IsReference(""))
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			_, _ = w.Write([]byte(`{"data":{"data":{"access_secret":"abc"},"metadata":{"version":3}}}`))
+		case "/v1/kv/app":
+			_, _ = w.Write([]byte(`{"data":{"access_secret":"v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := secrets.NewResolver(map[string]secrets.Provider{
+		"vault": &secrets.VaultProvider{Address: server.URL, Token: "token"},
+	})
+	ctx := context.Background()
+
+	value, err := resolver.Resolve(ctx, "secret://vault/secret/data/app#access_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", value)
+
+	value, err = resolver.Resolve(ctx, "secret://vault/kv/app#access_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	_, err = resolver.Resolve(ctx, "secret://vault/missing#key")
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// VaultProvider reads secrets from HashiCorp Vault over its HTTP API.
+// The path is the full API path without /v1, e.g. "secret/data/app" for KV v2.
+// The secret's data is returned as a JSON object, so references usually select a key.
+type VaultProvider struct {
+	// Address and Token default to VAULT_ADDR and VAULT_TOKEN when empty
+	Address string
+	Token   string
+
+	client *httpclient.Client // httpclient.DefaultConfig when nil
+}
+
+// NewVaultProvider creates a Vault provider configured from VAULT_ADDR and VAULT_TOKEN.
+func NewVaultProvider() *VaultProvider {
+	return &VaultProvider{client: httpclient.New(httpclient.DefaultConfig())}
+}
+
+// Get reads the secret at path and returns its data as JSON.
+func (p *VaultProvider) Get(ctx context.Context, path string) (string, error) {
+	address := p.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	token := p.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" || token == "" {
+		return "", fmt.Errorf("vault: VAULT_ADDR and VAULT_TOKEN are required")
+	}
+
+	client := p.client
+	if client == nil {
+		client = httpclient.New(nil)
+	}
+
+	url := strings.TrimRight(address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	resp, err := client.Get(ctx, url, map[string]string{"X-Vault-Token": token})
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("vault: failed to decode response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data, next to data.metadata
+	data := body.Data
+	if nested, ok := data["data"]; ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return "", fmt.Errorf("vault: failed to decode secret data: %w", err)
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("vault: failed to encode secret data: %w", err)
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,72 @@
+// Package session revokes the tokens of an account before they expire. Tokens are
+// stateless, so a revocation is a per-account timestamp: tokens issued before it are
+// rejected by middleware.BearerAuthMiddleware.
+package session
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxAccounts is the number of accounts an in-memory store keeps revocations for
+	DefaultMaxAccounts = 100_000
+
+	// keyPrefix namespaces revocations in the shared store
+	keyPrefix = "session:revoked:"
+)
+
+// Revocations keeps the last revocation time of each account in a cache.Store.
+// Use a Redis store when more than one process serves or revokes tokens.
+type Revocations struct {
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewRevocations creates Revocations kept for ttl, which should be the lifetime of the
+// longest-lived token: after that, every token issued before the revocation has expired.
+func NewRevocations(store cache.Store, ttl time.Duration) *Revocations {
+	return &Revocations{store: store, ttl: ttl}
+}
+
+// Revoke rejects every token of the account issued before at.
+func (r *Revocations) Revoke(ctx context.Context, accountNumber string, at time.Time) error {
+	value := strconv.FormatInt(at.UnixNano(), 10)
+	return r.store.Set(ctx, keyPrefix+accountNumber, "at", []byte(value), r.ttl)
+}
+
+// IsRevoked reports whether a token of the account issued at issuedAt was revoked.
+// Token issue times have a millisecond resolution (see package jwtc) and round down, so only
+// a token issued in the same millisecond after the revocation is rejected too. A nil
+// *Revocations revokes nothing.
+func (r *Revocations) IsRevoked(ctx context.Context, accountNumber string, issuedAt time.Time) (bool, error) {
+	if r == nil {
+		return false, nil
+	}
+
+	value, ok, err := r.store.Get(ctx, keyPrefix+accountNumber, "at")
+	if err != nil || !ok {
+		return false, err
+	}
+
+	revokedAt, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAt.UnixNano() < revokedAt, nil
+}
+
+var current atomic.Pointer[Revocations]
+
+// SetDefault installs the revocations checked by middleware.BearerAuthMiddleware; nil disables them.
+func SetDefault(r *Revocations) {
+	current.Store(r)
+}
+
+// Default returns the installed revocations, nil when none are installed.
+func Default() *Revocations {
+	return current.Load()
+}
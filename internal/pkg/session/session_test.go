@@ -0,0 +1,52 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocations(t *testing.T) {
+	ctx := context.Background()
+	revocations := session.NewRevocations(cache.NewMemoryStore(10), time.Hour)
+	revokedAt := time.Date(2026, 1, 1, 10, 0, 0, 500_000_000, time.UTC)
+
+	revoked, err := revocations.IsRevoked(ctx, "acc-1", revokedAt.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.False(t, revoked, "nothing revoked yet")
+
+	require.NoError(t, revocations.Revoke(ctx, "acc-1", revokedAt))
+
+	tests := []struct {
+		name     string
+		account  string
+		issuedAt time.Time
+		want     bool
+	}{
+		{"Issued Before", "acc-1", revokedAt.Add(-time.Minute), true},
+		{"Issued Earlier In The Same Second", "acc-1", revokedAt.Add(-100 * time.Millisecond), true},
+		{"Issued Later In The Same Second", "acc-1", revokedAt.Add(100 * time.Millisecond), false},
+		{"Issued After", "acc-1", revokedAt.Add(time.Second).Truncate(time.Second), false},
+		{"Other Account", "acc-2", revokedAt.Add(-time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := revocations.IsRevoked(ctx, tt.account, tt.issuedAt)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, revoked)
+		})
+	}
+
+	t.Run("Nil Revokes Nothing", func(t *testing.T) {
+		var none *session.Revocations
+		revoked, err := none.IsRevoked(ctx, "acc-1", revokedAt.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
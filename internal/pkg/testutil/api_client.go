@@ -0,0 +1,186 @@
+// Package testutil holds helpers shared by the tests of several packages. It is imported by
+// _test.go files only.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// APIClient sends requests to a handler, usually an *echo.Echo with the routes under test,
+// and decodes the responses. Requests fail the test on setup errors, never the handler's.
+//
+//	client := testutil.NewAPIClient(t, e, jwtConfig)
+//	res := client.AuthAs(user).Get("/v1/users/me")
+//	res.AssertStatus(http.StatusOK)
+//	var me models.GetUserByAccountNumberResponse
+//	res.DecodeData(&me)
+type APIClient struct {
+	t         testing.TB
+	handler   http.Handler
+	jwtConfig *jwtc.Configuration
+	header    http.Header
+}
+
+// NewAPIClient creates an APIClient. jwtConfig signs the tokens of AuthAs and may be nil
+// when it is not used.
+func NewAPIClient(t testing.TB, handler http.Handler, jwtConfig *jwtc.Configuration) *APIClient {
+	return &APIClient{t: t, handler: handler, jwtConfig: jwtConfig, header: http.Header{}}
+}
+
+// WithHeader returns a copy of the client sending the header with every request.
+func (c *APIClient) WithHeader(key, value string) *APIClient {
+	clone := *c
+	clone.header = c.header.Clone()
+	clone.header.Set(key, value)
+	return &clone
+}
+
+// AuthAs returns a copy of the client sending an access token of the user.
+func (c *APIClient) AuthAs(user *models.User) *APIClient {
+	c.t.Helper()
+	require.NotNil(c.t, c.jwtConfig, "testutil: AuthAs needs the jwt configuration of NewAPIClient")
+
+	token, err := generator.AccessToken(user, c.jwtConfig)
+	require.NoError(c.t, err, "testutil: signing the access token")
+	return c.WithHeader(echo.HeaderAuthorization, "Bearer "+token.Token)
+}
+
+// Get sends a GET request.
+func (c *APIClient) Get(path string) *APIResponse {
+	c.t.Helper()
+	return c.Do(httptest.NewRequest(http.MethodGet, path, nil))
+}
+
+// Delete sends a DELETE request.
+func (c *APIClient) Delete(path string) *APIResponse {
+	c.t.Helper()
+	return c.Do(httptest.NewRequest(http.MethodDelete, path, nil))
+}
+
+// PostJSON sends a POST request with body encoded as JSON. A string or []byte body is sent as is.
+func (c *APIClient) PostJSON(path string, body any) *APIResponse {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPost, path, body)
+}
+
+// PutJSON sends a PUT request with body encoded as JSON.
+func (c *APIClient) PutJSON(path string, body any) *APIResponse {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPut, path, body)
+}
+
+// PatchJSON sends a PATCH request with body encoded as JSON.
+func (c *APIClient) PatchJSON(path string, body any) *APIResponse {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPatch, path, body)
+}
+
+// Do sends the request with the headers of the client and decodes the response.
+func (c *APIClient) Do(req *http.Request) *APIResponse {
+	c.t.Helper()
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+
+	res := &APIResponse{ResponseRecorder: rec, t: c.t}
+	if rec.Body.Len() > 0 && isJSON(rec.Header().Get(echo.HeaderContentType)) {
+		require.NoError(c.t, json.Unmarshal(rec.Body.Bytes(), &res.Response), "testutil: decoding the response: %s", rec.Body.String())
+	}
+	return res
+}
+
+func (c *APIClient) sendJSON(method, path string, body any) *APIResponse {
+	c.t.Helper()
+
+	var reader io.Reader
+	switch body := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(body)
+	case []byte:
+		reader = bytes.NewReader(body)
+	default:
+		data, err := json.Marshal(body)
+		require.NoError(c.t, err, "testutil: encoding the request body")
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	return c.Do(req)
+}
+
+// APIResponse is a recorded response. Response is the decoded models.Response envelope,
+// zero when the body is not JSON.
+type APIResponse struct {
+	*httptest.ResponseRecorder
+	Response models.Response
+
+	t testing.TB
+}
+
+// DecodeData decodes the data of the response into v, failing the test when it cannot.
+func (r *APIResponse) DecodeData(v any) {
+	r.t.Helper()
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(r.t, json.Unmarshal(r.Body.Bytes(), &envelope), "testutil: decoding the response: %s", r.Body.String())
+	require.NotEmpty(r.t, envelope.Data, "testutil: the response has no data: %s", r.Body.String())
+	require.NoError(r.t, json.Unmarshal(envelope.Data, v), "testutil: decoding the response data")
+}
+
+// AssertStatus asserts the HTTP status code, showing the body when it differs.
+func (r *APIResponse) AssertStatus(code int) bool {
+	r.t.Helper()
+	return assert.Equal(r.t, code, r.Code, "response: %s", r.Body.String())
+}
+
+// AssertError asserts the response is the error of expected, one of the predefined
+// errorc errors: same HTTP status code and status.
+func (r *APIResponse) AssertError(expected *errorc.HTTPError) bool {
+	r.t.Helper()
+	return r.AssertStatus(expected.Response.Code) &&
+		assert.Equal(r.t, expected.Response.Status, r.Response.Status, "response: %s", r.Body.String())
+}
+
+// AssertValidationError asserts the response is a validation error reporting field.
+func (r *APIResponse) AssertValidationError(field string) bool {
+	r.t.Helper()
+	if !r.AssertError(errorc.ErrorValidation) {
+		return false
+	}
+
+	var body struct {
+		Errors []models.ErrorValidationResponse `json:"errors"`
+	}
+	require.NoError(r.t, json.Unmarshal(r.Body.Bytes(), &body), "testutil: decoding the validation errors")
+	for _, fieldErr := range body.Errors {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return assert.Fail(r.t, "no validation error for field "+field, "response: %s", r.Body.String())
+}
+
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(contentType, echo.MIMEApplicationJSON)
+}
@@ -0,0 +1,44 @@
+package testutil_test
+
+import (
+	"net/http"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/testutil"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIClient(t *testing.T) {
+	e := echo.New()
+	e.POST("/echo", func(c echo.Context) error {
+		var body map[string]any
+		if err := c.Bind(&body); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]any{"data": body, "message": c.Request().Header.Get("X-Test")})
+	})
+	e.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, "plain")
+	})
+
+	client := testutil.NewAPIClient(t, e, nil)
+	withHeader := client.WithHeader("X-Test", "set")
+
+	res := withHeader.PostJSON("/echo", map[string]string{"name": "John"})
+	res.AssertStatus(http.StatusOK)
+	assert.Equal(t, "set", res.Response.Message)
+
+	var data struct{ Name string }
+	res.DecodeData(&data)
+	assert.Equal(t, "John", data.Name)
+
+	res = client.PostJSON("/echo", `{"name":"Jane"}`)
+	assert.Empty(t, res.Response.Message, "WithHeader does not change the original client")
+
+	res = client.Get("/text")
+	res.AssertStatus(http.StatusOK)
+	assert.Equal(t, "plain", res.Body.String())
+	assert.Zero(t, res.Response.Code, "non-JSON bodies are not decoded")
+}
@@ -0,0 +1,50 @@
+// Package useragent parses User-Agent headers into the device type, operating system, and
+// browser of the client with github.com/mileusna/useragent. Fields it doesn't recognize are
+// left empty.
+package useragent
+
+import "github.com/mileusna/useragent"
+
+// Device types
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+)
+
+// Agent is a parsed User-Agent. Fields that couldn't be recognized are empty.
+type Agent struct {
+	Device         string // desktop, mobile, tablet, or bot
+	OS             string // e.g. "Windows", "macOS", "iOS", "Android", "Linux"
+	OSVersion      string // e.g. "10.0", "17.4"
+	Browser        string // e.g. "Chrome", "Safari", or a tool such as "curl"
+	BrowserVersion string // e.g. "124.0.6367.91"
+}
+
+// Parse parses a User-Agent header.
+func Parse(userAgent string) Agent {
+	parsed := useragent.Parse(userAgent)
+	return Agent{
+		Device:         device(parsed),
+		OS:             parsed.OS,
+		OSVersion:      parsed.OSVersion,
+		Browser:        parsed.Name,
+		BrowserVersion: parsed.Version,
+	}
+}
+
+func device(parsed useragent.UserAgent) string {
+	switch {
+	case parsed.Bot:
+		return DeviceBot
+	case parsed.Tablet:
+		return DeviceTablet
+	case parsed.Mobile:
+		return DeviceMobile
+	case parsed.Desktop:
+		return DeviceDesktop
+	default:
+		return ""
+	}
+}
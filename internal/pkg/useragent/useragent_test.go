@@ -0,0 +1,77 @@
+package useragent_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/useragent"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      useragent.Agent
+	}{
+		{
+			name:      "Chrome On Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.6367.91 Safari/537.36",
+			want:      useragent.Agent{Device: "desktop", OS: "Windows", OSVersion: "10.0", Browser: "Chrome", BrowserVersion: "124.0.6367.91"},
+		},
+		{
+			name:      "Edge On Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.67",
+			want:      useragent.Agent{Device: "desktop", OS: "Windows", OSVersion: "10.0", Browser: "Edge", BrowserVersion: "124.0.2478.67"},
+		},
+		{
+			name:      "Safari On macOS",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Safari/605.1.15",
+			want:      useragent.Agent{Device: "desktop", OS: "macOS", OSVersion: "10.15.7", Browser: "Safari", BrowserVersion: "17.4.1"},
+		},
+		{
+			name:      "Firefox On Linux",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			want:      useragent.Agent{Device: "desktop", OS: "Linux", OSVersion: "x86_64", Browser: "Firefox", BrowserVersion: "125.0"},
+		},
+		{
+			name:      "Safari On iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want:      useragent.Agent{Device: "mobile", OS: "iOS", OSVersion: "17.4", Browser: "Safari", BrowserVersion: "17.4"},
+		},
+		{
+			name:      "Chrome On iPad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.6367.88 Mobile/15E148 Safari/604.1",
+			want:      useragent.Agent{Device: "tablet", OS: "iOS", OSVersion: "16.6", Browser: "Chrome", BrowserVersion: "124.0.6367.88"},
+		},
+		{
+			name:      "Samsung Internet On Android Phone",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Mobile Safari/537.36",
+			want:      useragent.Agent{Device: "mobile", OS: "Android", OSVersion: "14", Browser: "Samsung Browser", BrowserVersion: "24.0"},
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want:      useragent.Agent{Device: "bot", Browser: "Googlebot", BrowserVersion: "2.1"},
+		},
+		{
+			name:      "curl",
+			userAgent: "curl/8.4.0",
+			want:      useragent.Agent{Browser: "curl", BrowserVersion: "8.4.0"},
+		},
+		{
+			name:      "Unknown Product",
+			userAgent: "my-service",
+			want:      useragent.Agent{Browser: "my-service"},
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, useragent.Parse(tt.userAgent))
+		})
+	}
+}
@@ -1,20 +1,46 @@
 package validator
 
-// AccountNumberLength defines the total length of the account number including check digit
+import (
+	"go-echo-boilerplate/internal/models"
+	"strings"
+)
+
+// AccountNumberLength defines the total length of the default account number including check digit
 const AccountNumberLength = 16
 
-// ValidateAccountNumber validates an account number using the Luhn algorithm
+// ValidateAccountNumber validates an account number against a profile's length, prefix pool,
+// and check digit algorithm. Without a profile it validates the default format
+// (16 digits with a Luhn check digit).
 //
 // Parameters:
 //   - accountNumber: The account number to validate
+//   - profile: Optional profile, e.g. from generator.GetAccountNumberProfile
 //
 // Returns:
 //   - bool: true if valid, false otherwise
-func AccountNumber(accountNumber string) bool {
-	if len(accountNumber) != AccountNumberLength {
+func AccountNumber(accountNumber string, profile ...models.AccountNumberProfile) bool {
+	p := models.AccountNumberProfile{Length: AccountNumberLength, CheckDigit: models.CheckDigitLuhn}
+	if len(profile) > 0 {
+		p = profile[0]
+	}
+
+	if len(accountNumber) != p.Length {
 		return false
 	}
 
+	if len(p.Prefixes) > 0 {
+		var hasPrefix bool
+		for _, prefix := range p.Prefixes {
+			if strings.HasPrefix(accountNumber, prefix) {
+				hasPrefix = true
+				break
+			}
+		}
+		if !hasPrefix {
+			return false
+		}
+	}
+
 	// Convert to digits
 	digits := make([]int, len(accountNumber))
 	for i, char := range accountNumber {
@@ -24,6 +50,10 @@ func AccountNumber(accountNumber string) bool {
 		digits[i] = int(char - '0')
 	}
 
+	if p.CheckDigit == models.CheckDigitMod97 {
+		return Mod97(digits)
+	}
+
 	// Validate using Luhn algorithm
 	return Luhn(digits)
 }
@@ -51,3 +81,18 @@ func Luhn(digits []int) bool {
 	// Valid if sum is divisible by 10
 	return sum%10 == 0
 }
+
+// Mod97 validates a complete number (including the two trailing check digits)
+// using ISO 7064 MOD 97-10: the whole number must be 1 mod 97
+func Mod97(digits []int) bool {
+	if len(digits) < 3 {
+		return false
+	}
+
+	remainder := 0
+	for _, digit := range digits {
+		remainder = (remainder*10 + digit) % 97
+	}
+
+	return remainder == 1
+}
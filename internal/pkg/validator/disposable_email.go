@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DomainBlocklist rejects email addresses of listed domains, e.g. disposable email
+// providers. A listed domain also blocks its subdomains. The zero value and nil block nothing.
+type DomainBlocklist struct {
+	domains map[string]bool
+}
+
+// NewDomainBlocklist creates a blocklist of domains; case and surrounding spaces are ignored.
+func NewDomainBlocklist(domains ...string) *DomainBlocklist {
+	blocklist := &DomainBlocklist{domains: make(map[string]bool, len(domains))}
+	for _, domain := range domains {
+		if domain = normalizeDomain(domain); domain != "" {
+			blocklist.domains[domain] = true
+		}
+	}
+	return blocklist
+}
+
+// Load adds the domains of r, one per line, to the blocklist. Empty lines and
+// lines starting with "#" are skipped, so published disposable domain lists can be used as is.
+func (b *DomainBlocklist) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if domain := normalizeDomain(line); domain != "" {
+			b.domains[domain] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read domain blocklist: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of blocked domains.
+func (b *DomainBlocklist) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.domains)
+}
+
+// Blocked reports whether the domain of email, or one of its parent domains, is listed.
+func (b *DomainBlocklist) Blocked(email string) bool {
+	if b.Len() == 0 {
+		return false
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := normalizeDomain(email[at+1:])
+	for domain != "" {
+		if b.domains[domain] {
+			return true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+	return false
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainBlocklist(t *testing.T) {
+	blocklist := NewDomainBlocklist(" Mailinator.com ", "")
+	require.NoError(t, blocklist.Load(strings.NewReader("# disposable\nyopmail.com\n\n  trashmail.de.\n")))
+	assert.Equal(t, 3, blocklist.Len())
+
+	for _, email := range []string{"john@mailinator.com", "john@MAILINATOR.COM", "john@eu.mailinator.com", "john@yopmail.com", "john@trashmail.de"} {
+		assert.True(t, blocklist.Blocked(email), "Should block: %s", email)
+	}
+	for _, email := range []string{"john@example.com", "john@notmailinator.com", "mailinator.com", ""} {
+		assert.False(t, blocklist.Blocked(email), "Should allow: %s", email)
+	}
+
+	var empty *DomainBlocklist
+	assert.False(t, empty.Blocked("john@mailinator.com"))
+}
@@ -3,25 +3,40 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"go-echo-boilerplate/internal/pkg/hashc"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Verify is a package-level convenience function that verifies a password against a hash.
-// This allows you to verify passwords without creating a hasher instance first.
+// Hash verifies a password against a stored hash created by generator.Hash.
+// Peppered hashes are verified with the pepper version recorded in the hash,
+// so hashes from retired versions keep working while their pepper is configured.
 //
 // Example:
 //
-//	match, err := generator.Verify("MyPassword123", storedHash)
+//	match, err := validator.Hash("MyPassword123", storedHash, hashConfig)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	if match {
 //	    fmt.Println("Password is correct!")
 //	}
-func Hash(password, hashedPassword string) (bool, error) {
+func Hash(password, hashedPassword string, config *hashc.Configuration) (bool, error) {
+	version, hash := hashc.Decode(hashedPassword)
+	if version != 0 {
+		if config == nil {
+			return false, fmt.Errorf("failed to verify password: pepper version %d is not configured", version)
+		}
+
+		peppered, err := config.Pepper(password, version)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify password: %w", err)
+		}
+		password = peppered
+	}
+
 	// Compare password with hash
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
 			// Password doesn't match - this is not an error, just return false
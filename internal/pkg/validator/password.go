@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"strconv"
 	"strings"
 
 	v10 "github.com/go-playground/validator/v10"
@@ -19,6 +20,7 @@ func registerPasswordValidations() {
 	registerPasswordMinLength()
 	registerPasswordMaxLength()
 	registerPasswordStrength()
+	registerPasswordScore()
 }
 
 // registerPasswordMinLength validates minimum password length
@@ -95,3 +97,31 @@ func registerPasswordStrength() {
 		panic(err)
 	}
 }
+
+// registerPasswordScore validates the estimated password strength (see ScorePassword)
+// Usage: `validate:"passwordScore=3"` requires a score of at least 3 out of 4 (default 3)
+func registerPasswordScore() {
+	if err := valid.RegisterValidation("passwordScore", func(fl v10.FieldLevel) bool {
+		str, ok := getStringValue(fl)
+		if !ok {
+			return false
+		}
+
+		if str == "" {
+			return true // Empty strings handled by 'required' tag
+		}
+
+		minScore := PasswordScoreSafelyUnguessable
+		if param := fl.Param(); param != "" {
+			parsed, err := strconv.Atoi(param)
+			if err != nil {
+				return false
+			}
+			minScore = parsed
+		}
+
+		return ScorePassword(str).Score >= minScore
+	}); err != nil {
+		panic(err)
+	}
+}
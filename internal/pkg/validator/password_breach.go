@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultBreachAPIURL is the HaveIBeenPwned Pwned Passwords range endpoint
+const DefaultBreachAPIURL = "https://api.pwnedpasswords.com/range/"
+
+// PasswordBreachChecker reports how many times a password appears in known data breaches.
+type PasswordBreachChecker interface {
+	Count(ctx context.Context, password string) (int, error)
+}
+
+// BreachChecker queries a Pwned Passwords compatible API using k-anonymity:
+// only the first 5 hex characters of the password's SHA-1 are sent, and the
+// suffix is matched locally against the returned candidates.
+type BreachChecker struct {
+	client *httpclient.Client
+	apiURL string
+}
+
+// NewBreachChecker creates a checker using the given client and range API URL (DefaultBreachAPIURL when empty).
+func NewBreachChecker(client *httpclient.Client, apiURL string) *BreachChecker {
+	if apiURL == "" {
+		apiURL = DefaultBreachAPIURL
+	}
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+
+	return &BreachChecker{client: client, apiURL: apiURL}
+}
+
+// Count returns the number of breaches the password was seen in (0 if none).
+func (b *BreachChecker) Count(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	// Add-Padding hides the real number of candidates from network observers
+	resp, err := b.client.Get(ctx, b.apiURL+prefix, map[string]string{"Add-Padding": "true"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query breach api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("breach api returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+
+		// Padding entries have a count of 0
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return 0, fmt.Errorf("invalid breach api response: %w", err)
+		}
+		return n, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read breach api response: %w", err)
+	}
+
+	return 0, nil
+}
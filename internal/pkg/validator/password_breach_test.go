@@ -0,0 +1,48 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/httpclient"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreachChecker_Count(t *testing.T) {
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		fmt.Fprint(w, "003D68EB55068C33ACE09247EE4C639306B:0\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\r\n")
+	}))
+	defer server.Close()
+
+	checker := validator.NewBreachChecker(httpclient.New(nil), server.URL)
+
+	count, err := checker.Count(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.Equal(t, 3861493, count)
+	assert.Equal(t, "/5BAA6", requestedPath) // only the 5 character prefix leaves the process
+
+	count, err = checker.Count(context.Background(), "gT7#pQ2!vL9@")
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestBreachChecker_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	checker := validator.NewBreachChecker(httpclient.New(nil), server.URL)
+
+	_, err := checker.Count(context.Background(), "password")
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package validator
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Password score thresholds, loosely following zxcvbn's 0-4 scale
+const (
+	PasswordScoreTooGuessable = iota
+	PasswordScoreVeryGuessable
+	PasswordScoreSomewhatGuessable
+	PasswordScoreSafelyUnguessable
+	PasswordScoreVeryUnguessable
+)
+
+// Password suggestion keys, translated through the i18n catalogs
+const (
+	PasswordSuggestionAvoidCommon   = "password.suggestion.avoidCommon"
+	PasswordSuggestionAvoidPersonal = "password.suggestion.avoidPersonal"
+	PasswordSuggestionAvoidSequence = "password.suggestion.avoidSequence"
+	PasswordSuggestionAvoidRepeat   = "password.suggestion.avoidRepeat"
+	PasswordSuggestionLonger        = "password.suggestion.longer"
+	PasswordSuggestionAddVariety    = "password.suggestion.addVariety"
+)
+
+// PasswordStrength is the result of ScorePassword.
+type PasswordStrength struct {
+	Score       int      // 0 (too guessable) to 4 (very unguessable)
+	Entropy     float64  // estimated bits after pattern penalties
+	Suggestions []string // i18n keys, most important first
+}
+
+// commonPasswords is a short list of the most frequently breached passwords.
+// Matching is done after lowercasing and undoing common leet substitutions.
+var commonPasswords = map[string]struct{}{}
+
+func init() {
+	for _, password := range []string{
+		"password", "passw0rd", "123456", "12345678", "123456789", "1234567890", "qwerty", "qwertyuiop",
+		"abc123", "111111", "123123", "letmein", "welcome", "monkey", "dragon", "football", "baseball",
+		"iloveyou", "admin", "administrator", "login", "master", "sunshine", "princess", "shadow",
+		"superman", "trustno1", "starwars", "whatever", "freedom", "hello", "charlie", "michael",
+		"jennifer", "jordan", "hunter", "ranger", "buster", "soccer", "hockey", "batman", "computer",
+		"secret", "summer", "winter", "spring", "autumn", "changeme", "default", "root", "toor",
+		"access", "flower", "cheese", "ginger", "pepper", "matrix", "internet", "samsung", "google",
+		"indonesia", "jakarta", "bismillah", "sayang", "rahasia", "katasandi",
+	} {
+		commonPasswords[password] = struct{}{}
+	}
+}
+
+// leetReplacer undoes common character substitutions before dictionary lookup
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+// keyboardRows are checked for runs of adjacent keys such as "asdf" or "7890"
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// ScorePassword estimates how guessable a password is.
+// userInputs (name, email, phone number, ...) are penalized when they appear in the password.
+//
+// Example:
+//
+//	strength := validator.ScorePassword("Tr0ub4dor&3", request.Name, request.Email)
+//	if strength.Score < validator.PasswordScoreSafelyUnguessable {
+//	    // reject, showing strength.Suggestions
+//	}
+func ScorePassword(password string, userInputs ...string) PasswordStrength {
+	if password == "" {
+		return PasswordStrength{Score: PasswordScoreTooGuessable, Suggestions: []string{PasswordSuggestionLonger}}
+	}
+
+	lower := strings.ToLower(password)
+	var suggestions []string
+
+	// Dictionary: common passwords, also with leet and trailing digits/symbols removed ("P@ssw0rd123!")
+	base := strings.TrimRightFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+	if isCommonPassword(lower) || isCommonPassword(leetReplacer.Replace(lower)) || isCommonPassword(leetReplacer.Replace(base)) {
+		return PasswordStrength{Score: PasswordScoreTooGuessable, Suggestions: []string{PasswordSuggestionAvoidCommon, PasswordSuggestionLonger}}
+	}
+
+	runes := []rune(lower)
+	effective := float64(len(runes))
+
+	// Personal information counts as a single guess
+	for _, input := range personalTokens(userInputs) {
+		if strings.Contains(lower, input) {
+			effective -= float64(len([]rune(input))) - 1
+			suggestions = appendOnce(suggestions, PasswordSuggestionAvoidPersonal)
+		}
+	}
+
+	// Sequences ("abcd", "4321", "qwer") and repeats ("aaaa") add little beyond their first character
+	if penalty := sequencePenalty(runes); penalty > 0 {
+		effective -= penalty
+		suggestions = appendOnce(suggestions, PasswordSuggestionAvoidSequence)
+	}
+	if penalty := repeatPenalty(runes); penalty > 0 {
+		effective -= penalty
+		suggestions = appendOnce(suggestions, PasswordSuggestionAvoidRepeat)
+	}
+
+	pool, classes := characterPool(password)
+	entropy := math.Max(effective, 1) * math.Log2(float64(pool))
+
+	if len(runes) < 12 {
+		suggestions = appendOnce(suggestions, PasswordSuggestionLonger)
+	}
+	if classes < 3 {
+		suggestions = appendOnce(suggestions, PasswordSuggestionAddVariety)
+	}
+
+	var score int
+	switch {
+	case entropy < 28:
+		score = PasswordScoreTooGuessable
+	case entropy < 36:
+		score = PasswordScoreVeryGuessable
+	case entropy < 50:
+		score = PasswordScoreSomewhatGuessable
+	case entropy < 64:
+		score = PasswordScoreSafelyUnguessable
+	default:
+		score = PasswordScoreVeryUnguessable
+	}
+
+	if len(suggestions) == 0 && score < PasswordScoreVeryUnguessable {
+		suggestions = []string{PasswordSuggestionLonger}
+	}
+
+	return PasswordStrength{Score: score, Entropy: entropy, Suggestions: suggestions}
+}
+
+func isCommonPassword(password string) bool {
+	_, ok := commonPasswords[password]
+	return ok
+}
+
+// personalTokens lowercases user inputs and splits emails into local part and domain name,
+// keeping tokens of at least 3 characters
+func personalTokens(userInputs []string) []string {
+	var tokens []string
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if local, domain, ok := strings.Cut(input, "@"); ok {
+			domainName, _, _ := strings.Cut(domain, ".")
+			tokens = append(tokens, local, domainName)
+			continue
+		}
+		tokens = append(tokens, strings.Fields(input)...)
+		tokens = append(tokens, strings.TrimLeft(input, "+0"))
+	}
+
+	filtered := tokens[:0]
+	for _, token := range tokens {
+		if len([]rune(token)) >= 3 {
+			filtered = append(filtered, token)
+		}
+	}
+	return filtered
+}
+
+// sequencePenalty counts characters that belong to an ascending/descending or keyboard run of 3+,
+// except the first character of each run
+func sequencePenalty(runes []rune) float64 {
+	return runPenalty(runes, func(a, b, c rune) bool {
+		step := b - a
+		return (step == 1 || step == -1) && c-b == step || onKeyboard(a, b, c)
+	})
+}
+
+// repeatPenalty counts characters that repeat the previous one in runs of 3+,
+// except the first character of each run
+func repeatPenalty(runes []rune) float64 {
+	return runPenalty(runes, func(a, b, c rune) bool {
+		return a == b && b == c
+	})
+}
+
+// runPenalty slides a 3-character window over runes and penalizes windows matching inRun
+func runPenalty(runes []rune, inRun func(a, b, c rune) bool) float64 {
+	var penalty float64
+	running := false
+	for i := 2; i < len(runes); i++ {
+		if !inRun(runes[i-2], runes[i-1], runes[i]) {
+			running = false
+			continue
+		}
+		if running {
+			penalty++
+		} else {
+			penalty += 2
+			running = true
+		}
+	}
+	return penalty
+}
+
+func onKeyboard(a, b, c rune) bool {
+	chunk, reversed := string([]rune{a, b, c}), string([]rune{c, b, a})
+	for _, row := range keyboardRows {
+		if strings.Contains(row, chunk) || strings.Contains(row, reversed) {
+			return true
+		}
+	}
+	return false
+}
+
+// characterPool returns the brute-force alphabet size and the number of character classes used
+func characterPool(password string) (pool, classes int) {
+	var hasLower, hasUpper, hasDigit, hasSymbol, hasOther bool
+	for _, char := range password {
+		switch {
+		case char >= 'a' && char <= 'z':
+			hasLower = true
+		case char >= 'A' && char <= 'Z':
+			hasUpper = true
+		case char >= '0' && char <= '9':
+			hasDigit = true
+		case char < unicode.MaxASCII && unicode.IsPrint(char):
+			hasSymbol = true
+		default:
+			hasOther = true
+		}
+	}
+
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{hasLower, 26}, {hasUpper, 26}, {hasDigit, 10}, {hasSymbol, 33}, {hasOther, 100}} {
+		if class.present {
+			pool += class.size
+			classes++
+		}
+	}
+	return pool, classes
+}
+
+func appendOnce(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
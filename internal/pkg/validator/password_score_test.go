@@ -0,0 +1,56 @@
+package validator_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScorePassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		userInputs []string
+		maxScore   int
+		minScore   int
+		suggestion string
+	}{
+		{"Common", "password", nil, 0, 0, validator.PasswordSuggestionAvoidCommon},
+		{"Common With Leet And Suffix", "P@ssw0rd123!", nil, 0, 0, validator.PasswordSuggestionAvoidCommon},
+		{"Sequence", "abcdefgh12", nil, 1, 0, validator.PasswordSuggestionAvoidSequence},
+		{"Repeat", "aaaaaaaaaaZ", nil, 1, 0, validator.PasswordSuggestionAvoidRepeat},
+		{"Personal", "johndoe1985!", []string{"John Doe", "johndoe@example.com"}, 2, 0, validator.PasswordSuggestionAvoidPersonal},
+		{"Short", "xK9#", nil, 1, 0, validator.PasswordSuggestionLonger},
+		{"Strong Passphrase", "correct horse battery staple", nil, 4, 4, ""},
+		{"Strong Mixed", "gT7#pQ2!vL9@", nil, 4, 4, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strength := validator.ScorePassword(tt.password, tt.userInputs...)
+
+			assert.LessOrEqual(t, strength.Score, tt.maxScore)
+			assert.GreaterOrEqual(t, strength.Score, tt.minScore)
+			if tt.suggestion != "" {
+				assert.Contains(t, strength.Suggestions, tt.suggestion)
+			}
+		})
+	}
+}
+
+func TestPasswordScoreTag(t *testing.T) {
+	type request struct {
+		Password string `json:"password" validate:"passwordScore=3"`
+	}
+
+	assert.NoError(t, validator.Input(request{Password: "gT7#pQ2!vL9@"}))
+
+	errs := validationErrors(t, validator.Input(request{Password: "password1"}, i18n.Indonesian))
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, validator.ErrorCodeInvalidField, errs[0].Code)
+		assert.Equal(t, "password terlalu lemah: hindari kata sandi umum dan variasinya", errs[0].Message)
+	}
+}
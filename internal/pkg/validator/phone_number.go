@@ -2,35 +2,40 @@ package validator
 
 import (
 	"errors"
+	"fmt"
 	stringc "go-echo-boilerplate/internal/pkg/stringc"
+	"strings"
 
 	"github.com/nyaruka/phonenumbers"
 )
 
-const (
-	DefaultCountryCode        = "ID"
-	IDPhonePrefix             = "08"
-	IDE164PhonePrefix         = "62"
-	IDE164PhonePrefixWithPlus = "+62"
-)
+// DefaultCountryCode is the country of national numbers validated without one
+const DefaultCountryCode = "ID"
 
+// PhoneNumber reports whether phoneNumber is a valid phone number. Numbers in international
+// format ("+44 7400 123456") may belong to any country; other numbers must be valid national
+// numbers of countryCode (an ISO 3166-1 alpha-2 region, DefaultCountryCode when empty).
 func PhoneNumber(countryCode, phoneNumber string) (isValid bool, err error) {
 	if stringc.ContainsAlphabet(phoneNumber) {
 		return false, errors.New("phone number contains alphabet")
 	}
 
-	if countryCode == "" {
-		countryCode = DefaultCountryCode
+	region := strings.ToUpper(strings.TrimSpace(countryCode))
+	if region == "" {
+		region = DefaultCountryCode
+	}
+	if phonenumbers.GetCountryCodeForRegion(region) == 0 {
+		return false, fmt.Errorf("unknown country code %q", countryCode)
 	}
 
 	// parse to phone struct format
-	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber, countryCode)
+	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber, region)
 	if err != nil {
 		return false, err
 	}
 
-	// check if phone number valid
-	if !phonenumbers.IsValidNumberForRegion(parsedPhoneNumber, countryCode) {
+	// check if phone number valid for the country it belongs to
+	if !phonenumbers.IsValidNumber(parsedPhoneNumber) {
 		return false, errors.New("invalid phone number")
 	}
 
@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoneNumber(t *testing.T) {
+	valid := []struct{ countryCode, number string }{
+		{"", "081234567890"},
+		{"", "+44 7400 123456"},
+		{"GB", "07400 123456"},
+		{"us", "(650) 253-0000"},
+	}
+	for _, tt := range valid {
+		isValid, err := PhoneNumber(tt.countryCode, tt.number)
+		assert.NoError(t, err, "Should validate: %s %s", tt.countryCode, tt.number)
+		assert.True(t, isValid)
+	}
+
+	invalid := []struct{ countryCode, number string }{
+		{"", "call me"},
+		{"", "07400 123456"},
+		{"GB", "081234567890"},
+		{"XX", "081234567890"},
+	}
+	for _, tt := range invalid {
+		isValid, err := PhoneNumber(tt.countryCode, tt.number)
+		assert.Error(t, err, "Should reject: %s %s", tt.countryCode, tt.number)
+		assert.False(t, isValid)
+	}
+}
+
+func TestPhoneFormat(t *testing.T) {
+	assert.Nil(t, Input(&models.PhoneNumber{CountryCode: "GB", Number: "07400 123456"}))
+	assert.Nil(t, Input(&models.PhoneNumber{Number: "+447400123456"}))
+	assert.NotNil(t, Input(&models.PhoneNumber{Number: "07400 123456"}))
+}
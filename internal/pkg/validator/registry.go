@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"fmt"
+	"sync"
+
+	v10 "github.com/go-playground/validator/v10"
+)
+
+// registryMu guards validationErrorMap against concurrent registration and lookup.
+//
+// Note: the underlying go-playground validator is not safe for registering rules
+// while validating, so custom rules should be registered during startup
+// (e.g., in core.Setup) before the server accepts traffic.
+var registryMu sync.RWMutex
+
+// RuleOption customizes the error reported for a registered rule
+type RuleOption func(*validationErrorConfig)
+
+// WithCode sets the error code reported when the rule fails (default: INVALID_FIELD)
+func WithCode(code string) RuleOption {
+	return func(c *validationErrorConfig) {
+		c.Code = code
+	}
+}
+
+// WithMessage sets the message builder used when the rule fails
+func WithMessage(builder MessageBuilder) RuleOption {
+	return func(c *validationErrorConfig) {
+		c.MessageBuilder = builder
+	}
+}
+
+// WithMessageKey renders the message from an i18n catalog key, formatted with the field name
+func WithMessageKey(key string) RuleOption {
+	return WithMessage(fieldMessage(key))
+}
+
+// RegisterValidation registers a custom field validator under the given tag,
+// along with the error code and message reported when it fails.
+// Registering an existing tag overrides both the rule and its message.
+//
+// Example:
+//
+//	err := validator.RegisterValidation("evenNumber", func(fl v10.FieldLevel) bool {
+//	    return fl.Field().Int()%2 == 0
+//	}, validator.WithMessage(func(fe v10.FieldError, _ i18n.Locale) string {
+//	    return fmt.Sprintf("%s must be an even number", fe.Field())
+//	}))
+func RegisterValidation(tag string, fn v10.Func, opts ...RuleOption) error {
+	if tag == "" {
+		return fmt.Errorf("validation tag must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("validation function for tag %q must not be nil", tag)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if err := valid.RegisterValidation(tag, fn); err != nil {
+		return fmt.Errorf("failed to register validation %q: %w", tag, err)
+	}
+
+	if len(opts) > 0 {
+		config := validationErrorMap[tag]
+		if config.Code == "" {
+			config.Code = ErrorCodeInvalidField
+		}
+		for _, opt := range opts {
+			opt(&config)
+		}
+		validationErrorMap[tag] = config
+	}
+
+	return nil
+}
+
+// RegisterConditionalValidation registers a field validator that only runs when
+// the condition holds; otherwise the field is considered valid.
+//
+// Example:
+//
+//	// Require a tax ID only for business accounts
+//	err := validator.RegisterConditionalValidation("taxIDForBusiness",
+//	    func(fl v10.FieldLevel) bool { return fl.Parent().FieldByName("Type").String() == "business" },
+//	    func(fl v10.FieldLevel) bool { return fl.Field().String() != "" },
+//	    validator.WithCode(validator.ErrorCodeMissingField),
+//	)
+func RegisterConditionalValidation(tag string, condition func(fl v10.FieldLevel) bool, fn v10.Func, opts ...RuleOption) error {
+	if condition == nil {
+		return fmt.Errorf("condition for tag %q must not be nil", tag)
+	}
+	if fn == nil {
+		return fmt.Errorf("validation function for tag %q must not be nil", tag)
+	}
+
+	return RegisterValidation(tag, func(fl v10.FieldLevel) bool {
+		if !condition(fl) {
+			return true
+		}
+		return fn(fl)
+	}, opts...)
+}
+
+// RegisterStructValidation registers a struct-level validator for the given types.
+// Errors reported through sl.ReportError are mapped like field errors, so pair it
+// with RegisterMessage for the tags it reports.
+//
+// Example:
+//
+//	validator.RegisterStructValidation(func(sl v10.StructLevel) {
+//	    req := sl.Current().Interface().(models.DateRangeRequest)
+//	    if req.To.Before(req.From) {
+//	        sl.ReportError(req.To, "to", "To", "afterFrom", "")
+//	    }
+//	}, models.DateRangeRequest{})
+func RegisterStructValidation(fn v10.StructLevelFunc, types ...interface{}) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	valid.RegisterStructValidation(fn, types...)
+}
+
+// RegisterMessage overrides (or adds) the error code and message for a tag,
+// including the built-in ones in validationErrorMap.
+//
+// Example:
+//
+//	validator.RegisterMessage(validator.TagRequired, validator.ErrorCodeMissingField,
+//	    func(fe v10.FieldError, _ i18n.Locale) string { return fe.Field() + " can't be blank" })
+func RegisterMessage(tag, code string, builder MessageBuilder) {
+	if code == "" {
+		code = ErrorCodeInvalidField
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	validationErrorMap[tag] = validationErrorConfig{
+		Code:           code,
+		MessageBuilder: builder,
+	}
+}
@@ -0,0 +1,91 @@
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryTestRequest struct {
+	Type  string `json:"type"`
+	Code  string `json:"code" validate:"registryEven"`
+	TaxID string `json:"taxId" validate:"registryTaxID"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+}
+
+func validationErrors(t *testing.T, err error) []models.ErrorValidationResponse {
+	t.Helper()
+
+	var merr *multierror.Error
+	require.True(t, errors.As(err, &merr))
+
+	result := make([]models.ErrorValidationResponse, 0, len(merr.Errors))
+	for _, e := range merr.Errors {
+		result = append(result, e.(models.ErrorValidationResponse))
+	}
+	return result
+}
+
+func TestRegistry(t *testing.T) {
+	require.NoError(t, validator.RegisterValidation("registryEven", func(fl v10.FieldLevel) bool {
+		return len(fl.Field().String())%2 == 0
+	}, validator.WithCode("ODD_CODE"), validator.WithMessage(func(fe v10.FieldError, _ i18n.Locale) string {
+		return fe.Field() + " must have an even length"
+	})))
+
+	require.NoError(t, validator.RegisterConditionalValidation("registryTaxID",
+		func(fl v10.FieldLevel) bool { return fl.Parent().FieldByName("Type").String() == "business" },
+		func(fl v10.FieldLevel) bool { return fl.Field().String() != "" },
+		validator.WithCode(validator.ErrorCodeMissingField),
+	))
+
+	validator.RegisterStructValidation(func(sl v10.StructLevel) {
+		req := sl.Current().Interface().(registryTestRequest)
+		if req.To < req.From {
+			sl.ReportError(req.To, "to", "To", "registryAfterFrom", "")
+		}
+	}, registryTestRequest{})
+	validator.RegisterMessage("registryAfterFrom", "", func(fe v10.FieldError, _ i18n.Locale) string {
+		return "to must not be before from"
+	})
+
+	t.Run("Valid Request", func(t *testing.T) {
+		assert.NoError(t, validator.Input(registryTestRequest{Code: "ab", Type: "personal", From: 1, To: 2}))
+	})
+
+	t.Run("Custom Field Rule Uses Registered Code And Message", func(t *testing.T) {
+		errs := validationErrors(t, validator.Input(registryTestRequest{Code: "abc", To: 1}))
+		require.Len(t, errs, 1)
+		assert.Equal(t, "ODD_CODE", errs[0].Code)
+		assert.Equal(t, "code must have an even length", errs[0].Message)
+	})
+
+	t.Run("Conditional Rule Only Applies When Condition Holds", func(t *testing.T) {
+		assert.NoError(t, validator.Input(registryTestRequest{Type: "personal"}))
+
+		errs := validationErrors(t, validator.Input(registryTestRequest{Type: "business"}))
+		require.Len(t, errs, 1)
+		assert.Equal(t, validator.ErrorCodeMissingField, errs[0].Code)
+		assert.Equal(t, "taxId", errs[0].Field)
+	})
+
+	t.Run("Struct Level Rule", func(t *testing.T) {
+		errs := validationErrors(t, validator.Input(registryTestRequest{From: 5, To: 1}))
+		require.Len(t, errs, 1)
+		assert.Equal(t, validator.ErrorCodeInvalidField, errs[0].Code)
+		assert.Equal(t, "to must not be before from", errs[0].Message)
+	})
+
+	t.Run("Empty Tag Is Rejected", func(t *testing.T) {
+		assert.Error(t, validator.RegisterValidation("", func(fl v10.FieldLevel) bool { return true }))
+	})
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
 	"net/mail"
 	"reflect"
 	"regexp"
@@ -23,8 +24,15 @@ var (
 	datetimePattern       *regexp.Regexp
 )
 
-// Validate validates a struct and returns validation errors
-func Input(request interface{}) error {
+// Input validates a struct and returns validation errors.
+// Messages are rendered in the optional locale (default: English).
+//
+// Example:
+//
+//	if err := validator.Input(request, i18n.FromContext(ctx.Request().Context())); err != nil {
+//	    return response.ErrorValidation(ctx, err)
+//	}
+func Input(request interface{}, locale ...i18n.Locale) error {
 	var errs *multierror.Error
 	if err := valid.Struct(request); err != nil {
 		// This check is only needed when your code could produce
@@ -40,7 +48,7 @@ func Input(request interface{}) error {
 		var validatorErrs v10.ValidationErrors
 		if errors.As(err, &validatorErrs) {
 			for _, validatorErr := range validatorErrs {
-				validationError := ValidationMapper(validatorErr)
+				validationError := ValidationMapper(validatorErr, locale...)
 				errs = multierror.Append(errs, validationError)
 			}
 		}
@@ -76,6 +84,7 @@ func init() {
 	registerEmailPhoneFormat()
 	registeryyyymmddFormat()
 	registerEmailOrPhoneField()
+	registerUsername()
 	registerPasswordValidations()
 }
 
@@ -195,7 +204,7 @@ func registerPhoneFormat() {
 			return true // Empty strings are valid
 		}
 
-		isValid, err := PhoneNumber("", str)
+		isValid, err := PhoneNumber(siblingCountryCode(fl), str)
 		if err != nil {
 			return false
 		}
@@ -206,6 +215,27 @@ func registerPhoneFormat() {
 	}
 }
 
+// siblingCountryCode returns the CountryCode field next to a phone number (see
+// models.PhoneNumber), empty when the struct has none
+func siblingCountryCode(fl v10.FieldLevel) string {
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Pointer {
+		if parent.IsNil() {
+			return ""
+		}
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return ""
+	}
+
+	field := parent.FieldByName("CountryCode")
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+	return field.String()
+}
+
 func registerEmailPhoneFormat() {
 	if err := valid.RegisterValidation("emailPhoneFormat", func(fl v10.FieldLevel) bool {
 		str, ok := getStringValue(fl)
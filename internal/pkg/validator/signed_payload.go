@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSignedPayloadInvalid is returned for malformed or tampered tokens
+	ErrSignedPayloadInvalid = errors.New("invalid signed payload")
+	// ErrSignedPayloadExpired is returned for correctly signed tokens past their expiry
+	ErrSignedPayloadExpired = errors.New("signed payload expired")
+)
+
+// SignedPayload verifies a token created by generator.SignedPayload and returns its data.
+// The signature is checked before the expiry, so ErrSignedPayloadExpired is only
+// returned for tokens that were genuinely issued with the secret.
+//
+// Example:
+//
+//	data, err := validator.SignedPayload(ctx.QueryParam("token"), secret)
+//	if errors.Is(err, validator.ErrSignedPayloadExpired) {
+//	    // ask the user to request a new link
+//	}
+func SignedPayload(token string, secret string) (string, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return "", ErrSignedPayloadInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) < 8 {
+		return "", ErrSignedPayloadInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", ErrSignedPayloadInvalid
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", ErrSignedPayloadInvalid
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), 0)
+	if time.Now().After(expiresAt) {
+		return "", ErrSignedPayloadExpired
+	}
+
+	return string(payload[8:]), nil
+}
@@ -0,0 +1,68 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedPayload(t *testing.T) {
+	secret := "test-signing-secret"
+
+	t.Run("Round Trip", func(t *testing.T) {
+		token, err := generator.SignedPayload("unsubscribe:1234567890", time.Hour, secret)
+		assert.NoError(t, err)
+		assert.NotContains(t, token, "=") // URL safe, unpadded
+
+		data, err := validator.SignedPayload(token, secret)
+		assert.NoError(t, err)
+		assert.Equal(t, "unsubscribe:1234567890", data)
+	})
+
+	t.Run("Wrong Secret", func(t *testing.T) {
+		token, _ := generator.SignedPayload("data", time.Hour, secret)
+
+		_, err := validator.SignedPayload(token, "other-secret")
+		assert.ErrorIs(t, err, validator.ErrSignedPayloadInvalid)
+	})
+
+	t.Run("Tampered Payload", func(t *testing.T) {
+		token, _ := generator.SignedPayload("download:1", time.Hour, secret)
+		forged, _ := generator.SignedPayload("download:2", time.Hour, secret)
+
+		// Swap in another payload while keeping the original signature
+		payload, _, _ := strings.Cut(forged, ".")
+		_, signature, _ := strings.Cut(token, ".")
+
+		_, err := validator.SignedPayload(payload+"."+signature, secret)
+		assert.ErrorIs(t, err, validator.ErrSignedPayloadInvalid)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		token, _ := generator.SignedPayload("data", time.Nanosecond, secret)
+		time.Sleep(1100 * time.Millisecond) // expiry has second precision
+
+		_, err := validator.SignedPayload(token, secret)
+		assert.ErrorIs(t, err, validator.ErrSignedPayloadExpired)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		for _, token := range []string{"", "abc", "abc.def", "!!!.???"} {
+			_, err := validator.SignedPayload(token, secret)
+			assert.ErrorIs(t, err, validator.ErrSignedPayloadInvalid, token)
+		}
+	})
+
+	t.Run("Invalid Arguments", func(t *testing.T) {
+		_, err := generator.SignedPayload("data", time.Hour, "")
+		assert.Error(t, err)
+
+		_, err = generator.SignedPayload("data", 0, secret)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+
+	v10 "github.com/go-playground/validator/v10"
+)
+
+// Length bounds of a username
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 30
+)
+
+// DefaultReservedUsernames are refused as usernames: they name routes, roles, or the service
+// itself and could be used to impersonate it. username.reserved adds to them.
+var DefaultReservedUsernames = []string{
+	"about", "account", "accounts", "admin", "administrator", "api", "app", "auth", "billing",
+	"contact", "dashboard", "help", "info", "login", "logout", "mail", "me", "moderator", "null",
+	"official", "operator", "owner", "postmaster", "privacy", "register", "root", "security",
+	"settings", "signin", "signup", "staff", "status", "support", "system", "team", "terms",
+	"undefined", "user", "users", "webmaster", "www",
+}
+
+// Reasons a username is refused
+var (
+	ErrUsernameLength     = errors.New("username must be 3 to 30 characters")
+	ErrUsernameCharacters = errors.New("username may only contain letters, digits, dots, and underscores, starting with a letter or digit and not ending with a dot")
+	ErrUsernameReserved   = errors.New("username is reserved")
+)
+
+// NormalizeUsername returns the stored form of a username: trimmed and lowercased, so
+// usernames are unique regardless of case.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// Username checks a normalized username (see NormalizeUsername): 3 to 30 ASCII letters,
+// digits, dots, and underscores, starting with a letter or digit, without a trailing or
+// doubled dot, and neither one of DefaultReservedUsernames nor of reserved. Reserved names
+// are compared without their dots and underscores, so "ad.min" is reserved too.
+func Username(username string, reserved ...string) error {
+	if len(username) < UsernameMinLength || len(username) > UsernameMaxLength {
+		return ErrUsernameLength
+	}
+
+	for i := 0; i < len(username); i++ {
+		c := username[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case (c == '.' || c == '_') && i > 0:
+			if c == '.' && (i == len(username)-1 || username[i-1] == '.') {
+				return ErrUsernameCharacters
+			}
+		default:
+			return ErrUsernameCharacters
+		}
+	}
+
+	bare := bareUsername(username)
+	for _, lists := range [][]string{DefaultReservedUsernames, reserved} {
+		for _, name := range lists {
+			if bareUsername(NormalizeUsername(name)) == bare {
+				return ErrUsernameReserved
+			}
+		}
+	}
+	return nil
+}
+
+// bareUsername drops the separators of a username
+func bareUsername(username string) string {
+	return strings.NewReplacer(".", "", "_", "").Replace(username)
+}
+
+// registerUsername validates usernames against DefaultReservedUsernames; the service also
+// checks username.reserved
+func registerUsername() {
+	if err := valid.RegisterValidation("username", func(fl v10.FieldLevel) bool {
+		str, ok := getStringValue(fl)
+		if !ok {
+			return false
+		}
+
+		if str == "" {
+			return true // Empty strings are valid
+		}
+
+		return Username(NormalizeUsername(str)) == nil
+	}); err != nil {
+		panic(err)
+	}
+}
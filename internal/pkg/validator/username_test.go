@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsername(t *testing.T) {
+	for _, username := range []string{"john", "john.doe", "john_doe_", "j0hn", "007", "abcdefghijklmnopqrstuvwxyz1234"} {
+		assert.NoError(t, Username(username), "Should accept: %s", username)
+	}
+
+	tests := []struct {
+		username string
+		err      error
+	}{
+		{"jo", ErrUsernameLength},
+		{"abcdefghijklmnopqrstuvwxyz12345", ErrUsernameLength},
+		{"john doe", ErrUsernameCharacters},
+		{"John", ErrUsernameCharacters},
+		{"_john", ErrUsernameCharacters},
+		{".john", ErrUsernameCharacters},
+		{"john.", ErrUsernameCharacters},
+		{"john..doe", ErrUsernameCharacters},
+		{"jöhn", ErrUsernameCharacters},
+		{"admin", ErrUsernameReserved},
+		{"ad.min_", ErrUsernameReserved},
+	}
+	for _, tt := range tests {
+		assert.ErrorIs(t, Username(tt.username), tt.err, "Should reject: %s", tt.username)
+	}
+
+	assert.ErrorIs(t, Username("acme", "ACME"), ErrUsernameReserved, "configured names are reserved too")
+	assert.Equal(t, "john.doe", NormalizeUsername("  John.Doe "))
+}
+
+func TestUsernameTag(t *testing.T) {
+	type request struct {
+		Username string `json:"username" validate:"omitempty,username"`
+	}
+
+	assert.Nil(t, Input(&request{}))
+	assert.Nil(t, Input(&request{Username: "John.Doe"}), "usernames are checked case-insensitively")
+	assert.NotNil(t, Input(&request{Username: "root"}))
+}
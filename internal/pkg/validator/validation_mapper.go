@@ -1,8 +1,9 @@
 package validator
 
 import (
-	"fmt"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"reflect"
 	"strings"
 	"time"
 
@@ -31,9 +32,11 @@ const (
 	TagYYYYMMDDNoExceedToday = "yyyymmddNoExceedToday"
 	TagHHMMFormat            = "hhmmFormat"
 	TagEmailOrPhoneField     = "emailOrPhoneField"
+	TagUsername              = "username"
 	TagPasswordMinLength     = "passwordMinLength"
 	TagPasswordMaxLength     = "passwordMaxLength"
 	TagPasswordStrength      = "passwordStrength"
+	TagPasswordScore         = "passwordScore"
 )
 
 // Error code constants
@@ -42,173 +45,225 @@ const (
 	ErrorCodeInvalidField = "INVALID_FIELD"
 )
 
+// MessageBuilder renders the (localized) message for a failed validation
+type MessageBuilder func(fe v10.FieldError, locale i18n.Locale) string
+
 // validationErrorConfig holds the configuration for each validation error
 type validationErrorConfig struct {
 	Code           string
-	MessageBuilder func(v10.FieldError) string
+	MessageBuilder MessageBuilder
+}
+
+// fieldMessage builds a message builder for catalog entries formatted with the field name only
+func fieldMessage(key string) MessageBuilder {
+	return func(fe v10.FieldError, locale i18n.Locale) string {
+		return i18n.T(locale, key, fe.Field())
+	}
+}
+
+// fieldParamMessage builds a message builder for catalog entries formatted with the field name and tag param
+func fieldParamMessage(key string) MessageBuilder {
+	return func(fe v10.FieldError, locale i18n.Locale) string {
+		return i18n.T(locale, key, fe.Field(), fe.Param())
+	}
+}
+
+// lengthOrValueMessage picks the ".number" variant of key for numeric fields,
+// so min/max read as a value bound instead of a character count (e.g. query "limit")
+func lengthOrValueMessage(key string) MessageBuilder {
+	return func(fe v10.FieldError, locale i18n.Locale) string {
+		switch fe.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			key += ".number"
+		}
+		return i18n.T(locale, key, fe.Field(), fe.Param())
+	}
 }
 
 // validationErrorMap provides O(1) lookup for validation error messages
 var validationErrorMap = map[string]validationErrorConfig{
 	TagRequired: {
 		Code:           ErrorCodeMissingField,
-		MessageBuilder: func(fe v10.FieldError) string { return fmt.Sprintf("%s is required", fe.Field()) },
+		MessageBuilder: fieldMessage("validation.required"),
 	},
 	TagRequiredWithout: {
 		Code: ErrorCodeMissingField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s is required when %s is not inputted", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			return i18n.T(locale, "validation.required_without", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
 		},
 	},
 	TagRequiredWithoutAll: {
 		Code: ErrorCodeMissingField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s is required when %s is not inputted", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			return i18n.T(locale, "validation.required_without", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
 		},
 	},
 	TagOneOf: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s should be inputted either %s", fe.Field(), fe.Param())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldParamMessage("validation.oneof"),
 	},
 	TagMin: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: lengthOrValueMessage("validation.min"),
 	},
 	TagMax: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: lengthOrValueMessage("validation.max"),
 	},
 	TagEmail: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid email address", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.email"),
 	},
 	TagNoSpecial: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must not contain special characters", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.nospecial"),
 	},
 	TagNoStartEndSpaces: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must not start or end with spaces", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.noStartEndSpaces"),
 	},
 	TagDate: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid date in YYYY-MM-DD format", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.date"),
 	},
 	TagDatetime: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid date in YYYY-MM-DD HH:MM:SS format", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.datetime"),
 	},
 	TagYYYMMDDFormat: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid date in YYYY-MM-DD format", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.date"),
 	},
 	TagPhoneFormat: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid phone number in format +628123456789", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.phoneFormat"),
 	},
 	TagEmailFormat: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid email address", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.email"),
 	},
 	TagEmailPhoneFormat: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid email or phone number", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.emailPhoneFormat"),
 	},
 	TagNumeric: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid number", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.numeric"),
 	},
 	TagDateRange: {
 		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("Your date range should be between %s and %s",
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			return i18n.T(locale, "validation.daterange",
 				time.Now().AddDate(0, -6, 0).Format("2006-01-02"),
 				time.Now().Format("2006-01-02"))
 		},
 	},
 	TagYYYYMMDDNoExceedToday: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid date in YYYY-MM-DD format and not exceed today", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.yyyymmddNoExceedToday"),
 	},
 	TagHHMMFormat: {
-		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be a valid time in HH:MM format", fe.Field())
-		},
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.hhmmFormat"),
 	},
 	TagEmailOrPhoneField: {
-		Code:           ErrorCodeMissingField,
-		MessageBuilder: func(fe v10.FieldError) string { return "Email or Phone Number cannot be empty" },
+		Code: ErrorCodeMissingField,
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			return i18n.T(locale, "validation.emailOrPhoneField")
+		},
+	},
+	TagUsername: {
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.username"),
 	},
 	TagPasswordMinLength: {
 		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must be at least 8 characters long", fe.Field())
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			return i18n.T(locale, "validation.passwordMinLength", fe.Field(), MinPasswordLength)
 		},
 	},
 	TagPasswordMaxLength: {
 		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must not exceed 72 characters (bcrypt limit)", fe.Field())
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			return i18n.T(locale, "validation.passwordMaxLength", fe.Field(), MaxPasswordLength)
 		},
 	},
 	TagPasswordStrength: {
+		Code:           ErrorCodeInvalidField,
+		MessageBuilder: fieldMessage("validation.passwordStrength"),
+	},
+	TagPasswordScore: {
 		Code: ErrorCodeInvalidField,
-		MessageBuilder: func(fe v10.FieldError) string {
-			return fmt.Sprintf("%s must contain at least one uppercase letter, one lowercase letter, one number, and one special character", fe.Field())
+		MessageBuilder: func(fe v10.FieldError, locale i18n.Locale) string {
+			password, _ := fe.Value().(string)
+			return PasswordScoreMessage(fe.Field(), ScorePassword(password), locale)
 		},
 	},
 }
 
-// ValidationMapper maps a validator.FieldError to an ErrorValidationResponse
-func ValidationMapper(validationError v10.FieldError) models.ErrorValidationResponse {
-	code, message := ErrorValidationMapping(validationError)
+// PasswordScoreMessage renders an actionable message for a weak password,
+// using the most important suggestion from its PasswordStrength.
+func PasswordScoreMessage(field string, strength PasswordStrength, locale i18n.Locale) string {
+	suggestion := PasswordSuggestionLonger
+	if len(strength.Suggestions) > 0 {
+		suggestion = strength.Suggestions[0]
+	}
+	return i18n.T(locale, "validation.passwordScore", field, i18n.T(locale, suggestion))
+}
+
+// ValidationMapper maps a validator.FieldError to an ErrorValidationResponse.
+// The message is rendered in the locale passed as the optional second argument (default: English).
+func ValidationMapper(validationError v10.FieldError, locale ...i18n.Locale) models.ErrorValidationResponse {
+	code, message := ErrorValidationMapping(validationError, locale...)
 
 	return models.ErrorValidationResponse{
 		Code:    code,
-		Field:   validationError.Field(),
+		Field:   FieldPath(validationError),
 		Message: message,
 	}
 }
 
-// ErrorValidationMapping returns the error code and message for a validation error
+// FieldPath returns the JSON-path style location of the failed field relative to the
+// validated struct, built from JSON tag names (e.g. "items[2].price", "phoneNumber.number").
+// Top-level fields are returned as their plain JSON name.
+//
+// Note: slice and map elements are only validated when the field has the `dive` tag.
+func FieldPath(validationError v10.FieldError) string {
+	namespace := validationError.Namespace()
+
+	// Strip the root struct name ("CreateUserRequest.items[2].price" -> "items[2].price")
+	if _, path, found := strings.Cut(namespace, "."); found && path != "" {
+		return path
+	}
+
+	return validationError.Field()
+}
+
+// ErrorValidationMapping returns the error code and localized message for a validation error
 // Uses map-based lookup for O(1) performance instead of switch statement
-func ErrorValidationMapping(validationError v10.FieldError) (string, string) {
+func ErrorValidationMapping(validationError v10.FieldError, locale ...i18n.Locale) (string, string) {
 	tag := validationError.Tag()
 
+	lang := i18n.DefaultLocale
+	if len(locale) > 0 {
+		lang = locale[0]
+	}
+
 	// Use map lookup for known validation tags
-	if config, exists := validationErrorMap[tag]; exists {
-		return config.Code, config.MessageBuilder(validationError)
+	registryMu.RLock()
+	config, exists := validationErrorMap[tag]
+	registryMu.RUnlock()
+
+	if exists && config.MessageBuilder != nil {
+		return config.Code, config.MessageBuilder(validationError, lang)
 	}
 
-	// Default case for unknown validation tags
-	return ErrorCodeInvalidField, fmt.Sprintf("invalid input on field %s: %s", validationError.Field(), tag)
+	// Default case for unknown validation tags (or registered tags without a message)
+	code := ErrorCodeInvalidField
+	if exists && config.Code != "" {
+		code = config.Code
+	}
+	return code, i18n.T(lang, "validation.default", validationError.Field(), tag)
 }
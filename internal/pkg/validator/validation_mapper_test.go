@@ -0,0 +1,62 @@
+package validator_test
+
+import (
+	"testing"
+
+	"go-echo-boilerplate/internal/pkg/i18n"
+	"go-echo-boilerplate/internal/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nestedItem struct {
+	Name  string `json:"name" validate:"required"`
+	Price int    `json:"price" validate:"min=1"`
+}
+
+type nestedAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type nestedRequest struct {
+	Title   string            `json:"title" validate:"required"`
+	Address nestedAddress     `json:"address"`
+	Items   []nestedItem      `json:"items" validate:"dive"`
+	Tags    map[string]string `json:"tags" validate:"dive,required"`
+}
+
+func TestFieldPath(t *testing.T) {
+	request := nestedRequest{
+		Title: "",
+		Items: []nestedItem{
+			{Name: "first", Price: 10},
+			{Name: "", Price: 10},
+			{Name: "third", Price: 0},
+		},
+		Tags: map[string]string{"color": ""},
+	}
+
+	errs := validationErrors(t, validator.Input(request))
+
+	fields := make(map[string]string, len(errs))
+	for _, e := range errs {
+		fields[e.Field] = e.Message
+	}
+
+	require.Len(t, fields, 5)
+	assert.Contains(t, fields, "title")
+	assert.Contains(t, fields, "address.city")
+	assert.Contains(t, fields, "items[1].name")
+	assert.Contains(t, fields, "items[2].price")
+	assert.Contains(t, fields, "tags[color]")
+	assert.Equal(t, "city is required", fields["address.city"])
+}
+
+func TestValidationLocale(t *testing.T) {
+	errs := validationErrors(t, validator.Input(nestedRequest{Address: nestedAddress{City: "Jakarta"}}, i18n.Indonesian))
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "title", errs[0].Field)
+	assert.Equal(t, "title wajib diisi", errs[0].Message)
+}
@@ -1,16 +1,56 @@
 package repository
 
 import (
+	"fmt"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/cache"
 	"go-echo-boilerplate/internal/pkg/database"
+	"go-echo-boilerplate/internal/pkg/di"
 	"go-echo-boilerplate/internal/repository/pgsql"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type Repository struct {
 	Postgre *pgsql.PostgreRepository
 }
 
-func New(database *database.Database) *Repository {
-	return &Repository{
-		Postgre: pgsql.New(database.PostgreDatabase),
+// Provide registers the repositories of every store, built on the *database.Database of the
+// container. A new repository is added to the New of its store, which transactions reuse.
+func Provide(c *di.Container) {
+	di.Provide(c, func(database *database.Database) *gorm.DB {
+		return database.PostgreDatabase
+	})
+	di.Provide(c, func(db *gorm.DB, database *database.Database, configuration *config.Configuration) (*pgsql.PostgreRepository, error) {
+		postgre := pgsql.New(db)
+		if err := cacheRepositories(postgre, database, configuration.RepositoryCache); err != nil {
+			return nil, err
+		}
+		return postgre, nil
+	})
+
+	di.Provide(c, func(postgre *pgsql.PostgreRepository) *Repository {
+		return &Repository{
+			Postgre: postgre,
+		}
+	})
+}
+
+// cacheRepositories caches the reads of repository_cache, when its ttl is set
+func cacheRepositories(postgre *pgsql.PostgreRepository, database *database.Database, cfg config.RepositoryCache) error {
+	if cfg.TTL == "" {
+		return nil
+	}
+
+	ttl, err := time.ParseDuration(cfg.TTL)
+	if err != nil {
+		return fmt.Errorf("invalid repository_cache.ttl: %w", err)
+	}
+	store, err := cache.NewStore(cfg.Store, cfg.MaxEntries, database.Redis)
+	if err != nil {
+		return fmt.Errorf("repository_cache.store: %w", err)
 	}
+	postgre.CacheUsers(store, ttl)
+	return nil
 }
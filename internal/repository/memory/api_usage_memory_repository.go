@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"maps"
+	"sync"
+	"time"
+)
+
+type apiUsageKey struct {
+	client string
+	period string
+	start  time.Time
+}
+
+type apiUsageRepository struct {
+	mu       sync.Mutex
+	requests map[apiUsageKey]int64
+}
+
+// NewAPIUsageRepository creates an empty APIUsageRepository.
+func NewAPIUsageRepository() pgsql.APIUsageRepository {
+	return &apiUsageRepository{requests: map[apiUsageKey]int64{}}
+}
+
+// Add adds delta requests to the usage of the client in the period starting at start and
+// returns its total.
+func (ar *apiUsageRepository) Add(ctx context.Context, client, period string, start time.Time, delta int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	key := apiUsageKey{client: client, period: period, start: start.UTC()}
+	ar.requests[key] += delta
+	return ar.requests[key], nil
+}
+
+// snapshot implements snapshotter.
+func (ar *apiUsageRepository) snapshot() func() {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	requests := maps.Clone(ar.requests)
+	return func() {
+		ar.mu.Lock()
+		defer ar.mu.Unlock()
+		ar.requests = requests
+	}
+}
@@ -0,0 +1,34 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIUsage(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewAPIUsageRepository()
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	total, err := repo.Add(ctx, "partner-a", "daily", today, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	total, err = repo.Add(ctx, "partner-a", "daily", today.In(time.FixedZone("WIB", 7*60*60)), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total, "the same period in another zone")
+
+	total, err = repo.Add(ctx, "partner-a", "daily", today.AddDate(0, 0, 1), 0)
+	require.NoError(t, err)
+	assert.Zero(t, total, "another period")
+
+	total, err = repo.Add(ctx, "partner-b", "daily", today, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total, "another client")
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"sync"
+	"time"
+)
+
+type consentRepository struct {
+	mu       sync.RWMutex
+	consents []models.Consent // in insertion order, IDs are index+1
+}
+
+// NewConsentRepository creates an empty ConsentRepository. Unlike the consents table, it
+// doesn't check that the account exists.
+func NewConsentRepository() pgsql.ConsentRepository {
+	return &consentRepository{}
+}
+
+// Create inserts the consent, setting its ID and zero timestamp like gorm does.
+func (cr *consentRepository) Create(ctx context.Context, consent *models.Consent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if consent.CreatedAt.IsZero() {
+		consent.CreatedAt = time.Now()
+	}
+	consent.ID = int64(len(cr.consents) + 1)
+
+	cr.consents = append(cr.consents, *consent)
+	return nil
+}
+
+// GetLatest returns the newest consent of the account, like QueryGetLatestConsent.
+func (cr *consentRepository) GetLatest(ctx context.Context, accountNumber string) (*models.Consent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	var latest *models.Consent
+	for i := range cr.consents {
+		consent := &cr.consents[i]
+		if consent.AccountNumber != accountNumber {
+			continue
+		}
+		if latest == nil || !consent.CreatedAt.Before(latest.CreatedAt) {
+			latest = consent
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+	found := *latest
+	return &found, nil
+}
+
+// Anonymize clears the IP and user agent of every consent of the account.
+func (cr *consentRepository) Anonymize(ctx context.Context, accountNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	for i := range cr.consents {
+		if cr.consents[i].AccountNumber == accountNumber {
+			cr.consents[i].IP, cr.consents[i].UserAgent = "", ""
+		}
+	}
+	return nil
+}
+
+// snapshot implements snapshotter.
+func (cr *consentRepository) snapshot() func() {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	consents := slices.Clone(cr.consents)
+	return func() {
+		cr.mu.Lock()
+		defer cr.mu.Unlock()
+		cr.consents = consents
+	}
+}
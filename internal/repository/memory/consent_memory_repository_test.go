@@ -0,0 +1,47 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsent(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewConsentRepository()
+
+	consent, err := repo.GetLatest(ctx, "12345")
+	require.NoError(t, err)
+	assert.Nil(t, consent, "no consent yet")
+
+	first := models.Consent{AccountNumber: "12345", TermsVersion: "2026-01-01", IP: "81.2.69.142", CreatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, repo.Create(ctx, &first))
+	assert.Equal(t, int64(1), first.ID)
+	require.NoError(t, repo.Create(ctx, &models.Consent{AccountNumber: "67890", TermsVersion: "2025-01-01"}))
+
+	second := models.Consent{AccountNumber: "12345", TermsVersion: "2026-10-01", MarketingOptIn: true}
+	require.NoError(t, repo.Create(ctx, &second))
+	assert.False(t, second.CreatedAt.IsZero())
+
+	consent, err = repo.GetLatest(ctx, "12345")
+	require.NoError(t, err)
+	require.NotNil(t, consent)
+	assert.Equal(t, second, *consent)
+
+	consent.TermsVersion = "changed"
+	again, err := repo.GetLatest(ctx, "12345")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-01", again.TermsVersion, "returns a copy")
+
+	require.NoError(t, repo.Anonymize(ctx, "12345"))
+	consent, err = repo.GetLatest(ctx, "12345")
+	require.NoError(t, err)
+	assert.Empty(t, consent.IP)
+	assert.Equal(t, "2026-10-01", consent.TermsVersion, "what was agreed to stays")
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"sync"
+	"time"
+)
+
+type debugCaptureRepository struct {
+	mu       sync.RWMutex
+	captures []models.DebugCapture // in insertion order
+	nextID   int64
+}
+
+// NewDebugCaptureRepository creates an empty DebugCaptureRepository.
+func NewDebugCaptureRepository() pgsql.DebugCaptureRepository {
+	return &debugCaptureRepository{}
+}
+
+// Create inserts the debug capture, setting its ID.
+func (dr *debugCaptureRepository) Create(ctx context.Context, capture *models.DebugCapture) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	dr.nextID++
+	capture.ID = dr.nextID
+	dr.captures = append(dr.captures, *capture)
+	return nil
+}
+
+// List returns copies of up to limit debug captures, the latest first, without their request
+// and response.
+func (dr *debugCaptureRepository) List(ctx context.Context, limit int) ([]models.DebugCapture, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+
+	captures := make([]models.DebugCapture, 0, min(limit, len(dr.captures)))
+	for i := len(dr.captures) - 1; i >= 0 && len(captures) < limit; i-- {
+		capture := dr.captures[i]
+		capture.Request, capture.Response = "", ""
+		captures = append(captures, capture)
+	}
+	return captures, nil
+}
+
+// Get returns a copy of the debug capture of id, nil when there is none.
+func (dr *debugCaptureRepository) Get(ctx context.Context, id int64) (*models.DebugCapture, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+
+	for _, capture := range dr.captures {
+		if capture.ID == id {
+			return &capture, nil
+		}
+	}
+	return nil, nil
+}
+
+// DeleteExpired deletes the debug captures expired at now, returning how many it deleted.
+func (dr *debugCaptureRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	before := len(dr.captures)
+	dr.captures = slices.DeleteFunc(dr.captures, func(capture models.DebugCapture) bool {
+		return !capture.ExpiresAt.After(now)
+	})
+	return int64(before - len(dr.captures)), nil
+}
+
+// snapshot implements snapshotter.
+func (dr *debugCaptureRepository) snapshot() func() {
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+
+	captures, nextID := slices.Clone(dr.captures), dr.nextID
+	return func() {
+		dr.mu.Lock()
+		defer dr.mu.Unlock()
+		dr.captures, dr.nextID = captures, nextID
+	}
+}
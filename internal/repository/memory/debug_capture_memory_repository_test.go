@@ -0,0 +1,46 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugCapture(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewDebugCaptureRepository()
+	now := time.Now()
+
+	capture, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, capture)
+
+	expired := &models.DebugCapture{Path: "/api/v1/users", Request: "{}", Response: "{}", ExpiresAt: now.Add(-time.Minute)}
+	kept := &models.DebugCapture{Path: "/api/v1/users/profile", Request: "{}", Response: "{}", ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.Create(ctx, expired))
+	require.NoError(t, repo.Create(ctx, kept))
+	assert.Equal(t, int64(2), kept.ID)
+
+	captures, err := repo.List(ctx, 50)
+	require.NoError(t, err)
+	if assert.Len(t, captures, 2) {
+		assert.Equal(t, kept.ID, captures[0].ID, "the latest first")
+		assert.Empty(t, captures[0].Request, "without the request")
+	}
+
+	deleted, err := repo.DeleteExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	capture, err = repo.Get(ctx, kept.ID)
+	require.NoError(t, err)
+	if assert.NotNil(t, capture) {
+		assert.Equal(t, "{}", capture.Request)
+	}
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"sync"
+	"time"
+)
+
+type emailChangeRepository struct {
+	mu      sync.RWMutex
+	changes []models.EmailChange // in insertion order
+	nextID  int64
+}
+
+// NewEmailChangeRepository creates an empty EmailChangeRepository. Unlike the email_changes
+// table, it doesn't check that the account exists.
+func NewEmailChangeRepository() pgsql.EmailChangeRepository {
+	return &emailChangeRepository{}
+}
+
+// Create inserts the change, setting its ID and zero timestamp like gorm does.
+func (er *emailChangeRepository) Create(ctx context.Context, change *models.EmailChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	er.nextID++
+	change.ID = er.nextID
+
+	er.changes = append(er.changes, cloneEmailChange(*change))
+	return nil
+}
+
+// GetByID returns a copy of the change, nil when there is none.
+func (er *emailChangeRepository) GetByID(ctx context.Context, id int64) (*models.EmailChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+
+	for _, change := range er.changes {
+		if change.ID == id {
+			found := cloneEmailChange(change)
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+// CancelPending cancels the changes of the account that are neither completed nor cancelled.
+func (er *emailChangeRepository) CancelPending(ctx context.Context, accountNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	now := time.Now()
+	for i := range er.changes {
+		change := &er.changes[i]
+		if change.AccountNumber == accountNumber && change.CompletedAt == nil && change.CancelledAt == nil {
+			change.CancelledAt = &now
+		}
+	}
+	return nil
+}
+
+// Update stores the confirmation and completion times of the change, false when it is no
+// longer pending, like QueryUpdateEmailChange.
+func (er *emailChangeRepository) Update(ctx context.Context, change *models.EmailChange) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	updated := cloneEmailChange(*change)
+	for i := range er.changes {
+		stored := &er.changes[i]
+		if stored.ID != change.ID || stored.CompletedAt != nil || stored.CancelledAt != nil {
+			continue
+		}
+		if stored.OldConfirmedAt == nil {
+			stored.OldConfirmedAt = updated.OldConfirmedAt
+		}
+		if stored.NewConfirmedAt == nil {
+			stored.NewConfirmedAt = updated.NewConfirmedAt
+		}
+		stored.CompletedAt = updated.CompletedAt
+		return true, nil
+	}
+	return false, nil
+}
+
+// DeleteByAccountNumber deletes every change of the account. IDs are not reused.
+func (er *emailChangeRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	er.changes = slices.DeleteFunc(er.changes, func(change models.EmailChange) bool {
+		return change.AccountNumber == accountNumber
+	})
+	return nil
+}
+
+// cloneEmailChange copies the change, so callers never share the pointers of the stored one
+func cloneEmailChange(change models.EmailChange) models.EmailChange {
+	for _, at := range []**time.Time{&change.OldConfirmedAt, &change.NewConfirmedAt, &change.CompletedAt, &change.CancelledAt} {
+		if *at != nil {
+			copied := **at
+			*at = &copied
+		}
+	}
+	if change.OldEmail != nil {
+		email := *change.OldEmail
+		change.OldEmail = &email
+	}
+	return change
+}
+
+// snapshot implements snapshotter.
+func (er *emailChangeRepository) snapshot() func() {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+
+	changes, nextID := slices.Clone(er.changes), er.nextID
+	return func() {
+		er.mu.Lock()
+		defer er.mu.Unlock()
+		er.changes, er.nextID = changes, nextID
+	}
+}
@@ -0,0 +1,84 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailChange(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewEmailChangeRepository()
+
+	change, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, change, "no change yet")
+
+	first := models.EmailChange{AccountNumber: "12345", OldEmail: strPtr("john@example.com"), NewEmail: "johnny@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.Create(ctx, &first))
+	assert.Equal(t, int64(1), first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	now := time.Now()
+	first.OldConfirmedAt = &now
+	updated, err := repo.Update(ctx, &first)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	change, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, change)
+	assert.Equal(t, first, *change)
+
+	// A concurrent confirmation of the other side keeps this one
+	stale := models.EmailChange{ID: first.ID, NewConfirmedAt: &now}
+	updated, err = repo.Update(ctx, &stale)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	change, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.NotNil(t, change.OldConfirmedAt)
+	assert.NotNil(t, change.NewConfirmedAt)
+
+	completed := *change
+	completed.CompletedAt = &now
+	updated, err = repo.Update(ctx, &completed)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = repo.Update(ctx, &completed)
+	require.NoError(t, err)
+	assert.False(t, updated, "no longer pending")
+	first = completed
+
+	*change.OldEmail = "changed@example.com"
+	again, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", *again.OldEmail, "returns a copy")
+
+	second := models.EmailChange{AccountNumber: "12345", NewEmail: "j@example.com"}
+	require.NoError(t, repo.Create(ctx, &second))
+	other := models.EmailChange{AccountNumber: "67890", NewEmail: "jane@example.com"}
+	require.NoError(t, repo.Create(ctx, &other))
+
+	require.NoError(t, repo.CancelPending(ctx, "12345"))
+	change, err = repo.GetByID(ctx, second.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, change.CancelledAt)
+	change, err = repo.GetByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Nil(t, change.CancelledAt, "other accounts are untouched")
+
+	require.NoError(t, repo.DeleteByAccountNumber(ctx, "12345"))
+	change, err = repo.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Nil(t, change)
+
+	third := models.EmailChange{AccountNumber: "12345", NewEmail: "john@example.com"}
+	require.NoError(t, repo.Create(ctx, &third))
+	assert.Equal(t, int64(4), third.ID, "IDs are not reused")
+}
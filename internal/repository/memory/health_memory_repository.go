@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+)
+
+type healthRepository struct{}
+
+// NewHealthRepository creates a HealthRepository that is always healthy.
+func NewHealthRepository() pgsql.HealthRepository {
+	return healthRepository{}
+}
+
+func (healthRepository) Check(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Stats reports an empty pool without replicas.
+func (healthRepository) Stats(ctx context.Context) (*models.PostgreSQLStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &models.PostgreSQLStats{}, nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type loginEventRepository struct {
+	mu     sync.RWMutex
+	events []models.LoginEvent // in insertion order
+	nextID int64
+}
+
+// NewLoginEventRepository creates an empty LoginEventRepository. Unlike the login_events
+// table, it doesn't check that the account exists.
+func NewLoginEventRepository() pgsql.LoginEventRepository {
+	return &loginEventRepository{}
+}
+
+// Create inserts the event, setting its ID and zero timestamp like gorm does.
+func (lr *loginEventRepository) Create(ctx context.Context, event *models.LoginEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	lr.nextID++
+	event.ID = lr.nextID
+
+	lr.events = append(lr.events, *event)
+	return nil
+}
+
+// GetHistory compares the event to the earlier logins of its account, like QueryGetLoginHistory.
+func (lr *loginEventRepository) GetHistory(ctx context.Context, event *models.LoginEvent) (*models.LoginHistory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	var history models.LoginHistory
+	for _, earlier := range lr.events {
+		if earlier.AccountNumber != event.AccountNumber {
+			continue
+		}
+		history.Logins++
+		history.CountrySeen = history.CountrySeen || earlier.Country == event.Country
+		history.DeviceSeen = history.DeviceSeen ||
+			(earlier.Device == event.Device && earlier.OS == event.OS && earlier.Browser == event.Browser)
+	}
+	return &history, nil
+}
+
+// ListByAccountNumber returns the most recent logins of the account, newest first.
+func (lr *loginEventRepository) ListByAccountNumber(ctx context.Context, accountNumber string, limit int) ([]models.LoginEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	events := []models.LoginEvent{}
+	for _, event := range lr.events {
+		if event.AccountNumber == accountNumber {
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID > events[j].ID
+	})
+
+	return events[:min(max(limit, 0), len(events))], nil
+}
+
+// DeleteByAccountNumber deletes every login of the account. IDs are not reused.
+func (lr *loginEventRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	lr.events = slices.DeleteFunc(lr.events, func(event models.LoginEvent) bool {
+		return event.AccountNumber == accountNumber
+	})
+	return nil
+}
+
+// snapshot implements snapshotter.
+func (lr *loginEventRepository) snapshot() func() {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	events, nextID := slices.Clone(lr.events), lr.nextID
+	return func() {
+		lr.mu.Lock()
+		defer lr.mu.Unlock()
+		lr.events, lr.nextID = events, nextID
+	}
+}
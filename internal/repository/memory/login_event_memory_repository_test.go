@@ -0,0 +1,66 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewLoginEventRepository()
+
+	desktop := models.LoginEvent{AccountNumber: "12345", Country: "ID", Device: "desktop", OS: "Windows", Browser: "Chrome"}
+	history, err := repo.GetHistory(ctx, &desktop)
+	require.NoError(t, err)
+	assert.Equal(t, &models.LoginHistory{}, history, "no earlier logins")
+
+	first := desktop
+	first.CreatedAt = time.Now().Add(-time.Hour)
+	require.NoError(t, repo.Create(ctx, &first))
+	assert.Equal(t, int64(1), first.ID)
+	require.NoError(t, repo.Create(ctx, &models.LoginEvent{AccountNumber: "67890", Country: "GB"}))
+
+	history, err = repo.GetHistory(ctx, &models.LoginEvent{AccountNumber: "12345", Country: "GB", Device: "desktop", OS: "Windows", Browser: "Chrome"})
+	require.NoError(t, err)
+	assert.Equal(t, &models.LoginHistory{Logins: 1, DeviceSeen: true}, history, "other accounts are ignored")
+
+	history, err = repo.GetHistory(ctx, &models.LoginEvent{AccountNumber: "12345", Country: "ID", Device: "desktop", OS: "Windows", Browser: "Firefox"})
+	require.NoError(t, err)
+	assert.Equal(t, &models.LoginHistory{Logins: 1, CountrySeen: true}, history, "a new browser is a new device")
+
+	second := models.LoginEvent{AccountNumber: "12345", Country: "GB", Suspicious: true, Reasons: "new_country"}
+	require.NoError(t, repo.Create(ctx, &second))
+	assert.False(t, second.CreatedAt.IsZero())
+
+	events, err := repo.ListByAccountNumber(ctx, "12345", 20)
+	require.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, second.ID, events[0].ID, "newest first")
+		assert.Equal(t, first.ID, events[1].ID)
+	}
+
+	events, err = repo.ListByAccountNumber(ctx, "12345", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = repo.ListByAccountNumber(ctx, "unknown", 20)
+	require.NoError(t, err)
+	assert.NotNil(t, events)
+	assert.Empty(t, events)
+
+	require.NoError(t, repo.DeleteByAccountNumber(ctx, "12345"))
+	events, err = repo.ListByAccountNumber(ctx, "12345", 20)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	third := models.LoginEvent{AccountNumber: "12345"}
+	require.NoError(t, repo.Create(ctx, &third))
+	assert.Greater(t, third.ID, second.ID, "IDs are not reused")
+}
@@ -0,0 +1,38 @@
+// Package memory implements the repositories in memory, for tests and examples that run
+// without a database. Each repository has the same interface and observable behaviour as
+// its pgsql counterpart: the same constraints, the same columns returned by each query, and
+// (nil, nil) for a missing row. A new pgsql repository gets its memory twin here.
+//
+//	deps := &service.Dependencies{Repository: repository.Repository{Postgre: memory.New()}}
+package memory
+
+import (
+	"go-echo-boilerplate/internal/pkg/di"
+	"go-echo-boilerplate/internal/repository/pgsql"
+)
+
+// New creates empty in-memory repositories in place of pgsql.New.
+func New() *pgsql.PostgreRepository {
+	repo := &pgsql.PostgreRepository{
+		Health:      NewHealthRepository(),
+		User:        NewUserRepository(),
+		LoginEvent:  NewLoginEventRepository(),
+		Consent:     NewConsentRepository(),
+		EmailChange: NewEmailChangeRepository(),
+		PhoneChange: NewPhoneChangeRepository(),
+
+		Notification:  NewNotificationRepository(),
+		APIUsage:      NewAPIUsageRepository(),
+		QuotaOverride: NewQuotaOverrideRepository(),
+
+		DebugCapture: NewDebugCaptureRepository(),
+	}
+	repo.Transaction = NewTransactionRepository(repo)
+	return repo
+}
+
+// Provide replaces the pgsql repositories of the container with empty in-memory ones, so
+// the services resolve without a *database.Database.
+func Provide(c *di.Container) {
+	di.Provide(c, New)
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"maps"
+	"slices"
+	"sync"
+)
+
+type notificationRepository struct {
+	mu          sync.RWMutex
+	preferences map[string]models.NotificationPreferences // by account number
+	letters     []models.NotificationDeadLetter           // in insertion order
+	nextID      int64
+}
+
+// NewNotificationRepository creates an empty NotificationRepository. Unlike the
+// notification_preferences table, it doesn't check that the account exists.
+func NewNotificationRepository() pgsql.NotificationRepository {
+	return &notificationRepository{preferences: map[string]models.NotificationPreferences{}}
+}
+
+// GetPreferences returns a copy of the preferences of the account, nil when it never changed them.
+func (nr *notificationRepository) GetPreferences(ctx context.Context, accountNumber string) (*models.NotificationPreferences, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nr.mu.RLock()
+	defer nr.mu.RUnlock()
+
+	preferences, ok := nr.preferences[accountNumber]
+	if !ok {
+		return nil, nil
+	}
+	return &preferences, nil
+}
+
+// SavePreferences creates or replaces the preferences of the account.
+func (nr *notificationRepository) SavePreferences(ctx context.Context, preferences *models.NotificationPreferences) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	nr.preferences[preferences.AccountNumber] = *preferences
+	return nil
+}
+
+// CreateDeadLetter inserts the dead letter, setting its ID.
+func (nr *notificationRepository) CreateDeadLetter(ctx context.Context, letter *models.NotificationDeadLetter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	nr.nextID++
+	letter.ID = nr.nextID
+	nr.letters = append(nr.letters, *letter)
+	return nil
+}
+
+// ListDeadLetters returns copies of up to limit dead letters, the oldest first.
+func (nr *notificationRepository) ListDeadLetters(ctx context.Context, limit int) ([]models.NotificationDeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nr.mu.RLock()
+	defer nr.mu.RUnlock()
+
+	letters := nr.letters[:min(limit, len(nr.letters))]
+	return append([]models.NotificationDeadLetter(nil), letters...), nil
+}
+
+// GetDeadLetter returns a copy of the dead letter of id, nil when there is none.
+func (nr *notificationRepository) GetDeadLetter(ctx context.Context, id int64) (*models.NotificationDeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nr.mu.RLock()
+	defer nr.mu.RUnlock()
+
+	for _, letter := range nr.letters {
+		if letter.ID == id {
+			return &letter, nil
+		}
+	}
+	return nil, nil
+}
+
+// DeleteDeadLetter deletes the dead letter of id.
+func (nr *notificationRepository) DeleteDeadLetter(ctx context.Context, id int64) error {
+	return nr.deleteLetters(ctx, func(letter models.NotificationDeadLetter) bool { return letter.ID == id })
+}
+
+// DeleteByAccountNumber deletes the preferences and the dead letters of the account.
+func (nr *notificationRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	if err := nr.deleteLetters(ctx, func(letter models.NotificationDeadLetter) bool {
+		return letter.AccountNumber == accountNumber
+	}); err != nil {
+		return err
+	}
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	delete(nr.preferences, accountNumber)
+	return nil
+}
+
+func (nr *notificationRepository) deleteLetters(ctx context.Context, match func(models.NotificationDeadLetter) bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	kept := nr.letters[:0]
+	for _, letter := range nr.letters {
+		if !match(letter) {
+			kept = append(kept, letter)
+		}
+	}
+	nr.letters = kept
+	return nil
+}
+
+// snapshot implements snapshotter.
+func (nr *notificationRepository) snapshot() func() {
+	nr.mu.RLock()
+	defer nr.mu.RUnlock()
+
+	preferences, letters, nextID := maps.Clone(nr.preferences), slices.Clone(nr.letters), nr.nextID
+	return func() {
+		nr.mu.Lock()
+		defer nr.mu.Unlock()
+		nr.preferences, nr.letters, nr.nextID = preferences, letters, nextID
+	}
+}
@@ -0,0 +1,52 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotification(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewNotificationRepository()
+
+	preferences, err := repo.GetPreferences(ctx, "12345")
+	require.NoError(t, err)
+	assert.Nil(t, preferences, "never changed")
+
+	require.NoError(t, repo.SavePreferences(ctx, &models.NotificationPreferences{AccountNumber: "12345", Email: true}))
+	require.NoError(t, repo.SavePreferences(ctx, &models.NotificationPreferences{AccountNumber: "12345", SMS: true}))
+	preferences, err = repo.GetPreferences(ctx, "12345")
+	require.NoError(t, err)
+	assert.Equal(t, models.NotificationPreferences{AccountNumber: "12345", SMS: true}, *preferences, "replaced")
+
+	first := models.NotificationDeadLetter{AccountNumber: "12345", Event: "login.suspicious", Channel: "email"}
+	require.NoError(t, repo.CreateDeadLetter(ctx, &first))
+	assert.Equal(t, int64(1), first.ID)
+	require.NoError(t, repo.CreateDeadLetter(ctx, &models.NotificationDeadLetter{Event: "system"}))
+
+	letters, err := repo.ListDeadLetters(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.NotificationDeadLetter{first}, letters)
+
+	letter, err := repo.GetDeadLetter(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "system", letter.Event)
+	require.NoError(t, repo.DeleteDeadLetter(ctx, 2))
+	letter, err = repo.GetDeadLetter(ctx, 2)
+	require.NoError(t, err)
+	assert.Nil(t, letter)
+
+	require.NoError(t, repo.DeleteByAccountNumber(ctx, "12345"))
+	preferences, err = repo.GetPreferences(ctx, "12345")
+	require.NoError(t, err)
+	assert.Nil(t, preferences)
+	letters, err = repo.ListDeadLetters(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"sync"
+	"time"
+)
+
+type phoneChangeRepository struct {
+	mu      sync.RWMutex
+	changes []models.PhoneChange // in insertion order
+	nextID  int64
+}
+
+// NewPhoneChangeRepository creates an empty PhoneChangeRepository. Unlike the phone_changes
+// table, it doesn't check that the account exists.
+func NewPhoneChangeRepository() pgsql.PhoneChangeRepository {
+	return &phoneChangeRepository{}
+}
+
+// Create inserts the change, setting its ID and zero timestamp like gorm does.
+func (pr *phoneChangeRepository) Create(ctx context.Context, change *models.PhoneChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	pr.nextID++
+	change.ID = pr.nextID
+
+	pr.changes = append(pr.changes, clonePhoneChange(*change))
+	return nil
+}
+
+// GetPending returns a copy of the latest pending change of the account, nil when there is none.
+func (pr *phoneChangeRepository) GetPending(ctx context.Context, accountNumber string) (*models.PhoneChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	for i := len(pr.changes) - 1; i >= 0; i-- {
+		change := pr.changes[i]
+		if change.AccountNumber == accountNumber && change.CompletedAt == nil && change.CancelledAt == nil {
+			found := clonePhoneChange(change)
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+// CancelPending cancels the changes of the account that are neither completed nor cancelled.
+func (pr *phoneChangeRepository) CancelPending(ctx context.Context, accountNumber string) error {
+	now := time.Now()
+	return pr.update(ctx, func(change *models.PhoneChange) {
+		if change.AccountNumber == accountNumber && change.CompletedAt == nil && change.CancelledAt == nil {
+			change.CancelledAt = &now
+		}
+	})
+}
+
+// IncrementAttempts counts a code entered for the pending change and returns the count, 0
+// when it already has max attempts or is no longer pending.
+func (pr *phoneChangeRepository) IncrementAttempts(ctx context.Context, id int64, max int) (int, error) {
+	attempts := 0
+	err := pr.update(ctx, func(change *models.PhoneChange) {
+		if change.ID == id && change.Attempts < max && change.CompletedAt == nil && change.CancelledAt == nil {
+			change.Attempts++
+			attempts = change.Attempts
+		}
+	})
+	return attempts, err
+}
+
+// Complete marks the change as completed at the given time, false when it is no longer pending.
+func (pr *phoneChangeRepository) Complete(ctx context.Context, id int64, at time.Time) (bool, error) {
+	completed := false
+	err := pr.update(ctx, func(change *models.PhoneChange) {
+		if change.ID == id && change.CompletedAt == nil && change.CancelledAt == nil {
+			change.CompletedAt = &at
+			completed = true
+		}
+	})
+	return completed, err
+}
+
+// DeleteByAccountNumber deletes every change of the account. IDs are not reused.
+func (pr *phoneChangeRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.changes = slices.DeleteFunc(pr.changes, func(change models.PhoneChange) bool {
+		return change.AccountNumber == accountNumber
+	})
+	return nil
+}
+
+// update applies change to every stored change
+func (pr *phoneChangeRepository) update(ctx context.Context, change func(change *models.PhoneChange)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	for i := range pr.changes {
+		change(&pr.changes[i])
+	}
+	return nil
+}
+
+// clonePhoneChange copies the change, so callers never share the pointers of the stored one
+func clonePhoneChange(change models.PhoneChange) models.PhoneChange {
+	for _, at := range []**time.Time{&change.CompletedAt, &change.CancelledAt} {
+		if *at != nil {
+			copied := **at
+			*at = &copied
+		}
+	}
+	return change
+}
+
+// snapshot implements snapshotter.
+func (pr *phoneChangeRepository) snapshot() func() {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	changes, nextID := slices.Clone(pr.changes), pr.nextID
+	return func() {
+		pr.mu.Lock()
+		defer pr.mu.Unlock()
+		pr.changes, pr.nextID = changes, nextID
+	}
+}
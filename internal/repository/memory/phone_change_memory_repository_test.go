@@ -0,0 +1,102 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneChange(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPhoneChangeRepository()
+
+	change, err := repo.GetPending(ctx, "12345")
+	require.NoError(t, err)
+	assert.Nil(t, change, "no change yet")
+
+	first := models.PhoneChange{AccountNumber: "12345", PhoneNumber: "+6281234567890", CodeHash: "first"}
+	require.NoError(t, repo.Create(ctx, &first))
+	second := models.PhoneChange{AccountNumber: "12345", PhoneNumber: "+6281234567891", CodeHash: "second"}
+	require.NoError(t, repo.Create(ctx, &second))
+	assert.False(t, second.CreatedAt.IsZero())
+
+	change, err = repo.GetPending(ctx, "12345")
+	require.NoError(t, err)
+	require.NotNil(t, change)
+	assert.Equal(t, "second", change.CodeHash, "the latest first")
+
+	attempts, err := repo.IncrementAttempts(ctx, second.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+	attempts, err = repo.IncrementAttempts(ctx, second.ID, 1)
+	require.NoError(t, err)
+	assert.Zero(t, attempts, "past the limit")
+	now := time.Now()
+	completed, err := repo.Complete(ctx, second.ID, now)
+	require.NoError(t, err)
+	assert.True(t, completed)
+	completed, err = repo.Complete(ctx, second.ID, now)
+	require.NoError(t, err)
+	assert.False(t, completed, "already completed")
+	change, err = repo.GetPending(ctx, "12345")
+	require.NoError(t, err)
+	assert.Equal(t, "first", change.CodeHash, "completed changes are not pending")
+
+	require.NoError(t, repo.CancelPending(ctx, "12345"))
+	change, err = repo.GetPending(ctx, "12345")
+	require.NoError(t, err)
+	assert.Nil(t, change)
+	completed, err = repo.Complete(ctx, first.ID, now)
+	require.NoError(t, err)
+	assert.False(t, completed, "cancelled")
+
+	require.NoError(t, repo.DeleteByAccountNumber(ctx, "12345"))
+	third := models.PhoneChange{AccountNumber: "12345"}
+	require.NoError(t, repo.Create(ctx, &third))
+	assert.Equal(t, int64(3), third.ID, "IDs are not reused")
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+
+	failed := errors.New("failed")
+	err := repo.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+		assert.Same(t, repo, r)
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	err = repo.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+		return r.User.Create(ctx, &models.User{AccountNumber: "12345", Email: strPtr("john@example.com")})
+	})
+	require.NoError(t, err)
+
+	err = repo.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+		require.NoError(t, r.User.Create(ctx, &models.User{AccountNumber: "67890", Email: strPtr("jane@example.com")}))
+		require.NoError(t, r.PhoneChange.Create(ctx, &models.PhoneChange{AccountNumber: "12345"}))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	exists, err := repo.User.CheckByAccountNumber(ctx, "12345")
+	require.NoError(t, err)
+	assert.True(t, exists, "committed writes are kept")
+	exists, err = repo.User.CheckByAccountNumber(ctx, "67890")
+	require.NoError(t, err)
+	assert.False(t, exists, "the writes of a failed Atomic are rolled back")
+	pending, err := repo.PhoneChange.GetPending(ctx, "12345")
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+
+	_, err = repo.Transaction.Begin(ctx)
+	assert.Error(t, err, "only Atomic is supported")
+}
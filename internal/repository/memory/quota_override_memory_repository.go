@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"maps"
+	"sort"
+	"sync"
+)
+
+type quotaOverrideRepository struct {
+	mu        sync.RWMutex
+	overrides map[string]models.QuotaOverride // by client
+}
+
+// NewQuotaOverrideRepository creates an empty QuotaOverrideRepository.
+func NewQuotaOverrideRepository() pgsql.QuotaOverrideRepository {
+	return &quotaOverrideRepository{overrides: map[string]models.QuotaOverride{}}
+}
+
+// List returns copies of every quota override, by client.
+func (qr *quotaOverrideRepository) List(ctx context.Context) ([]models.QuotaOverride, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+
+	overrides := make([]models.QuotaOverride, 0, len(qr.overrides))
+	for _, override := range qr.overrides {
+		overrides = append(overrides, override)
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Client < overrides[j].Client })
+	return overrides, nil
+}
+
+// Get returns a copy of the quota override of the client, nil when it has none.
+func (qr *quotaOverrideRepository) Get(ctx context.Context, client string) (*models.QuotaOverride, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+
+	override, ok := qr.overrides[client]
+	if !ok {
+		return nil, nil
+	}
+	return &override, nil
+}
+
+// Save creates or replaces the quota override of the client.
+func (qr *quotaOverrideRepository) Save(ctx context.Context, override *models.QuotaOverride) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	qr.overrides[override.Client] = *override
+	return nil
+}
+
+// Delete deletes the quota override of the client, reporting whether it had one.
+func (qr *quotaOverrideRepository) Delete(ctx context.Context, client string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	_, ok := qr.overrides[client]
+	delete(qr.overrides, client)
+	return ok, nil
+}
+
+// snapshot implements snapshotter.
+func (qr *quotaOverrideRepository) snapshot() func() {
+	qr.mu.RLock()
+	defer qr.mu.RUnlock()
+
+	overrides := maps.Clone(qr.overrides)
+	return func() {
+		qr.mu.Lock()
+		defer qr.mu.Unlock()
+		qr.overrides = overrides
+	}
+}
@@ -0,0 +1,40 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaOverride(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewQuotaOverrideRepository()
+
+	override, err := repo.Get(ctx, "partner-a")
+	require.NoError(t, err)
+	assert.Nil(t, override)
+
+	daily := int64(20000)
+	require.NoError(t, repo.Save(ctx, &models.QuotaOverride{Client: "partner-b", Reason: "SUP-1235"}))
+	require.NoError(t, repo.Save(ctx, &models.QuotaOverride{Client: "partner-a", Reason: "SUP-1234"}))
+	require.NoError(t, repo.Save(ctx, &models.QuotaOverride{Client: "partner-a", DailyQuota: &daily, Reason: "SUP-1236"}))
+
+	overrides, err := repo.List(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, overrides, 2) {
+		assert.Equal(t, "partner-a", overrides[0].Client, "by client")
+		assert.Equal(t, "SUP-1236", overrides[0].Reason, "replaced")
+	}
+
+	deleted, err := repo.Delete(ctx, "partner-a")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = repo.Delete(ctx, "partner-a")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// errManualTransaction is returned by the Begin, Commit, and Rollback of the memory
+// TransactionRepository, which has no database transaction to hand out
+var errManualTransaction = errors.New("memory: manual transactions are not supported, use Atomic")
+
+// snapshotter is implemented by the memory repositories holding rows, so Atomic can roll
+// them back
+type snapshotter interface {
+	// snapshot copies the rows, the returned function puts the copy back
+	snapshot() (restore func())
+}
+
+type transactionRepository struct {
+	mu   sync.Mutex
+	repo *pgsql.PostgreRepository
+}
+
+// NewTransactionRepository creates a TransactionRepository running Atomic functions on repo.
+// Atomic functions run one at a time, and the rows of the memory repositories are restored
+// when they fail. Unlike Postgres, this also undoes the writes made outside Atomic while it
+// runs, and the IDs it handed out are reused.
+func NewTransactionRepository(repo *pgsql.PostgreRepository) pgsql.TransactionRepository {
+	return &transactionRepository{repo: repo}
+}
+
+func (tr *transactionRepository) Begin(ctx context.Context) (*gorm.DB, error) {
+	return nil, errManualTransaction
+}
+
+func (tr *transactionRepository) Commit(ctx context.Context, tx *gorm.DB) error {
+	return errManualTransaction
+}
+
+func (tr *transactionRepository) Rollback(ctx context.Context, tx *gorm.DB) error {
+	return errManualTransaction
+}
+
+// Atomic runs fc with the repositories, serialized with the other Atomic calls. The
+// repositories are rolled back when fc returns an error or panics.
+func (tr *transactionRepository) Atomic(ctx context.Context, fc func(ctx context.Context, r *pgsql.PostgreRepository) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	restore := tr.snapshotRepositories()
+	committed := false
+	defer func() {
+		if !committed {
+			restore()
+		}
+	}()
+
+	if err := fc(ctx, tr.repo); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// snapshotRepositories snapshots every repository implementing snapshotter, the returned
+// function restores them all
+func (tr *transactionRepository) snapshotRepositories() func() {
+	var restores []func()
+	fields := reflect.ValueOf(tr.repo).Elem()
+	for i := range fields.NumField() {
+		if s, ok := fields.Field(i).Interface().(snapshotter); ok {
+			restores = append(restores, s.snapshot())
+		}
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
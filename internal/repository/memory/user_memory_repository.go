@@ -0,0 +1,589 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/actor"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	mu     sync.RWMutex
+	users  []models.User // in insertion order, IDs are index+1
+	nextID int
+}
+
+// NewUserRepository creates a UserRepository holding the given users, inserted with Create.
+// It panics when they violate a constraint of the users table.
+func NewUserRepository(users ...models.User) pgsql.UserRepository {
+	ur := &userRepository{nextID: 1}
+	for i := range users {
+		if err := ur.Create(context.Background(), &users[i]); err != nil {
+			panic(fmt.Sprintf("memory: seeding user %d: %v", i, err))
+		}
+	}
+	return ur
+}
+
+// Create inserts the user, setting its ID, zero timestamps, zero version, and unset audit
+// columns (from the actor of ctx) like gorm does. The unique and
+// check constraints of the users table are enforced, violations wrap gorm.ErrDuplicatedKey
+// and gorm.ErrCheckConstraintViolated.
+func (ur *userRepository) Create(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if value(user.Email) == "" && value(user.PhoneNumber) == "" && !user.DeletedAt.Valid {
+		return fmt.Errorf("%w: check_email_or_phone", gorm.ErrCheckConstraintViolated)
+	}
+	for _, existing := range ur.users {
+		switch {
+		case existing.AccountNumber == user.AccountNumber:
+			return fmt.Errorf("%w: users_account_number_key", gorm.ErrDuplicatedKey)
+		case value(user.Email) != "" && value(existing.Email) == value(user.Email):
+			return fmt.Errorf("%w: idx_users_email_unique", gorm.ErrDuplicatedKey)
+		case value(user.PhoneNumber) != "" && value(existing.PhoneNumber) == value(user.PhoneNumber):
+			return fmt.Errorf("%w: idx_users_phone_number_unique", gorm.ErrDuplicatedKey)
+		case user.Username != nil && existing.Username != nil && *existing.Username == *user.Username:
+			return fmt.Errorf("%w: idx_users_username_unique", gorm.ErrDuplicatedKey)
+		}
+	}
+
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+	if user.Version == 0 {
+		user.Version = 1
+	}
+	if id, ok := actor.FromContext(ctx); ok {
+		if user.CreatedBy == nil {
+			user.CreatedBy = &id
+		}
+		if user.UpdatedBy == nil {
+			user.UpdatedBy = &id
+		}
+	}
+	user.ID = ur.nextID
+	ur.nextID++
+
+	ur.users = append(ur.users, clone(*user))
+	return nil
+}
+
+// CheckByEmailOrPhoneNumber includes soft-deleted users, like the unique indexes.
+func (ur *userRepository) CheckByEmailOrPhoneNumber(ctx context.Context, email string, phoneNumber string) (bool, error) {
+	user, err := ur.find(ctx, matchEmailOrPhoneNumber(email, phoneNumber))
+	return user != nil, err
+}
+
+// CheckByAccountNumber includes soft-deleted users, their account numbers are never reused.
+func (ur *userRepository) CheckByAccountNumber(ctx context.Context, accountNumber string) (bool, error) {
+	user, err := ur.find(ctx, matchAccountNumber(accountNumber))
+	return user != nil, err
+}
+
+// CheckByUsername includes soft-deleted users, like the unique index.
+func (ur *userRepository) CheckByUsername(ctx context.Context, username string) (bool, error) {
+	user, err := ur.find(ctx, matchUsername(username))
+	return user != nil, err
+}
+
+// GetCredentialsByEmailOrPhoneNumber returns the columns of QueryGetCredentialsByEmailOrPhoneNumber.
+func (ur *userRepository) GetCredentialsByEmailOrPhoneNumber(ctx context.Context, email string, phoneNumber string) (*models.User, error) {
+	user, err := ur.find(ctx, matchEmailOrPhoneNumber(email, phoneNumber))
+	return credentials(user), err
+}
+
+// GetCredentialsByUsername returns the columns of QueryGetCredentialsByUsername.
+func (ur *userRepository) GetCredentialsByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := ur.find(ctx, matchUsername(username))
+	return credentials(user), err
+}
+
+// credentials keeps the columns of the credentials queries, nil for nil
+func credentials(user *models.User) *models.User {
+	if user == nil {
+		return nil
+	}
+
+	return &models.User{
+		ID:               user.ID,
+		AccountNumber:    user.AccountNumber,
+		Name:             user.Name,
+		Username:         user.Username,
+		Email:            user.Email,
+		PhoneNumber:      user.PhoneNumber,
+		PhoneCountryCode: user.PhoneCountryCode,
+		Password:         user.Password,
+
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}
+}
+
+// GetOneByAccountNumber returns the columns of QueryGetByAccountNumber.
+func (ur *userRepository) GetOneByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
+	user, err := ur.find(ctx, matchAccountNumber(accountNumber))
+	if user == nil {
+		return nil, err
+	}
+
+	return &models.User{
+		ID:               user.ID,
+		AccountNumber:    user.AccountNumber,
+		Name:             user.Name,
+		Username:         user.Username,
+		Email:            user.Email,
+		PhoneNumber:      user.PhoneNumber,
+		PhoneCountryCode: user.PhoneCountryCode,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		DeletedAt:        user.DeletedAt,
+		Version:          user.Version,
+
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}, nil
+}
+
+// List returns a page of the users matching the filter, with the columns of QueryListUsersColumns.
+// Names are compared case-insensitively but sorted byte-wise, where PostgreSQL would use the
+// collation of the database.
+func (ur *userRepository) List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var matches []models.User
+	for _, user := range ur.users {
+		if user.DeletedAt.Valid ||
+			(filter.Name != "" && !strings.Contains(strings.ToLower(user.Name), strings.ToLower(filter.Name))) ||
+			(filter.CreatedFrom != nil && user.CreatedAt.Before(*filter.CreatedFrom)) ||
+			(filter.CreatedTo != nil && !user.CreatedAt.Before(*filter.CreatedTo)) {
+			continue
+		}
+		matches = append(matches, models.User{
+			ID:            user.ID,
+			AccountNumber: user.AccountNumber,
+			Name:          user.Name,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		})
+	}
+
+	total := len(matches)
+	if total == 0 {
+		return []models.User{}, 0, nil
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if filter.SortDesc {
+			a, b = b, a
+		}
+		if filter.SortBy == "name" {
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		} else if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	start := min(max(filter.Offset, 0), total)
+	end := min(start+max(filter.Limit, 0), total)
+	return matches[start:end], total, nil
+}
+
+// Search matches like the pgsql repository without the trigram similarity: names, usernames,
+// emails, and phone numbers match when they contain the search, account numbers when they
+// equal it. Exact account numbers, usernames, and emails rank 1, prefixes 0.75, others 0.5.
+func (ur *userRepository) Search(ctx context.Context, filter models.UserSearchFilter) ([]models.UserSearchResult, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var matches []models.UserSearchResult
+	for _, user := range ur.users {
+		if user.DeletedAt.Valid {
+			continue
+		}
+		if rank := searchRank(&user, filter.Query); rank > 0 {
+			user = clone(user)
+			user.Password = ""
+			matches = append(matches, models.UserSearchResult{User: user, Rank: rank})
+		}
+	}
+
+	total := len(matches)
+	if total == 0 {
+		return []models.UserSearchResult{}, 0, nil
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	start := min(max(filter.Offset, 0), total)
+	end := min(start+max(filter.Limit, 0), total)
+	return matches[start:end], total, nil
+}
+
+// Iterate walks the users like the pgsql repository, a batch per read lock.
+func (ur *userRepository) Iterate(ctx context.Context, filter models.UserIterationFilter, batchSize int, fn func(user *models.User) error) error {
+	fetch := func(ctx context.Context, afterID, limit int) ([]models.User, error) {
+		ur.mu.RLock()
+		defer ur.mu.RUnlock()
+
+		// Users are stored in ID order
+		var users []models.User
+		for _, user := range ur.users {
+			if len(users) >= limit {
+				break
+			}
+			if user.ID <= afterID ||
+				(!filter.IncludeDeleted && user.DeletedAt.Valid) ||
+				(filter.Name != "" && !strings.Contains(strings.ToLower(user.Name), strings.ToLower(filter.Name))) ||
+				(filter.CreatedFrom != nil && user.CreatedAt.Before(*filter.CreatedFrom)) ||
+				(filter.CreatedTo != nil && !user.CreatedAt.Before(*filter.CreatedTo)) {
+				continue
+			}
+			user = clone(user)
+			user.Password = ""
+			users = append(users, user)
+		}
+		return users, nil
+	}
+	return pgsql.Iterate(ctx, batchSize, fetch, func(user *models.User) int { return user.ID }, fn)
+}
+
+// searchRank ranks how well the user matches query, 0 when it does not
+func searchRank(user *models.User, query string) float64 {
+	if query == "" {
+		return 0
+	}
+	if user.AccountNumber == query || value(user.Username) == query || strings.ToLower(value(user.Email)) == query {
+		return 1
+	}
+
+	rank := 0.0
+	for _, field := range []string{user.Name, value(user.Username), value(user.Email), value(user.PhoneNumber)} {
+		field = strings.ToLower(field)
+		switch {
+		case strings.HasPrefix(field, query):
+			rank = max(rank, 0.75)
+		case strings.Contains(field, query):
+			rank = max(rank, 0.5)
+		}
+	}
+	return rank
+}
+
+// UpdateProfile replaces the name of the user when it is still at version, recording the
+// actor of ctx, otherwise it returns a *pgsql.VersionConflictError like pgsql.UpdateVersioned.
+func (ur *userRepository) UpdateProfile(ctx context.Context, id, version int, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	for i := range ur.users {
+		if ur.users[i].ID == id && ur.users[i].Version == version {
+			ur.users[i].Name = name
+			ur.users[i].UpdatedAt = time.Now()
+			ur.users[i].Version++
+			if id, ok := actor.FromContext(ctx); ok {
+				ur.users[i].UpdatedBy = &id
+			}
+			return nil
+		}
+	}
+	return &pgsql.VersionConflictError{Table: "users", ID: id, Version: version}
+}
+
+// UpdatePassword replaces the stored password hash of the user, ignoring soft-deleted users.
+func (ur *userRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	for i := range ur.users {
+		if ur.users[i].ID == id && !ur.users[i].DeletedAt.Valid {
+			ur.users[i].Password = hashedPassword
+			touch(ctx, &ur.users[i])
+		}
+	}
+	return nil
+}
+
+// UpdateEmail replaces the email of the user, ignoring soft-deleted users. An email taken by
+// another user wraps gorm.ErrDuplicatedKey, like the unique index.
+func (ur *userRepository) UpdateEmail(ctx context.Context, id int, email string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	for _, existing := range ur.users {
+		if existing.ID != id && value(existing.Email) == email {
+			return fmt.Errorf("%w: idx_users_email_unique", gorm.ErrDuplicatedKey)
+		}
+	}
+	for i := range ur.users {
+		if ur.users[i].ID == id && !ur.users[i].DeletedAt.Valid {
+			ur.users[i].Email = &email
+			touch(ctx, &ur.users[i])
+		}
+	}
+	return nil
+}
+
+// UpdatePhoneNumber replaces the phone number of the user, ignoring soft-deleted users. A
+// number taken by another user wraps gorm.ErrDuplicatedKey, like the unique index.
+func (ur *userRepository) UpdatePhoneNumber(ctx context.Context, id int, phoneNumber, countryCode string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	for _, existing := range ur.users {
+		if existing.ID != id && value(existing.PhoneNumber) == phoneNumber {
+			return fmt.Errorf("%w: idx_users_phone_number_unique", gorm.ErrDuplicatedKey)
+		}
+	}
+	for i := range ur.users {
+		if ur.users[i].ID == id && !ur.users[i].DeletedAt.Valid {
+			ur.users[i].PhoneNumber = &phoneNumber
+			ur.users[i].PhoneCountryCode = countryCode
+			touch(ctx, &ur.users[i])
+		}
+	}
+	return nil
+}
+
+// ScheduleDeletion sets when the user is anonymized, ignoring soft-deleted users.
+func (ur *userRepository) ScheduleDeletion(ctx context.Context, id int, at time.Time) error {
+	return ur.update(ctx, id, func(user *models.User) bool {
+		if user.DeletedAt.Valid {
+			return false
+		}
+		user.DeletionScheduledAt = &at
+		return true
+	})
+}
+
+// CancelDeletion clears the scheduled deletion of the user, unless it was carried out.
+func (ur *userRepository) CancelDeletion(ctx context.Context, id int) error {
+	return ur.update(ctx, id, func(user *models.User) bool {
+		if user.DeletedAt.Valid {
+			return false
+		}
+		user.DeletionScheduledAt = nil
+		return true
+	})
+}
+
+// ListDueForDeletion returns the columns of QueryListDueForDeletion, the longest due first.
+func (ur *userRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range ur.users {
+		if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+			continue
+		}
+		scheduledAt := *user.DeletionScheduledAt
+		users = append(users, models.User{
+			ID:                  user.ID,
+			AccountNumber:       user.AccountNumber,
+			DeletedAt:           user.DeletedAt,
+			DeletionScheduledAt: &scheduledAt,
+		})
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].DeletionScheduledAt.Before(*users[j].DeletionScheduledAt)
+	})
+	return users[:min(max(limit, 0), len(users))], nil
+}
+
+// Anonymize removes the personal data of the user and soft-deletes it, like QueryAnonymizeUser.
+func (ur *userRepository) Anonymize(ctx context.Context, id int, now time.Time) (bool, error) {
+	anonymized := false
+	err := ur.update(ctx, id, func(user *models.User) bool {
+		if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+			return false
+		}
+		user.Name, user.Username, user.Email, user.PhoneNumber, user.PhoneCountryCode, user.Password = "", nil, nil, nil, "", ""
+		if !user.DeletedAt.Valid {
+			user.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+		anonymized = true
+		return true
+	})
+	return anonymized, err
+}
+
+// CompleteDeletion clears the schedule of an anonymized user.
+func (ur *userRepository) CompleteDeletion(ctx context.Context, id int) error {
+	return ur.update(ctx, id, func(user *models.User) bool {
+		if !user.DeletedAt.Valid {
+			return false
+		}
+		user.DeletionScheduledAt = nil
+		return true
+	})
+}
+
+// update applies change to the user with the id; change reports whether it changed anything,
+// which touches the user
+func (ur *userRepository) update(ctx context.Context, id int, change func(user *models.User) bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	for i := range ur.users {
+		if ur.users[i].ID == id && change(&ur.users[i]) {
+			touch(ctx, &ur.users[i])
+		}
+	}
+	return nil
+}
+
+// touch records an update of the user like the UPDATE queries: it touches UpdatedAt, bumps
+// Version, and sets UpdatedBy to the actor of ctx, if any
+func touch(ctx context.Context, user *models.User) {
+	user.UpdatedAt = time.Now()
+	user.Version++
+	if id, ok := actor.FromContext(ctx); ok {
+		user.UpdatedBy = &id
+	}
+}
+
+// find returns a copy of the first user matching, nil when none does
+func (ur *userRepository) find(ctx context.Context, match func(user *models.User) bool) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	for i := range ur.users {
+		if match(&ur.users[i]) {
+			user := clone(ur.users[i])
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func matchEmailOrPhoneNumber(email, phoneNumber string) func(user *models.User) bool {
+	return func(user *models.User) bool {
+		return (email != "" && value(user.Email) == email) ||
+			(phoneNumber != "" && value(user.PhoneNumber) == phoneNumber)
+	}
+}
+
+func matchUsername(username string) func(user *models.User) bool {
+	return func(user *models.User) bool {
+		return username != "" && value(user.Username) == username
+	}
+}
+
+func matchAccountNumber(accountNumber string) func(user *models.User) bool {
+	return func(user *models.User) bool {
+		return user.AccountNumber == accountNumber
+	}
+}
+
+// clone copies the user, so callers never share the pointers of the stored one
+func clone(user models.User) models.User {
+	if user.Username != nil {
+		username := *user.Username
+		user.Username = &username
+	}
+	if user.Email != nil {
+		email := *user.Email
+		user.Email = &email
+	}
+	if user.PhoneNumber != nil {
+		phoneNumber := *user.PhoneNumber
+		user.PhoneNumber = &phoneNumber
+	}
+	if user.DeletionScheduledAt != nil {
+		at := *user.DeletionScheduledAt
+		user.DeletionScheduledAt = &at
+	}
+	if user.CreatedBy != nil {
+		createdBy := *user.CreatedBy
+		user.CreatedBy = &createdBy
+	}
+	if user.UpdatedBy != nil {
+		updatedBy := *user.UpdatedBy
+		user.UpdatedBy = &updatedBy
+	}
+	return user
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// snapshot implements snapshotter.
+func (ur *userRepository) snapshot() func() {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	users, nextID := slices.Clone(ur.users), ur.nextID
+	return func() {
+		ur.mu.Lock()
+		defer ur.mu.Unlock()
+		ur.users, ur.nextID = users, nextID
+	}
+}
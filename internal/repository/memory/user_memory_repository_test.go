@@ -0,0 +1,373 @@
+package memory_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/actor"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestUserCreate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create User Success", func(t *testing.T) {
+		repo := memory.NewUserRepository()
+		user := &models.User{AccountNumber: "12345", Name: "John Doe", Email: strPtr("john@example.com"), Password: "hash"}
+
+		require.NoError(t, repo.Create(ctx, user))
+		assert.Equal(t, 1, user.ID)
+		assert.False(t, user.CreatedAt.IsZero())
+
+		*user.Email = "changed@example.com"
+		stored, err := repo.GetOneByAccountNumber(ctx, "12345")
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", *stored.Email, "the stored user is a copy")
+		assert.Empty(t, stored.Password, "the password is not selected")
+	})
+
+	t.Run("Constraint Violations", func(t *testing.T) {
+		repo := memory.NewUserRepository(models.User{AccountNumber: "12345", Email: strPtr("john@example.com"), PhoneNumber: strPtr("+62811")})
+
+		err := repo.Create(ctx, &models.User{AccountNumber: "12345", Email: strPtr("other@example.com")})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+		err = repo.Create(ctx, &models.User{AccountNumber: "67890", Email: strPtr("john@example.com")})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+		err = repo.Create(ctx, &models.User{AccountNumber: "67890", PhoneNumber: strPtr("+62811")})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+		err = repo.Create(ctx, &models.User{AccountNumber: "67890", Email: strPtr("")})
+		assert.ErrorIs(t, err, gorm.ErrCheckConstraintViolated)
+
+		assert.NoError(t, repo.Create(ctx, &models.User{AccountNumber: "67890", Email: strPtr(""), PhoneNumber: strPtr("+62822")}),
+			"empty emails are not unique")
+	})
+}
+
+func TestUserLookups(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "12345", Email: strPtr("john@example.com"), Password: "hash"},
+		models.User{AccountNumber: "67890", PhoneNumber: strPtr("+62811"), DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+	)
+
+	exists, err := repo.CheckByEmailOrPhoneNumber(ctx, "", "+62811")
+	require.NoError(t, err)
+	assert.True(t, exists, "soft-deleted users keep their phone number")
+
+	exists, err = repo.CheckByEmailOrPhoneNumber(ctx, "", "")
+	require.NoError(t, err)
+	assert.False(t, exists, "empty values match nothing")
+
+	exists, err = repo.CheckByAccountNumber(ctx, "67890")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	user, err := repo.GetCredentialsByEmailOrPhoneNumber(ctx, "john@example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, "hash", user.Password)
+	assert.True(t, user.CreatedAt.IsZero(), "timestamps are not selected")
+
+	user, err = repo.GetOneByAccountNumber(ctx, "00000")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+
+	require.NoError(t, repo.UpdatePassword(ctx, 1, "new-hash"))
+	user, err = repo.GetCredentialsByEmailOrPhoneNumber(ctx, "john@example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", user.Password)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repo.CheckByAccountNumber(canceled, "12345")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUserList(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "1", Name: "John Doe", Email: strPtr("john@example.com"), CreatedAt: day(1)},
+		models.User{AccountNumber: "2", Name: "Jane Doe", Email: strPtr("jane@example.com"), CreatedAt: day(2)},
+		models.User{AccountNumber: "3", Name: "Johnny", Email: strPtr("johnny@example.com"), CreatedAt: day(3)},
+		models.User{AccountNumber: "4", Name: "John Deleted", Email: strPtr("gone@example.com"), CreatedAt: day(3), DeletedAt: sql.NullTime{Valid: true}},
+	)
+
+	users, total, err := repo.List(ctx, models.UserFilter{Name: "JOHN", SortBy: "created_at", SortDesc: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "3", users[0].AccountNumber)
+	assert.Nil(t, users[0].Email, "the email is not selected")
+
+	from, to := day(2), day(3)
+	users, total, err = repo.List(ctx, models.UserFilter{CreatedFrom: &from, CreatedTo: &to, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "2", users[0].AccountNumber)
+
+	users, total, err = repo.List(ctx, models.UserFilter{SortBy: "name", Limit: 10, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, users, 2)
+	assert.Equal(t, "John Doe", users[0].Name)
+	assert.Equal(t, "Johnny", users[1].Name)
+
+	users, total, err = repo.List(ctx, models.UserFilter{Name: "nobody", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.NotNil(t, users)
+}
+
+func TestUserSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "1001", Name: "John Doe", Email: strPtr("john@example.com"), Password: "hash"},
+		models.User{AccountNumber: "1002", Name: "Mary Johnson", Username: strPtr("mary"), PhoneNumber: strPtr("+6281234567890")},
+		models.User{AccountNumber: "1003", Name: "Johnny", Username: strPtr("john"), Email: strPtr("jj@example.com")},
+		models.User{AccountNumber: "1004", Name: "John Deleted", Email: strPtr("gone@example.com"), DeletedAt: sql.NullTime{Valid: true}},
+	)
+
+	results, total, err := repo.Search(ctx, models.UserSearchFilter{Query: "john", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, results, 3)
+	assert.Equal(t, "1003", results[0].AccountNumber, "the exact username ranks first")
+	assert.Equal(t, 1.0, results[0].Rank)
+	assert.Equal(t, "1001", results[1].AccountNumber)
+	assert.Equal(t, 0.75, results[1].Rank)
+	assert.Equal(t, "1002", results[2].AccountNumber)
+	assert.Equal(t, 0.5, results[2].Rank)
+	assert.Empty(t, results[1].Password, "the password is not selected")
+
+	results, total, err = repo.Search(ctx, models.UserSearchFilter{Query: "john", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, results, 1)
+	assert.Equal(t, "1001", results[0].AccountNumber)
+
+	results, _, err = repo.Search(ctx, models.UserSearchFilter{Query: "1234567", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "1002", results[0].AccountNumber, "phone numbers match by substring")
+
+	results, total, err = repo.Search(ctx, models.UserSearchFilter{Query: "100", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total, "account numbers match exactly")
+	assert.NotNil(t, results)
+}
+
+func TestUserIterate(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "1", Name: "John", Email: strPtr("a@example.com"), Password: "hash", CreatedAt: day(1)},
+		models.User{AccountNumber: "2", Name: "John", Email: strPtr("b@example.com"), CreatedAt: day(2), DeletedAt: sql.NullTime{Valid: true}},
+		models.User{AccountNumber: "3", Name: "Jane", Email: strPtr("c@example.com"), CreatedAt: day(3)},
+		models.User{AccountNumber: "4", Name: "Johnny", Email: strPtr("d@example.com"), CreatedAt: day(4)},
+	)
+
+	iterate := func(filter models.UserIterationFilter) []string {
+		var accountNumbers []string
+		err := repo.Iterate(ctx, filter, 2, func(user *models.User) error {
+			assert.Empty(t, user.Password, "the password is not selected")
+			accountNumbers = append(accountNumbers, user.AccountNumber)
+			return nil
+		})
+		require.NoError(t, err)
+		return accountNumbers
+	}
+
+	assert.Equal(t, []string{"1", "3", "4"}, iterate(models.UserIterationFilter{}))
+	assert.Equal(t, []string{"1", "2", "4"}, iterate(models.UserIterationFilter{Name: "JOHN", IncludeDeleted: true}))
+	from, to := day(2), day(4)
+	assert.Equal(t, []string{"3"}, iterate(models.UserIterationFilter{CreatedFrom: &from, CreatedTo: &to}))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	err := repo.Iterate(canceled, models.UserIterationFilter{}, 2, func(*models.User) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUserDeletion(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "1", Name: "John Doe", Email: strPtr("john@example.com"), Password: "hash"},
+		models.User{AccountNumber: "2", Name: "Jane Doe", PhoneNumber: strPtr("+6281234567890"), Password: "hash"},
+		models.User{AccountNumber: "3", Name: "Johnny", Email: strPtr("johnny@example.com"), Password: "hash"},
+	)
+
+	require.NoError(t, repo.ScheduleDeletion(ctx, 1, now.Add(-time.Hour)))
+	require.NoError(t, repo.ScheduleDeletion(ctx, 2, now.Add(-2*time.Hour)))
+	require.NoError(t, repo.ScheduleDeletion(ctx, 3, now.Add(time.Hour)))
+
+	user, err := repo.GetCredentialsByEmailOrPhoneNumber(ctx, "john@example.com", "")
+	require.NoError(t, err)
+	require.NotNil(t, user.DeletionScheduledAt)
+	assert.Equal(t, now.Add(-time.Hour), *user.DeletionScheduledAt)
+
+	due, err := repo.ListDueForDeletion(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "2", due[0].AccountNumber, "the longest due first")
+	assert.Equal(t, "1", due[1].AccountNumber)
+
+	require.NoError(t, repo.CancelDeletion(ctx, 1))
+	anonymized, err := repo.Anonymize(ctx, 1, now)
+	require.NoError(t, err)
+	assert.False(t, anonymized, "the deletion was cancelled")
+
+	anonymized, err = repo.Anonymize(ctx, 2, now)
+	require.NoError(t, err)
+	assert.True(t, anonymized)
+
+	user, err = repo.GetOneByAccountNumber(ctx, "2")
+	require.NoError(t, err)
+	assert.Empty(t, user.Name)
+	assert.Nil(t, user.PhoneNumber)
+	exists, err := repo.CheckByEmailOrPhoneNumber(ctx, "", "+6281234567890")
+	require.NoError(t, err)
+	assert.False(t, exists, "the phone number can be used again")
+
+	require.NoError(t, repo.CancelDeletion(ctx, 2))
+	due, err = repo.ListDueForDeletion(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 1, "an anonymized user can't cancel")
+
+	require.NoError(t, repo.CompleteDeletion(ctx, 2))
+	due, err = repo.ListDueForDeletion(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestUserUpdateEmail(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "1", Email: strPtr("john@example.com")},
+		models.User{AccountNumber: "2", Email: strPtr("jane@example.com")},
+	)
+
+	err := repo.UpdateEmail(ctx, 1, "jane@example.com")
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	require.NoError(t, repo.UpdateEmail(ctx, 1, "johnny@example.com"))
+	user, err := repo.GetOneByAccountNumber(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "johnny@example.com", *user.Email)
+
+	exists, err := repo.CheckByEmailOrPhoneNumber(ctx, "john@example.com", "")
+	require.NoError(t, err)
+	assert.False(t, exists, "the old email is free again")
+}
+
+func TestUserUpdatePhoneNumber(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(
+		models.User{AccountNumber: "1", Email: strPtr("john@example.com")},
+		models.User{AccountNumber: "2", PhoneNumber: strPtr("+6281234567890"), PhoneCountryCode: "ID"},
+	)
+
+	err := repo.UpdatePhoneNumber(ctx, 1, "+6281234567890", "ID")
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	require.NoError(t, repo.UpdatePhoneNumber(ctx, 1, "+447911123456", "GB"))
+	user, err := repo.GetOneByAccountNumber(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "+447911123456", *user.PhoneNumber)
+	assert.Equal(t, "GB", user.PhoneCountryCode)
+}
+
+func TestUserUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(models.User{AccountNumber: "1", Name: "John", Email: strPtr("john@example.com")})
+
+	require.NoError(t, repo.UpdateProfile(ctx, 1, 1, "John Doe"))
+	err := repo.UpdateProfile(ctx, 1, 1, "Johnny")
+	assert.True(t, pgsql.IsVersionConflict(err), "the version was bumped")
+
+	require.NoError(t, repo.UpdatePassword(ctx, 1, "hash"))
+	user, err := repo.GetOneByAccountNumber(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, 3, user.Version, "every update bumps the version")
+}
+
+func TestUserAudit(t *testing.T) {
+	repo := memory.NewUserRepository()
+	admin := actor.WithActor(context.Background(), actor.Admin)
+
+	require.NoError(t, repo.Create(admin, &models.User{AccountNumber: "1", Email: strPtr("john@example.com")}))
+	require.NoError(t, repo.Create(context.Background(), &models.User{AccountNumber: "2", Email: strPtr("jane@example.com")}))
+	require.NoError(t, repo.UpdateProfile(actor.WithActor(context.Background(), "1"), 1, 1, "John"))
+
+	var users []models.User
+	require.NoError(t, repo.Iterate(context.Background(), models.UserIterationFilter{}, 10, func(user *models.User) error {
+		users = append(users, *user)
+		return nil
+	}))
+	require.Len(t, users, 2)
+	assert.Equal(t, actor.Admin, *users[0].CreatedBy)
+	assert.Equal(t, "1", *users[0].UpdatedBy)
+	assert.Nil(t, users[1].CreatedBy, "signups have no actor")
+	assert.Nil(t, users[1].UpdatedBy)
+
+	updatedBy := func() *string {
+		var updated *string
+		require.NoError(t, repo.Iterate(context.Background(), models.UserIterationFilter{}, 10, func(user *models.User) error {
+			if user.AccountNumber == "2" {
+				updated = user.UpdatedBy
+			}
+			return nil
+		}))
+		return updated
+	}
+	require.NoError(t, repo.UpdateEmail(actor.WithActor(context.Background(), "2"), 2, "janet@example.com"))
+	require.NoError(t, repo.UpdatePassword(context.Background(), 2, "hash"))
+	if assert.NotNil(t, updatedBy()) {
+		assert.Equal(t, "2", *updatedBy(), "updates without an actor keep it")
+	}
+
+	require.NoError(t, repo.ScheduleDeletion(admin, 2, time.Now()))
+	if assert.NotNil(t, updatedBy()) {
+		assert.Equal(t, actor.Admin, *updatedBy())
+	}
+}
+
+func TestUserUsername(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(models.User{AccountNumber: "1", Username: strPtr("john.doe"), Email: strPtr("john@example.com"), Password: "hash"})
+
+	err := repo.Create(ctx, &models.User{AccountNumber: "2", Username: strPtr("john.doe"), Email: strPtr("johnny@example.com")})
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	require.NoError(t, repo.Create(ctx, &models.User{AccountNumber: "3", Email: strPtr("jane@example.com")}), "usernames are optional")
+
+	exists, err := repo.CheckByUsername(ctx, "john.doe")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	user, err := repo.GetCredentialsByUsername(ctx, "john.doe")
+	require.NoError(t, err)
+	if assert.NotNil(t, user) {
+		assert.Equal(t, "1", user.AccountNumber)
+		assert.Equal(t, "hash", user.Password)
+	}
+
+	user, err = repo.GetCredentialsByUsername(ctx, "")
+	require.NoError(t, err)
+	assert.Nil(t, user, "an empty username matches no one")
+}
@@ -0,0 +1,11 @@
+package pgsql
+
+var (
+	// QueryAddAPIUsage adds requests ($4) to the usage of a client in a period, returning its total
+	QueryAddAPIUsage = `
+		INSERT INTO api_usage (client, period, period_start, requests, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (client, period, period_start) DO UPDATE SET requests = api_usage.requests + EXCLUDED.requests, updated_at = EXCLUDED.updated_at
+		RETURNING requests
+	`
+)
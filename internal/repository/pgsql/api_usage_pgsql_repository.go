@@ -0,0 +1,32 @@
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIUsageRepository persists the requests of the API clients per quota period, see quota.Store
+type APIUsageRepository interface {
+	Add(ctx context.Context, client, period string, start time.Time, delta int64) (int64, error)
+}
+
+type apiUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIUsageRepository(db *gorm.DB) APIUsageRepository {
+	return &apiUsageRepository{db: db}
+}
+
+// Add adds delta requests to the usage of the client in the period starting at start and
+// returns its total; a zero delta reads it
+func (ar *apiUsageRepository) Add(ctx context.Context, client, period string, start time.Time, delta int64) (int64, error) {
+	var total int64
+
+	if err := ar.db.WithContext(ctx).Raw(QueryAddAPIUsage, client, period, start.UTC(), delta).Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
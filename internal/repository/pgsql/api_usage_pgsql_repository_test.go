@@ -0,0 +1,62 @@
+package pgsql_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestAPIUsage(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.APIUsageRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewAPIUsageRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Add Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (client, period, period_start) DO UPDATE SET requests = api_usage.requests + EXCLUDED.requests`)).
+			WithArgs("partner-a", "daily", start, int64(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"requests"}).AddRow(42))
+
+		total, err := repo.Add(context.Background(), "partner-a", "daily", start.In(time.FixedZone("WIB", 7*60*60)), 5)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Add Error", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO api_usage`)).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.Add(context.Background(), "partner-a", "daily", start, 5)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,255 @@
+package pgsql
+
+import (
+	"fmt"
+	"go-echo-boilerplate/internal/models"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SortColumns whitelists the columns a list can be ordered by, keyed by the name the caller
+// sorts by (e.g. "created_at"); names outside of it never reach the query
+type SortColumns map[string]string
+
+// ListColumns maps the fields of a models.ListSpec, as clients name them (e.g. "createdAt"),
+// to their columns (e.g. "created_at"), see Query.List
+type ListColumns map[string]string
+
+// identifierPattern matches the column names a Query accepts outside of its conditions
+var identifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// filterOperators are the `where` tag operators of Query.Filter, each a condition on the
+// column with one argument
+var filterOperators = map[string]func(column string) string{
+	"eq":       func(column string) string { return column + " = ?" },
+	"contains": func(column string) string { return column + ` ILIKE '%' || ? || '%'` },
+	"gte":      func(column string) string { return column + " >= ?" },
+	"gt":       func(column string) string { return column + " > ?" },
+	"lte":      func(column string) string { return column + " <= ?" },
+	"lt":       func(column string) string { return column + " < ?" },
+	"in":       func(column string) string { return column + " = ANY(?)" },
+}
+
+// Query builds a parameterized SELECT (and the matching COUNT) for list endpoints, whose
+// WHERE clause depends on the filters a request sets. Values only ever travel as arguments
+// ($1, $2, ...); column names come from the code, from `where` tags, or through a SortColumns
+// whitelist.
+//
+// Usage:
+//
+//	query := pgsql.From("users", "id, name, created_at").
+//	    Where("deleted_at IS NULL").
+//	    Filter(filter).
+//	    Sort(userSortColumns, filter.SortBy, "created_at", filter.SortDesc).
+//	    OrderBy("id", filter.SortDesc).
+//	    Page(filter.Limit, filter.Offset)
+//	countSQL, countArgs := query.Count()
+//	listSQL, listArgs := query.Build()
+type Query struct {
+	table      string
+	columns    string
+	conditions []string
+	args       []any
+	orderBy    []string
+	limit      int
+	offset     int
+}
+
+// From starts a query selecting columns (a trusted, comma-separated list) from table
+func From(table, columns string) *Query {
+	return &Query{table: table, columns: columns}
+}
+
+// Where adds a condition, ANDed with the others. Each ? in it is replaced by the next
+// argument, so the condition itself must come from the code, not from input.
+func (q *Query) Where(condition string, args ...any) *Query {
+	if count := strings.Count(condition, "?"); count != len(args) {
+		panic(fmt.Sprintf("pgsql: condition %q has %d placeholders for %d arguments", condition, count, len(args)))
+	}
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+	return q
+}
+
+// WhereIf adds the condition of Where only when ok, e.g. when a filter is set
+func (q *Query) WhereIf(ok bool, condition string, args ...any) *Query {
+	if !ok {
+		return q
+	}
+	return q.Where(condition, args...)
+}
+
+// Filter adds a condition for every set field of filter, a struct (or pointer to one) whose
+// fields carry a `where:"column,operator"` tag; fields without the tag are ignored. Zero
+// values (an empty string, a nil pointer, an empty slice) are not set. Operators:
+//
+//	eq        column = value
+//	contains  column ILIKE '%value%', with the LIKE wildcards of the value escaped
+//	gt, gte   column > value, column >= value
+//	lt, lte   column < value, column <= value
+//	in        column = ANY(value), for a slice
+//
+// An invalid tag is a programming error and panics.
+func (q *Query) Filter(filter any) *Query {
+	value := reflect.Indirect(reflect.ValueOf(filter))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("pgsql: filter must be a struct, got %T", filter))
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		tag, ok := value.Type().Field(i).Tag.Lookup("where")
+		if !ok {
+			continue
+		}
+		column, operator, _ := strings.Cut(tag, ",")
+		condition, known := filterOperators[operator]
+		if !identifierPattern.MatchString(column) || !known {
+			panic(fmt.Sprintf("pgsql: invalid where tag %q on %s", tag, value.Type().Field(i).Name))
+		}
+
+		field := value.Field(i)
+		if field.IsZero() || (field.Kind() == reflect.Slice && field.Len() == 0) {
+			continue
+		}
+		arg := reflect.Indirect(field).Interface()
+		if operator == "contains" {
+			arg = escapeLike(fmt.Sprint(arg))
+		}
+		q.Where(condition(column), arg)
+	}
+	return q
+}
+
+// OrderBy appends a trusted column to the ORDER BY clause, e.g. the id tie-breaker
+func (q *Query) OrderBy(column string, desc bool) *Query {
+	if !identifierPattern.MatchString(column) {
+		panic(fmt.Sprintf("pgsql: invalid order by column %q", column))
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	q.orderBy = append(q.orderBy, column+" "+direction)
+	return q
+}
+
+// Sort appends the column of sortBy in columns to the ORDER BY clause, or the one of
+// fallback when sortBy is not whitelisted
+func (q *Query) Sort(columns SortColumns, sortBy, fallback string, desc bool) *Query {
+	column, ok := columns[sortBy]
+	if !ok {
+		column = columns[fallback]
+	}
+	return q.OrderBy(column, desc)
+}
+
+// List adds the filters, the sort, and the page or the cursor of options, built from a
+// models.ListQuery by the handler (see binder.List), to the query. columns maps the fields of
+// the ListSpec of the endpoint to their columns; a field outside of it, which binder.List
+// refused already, is a programming error and panics. The rows are ordered by id last, the
+// tie-breaker of the pages and of the cursor: the table has an id column. With a cursor,
+// Count counts the rows after it.
+//
+//	query := pgsql.From("users", QueryListUsersColumns).
+//	    Where("deleted_at IS NULL").
+//	    List(options, userListColumns)
+func (q *Query) List(options models.ListOptions, columns ListColumns) *Query {
+	column := func(field string) string {
+		column, ok := columns[field]
+		if !ok || !identifierPattern.MatchString(column) {
+			panic(fmt.Sprintf("pgsql: field %q has no column", field))
+		}
+		return column
+	}
+
+	for _, filter := range options.Filters {
+		condition, known := filterOperators[filter.Operator]
+		if !known {
+			panic(fmt.Sprintf("pgsql: unknown filter operator %q", filter.Operator))
+		}
+		var arg any = filter.Value
+		switch filter.Operator {
+		case models.FilterContains:
+			arg = escapeLike(filter.Value)
+		case models.FilterIn:
+			arg = strings.Split(filter.Value, ",")
+		}
+		q.Where(condition(column(filter.Field)), arg)
+	}
+
+	desc := len(options.Sorts) > 0 && options.Sorts[0].Desc
+	if options.After != nil && len(options.Sorts) == 1 {
+		operator := ">"
+		if desc {
+			operator = "<"
+		}
+		q.Where("("+column(options.Sorts[0].Field)+", id) "+operator+" (?, ?)", options.After.Value, options.After.ID)
+	}
+	for _, sort := range options.Sorts {
+		q.OrderBy(column(sort.Field), sort.Desc)
+	}
+	return q.OrderBy("id", desc).Page(options.Limit, options.Offset)
+}
+
+// Page sets LIMIT and OFFSET; a limit of zero or less lists every row
+func (q *Query) Page(limit, offset int) *Query {
+	q.limit = limit
+	q.offset = offset
+	return q
+}
+
+// Build returns the SELECT and its arguments
+func (q *Query) Build() (string, []any) {
+	var sql strings.Builder
+	sql.WriteString("SELECT " + q.columns + " FROM " + q.table)
+	args := q.writeWhere(&sql)
+
+	if len(q.orderBy) > 0 {
+		sql.WriteString(" ORDER BY " + strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		args = append(args, q.limit)
+		sql.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+	if q.offset > 0 {
+		args = append(args, q.offset)
+		sql.WriteString(" OFFSET $" + strconv.Itoa(len(args)))
+	}
+	return sql.String(), args
+}
+
+// Count returns the COUNT(*) of the rows matching the conditions and its arguments
+func (q *Query) Count() (string, []any) {
+	var sql strings.Builder
+	sql.WriteString("SELECT COUNT(*) FROM " + q.table)
+	args := q.writeWhere(&sql)
+	return sql.String(), args
+}
+
+// writeWhere writes the WHERE clause, numbering the placeholders, and returns its arguments
+func (q *Query) writeWhere(sql *strings.Builder) []any {
+	if len(q.conditions) == 0 {
+		return nil
+	}
+
+	n := 0
+	sql.WriteString(" WHERE ")
+	for i, condition := range q.conditions {
+		if i > 0 {
+			sql.WriteString(" AND ")
+		}
+		sql.WriteString("(")
+		for _, r := range condition {
+			if r == '?' {
+				n++
+				sql.WriteString("$" + strconv.Itoa(n))
+				continue
+			}
+			sql.WriteRune(r)
+		}
+		sql.WriteString(")")
+	}
+	return append([]any(nil), q.args...)
+}
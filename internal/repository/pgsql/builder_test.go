@@ -0,0 +1,109 @@
+package pgsql_test
+
+import (
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listFilter struct {
+	Name      string     `where:"name,contains"`
+	Status    string     `where:"status,eq"`
+	From      *time.Time `where:"created_at,gte"`
+	Countries []string   `where:"phone_country_code,in"`
+	Sort      string
+}
+
+func TestQuery(t *testing.T) {
+	columns := pgsql.SortColumns{"name": "name", "created_at": "created_at"}
+
+	t.Run("Builds The Set Filters", func(t *testing.T) {
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		query := pgsql.From("users", "id, name").
+			Where("deleted_at IS NULL").
+			Filter(listFilter{Name: "50%_off", From: &from, Countries: []string{"62", "65"}}).
+			Sort(columns, "name", "created_at", true).
+			OrderBy("id", true).
+			Page(20, 40)
+
+		sql, args := query.Build()
+		assert.Equal(t, `SELECT id, name FROM users WHERE (deleted_at IS NULL) AND (name ILIKE '%' || $1 || '%') AND (created_at >= $2) AND (phone_country_code = ANY($3)) ORDER BY name DESC, id DESC LIMIT $4 OFFSET $5`, sql)
+		assert.Equal(t, []any{`50\%\_off`, from, []string{"62", "65"}, 20, 40}, args)
+
+		sql, args = query.Count()
+		assert.Equal(t, `SELECT COUNT(*) FROM users WHERE (deleted_at IS NULL) AND (name ILIKE '%' || $1 || '%') AND (created_at >= $2) AND (phone_country_code = ANY($3))`, sql)
+		assert.Len(t, args, 3)
+	})
+
+	t.Run("Skips Unset Filters And Paging", func(t *testing.T) {
+		sql, args := pgsql.From("users", "id").Filter(&listFilter{Countries: []string{}}).Build()
+		assert.Equal(t, "SELECT id FROM users", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("Numbers Placeholders Across Conditions", func(t *testing.T) {
+		sql, args := pgsql.From("users", "id").
+			Where("created_at BETWEEN ? AND ?", 1, 2).
+			WhereIf(false, "name = ?", "skipped").
+			WhereIf(true, "status = ?", "active").
+			Build()
+		assert.Equal(t, "SELECT id FROM users WHERE (created_at BETWEEN $1 AND $2) AND (status = $3)", sql)
+		assert.Equal(t, []any{1, 2, "active"}, args)
+	})
+
+	t.Run("Unknown Sort Falls Back", func(t *testing.T) {
+		sql, _ := pgsql.From("users", "id").Sort(columns, "password; DROP TABLE users", "created_at", false).Build()
+		assert.Equal(t, "SELECT id FROM users ORDER BY created_at ASC", sql)
+	})
+
+	t.Run("Rejects Programming Errors", func(t *testing.T) {
+		assert.Panics(t, func() { pgsql.From("users", "id").Where("name = ?") })
+		assert.Panics(t, func() { pgsql.From("users", "id").OrderBy("id; DROP TABLE users", false) })
+		assert.Panics(t, func() {
+			pgsql.From("users", "id").Filter(struct {
+				Name string `where:"name,like"`
+			}{Name: "x"})
+		})
+		assert.Panics(t, func() { pgsql.From("users", "id").Filter("name") })
+	})
+}
+
+func TestQuery_List(t *testing.T) {
+	columns := pgsql.ListColumns{"name": "name", "createdAt": "created_at", "country": "phone_country_code"}
+
+	t.Run("Page", func(t *testing.T) {
+		options := models.ListOptions{
+			Filters: []models.ListFilter{
+				{Field: "name", Operator: models.FilterContains, Value: "jo%"},
+				{Field: "country", Operator: models.FilterIn, Value: "62,65"},
+			},
+			Sorts:  []models.ListSort{{Field: "createdAt", Desc: true}, {Field: "name"}},
+			Limit:  20,
+			Offset: 40,
+		}
+		sql, args := pgsql.From("users", "id").Where("deleted_at IS NULL").List(options, columns).Build()
+		assert.Equal(t, `SELECT id FROM users WHERE (deleted_at IS NULL) AND (name ILIKE '%' || $1 || '%') AND (phone_country_code = ANY($2)) ORDER BY created_at DESC, name ASC, id DESC LIMIT $3 OFFSET $4`, sql)
+		assert.Equal(t, []any{`jo\%`, []string{"62", "65"}, 20, 40}, args)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		options := models.ListOptions{
+			Sorts: []models.ListSort{{Field: "createdAt", Desc: true}},
+			Limit: 20,
+			After: &models.Cursor{Sort: "-createdAt", Value: "2026-01-01T00:00:00Z", ID: 7},
+		}
+		sql, args := pgsql.From("users", "id").List(options, columns).Build()
+		assert.Equal(t, `SELECT id FROM users WHERE ((created_at, id) < ($1, $2)) ORDER BY created_at DESC, id DESC LIMIT $3`, sql)
+		assert.Equal(t, []any{"2026-01-01T00:00:00Z", 7, 20}, args)
+	})
+
+	t.Run("Field Without Column", func(t *testing.T) {
+		assert.Panics(t, func() {
+			pgsql.From("users", "id").List(models.ListOptions{Sorts: []models.ListSort{{Field: "password"}}}, columns)
+		})
+	})
+}
@@ -0,0 +1,18 @@
+package pgsql
+
+var (
+	// QueryGetLatestConsent selects the current consent of an account, its newest row
+	QueryGetLatestConsent = `
+		SELECT id, account_number, terms_version, terms_accepted_at, marketing_opt_in, marketing_updated_at, ip, user_agent, created_at FROM consents
+		WHERE account_number = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	// QueryAnonymizeConsents clears the client of every consent of an account; what was agreed
+	// to and when stays
+	QueryAnonymizeConsents = `
+		UPDATE consents SET ip = '', user_agent = ''
+		WHERE account_number = $1
+	`
+)
@@ -0,0 +1,45 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ConsentRepository interface {
+	Create(ctx context.Context, consent *models.Consent) error
+	GetLatest(ctx context.Context, accountNumber string) (*models.Consent, error)
+	Anonymize(ctx context.Context, accountNumber string) error
+}
+
+type consentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(db *gorm.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
+
+func (cr *consentRepository) Create(ctx context.Context, consent *models.Consent) error {
+	return cr.db.WithContext(ctx).Create(consent).Error
+}
+
+// GetLatest returns the current consent of the account, nil when it never gave one
+func (cr *consentRepository) GetLatest(ctx context.Context, accountNumber string) (*models.Consent, error) {
+	var consents []models.Consent
+
+	if err := cr.db.WithContext(ctx).Raw(QueryGetLatestConsent, accountNumber).Scan(&consents).Error; err != nil {
+		return nil, err
+	}
+
+	if len(consents) == 0 {
+		return nil, nil
+	}
+	return &consents[0], nil
+}
+
+// Anonymize clears the IP and user agent of every consent of the account
+func (cr *consentRepository) Anonymize(ctx context.Context, accountNumber string) error {
+	return cr.db.WithContext(ctx).Exec(QueryAnonymizeConsents, accountNumber).Error
+}
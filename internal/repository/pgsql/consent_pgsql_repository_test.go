@@ -0,0 +1,96 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestConsent(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.ConsentRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewConsentRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	t.Run("Create Consent Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		now := time.Now()
+		consent := &models.Consent{AccountNumber: "12345", TermsVersion: "2026-10-01", TermsAcceptedAt: &now, IP: "81.2.69.142"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "consents"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Create(context.Background(), consent))
+		assert.Equal(t, int64(1), consent.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Latest Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM consents`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "terms_version", "marketing_opt_in", "created_at"}).
+				AddRow(2, "12345", "2026-10-01", true, time.Now()))
+
+		consent, err := repo.GetLatest(context.Background(), "12345")
+		assert.NoError(t, err)
+		if assert.NotNil(t, consent) {
+			assert.Equal(t, int64(2), consent.ID)
+			assert.Equal(t, "2026-10-01", consent.TermsVersion)
+			assert.True(t, consent.MarketingOptIn)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Latest Without Consent", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM consents`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		consent, err := repo.GetLatest(context.Background(), "12345")
+		assert.NoError(t, err)
+		assert.Nil(t, consent)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Anonymize Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE consents SET ip = '', user_agent = ''`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		assert.NoError(t, repo.Anonymize(context.Background(), "12345"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,30 @@
+package pgsql
+
+var (
+	// QueryCreateDebugCapture inserts a debug capture, returning its id
+	QueryCreateDebugCapture = `
+		INSERT INTO debug_captures (request_id, trigger, label, account_number, method, path, status, request, response, captured_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10, $11)
+		RETURNING id
+	`
+
+	// QueryListDebugCaptures selects the latest debug captures first, without their request and response
+	QueryListDebugCaptures = `
+		SELECT id, request_id, trigger, label, account_number, method, path, status, captured_at, expires_at FROM debug_captures
+		ORDER BY id DESC
+		LIMIT $1
+	`
+
+	// QueryGetDebugCapture selects a debug capture
+	QueryGetDebugCapture = `
+		SELECT id, request_id, trigger, label, account_number, method, path, status, request::text AS request, response::text AS response,
+			captured_at, expires_at FROM debug_captures
+		WHERE id = $1
+	`
+
+	// QueryDeleteExpiredDebugCaptures deletes the debug captures expired at $1
+	QueryDeleteExpiredDebugCaptures = `
+		DELETE FROM debug_captures
+		WHERE expires_at <= $1
+	`
+)
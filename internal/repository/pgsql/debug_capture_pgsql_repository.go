@@ -0,0 +1,62 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DebugCaptureRepository interface {
+	Create(ctx context.Context, capture *models.DebugCapture) error
+	List(ctx context.Context, limit int) ([]models.DebugCapture, error)
+	Get(ctx context.Context, id int64) (*models.DebugCapture, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type debugCaptureRepository struct {
+	db *gorm.DB
+}
+
+func NewDebugCaptureRepository(db *gorm.DB) DebugCaptureRepository {
+	return &debugCaptureRepository{db: db}
+}
+
+// Create inserts the debug capture, setting its ID
+func (dr *debugCaptureRepository) Create(ctx context.Context, capture *models.DebugCapture) error {
+	return dr.db.WithContext(ctx).Raw(QueryCreateDebugCapture,
+		capture.RequestID, capture.Trigger, capture.Label, capture.AccountNumber, capture.Method, capture.Path, capture.Status,
+		capture.Request, capture.Response, capture.CapturedAt, capture.ExpiresAt,
+	).Scan(&capture.ID).Error
+}
+
+// List returns up to limit debug captures, the latest first, without their request and response
+func (dr *debugCaptureRepository) List(ctx context.Context, limit int) ([]models.DebugCapture, error) {
+	captures := []models.DebugCapture{}
+
+	if err := dr.db.WithContext(ctx).Raw(QueryListDebugCaptures, limit).Scan(&captures).Error; err != nil {
+		return nil, err
+	}
+	return captures, nil
+}
+
+// Get returns the debug capture of id, nil when there is none
+func (dr *debugCaptureRepository) Get(ctx context.Context, id int64) (*models.DebugCapture, error) {
+	var captures []models.DebugCapture
+
+	if err := dr.db.WithContext(ctx).Raw(QueryGetDebugCapture, id).Scan(&captures).Error; err != nil {
+		return nil, err
+	}
+
+	if len(captures) == 0 {
+		return nil, nil
+	}
+	return &captures[0], nil
+}
+
+// DeleteExpired deletes the debug captures expired at now, returning how many it deleted
+func (dr *debugCaptureRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := dr.db.WithContext(ctx).Exec(QueryDeleteExpiredDebugCaptures, now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,100 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestDebugCapture(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.DebugCaptureRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewDebugCaptureRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	t.Run("Create Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		now := time.Now()
+		capture := &models.DebugCapture{
+			RequestID: "req-1", Trigger: models.DebugCaptureTriggerToken, Label: "SUP-1234", Method: "POST", Path: "/api/v1/users",
+			Status: 422, Request: `{"method":"POST"}`, Response: `{"status":422}`, CapturedAt: now, ExpiresAt: now.Add(time.Hour),
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO debug_captures`)).
+			WithArgs("req-1", "token", "SUP-1234", "", "POST", "/api/v1/users", 422, `{"method":"POST"}`, `{"status":422}`, now, capture.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+		assert.NoError(t, repo.Create(context.Background(), capture))
+		assert.Equal(t, int64(7), capture.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("List Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM debug_captures`)).
+			WithArgs(50).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "trigger", "path", "status"}).
+				AddRow(2, "account", "/api/v1/users/profile", 200).
+				AddRow(1, "token", "/api/v1/users", 422))
+
+		captures, err := repo.List(context.Background(), 50)
+		assert.NoError(t, err)
+		if assert.Len(t, captures, 2) {
+			assert.Equal(t, int64(2), captures[0].ID)
+			assert.Equal(t, 422, captures[1].Status)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Missing", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM debug_captures`)).
+			WithArgs(int64(9)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		capture, err := repo.Get(context.Background(), 9)
+		assert.NoError(t, err)
+		assert.Nil(t, capture)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete Expired", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		now := time.Now()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM debug_captures`)).
+			WithArgs(now).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		deleted, err := repo.DeleteExpired(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,28 @@
+package pgsql
+
+var (
+	// QueryGetEmailChange selects an email change by id
+	QueryGetEmailChange = `
+		SELECT id, account_number, old_email, new_email, old_confirmed_at, new_confirmed_at, completed_at, cancelled_at, expires_at, created_at FROM email_changes
+		WHERE id = $1
+	`
+
+	// QueryCancelPendingEmailChanges cancels the email changes of an account still waiting for confirmation
+	QueryCancelPendingEmailChanges = `
+		UPDATE email_changes SET cancelled_at = NOW()
+		WHERE account_number = $1 AND completed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	// QueryUpdateEmailChange records the confirmations and completion of an email change still
+	// pending, keeping the confirmations stored by a concurrent update
+	QueryUpdateEmailChange = `
+		UPDATE email_changes SET old_confirmed_at = COALESCE(old_confirmed_at, $1), new_confirmed_at = COALESCE(new_confirmed_at, $2), completed_at = $3
+		WHERE id = $4 AND completed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	// QueryDeleteEmailChanges deletes the email changes of an account, they hold its addresses
+	QueryDeleteEmailChanges = `
+		DELETE FROM email_changes
+		WHERE account_number = $1
+	`
+)
@@ -0,0 +1,62 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EmailChangeRepository interface {
+	Create(ctx context.Context, change *models.EmailChange) error
+	GetByID(ctx context.Context, id int64) (*models.EmailChange, error)
+	CancelPending(ctx context.Context, accountNumber string) error
+	Update(ctx context.Context, change *models.EmailChange) (bool, error)
+	DeleteByAccountNumber(ctx context.Context, accountNumber string) error
+}
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+func (er *emailChangeRepository) Create(ctx context.Context, change *models.EmailChange) error {
+	return er.db.WithContext(ctx).Create(change).Error
+}
+
+// GetByID returns the email change, nil when there is none
+func (er *emailChangeRepository) GetByID(ctx context.Context, id int64) (*models.EmailChange, error) {
+	var changes []models.EmailChange
+
+	if err := er.db.WithContext(ctx).Raw(QueryGetEmailChange, id).Scan(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return &changes[0], nil
+}
+
+// CancelPending cancels the email changes of the account that are neither completed nor cancelled
+func (er *emailChangeRepository) CancelPending(ctx context.Context, accountNumber string) error {
+	return er.db.WithContext(ctx).Exec(QueryCancelPendingEmailChanges, accountNumber).Error
+}
+
+// Update stores the confirmation and completion times of the change, false when it is no
+// longer pending
+func (er *emailChangeRepository) Update(ctx context.Context, change *models.EmailChange) (bool, error) {
+	result := er.db.WithContext(ctx).Exec(QueryUpdateEmailChange, change.OldConfirmedAt, change.NewConfirmedAt, change.CompletedAt, change.ID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteByAccountNumber deletes every email change of the account
+func (er *emailChangeRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	return er.db.WithContext(ctx).Exec(QueryDeleteEmailChanges, accountNumber).Error
+}
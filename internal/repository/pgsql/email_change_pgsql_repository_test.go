@@ -0,0 +1,117 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestEmailChange(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.EmailChangeRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewEmailChangeRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Create Email Change Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		oldEmail := "john@example.com"
+		change := &models.EmailChange{AccountNumber: "12345", OldEmail: &oldEmail, NewEmail: "johnny@example.com", ExpiresAt: now}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "email_changes"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Create(context.Background(), change))
+		assert.Equal(t, int64(1), change.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get By ID Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM email_changes`)).
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "old_email", "new_email", "old_confirmed_at", "expires_at"}).
+				AddRow(3, "12345", "john@example.com", "johnny@example.com", now, now.Add(time.Hour)))
+
+		change, err := repo.GetByID(context.Background(), 3)
+		assert.NoError(t, err)
+		if assert.NotNil(t, change) {
+			assert.Equal(t, "johnny@example.com", change.NewEmail)
+			assert.Equal(t, "john@example.com", *change.OldEmail)
+			assert.NotNil(t, change.OldConfirmedAt)
+			assert.Nil(t, change.NewConfirmedAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get By ID Not Found", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM email_changes`)).
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		change, err := repo.GetByID(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Nil(t, change)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Cancel, Update And Delete", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		change := &models.EmailChange{ID: 3, OldConfirmedAt: &now}
+
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE email_changes SET cancelled_at = NOW()`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE email_changes SET old_confirmed_at = COALESCE(old_confirmed_at, $1)`)).
+			WithArgs(now, nil, nil, int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`WHERE id = $4 AND completed_at IS NULL AND cancelled_at IS NULL`)).
+			WithArgs(now, nil, nil, int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM email_changes`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		assert.NoError(t, repo.CancelPending(context.Background(), "12345"))
+		updated, err := repo.Update(context.Background(), change)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		updated, err = repo.Update(context.Background(), change)
+		assert.NoError(t, err)
+		assert.False(t, updated, "no longer pending")
+		assert.NoError(t, repo.DeleteByAccountNumber(context.Background(), "12345"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
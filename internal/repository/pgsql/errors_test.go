@@ -0,0 +1,20 @@
+package pgsql_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	assert.True(t, pgsql.IsUniqueViolation(fmt.Errorf("update: %w", &pgconn.PgError{Code: "23505"})))
+	assert.True(t, pgsql.IsUniqueViolation(fmt.Errorf("%w: idx_users_phone_number_unique", gorm.ErrDuplicatedKey)))
+	assert.False(t, pgsql.IsUniqueViolation(&pgconn.PgError{Code: "23514"}), "check violations are not unique violations")
+	assert.False(t, pgsql.IsUniqueViolation(errors.New("connection refused")))
+}
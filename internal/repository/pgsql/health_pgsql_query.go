@@ -0,0 +1,11 @@
+package pgsql
+
+var (
+	// QueryReplicationStats counts the replicas streaming from the database along with the
+	// replay lag of the slowest one, in seconds. Without the pg_monitor role the lag reads 0.
+	QueryReplicationStats = `
+		SELECT COUNT(*) AS replicas,
+		       COALESCE(EXTRACT(EPOCH FROM MAX(replay_lag)), 0) AS lag_seconds
+		FROM pg_stat_replication
+	`
+)
@@ -2,12 +2,15 @@ package pgsql
 
 import (
 	"context"
+	"go-echo-boilerplate/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type HealthRepository interface {
 	Check(ctx context.Context) error
+	Stats(ctx context.Context) (*models.PostgreSQLStats, error)
 }
 
 type healthRepository struct {
@@ -26,3 +29,24 @@ func (hr *healthRepository) Check(ctx context.Context) error {
 
 	return pgsql.PingContext(ctx)
 }
+
+// Stats returns the connection pool figures and the replicas streaming from the database
+func (hr *healthRepository) Stats(ctx context.Context) (*models.PostgreSQLStats, error) {
+	pgsql, err := hr.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	stats := &models.PostgreSQLStats{Pool: pgsql.Stats()}
+
+	var replication struct {
+		Replicas   int
+		LagSeconds float64
+	}
+	if err := hr.db.WithContext(ctx).Raw(QueryReplicationStats).Scan(&replication).Error; err != nil {
+		return nil, err
+	}
+	stats.Replicas = replication.Replicas
+	stats.ReplicationLag = time.Duration(replication.LagSeconds * float64(time.Second))
+
+	return stats, nil
+}
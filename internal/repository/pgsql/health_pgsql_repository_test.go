@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"go-echo-boilerplate/internal/repository/pgsql"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -59,4 +61,30 @@ func TestHealthCheck(t *testing.T) {
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("Stats", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM pg_stat_replication`)).
+			WillReturnRows(sqlmock.NewRows([]string{"replicas", "lag_seconds"}).AddRow(2, 1.5))
+
+		stats, err := repo.Stats(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stats.Replicas)
+		assert.Equal(t, 1500*time.Millisecond, stats.ReplicationLag)
+		assert.Equal(t, 1, stats.Pool.OpenConnections, "the connection of the ping")
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Stats Failure", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM pg_stat_replication`)).WillReturnError(errors.New("permission denied"))
+
+		_, err := repo.Stats(context.Background())
+		assert.Error(t, err)
+	})
 }
@@ -0,0 +1,62 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStopIteration ends an Iterate early when returned by its fn; Iterate then returns nil
+var ErrStopIteration = errors.New("pgsql: stop iteration")
+
+// Iterate walks rows in keyset batches: fetch returns at most limit rows ordered by key,
+// after the key of the last row of the previous batch (the zero K for the first). fn is called
+// with each row; an error of it stops the walk and is returned, ErrStopIteration aside.
+//
+// Only one batch is held at a time and no connection is kept between batches, so it suits
+// exports, backfills, and jobs over large tables. Rows inserted during the walk are visited
+// when their key is past the current one. The context is checked before every batch.
+//
+// Usage:
+//
+//	err := pgsql.Iterate(ctx, 500,
+//	    func(ctx context.Context, afterID, limit int) ([]models.User, error) {
+//	        return listUsersAfter(ctx, afterID, limit)
+//	    },
+//	    func(user *models.User) int { return user.ID },
+//	    func(user *models.User) error { return process(user) },
+//	)
+func Iterate[T, K any](
+	ctx context.Context,
+	batchSize int,
+	fetch func(ctx context.Context, after K, limit int) ([]T, error),
+	key func(row *T) K,
+	fn func(row *T) error,
+) error {
+	batchSize = max(batchSize, 1)
+
+	var after K
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, err := fetch(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = key(&batch[len(batch)-1])
+	}
+}
@@ -0,0 +1,85 @@
+package pgsql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterate(t *testing.T) {
+	rows := []string{"a", "b", "c", "d", "e"}
+
+	// fetch serves the rows after the given one, recording the batches asked for
+	var fetched []string
+	fetch := func(ctx context.Context, after string, limit int) ([]string, error) {
+		fetched = append(fetched, after)
+		var batch []string
+		for _, row := range rows {
+			if row > after && len(batch) < limit {
+				batch = append(batch, row)
+			}
+		}
+		return batch, nil
+	}
+	key := func(row *string) string { return *row }
+
+	t.Run("Walks Every Row In Batches", func(t *testing.T) {
+		fetched = nil
+		var visited []string
+		err := pgsql.Iterate(context.Background(), 2, fetch, key, func(row *string) error {
+			visited = append(visited, *row)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rows, visited)
+		assert.Equal(t, []string{"", "b", "d"}, fetched)
+	})
+
+	t.Run("Full Last Batch Needs An Empty One", func(t *testing.T) {
+		fetched = nil
+		err := pgsql.Iterate(context.Background(), 5, fetch, key, func(*string) error { return nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"", "e"}, fetched)
+	})
+
+	t.Run("Stops Early", func(t *testing.T) {
+		var visited []string
+		err := pgsql.Iterate(context.Background(), 2, fetch, key, func(row *string) error {
+			visited = append(visited, *row)
+			if len(visited) == 3 {
+				return pgsql.ErrStopIteration
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, visited)
+	})
+
+	t.Run("Returns The Errors", func(t *testing.T) {
+		failure := errors.New("failed")
+		err := pgsql.Iterate(context.Background(), 2, fetch, key, func(*string) error { return failure })
+		assert.ErrorIs(t, err, failure)
+
+		err = pgsql.Iterate(context.Background(), 2, func(context.Context, string, int) ([]string, error) {
+			return nil, failure
+		}, key, func(*string) error { return nil })
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("Honors Cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := pgsql.Iterate(ctx, 2, fetch, key, func(*string) error {
+			cancel()
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,27 @@
+package pgsql
+
+var (
+	// QueryGetLoginHistory summarizes the logins of an account: how many there were and whether
+	// any came from the country ($2) or the device, OS, and browser ($3, $4, $5) of a new one
+	QueryGetLoginHistory = `
+		SELECT COUNT(*) AS logins,
+		       COALESCE(BOOL_OR(country = $2), FALSE) AS country_seen,
+		       COALESCE(BOOL_OR(device = $3 AND os = $4 AND browser = $5), FALSE) AS device_seen
+		FROM login_events
+		WHERE account_number = $1
+	`
+
+	// QueryListLoginEvents lists the most recent logins of an account, newest first; $2 is LIMIT
+	QueryListLoginEvents = `
+		SELECT id, account_number, ip, country, city, device, os, browser, suspicious, reasons, created_at FROM login_events
+		WHERE account_number = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	// QueryDeleteLoginEvents deletes the logins of an account, they hold its IPs and locations
+	QueryDeleteLoginEvents = `
+		DELETE FROM login_events
+		WHERE account_number = $1
+	`
+)
@@ -0,0 +1,54 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type LoginEventRepository interface {
+	Create(ctx context.Context, event *models.LoginEvent) error
+	GetHistory(ctx context.Context, event *models.LoginEvent) (*models.LoginHistory, error)
+	ListByAccountNumber(ctx context.Context, accountNumber string, limit int) ([]models.LoginEvent, error)
+	DeleteByAccountNumber(ctx context.Context, accountNumber string) error
+}
+
+type loginEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginEventRepository(db *gorm.DB) LoginEventRepository {
+	return &loginEventRepository{db: db}
+}
+
+func (lr *loginEventRepository) Create(ctx context.Context, event *models.LoginEvent) error {
+	return lr.db.WithContext(ctx).Create(event).Error
+}
+
+// GetHistory compares the event to the earlier logins of its account
+func (lr *loginEventRepository) GetHistory(ctx context.Context, event *models.LoginEvent) (*models.LoginHistory, error) {
+	var history models.LoginHistory
+
+	if err := lr.db.WithContext(ctx).Raw(QueryGetLoginHistory, event.AccountNumber, event.Country, event.Device, event.OS, event.Browser).Scan(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// ListByAccountNumber returns the most recent logins of the account, newest first
+func (lr *loginEventRepository) ListByAccountNumber(ctx context.Context, accountNumber string, limit int) ([]models.LoginEvent, error) {
+	events := []models.LoginEvent{}
+
+	if err := lr.db.WithContext(ctx).Raw(QueryListLoginEvents, accountNumber, limit).Scan(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DeleteByAccountNumber deletes every login of the account
+func (lr *loginEventRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	return lr.db.WithContext(ctx).Exec(QueryDeleteLoginEvents, accountNumber).Error
+}
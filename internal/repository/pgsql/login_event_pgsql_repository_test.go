@@ -0,0 +1,111 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestLoginEvent(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.LoginEventRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewLoginEventRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	t.Run("Create Login Event Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		event := &models.LoginEvent{AccountNumber: "12345", IP: "81.2.69.142", Country: "GB", Suspicious: true, Reasons: "new_country"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "login_events"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Create(context.Background(), event))
+		assert.Equal(t, int64(1), event.ID)
+		assert.False(t, event.CreatedAt.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get History Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		event := &models.LoginEvent{AccountNumber: "12345", Country: "GB", Device: "mobile", OS: "iOS", Browser: "Safari"}
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM login_events`)).
+			WithArgs("12345", "GB", "mobile", "iOS", "Safari").
+			WillReturnRows(sqlmock.NewRows([]string{"logins", "country_seen", "device_seen"}).AddRow(3, true, false))
+
+		history, err := repo.GetHistory(context.Background(), event)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.LoginHistory{Logins: 3, CountrySeen: true}, history)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("List By Account Number Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY created_at DESC, id DESC`)).
+			WithArgs("12345", 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "ip", "country", "suspicious", "reasons", "created_at"}).
+				AddRow(2, "12345", "81.2.69.142", "GB", true, "new_country", time.Now()).
+				AddRow(1, "12345", "103.10.20.30", "ID", false, "", time.Now()))
+
+		events, err := repo.ListByAccountNumber(context.Background(), "12345", 20)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, "GB", events[0].Country)
+			assert.True(t, events[0].Suspicious)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("List By Account Number Empty", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM login_events`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		events, err := repo.ListByAccountNumber(context.Background(), "12345", 20)
+		assert.NoError(t, err)
+		assert.NotNil(t, events)
+		assert.Empty(t, events)
+	})
+
+	t.Run("Delete By Account Number Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM login_events`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		assert.NoError(t, repo.DeleteByAccountNumber(context.Background(), "12345"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
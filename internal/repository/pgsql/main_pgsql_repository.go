@@ -5,14 +5,36 @@ import (
 )
 
 type PostgreRepository struct {
-	Health HealthRepository
+	Health      HealthRepository
+	Transaction TransactionRepository
 
-	User UserRepository
+	User        UserRepository
+	LoginEvent  LoginEventRepository
+	Consent     ConsentRepository
+	EmailChange EmailChangeRepository
+	PhoneChange PhoneChangeRepository
+
+	Notification  NotificationRepository
+	APIUsage      APIUsageRepository
+	QuotaOverride QuotaOverrideRepository
+
+	DebugCapture DebugCaptureRepository
 }
 
 func New(db *gorm.DB) *PostgreRepository {
 	return &PostgreRepository{
-		Health: NewHealthRepository(db),
-		User:   NewUserRepository(db),
+		Health:      NewHealthRepository(db),
+		Transaction: NewTransactionRepository(db),
+		User:        NewUserRepository(db),
+		LoginEvent:  NewLoginEventRepository(db),
+		Consent:     NewConsentRepository(db),
+		EmailChange: NewEmailChangeRepository(db),
+		PhoneChange: NewPhoneChangeRepository(db),
+
+		Notification:  NewNotificationRepository(db),
+		APIUsage:      NewAPIUsageRepository(db),
+		QuotaOverride: NewQuotaOverrideRepository(db),
+
+		DebugCapture: NewDebugCaptureRepository(db),
 	}
 }
@@ -0,0 +1,55 @@
+package pgsql
+
+var (
+	// QueryGetNotificationPreferences selects the notification preferences of an account
+	QueryGetNotificationPreferences = `
+		SELECT account_number, email, sms, updated_at FROM notification_preferences
+		WHERE account_number = $1
+	`
+
+	// QuerySaveNotificationPreferences creates or replaces the notification preferences of an account
+	QuerySaveNotificationPreferences = `
+		INSERT INTO notification_preferences (account_number, email, sms, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_number) DO UPDATE SET email = EXCLUDED.email, sms = EXCLUDED.sms, updated_at = EXCLUDED.updated_at
+	`
+
+	// QueryCreateNotificationDeadLetter inserts a dead letter, returning its id
+	QueryCreateNotificationDeadLetter = `
+		INSERT INTO notification_dead_letters (account_number, event, channel, message, attempts, error, failed_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7)
+		RETURNING id
+	`
+
+	// QueryListNotificationDeadLetters selects the oldest dead letters first
+	QueryListNotificationDeadLetters = `
+		SELECT id, account_number, event, channel, message::text AS message, attempts, error, failed_at FROM notification_dead_letters
+		ORDER BY id
+		LIMIT $1
+	`
+
+	// QueryGetNotificationDeadLetter selects a dead letter
+	QueryGetNotificationDeadLetter = `
+		SELECT id, account_number, event, channel, message::text AS message, attempts, error, failed_at FROM notification_dead_letters
+		WHERE id = $1
+	`
+
+	// QueryDeleteNotificationDeadLetter deletes a dead letter
+	QueryDeleteNotificationDeadLetter = `
+		DELETE FROM notification_dead_letters
+		WHERE id = $1
+	`
+
+	// QueryDeleteNotificationPreferences deletes the notification preferences of an account
+	QueryDeleteNotificationPreferences = `
+		DELETE FROM notification_preferences
+		WHERE account_number = $1
+	`
+
+	// QueryDeleteNotificationDeadLetters deletes the dead letters of an account, they hold its
+	// addresses
+	QueryDeleteNotificationDeadLetters = `
+		DELETE FROM notification_dead_letters
+		WHERE account_number = $1
+	`
+)
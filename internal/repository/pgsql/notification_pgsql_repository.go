@@ -0,0 +1,91 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	GetPreferences(ctx context.Context, accountNumber string) (*models.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, preferences *models.NotificationPreferences) error
+	CreateDeadLetter(ctx context.Context, letter *models.NotificationDeadLetter) error
+	ListDeadLetters(ctx context.Context, limit int) ([]models.NotificationDeadLetter, error)
+	GetDeadLetter(ctx context.Context, id int64) (*models.NotificationDeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, id int64) error
+	DeleteByAccountNumber(ctx context.Context, accountNumber string) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// GetPreferences returns the notification preferences of the account, nil when it never
+// changed them
+func (nr *notificationRepository) GetPreferences(ctx context.Context, accountNumber string) (*models.NotificationPreferences, error) {
+	var preferences []models.NotificationPreferences
+
+	if err := nr.db.WithContext(ctx).Raw(QueryGetNotificationPreferences, accountNumber).Scan(&preferences).Error; err != nil {
+		return nil, err
+	}
+
+	if len(preferences) == 0 {
+		return nil, nil
+	}
+	return &preferences[0], nil
+}
+
+// SavePreferences creates or replaces the notification preferences of the account
+func (nr *notificationRepository) SavePreferences(ctx context.Context, preferences *models.NotificationPreferences) error {
+	return nr.db.WithContext(ctx).Exec(QuerySaveNotificationPreferences,
+		preferences.AccountNumber, preferences.Email, preferences.SMS, preferences.UpdatedAt).Error
+}
+
+// CreateDeadLetter inserts the dead letter, setting its ID
+func (nr *notificationRepository) CreateDeadLetter(ctx context.Context, letter *models.NotificationDeadLetter) error {
+	return nr.db.WithContext(ctx).Raw(QueryCreateNotificationDeadLetter,
+		letter.AccountNumber, letter.Event, letter.Channel, letter.Message, letter.Attempts, letter.Error, letter.FailedAt,
+	).Scan(&letter.ID).Error
+}
+
+// ListDeadLetters returns up to limit dead letters, the oldest first
+func (nr *notificationRepository) ListDeadLetters(ctx context.Context, limit int) ([]models.NotificationDeadLetter, error) {
+	var letters []models.NotificationDeadLetter
+
+	if err := nr.db.WithContext(ctx).Raw(QueryListNotificationDeadLetters, limit).Scan(&letters).Error; err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
+
+// GetDeadLetter returns the dead letter of id, nil when there is none
+func (nr *notificationRepository) GetDeadLetter(ctx context.Context, id int64) (*models.NotificationDeadLetter, error) {
+	var letters []models.NotificationDeadLetter
+
+	if err := nr.db.WithContext(ctx).Raw(QueryGetNotificationDeadLetter, id).Scan(&letters).Error; err != nil {
+		return nil, err
+	}
+
+	if len(letters) == 0 {
+		return nil, nil
+	}
+	return &letters[0], nil
+}
+
+// DeleteDeadLetter deletes the dead letter of id
+func (nr *notificationRepository) DeleteDeadLetter(ctx context.Context, id int64) error {
+	return nr.db.WithContext(ctx).Exec(QueryDeleteNotificationDeadLetter, id).Error
+}
+
+// DeleteByAccountNumber deletes the notification preferences and the dead letters of the account
+func (nr *notificationRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	if err := nr.db.WithContext(ctx).Exec(QueryDeleteNotificationPreferences, accountNumber).Error; err != nil {
+		return err
+	}
+	return nr.db.WithContext(ctx).Exec(QueryDeleteNotificationDeadLetters, accountNumber).Error
+}
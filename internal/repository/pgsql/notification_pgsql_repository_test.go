@@ -0,0 +1,141 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestNotification(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.NotificationRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewNotificationRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	t.Run("Get Preferences Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM notification_preferences`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"account_number", "email", "sms", "updated_at"}).
+				AddRow("12345", true, false, time.Now()))
+
+		preferences, err := repo.GetPreferences(context.Background(), "12345")
+		assert.NoError(t, err)
+		if assert.NotNil(t, preferences) {
+			assert.True(t, preferences.Email)
+			assert.False(t, preferences.SMS)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Preferences Never Changed", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM notification_preferences`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"account_number"}))
+
+		preferences, err := repo.GetPreferences(context.Background(), "12345")
+		assert.NoError(t, err)
+		assert.Nil(t, preferences)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Save Preferences Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		now := time.Now()
+		mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (account_number) DO UPDATE`)).
+			WithArgs("12345", true, false, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.SavePreferences(context.Background(), &models.NotificationPreferences{AccountNumber: "12345", Email: true, UpdatedAt: now}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create Dead Letter Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		letter := &models.NotificationDeadLetter{AccountNumber: "12345", Event: "login.suspicious", Channel: "email", Message: `{"event":"login.suspicious"}`, Attempts: 3, Error: "timeout", FailedAt: time.Now()}
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO notification_dead_letters`)).
+			WithArgs("12345", "login.suspicious", "email", `{"event":"login.suspicious"}`, 3, "timeout", letter.FailedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+		assert.NoError(t, repo.CreateDeadLetter(context.Background(), letter))
+		assert.Equal(t, int64(7), letter.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("List Dead Letters Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM notification_dead_letters`)).
+			WithArgs(50).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "event", "channel", "message", "attempts"}).
+				AddRow(1, "login.suspicious", "email", `{"event":"login.suspicious"}`, 3).
+				AddRow(2, "phone_change.code", "sms", `{"event":"phone_change.code"}`, 3))
+
+		letters, err := repo.ListDeadLetters(context.Background(), 50)
+		assert.NoError(t, err)
+		if assert.Len(t, letters, 2) {
+			assert.Equal(t, `{"event":"login.suspicious"}`, letters[0].Message)
+			assert.Equal(t, "sms", letters[1].Channel)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Dead Letter Missing", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM notification_dead_letters`)).
+			WithArgs(int64(9)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		letter, err := repo.GetDeadLetter(context.Background(), 9)
+		assert.NoError(t, err)
+		assert.Nil(t, letter)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete By Account Number Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM notification_preferences`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM notification_dead_letters`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		assert.NoError(t, repo.DeleteByAccountNumber(context.Background(), "12345"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
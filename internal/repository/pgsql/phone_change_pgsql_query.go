@@ -0,0 +1,38 @@
+package pgsql
+
+var (
+	// QueryGetPendingPhoneChange selects the latest phone change of an account still waiting
+	// for its code
+	QueryGetPendingPhoneChange = `
+		SELECT id, account_number, phone_number, phone_country_code, code_hash, attempts, expires_at, completed_at, cancelled_at, created_at FROM phone_changes
+		WHERE account_number = $1 AND completed_at IS NULL AND cancelled_at IS NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	// QueryCancelPendingPhoneChanges cancels the phone changes of an account still waiting for their code
+	QueryCancelPendingPhoneChanges = `
+		UPDATE phone_changes SET cancelled_at = NOW()
+		WHERE account_number = $1 AND completed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	// QueryIncrementPhoneChangeAttempts counts a code entered for a pending phone change with
+	// fewer than $2 attempts, returning the count; no row is returned past the limit
+	QueryIncrementPhoneChangeAttempts = `
+		UPDATE phone_changes SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2 AND completed_at IS NULL AND cancelled_at IS NULL
+		RETURNING attempts
+	`
+
+	// QueryCompletePhoneChange marks a phone change as completed while it is still pending
+	QueryCompletePhoneChange = `
+		UPDATE phone_changes SET completed_at = $1
+		WHERE id = $2 AND completed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	// QueryDeletePhoneChanges deletes the phone changes of an account, they hold its numbers
+	QueryDeletePhoneChanges = `
+		DELETE FROM phone_changes
+		WHERE account_number = $1
+	`
+)
@@ -0,0 +1,76 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PhoneChangeRepository interface {
+	Create(ctx context.Context, change *models.PhoneChange) error
+	GetPending(ctx context.Context, accountNumber string) (*models.PhoneChange, error)
+	CancelPending(ctx context.Context, accountNumber string) error
+	IncrementAttempts(ctx context.Context, id int64, max int) (int, error)
+	Complete(ctx context.Context, id int64, at time.Time) (bool, error)
+	DeleteByAccountNumber(ctx context.Context, accountNumber string) error
+}
+
+type phoneChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewPhoneChangeRepository(db *gorm.DB) PhoneChangeRepository {
+	return &phoneChangeRepository{db: db}
+}
+
+func (pr *phoneChangeRepository) Create(ctx context.Context, change *models.PhoneChange) error {
+	return pr.db.WithContext(ctx).Create(change).Error
+}
+
+// GetPending returns the latest phone change of the account that is neither completed nor
+// cancelled, nil when there is none. It may be expired.
+func (pr *phoneChangeRepository) GetPending(ctx context.Context, accountNumber string) (*models.PhoneChange, error) {
+	var changes []models.PhoneChange
+
+	if err := pr.db.WithContext(ctx).Raw(QueryGetPendingPhoneChange, accountNumber).Scan(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return &changes[0], nil
+}
+
+// CancelPending cancels the phone changes of the account that are neither completed nor cancelled
+func (pr *phoneChangeRepository) CancelPending(ctx context.Context, accountNumber string) error {
+	return pr.db.WithContext(ctx).Exec(QueryCancelPendingPhoneChanges, accountNumber).Error
+}
+
+// IncrementAttempts counts a code entered for the change and returns the count, in one
+// statement so concurrent guesses can't pass the limit. It returns 0 when the change already
+// has max attempts or is no longer pending.
+func (pr *phoneChangeRepository) IncrementAttempts(ctx context.Context, id int64, max int) (int, error) {
+	var attempts int
+	if err := pr.db.WithContext(ctx).Raw(QueryIncrementPhoneChangeAttempts, id, max).Scan(&attempts).Error; err != nil {
+		return 0, err
+	}
+	return attempts, nil
+}
+
+// Complete marks the change as completed at the given time. It returns false when the change
+// is no longer pending: completed by a concurrent verification, or cancelled by a newer request.
+func (pr *phoneChangeRepository) Complete(ctx context.Context, id int64, at time.Time) (bool, error) {
+	result := pr.db.WithContext(ctx).Exec(QueryCompletePhoneChange, at, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteByAccountNumber deletes every phone change of the account
+func (pr *phoneChangeRepository) DeleteByAccountNumber(ctx context.Context, accountNumber string) error {
+	return pr.db.WithContext(ctx).Exec(QueryDeletePhoneChanges, accountNumber).Error
+}
@@ -0,0 +1,125 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestPhoneChange(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.PhoneChangeRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewPhoneChangeRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Create Phone Change Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		change := &models.PhoneChange{AccountNumber: "12345", PhoneNumber: "+6281234567890", PhoneCountryCode: "ID", CodeHash: "hash", ExpiresAt: now}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "phone_changes"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Create(context.Background(), change))
+		assert.Equal(t, int64(1), change.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Pending Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM phone_changes`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "phone_number", "phone_country_code", "code_hash", "attempts", "expires_at"}).
+				AddRow(3, "12345", "+6281234567890", "ID", "hash", 2, now))
+
+		change, err := repo.GetPending(context.Background(), "12345")
+		assert.NoError(t, err)
+		if assert.NotNil(t, change) {
+			assert.Equal(t, "+6281234567890", change.PhoneNumber)
+			assert.Equal(t, "hash", change.CodeHash)
+			assert.Equal(t, 2, change.Attempts)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Pending Without Change", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM phone_changes`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		change, err := repo.GetPending(context.Background(), "12345")
+		assert.NoError(t, err)
+		assert.Nil(t, change)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Cancel, Attempt, Complete And Delete", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE phone_changes SET cancelled_at = NOW()`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE phone_changes SET attempts = attempts + 1`)).
+			WithArgs(int64(3), 5).
+			WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE id = $1 AND attempts < $2 AND completed_at IS NULL`)).
+			WithArgs(int64(3), 5).
+			WillReturnRows(sqlmock.NewRows([]string{"attempts"}))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE phone_changes SET completed_at = $1`)).
+			WithArgs(now, int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`WHERE id = $2 AND completed_at IS NULL AND cancelled_at IS NULL`)).
+			WithArgs(now, int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM phone_changes`)).
+			WithArgs("12345").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		assert.NoError(t, repo.CancelPending(context.Background(), "12345"))
+		attempts, err := repo.IncrementAttempts(context.Background(), 3, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		attempts, err = repo.IncrementAttempts(context.Background(), 3, 5)
+		assert.NoError(t, err)
+		assert.Zero(t, attempts, "past the limit")
+		completed, err := repo.Complete(context.Background(), 3, now)
+		assert.NoError(t, err)
+		assert.True(t, completed)
+		completed, err = repo.Complete(context.Background(), 3, now)
+		assert.NoError(t, err)
+		assert.False(t, completed, "no longer pending")
+		assert.NoError(t, repo.DeleteByAccountNumber(context.Background(), "12345"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,29 @@
+package pgsql
+
+var (
+	// QueryListQuotaOverrides selects every quota override, expired ones included
+	QueryListQuotaOverrides = `
+		SELECT client, daily_quota, monthly_quota, reason, expires_at, updated_at FROM quota_overrides
+		ORDER BY client
+	`
+
+	// QueryGetQuotaOverride selects the quota override of a client
+	QueryGetQuotaOverride = `
+		SELECT client, daily_quota, monthly_quota, reason, expires_at, updated_at FROM quota_overrides
+		WHERE client = $1
+	`
+
+	// QuerySaveQuotaOverride creates or replaces the quota override of a client
+	QuerySaveQuotaOverride = `
+		INSERT INTO quota_overrides (client, daily_quota, monthly_quota, reason, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (client) DO UPDATE SET daily_quota = EXCLUDED.daily_quota, monthly_quota = EXCLUDED.monthly_quota,
+			reason = EXCLUDED.reason, expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at
+	`
+
+	// QueryDeleteQuotaOverride deletes the quota override of a client
+	QueryDeleteQuotaOverride = `
+		DELETE FROM quota_overrides
+		WHERE client = $1
+	`
+)
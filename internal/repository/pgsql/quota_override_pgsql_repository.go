@@ -0,0 +1,62 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type QuotaOverrideRepository interface {
+	List(ctx context.Context) ([]models.QuotaOverride, error)
+	Get(ctx context.Context, client string) (*models.QuotaOverride, error)
+	Save(ctx context.Context, override *models.QuotaOverride) error
+	Delete(ctx context.Context, client string) (bool, error)
+}
+
+type quotaOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaOverrideRepository(db *gorm.DB) QuotaOverrideRepository {
+	return &quotaOverrideRepository{db: db}
+}
+
+// List returns every quota override, expired ones included, by client
+func (qr *quotaOverrideRepository) List(ctx context.Context) ([]models.QuotaOverride, error) {
+	overrides := []models.QuotaOverride{}
+
+	if err := qr.db.WithContext(ctx).Raw(QueryListQuotaOverrides).Scan(&overrides).Error; err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// Get returns the quota override of the client, nil when it has none
+func (qr *quotaOverrideRepository) Get(ctx context.Context, client string) (*models.QuotaOverride, error) {
+	var overrides []models.QuotaOverride
+
+	if err := qr.db.WithContext(ctx).Raw(QueryGetQuotaOverride, client).Scan(&overrides).Error; err != nil {
+		return nil, err
+	}
+
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	return &overrides[0], nil
+}
+
+// Save creates or replaces the quota override of the client
+func (qr *quotaOverrideRepository) Save(ctx context.Context, override *models.QuotaOverride) error {
+	return qr.db.WithContext(ctx).Exec(QuerySaveQuotaOverride,
+		override.Client, override.DailyQuota, override.MonthlyQuota, override.Reason, override.ExpiresAt, override.UpdatedAt).Error
+}
+
+// Delete deletes the quota override of the client, reporting whether it had one
+func (qr *quotaOverrideRepository) Delete(ctx context.Context, client string) (bool, error) {
+	result := qr.db.WithContext(ctx).Exec(QueryDeleteQuotaOverride, client)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
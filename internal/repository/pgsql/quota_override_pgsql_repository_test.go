@@ -0,0 +1,98 @@
+package pgsql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestQuotaOverride(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.QuotaOverrideRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewQuotaOverrideRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+	columns := []string{"client", "daily_quota", "monthly_quota", "reason", "expires_at", "updated_at"}
+
+	t.Run("List Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		now := time.Now()
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM quota_overrides`)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("partner-a", 20000, nil, "SUP-1234", now, now).
+				AddRow("partner-b", nil, 0, "SUP-1235", nil, now))
+
+		overrides, err := repo.List(context.Background())
+		assert.NoError(t, err)
+		if assert.Len(t, overrides, 2) {
+			assert.Equal(t, int64(20000), *overrides[0].DailyQuota)
+			assert.Nil(t, overrides[0].MonthlyQuota)
+			assert.Equal(t, int64(0), *overrides[1].MonthlyQuota)
+			assert.Nil(t, overrides[1].ExpiresAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get None", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM quota_overrides`)).
+			WithArgs("partner-a").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		override, err := repo.Get(context.Background(), "partner-a")
+		assert.NoError(t, err)
+		assert.Nil(t, override)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Save Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		now := time.Now()
+		daily := int64(20000)
+		mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (client) DO UPDATE`)).
+			WithArgs("partner-a", &daily, nil, "SUP-1234", nil, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.Save(context.Background(), &models.QuotaOverride{Client: "partner-a", DailyQuota: &daily, Reason: "SUP-1234", UpdatedAt: now}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM quota_overrides`)).
+			WithArgs("partner-a").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		deleted, err := repo.Delete(context.Background(), "partner-a")
+		assert.NoError(t, err)
+		assert.False(t, deleted, "had none")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,184 @@
+package pgsql
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// cachedUserRepository caches the users of GetOneByAccountNumber and drops them on the
+// writes of the wrapped UserRepository
+type cachedUserRepository struct {
+	UserRepository
+	users    *cache.Loader[*models.User] // by account number; nil for a missing user
+	accounts *cache.Loader[string]       // account number by user id, for the writes by id
+
+	// pending is set inside a transaction: reads skip the cache, which only holds committed
+	// users, and the written users are dropped once the transaction ends
+	pending *pendingInvalidations
+}
+
+// pendingInvalidations are the users written in a transaction
+type pendingInvalidations struct {
+	mu             sync.Mutex
+	ids            []int
+	accountNumbers []string
+}
+
+// NewCachedUserRepository caches the users users.GetOneByAccountNumber returns (missing ones
+// included) in store for ttl. Concurrent misses of an account number share one query, and
+// the write methods drop the user they change, so reads are only stale when a write happens
+// on another instance with a memory store, and then for ttl at most. Writes in transactions
+// go through PostgreRepository.CacheUsers instead.
+func NewCachedUserRepository(users UserRepository, store cache.Store, ttl time.Duration) UserRepository {
+	return newCachedUserRepository(users, store, ttl)
+}
+
+func newCachedUserRepository(users UserRepository, store cache.Store, ttl time.Duration) *cachedUserRepository {
+	return &cachedUserRepository{
+		UserRepository: users,
+		users:          cache.NewLoader[*models.User](store, "users", ttl),
+		accounts:       cache.NewLoader[string](store, "user_accounts", ttl),
+	}
+}
+
+// CacheUsers caches the users of r.User, see NewCachedUserRepository. Transactions of
+// r.Transaction.Atomic read the users from the database and drop the ones they wrote from
+// the cache once they end; transactions of Begin are not tracked.
+func (r *PostgreRepository) CacheUsers(store cache.Store, ttl time.Duration) {
+	users := newCachedUserRepository(r.User, store, ttl)
+	r.User = users
+	r.Transaction = &cachedTransactionRepository{TransactionRepository: r.Transaction, users: users}
+}
+
+func (cr *cachedUserRepository) GetOneByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
+	if cr.pending != nil {
+		return cr.UserRepository.GetOneByAccountNumber(ctx, accountNumber)
+	}
+
+	return cr.users.Get(ctx, accountNumber, func(ctx context.Context) (*models.User, error) {
+		user, err := cr.UserRepository.GetOneByAccountNumber(ctx, accountNumber)
+		if err == nil && user != nil {
+			_ = cr.accounts.Set(ctx, strconv.Itoa(user.ID), user.AccountNumber)
+		}
+		return user, err
+	})
+}
+
+func (cr *cachedUserRepository) Create(ctx context.Context, user *models.User) error {
+	err := cr.UserRepository.Create(ctx, user)
+	cr.forgetAccountNumber(ctx, user.AccountNumber)
+	return err
+}
+
+func (cr *cachedUserRepository) UpdateProfile(ctx context.Context, id, version int, name string) error {
+	err := cr.UserRepository.UpdateProfile(ctx, id, version, name)
+	cr.forget(ctx, id)
+	return err
+}
+
+func (cr *cachedUserRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+	err := cr.UserRepository.UpdatePassword(ctx, id, hashedPassword)
+	cr.forget(ctx, id)
+	return err
+}
+
+func (cr *cachedUserRepository) UpdateEmail(ctx context.Context, id int, email string) error {
+	err := cr.UserRepository.UpdateEmail(ctx, id, email)
+	cr.forget(ctx, id)
+	return err
+}
+
+func (cr *cachedUserRepository) UpdatePhoneNumber(ctx context.Context, id int, phoneNumber, countryCode string) error {
+	err := cr.UserRepository.UpdatePhoneNumber(ctx, id, phoneNumber, countryCode)
+	cr.forget(ctx, id)
+	return err
+}
+
+func (cr *cachedUserRepository) ScheduleDeletion(ctx context.Context, id int, at time.Time) error {
+	err := cr.UserRepository.ScheduleDeletion(ctx, id, at)
+	cr.forget(ctx, id)
+	return err
+}
+
+func (cr *cachedUserRepository) CancelDeletion(ctx context.Context, id int) error {
+	err := cr.UserRepository.CancelDeletion(ctx, id)
+	cr.forget(ctx, id)
+	return err
+}
+
+func (cr *cachedUserRepository) Anonymize(ctx context.Context, id int, now time.Time) (bool, error) {
+	anonymized, err := cr.UserRepository.Anonymize(ctx, id, now)
+	cr.forget(ctx, id)
+	return anonymized, err
+}
+
+func (cr *cachedUserRepository) CompleteDeletion(ctx context.Context, id int) error {
+	err := cr.UserRepository.CompleteDeletion(ctx, id)
+	cr.forget(ctx, id)
+	return err
+}
+
+// forget drops the cached user of id, or defers it to the end of the transaction. Users
+// whose account number is not known were not cached by this store.
+func (cr *cachedUserRepository) forget(ctx context.Context, id int) {
+	if cr.pending != nil {
+		cr.pending.mu.Lock()
+		cr.pending.ids = append(cr.pending.ids, id)
+		cr.pending.mu.Unlock()
+		return
+	}
+
+	if accountNumber, ok := cr.accounts.Peek(ctx, strconv.Itoa(id)); ok {
+		cr.forgetAccountNumber(ctx, accountNumber)
+	}
+}
+
+// forgetAccountNumber drops the cached user of accountNumber, or defers it to the end of
+// the transaction
+func (cr *cachedUserRepository) forgetAccountNumber(ctx context.Context, accountNumber string) {
+	if cr.pending != nil {
+		cr.pending.mu.Lock()
+		cr.pending.accountNumbers = append(cr.pending.accountNumbers, accountNumber)
+		cr.pending.mu.Unlock()
+		return
+	}
+
+	if err := cr.users.Invalidate(ctx, accountNumber); err != nil {
+		logger.FromContext(ctx).Warn(ctx, "user cache invalidation failed", logger.Error(err))
+	}
+}
+
+// cachedTransactionRepository tracks the users written in its Atomic transactions
+type cachedTransactionRepository struct {
+	TransactionRepository
+	users *cachedUserRepository
+}
+
+// Atomic runs fc like TransactionRepository.Atomic, then drops the users it wrote from the
+// cache, committed or not
+func (tr *cachedTransactionRepository) Atomic(ctx context.Context, fc func(ctx context.Context, r *PostgreRepository) error) error {
+	pending := &pendingInvalidations{}
+	err := tr.TransactionRepository.Atomic(ctx, func(ctx context.Context, r *PostgreRepository) error {
+		tx := *r
+		tx.User = &cachedUserRepository{
+			UserRepository: r.User,
+			users:          tr.users.users,
+			accounts:       tr.users.accounts,
+			pending:        pending,
+		}
+		return fc(ctx, &tx)
+	})
+
+	for _, id := range pending.ids {
+		tr.users.forget(ctx, id)
+	}
+	for _, accountNumber := range pending.accountNumbers {
+		tr.users.forgetAccountNumber(ctx, accountNumber)
+	}
+	return err
+}
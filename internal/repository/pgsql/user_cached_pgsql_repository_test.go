@@ -0,0 +1,113 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingUserRepository counts the reads reaching the wrapped repository
+type countingUserRepository struct {
+	pgsql.UserRepository
+	reads int
+}
+
+func (r *countingUserRepository) GetOneByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
+	r.reads++
+	return r.UserRepository.GetOneByAccountNumber(ctx, accountNumber)
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*pgsql.PostgreRepository, *countingUserRepository, *models.User) {
+		users := &countingUserRepository{UserRepository: memory.NewUserRepository()}
+		email := "john@example.com"
+		user := &models.User{AccountNumber: "12345", Name: "John Doe", Email: &email, Password: "hash"}
+		require.NoError(t, users.Create(ctx, user))
+
+		repo := &pgsql.PostgreRepository{
+			User: users,
+			// What Atomic hands to its function, like New(tx)
+			Transaction: memory.NewTransactionRepository(&pgsql.PostgreRepository{User: users}),
+		}
+		repo.CacheUsers(cache.NewMemoryStore(100), time.Minute)
+		return repo, users, user
+	}
+
+	t.Run("Reads Once", func(t *testing.T) {
+		repo, users, _ := setup(t)
+
+		for range 3 {
+			user, err := repo.User.GetOneByAccountNumber(ctx, "12345")
+			require.NoError(t, err)
+			assert.Equal(t, "John Doe", user.Name)
+		}
+		assert.Equal(t, 1, users.reads)
+	})
+
+	t.Run("Caches Missing Users Until Created", func(t *testing.T) {
+		repo, users, _ := setup(t)
+		phoneNumber := "+6281234567890"
+
+		for range 2 {
+			user, err := repo.User.GetOneByAccountNumber(ctx, "67890")
+			require.NoError(t, err)
+			assert.Nil(t, user)
+		}
+		assert.Equal(t, 1, users.reads)
+
+		require.NoError(t, repo.User.Create(ctx, &models.User{AccountNumber: "67890", Name: "Jane Doe", PhoneNumber: &phoneNumber}))
+		user, err := repo.User.GetOneByAccountNumber(ctx, "67890")
+		require.NoError(t, err)
+		require.NotNil(t, user)
+		assert.Equal(t, "Jane Doe", user.Name)
+	})
+
+	t.Run("Writes Drop The User", func(t *testing.T) {
+		repo, users, created := setup(t)
+
+		_, err := repo.User.GetOneByAccountNumber(ctx, "12345")
+		require.NoError(t, err)
+		require.NoError(t, repo.User.UpdateProfile(ctx, created.ID, 1, "Johnny"))
+
+		user, err := repo.User.GetOneByAccountNumber(ctx, "12345")
+		require.NoError(t, err)
+		assert.Equal(t, "Johnny", user.Name)
+		assert.Equal(t, 2, users.reads)
+	})
+
+	t.Run("Transactions Read The Database And Drop Written Users At The End", func(t *testing.T) {
+		repo, users, created := setup(t)
+
+		_, err := repo.User.GetOneByAccountNumber(ctx, "12345")
+		require.NoError(t, err)
+
+		err = repo.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+			if err := r.User.UpdateEmail(ctx, created.ID, "johnny@example.com"); err != nil {
+				return err
+			}
+			user, err := r.User.GetOneByAccountNumber(ctx, "12345")
+			require.NoError(t, err)
+			assert.Equal(t, "johnny@example.com", *user.Email, "reads skip the cache")
+
+			cached, err := repo.User.GetOneByAccountNumber(ctx, "12345")
+			require.NoError(t, err)
+			assert.Equal(t, "john@example.com", *cached.Email, "dropped once the transaction ends")
+			return nil
+		})
+		require.NoError(t, err)
+
+		user, err := repo.User.GetOneByAccountNumber(ctx, "12345")
+		require.NoError(t, err)
+		assert.Equal(t, "johnny@example.com", *user.Email)
+		assert.Equal(t, 3, users.reads)
+	})
+}
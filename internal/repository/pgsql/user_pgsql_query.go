@@ -11,19 +11,163 @@ var (
 		)
 	`
 
+	// QueryCheckByUsername checks if a user exists with the given (lowercased) username
+	QueryCheckByUsername = `
+		SELECT EXISTS (
+			SELECT 1 FROM users
+			WHERE username = $1
+		)
+	`
+
+	// QueryCheckByAccountNumber checks if a user exists with the given account number
+	// Soft-deleted users are included, their account numbers are never reused
+	QueryCheckByAccountNumber = `
+		SELECT EXISTS (
+			SELECT 1 FROM users
+			WHERE account_number = $1
+		)
+	`
+
 	// QueryGetCredentialsByEmailOrPhoneNumber gets the user's credentials (id, email, phone number, password) by email or phone number
 	// Returns the user's credentials if a user with either the email (when not empty) or phone number (when not empty) exists
 	// #nosec G101 -- This is a SQL query string, not hardcoded credentials
 	QueryGetCredentialsByEmailOrPhoneNumber = `
-		SELECT id, account_number, name, email, phone_number, phone_country_code, password FROM users
+		SELECT id, account_number, name, email, phone_number, phone_country_code, password, deletion_scheduled_at, username FROM users
 		WHERE (email = $1 AND $1 != '')
 		   OR (phone_number = $2 AND $2 != '')
 	`
 
+	// QueryGetCredentialsByUsername gets the same columns as QueryGetCredentialsByEmailOrPhoneNumber
+	// by (lowercased) username
+	// #nosec G101 -- This is a SQL query string, not hardcoded credentials
+	QueryGetCredentialsByUsername = `
+		SELECT id, account_number, name, email, phone_number, phone_country_code, password, deletion_scheduled_at, username FROM users
+		WHERE username = $1
+	`
+
 	// QueryGetByAccountNumber gets the user's credentials (id, email, phone number, password) by account number
 	// Returns the user's credentials if a user with the account number exists
 	QueryGetByAccountNumber = `
-		SELECT id, account_number, name, email, phone_number, phone_country_code, created_at, updated_at FROM users
+		SELECT id, account_number, name, email, phone_number, phone_country_code, created_at, updated_at, deleted_at, deletion_scheduled_at, username, version FROM users
 		WHERE account_number = $1
 	`
+
+	// QueryUpdatePassword replaces the user's password hash, e.g. after a rehash on login. Its
+	// last parameter, like that of the UPDATE queries below, is updated_by: NULL keeps it.
+	// #nosec G101 -- This is a SQL query string, not hardcoded credentials
+	QueryUpdatePassword = `
+		UPDATE users SET password = $1, updated_at = NOW(), updated_by = COALESCE($3, updated_by), version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	// QueryUpdatePhoneNumber replaces the user's phone number, e.g. after a verified phone change
+	QueryUpdatePhoneNumber = `
+		UPDATE users SET phone_number = $1, phone_country_code = $2, updated_at = NOW(), updated_by = COALESCE($4, updated_by), version = version + 1
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+
+	// QueryUpdateEmail replaces the user's email, e.g. after a confirmed email change
+	QueryUpdateEmail = `
+		UPDATE users SET email = $1, updated_at = NOW(), updated_by = COALESCE($3, updated_by), version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	// QueryScheduleDeletion sets when the user is anonymized ($1)
+	QueryScheduleDeletion = `
+		UPDATE users SET deletion_scheduled_at = $1, updated_at = NOW(), updated_by = COALESCE($3, updated_by), version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	// QueryCancelDeletion clears a scheduled deletion that has not been carried out
+	QueryCancelDeletion = `
+		UPDATE users SET deletion_scheduled_at = NULL, updated_at = NOW(), updated_by = COALESCE($2, updated_by), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	// QueryListDueForDeletion lists the users whose deletion is due at $1, including the
+	// anonymized ones whose deletion was not completed; $2 is LIMIT
+	QueryListDueForDeletion = `
+		SELECT id, account_number, deletion_scheduled_at, deleted_at FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at, id
+		LIMIT $2
+	`
+
+	// QueryAnonymizeUser removes the personal data of a user whose deletion is due at $2 and
+	// soft-deletes it. The account number stays, other tables reference it.
+	// #nosec G101 -- This is a SQL query string, not hardcoded credentials
+	QueryAnonymizeUser = `
+		UPDATE users SET name = '', email = NULL, phone_number = NULL, phone_country_code = '', password = '', username = NULL,
+		       deleted_at = COALESCE(deleted_at, NOW()), updated_at = NOW(), updated_by = COALESCE($3, updated_by), version = version + 1
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $2
+	`
+
+	// QueryCompleteDeletion clears the schedule of an anonymized user, the sweep is done with it
+	QueryCompleteDeletion = `
+		UPDATE users SET deletion_scheduled_at = NULL, updated_by = COALESCE($2, updated_by), version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	// QueryIterateUsers lists at most $6 users after id $1 in id order, a batch of
+	// UserRepository.Iterate. Empty/NULL parameters disable their condition: $2 includes the
+	// soft-deleted users, $3 name (partial, case-insensitive), $4 created_at lower bound,
+	// $5 created_at upper bound (exclusive)
+	QueryIterateUsers = `
+		SELECT id, account_number, name, username, email, phone_number, phone_country_code,
+		       created_at, updated_at, deleted_at, deletion_scheduled_at
+		FROM users
+		WHERE id > $1
+		  AND ($2 OR deleted_at IS NULL)
+		  AND ($3 = '' OR name ILIKE '%' || $3 || '%')
+		  AND ($4::timestamp IS NULL OR created_at >= $4::timestamp)
+		  AND ($5::timestamp IS NULL OR created_at < $5::timestamp)
+		ORDER BY id
+		LIMIT $6
+	`
+)
+
+// QueryListUsersColumns are the columns of UserRepository.List, built with From
+const QueryListUsersColumns = "id, account_number, name, created_at, updated_at"
+
+var (
+	// queryUserSearchFilter is the shared WHERE clause for searching users: $1 is the
+	// lowercased search, $2 the same escaped for LIKE. Names, usernames, and emails match when
+	// they resemble the search (pg_trgm similarity) or contain it, phone numbers when they
+	// contain it, and account numbers when they equal it.
+	queryUserSearchFilter = `
+		WHERE deleted_at IS NULL
+		  AND (name % $1 OR username % $1 OR email % $1
+		       OR name ILIKE '%' || $2 || '%'
+		       OR username LIKE '%' || $2 || '%'
+		       OR email ILIKE '%' || $2 || '%'
+		       OR phone_number LIKE '%' || $2 || '%'
+		       OR account_number = $1)
+	`
+
+	// QuerySearchUsers lists users matching the search, best match first: exact account
+	// numbers, usernames, and emails rank 1, others by their closest trigram similarity.
+	// $3 is LIMIT and $4 is OFFSET.
+	QuerySearchUsers = `
+		SELECT id, account_number, name, username, email, phone_number, phone_country_code,
+		       created_at, updated_at, deletion_scheduled_at,
+		       GREATEST(
+		           similarity(name, $1), similarity(username, $1), similarity(email, $1),
+		           CASE WHEN account_number = $1 OR username = $1 OR LOWER(email) = $1 THEN 1::real ELSE 0::real END
+		       ) AS rank
+		FROM users
+	` + queryUserSearchFilter + `
+		ORDER BY rank DESC, id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	// QueryCountSearchUsers counts users matching the same search as QuerySearchUsers
+	QueryCountSearchUsers = `
+		SELECT COUNT(*) FROM users
+	` + queryUserSearchFilter
 )
+
+// userSortColumns whitelists the columns that can be used in ORDER BY
+var userSortColumns = SortColumns{
+	"name":       "name",
+	"created_at": "created_at",
+}
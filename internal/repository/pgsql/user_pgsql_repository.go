@@ -3,6 +3,9 @@ package pgsql
 import (
 	"context"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/actor"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,8 +13,23 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	CheckByEmailOrPhoneNumber(ctx context.Context, email string, phoneNumber string) (bool, error)
+	CheckByAccountNumber(ctx context.Context, accountNumber string) (bool, error)
+	CheckByUsername(ctx context.Context, username string) (bool, error)
 	GetCredentialsByEmailOrPhoneNumber(ctx context.Context, email string, phoneNumber string) (*models.User, error)
+	GetCredentialsByUsername(ctx context.Context, username string) (*models.User, error)
 	GetOneByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error)
+	List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error)
+	Search(ctx context.Context, filter models.UserSearchFilter) ([]models.UserSearchResult, int, error)
+	Iterate(ctx context.Context, filter models.UserIterationFilter, batchSize int, fn func(user *models.User) error) error
+	UpdateProfile(ctx context.Context, id, version int, name string) error
+	UpdatePassword(ctx context.Context, id int, hashedPassword string) error
+	UpdateEmail(ctx context.Context, id int, email string) error
+	UpdatePhoneNumber(ctx context.Context, id int, phoneNumber, countryCode string) error
+	ScheduleDeletion(ctx context.Context, id int, at time.Time) error
+	CancelDeletion(ctx context.Context, id int) error
+	ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error)
+	Anonymize(ctx context.Context, id int, now time.Time) (bool, error)
+	CompleteDeletion(ctx context.Context, id int) error
 }
 
 type userRepository struct {
@@ -36,22 +54,205 @@ func (ur *userRepository) CheckByEmailOrPhoneNumber(ctx context.Context, email s
 	return exists, nil
 }
 
+func (ur *userRepository) CheckByAccountNumber(ctx context.Context, accountNumber string) (bool, error) {
+	var exists bool
+
+	if err := ur.db.WithContext(ctx).Raw(QueryCheckByAccountNumber, accountNumber).Scan(&exists).Error; err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// CheckByUsername includes soft-deleted users, like idx_users_username_unique
+func (ur *userRepository) CheckByUsername(ctx context.Context, username string) (bool, error) {
+	var exists bool
+
+	if err := ur.db.WithContext(ctx).Raw(QueryCheckByUsername, username).Scan(&exists).Error; err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetCredentialsByEmailOrPhoneNumber returns the user's credentials, nil when there is no such user
 func (ur *userRepository) GetCredentialsByEmailOrPhoneNumber(ctx context.Context, email string, phoneNumber string) (*models.User, error) {
-	var user models.User
+	var users []models.User
+
+	if err := ur.db.WithContext(ctx).Raw(QueryGetCredentialsByEmailOrPhoneNumber, email, phoneNumber).Scan(&users).Error; err != nil {
+		return nil, err
+	}
 
-	if err := ur.db.WithContext(ctx).Raw(QueryGetCredentialsByEmailOrPhoneNumber, email, phoneNumber).Scan(&user).Error; err != nil {
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+// GetCredentialsByUsername returns the user's credentials, nil when there is no such user
+func (ur *userRepository) GetCredentialsByUsername(ctx context.Context, username string) (*models.User, error) {
+	var users []models.User
+
+	if err := ur.db.WithContext(ctx).Raw(QueryGetCredentialsByUsername, username).Scan(&users).Error; err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
 }
 
+// GetOneByAccountNumber returns the user, nil when there is no such user
 func (ur *userRepository) GetOneByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
-	var user models.User
+	var users []models.User
+
+	if err := ur.db.WithContext(ctx).Raw(QueryGetByAccountNumber, accountNumber).Scan(&users).Error; err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+// List returns a page of users matching the filter along with the total number of matches
+func (ur *userRepository) List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error) {
+	query := From("users", QueryListUsersColumns).
+		Where("deleted_at IS NULL").
+		Filter(filter).
+		Sort(userSortColumns, filter.SortBy, "created_at", filter.SortDesc).
+		OrderBy("id", filter.SortDesc).
+		Page(filter.Limit, filter.Offset)
+
+	var total int
+	countSQL, countArgs := query.Count()
+	if err := ur.db.WithContext(ctx).Raw(countSQL, countArgs...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.User{}, 0, nil
+	}
+
+	var users []models.User
+	listSQL, listArgs := query.Build()
+	if err := ur.db.WithContext(ctx).Raw(listSQL, listArgs...).Scan(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// Search returns a page of users matching the search, best match first, along with the
+// total number of matches
+func (ur *userRepository) Search(ctx context.Context, filter models.UserSearchFilter) ([]models.UserSearchResult, int, error) {
+	pattern := escapeLike(filter.Query)
+
+	var total int
+	if err := ur.db.WithContext(ctx).Raw(QueryCountSearchUsers, filter.Query, pattern).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.UserSearchResult{}, 0, nil
+	}
+
+	var results []models.UserSearchResult
+	if err := ur.db.WithContext(ctx).Raw(QuerySearchUsers, filter.Query, pattern, filter.Limit, filter.Offset).Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// Iterate calls fn with the users matching the filter in ID order, reading batchSize users per
+// query, see Iterate
+func (ur *userRepository) Iterate(ctx context.Context, filter models.UserIterationFilter, batchSize int, fn func(user *models.User) error) error {
+	fetch := func(ctx context.Context, afterID, limit int) ([]models.User, error) {
+		var users []models.User
+		err := ur.db.WithContext(ctx).Raw(QueryIterateUsers,
+			afterID, filter.IncludeDeleted, filter.Name, filter.CreatedFrom, filter.CreatedTo, limit,
+		).Scan(&users).Error
+		return users, err
+	}
+	return Iterate(ctx, batchSize, fetch, userID, fn)
+}
+
+// userID is the key users are iterated by
+func userID(user *models.User) int {
+	return user.ID
+}
+
+// escapeLike escapes the LIKE wildcards of s, so a search for "50%" matches it literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// UpdateProfile replaces the name of the user when it is still at version, see UpdateVersioned
+func (ur *userRepository) UpdateProfile(ctx context.Context, id, version int, name string) error {
+	return UpdateVersioned(ur.db.WithContext(ctx), &models.User{}, id, version, map[string]any{"name": name})
+}
 
-	if err := ur.db.WithContext(ctx).Raw(QueryGetByAccountNumber, accountNumber).Scan(&user).Error; err != nil {
+// UpdatePassword replaces the stored password hash of the user
+func (ur *userRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+	return ur.db.WithContext(ctx).Exec(QueryUpdatePassword, hashedPassword, id, updatedBy(ctx)).Error
+}
+
+// UpdateEmail replaces the email of the user. Emails taken by another user fail with the
+// unique index idx_users_email_unique.
+func (ur *userRepository) UpdateEmail(ctx context.Context, id int, email string) error {
+	return ur.db.WithContext(ctx).Exec(QueryUpdateEmail, email, id, updatedBy(ctx)).Error
+}
+
+// UpdatePhoneNumber replaces the phone number of the user. Numbers taken by another user fail
+// with the unique index idx_users_phone_number_unique (see IsUniqueViolation).
+func (ur *userRepository) UpdatePhoneNumber(ctx context.Context, id int, phoneNumber, countryCode string) error {
+	return ur.db.WithContext(ctx).Exec(QueryUpdatePhoneNumber, phoneNumber, countryCode, id, updatedBy(ctx)).Error
+}
+
+// ScheduleDeletion sets when the user is anonymized, ignoring soft-deleted users
+func (ur *userRepository) ScheduleDeletion(ctx context.Context, id int, at time.Time) error {
+	return ur.db.WithContext(ctx).Exec(QueryScheduleDeletion, at, id, updatedBy(ctx)).Error
+}
+
+// CancelDeletion clears the scheduled deletion of the user, unless it was carried out
+func (ur *userRepository) CancelDeletion(ctx context.Context, id int) error {
+	return ur.db.WithContext(ctx).Exec(QueryCancelDeletion, id, updatedBy(ctx)).Error
+}
+
+// ListDueForDeletion returns the users whose deletion is due at now, the longest due first
+func (ur *userRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	users := []models.User{}
+
+	if err := ur.db.WithContext(ctx).Raw(QueryListDueForDeletion, now, limit).Scan(&users).Error; err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	return users, nil
+}
+
+// Anonymize removes the personal data of the user and soft-deletes it. It reports false
+// when the deletion is not due at now, e.g. it was cancelled in the meantime.
+func (ur *userRepository) Anonymize(ctx context.Context, id int, now time.Time) (bool, error) {
+	result := ur.db.WithContext(ctx).Exec(QueryAnonymizeUser, id, now, updatedBy(ctx))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CompleteDeletion clears the schedule of an anonymized user
+func (ur *userRepository) CompleteDeletion(ctx context.Context, id int) error {
+	return ur.db.WithContext(ctx).Exec(QueryCompleteDeletion, id, updatedBy(ctx)).Error
+}
+
+// updatedBy is the updated_by of the raw UPDATE queries, which the audit callbacks don't see:
+// the actor of ctx, nil to keep the column (see database.RegisterAuditCallbacks)
+func updatedBy(ctx context.Context) *string {
+	if id, ok := actor.FromContext(ctx); ok {
+		return &id
+	}
+	return nil
 }
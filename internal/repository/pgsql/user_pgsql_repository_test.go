@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/actor"
 	"go-echo-boilerplate/internal/repository/pgsql"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -43,6 +44,7 @@ func TestUserCreate(t *testing.T) {
 
 		user := &models.User{
 			Name:             "John Doe",
+			Username:         strPtr("john.doe"),
 			Email:            strPtr("john@example.com"),
 			PhoneNumber:      strPtr("123456789"),
 			PhoneCountryCode: "+62",
@@ -57,6 +59,7 @@ func TestUserCreate(t *testing.T) {
 			WithArgs(
 				user.AccountNumber,
 				user.Name,
+				user.Username,
 				user.Email,
 				user.PhoneNumber,
 				user.PhoneCountryCode,
@@ -64,6 +67,10 @@ func TestUserCreate(t *testing.T) {
 				sqlmock.AnyArg(), // CreatedAt
 				sqlmock.AnyArg(), // UpdatedAt
 				sqlmock.AnyArg(), // DeletedAt
+				sqlmock.AnyArg(), // DeletionScheduledAt
+				1,                // Version
+				sqlmock.AnyArg(), // CreatedBy
+				sqlmock.AnyArg(), // UpdatedBy
 			).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		mock.ExpectCommit()
@@ -172,3 +179,407 @@ func TestCheckByEmailOrPhoneNumber(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestUserList(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.UserRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewUserRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	t.Run("List Users Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		filter := models.UserFilter{Name: "john", SortBy: "name", SortDesc: true, Limit: 10, Offset: 10}
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users WHERE (deleted_at IS NULL) AND (name ILIKE '%' || $1 || '%')`)).
+			WithArgs("john").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+		mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY name DESC, id DESC LIMIT $2 OFFSET $3`)).
+			WithArgs("john", 10, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "name", "created_at", "updated_at"}).
+				AddRow(11, "12345", "John Doe", time.Now(), time.Now()))
+
+		users, total, err := repo.List(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, total)
+		assert.Len(t, users, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown Sort Falls Back To Created At", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY created_at ASC, id ASC`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		_, _, err := repo.List(context.Background(), models.UserFilter{SortBy: "password; DROP TABLE users", Limit: 20})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Empty Result Skips Select", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		users, total, err := repo.List(context.Background(), models.UserFilter{Limit: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, users)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Count Error", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WillReturnError(errors.New("db error"))
+
+		_, _, err := repo.List(context.Background(), models.UserFilter{Limit: 20})
+		assert.Error(t, err)
+	})
+}
+
+func TestUserSearch(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.UserRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		repo := pgsql.NewUserRepository(gormDB)
+
+		return repo, mock, func() {
+			db.Close()
+		}
+	}
+
+	t.Run("Search Users Success", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		filter := models.UserSearchFilter{Query: "50%_off", Limit: 10, Offset: 10}
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WithArgs("50%_off", `50\%\_off`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+		mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY rank DESC, id ASC`)).
+			WithArgs("50%_off", `50\%\_off`, 10, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "name", "email", "rank"}).
+				AddRow(11, "12345", "50% Off Deals", "deals@example.com", 0.42))
+
+		results, total, err := repo.Search(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, total)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "12345", results[0].AccountNumber)
+			assert.Equal(t, "deals@example.com", *results[0].Email)
+			assert.InDelta(t, 0.42, results[0].Rank, 0.001)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Empty Result Skips Select", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		results, total, err := repo.Search(context.Background(), models.UserSearchFilter{Query: "nobody", Limit: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.NotNil(t, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserIterate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := models.UserIterationFilter{Name: "john", CreatedFrom: &from, IncludeDeleted: true}
+
+	columns := []string{"id", "account_number", "name"}
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id > $1`)).WithArgs(0, true, "john", &from, nil, 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "111", "A").AddRow(4, "444", "B"))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id > $1`)).WithArgs(4, true, "john", &from, nil, 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, "777", "C"))
+
+	var accountNumbers []string
+	err = pgsql.NewUserRepository(gormDB).Iterate(context.Background(), filter, 2, func(user *models.User) error {
+		accountNumbers = append(accountNumbers, user.AccountNumber)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"111", "444", "777"}, accountNumbers)
+	assert.NoError(t, mock.ExpectationsWereMet(), "a short batch ends the walk")
+}
+
+func TestUserGet(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.UserRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		return pgsql.NewUserRepository(gormDB), mock, func() { db.Close() }
+	}
+
+	t.Run("Get Credentials", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_number, name, email, phone_number, phone_country_code, password`)).
+			WithArgs("john@example.com", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "email", "password"}).
+				AddRow(7, "12345", "john@example.com", "hash"))
+
+		user, err := repo.GetCredentialsByEmailOrPhoneNumber(context.Background(), "john@example.com", "")
+		assert.NoError(t, err)
+		if assert.NotNil(t, user) {
+			assert.Equal(t, 7, user.ID)
+			assert.Equal(t, "hash", user.Password)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("By Username", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM users
+			WHERE username = $1`)).
+			WithArgs("john.doe").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE username = $1`)).
+			WithArgs("john.doe").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "username", "password"}).
+				AddRow(7, "12345", "john.doe", "hash"))
+
+		exists, err := repo.CheckByUsername(context.Background(), "john.doe")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		user, err := repo.GetCredentialsByUsername(context.Background(), "john.doe")
+		assert.NoError(t, err)
+		if assert.NotNil(t, user) {
+			assert.Equal(t, "john.doe", *user.Username)
+			assert.Equal(t, "hash", user.Password)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Missing Users Are Nil", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, account_number, name, email, phone_number, phone_country_code, password`)).
+			WithArgs("john@example.com", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE account_number = $1`)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		user, err := repo.GetCredentialsByEmailOrPhoneNumber(context.Background(), "john@example.com", "")
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+
+		user, err = repo.GetOneByAccountNumber(context.Background(), "12345")
+		assert.NoError(t, err)
+		assert.Nil(t, user)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Cancelled With The Context", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE account_number = $1`)).
+			WithArgs("12345").
+			WillDelayFor(time.Minute).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		_, err := repo.GetOneByAccountNumber(ctx, "12345")
+		assert.Error(t, err)
+		assert.Less(t, time.Since(started), time.Second, "the query does not outlive the request")
+	})
+}
+
+func TestUserUpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := pgsql.NewUserRepository(gormDB)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET password = $1, updated_at = NOW(), updated_by = COALESCE($3, updated_by)`)).
+		WithArgs("newhash", 7, "12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdatePassword(actor.WithActor(context.Background(), "12345"), 7, "newhash")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserUpdateEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := pgsql.NewUserRepository(gormDB)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET email = $1, updated_at = NOW(), updated_by = COALESCE($3, updated_by)`)).
+		WithArgs("johnny@example.com", 7, nil). // no actor keeps updated_by
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateEmail(context.Background(), 7, "johnny@example.com")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserUpdatePhoneNumber(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := pgsql.NewUserRepository(gormDB)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET phone_number = $1, phone_country_code = $2, updated_at = NOW(), updated_by = COALESCE($4, updated_by)`)).
+		WithArgs("+6281234567890", "ID", 7, "12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdatePhoneNumber(actor.WithActor(context.Background(), "12345"), 7, "+6281234567890", "ID")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserDeletion(t *testing.T) {
+	setup := func(t *testing.T) (pgsql.UserRepository, sqlmock.Sqlmock, func()) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+
+		return pgsql.NewUserRepository(gormDB), mock, func() {
+			db.Close()
+		}
+	}
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Schedule And Cancel", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET deletion_scheduled_at = $1, updated_at = NOW(), updated_by = COALESCE($3, updated_by)`)).
+			WithArgs(now, 7, "12345").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET deletion_scheduled_at = NULL, updated_at = NOW(), updated_by = COALESCE($2, updated_by)`)).
+			WithArgs(7, actor.Admin).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.ScheduleDeletion(actor.WithActor(context.Background(), "12345"), 7, now))
+		assert.NoError(t, repo.CancelDeletion(actor.WithActor(context.Background(), actor.Admin), 7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("List Due", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1`)).
+			WithArgs(now, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_number", "deletion_scheduled_at"}).
+				AddRow(7, "12345", now.Add(-time.Hour)))
+
+		users, err := repo.ListDueForDeletion(context.Background(), now, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, users, 1) {
+			assert.Equal(t, "12345", users[0].AccountNumber)
+			assert.NotNil(t, users[0].DeletionScheduledAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Anonymize Reports Whether It Was Due", func(t *testing.T) {
+		repo, mock, teardown := setup(t)
+		defer teardown()
+
+		// The sweep has no actor, updated_by stays that of the scheduling
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET name = '', email = NULL, phone_number = NULL`)).
+			WithArgs(7, now, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`updated_by = COALESCE($3, updated_by)`)).
+			WithArgs(8, now, nil).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET deletion_scheduled_at = NULL, updated_by = COALESCE($2, updated_by)`)).
+			WithArgs(7, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		anonymized, err := repo.Anonymize(context.Background(), 7, now)
+		assert.NoError(t, err)
+		assert.True(t, anonymized)
+
+		anonymized, err = repo.Anonymize(context.Background(), 8, now)
+		assert.NoError(t, err)
+		assert.False(t, anonymized, "the deletion was cancelled")
+
+		assert.NoError(t, repo.CompleteDeletion(context.Background(), 7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
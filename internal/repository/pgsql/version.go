@@ -0,0 +1,54 @@
+package pgsql
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"gorm.io/gorm"
+)
+
+// VersionConflictError is returned by UpdateVersioned when the row is no longer at the
+// version the caller read, because another update got there first (or the row is gone)
+type VersionConflictError struct {
+	Table   string
+	ID      int
+	Version int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("pgsql: %s %d is no longer at version %d", e.Table, e.ID, e.Version)
+}
+
+// IsVersionConflict reports whether err is a *VersionConflictError
+func IsVersionConflict(err error) bool {
+	var conflict *VersionConflictError
+	return errors.As(err, &conflict)
+}
+
+// UpdateVersioned sets columns of the row of model (e.g. &models.User{}) with the id when it is
+// still at version, for optimistic locking. Versioned tables have a "version INTEGER NOT NULL
+// DEFAULT 1" column that every UPDATE of them bumps, the raw queries included (SET version =
+// version + 1); UpdateVersioned bumps it and touches updated_at too. Callers pass the version
+// they read, usually echoed by the client, and get a *VersionConflictError when the row
+// changed since. Being a gorm update of the model, it runs the update callbacks, e.g. the
+// updated_by of models.Audit.
+//
+// Usage:
+//
+//	err := pgsql.UpdateVersioned(ur.db.WithContext(ctx), &models.User{}, id, version, map[string]any{"name": name})
+func UpdateVersioned(db *gorm.DB, model any, id, version int, columns map[string]any) error {
+	values := make(map[string]any, len(columns)+2)
+	maps.Copy(values, columns)
+	values["version"] = gorm.Expr("version + 1")
+	values["updated_at"] = gorm.Expr("NOW()")
+
+	result := db.Model(model).Where("id = ? AND version = ?", id, version).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &VersionConflictError{Table: result.Statement.Table, ID: id, Version: version}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package pgsql_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/repository/pgsql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestUpdateVersioned(t *testing.T) {
+	setup := func(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		gormDB, err := gorm.Open(postgres.New(postgres.Config{
+			Conn: db,
+		}), &gorm.Config{})
+		assert.NoError(t, err)
+		return gormDB, mock
+	}
+	update := regexp.QuoteMeta(`UPDATE "users" SET "name"=$1,"updated_at"=NOW(),"version"=version + 1 WHERE id = $2 AND version = $3`)
+
+	t.Run("Bumps The Version", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("John", 7, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		columns := map[string]any{"name": "John"}
+		assert.NoError(t, pgsql.UpdateVersioned(db, &models.User{}, 7, 3, columns))
+		assert.Len(t, columns, 1, "the columns of the caller are left as is")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Stale Version Conflicts", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("John", 7, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := pgsql.UpdateVersioned(db, &models.User{}, 7, 3, map[string]any{"name": "John"})
+
+		var conflict *pgsql.VersionConflictError
+		if assert.ErrorAs(t, err, &conflict) {
+			assert.Equal(t, pgsql.VersionConflictError{Table: "users", ID: 7, Version: 3}, *conflict)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		db, mock := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := pgsql.UpdateVersioned(db, &models.User{}, 7, 3, map[string]any{"name": "John"})
+		assert.Error(t, err)
+		assert.False(t, pgsql.IsVersionConflict(err))
+	})
+}
+
+func TestIsVersionConflict(t *testing.T) {
+	assert.True(t, pgsql.IsVersionConflict(fmt.Errorf("update: %w", &pgsql.VersionConflictError{Table: "users", ID: 1, Version: 1})))
+	assert.False(t, pgsql.IsVersionConflict(errors.New("connection refused")))
+}
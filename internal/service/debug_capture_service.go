@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/capture"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"time"
+)
+
+// DefaultDebugCaptureLimit is the number of captures listed when none is requested
+const DefaultDebugCaptureLimit = 50
+
+type DebugCaptureService interface {
+	CreateToken(ctx context.Context, request *models.CreateDebugCaptureTokenRequest) (*models.DebugCaptureTokenResponse, error)
+	ListCaptures(ctx context.Context, limit int) ([]models.DebugCapture, error)
+	GetCapture(ctx context.Context, id int64) (*models.DebugCaptureResponse, error)
+}
+
+type debugCaptureService struct {
+	d *Dependencies
+}
+
+func NewDebugCaptureService(d *Dependencies) DebugCaptureService {
+	return &debugCaptureService{d: d}
+}
+
+// CreateToken returns a token capturing the requests sending it in the X-Debug-Capture
+// header until it expires, e.g. for a customer reproducing an issue.
+func (ds *debugCaptureService) CreateToken(ctx context.Context, request *models.CreateDebugCaptureTokenRequest) (*models.DebugCaptureTokenResponse, error) {
+	logger.Add(ctx, "operation", "debug_capture_create_token")
+
+	capturer := capture.Default()
+	if capturer == nil {
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Debug capture is not enabled")
+	}
+
+	token, expiresAt, err := capturer.Token(request.Label)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "GenerationError",
+			Code:      "DEBUG_CAPTURE_TOKEN_FAILED",
+			Message:   err.Error(),
+			Retriable: false,
+		})
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Failed to create the debug capture token")
+	}
+	return &models.DebugCaptureTokenResponse{Header: capture.Header, Token: token, Label: request.Label, ExpiresAt: expiresAt}, nil
+}
+
+// ListCaptures returns up to limit captures that did not expire, the latest first, without
+// their request and response.
+func (ds *debugCaptureService) ListCaptures(ctx context.Context, limit int) ([]models.DebugCapture, error) {
+	if limit <= 0 {
+		limit = DefaultDebugCaptureLimit
+	}
+
+	captures, err := ds.d.Repository.Postgre.DebugCapture.List(ctx, limit)
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to list debug captures")
+	}
+
+	now := time.Now()
+	active := captures[:0]
+	for _, capture := range captures {
+		if capture.ExpiresAt.After(now) {
+			active = append(active, capture)
+		}
+	}
+	return active, nil
+}
+
+// GetCapture returns a capture that did not expire, with its request and response.
+func (ds *debugCaptureService) GetCapture(ctx context.Context, id int64) (*models.DebugCaptureResponse, error) {
+	capture, err := ds.d.Repository.Postgre.DebugCapture.Get(ctx, id)
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to get the debug capture")
+	}
+	if capture == nil || !capture.ExpiresAt.After(time.Now()) {
+		return nil, errorc.Error(errorc.ErrorDataNotFound, "Debug capture not found")
+	}
+
+	response, err := capture.DebugCaptureResponse()
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Failed to decode the debug capture")
+	}
+	return &response, nil
+}
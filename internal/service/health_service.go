@@ -4,6 +4,18 @@ import (
 	"context"
 	"fmt"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/leader"
+	"go-echo-boilerplate/internal/pkg/limiter"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults for the health.* thresholds left unset
+const (
+	defaultHealthMaxPoolUsage      = 0.9
+	defaultHealthMaxPoolWait       = 100 * time.Millisecond
+	defaultHealthMaxReplicationLag = 10 * time.Second
 )
 
 type HealthService interface {
@@ -19,27 +31,12 @@ func NewHealthService(d *Dependencies) HealthService {
 }
 
 func (hs *healthService) Check(ctx context.Context) (*models.HealthResponse, error) {
-	var healthDetail []models.HealthDetailResponse
-
-	var postgreHealth models.HealthDetailResponse
-	postgreErr := hs.d.Repository.Postgre.Health.Check(ctx)
-	if postgreErr != nil {
-		postgreHealth = models.HealthDetailResponse{
-			Type:        models.TYPE_HEALTH,
-			Component:   "PostgreSQL",
-			Status:      "ERROR",
-			Description: fmt.Sprintf("PostgreSQL is not healthy, due to %v", postgreErr),
-		}
-
-		healthDetail = append(healthDetail, postgreHealth)
-	} else {
-		postgreHealth = models.HealthDetailResponse{
-			Type:      models.TYPE_HEALTH,
-			Component: "PostgreSQL",
-			Status:    "OK",
-		}
-
-		healthDetail = append(healthDetail, postgreHealth)
+	healthDetail := []models.HealthDetailResponse{hs.checkPostgreSQL(ctx)}
+	if elector := leader.Default(); elector != nil {
+		healthDetail = append(healthDetail, checkLeader(elector))
+	}
+	if limits := limiter.Default(); limits != nil {
+		healthDetail = append(healthDetail, checkConcurrency(limits))
 	}
 
 	return &models.HealthResponse{
@@ -47,3 +44,149 @@ func (hs *healthService) Check(ctx context.Context) (*models.HealthResponse, err
 		Dependencies: healthDetail,
 	}, nil
 }
+
+// checkPostgreSQL pings PostgreSQL and reports its connection pool and replicas, as WARN past
+// the health.* thresholds or when the figures are unavailable
+func (hs *healthService) checkPostgreSQL(ctx context.Context) models.HealthDetailResponse {
+	postgreHealth := models.HealthDetailResponse{
+		Type:      models.TYPE_HEALTH,
+		Component: "PostgreSQL",
+		Status:    models.HEALTH_OK,
+	}
+
+	if err := hs.d.Repository.Postgre.Health.Check(ctx); err != nil {
+		postgreHealth.Status = models.HEALTH_ERROR
+		postgreHealth.Description = fmt.Sprintf("PostgreSQL is not healthy, due to %v", err)
+		return postgreHealth
+	}
+
+	stats, err := hs.d.Repository.Postgre.Health.Stats(ctx)
+	if err != nil {
+		postgreHealth.Status = models.HEALTH_WARN
+		postgreHealth.Description = fmt.Sprintf("PostgreSQL statistics are unavailable, due to %v", err)
+		return postgreHealth
+	}
+	postgreHealth.Pool = stats.HealthPoolResponse()
+	postgreHealth.Replication = stats.HealthReplicationResponse()
+
+	var warnings []string
+	pool := stats.Pool
+	if pool.MaxOpenConnections > 0 && float64(pool.InUse) >= hs.maxPoolUsage()*float64(pool.MaxOpenConnections) {
+		warnings = append(warnings, fmt.Sprintf("%d of %d connections in use", pool.InUse, pool.MaxOpenConnections))
+	}
+	if pool.WaitCount > 0 {
+		if wait := pool.WaitDuration / time.Duration(pool.WaitCount); wait >= hs.maxPoolWait() {
+			warnings = append(warnings, fmt.Sprintf("connections waited for %v on average", wait.Round(time.Millisecond)))
+		}
+	}
+	if stats.Replicas > 0 && stats.ReplicationLag >= hs.maxReplicationLag() {
+		warnings = append(warnings, fmt.Sprintf("replicas lag %v behind", stats.ReplicationLag.Round(time.Millisecond)))
+	}
+
+	if len(warnings) > 0 {
+		postgreHealth.Status = models.HEALTH_WARN
+		postgreHealth.Description = "PostgreSQL is degraded: " + strings.Join(warnings, ", ")
+	}
+	return postgreHealth
+}
+
+// checkLeader reports the leadership of the instance, as WARN when the election cannot reach
+// the lock store: a follower cannot take over then, and a leader may be about to lose it
+func checkLeader(elector *leader.Elector) models.HealthDetailResponse {
+	status := elector.Status()
+	leaderHealth := models.HealthDetailResponse{
+		Type:        models.TYPE_HEALTH,
+		Component:   "Leader",
+		Status:      models.HEALTH_OK,
+		Description: "Following",
+		Leader:      &models.HealthLeaderResponse{Election: elector.Name(), Leading: status.Leading, Since: status.Since},
+	}
+	if status.Leading {
+		leaderHealth.Description = "Leading"
+	}
+
+	if status.Err != nil {
+		leaderHealth.Status = models.HEALTH_WARN
+		leaderHealth.Description = fmt.Sprintf("Leader election is failing, due to %v", status.Err)
+	}
+	return leaderHealth
+}
+
+// checkConcurrency reports the saturation of the concurrency limits, as WARN while requests
+// queue for a slot of one of them
+func checkConcurrency(limits *limiter.Limits) models.HealthDetailResponse {
+	concurrencyHealth := models.HealthDetailResponse{
+		Type:        models.TYPE_HEALTH,
+		Component:   "Concurrency",
+		Status:      models.HEALTH_OK,
+		Description: "Requests are served without waiting",
+	}
+
+	stats := limits.Stats()
+	routes := make([]string, 0, len(stats))
+	for route := range stats {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes) // "*" first
+
+	var saturated []string
+	for _, route := range routes {
+		s := stats[route]
+		var avgWait int64
+		if s.Admitted > 0 {
+			avgWait = (s.Waited / time.Duration(s.Admitted)).Milliseconds()
+		}
+		concurrencyHealth.Concurrency = append(concurrencyHealth.Concurrency, models.HealthConcurrencyResponse{
+			Route:     route,
+			Limit:     s.Limit,
+			InFlight:  s.InFlight,
+			Waiting:   s.Waiting,
+			Admitted:  s.Admitted,
+			Shed:      s.Shed,
+			AvgWaitMs: avgWait,
+		})
+		if s.Waiting > 0 {
+			saturated = append(saturated, fmt.Sprintf("%d waiting for %s", s.Waiting, route))
+		}
+	}
+
+	if len(saturated) > 0 {
+		concurrencyHealth.Status = models.HEALTH_WARN
+		concurrencyHealth.Description = "Saturated: " + strings.Join(saturated, ", ")
+	}
+	return concurrencyHealth
+}
+
+// maxPoolUsage returns health.max_pool_usage
+func (hs *healthService) maxPoolUsage() float64 {
+	if hs.d.Config == nil || hs.d.Config.Health.MaxPoolUsage == 0 {
+		return defaultHealthMaxPoolUsage
+	}
+	return hs.d.Config.Health.MaxPoolUsage
+}
+
+// maxPoolWait returns health.max_pool_wait
+func (hs *healthService) maxPoolWait() time.Duration {
+	if hs.d.Config == nil {
+		return defaultHealthMaxPoolWait
+	}
+	return healthThreshold(hs.d.Config.Health.MaxPoolWait, defaultHealthMaxPoolWait)
+}
+
+// maxReplicationLag returns health.max_replication_lag
+func (hs *healthService) maxReplicationLag() time.Duration {
+	if hs.d.Config == nil {
+		return defaultHealthMaxReplicationLag
+	}
+	return healthThreshold(hs.d.Config.Health.MaxReplicationLag, defaultHealthMaxReplicationLag)
+}
+
+// healthThreshold parses a health.* duration, fallback when unset
+func healthThreshold(value string, fallback time.Duration) time.Duration {
+	// Validated at startup
+	threshold, err := time.ParseDuration(value)
+	if value == "" || err != nil {
+		return fallback
+	}
+	return threshold
+}
@@ -2,14 +2,22 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/leader"
+	"go-echo-boilerplate/internal/pkg/limiter"
+	"go-echo-boilerplate/internal/pkg/lock"
 	"go-echo-boilerplate/internal/repository"
 	"go-echo-boilerplate/internal/repository/pgsql"
 	"go-echo-boilerplate/internal/service"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockHealthRepository
@@ -22,10 +30,21 @@ func (m *MockHealthRepository) Check(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockHealthRepository) Stats(ctx context.Context) (*models.PostgreSQLStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostgreSQLStats), args.Error(1)
+}
+
 func TestHealthService_Check(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockHealthRepository)
 		mockRepo.On("Check", mock.Anything).Return(nil)
+		mockRepo.On("Stats", mock.Anything).Return(&models.PostgreSQLStats{
+			Pool: sql.DBStats{MaxOpenConnections: 100, OpenConnections: 12, InUse: 2, Idle: 10},
+		}, nil)
 
 		// Construct Dependencies
 		deps := service.Dependencies{
@@ -43,6 +62,8 @@ func TestHealthService_Check(t *testing.T) {
 		assert.Equal(t, "Service is healthy", resp.Description)
 		assert.Len(t, resp.Dependencies, 1) // Expecting Postgre result
 		assert.Equal(t, "OK", resp.Dependencies[0].Status)
+		assert.Equal(t, &models.HealthPoolResponse{MaxOpen: 100, Open: 12, Idle: 10, InUse: 2}, resp.Dependencies[0].Pool)
+		assert.Nil(t, resp.Dependencies[0].Replication, "no replicas")
 
 		mockRepo.AssertExpectations(t)
 	})
@@ -71,4 +92,138 @@ func TestHealthService_Check(t *testing.T) {
 
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("PostgreSQL Degraded", func(t *testing.T) {
+		check := func(cfg *config.Configuration, stats *models.PostgreSQLStats, statsErr error) models.HealthDetailResponse {
+			mockRepo := new(MockHealthRepository)
+			mockRepo.On("Check", mock.Anything).Return(nil)
+			mockRepo.On("Stats", mock.Anything).Return(stats, statsErr)
+
+			svc := service.NewHealthService(&service.Dependencies{
+				Config:     cfg,
+				Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{Health: mockRepo}},
+			})
+			resp, err := svc.Check(context.Background())
+			assert.NoError(t, err)
+			return resp.Dependencies[0]
+		}
+
+		busy := check(nil, &models.PostgreSQLStats{
+			Pool: sql.DBStats{MaxOpenConnections: 10, OpenConnections: 10, InUse: 9, WaitCount: 4, WaitDuration: time.Second},
+		}, nil)
+		assert.Equal(t, "WARN", busy.Status)
+		assert.Contains(t, busy.Description, "9 of 10 connections in use")
+		assert.Contains(t, busy.Description, "connections waited for 250ms on average")
+
+		lagging := check(nil, &models.PostgreSQLStats{Replicas: 2, ReplicationLag: 15 * time.Second}, nil)
+		assert.Equal(t, "WARN", lagging.Status)
+		assert.Equal(t, &models.HealthReplicationResponse{Replicas: 2, LagMs: 15000}, lagging.Replication)
+
+		cfg := &config.Configuration{}
+		cfg.Health.MaxReplicationLag = "30s"
+		assert.Equal(t, "OK", check(cfg, &models.PostgreSQLStats{Replicas: 2, ReplicationLag: 15 * time.Second}, nil).Status,
+			"within health.max_replication_lag")
+
+		unavailable := check(nil, nil, errors.New("permission denied"))
+		assert.Equal(t, "WARN", unavailable.Status)
+		assert.Contains(t, unavailable.Description, "permission denied")
+	})
+	t.Run("Leader", func(t *testing.T) {
+		t.Cleanup(func() { leader.SetDefault(nil) })
+		check := func(locker lock.Locker) models.HealthDetailResponse {
+			mockRepo := new(MockHealthRepository)
+			mockRepo.On("Check", mock.Anything).Return(nil)
+			mockRepo.On("Stats", mock.Anything).Return(&models.PostgreSQLStats{}, nil)
+
+			elector := leader.New(locker, "jobs", time.Minute)
+			leader.SetDefault(elector)
+			ctx, cancel := context.WithCancel(context.Background())
+			go elector.Start(ctx)
+			defer cancel()
+			time.Sleep(20 * time.Millisecond)
+
+			svc := service.NewHealthService(&service.Dependencies{
+				Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{Health: mockRepo}},
+			})
+			resp, err := svc.Check(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, resp.Dependencies, 2)
+			return resp.Dependencies[1]
+		}
+
+		leading := check(stubLocker{})
+		assert.Equal(t, "OK", leading.Status)
+		assert.Equal(t, "Leading", leading.Description)
+		assert.True(t, leading.Leader.Leading)
+		assert.Equal(t, "jobs", leading.Leader.Election)
+
+		failing := check(stubLocker{err: errors.New("connection refused")})
+		assert.Equal(t, "WARN", failing.Status)
+		assert.Contains(t, failing.Description, "connection refused")
+		assert.False(t, failing.Leader.Leading)
+	})
+	t.Run("Concurrency", func(t *testing.T) {
+		t.Cleanup(func() { limiter.SetDefault(nil) })
+		limits, err := limiter.FromConfig(config.Concurrency{
+			MaxInFlight:  1,
+			QueueTimeout: "1s",
+			Routes:       []config.ConcurrencyRoute{{Route: "GET /api/v1/admin/users/export", MaxInFlight: 2}},
+		})
+		require.NoError(t, err)
+		limiter.SetDefault(limits)
+
+		check := func() models.HealthDetailResponse {
+			mockRepo := new(MockHealthRepository)
+			mockRepo.On("Check", mock.Anything).Return(nil)
+			mockRepo.On("Stats", mock.Anything).Return(&models.PostgreSQLStats{}, nil)
+			svc := service.NewHealthService(&service.Dependencies{
+				Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{Health: mockRepo}},
+			})
+			resp, err := svc.Check(context.Background())
+			require.NoError(t, err)
+			require.Len(t, resp.Dependencies, 2)
+			return resp.Dependencies[1]
+		}
+
+		release, _, _, err := limits.Acquire(context.Background(), "GET /api/v1/users")
+		require.NoError(t, err)
+		idle := check()
+		assert.Equal(t, "OK", idle.Status)
+		if assert.Len(t, idle.Concurrency, 2) {
+			assert.Equal(t, models.HealthConcurrencyResponse{Route: "*", Limit: 1, InFlight: 1, Admitted: 1}, idle.Concurrency[0])
+			assert.Equal(t, "GET /api/v1/admin/users/export", idle.Concurrency[1].Route)
+		}
+
+		queued := make(chan struct{})
+		go func() {
+			defer close(queued)
+			if release, _, _, err := limits.Acquire(context.Background(), "GET /api/v1/users"); err == nil {
+				release()
+			}
+		}()
+		require.Eventually(t, func() bool { return limits.Stats()[limiter.Global].Waiting == 1 }, time.Second, time.Millisecond)
+		saturated := check()
+		assert.Equal(t, "WARN", saturated.Status)
+		assert.Contains(t, saturated.Description, "1 waiting for *")
+
+		release()
+		<-queued
+	})
+}
+
+// stubLocker grants every lock, or fails with err
+type stubLocker struct {
+	err error
 }
+
+func (l stubLocker) TryLock(context.Context, string) (lock.Lock, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return stubLock{}, nil
+}
+
+type stubLock struct{}
+
+func (stubLock) Lost() <-chan struct{}        { return nil }
+func (stubLock) Unlock(context.Context) error { return nil }
@@ -2,8 +2,12 @@ package service
 
 import (
 	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/pkg/di"
+	"go-echo-boilerplate/internal/pkg/hashc"
 	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/notify"
 	"go-echo-boilerplate/internal/pkg/openauth"
+	"go-echo-boilerplate/internal/pkg/validator"
 	"go-echo-boilerplate/internal/repository"
 )
 
@@ -12,16 +16,69 @@ type Dependencies struct {
 	OAuth      openauth.OAuth
 	Config     *config.Configuration
 	JWTConfig  *jwtc.Configuration
+	HashConfig *hashc.Configuration
+
+	// PasswordBreach is nil when password.breach_check is disabled
+	PasswordBreach validator.PasswordBreachChecker
+
+	// DisposableDomains is nil when no email domain is refused
+	DisposableDomains *validator.DomainBlocklist
+
+	// Notifications is nil when no notification channel is configured
+	Notifications *notify.Queue
 }
 
 type Service struct {
-	Health HealthService
-	User   UserService
+	Health       HealthService
+	User         UserService
+	Notification NotificationService
+	Quota        QuotaService
+	DebugCapture DebugCaptureService
 }
 
-func New(d Dependencies) *Service {
-	return &Service{
-		Health: NewHealthService(&d),
-		User:   NewUserService(&d),
-	}
+// Provide registers the shared services, built on the repositories and configurations of
+// the container. Service.User is provided by the users module (internal/modules/users);
+// features added as a core.Module provide their own services instead.
+func Provide(c *di.Container) {
+	di.Provide(c, func(
+		repository *repository.Repository,
+		config *config.Configuration,
+		jwtConfig *jwtc.Configuration,
+		hashConfig *hashc.Configuration,
+		passwordBreach validator.PasswordBreachChecker,
+		disposableDomains *validator.DomainBlocklist,
+		notifications *notify.Queue,
+	) *Dependencies {
+		return &Dependencies{
+			Repository: *repository,
+			// OAuth:      *oa,
+			Config:            config,
+			JWTConfig:         jwtConfig,
+			HashConfig:        hashConfig,
+			PasswordBreach:    passwordBreach,
+			DisposableDomains: disposableDomains,
+			Notifications:     notifications,
+		}
+	})
+
+	di.Provide(c, NewHealthService)
+	di.Provide(c, NewNotificationService)
+	di.Provide(c, NewQuotaService)
+	di.Provide(c, NewDebugCaptureService)
+
+	di.Provide(c, func(
+		health HealthService,
+		user UserService,
+		notification NotificationService,
+		quota QuotaService,
+		debugCapture DebugCaptureService,
+	) *Service {
+		return &Service{
+			Health:       health,
+			User:         user,
+			Notification: notification,
+			Quota:        quota,
+			DebugCapture: debugCapture,
+		}
+	})
 }
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/notify"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"time"
+)
+
+type NotificationService interface {
+	GetPreferences(ctx context.Context, accountNumber string) (*models.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, accountNumber string, request *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferences, error)
+	ListDeadLetters(ctx context.Context, limit int) ([]models.NotificationDeadLetter, error)
+	RetryDeadLetter(ctx context.Context, id int64) error
+}
+
+type notificationService struct {
+	d *Dependencies
+}
+
+func NewNotificationService(d *Dependencies) NotificationService {
+	return &notificationService{d: d}
+}
+
+// GetPreferences returns the notification preferences of the account, every channel on when
+// it never changed them.
+func (ns *notificationService) GetPreferences(ctx context.Context, accountNumber string) (*models.NotificationPreferences, error) {
+	logger.Add(ctx, "operation", "notification_get_preferences")
+
+	preferences, err := ns.d.Repository.Postgre.Notification.GetPreferences(ctx, accountNumber)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "NOTIFICATION_PREFERENCES_GET_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to get notification preferences")
+	}
+
+	if preferences == nil {
+		return models.DefaultNotificationPreferences(accountNumber), nil
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences turns the channels of the request on or off for the optional
+// notifications of the account; the channels left out keep their preference.
+func (ns *notificationService) UpdatePreferences(ctx context.Context, accountNumber string, request *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferences, error) {
+	logger.Add(ctx, "operation", "notification_update_preferences")
+
+	preferences, err := ns.GetPreferences(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if request.Email != nil {
+		preferences.Email = *request.Email
+	}
+	if request.SMS != nil {
+		preferences.SMS = *request.SMS
+	}
+	preferences.UpdatedAt = time.Now()
+
+	if err := ns.d.Repository.Postgre.Notification.SavePreferences(ctx, preferences); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "NOTIFICATION_PREFERENCES_SAVE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to update notification preferences")
+	}
+
+	logger.AddMap(ctx, map[string]any{
+		"notification_email": preferences.Email,
+		"notification_sms":   preferences.SMS,
+	})
+	return preferences, nil
+}
+
+// ListDeadLetters returns up to limit notification deliveries that failed every attempt, the
+// oldest first.
+func (ns *notificationService) ListDeadLetters(ctx context.Context, limit int) ([]models.NotificationDeadLetter, error) {
+	letters, err := ns.d.Repository.Postgre.Notification.ListDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to list notification dead letters")
+	}
+	return letters, nil
+}
+
+// RetryDeadLetter enqueues the delivery of a dead letter again, on its channel, and deletes
+// it. A delivery that fails again comes back as a new dead letter.
+func (ns *notificationService) RetryDeadLetter(ctx context.Context, id int64) error {
+	if ns.d.Notifications == nil {
+		return errorc.Error(errorc.ErrorInternalServer, "No notification channel is configured")
+	}
+
+	repository := ns.d.Repository.Postgre.Notification
+	letter, err := repository.GetDeadLetter(ctx, id)
+	if err != nil {
+		return errorc.Error(errorc.ErrorDatabase, "Failed to get the notification dead letter")
+	}
+	if letter == nil {
+		return errorc.Error(errorc.ErrorDataNotFound, "Notification dead letter not found")
+	}
+
+	var message notify.Message
+	if err := json.Unmarshal([]byte(letter.Message), &message); err != nil {
+		return errorc.Error(errorc.ErrorInternalServer, "Notification dead letter is not a notification")
+	}
+	if err := ns.d.Notifications.Enqueue(ctx, message); err != nil {
+		return errorc.Error(errorc.ErrorInternalServer, "Failed to enqueue the notification")
+	}
+
+	if err := repository.DeleteDeadLetter(ctx, id); err != nil {
+		return errorc.Error(errorc.ErrorDatabase, "Failed to delete the notification dead letter")
+	}
+	return nil
+}
+
+// NotificationDeadLetters keeps the deliveries notifications gives up on in the
+// notification_dead_letters of repository.
+func NotificationDeadLetters(repository pgsql.NotificationRepository) notify.DeadLetterStore {
+	return notify.DeadLetterFunc(func(ctx context.Context, letter notify.DeadLetter) error {
+		message, err := json.Marshal(letter.Message)
+		if err != nil {
+			return err
+		}
+		return repository.CreateDeadLetter(ctx, &models.NotificationDeadLetter{
+			AccountNumber: letter.Message.User,
+			Event:         letter.Message.Event,
+			Channel:       letter.Channel,
+			Message:       string(message),
+			Attempts:      letter.Attempts,
+			Error:         letter.Error,
+			FailedAt:      letter.FailedAt,
+		})
+	})
+}
+
+// NotificationPreferences reads the channels each user turned on or off from repository.
+func NotificationPreferences(repository pgsql.NotificationRepository) notify.Preferences {
+	return notify.PreferencesFunc(func(ctx context.Context, user string) (map[string]bool, error) {
+		preferences, err := repository.GetPreferences(ctx, user)
+		if err != nil || preferences == nil {
+			return nil, err
+		}
+		return preferences.Channels(), nil
+	})
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/notify"
+	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationService_Preferences(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	svc := service.NewNotificationService(&service.Dependencies{Repository: repository.Repository{Postgre: repo}})
+
+	preferences, err := svc.GetPreferences(ctx, "12345")
+	require.NoError(t, err)
+	assert.True(t, preferences.Email && preferences.SMS, "every channel is on by default")
+	assert.Nil(t, preferences.NotificationPreferencesResponse().UpdatedAt)
+
+	off := false
+	preferences, err = svc.UpdatePreferences(ctx, "12345", &models.UpdateNotificationPreferencesRequest{SMS: &off})
+	require.NoError(t, err)
+	assert.True(t, preferences.Email, "left out, kept")
+	assert.False(t, preferences.SMS)
+
+	channels, err := service.NotificationPreferences(repo.Notification).Channels(ctx, "12345")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{notify.ChannelEmail: true, notify.ChannelSMS: false}, channels)
+
+	channels, err = service.NotificationPreferences(repo.Notification).Channels(ctx, "67890")
+	require.NoError(t, err)
+	assert.Nil(t, channels, "never changed")
+}
+
+func TestNotificationService_DeadLetters(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	sms := &notifications{}
+	failing := failingNotifier{err: errors.New("unavailable")}
+	queue := notify.NewQueue(notify.Channels{notify.ChannelEmail: failing, notify.ChannelSMS: sms}, notify.QueueOptions{
+		Workers:     1,
+		MaxAttempts: 1,
+		DeadLetters: service.NotificationDeadLetters(repo.Notification),
+	})
+	svc := service.NewNotificationService(&service.Dependencies{Repository: repository.Repository{Postgre: repo}, Notifications: queue})
+
+	require.NoError(t, queue.Enqueue(ctx, notify.Message{Event: "login.suspicious", User: "12345", Text: "Hello", Time: time.Now()}))
+	require.Eventually(t, func() bool {
+		letters, _ := svc.ListDeadLetters(ctx, 10)
+		return len(letters) == 1
+	}, time.Second, time.Millisecond)
+
+	letters, err := svc.ListDeadLetters(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "12345", letters[0].AccountNumber)
+	assert.Equal(t, notify.ChannelEmail, letters[0].Channel)
+	assert.Equal(t, "unavailable", letters[0].Error)
+
+	require.NoError(t, svc.RetryDeadLetter(ctx, letters[0].ID))
+	require.NoError(t, queue.Close(ctx))
+	assert.Len(t, sms.messages, 1, "retried on its channel only")
+
+	letters, err = svc.ListDeadLetters(ctx, 10)
+	require.NoError(t, err)
+	if assert.Len(t, letters, 1, "failed again") {
+		assert.Equal(t, int64(2), letters[0].ID)
+		assert.Contains(t, letters[0].Message, `"text":"Hello"`)
+	}
+
+	err = svc.RetryDeadLetter(ctx, 99)
+	assert.Equal(t, errorc.ErrorDataNotFound.Response.Code, errorc.GetResponse(err).Code)
+}
+
+// failingNotifier fails every delivery with err
+type failingNotifier struct {
+	err error
+}
+
+func (n failingNotifier) Notify(context.Context, notify.Message) error {
+	return n.err
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"go-echo-boilerplate/internal/pkg/mail/templates"
+	"go-echo-boilerplate/internal/pkg/notify"
+)
+
+// notificationTemplates are the text messages of the notifications the services send, rendered
+// with the Vars of each notify.Message. Emails are rendered from internal/pkg/mail/templates.
+var notificationTemplates = map[string]notify.Template{
+	"phone_change.code": {
+		Text: "Your verification code is {{.code}}. It expires in {{.expires_in}}; never share it with anyone.",
+	},
+}
+
+// NotificationTemplates parses the templates of the notifications the services send, for the
+// notify.Queue of Dependencies.Notifications: the emails first, then the text messages.
+func NotificationTemplates() (notify.Renderer, error) {
+	emails, err := templates.New()
+	if err != nil {
+		return nil, err
+	}
+	texts, err := notify.NewTemplates(notificationTemplates)
+	if err != nil {
+		return nil, err
+	}
+	return notify.Renderers{emails, texts}, nil
+}
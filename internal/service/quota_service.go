@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/quota"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"slices"
+	"time"
+)
+
+type QuotaService interface {
+	ListOverrides(ctx context.Context) ([]models.QuotaOverride, error)
+	GetOverride(ctx context.Context, client string) (*models.QuotaOverride, error)
+	SaveOverride(ctx context.Context, client string, request *models.SaveQuotaOverrideRequest) (*models.QuotaOverride, error)
+	DeleteOverride(ctx context.Context, client string) error
+}
+
+type quotaService struct {
+	d *Dependencies
+}
+
+func NewQuotaService(d *Dependencies) QuotaService {
+	return &quotaService{d: d}
+}
+
+// ListOverrides returns every quota override, expired ones included.
+func (qs *quotaService) ListOverrides(ctx context.Context) ([]models.QuotaOverride, error) {
+	overrides, err := qs.d.Repository.Postgre.QuotaOverride.List(ctx)
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to list quota overrides")
+	}
+	return overrides, nil
+}
+
+// GetOverride returns the quota override of a client of authorization.clients.
+func (qs *quotaService) GetOverride(ctx context.Context, client string) (*models.QuotaOverride, error) {
+	if err := qs.checkClient(client); err != nil {
+		return nil, err
+	}
+
+	override, err := qs.d.Repository.Postgre.QuotaOverride.Get(ctx, client)
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to get the quota override")
+	}
+	if override == nil {
+		return nil, errorc.Error(errorc.ErrorDataNotFound, "The API client has no quota override")
+	}
+	return override, nil
+}
+
+// SaveOverride creates or replaces the quota override of a client of authorization.clients
+// and applies it on this instance right away; the others apply it within quota.overrides_ttl.
+func (qs *quotaService) SaveOverride(ctx context.Context, client string, request *models.SaveQuotaOverrideRequest) (*models.QuotaOverride, error) {
+	logger.Add(ctx, "operation", "quota_save_override")
+
+	if err := qs.checkClient(client); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, errorc.Error(errorc.ErrorInvalidInput, "expires_at must be in the future")
+	}
+
+	override := &models.QuotaOverride{
+		Client:       client,
+		DailyQuota:   request.DailyQuota,
+		MonthlyQuota: request.MonthlyQuota,
+		Reason:       request.Reason,
+		ExpiresAt:    request.ExpiresAt,
+		UpdatedAt:    now,
+	}
+	if err := qs.d.Repository.Postgre.QuotaOverride.Save(ctx, override); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "QUOTA_OVERRIDE_SAVE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to save the quota override")
+	}
+
+	refreshQuotaOverrides(ctx)
+	return override, nil
+}
+
+// DeleteOverride deletes the quota override of a client of authorization.clients, so its
+// configured quotas apply again.
+func (qs *quotaService) DeleteOverride(ctx context.Context, client string) error {
+	logger.Add(ctx, "operation", "quota_delete_override")
+
+	if err := qs.checkClient(client); err != nil {
+		return err
+	}
+
+	deleted, err := qs.d.Repository.Postgre.QuotaOverride.Delete(ctx, client)
+	if err != nil {
+		return errorc.Error(errorc.ErrorDatabase, "Failed to delete the quota override")
+	}
+	if !deleted {
+		return errorc.Error(errorc.ErrorDataNotFound, "The API client has no quota override")
+	}
+
+	refreshQuotaOverrides(ctx)
+	return nil
+}
+
+// checkClient checks that client is one of authorization.clients
+func (qs *quotaService) checkClient(client string) error {
+	if qs.d.Config == nil || !slices.ContainsFunc(qs.d.Config.Authorization.Clients, func(c config.APIClient) bool {
+		return c.Name == client
+	}) {
+		return errorc.Error(errorc.ErrorDataNotFound, "API client not found")
+	}
+	return nil
+}
+
+// refreshQuotaOverrides applies a changed override on this instance without waiting for the TTL
+func refreshQuotaOverrides(ctx context.Context) {
+	if quotas := quota.Default(); quotas != nil {
+		if err := quotas.RefreshOverrides(ctx); err != nil {
+			logger.L().Warn(ctx, "quota overrides refresh failed", logger.Error(err))
+		}
+	}
+}
+
+// QuotaOverrides reads the quota overrides that did not expire from repository.
+func QuotaOverrides(repository pgsql.QuotaOverrideRepository) quota.OverrideSource {
+	return func(ctx context.Context) (map[string]quota.Override, error) {
+		overrides, err := repository.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		active := make(map[string]quota.Override, len(overrides))
+		for _, override := range overrides {
+			if override.Active(now) {
+				active[override.Client] = quota.Override{Daily: override.DailyQuota, Monthly: override.MonthlyQuota}
+			}
+		}
+		return active, nil
+	}
+}
@@ -0,0 +1,51 @@
+package service_test
+
+import (
+	"context"
+	"go-echo-boilerplate/internal/config"
+	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/errorc"
+	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
+	"go-echo-boilerplate/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaService_Overrides(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	cfg := &config.Configuration{Authorization: config.Authorization{Clients: []config.APIClient{{Name: "partner-a"}, {Name: "partner-b"}}}}
+	svc := service.NewQuotaService(&service.Dependencies{Repository: repository.Repository{Postgre: repo}, Config: cfg})
+
+	_, err := svc.GetOverride(ctx, "partner-c")
+	assert.Equal(t, errorc.ErrorDataNotFound.Response.Code, errorc.GetResponse(err).Code, "unknown client")
+
+	daily, monthly := int64(0), int64(500)
+	override, err := svc.SaveOverride(ctx, "partner-a", &models.SaveQuotaOverrideRequest{DailyQuota: &daily, Reason: "SUP-1234"})
+	require.NoError(t, err)
+	assert.False(t, override.UpdatedAt.IsZero())
+
+	expires := time.Now().Add(time.Hour)
+	_, err = svc.SaveOverride(ctx, "partner-b", &models.SaveQuotaOverrideRequest{MonthlyQuota: &monthly, Reason: "SUP-1235", ExpiresAt: &expires})
+	require.NoError(t, err)
+
+	overrides, err := service.QuotaOverrides(repo.QuotaOverride)(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &daily, overrides["partner-a"].Daily)
+	assert.Nil(t, overrides["partner-a"].Monthly)
+	assert.Equal(t, &monthly, overrides["partner-b"].Monthly)
+
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, repo.QuotaOverride.Save(ctx, &models.QuotaOverride{Client: "partner-b", ExpiresAt: &expired}))
+	overrides, err = service.QuotaOverrides(repo.QuotaOverride)(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, overrides, "partner-b", "expired")
+
+	require.NoError(t, svc.DeleteOverride(ctx, "partner-a"))
+	_, err = svc.GetOverride(ctx, "partner-a")
+	assert.Equal(t, errorc.ErrorDataNotFound.Response.Code, errorc.GetResponse(err).Code)
+}
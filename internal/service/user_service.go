@@ -2,18 +2,105 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/clientinfo"
 	"go-echo-boilerplate/internal/pkg/errorc"
 	"go-echo-boilerplate/internal/pkg/formatter"
 	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/i18n"
 	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/loginguard"
+	"go-echo-boilerplate/internal/pkg/notify"
+	"go-echo-boilerplate/internal/pkg/session"
 	"go-echo-boilerplate/internal/pkg/validator"
+	"go-echo-boilerplate/internal/repository/pgsql"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	defaultLoginListLimit = 20
+
+	// defaultAccountNumberRetries is used when account_number.max_retries is not configured
+	defaultAccountNumberRetries = 5
+	userListDateLayout          = "2006-01-02"
+
+	// maxConsentUserAgent is the length of consents.user_agent
+	maxConsentUserAgent = 512
+
+	// defaultDeletionGracePeriod is used when account_deletion.grace_period is not configured
+	defaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+	// defaultEmailChangeTTL is used when email_change.ttl is not configured
+	defaultEmailChangeTTL = 24 * time.Hour
+	// emailChangeTokenPrefix is the purpose of the signed tokens confirming an email change
+	emailChangeTokenPrefix = "email_change:"
+
+	// defaultPhoneChangeTTL and defaultPhoneChangeAttempts are used when phone_change.ttl and
+	// phone_change.max_attempts are not configured
+	defaultPhoneChangeTTL      = 10 * time.Minute
+	defaultPhoneChangeAttempts = 5
+	phoneChangeCodeDigits      = 6
+)
+
+// errPhoneNumberTaken aborts the transaction of a phone change whose number was taken
+var errPhoneNumberTaken = errors.New("phone number taken")
+
+// errPhoneChangeNotPending aborts the transaction of a phone change another verification
+// completed, or a newer request cancelled, after its code was checked
+var errPhoneChangeNotPending = errors.New("phone change not pending")
+
+// errEmailChangeNotPending aborts the transaction of an email change another confirmation
+// completed, or a cancellation ended, meanwhile
+var errEmailChangeNotPending = errors.New("email change not pending")
+
+// DefaultDeletionBatchSize is the number of accounts a sweep anonymizes when
+// account_deletion.batch_size is not configured
+const DefaultDeletionBatchSize = 100
+
+// MaxUserImportRows bounds the rows of an import, each costs a password hash: at bcrypt cost
+// 12 (about 250ms a hash) the import finishes within UserImportWriteTimeout
+const MaxUserImportRows = 200
+
+// UserImportWriteTimeout is the write deadline of an import, past server.write_timeout
+const UserImportWriteTimeout = 2 * time.Minute
+
+// userImportBatchSize is the number of imported users inserted per transaction
+const userImportBatchSize = 100
+
+// userExportPageSize is the number of users an export reads per query
+const userExportPageSize = 500
+
 type UserService interface {
 	Create(ctx context.Context, request *models.CreateUserRequest) (*models.User, error)
 	GetTokens(ctx context.Context, request *models.GetUserTokenRequest) (*models.GetUserTokenResponse, error)
 	GetByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error)
+	UpdateProfile(ctx context.Context, accountNumber string, request *models.UpdateUserRequest) (*models.User, error)
+	List(ctx context.Context, request *models.ListUserRequest) ([]models.User, int, error)
+	Search(ctx context.Context, request *models.SearchUserRequest) ([]models.UserSearchResult, int, error)
+	Import(ctx context.Context, rows []models.UserImportRow) (*models.UserImportResponse, error)
+	Export(ctx context.Context, fn func(user *models.User) error) error
+	ResetPassword(ctx context.Context, accountNumber, password string) error
+	RevokeSessions(ctx context.Context, accountNumber string) error
+	ListLogins(ctx context.Context, accountNumber string, request *models.ListLoginEventRequest) ([]models.LoginEvent, error)
+	GetConsent(ctx context.Context, accountNumber string) (*models.Consent, error)
+	UpdateConsent(ctx context.Context, accountNumber string, request *models.UpdateConsentRequest) (*models.Consent, error)
+	HasAcceptedTerms(ctx context.Context, accountNumber string) (bool, error)
+	ScheduleDeletion(ctx context.Context, accountNumber string) (*models.User, error)
+	AnonymizeDueAccounts(ctx context.Context, limit int) (int, error)
+	RequestEmailChange(ctx context.Context, accountNumber string, request *models.ChangeEmailRequest) (*models.EmailChange, error)
+	ConfirmEmailChange(ctx context.Context, request *models.ConfirmEmailChangeRequest) (*models.EmailChange, error)
+	RequestPhoneChange(ctx context.Context, accountNumber string, request *models.ChangePhoneRequest) (*models.PhoneChange, error)
+	VerifyPhoneChange(ctx context.Context, accountNumber string, request *models.VerifyPhoneChangeRequest) (*models.PhoneChange, error)
+	CheckUsernameAvailability(ctx context.Context, username string) (*models.UsernameAvailabilityResponse, error)
 }
 
 type userService struct {
@@ -28,15 +115,67 @@ func (us *userService) Create(ctx context.Context, request *models.CreateUserReq
 	// Enrich wide event with business context
 	logger.Add(ctx, "operation", "user_create")
 
+	if current := us.currentTermsVersion(); current != "" && request.TermsVersion != current {
+		logger.AddMap(ctx, map[string]any{
+			"terms_version":         request.TermsVersion,
+			"current_terms_version": current,
+		})
+		return nil, errorc.Error(errorc.ErrorInvalidInput, fmt.Sprintf("The current terms (version %s) must be accepted", current))
+	}
+
+	user, err := us.newUser(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist user to database
+	err = us.d.Repository.Postgre.User.Create(ctx, user)
+	if pgsql.IsUniqueViolation(err) {
+		// Taken by a concurrent signup since the checks above
+		logger.Add(ctx, "user_create_conflict", err.Error())
+		return nil, errorc.Error(errorc.ErrorAlreadyExist, "User with the same email, phone number, or username already exists")
+	}
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_CREATE_FAILED",
+			Message:   err.Error(),
+			Retriable: false, // Creation failures usually indicate constraint violations
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to create user")
+	}
+
+	if request.TermsVersion != "" || request.MarketingOptIn {
+		us.recordSignupConsent(ctx, user.AccountNumber, request)
+	}
+
+	// Enrich wide event with success metrics
+	logger.AddMap(ctx, map[string]any{
+		"user_account_number": user.AccountNumber,
+		"user_has_email":      user.Email != nil,
+		"user_has_phone":      user.PhoneNumber != nil,
+		"user_has_username":   user.Username != nil,
+	})
+
+	return user, nil
+}
+
+// newUser checks a signup or imported user the way Create does and builds the user to insert,
+// with a fresh account number and the password hashed. The terms are the caller's business.
+func (us *userService) newUser(ctx context.Context, request *models.CreateUserRequest) (*models.User, error) {
+	if request.Email != "" {
+		request.Email = us.normalizeEmail(request.Email)
+		if err := us.checkEmailDomain(ctx, request.Email); err != nil {
+			return nil, err
+		}
+	}
+
 	// Process phone number formatting if provided
 	phoneNumber := request.PhoneNumber.Number
 	phoneCountryCode := request.PhoneNumber.CountryCode
 
 	// Format phone number to international format if provided
 	if phoneNumber != "" {
-		if phoneCountryCode == "" {
-			phoneCountryCode = "ID"
-		}
 		formattedPhoneNumber, err := formatter.PhoneNumber(models.PhoneNumber{
 			Number:      phoneNumber,
 			CountryCode: phoneCountryCode,
@@ -45,7 +184,11 @@ func (us *userService) Create(ctx context.Context, request *models.CreateUserReq
 			logger.Add(ctx, "phone_format_error", err.Error())
 			return nil, errorc.Error(errorc.ErrorInvalidInput, "Invalid phone number format")
 		}
-		phoneNumber = *formattedPhoneNumber
+		phoneNumber, phoneCountryCode = formattedPhoneNumber.Number, formattedPhoneNumber.CountryCode
+	}
+
+	if err := us.checkPassword(ctx, request.Password, request.Name, request.Email, phoneNumber); err != nil {
+		return nil, err
 	}
 
 	// Check if user already exists
@@ -68,20 +211,23 @@ func (us *userService) Create(ctx context.Context, request *models.CreateUserReq
 		return nil, errorc.Error(errorc.ErrorAlreadyExist, "User with the same email or phone number already exists")
 	}
 
+	var usernamePtr *string
+	if request.Username != "" {
+		username, err := us.checkUsername(ctx, request.Username)
+		if err != nil {
+			return nil, err
+		}
+		usernamePtr = &username
+	}
+
 	// Generate unique account number
-	accountNumber, err := generator.AccountNumber()
+	accountNumber, err := us.generateAccountNumber(ctx)
 	if err != nil {
-		logger.AddError(ctx, &logger.ErrorContext{
-			Type:      "GenerationError",
-			Code:      "ACCOUNT_NUMBER_GENERATION_FAILED",
-			Message:   err.Error(),
-			Retriable: false,
-		})
-		return nil, errorc.Error(errorc.ErrorInternalServer, "Failed to generate account number")
+		return nil, err
 	}
 
 	// Hash password
-	hashedPassword, err := generator.Hash(request.Password)
+	hashedPassword, err := generator.Hash(request.Password, us.d.HashConfig)
 	if err != nil {
 		logger.AddError(ctx, &logger.ErrorContext{
 			Type:      "HashError",
@@ -103,36 +249,182 @@ func (us *userService) Create(ctx context.Context, request *models.CreateUserReq
 	}
 
 	// Create user model
-	user := &models.User{
+	return &models.User{
 		AccountNumber:    accountNumber,
 		Name:             request.Name,
+		Username:         usernamePtr,
 		Email:            emailPtr,
 		Password:         hashedPassword, // Use local variable, don't mutate input
 		PhoneNumber:      phonePtr,
 		PhoneCountryCode: phoneCountryCode,
 		// CreatedAt and UpdatedAt will be set by database defaults
+	}, nil
+}
+
+// checkUsername normalizes a requested username and checks it is valid, not reserved by
+// validator.DefaultReservedUsernames or username.reserved, and not taken
+func (us *userService) checkUsername(ctx context.Context, requested string) (string, error) {
+	username, reason, err := us.usernameAvailability(ctx, requested)
+	if err != nil {
+		return "", err
 	}
 
-	// Persist user to database
-	err = us.d.Repository.Postgre.User.Create(ctx, user)
+	switch reason {
+	case models.UsernameInvalid:
+		return "", errorc.Error(errorc.ErrorInvalidInput, "Invalid username format")
+	case models.UsernameReserved:
+		return "", errorc.Error(errorc.ErrorInvalidInput, "The username is reserved")
+	case models.UsernameTaken:
+		logger.Add(ctx, "conflict_username", username)
+		return "", errorc.Error(errorc.ErrorAlreadyExist, "The username is already taken")
+	}
+	return username, nil
+}
+
+// usernameAvailability returns the normalized username and why it cannot be registered,
+// empty when it can
+func (us *userService) usernameAvailability(ctx context.Context, requested string) (string, string, error) {
+	username := validator.NormalizeUsername(requested)
+
+	var reserved []string
+	if us.d.Config != nil {
+		reserved = us.d.Config.Username.Reserved
+	}
+	if err := validator.Username(username, reserved...); err != nil {
+		if errors.Is(err, validator.ErrUsernameReserved) {
+			return username, models.UsernameReserved, nil
+		}
+		return username, models.UsernameInvalid, nil
+	}
+
+	taken, err := us.d.Repository.Postgre.User.CheckByUsername(ctx, username)
 	if err != nil {
 		logger.AddError(ctx, &logger.ErrorContext{
 			Type:      "DatabaseError",
-			Code:      "USER_CREATE_FAILED",
+			Code:      "USERNAME_CHECK_FAILED",
 			Message:   err.Error(),
-			Retriable: false, // Creation failures usually indicate constraint violations
+			Retriable: true,
 		})
-		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to create user")
+		return "", "", errorc.Error(errorc.ErrorDatabase)
+	}
+	if taken {
+		return username, models.UsernameTaken, nil
 	}
+	return username, "", nil
+}
 
-	// Enrich wide event with success metrics
-	logger.AddMap(ctx, map[string]any{
-		"user_account_number": accountNumber,
-		"user_has_email":      request.Email != "",
-		"user_has_phone":      phoneNumber != "",
+// CheckUsernameAvailability tells whether a username can be registered, and why not
+func (us *userService) CheckUsernameAvailability(ctx context.Context, username string) (*models.UsernameAvailabilityResponse, error) {
+	logger.Add(ctx, "operation", "user_check_username")
+
+	normalized, reason, err := us.usernameAvailability(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Add(ctx, "username_available", reason == "")
+	return &models.UsernameAvailabilityResponse{
+		Type:      models.TYPE_USERNAME_AVAILABILITY,
+		Username:  normalized,
+		Available: reason == "",
+		Reason:    reason,
+	}, nil
+}
+
+// generateAccountNumber generates an account number that is not taken yet, retrying on collision.
+// The unique constraint on users.account_number still guards against concurrent inserts.
+func (us *userService) generateAccountNumber(ctx context.Context) (string, error) {
+	maxRetries := defaultAccountNumberRetries
+	var profile string
+	if us.d.Config != nil {
+		if us.d.Config.AccountNumber.MaxRetries > 0 {
+			maxRetries = us.d.Config.AccountNumber.MaxRetries
+		}
+		profile = us.d.Config.AccountNumber.Profile
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		accountNumber, err := generator.AccountNumber(profile)
+		if err != nil {
+			logger.AddError(ctx, &logger.ErrorContext{
+				Type:      "GenerationError",
+				Code:      "ACCOUNT_NUMBER_GENERATION_FAILED",
+				Message:   err.Error(),
+				Retriable: false,
+			})
+			return "", errorc.Error(errorc.ErrorInternalServer, "Failed to generate account number")
+		}
+
+		exists, err := us.d.Repository.Postgre.User.CheckByAccountNumber(ctx, accountNumber)
+		if err != nil {
+			logger.AddError(ctx, &logger.ErrorContext{
+				Type:      "DatabaseError",
+				Code:      "ACCOUNT_NUMBER_CHECK_FAILED",
+				Message:   err.Error(),
+				Retriable: true,
+			})
+			return "", errorc.Error(errorc.ErrorDatabase)
+		}
+
+		if !exists {
+			if attempt > 0 {
+				logger.Add(ctx, "account_number_collisions", attempt)
+			}
+			return accountNumber, nil
+		}
+	}
+
+	logger.AddError(ctx, &logger.ErrorContext{
+		Type:      "GenerationError",
+		Code:      "ACCOUNT_NUMBER_RETRIES_EXHAUSTED",
+		Message:   fmt.Sprintf("account number collided %d times", maxRetries+1),
+		Retriable: true,
 	})
+	return "", errorc.Error(errorc.ErrorInternalServer, "Failed to generate account number")
+}
 
-	return user, nil
+// checkPassword enforces password.min_score and, when enabled, the breach check.
+// The breach check fails open: an unavailable breach API never blocks registration, and is
+// recorded as breach_check "unavailable" rather than as the error of a request that succeeds.
+func (us *userService) checkPassword(ctx context.Context, password string, userInputs ...string) error {
+	if us.d.Config == nil {
+		return nil
+	}
+
+	locale := i18n.FromContext(ctx)
+
+	if minScore := us.d.Config.Password.MinScore; minScore > 0 {
+		strength := validator.ScorePassword(password, userInputs...)
+		logger.Add(ctx, "password_score", strength.Score)
+
+		if strength.Score < minScore {
+			return errorc.Validation(models.ErrorValidationResponse{
+				Code:    validator.ErrorCodeInvalidField,
+				Field:   "password",
+				Message: validator.PasswordScoreMessage("password", strength, locale),
+			})
+		}
+	}
+
+	if us.d.PasswordBreach != nil {
+		count, err := us.d.PasswordBreach.Count(ctx, password)
+		if err != nil {
+			logger.Add(ctx, "breach_check", "unavailable")
+			logger.FromContext(ctx).Warn(ctx, "password breach check unavailable", logger.Error(err))
+			return nil
+		}
+
+		if count > 0 {
+			logger.Add(ctx, "password_breached", true)
+			return errorc.Validation(models.ErrorValidationResponse{
+				Code:    validator.ErrorCodeInvalidField,
+				Field:   "password",
+				Message: i18n.T(locale, "validation.passwordBreached", "password"),
+			})
+		}
+	}
+
+	return nil
 }
 
 func (us *userService) GetTokens(ctx context.Context, request *models.GetUserTokenRequest) (*models.GetUserTokenResponse, error) {
@@ -145,10 +437,23 @@ func (us *userService) GetTokens(ctx context.Context, request *models.GetUserTok
 			logger.Add(ctx, "phone_format_error", err.Error())
 			return nil, errorc.Error(errorc.ErrorInvalidInput, "Invalid phone number format")
 		}
-		request.PhoneNumber.Number = *formattedPhoneNumber
+		request.PhoneNumber = *formattedPhoneNumber
 	}
 
-	user, err := us.d.Repository.Postgre.User.GetCredentialsByEmailOrPhoneNumber(ctx, request.Email, request.PhoneNumber.Number)
+	var (
+		user *models.User
+		err  error
+	)
+	if request.Email == "" && request.PhoneNumber.Number == "" && request.Username != "" {
+		user, err = us.d.Repository.Postgre.User.GetCredentialsByUsername(ctx, validator.NormalizeUsername(request.Username))
+	} else {
+		// Accounts created before email.fold_gmail was enabled keep their unfolded address
+		loginEmail := us.normalizeEmail(request.Email)
+		user, err = us.d.Repository.Postgre.User.GetCredentialsByEmailOrPhoneNumber(ctx, loginEmail, request.PhoneNumber.Number)
+		if lowered := formatter.Email(request.Email, false); err == nil && user == nil && lowered != loginEmail {
+			user, err = us.d.Repository.Postgre.User.GetCredentialsByEmailOrPhoneNumber(ctx, lowered, request.PhoneNumber.Number)
+		}
+	}
 	if err != nil {
 		logger.AddError(ctx, &logger.ErrorContext{
 			Type:      "DatabaseError",
@@ -161,14 +466,17 @@ func (us *userService) GetTokens(ctx context.Context, request *models.GetUserTok
 
 	if user == nil {
 		logger.AddMap(ctx, map[string]any{
-			"email": request.Email,
-			"phone": request.PhoneNumber.Number,
+			"email":    request.Email,
+			"phone":    request.PhoneNumber.Number,
+			"username": request.Username,
 		})
+		auditLogin(ctx, "", "unknown_user")
+		reportLoginAttempt(ctx, false)
 		return nil, errorc.Error(errorc.ErrorDataNotFound, "User not found")
 	}
 
 	// Verify password
-	match, err := validator.Hash(request.Password, user.Password)
+	match, err := validator.Hash(request.Password, user.Password, us.d.HashConfig)
 	if err != nil {
 		logger.AddError(ctx, &logger.ErrorContext{
 			Type:      "VerificationError",
@@ -184,9 +492,19 @@ func (us *userService) GetTokens(ctx context.Context, request *models.GetUserTok
 			"email": request.Email,
 			"phone": request.PhoneNumber.Number,
 		})
+		auditLogin(ctx, user.AccountNumber, "invalid_password")
+		reportLoginAttempt(ctx, false)
 		return nil, errorc.Error(errorc.ErrorInvalidInput, "Invalid password")
 	}
 
+	// Upgrade the stored hash while the plain password is available
+	us.rehashPassword(ctx, user, request.Password)
+
+	// Signing in during the grace period keeps the account
+	if user.DeletionScheduledAt != nil {
+		us.cancelDeletion(ctx, user)
+	}
+
 	var tokens []models.Token
 
 	// Generate tokens
@@ -229,21 +547,239 @@ func (us *userService) GetTokens(ctx context.Context, request *models.GetUserTok
 		email = *user.Email
 	}
 
+	username := ""
+	if user.Username != nil {
+		username = *user.Username
+	}
+
 	userPhoneNumber := ""
 	if user.PhoneNumber != nil {
 		userPhoneNumber = *user.PhoneNumber
 	}
 
+	auditLogin(ctx, user.AccountNumber, "")
+	reportLoginAttempt(ctx, true)
+	us.recordLogin(ctx, user)
+
 	return &models.GetUserTokenResponse{
 		Type:          models.TYPE_USER,
 		AccountNumber: user.AccountNumber,
 		Name:          user.Name,
+		Username:      username,
 		Email:         email,
 		PhoneNumber:   models.PhoneNumber{Number: userPhoneNumber, CountryCode: user.PhoneCountryCode},
 		Tokens:        tokens,
 	}, nil
 }
 
+// auditLogin writes the audit record of a login attempt; failure is empty for a successful
+// login. With client_info enabled, it carries the client's location and device, so logins
+// from a new country or device can be spotted.
+func auditLogin(ctx context.Context, accountNumber, failure string) {
+	fields := []logger.Field{
+		logger.String("audit_action", "login"),
+		logger.Bool("success", failure == ""),
+	}
+	if accountNumber != "" {
+		fields = append(fields, logger.String("account_number", accountNumber))
+	}
+	if failure != "" {
+		fields = append(fields, logger.String("failure_reason", failure))
+	}
+	if info, ok := clientinfo.FromContext(ctx); ok {
+		fields = append(fields, info.Fields()...)
+	}
+
+	if failure != "" {
+		logger.FromContext(ctx).Warn(ctx, "audit: login failed", fields...)
+		return
+	}
+	logger.FromContext(ctx).Info(ctx, "audit: login", fields...)
+}
+
+// reportLoginAttempt tells the login throttle (see middleware.LoginThrottle) whether the
+// attempt succeeded. Failures are logged but never change the outcome of the login.
+func reportLoginAttempt(ctx context.Context, succeeded bool) {
+	report := loginguard.Failed
+	if succeeded {
+		report = loginguard.Succeeded
+	}
+	if err := report(ctx); err != nil {
+		logger.FromContext(ctx).Warn(ctx, "login throttle unavailable", logger.Error(err))
+	}
+}
+
+// recordLogin records a successful login when login_alerts is enabled, flagging it when the
+// account has logged in before but never from this country or device, and notifies the
+// user of flagged ones. Failures are logged but never block the login.
+func (us *userService) recordLogin(ctx context.Context, user *models.User) {
+	if us.d.Config == nil || !us.d.Config.LoginAlerts.Enabled {
+		return
+	}
+
+	info, _ := clientinfo.FromContext(ctx)
+	event := &models.LoginEvent{
+		AccountNumber: user.AccountNumber,
+		IP:            info.IP,
+		Country:       info.Country,
+		City:          info.City,
+		Device:        info.Device,
+		OS:            info.OS,
+		Browser:       info.Browser,
+	}
+
+	history, err := us.d.Repository.Postgre.LoginEvent.GetHistory(ctx, event)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "LOGIN_HISTORY_GET_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return
+	}
+
+	// The first login sets the baseline, and what client_info can't tell is never "new"
+	var reasons []string
+	if history.Logins > 0 {
+		if event.Country != "" && !history.CountrySeen {
+			reasons = append(reasons, models.LoginReasonNewCountry)
+		}
+		if (event.Device != "" || event.OS != "" || event.Browser != "") && !history.DeviceSeen {
+			reasons = append(reasons, models.LoginReasonNewDevice)
+		}
+	}
+	event.Suspicious = len(reasons) > 0
+	event.Reasons = strings.Join(reasons, ",")
+
+	logger.Add(ctx, "login_suspicious", event.Suspicious)
+	if event.Suspicious {
+		logger.Add(ctx, "login_suspicious_reasons", reasons)
+	}
+
+	if err := us.d.Repository.Postgre.LoginEvent.Create(ctx, event); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "LOGIN_EVENT_CREATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return
+	}
+
+	if event.Suspicious {
+		us.notifySuspiciousLogin(ctx, user, event, reasons)
+	}
+}
+
+// notifySuspiciousLogin writes the audit record of a flagged login and queues its
+// notification, emailed to the user when they have an email address
+func (us *userService) notifySuspiciousLogin(ctx context.Context, user *models.User, event *models.LoginEvent, reasons []string) {
+	fields := []logger.Field{
+		logger.String("audit_action", "login"),
+		logger.String("account_number", user.AccountNumber),
+		logger.Strings("reasons", reasons),
+	}
+	if info, ok := clientinfo.FromContext(ctx); ok {
+		fields = append(fields, info.Fields()...)
+	}
+	logger.FromContext(ctx).Warn(ctx, "audit: suspicious login", fields...)
+
+	if us.d.Notifications == nil {
+		return
+	}
+
+	var recipient string
+	if user.Email != nil {
+		recipient = *user.Email
+	}
+
+	err := us.d.Notifications.Enqueue(ctx, notify.Message{
+		Event:     "login.suspicious",
+		Recipient: recipient,
+		User:      user.AccountNumber,
+		Locale:    string(i18n.FromContext(ctx)),
+		Vars:      suspiciousLoginVars(user.Name, event),
+		Data: map[string]any{
+			"account_number": user.AccountNumber,
+			"ip":             event.IP,
+			"country":        event.Country,
+			"city":           event.City,
+			"device":         event.Device,
+			"os":             event.OS,
+			"browser":        event.Browser,
+			"reasons":        reasons,
+		},
+		Time: event.CreatedAt,
+	})
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "NotificationError",
+			Code:      "LOGIN_ALERT_ENQUEUE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return
+	}
+	logger.Add(ctx, "login_alert_queued", true)
+}
+
+// suspiciousLoginVars are the variables of the login.suspicious template
+func suspiciousLoginVars(name string, event *models.LoginEvent) map[string]any {
+	var where []string
+	for _, part := range []string{event.City, event.Country} {
+		if part != "" {
+			where = append(where, part)
+		}
+	}
+	var client []string
+	for _, part := range []string{event.Browser, event.OS, event.Device} {
+		if part != "" {
+			client = append(client, part)
+		}
+	}
+
+	return map[string]any{
+		"name":     name,
+		"time":     event.CreatedAt.UTC().Format(time.RFC1123),
+		"location": strings.Join(where, ", "),
+		"device":   strings.Join(client, ", "),
+		"ip":       event.IP,
+	}
+}
+
+// rehashPassword re-hashes and persists the password when the stored hash doesn't match
+// the configured hashing parameters. Failures are logged but never block the login.
+func (us *userService) rehashPassword(ctx context.Context, user *models.User, password string) {
+	if !generator.NeedsRehash(user.Password, us.d.HashConfig) {
+		return
+	}
+
+	hashedPassword, err := generator.Hash(password, us.d.HashConfig)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "HashError",
+			Code:      "PASSWORD_REHASH_FAILED",
+			Message:   err.Error(),
+			Retriable: false,
+		})
+		return
+	}
+
+	if err := us.d.Repository.Postgre.User.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "PASSWORD_REHASH_UPDATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return
+	}
+
+	user.Password = hashedPassword
+	logger.Add(ctx, "password_rehashed", true)
+}
+
 func (us *userService) GetByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
 	user, err := us.d.Repository.Postgre.User.GetOneByAccountNumber(ctx, accountNumber)
 	if err != nil {
@@ -265,3 +801,1360 @@ func (us *userService) GetByAccountNumber(ctx context.Context, accountNumber str
 
 	return user, nil
 }
+
+// UpdateProfile renames the user, provided it is still at the version of the request. A user
+// changed in the meantime is a 409 ErrorVersionConflict; the client reloads it and retries.
+func (us *userService) UpdateProfile(ctx context.Context, accountNumber string, request *models.UpdateUserRequest) (*models.User, error) {
+	logger.Add(ctx, "operation", "user_update_profile")
+
+	user, err := us.GetByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if err := us.d.Repository.Postgre.User.UpdateProfile(ctx, user.ID, request.Version, name); err != nil {
+		if pgsql.IsVersionConflict(err) {
+			logger.AddMap(ctx, map[string]any{
+				"version":         request.Version,
+				"current_version": user.Version,
+			})
+			return nil, errorc.Error(errorc.ErrorVersionConflict)
+		}
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_UPDATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to update user")
+	}
+
+	us.invalidateResponses(ctx, accountNumber)
+
+	user.Name = name
+	user.Version = request.Version + 1
+	user.UpdatedAt = time.Now()
+	return user, nil
+}
+
+// invalidateResponses drops the cached responses of the account (see middleware.ResponseCache)
+// after a change of what it is served. A failure only leaves them stale until they expire.
+func (us *userService) invalidateResponses(ctx context.Context, accountNumber string) {
+	if err := cache.Default().Invalidate(ctx, accountNumber); err != nil {
+		logger.FromContext(ctx).Warn(ctx, "response cache invalidation failed", logger.Error(err))
+	}
+}
+
+// ResetPassword replaces the password of the user after the same policy checks as Create.
+// Existing tokens stay valid; call RevokeSessions to sign the user out everywhere.
+func (us *userService) ResetPassword(ctx context.Context, accountNumber, password string) error {
+	logger.Add(ctx, "operation", "user_reset_password")
+
+	user, err := us.GetByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return err
+	}
+
+	var email, phoneNumber string
+	if user.Email != nil {
+		email = *user.Email
+	}
+	if user.PhoneNumber != nil {
+		phoneNumber = *user.PhoneNumber
+	}
+	if err := us.checkPassword(ctx, password, user.Name, email, phoneNumber); err != nil {
+		return err
+	}
+
+	hashedPassword, err := generator.Hash(password, us.d.HashConfig)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "HashError",
+			Code:      "PASSWORD_HASH_FAILED",
+			Message:   err.Error(),
+			Retriable: false,
+		})
+		return errorc.Error(errorc.ErrorInternalServer, "Failed to hash password")
+	}
+
+	if err := us.d.Repository.Postgre.User.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "PASSWORD_UPDATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return errorc.Error(errorc.ErrorDatabase, "Failed to update password")
+	}
+
+	return nil
+}
+
+// RevokeSessions rejects every token issued to the user so far (see session.Revocations).
+func (us *userService) RevokeSessions(ctx context.Context, accountNumber string) error {
+	logger.Add(ctx, "operation", "user_revoke_sessions")
+
+	if _, err := us.GetByAccountNumber(ctx, accountNumber); err != nil {
+		return err
+	}
+
+	revocations := session.Default()
+	if revocations == nil {
+		return errorc.Error(errorc.ErrorInternalServer, "Session revocation is not configured")
+	}
+
+	if err := revocations.Revoke(ctx, accountNumber, time.Now()); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "CacheError",
+			Code:      "SESSION_REVOKE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return errorc.Error(errorc.ErrorInternalServer, "Failed to revoke sessions")
+	}
+
+	return nil
+}
+
+// ListLogins returns the most recent logins of the user, newest first. The limit falls back
+// to 20 when omitted.
+func (us *userService) ListLogins(ctx context.Context, accountNumber string, request *models.ListLoginEventRequest) ([]models.LoginEvent, error) {
+	logger.Add(ctx, "operation", "user_list_logins")
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultLoginListLimit
+	}
+
+	events, err := us.d.Repository.Postgre.LoginEvent.ListByAccountNumber(ctx, accountNumber, limit)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "LOGIN_EVENT_LIST_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to list logins")
+	}
+
+	logger.Add(ctx, "login_list_count", len(events))
+	return events, nil
+}
+
+// GetConsent returns the current consent of the account, nil when it never gave one.
+func (us *userService) GetConsent(ctx context.Context, accountNumber string) (*models.Consent, error) {
+	logger.Add(ctx, "operation", "user_get_consent")
+
+	consent, err := us.d.Repository.Postgre.Consent.GetLatest(ctx, accountNumber)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "CONSENT_GET_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to get consent")
+	}
+
+	return consent, nil
+}
+
+// UpdateConsent accepts a terms version and/or changes the marketing choice of the account,
+// recording the new state with the client it came from. Only the current terms version can
+// be accepted; an unchanged request records nothing.
+func (us *userService) UpdateConsent(ctx context.Context, accountNumber string, request *models.UpdateConsentRequest) (*models.Consent, error) {
+	logger.Add(ctx, "operation", "user_update_consent")
+
+	if current := us.currentTermsVersion(); request.TermsVersion != "" && current != "" && request.TermsVersion != current {
+		logger.AddMap(ctx, map[string]any{
+			"terms_version":         request.TermsVersion,
+			"current_terms_version": current,
+		})
+		return nil, errorc.Error(errorc.ErrorInvalidInput, fmt.Sprintf("Only the current terms (version %s) can be accepted", current))
+	}
+
+	previous, err := us.GetConsent(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	consent := models.Consent{AccountNumber: accountNumber}
+	if previous != nil {
+		consent = *previous
+		consent.ID = 0
+		consent.CreatedAt = time.Time{}
+	}
+
+	now := time.Now()
+	changed := false
+	if request.TermsVersion != "" && request.TermsVersion != consent.TermsVersion {
+		consent.TermsVersion = request.TermsVersion
+		consent.TermsAcceptedAt = &now
+		changed = true
+	}
+	if request.MarketingOptIn != nil && (previous == nil || *request.MarketingOptIn != consent.MarketingOptIn) {
+		consent.MarketingOptIn = *request.MarketingOptIn
+		consent.MarketingUpdatedAt = &now
+		changed = true
+	}
+
+	logger.Add(ctx, "consent_changed", changed)
+	if !changed {
+		return previous, nil
+	}
+
+	if err := us.createConsent(ctx, &consent); err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// HasAcceptedTerms reports whether the account accepted consent.terms_version, always true
+// when none is configured.
+func (us *userService) HasAcceptedTerms(ctx context.Context, accountNumber string) (bool, error) {
+	current := us.currentTermsVersion()
+	if current == "" {
+		return true, nil
+	}
+
+	consent, err := us.GetConsent(ctx, accountNumber)
+	if err != nil {
+		return false, err
+	}
+
+	accepted := consent.AcceptedTerms(current)
+	logger.Add(ctx, "terms_accepted", accepted)
+	return accepted, nil
+}
+
+// recordSignupConsent records the consent given with a signup. The account exists by now,
+// so a failure is logged and leaves the account without consent, to be asked again.
+func (us *userService) recordSignupConsent(ctx context.Context, accountNumber string, request *models.CreateUserRequest) {
+	now := time.Now()
+	consent := models.Consent{
+		AccountNumber:  accountNumber,
+		MarketingOptIn: request.MarketingOptIn,
+	}
+	if request.TermsVersion != "" {
+		consent.TermsVersion = request.TermsVersion
+		consent.TermsAcceptedAt = &now
+	}
+	if request.MarketingOptIn {
+		consent.MarketingUpdatedAt = &now
+	}
+
+	_ = us.createConsent(ctx, &consent)
+}
+
+// createConsent stores the consent with the client it came from and writes its audit record
+func (us *userService) createConsent(ctx context.Context, consent *models.Consent) error {
+	if wideEvent := logger.GetWideEvent(ctx); wideEvent != nil {
+		consent.IP = wideEvent.RemoteIP
+		consent.UserAgent = wideEvent.UserAgent
+		if len(consent.UserAgent) > maxConsentUserAgent {
+			consent.UserAgent = strings.ToValidUTF8(consent.UserAgent[:maxConsentUserAgent], "")
+		}
+	}
+
+	if err := us.d.Repository.Postgre.Consent.Create(ctx, consent); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "CONSENT_CREATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return errorc.Error(errorc.ErrorDatabase, "Failed to record consent")
+	}
+
+	logger.Add(ctx, "consent_terms_version", consent.TermsVersion)
+	logger.FromContext(ctx).Info(ctx, "audit: consent updated",
+		logger.String("audit_action", "consent"),
+		logger.String("account_number", consent.AccountNumber),
+		logger.String("terms_version", consent.TermsVersion),
+		logger.Bool("marketing_opt_in", consent.MarketingOptIn),
+		logger.String("remote_ip", consent.IP),
+	)
+	return nil
+}
+
+func (us *userService) currentTermsVersion() string {
+	if us.d.Config == nil {
+		return ""
+	}
+	return us.d.Config.Consent.TermsVersion
+}
+
+// ScheduleDeletion schedules the anonymization of the account after
+// account_deletion.grace_period and signs it out everywhere; signing in again before then
+// cancels it. A deletion already scheduled keeps its time.
+func (us *userService) ScheduleDeletion(ctx context.Context, accountNumber string) (*models.User, error) {
+	logger.Add(ctx, "operation", "user_schedule_deletion")
+
+	user, err := us.GetByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt.Valid {
+		return nil, errorc.Error(errorc.ErrorDataNotFound, "User not found")
+	}
+
+	if user.DeletionScheduledAt == nil {
+		now := time.Now()
+		if revocations := session.Default(); revocations != nil {
+			if err := revocations.Revoke(ctx, accountNumber, now); err != nil {
+				logger.AddError(ctx, &logger.ErrorContext{
+					Type:      "CacheError",
+					Code:      "SESSION_REVOKE_FAILED",
+					Message:   err.Error(),
+					Retriable: true,
+				})
+				return nil, errorc.Error(errorc.ErrorInternalServer, "Failed to revoke sessions")
+			}
+		}
+
+		scheduledAt := now.Add(us.deletionGracePeriod())
+		if err := us.d.Repository.Postgre.User.ScheduleDeletion(ctx, user.ID, scheduledAt); err != nil {
+			logger.AddError(ctx, &logger.ErrorContext{
+				Type:      "DatabaseError",
+				Code:      "USER_SCHEDULE_DELETION_FAILED",
+				Message:   err.Error(),
+				Retriable: true,
+			})
+			return nil, errorc.Error(errorc.ErrorDatabase, "Failed to schedule deletion")
+		}
+		user.DeletionScheduledAt = &scheduledAt
+		us.invalidateResponses(ctx, accountNumber)
+
+		logger.FromContext(ctx).Info(ctx, "audit: account deletion scheduled",
+			logger.String("audit_action", "account_deletion_scheduled"),
+			logger.String("account_number", accountNumber),
+			logger.Time("deletion_scheduled_at", scheduledAt),
+		)
+	}
+
+	logger.Add(ctx, "deletion_scheduled_at", user.DeletionScheduledAt.Format(time.RFC3339))
+	return user, nil
+}
+
+// cancelDeletion cancels the scheduled deletion of a user who signed in. A failure is logged
+// and leaves the deletion scheduled, the user can sign in again to cancel it.
+func (us *userService) cancelDeletion(ctx context.Context, user *models.User) {
+	if err := us.d.Repository.Postgre.User.CancelDeletion(ctx, user.ID); err != nil {
+		logger.FromContext(ctx).Warn(ctx, "failed to cancel the account deletion",
+			logger.String("account_number", user.AccountNumber),
+			logger.Error(err),
+		)
+		return
+	}
+
+	user.DeletionScheduledAt = nil
+	us.invalidateResponses(ctx, user.AccountNumber)
+	logger.Add(ctx, "deletion_cancelled", true)
+	logger.FromContext(ctx).Info(ctx, "audit: account deletion cancelled",
+		logger.String("audit_action", "account_deletion_cancelled"),
+		logger.String("account_number", user.AccountNumber),
+	)
+}
+
+// AnonymizeDueAccounts anonymizes up to limit accounts whose deletion is due. The user loses
+// its name, email, phone number, and password, its logins are deleted, and its consents lose
+// the client they came from; the account number stays, so the rows referencing it stay valid.
+// It returns how many accounts were anonymized; the ones that failed are retried by the next
+// call.
+func (us *userService) AnonymizeDueAccounts(ctx context.Context, limit int) (int, error) {
+	now := time.Now()
+	users, err := us.d.Repository.Postgre.User.ListDueForDeletion(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the accounts due for deletion: %w", err)
+	}
+
+	anonymized := 0
+	var errs []error
+	for i := range users {
+		done, err := us.anonymize(ctx, &users[i], now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to anonymize account %s: %w", users[i].AccountNumber, err))
+			continue
+		}
+		if done {
+			anonymized++
+		}
+	}
+	return anonymized, errors.Join(errs...)
+}
+
+// anonymize anonymizes the user, then the rows of its account. It reports false when the
+// deletion was cancelled since the user was listed.
+func (us *userService) anonymize(ctx context.Context, user *models.User, now time.Time) (bool, error) {
+	repository := us.d.Repository.Postgre
+
+	anonymized, err := repository.User.Anonymize(ctx, user.ID, now)
+	if err != nil || !anonymized {
+		return false, err
+	}
+	us.invalidateResponses(ctx, user.AccountNumber)
+	if err := repository.LoginEvent.DeleteByAccountNumber(ctx, user.AccountNumber); err != nil {
+		return false, err
+	}
+	if err := repository.Consent.Anonymize(ctx, user.AccountNumber); err != nil {
+		return false, err
+	}
+	if err := repository.EmailChange.DeleteByAccountNumber(ctx, user.AccountNumber); err != nil {
+		return false, err
+	}
+	if err := repository.PhoneChange.DeleteByAccountNumber(ctx, user.AccountNumber); err != nil {
+		return false, err
+	}
+	if err := repository.Notification.DeleteByAccountNumber(ctx, user.AccountNumber); err != nil {
+		return false, err
+	}
+	if err := repository.User.CompleteDeletion(ctx, user.ID); err != nil {
+		return false, err
+	}
+
+	logger.FromContext(ctx).Info(ctx, "audit: account anonymized",
+		logger.String("audit_action", "account_anonymized"),
+		logger.String("account_number", user.AccountNumber),
+	)
+	return true, nil
+}
+
+func (us *userService) deletionGracePeriod() time.Duration {
+	if us.d.Config == nil || us.d.Config.Deletion.GracePeriod == "" {
+		return defaultDeletionGracePeriod
+	}
+	// Validated at startup
+	gracePeriod, err := time.ParseDuration(us.d.Config.Deletion.GracePeriod)
+	if err != nil {
+		return defaultDeletionGracePeriod
+	}
+	return gracePeriod
+}
+
+// RequestEmailChange starts changing the email of the user to request.Email. A signed token
+// is emailed to the current and to the new address; the email changes once both were
+// confirmed with ConfirmEmailChange, within email_change.ttl. A newer request cancels the
+// pending ones. An account without an email only confirms the new address.
+func (us *userService) RequestEmailChange(ctx context.Context, accountNumber string, request *models.ChangeEmailRequest) (*models.EmailChange, error) {
+	logger.Add(ctx, "operation", "user_request_email_change")
+
+	secret := us.signingSecret()
+	if secret == "" || us.d.Notifications == nil {
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Email change is not configured")
+	}
+
+	user, err := us.GetByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt.Valid {
+		return nil, errorc.Error(errorc.ErrorDataNotFound, "User not found")
+	}
+	request.Email = us.normalizeEmail(request.Email)
+	if user.Email != nil && strings.EqualFold(*user.Email, request.Email) {
+		return nil, errorc.Error(errorc.ErrorInvalidInput, "The new email is the current email")
+	}
+	if err := us.checkEmailDomain(ctx, request.Email); err != nil {
+		return nil, err
+	}
+
+	exists, err := us.d.Repository.Postgre.User.CheckByEmailOrPhoneNumber(ctx, request.Email, "")
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_CHECK_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase)
+	}
+	if exists {
+		return nil, errorc.Error(errorc.ErrorEmailExists)
+	}
+
+	repository := us.d.Repository.Postgre.EmailChange
+	if err := repository.CancelPending(ctx, accountNumber); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "EMAIL_CHANGE_CANCEL_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to request the email change")
+	}
+
+	now := time.Now()
+	change := &models.EmailChange{
+		AccountNumber: accountNumber,
+		OldEmail:      user.Email,
+		NewEmail:      request.Email,
+		ExpiresAt:     now.Add(us.emailChangeTTL()),
+	}
+	if change.OldEmail == nil {
+		change.OldConfirmedAt = &now
+	}
+	if err := repository.Create(ctx, change); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "EMAIL_CHANGE_CREATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to request the email change")
+	}
+
+	if change.OldEmail != nil {
+		if err := us.sendEmailChangeToken(ctx, user, change, models.EmailChangeOld, secret); err != nil {
+			return nil, err
+		}
+	}
+	if err := us.sendEmailChangeToken(ctx, user, change, models.EmailChangeNew, secret); err != nil {
+		return nil, err
+	}
+
+	logger.Add(ctx, "email_change_id", change.ID)
+	logger.FromContext(ctx).Info(ctx, "audit: email change requested",
+		logger.String("audit_action", "email_change_requested"),
+		logger.String("account_number", accountNumber),
+		logger.Int64("email_change_id", change.ID),
+	)
+	return change, nil
+}
+
+// normalizeEmail applies formatter.Email, folding Gmail addresses with email.fold_gmail
+func (us *userService) normalizeEmail(email string) string {
+	return formatter.Email(email, us.d.Config != nil && us.d.Config.Email.FoldGmail)
+}
+
+// checkEmailDomain refuses addresses of the domains of email.disposable_domains
+func (us *userService) checkEmailDomain(ctx context.Context, email string) error {
+	if !us.d.DisposableDomains.Blocked(email) {
+		return nil
+	}
+	logger.Add(ctx, "email_domain_blocked", true)
+	return errorc.Error(errorc.ErrorInvalidInput, "Disposable email addresses are not allowed")
+}
+
+// sendEmailChangeToken emails the token confirming one side of the change to its address
+func (us *userService) sendEmailChangeToken(ctx context.Context, user *models.User, change *models.EmailChange, side, secret string) error {
+	token, err := generator.SignedPayload(fmt.Sprintf("%s%s:%d", emailChangeTokenPrefix, side, change.ID), time.Until(change.ExpiresAt), secret)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "GenerationError",
+			Code:      "EMAIL_CHANGE_TOKEN_FAILED",
+			Message:   err.Error(),
+			Retriable: false,
+		})
+		return errorc.Error(errorc.ErrorInternalServer, "Failed to request the email change")
+	}
+
+	recipient := change.NewEmail
+	if side == models.EmailChangeOld {
+		recipient = *change.OldEmail
+	}
+
+	err = us.d.Notifications.Enqueue(ctx, notify.Message{
+		Event:     "email_change.confirm",
+		Recipient: recipient,
+		User:      user.AccountNumber,
+		Mandatory: true,
+		Locale:    string(i18n.FromContext(ctx)),
+		Vars:      emailChangeVars(user.Name, change, side, us.emailChangeLink(token)),
+		Data: map[string]any{
+			"account_number":  user.AccountNumber,
+			"email_change_id": change.ID,
+			"side":            side,
+		},
+	})
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "NotificationError",
+			Code:      "EMAIL_CHANGE_ENQUEUE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return errorc.Error(errorc.ErrorInternalServer, "Failed to send the confirmation email")
+	}
+	return nil
+}
+
+// emailChangeVars are the variables of the email_change.confirm template for one side of
+// the change
+func emailChangeVars(name string, change *models.EmailChange, side, link string) map[string]any {
+	vars := map[string]any{
+		"name":       name,
+		"new_email":  "",
+		"expires_at": change.ExpiresAt.UTC().Format(time.RFC1123),
+		"link":       link,
+	}
+	if side == models.EmailChangeOld {
+		vars["new_email"] = change.NewEmail
+	}
+	return vars
+}
+
+// emailChangeLink appends the token to email_change.confirm_url, or returns the bare token
+func (us *userService) emailChangeLink(token string) string {
+	if us.d.Config == nil || us.d.Config.EmailChange.ConfirmURL == "" {
+		return token
+	}
+	// Validated at startup
+	link, err := url.Parse(us.d.Config.EmailChange.ConfirmURL)
+	if err != nil {
+		return token
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// ConfirmEmailChange confirms one side of an email change with the token emailed to it, and
+// switches the email once both sides are confirmed. The confirmation and the switch are stored
+// in one transaction that only a pending change passes, so concurrent confirmations switch it
+// once. The switch signs the user out everywhere and notifies the old address. Confirming
+// again is a no-op.
+func (us *userService) ConfirmEmailChange(ctx context.Context, request *models.ConfirmEmailChangeRequest) (*models.EmailChange, error) {
+	logger.Add(ctx, "operation", "user_confirm_email_change")
+
+	secret := us.signingSecret()
+	if secret == "" {
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Email change is not configured")
+	}
+
+	data, err := validator.SignedPayload(request.Token, secret)
+	if errors.Is(err, validator.ErrSignedPayloadExpired) {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The confirmation token has expired")
+	}
+	if err != nil {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "Invalid confirmation token")
+	}
+	side, id, ok := parseEmailChangeToken(data)
+	if !ok {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "Invalid confirmation token")
+	}
+	logger.AddMap(ctx, map[string]any{
+		"email_change_id":   id,
+		"email_change_side": side,
+	})
+
+	repository := us.d.Repository.Postgre.EmailChange
+	change, err := repository.GetByID(ctx, id)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "EMAIL_CHANGE_GET_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to confirm the email change")
+	}
+	if change == nil || change.CancelledAt != nil {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The email change is no longer pending")
+	}
+	if change.CompletedAt != nil {
+		return change, nil
+	}
+	now := time.Now()
+	if !now.Before(change.ExpiresAt) {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The confirmation token has expired")
+	}
+
+	if side == models.EmailChangeOld && change.OldConfirmedAt == nil {
+		change.OldConfirmedAt = &now
+	}
+	if side == models.EmailChangeNew && change.NewConfirmedAt == nil {
+		change.NewConfirmedAt = &now
+	}
+	var user *models.User
+	switchEmail := false
+	if change.Confirmed() {
+		if user, err = us.emailChangeUser(ctx, change); err != nil {
+			return nil, err
+		}
+		switchEmail = user.Email == nil || !strings.EqualFold(*user.Email, change.NewEmail)
+		change.CompletedAt = &now
+	}
+
+	err = us.d.Repository.Postgre.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+		updated, err := r.EmailChange.Update(ctx, change)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return errEmailChangeNotPending
+		}
+		if switchEmail {
+			return r.User.UpdateEmail(ctx, user.ID, change.NewEmail)
+		}
+		return nil
+	})
+	if errors.Is(err, errEmailChangeNotPending) {
+		// A concurrent confirmation completing it makes this one a no-op
+		if current, err := repository.GetByID(ctx, id); err == nil && current != nil && current.CompletedAt != nil {
+			return current, nil
+		}
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The email change is no longer pending")
+	}
+	if pgsql.IsUniqueViolation(err) {
+		return nil, errorc.Error(errorc.ErrorEmailExists)
+	}
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "EMAIL_CHANGE_UPDATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to confirm the email change")
+	}
+
+	if change.CompletedAt != nil {
+		us.emailChanged(ctx, user, change, now)
+	}
+	return change, nil
+}
+
+// emailChangeUser returns the account of a confirmed change, provided it still uses the old
+// email (or already the new one) and nobody took the new one meanwhile
+func (us *userService) emailChangeUser(ctx context.Context, change *models.EmailChange) (*models.User, error) {
+	user, err := us.GetByAccountNumber(ctx, change.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt.Valid {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The email change is no longer pending")
+	}
+	if user.Email != nil && strings.EqualFold(*user.Email, change.NewEmail) {
+		return user, nil
+	}
+
+	if (user.Email == nil) != (change.OldEmail == nil) || (user.Email != nil && *user.Email != *change.OldEmail) {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The email change is no longer pending")
+	}
+
+	exists, err := us.d.Repository.Postgre.User.CheckByEmailOrPhoneNumber(ctx, change.NewEmail, "")
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_CHECK_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase)
+	}
+	if exists {
+		return nil, errorc.Error(errorc.ErrorEmailExists)
+	}
+	return user, nil
+}
+
+// emailChanged follows the switch of a completed change: it signs the user out everywhere,
+// drops their cached responses, and notifies the old address
+func (us *userService) emailChanged(ctx context.Context, user *models.User, change *models.EmailChange, now time.Time) {
+	us.invalidateResponses(ctx, user.AccountNumber)
+
+	// The email is a login identifier, sessions signed in with the old one end
+	if revocations := session.Default(); revocations != nil {
+		if err := revocations.Revoke(ctx, user.AccountNumber, now); err != nil {
+			logger.FromContext(ctx).Warn(ctx, "failed to revoke sessions after the email change",
+				logger.String("account_number", user.AccountNumber),
+				logger.Error(err),
+			)
+		}
+	}
+
+	logger.Add(ctx, "email_changed", true)
+	logger.FromContext(ctx).Info(ctx, "audit: email changed",
+		logger.String("audit_action", "email_changed"),
+		logger.String("account_number", user.AccountNumber),
+		logger.Int64("email_change_id", change.ID),
+	)
+
+	if change.OldEmail != nil && us.d.Notifications != nil {
+		err := us.d.Notifications.Enqueue(ctx, notify.Message{
+			Event:     "email_change.completed",
+			Recipient: *change.OldEmail,
+			User:      user.AccountNumber,
+			Mandatory: true,
+			Locale:    string(i18n.FromContext(ctx)),
+			Vars:      map[string]any{"name": user.Name, "new_email": change.NewEmail},
+			Data: map[string]any{
+				"account_number":  user.AccountNumber,
+				"email_change_id": change.ID,
+			},
+			Time: now,
+		})
+		if err != nil {
+			logger.AddError(ctx, &logger.ErrorContext{
+				Type:      "NotificationError",
+				Code:      "EMAIL_CHANGED_ENQUEUE_FAILED",
+				Message:   err.Error(),
+				Retriable: true,
+			})
+		}
+	}
+}
+
+// parseEmailChangeToken splits the data of an email change token into its side and change id
+func parseEmailChangeToken(data string) (string, int64, bool) {
+	rest, ok := strings.CutPrefix(data, emailChangeTokenPrefix)
+	if !ok {
+		return "", 0, false
+	}
+	side, rawID, ok := strings.Cut(rest, ":")
+	if !ok || (side != models.EmailChangeOld && side != models.EmailChangeNew) {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return side, id, true
+}
+
+func (us *userService) signingSecret() string {
+	if us.d.Config == nil {
+		return ""
+	}
+	return us.d.Config.Authorization.SigningSecret
+}
+
+func (us *userService) emailChangeTTL() time.Duration {
+	if us.d.Config == nil || us.d.Config.EmailChange.TTL == "" {
+		return defaultEmailChangeTTL
+	}
+	// Validated at startup
+	ttl, err := time.ParseDuration(us.d.Config.EmailChange.TTL)
+	if err != nil {
+		return defaultEmailChangeTTL
+	}
+	return ttl
+}
+
+// RequestPhoneChange starts changing the phone number of the user: the number is formatted to
+// E.164 and a code is texted to it, which VerifyPhoneChange checks within phone_change.ttl. A
+// newer request cancels the pending ones.
+func (us *userService) RequestPhoneChange(ctx context.Context, accountNumber string, request *models.ChangePhoneRequest) (*models.PhoneChange, error) {
+	logger.Add(ctx, "operation", "user_request_phone_change")
+
+	secret := us.signingSecret()
+	if secret == "" || us.d.Notifications == nil {
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Phone change is not configured")
+	}
+
+	if request.PhoneNumber.Number == "" {
+		return nil, errorc.Error(errorc.ErrorInvalidInput, "The phone number is required")
+	}
+	phoneNumber, err := formatter.PhoneNumber(request.PhoneNumber)
+	if err != nil {
+		logger.Add(ctx, "phone_format_error", err.Error())
+		return nil, errorc.Error(errorc.ErrorInvalidInput, "Invalid phone number format")
+	}
+
+	user, err := us.GetByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt.Valid {
+		return nil, errorc.Error(errorc.ErrorDataNotFound, "User not found")
+	}
+	if user.PhoneNumber != nil && *user.PhoneNumber == phoneNumber.Number {
+		return nil, errorc.Error(errorc.ErrorInvalidInput, "The new phone number is the current phone number")
+	}
+
+	exists, err := us.d.Repository.Postgre.User.CheckByEmailOrPhoneNumber(ctx, "", phoneNumber.Number)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_CHECK_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase)
+	}
+	if exists {
+		return nil, errorc.Error(errorc.ErrorAlreadyExist, "User with the same phone number already exists")
+	}
+
+	code, err := generator.Code(phoneChangeCodeDigits)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "GenerationError",
+			Code:      "PHONE_CHANGE_CODE_FAILED",
+			Message:   err.Error(),
+			Retriable: false,
+		})
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Failed to request the phone change")
+	}
+
+	repository := us.d.Repository.Postgre.PhoneChange
+	if err := repository.CancelPending(ctx, accountNumber); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "PHONE_CHANGE_CANCEL_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to request the phone change")
+	}
+
+	change := &models.PhoneChange{
+		AccountNumber:    accountNumber,
+		PhoneNumber:      phoneNumber.Number,
+		PhoneCountryCode: phoneNumber.CountryCode,
+		CodeHash:         phoneChangeCodeHash(secret, accountNumber, code),
+		ExpiresAt:        time.Now().Add(us.phoneChangeTTL()),
+	}
+	if err := repository.Create(ctx, change); err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "PHONE_CHANGE_CREATE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to request the phone change")
+	}
+
+	err = us.d.Notifications.Enqueue(ctx, notify.Message{
+		Event:     "phone_change.code",
+		Phone:     change.PhoneNumber,
+		User:      accountNumber,
+		Mandatory: true,
+		Vars:      map[string]any{"code": code, "expires_in": time.Until(change.ExpiresAt).Round(time.Minute).String()},
+		Data: map[string]any{
+			"account_number":  accountNumber,
+			"phone_change_id": change.ID,
+		},
+	})
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "NotificationError",
+			Code:      "PHONE_CHANGE_ENQUEUE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Failed to send the verification code")
+	}
+
+	logger.Add(ctx, "phone_change_id", change.ID)
+	logger.FromContext(ctx).Info(ctx, "audit: phone change requested",
+		logger.String("audit_action", "phone_change_requested"),
+		logger.String("account_number", accountNumber),
+		logger.Int64("phone_change_id", change.ID),
+	)
+	return change, nil
+}
+
+// VerifyPhoneChange checks the code of the pending phone change of the user and, when it
+// matches, switches the phone number. The uniqueness check, the switch, and the completion
+// of the change happen in one transaction. Each code entered is counted before it is compared,
+// atomically with the limit: after phone_change.max_attempts codes a new one must be requested.
+func (us *userService) VerifyPhoneChange(ctx context.Context, accountNumber string, request *models.VerifyPhoneChangeRequest) (*models.PhoneChange, error) {
+	logger.Add(ctx, "operation", "user_verify_phone_change")
+
+	secret := us.signingSecret()
+	if secret == "" {
+		return nil, errorc.Error(errorc.ErrorInternalServer, "Phone change is not configured")
+	}
+
+	repository := us.d.Repository.Postgre
+	change, err := repository.PhoneChange.GetPending(ctx, accountNumber)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "PHONE_CHANGE_GET_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to verify the phone change")
+	}
+	if change == nil {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "No phone change is pending")
+	}
+	logger.Add(ctx, "phone_change_id", change.ID)
+
+	now := time.Now()
+	if !now.Before(change.ExpiresAt) {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The verification code has expired")
+	}
+	// Counted and checked in one statement: concurrent guesses can't all pass a stale count
+	attempts, err := repository.PhoneChange.IncrementAttempts(ctx, change.ID, us.phoneChangeAttempts())
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "PHONE_CHANGE_ATTEMPT_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to verify the phone change")
+	}
+	if attempts == 0 {
+		return nil, errorc.Error(errorc.ErrorTooManyAttempts, "Too many wrong codes, request a new one")
+	}
+	if !hmac.Equal([]byte(change.CodeHash), []byte(phoneChangeCodeHash(secret, accountNumber, request.Code))) {
+		logger.Add(ctx, "phone_change_attempts", attempts)
+		return nil, errorc.Error(errorc.ErrorInvalidData, "Invalid verification code")
+	}
+
+	user, err := us.GetByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt.Valid {
+		return nil, errorc.Error(errorc.ErrorDataNotFound, "User not found")
+	}
+
+	err = repository.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+		completed, err := r.PhoneChange.Complete(ctx, change.ID, now)
+		if err != nil {
+			return err
+		}
+		if !completed {
+			return errPhoneChangeNotPending
+		}
+		exists, err := r.User.CheckByEmailOrPhoneNumber(ctx, "", change.PhoneNumber)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errPhoneNumberTaken
+		}
+		return r.User.UpdatePhoneNumber(ctx, user.ID, change.PhoneNumber, change.PhoneCountryCode)
+	})
+	if errors.Is(err, errPhoneChangeNotPending) {
+		return nil, errorc.Error(errorc.ErrorInvalidData, "The phone change is no longer pending")
+	}
+	if errors.Is(err, errPhoneNumberTaken) || pgsql.IsUniqueViolation(err) {
+		return nil, errorc.Error(errorc.ErrorAlreadyExist, "User with the same phone number already exists")
+	}
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_UPDATE_PHONE_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, errorc.Error(errorc.ErrorDatabase, "Failed to change the phone number")
+	}
+	change.CompletedAt = &now
+	change.Attempts = attempts
+	us.invalidateResponses(ctx, accountNumber)
+
+	logger.Add(ctx, "phone_changed", true)
+	logger.FromContext(ctx).Info(ctx, "audit: phone number changed",
+		logger.String("audit_action", "phone_changed"),
+		logger.String("account_number", accountNumber),
+		logger.Int64("phone_change_id", change.ID),
+	)
+	return change, nil
+}
+
+// phoneChangeCodeHash keys the hash of a code with the signing secret and the account, so a
+// leaked phone_changes table doesn't give the codes away to a brute force
+func phoneChangeCodeHash(secret, accountNumber, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("phone_change:" + accountNumber + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (us *userService) phoneChangeTTL() time.Duration {
+	if us.d.Config == nil || us.d.Config.PhoneChange.TTL == "" {
+		return defaultPhoneChangeTTL
+	}
+	// Validated at startup
+	ttl, err := time.ParseDuration(us.d.Config.PhoneChange.TTL)
+	if err != nil {
+		return defaultPhoneChangeTTL
+	}
+	return ttl
+}
+
+func (us *userService) phoneChangeAttempts() int {
+	if us.d.Config == nil || us.d.Config.PhoneChange.MaxAttempts <= 0 {
+		return defaultPhoneChangeAttempts
+	}
+	return us.d.Config.PhoneChange.MaxAttempts
+}
+
+// List returns a page of users matching the request filters and the total number of matches.
+// Page and limit fall back to 1 and 20 when omitted.
+func (us *userService) List(ctx context.Context, request *models.ListUserRequest) ([]models.User, int, error) {
+	logger.Add(ctx, "operation", "user_list")
+
+	filter := models.UserFilter{
+		Name:   request.Name,
+		SortBy: "created_at",
+		Limit:  request.Limit,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = models.DefaultListLimit
+	}
+
+	page := request.Page
+	if page <= 0 {
+		page = 1
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	if request.Sort != "" {
+		field, desc := strings.CutPrefix(request.Sort, "-")
+		filter.SortDesc = desc
+		if field == "name" {
+			filter.SortBy = "name"
+		}
+	}
+
+	// Dates are already validated by the request's yyymmddFormat tag
+	if request.CreatedFrom != "" {
+		from, err := time.Parse(userListDateLayout, request.CreatedFrom)
+		if err != nil {
+			return nil, 0, errorc.Error(errorc.ErrorInvalidInput, "invalid createdFrom date")
+		}
+		filter.CreatedFrom = &from
+	}
+
+	if request.CreatedTo != "" {
+		to, err := time.Parse(userListDateLayout, request.CreatedTo)
+		if err != nil {
+			return nil, 0, errorc.Error(errorc.ErrorInvalidInput, "invalid createdTo date")
+		}
+		// Make the whole createdTo day inclusive
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+
+	users, total, err := us.d.Repository.Postgre.User.List(ctx, filter)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_LIST_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, 0, errorc.Error(errorc.ErrorDatabase, "Failed to list users")
+	}
+
+	logger.AddMap(ctx, map[string]any{
+		"user_list_total": total,
+		"user_list_page":  page,
+		"user_list_limit": filter.Limit,
+	})
+
+	return users, total, nil
+}
+
+// Search returns a page of users matching the search of an admin, best match first, and the
+// total number of matches. Page and limit fall back to 1 and 20 when omitted.
+func (us *userService) Search(ctx context.Context, request *models.SearchUserRequest) ([]models.UserSearchResult, int, error) {
+	logger.Add(ctx, "operation", "user_search")
+
+	filter := models.UserSearchFilter{
+		Query: strings.ToLower(strings.TrimSpace(request.Q)),
+		Limit: request.Limit,
+	}
+	if filter.Query == "" {
+		return nil, 0, errorc.Error(errorc.ErrorInvalidInput, "Search must not be blank")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = models.DefaultListLimit
+	}
+
+	page := request.Page
+	if page <= 0 {
+		page = 1
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	results, total, err := us.d.Repository.Postgre.User.Search(ctx, filter)
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_SEARCH_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return nil, 0, errorc.Error(errorc.ErrorDatabase, "Failed to search users")
+	}
+
+	// The search itself may be an email or phone number, only its length is logged
+	logger.AddMap(ctx, map[string]any{
+		"user_search_length": len(filter.Query),
+		"user_search_total":  total,
+		"user_search_page":   page,
+		"user_search_limit":  filter.Limit,
+	})
+
+	return results, total, nil
+}
+
+// importedUser is a row of an import ready to be inserted
+type importedUser struct {
+	index int // in the rows and the report
+	user  *models.User
+}
+
+// Import creates the users of an admin import. Rows are checked like signups (the terms
+// aside) and against the earlier rows of the file, then inserted in transactions of
+// userImportBatchSize rows. A row failing its insert rolls its batch back, whose rows are then
+// inserted one at a time so only that row fails. The report has the outcome of every row.
+func (us *userService) Import(ctx context.Context, rows []models.UserImportRow) (*models.UserImportResponse, error) {
+	logger.Add(ctx, "operation", "user_import")
+
+	if len(rows) == 0 {
+		return nil, errorc.Error(errorc.ErrorInvalidInput, "The import has no users")
+	}
+	if len(rows) > MaxUserImportRows {
+		return nil, errorc.Error(errorc.ErrorInvalidInput, fmt.Sprintf("An import takes at most %d users", MaxUserImportRows))
+	}
+
+	report := &models.UserImportResponse{
+		Type:  models.TYPE_USER_IMPORT,
+		Total: len(rows),
+		Rows:  make([]models.UserImportRowResponse, len(rows)),
+	}
+
+	// seen maps the emails, phone numbers, and usernames of the earlier rows to their line
+	seen := make(map[string]int)
+	var pending []importedUser
+	for i := range rows {
+		report.Rows[i].Line = rows[i].Line
+		if len(rows[i].Errors) > 0 {
+			report.Fail(i, rows[i].Errors...)
+			continue
+		}
+
+		user, err := us.newUser(ctx, &rows[i].User)
+		if err != nil {
+			report.Fail(i, importErrors(err)...)
+			continue
+		}
+		if line, ok := claimImported(seen, user, rows[i].Line); !ok {
+			report.Fail(i, models.ErrorValidationResponse{
+				Code:    errorc.ErrorAlreadyExist.Response.Status,
+				Message: fmt.Sprintf("Same email, phone number, or username as line %d", line),
+			})
+			continue
+		}
+		pending = append(pending, importedUser{index: i, user: user})
+	}
+
+	for start := 0; start < len(pending); start += userImportBatchSize {
+		batch := pending[start:min(start+userImportBatchSize, len(pending))]
+		if err := us.insertImported(ctx, batch); err == nil {
+			for _, imported := range batch {
+				report.Succeed(imported.index, imported.user.AccountNumber)
+			}
+			continue
+		}
+
+		for _, imported := range batch {
+			imported.user.ID = 0
+			if err := us.insertImported(ctx, []importedUser{imported}); err != nil {
+				report.Fail(imported.index, importInsertError(err))
+				continue
+			}
+			report.Succeed(imported.index, imported.user.AccountNumber)
+		}
+	}
+
+	logger.AddMap(ctx, map[string]any{
+		"user_import_total":    report.Total,
+		"user_import_imported": report.Imported,
+		"user_import_failed":   report.Failed,
+	})
+
+	return report, nil
+}
+
+// Export calls fn with every user that is not deleted, in ID order, reading them
+// userExportPageSize at a time. An error of fn stops the export and is returned as is.
+func (us *userService) Export(ctx context.Context, fn func(user *models.User) error) error {
+	logger.Add(ctx, "operation", "user_export")
+
+	exported := 0
+	var fnErr error
+	err := us.d.Repository.Postgre.User.Iterate(ctx, models.UserIterationFilter{}, userExportPageSize, func(user *models.User) error {
+		if fnErr = fn(user); fnErr != nil {
+			return fnErr
+		}
+		exported++
+		return nil
+	})
+	logger.Add(ctx, "user_export_count", exported)
+
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		logger.AddError(ctx, &logger.ErrorContext{
+			Type:      "DatabaseError",
+			Code:      "USER_EXPORT_FAILED",
+			Message:   err.Error(),
+			Retriable: true,
+		})
+		return errorc.Error(errorc.ErrorDatabase, "Failed to export users")
+	}
+	return nil
+}
+
+// insertImported inserts the users in one transaction
+func (us *userService) insertImported(ctx context.Context, batch []importedUser) error {
+	return us.d.Repository.Postgre.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepository) error {
+		for _, imported := range batch {
+			if err := r.User.Create(ctx, imported.user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// claimImported records the email, phone number, and username of the user imported from line.
+// It returns the earlier line using one of them and false when one is taken.
+func claimImported(seen map[string]int, user *models.User, line int) (int, bool) {
+	var keys []string
+	if user.Email != nil {
+		keys = append(keys, "email:"+*user.Email)
+	}
+	if user.PhoneNumber != nil {
+		keys = append(keys, "phone:"+*user.PhoneNumber)
+	}
+	if user.Username != nil {
+		keys = append(keys, "username:"+*user.Username)
+	}
+
+	for _, key := range keys {
+		if earlier, ok := seen[key]; ok {
+			return earlier, false
+		}
+	}
+	for _, key := range keys {
+		seen[key] = line
+	}
+	return line, true
+}
+
+// importErrors reports the error of a row the way the API would have answered its signup
+func importErrors(err error) []models.ErrorValidationResponse {
+	response := errorc.GetResponse(err)
+	if errs, ok := response.Errors.([]models.ErrorValidationResponse); ok && len(errs) > 0 {
+		return errs
+	}
+	return []models.ErrorValidationResponse{{Code: response.Status, Message: response.Message}}
+}
+
+// importInsertError reports the failed insert of a row
+func importInsertError(err error) models.ErrorValidationResponse {
+	if pgsql.IsUniqueViolation(err) {
+		// Taken by a signup since the checks
+		return models.ErrorValidationResponse{
+			Code:    errorc.ErrorAlreadyExist.Response.Status,
+			Message: "User with the same email, phone number, or username already exists",
+		}
+	}
+	return models.ErrorValidationResponse{Code: errorc.ErrorDatabase.Response.Status, Message: "Failed to create user"}
+}
@@ -3,13 +3,29 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"go-echo-boilerplate/internal/config"
 	"go-echo-boilerplate/internal/models"
+	"go-echo-boilerplate/internal/pkg/cache"
+	"go-echo-boilerplate/internal/pkg/clientinfo"
 	"go-echo-boilerplate/internal/pkg/errorc"
 	"go-echo-boilerplate/internal/pkg/generator"
+	"go-echo-boilerplate/internal/pkg/hashc"
+	"go-echo-boilerplate/internal/pkg/jwtc"
+	"go-echo-boilerplate/internal/pkg/logger"
+	"go-echo-boilerplate/internal/pkg/notify"
+	"go-echo-boilerplate/internal/pkg/session"
+	"go-echo-boilerplate/internal/pkg/validator"
 	"go-echo-boilerplate/internal/repository"
+	"go-echo-boilerplate/internal/repository/memory"
 	"go-echo-boilerplate/internal/repository/pgsql"
 	"go-echo-boilerplate/internal/service"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,6 +35,15 @@ func strPtr(s string) *string {
 	return &s
 }
 
+// testJWTConfig signs tokens in tests, since the generator has no default secret
+var testJWTConfig = &jwtc.Configuration{
+	AccessTokenSecret:    "test-access-secret-0123456789abcdef",
+	AccessTokenDuration:  15 * time.Minute,
+	RefreshTokenSecret:   "test-refresh-secret-0123456789abcdef",
+	RefreshTokenDuration: 7 * 24 * time.Hour,
+	Issuer:               "test-issuer",
+}
+
 type MockUserRepository struct {
 	mock.Mock
 }
@@ -33,6 +58,11 @@ func (m *MockUserRepository) CheckByEmailOrPhoneNumber(ctx context.Context, emai
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) CheckByAccountNumber(ctx context.Context, accountNumber string) (bool, error) {
+	args := m.Called(ctx, accountNumber)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) GetCredentialsByEmailOrPhoneNumber(ctx context.Context, email string, phoneNumber string) (*models.User, error) {
 	args := m.Called(ctx, email, phoneNumber)
 	if args.Get(0) == nil {
@@ -41,6 +71,19 @@ func (m *MockUserRepository) GetCredentialsByEmailOrPhoneNumber(ctx context.Cont
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) CheckByUsername(ctx context.Context, username string) (bool, error) {
+	args := m.Called(ctx, username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) GetCredentialsByUsername(ctx context.Context, username string) (*models.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetOneByAccountNumber(ctx context.Context, accountNumber string) (*models.User, error) {
 	args := m.Called(ctx, accountNumber)
 	if args.Get(0) == nil {
@@ -49,6 +92,75 @@ func (m *MockUserRepository) GetOneByAccountNumber(ctx context.Context, accountN
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, filter models.UserSearchFilter) ([]models.UserSearchResult, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.UserSearchResult), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) Iterate(ctx context.Context, filter models.UserIterationFilter, batchSize int, fn func(user *models.User) error) error {
+	args := m.Called(ctx, filter, batchSize, fn)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, id, version int, name string) error {
+	args := m.Called(ctx, id, version, name)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+	args := m.Called(ctx, id, hashedPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ScheduleDeletion(ctx context.Context, id int, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CancelDeletion(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Anonymize(ctx context.Context, id int, now time.Time) (bool, error) {
+	args := m.Called(ctx, id, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CompleteDeletion(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, id int, email string) error {
+	args := m.Called(ctx, id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePhoneNumber(ctx context.Context, id int, phoneNumber, countryCode string) error {
+	args := m.Called(ctx, id, phoneNumber, countryCode)
+	return args.Error(0)
+}
+
 func TestUserService_Create(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
@@ -56,6 +168,9 @@ func TestUserService_Create(t *testing.T) {
 		// Expect CheckByEmailOrPhoneNumber to return false (not found)
 		mockRepo.On("CheckByEmailOrPhoneNumber", mock.Anything, "test@example.com", "+6281234567890").Return(false, nil)
 
+		// Expect the generated account number to be free
+		mockRepo.On("CheckByAccountNumber", mock.Anything, mock.Anything).Return(false, nil)
+
 		// Expect Create to succeed
 		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
 			return u.Email != nil && *u.Email == "test@example.com" && u.Name == "Test User"
@@ -182,6 +297,7 @@ func TestUserService_Create(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 
 		mockRepo.On("CheckByEmailOrPhoneNumber", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		mockRepo.On("CheckByAccountNumber", mock.Anything, mock.Anything).Return(false, nil)
 		mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db create error"))
 
 		deps := service.Dependencies{
@@ -213,7 +329,7 @@ func TestUserService_GetTokens(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 
-		hashedPassword, _ := generator.Hash("password123")
+		hashedPassword, _ := generator.Hash("password123", nil)
 
 		mockRepo.On("GetCredentialsByEmailOrPhoneNumber", mock.Anything, "test@example.com", "").
 			Return(&models.User{
@@ -230,7 +346,7 @@ func TestUserService_GetTokens(t *testing.T) {
 					User: mockRepo,
 				},
 			},
-			// JWTConfig is nil, rely on generator defaults
+			JWTConfig: testJWTConfig,
 		}
 
 		svc := service.NewUserService(&deps)
@@ -284,7 +400,7 @@ func TestUserService_GetTokens(t *testing.T) {
 	t.Run("Invalid Password", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 
-		hashedPassword, _ := generator.Hash("password123")
+		hashedPassword, _ := generator.Hash("password123", nil)
 
 		mockRepo.On("GetCredentialsByEmailOrPhoneNumber", mock.Anything, "test@example.com", "").
 			Return(&models.User{
@@ -317,3 +433,1249 @@ func TestUserService_GetTokens(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestUserService_List(t *testing.T) {
+	newService := func(mockRepo *MockUserRepository) service.UserService {
+		return service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{
+				Postgre: &pgsql.PostgreRepository{
+					User: mockRepo,
+				},
+			},
+		})
+	}
+
+	t.Run("Success With Filters", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f models.UserFilter) bool {
+			return f.Name == "john" &&
+				f.SortBy == "name" && f.SortDesc &&
+				f.Limit == 10 && f.Offset == 20 &&
+				f.CreatedFrom != nil && f.CreatedFrom.Format("2006-01-02") == "2026-01-01" &&
+				f.CreatedTo != nil && f.CreatedTo.Format("2006-01-02") == "2026-02-01" // exclusive bound
+		})).Return([]models.User{{Name: "John Doe"}}, 21, nil)
+
+		users, total, err := newService(mockRepo).List(context.Background(), &models.ListUserRequest{
+			Name:        "john",
+			CreatedFrom: "2026-01-01",
+			CreatedTo:   "2026-01-31",
+			Sort:        "-name",
+			Page:        3,
+			Limit:       10,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, 21, total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f models.UserFilter) bool {
+			return f.SortBy == "created_at" && !f.SortDesc && f.Limit == 20 && f.Offset == 0 &&
+				f.CreatedFrom == nil && f.CreatedTo == nil
+		})).Return([]models.User{}, 0, nil)
+
+		_, _, err := newService(mockRepo).List(context.Background(), &models.ListUserRequest{})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("List", mock.Anything, mock.Anything).Return(nil, 0, errors.New("db error"))
+
+		users, _, err := newService(mockRepo).List(context.Background(), &models.ListUserRequest{})
+
+		assert.Error(t, err)
+		assert.Nil(t, users)
+		assert.Contains(t, err.Error(), "Failed to list users")
+	})
+}
+
+func TestUserService_Search(t *testing.T) {
+	newService := func(mockRepo *MockUserRepository) service.UserService {
+		return service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{
+				Postgre: &pgsql.PostgreRepository{
+					User: mockRepo,
+				},
+			},
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Search", mock.Anything, models.UserSearchFilter{Query: "john doe", Limit: 10, Offset: 20}).
+			Return([]models.UserSearchResult{{User: models.User{Name: "John Doe"}, Rank: 1}}, 21, nil)
+
+		results, total, err := newService(mockRepo).Search(context.Background(), &models.SearchUserRequest{Q: "  John Doe ", Page: 3, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, 21, total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Search", mock.Anything, models.UserSearchFilter{Query: "jo", Limit: 20}).
+			Return([]models.UserSearchResult{}, 0, nil)
+
+		_, _, err := newService(mockRepo).Search(context.Background(), &models.SearchUserRequest{Q: "jo"})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Blank Search", func(t *testing.T) {
+		_, _, err := newService(new(MockUserRepository)).Search(context.Background(), &models.SearchUserRequest{Q: "   "})
+
+		assert.Equal(t, errorc.ErrorInvalidInput.Response.Code, errorc.GetResponse(err).Code)
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Search", mock.Anything, mock.Anything).Return(nil, 0, errors.New("db error"))
+
+		_, _, err := newService(mockRepo).Search(context.Background(), &models.SearchUserRequest{Q: "john"})
+
+		assert.Error(t, err)
+	})
+}
+
+type MockPasswordBreachChecker struct {
+	mock.Mock
+}
+
+func (m *MockPasswordBreachChecker) Count(ctx context.Context, password string) (int, error) {
+	args := m.Called(ctx, password)
+	return args.Int(0), args.Error(1)
+}
+
+func TestUserService_CreatePasswordPolicy(t *testing.T) {
+	request := func(password string) *models.CreateUserRequest {
+		return &models.CreateUserRequest{
+			Name:     "Test User",
+			Email:    "test@example.com",
+			Password: password,
+		}
+	}
+
+	t.Run("Weak Password", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		svc := service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+			Config:     &config.Configuration{Password: config.Password{MinScore: 3}},
+		})
+
+		user, err := svc.Create(context.Background(), request("testuser123"))
+
+		assert.Nil(t, user)
+		resp := errorc.GetResponse(err)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		if errs, ok := resp.Errors.([]models.ErrorValidationResponse); assert.True(t, ok) {
+			assert.Equal(t, "password", errs[0].Field)
+			assert.Contains(t, errs[0].Message, "avoid using your name")
+		}
+		mockRepo.AssertNotCalled(t, "CheckByEmailOrPhoneNumber", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Breached Password", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockBreach := new(MockPasswordBreachChecker)
+		mockBreach.On("Count", mock.Anything, "gT7#pQ2!vL9@").Return(12, nil)
+
+		svc := service.NewUserService(&service.Dependencies{
+			Repository:     repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+			Config:         &config.Configuration{},
+			PasswordBreach: mockBreach,
+		})
+
+		_, err := svc.Create(context.Background(), request("gT7#pQ2!vL9@"))
+
+		assert.Contains(t, fmt.Sprint(errorc.GetResponse(err).Errors), "known data breach")
+		mockBreach.AssertExpectations(t)
+	})
+
+	t.Run("Breach Check Unavailable Fails Open", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CheckByEmailOrPhoneNumber", mock.Anything, "test@example.com", "").Return(false, nil)
+		mockRepo.On("CheckByAccountNumber", mock.Anything, mock.Anything).Return(false, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockBreach := new(MockPasswordBreachChecker)
+		mockBreach.On("Count", mock.Anything, mock.Anything).Return(0, errors.New("timeout"))
+
+		svc := service.NewUserService(&service.Dependencies{
+			Repository:     repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+			Config:         &config.Configuration{},
+			PasswordBreach: mockBreach,
+		})
+
+		wideEvent := logger.NewWideEvent("req-1", http.MethodPost, "/api/v1/users", "", "")
+		ctx := logger.WithWideEvent(logger.WithLogger(context.Background(), logger.NewTestLogger()), wideEvent)
+		user, err := svc.Create(ctx, request("gT7#pQ2!vL9@"))
+
+		assert.NoError(t, err)
+		assert.NotNil(t, user)
+		assert.Equal(t, "unavailable", wideEvent.GetBusinessData()["breach_check"])
+		assert.Nil(t, wideEvent.GetError(), "a registration that succeeds logs no error")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserService_GetTokensRehash(t *testing.T) {
+	setup := func(updateErr error) (*MockUserRepository, service.UserService) {
+		mockRepo := new(MockUserRepository)
+
+		// Stored with a lower cost than configured
+		hashedPassword, _ := generator.Hash("password123", &hashc.Configuration{Cost: generator.MinCost})
+
+		mockRepo.On("GetCredentialsByEmailOrPhoneNumber", mock.Anything, "test@example.com", "").
+			Return(&models.User{
+				ID:            7,
+				Email:         strPtr("test@example.com"),
+				Password:      hashedPassword,
+				AccountNumber: "123456",
+			}, nil)
+		mockRepo.On("UpdatePassword", mock.Anything, 7, mock.MatchedBy(func(hash string) bool {
+			return !generator.NeedsRehash(hash, &hashc.Configuration{Cost: generator.MinCost + 1})
+		})).Return(updateErr)
+
+		return mockRepo, service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+			HashConfig: &hashc.Configuration{Cost: generator.MinCost + 1},
+			JWTConfig:  testJWTConfig,
+		})
+	}
+
+	request := &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"}
+
+	t.Run("Upgrades Hash", func(t *testing.T) {
+		mockRepo, svc := setup(nil)
+
+		_, err := svc.GetTokens(context.Background(), request)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Failure Does Not Block Login", func(t *testing.T) {
+		mockRepo, svc := setup(errors.New("db error"))
+
+		resp, err := svc.GetTokens(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserService_CreateAccountNumberCollision(t *testing.T) {
+	request := &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"}
+
+	t.Run("Retries Until Free", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CheckByEmailOrPhoneNumber", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		mockRepo.On("CheckByAccountNumber", mock.Anything, mock.Anything).Return(true, nil).Twice()
+		mockRepo.On("CheckByAccountNumber", mock.Anything, mock.Anything).Return(false, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+		})
+
+		user, err := svc.Create(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, user.AccountNumber)
+		mockRepo.AssertNumberOfCalls(t, "CheckByAccountNumber", 3)
+	})
+
+	t.Run("Retries Exhausted", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CheckByEmailOrPhoneNumber", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		mockRepo.On("CheckByAccountNumber", mock.Anything, mock.Anything).Return(true, nil)
+
+		svc := service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+			Config:     &config.Configuration{AccountNumber: config.AccountNumber{MaxRetries: 2}},
+		})
+
+		user, err := svc.Create(context.Background(), request)
+
+		assert.Nil(t, user)
+		assert.Contains(t, err.Error(), "Failed to generate account number")
+		mockRepo.AssertNumberOfCalls(t, "CheckByAccountNumber", 3)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	setup := func() (*MockUserRepository, service.UserService) {
+		mockRepo := new(MockUserRepository)
+		return mockRepo, service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+			HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, svc := setup()
+		mockRepo.On("GetOneByAccountNumber", mock.Anything, "123456").
+			Return(&models.User{ID: 7, AccountNumber: "123456"}, nil)
+		mockRepo.On("UpdatePassword", mock.Anything, 7, mock.MatchedBy(func(hash string) bool {
+			ok, err := validator.Hash("new-password-123", hash, &hashc.Configuration{Cost: generator.MinCost})
+			return err == nil && ok
+		})).Return(nil)
+
+		err := svc.ResetPassword(context.Background(), "123456", "new-password-123")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockRepo, svc := setup()
+		mockRepo.On("GetOneByAccountNumber", mock.Anything, "000000").Return(nil, nil)
+
+		err := svc.ResetPassword(context.Background(), "000000", "new-password-123")
+
+		assert.Equal(t, http.StatusNotFound, errorc.GetResponse(err).Code)
+		mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:  testJWTConfig,
+	})
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	updated, err := svc.UpdateProfile(ctx, created.AccountNumber, &models.UpdateUserRequest{Name: " John Doe ", Version: 1})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "John Doe", updated.Name)
+		assert.Equal(t, 2, updated.Version)
+	}
+
+	_, err = svc.UpdateProfile(ctx, created.AccountNumber, &models.UpdateUserRequest{Name: "Jane Doe", Version: 1})
+	assert.Equal(t, errorc.ErrorVersionConflict.Response.Status, errorc.GetResponse(err).Status, "the update was based on a stale version")
+
+	user, err := svc.GetByAccountNumber(ctx, created.AccountNumber)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "John Doe", user.Name, "the stale update changed nothing")
+		assert.Equal(t, 2, user.Version)
+	}
+
+	assert.NoError(t, svc.ResetPassword(ctx, created.AccountNumber, "new-password-123"))
+	_, err = svc.UpdateProfile(ctx, created.AccountNumber, &models.UpdateUserRequest{Name: "Jane Doe", Version: 2})
+	assert.Equal(t, http.StatusConflict, errorc.GetResponse(err).Code, "every update bumps the version")
+
+	_, err = svc.UpdateProfile(ctx, "missing", &models.UpdateUserRequest{Name: "Jane Doe", Version: 1})
+	assert.Equal(t, http.StatusNotFound, errorc.GetResponse(err).Code)
+}
+
+func TestUserService_RevokeSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetOneByAccountNumber", mock.Anything, "123456").
+		Return(&models.User{ID: 7, AccountNumber: "123456"}, nil)
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: &pgsql.PostgreRepository{User: mockRepo}},
+	})
+
+	t.Run("Not Configured", func(t *testing.T) {
+		err := svc.RevokeSessions(context.Background(), "123456")
+
+		assert.Equal(t, http.StatusInternalServerError, errorc.GetResponse(err).Code)
+	})
+
+	t.Run("Revokes Earlier Tokens", func(t *testing.T) {
+		revocations := session.NewRevocations(cache.NewMemoryStore(10), time.Hour)
+		session.SetDefault(revocations)
+		t.Cleanup(func() { session.SetDefault(nil) })
+		issuedAt := time.Now().Add(-time.Minute)
+
+		err := svc.RevokeSessions(context.Background(), "123456")
+
+		assert.NoError(t, err)
+		revoked, err := revocations.IsRevoked(context.Background(), "123456", issuedAt)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+}
+
+func TestUserService_GetTokensAudit(t *testing.T) {
+	log := logger.NewTestLogger()
+	ctx := logger.WithLogger(context.Background(), log)
+	ctx = clientinfo.WithInfo(ctx, clientinfo.Info{IP: "81.2.69.142", Country: "GB", City: "London", Device: "mobile", OS: "iOS", Browser: "Safari"})
+
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:  testJWTConfig,
+	})
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "wrong-password"})
+	assert.Error(t, err)
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "nobody@example.com", Password: "password123"})
+	assert.Error(t, err)
+
+	succeeded := log.EventsWithMessage("audit: login")
+	if assert.Len(t, succeeded, 1) {
+		assert.Equal(t, "info", succeeded[0].Level)
+		assert.Equal(t, created.AccountNumber, succeeded[0].Fields["account_number"])
+		assert.Equal(t, "81.2.69.142", succeeded[0].Fields["remote_ip"])
+		assert.Equal(t, "GB", succeeded[0].Fields["client_country"])
+		assert.Equal(t, "mobile", succeeded[0].Fields["client_device"])
+	}
+
+	failed := log.EventsWithMessage("audit: login failed")
+	if assert.Len(t, failed, 2) {
+		assert.Equal(t, "invalid_password", failed[0].Fields["failure_reason"])
+		assert.Equal(t, created.AccountNumber, failed[0].Fields["account_number"])
+		assert.Equal(t, "unknown_user", failed[1].Fields["failure_reason"])
+		assert.NotContains(t, failed[1].Fields, "account_number")
+		assert.Equal(t, "London", failed[1].Fields["client_city"])
+	}
+}
+
+func TestUserService_InMemory(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:  testJWTConfig,
+	})
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	assert.Equal(t, http.StatusConflict, errorc.GetResponse(err).Code, "the email is taken")
+
+	tokens, err := svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, created.AccountNumber, tokens.AccountNumber)
+	}
+
+	assert.NoError(t, svc.ResetPassword(ctx, created.AccountNumber, "new-password-123"))
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"})
+	assert.Error(t, err, "the old password no longer works")
+
+	users, total, err := svc.List(ctx, &models.ListUserRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, users, 1)
+}
+
+func TestUserService_Import(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:  testJWTConfig,
+	})
+
+	_, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Taken", Email: "taken@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	invalid := models.ErrorValidationResponse{Code: "INVALID_FIELD", Field: "email", Message: "email is invalid"}
+	report, err := svc.Import(ctx, []models.UserImportRow{
+		{Line: 2, User: models.CreateUserRequest{Name: "John Doe", Email: "john@example.com", Password: "password123"}},
+		{Line: 3, Errors: []models.ErrorValidationResponse{invalid}},
+		{Line: 4, User: models.CreateUserRequest{Name: "Taken Again", Email: "taken@example.com", Password: "password123"}},
+		{Line: 5, User: models.CreateUserRequest{Name: "John Again", Email: " John@Example.com", Password: "password123"}},
+		{Line: 6, User: models.CreateUserRequest{Name: "Jane Doe", PhoneNumber: models.PhoneNumber{Number: "081234567890"}, Password: "password123"}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 3, report.Failed)
+	if !assert.Len(t, report.Rows, 5) {
+		return
+	}
+	assert.Equal(t, models.UserImportImported, report.Rows[0].Status)
+	assert.NotEmpty(t, report.Rows[0].AccountNumber)
+	assert.Equal(t, []models.ErrorValidationResponse{invalid}, report.Rows[1].Errors)
+	assert.Equal(t, errorc.ErrorAlreadyExist.Response.Status, report.Rows[2].Errors[0].Code)
+	assert.Contains(t, report.Rows[3].Errors[0].Message, "line 2")
+	assert.Equal(t, 6, report.Rows[4].Line)
+	assert.Equal(t, models.UserImportImported, report.Rows[4].Status)
+
+	tokens, err := svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "john@example.com", Password: "password123"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, report.Rows[0].AccountNumber, tokens.AccountNumber)
+	}
+
+	_, err = svc.Import(ctx, nil)
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+
+	_, err = svc.Import(ctx, make([]models.UserImportRow, service.MaxUserImportRows+1))
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+}
+
+func TestUserService_Export(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+	})
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		_, err := svc.Create(ctx, &models.CreateUserRequest{Name: "User", Email: email, Password: "password123"})
+		assert.NoError(t, err)
+	}
+
+	var emails []string
+	err := svc.Export(ctx, func(user *models.User) error {
+		assert.Empty(t, user.Password)
+		emails = append(emails, *user.Email)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, emails)
+
+	stop := errors.New("client gone")
+	err = svc.Export(ctx, func(user *models.User) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestUserService_EmailNormalization(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	newService := func(foldGmail bool) service.UserService {
+		return service.NewUserService(&service.Dependencies{
+			Repository:        repository.Repository{Postgre: repo},
+			Config:            &config.Configuration{Email: config.Email{FoldGmail: foldGmail}},
+			HashConfig:        &hashc.Configuration{Cost: generator.MinCost},
+			JWTConfig:         testJWTConfig,
+			DisposableDomains: validator.NewDomainBlocklist("mailinator.com"),
+		})
+	}
+
+	legacy, err := newService(false).Create(ctx, &models.CreateUserRequest{Name: "Jane Doe", Email: " Jane.Doe@Gmail.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "jane.doe@gmail.com", *legacy.Email)
+
+	svc := newService(true)
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "John Doe", Email: "John.Doe+shop@googlemail.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "johndoe@gmail.com", *created.Email)
+
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "John Doe", Email: "JOHNDOE@gmail.com", Password: "password123"})
+	assert.Equal(t, http.StatusConflict, errorc.GetResponse(err).Code, "the folded address is taken")
+
+	tokens, err := svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "john.doe@gmail.com", Password: "password123"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, created.AccountNumber, tokens.AccountNumber)
+	}
+	tokens, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "Jane.Doe@gmail.com", Password: "password123"})
+	if assert.NoError(t, err, "accounts created before folding keep signing in") {
+		assert.Equal(t, legacy.AccountNumber, tokens.AccountNumber)
+	}
+
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "Spam", Email: "spam@eu.Mailinator.com", Password: "password123"})
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "disposable domains are refused")
+}
+
+func TestUserService_Username(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		Config:     &config.Configuration{Username: config.Username{Reserved: []string{"acme"}}},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:  testJWTConfig,
+	})
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "John Doe", Username: "John.Doe", Email: "john@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe", *created.Username)
+
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "John Doe", Username: "JOHN.DOE", Email: "johnny@example.com", Password: "password123"})
+	assert.Equal(t, http.StatusConflict, errorc.GetResponse(err).Code, "usernames are unique regardless of case")
+
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "Acme", Username: "a.c.m.e", Email: "acme@example.com", Password: "password123"})
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "configured names are reserved")
+
+	tokens, err := svc.GetTokens(ctx, &models.GetUserTokenRequest{Username: "John.Doe", Password: "password123"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, created.AccountNumber, tokens.AccountNumber)
+		assert.Equal(t, "john.doe", tokens.Username)
+	}
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Username: "jane.doe", Password: "password123"})
+	assert.Equal(t, http.StatusNotFound, errorc.GetResponse(err).Code)
+
+	for username, reason := range map[string]string{
+		"jane.doe": "",
+		"John.Doe": models.UsernameTaken,
+		"Admin":    models.UsernameReserved,
+		"acme":     models.UsernameReserved,
+		"j":        models.UsernameInvalid,
+	} {
+		availability, err := svc.CheckUsernameAvailability(ctx, username)
+		if assert.NoError(t, err) {
+			assert.Equal(t, reason == "", availability.Available, username)
+			assert.Equal(t, reason, availability.Reason, username)
+		}
+	}
+}
+
+// notifications records the delivered notifications
+type notifications struct {
+	mu       sync.Mutex
+	messages []notify.Message
+}
+
+func (n *notifications) Notify(ctx context.Context, message notify.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+// newNotificationQueue delivers to delivered the notifications rendered like the server does
+func newNotificationQueue(t *testing.T, delivered *notifications) *notify.Queue {
+	templates, err := service.NotificationTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notify.NewQueue(delivered, notify.QueueOptions{Templates: templates})
+}
+
+func TestUserService_GetTokensSuspiciousLogin(t *testing.T) {
+	log := logger.NewTestLogger()
+	delivered := &notifications{}
+	queue := newNotificationQueue(t, delivered)
+
+	svc := service.NewUserService(&service.Dependencies{
+		Repository:    repository.Repository{Postgre: memory.New()},
+		Config:        &config.Configuration{LoginAlerts: config.LoginAlerts{Enabled: true}},
+		HashConfig:    &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:     testJWTConfig,
+		Notifications: queue,
+	})
+	created, err := svc.Create(context.Background(), &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	login := func(info clientinfo.Info) map[string]any {
+		wideEvent := logger.NewWideEvent("req-1", http.MethodPost, "/api/v1/users/tokens", info.IP, "")
+		ctx := logger.WithWideEvent(logger.WithLogger(context.Background(), log), wideEvent)
+		ctx = clientinfo.WithInfo(ctx, info)
+		_, err := svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"})
+		assert.NoError(t, err)
+		return wideEvent.GetBusinessData()
+	}
+
+	jakarta := clientinfo.Info{IP: "103.10.20.30", Country: "ID", City: "Jakarta", Device: "desktop", OS: "Windows", Browser: "Chrome"}
+	data := login(jakarta)
+	assert.Equal(t, false, data["login_suspicious"], "the first login sets the baseline")
+
+	data = login(jakarta)
+	assert.Equal(t, false, data["login_suspicious"])
+
+	data = login(clientinfo.Info{IP: "81.2.69.142", Country: "GB", City: "London", Device: "mobile", OS: "iOS", Browser: "Safari"})
+	assert.Equal(t, true, data["login_suspicious"])
+	assert.Equal(t, []string{models.LoginReasonNewCountry, models.LoginReasonNewDevice}, data["login_suspicious_reasons"])
+
+	suspicious := log.EventsWithMessage("audit: suspicious login")
+	if assert.Len(t, suspicious, 1) {
+		assert.Equal(t, "warn", suspicious[0].Level)
+		assert.Equal(t, "GB", suspicious[0].Fields["client_country"])
+	}
+
+	assert.NoError(t, queue.Close(context.Background()))
+	if assert.Len(t, delivered.messages, 1) {
+		message := delivered.messages[0]
+		assert.Equal(t, "login.suspicious", message.Event)
+		assert.Equal(t, "test@example.com", message.Recipient)
+		assert.Contains(t, message.Text, "Location: London, GB")
+		assert.Contains(t, message.HTML, "<td>London, GB</td>")
+		assert.Equal(t, "en", message.Locale)
+		assert.Equal(t, created.AccountNumber, message.Data["account_number"])
+	}
+
+	logins, err := svc.ListLogins(context.Background(), created.AccountNumber, &models.ListLoginEventRequest{Limit: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, logins, 2) {
+		assert.Equal(t, "GB", logins[0].Country, "newest first")
+		assert.Equal(t, "new_country,new_device", logins[0].Reasons)
+		assert.False(t, logins[1].Suspicious)
+	}
+}
+
+func TestUserService_Consent(t *testing.T) {
+	log := logger.NewTestLogger()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		Config:     &config.Configuration{Consent: config.Consent{TermsVersion: "2026-10-01"}},
+		HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:  testJWTConfig,
+	})
+	wideEvent := logger.NewWideEvent("req-1", http.MethodPost, "/api/v1/users", "81.2.69.142", "Mozilla/5.0")
+	ctx := logger.WithWideEvent(logger.WithLogger(context.Background(), log), wideEvent)
+
+	_, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123", TermsVersion: "2026-01-01"})
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "outdated terms")
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123", TermsVersion: "2026-10-01"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	consent, err := svc.GetConsent(ctx, created.AccountNumber)
+	if assert.NoError(t, err) && assert.NotNil(t, consent) {
+		assert.Equal(t, "2026-10-01", consent.TermsVersion)
+		assert.NotNil(t, consent.TermsAcceptedAt)
+		assert.False(t, consent.MarketingOptIn)
+		assert.Nil(t, consent.MarketingUpdatedAt)
+		assert.Equal(t, "81.2.69.142", consent.IP)
+		assert.Equal(t, "Mozilla/5.0", consent.UserAgent)
+	}
+	assert.Len(t, log.EventsWithMessage("audit: consent updated"), 1)
+
+	accepted, err := svc.HasAcceptedTerms(ctx, created.AccountNumber)
+	assert.NoError(t, err)
+	assert.True(t, accepted)
+
+	optIn := true
+	updated, err := svc.UpdateConsent(ctx, created.AccountNumber, &models.UpdateConsentRequest{MarketingOptIn: &optIn})
+	if assert.NoError(t, err) {
+		assert.True(t, updated.MarketingOptIn)
+		assert.NotNil(t, updated.MarketingUpdatedAt)
+		assert.Equal(t, "2026-10-01", updated.TermsVersion, "the accepted terms carry over")
+		assert.Equal(t, consent.TermsAcceptedAt, updated.TermsAcceptedAt)
+	}
+
+	unchanged, err := svc.UpdateConsent(ctx, created.AccountNumber, &models.UpdateConsentRequest{MarketingOptIn: &optIn})
+	assert.NoError(t, err)
+	assert.Equal(t, updated.ID, unchanged.ID, "nothing changed, nothing recorded")
+	assert.Len(t, log.EventsWithMessage("audit: consent updated"), 2)
+
+	_, err = svc.UpdateConsent(ctx, created.AccountNumber, &models.UpdateConsentRequest{TermsVersion: "2027-01-01"})
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "only the current terms can be accepted")
+
+	// Without consent, e.g. an account created before consent tracking
+	accepted, err = svc.HasAcceptedTerms(ctx, "0000000000")
+	assert.NoError(t, err)
+	assert.False(t, accepted)
+}
+
+func TestUserService_Deletion(t *testing.T) {
+	log := logger.NewTestLogger()
+	repo := memory.New()
+	newService := func(gracePeriod string) service.UserService {
+		return service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: repo},
+			Config: &config.Configuration{
+				LoginAlerts: config.LoginAlerts{Enabled: true},
+				Deletion:    config.Deletion{GracePeriod: gracePeriod},
+			},
+			HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+			JWTConfig:  testJWTConfig,
+		})
+	}
+	svc := newService("720h")
+	wideEvent := logger.NewWideEvent("req-1", http.MethodPost, "/api/v1/users", "81.2.69.142", "Mozilla/5.0")
+	ctx := logger.WithWideEvent(logger.WithLogger(context.Background(), log), wideEvent)
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123", MarketingOptIn: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cached := cacheResponse(t, created.AccountNumber)
+	scheduled, err := svc.ScheduleDeletion(ctx, created.AccountNumber)
+	if assert.NoError(t, err) && assert.NotNil(t, scheduled.DeletionScheduledAt) {
+		assert.WithinDuration(t, time.Now().Add(720*time.Hour), *scheduled.DeletionScheduledAt, time.Minute)
+	}
+	assert.Len(t, log.EventsWithMessage("audit: account deletion scheduled"), 1)
+	assert.False(t, cached(), "the cached responses are dropped")
+
+	again, err := svc.ScheduleDeletion(ctx, created.AccountNumber)
+	if assert.NoError(t, err) {
+		assert.Equal(t, scheduled.DeletionScheduledAt, again.DeletionScheduledAt, "the first schedule stays")
+	}
+
+	// Signing in during the grace period cancels the deletion
+	cached = cacheResponse(t, created.AccountNumber)
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.Equal(t, true, wideEvent.GetBusinessData()["deletion_cancelled"])
+	assert.False(t, cached())
+	user, err := svc.GetByAccountNumber(ctx, created.AccountNumber)
+	if assert.NoError(t, err) {
+		assert.Nil(t, user.DeletionScheduledAt)
+	}
+
+	anonymized, err := svc.AnonymizeDueAccounts(ctx, 10)
+	assert.NoError(t, err)
+	assert.Zero(t, anonymized)
+
+	// Without a grace period, the deletion is due right away
+	svc = newService("1ns")
+	_, err = svc.ScheduleDeletion(ctx, created.AccountNumber)
+	assert.NoError(t, err)
+
+	cached = cacheResponse(t, created.AccountNumber)
+	anonymized, err = svc.AnonymizeDueAccounts(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, anonymized)
+	assert.Len(t, log.EventsWithMessage("audit: account anonymized"), 1)
+	assert.False(t, cached())
+
+	user, err = svc.GetByAccountNumber(ctx, created.AccountNumber)
+	if assert.NoError(t, err) {
+		assert.Empty(t, user.Name)
+		assert.Nil(t, user.Email)
+		assert.Nil(t, user.DeletionScheduledAt)
+	}
+	_, err = svc.GetTokens(ctx, &models.GetUserTokenRequest{Email: "test@example.com", Password: "password123"})
+	assert.Error(t, err, "the email no longer signs in")
+	_, err = svc.ScheduleDeletion(ctx, created.AccountNumber)
+	assert.Equal(t, http.StatusNotFound, errorc.GetResponse(err).Code, "the account is deleted")
+
+	logins, err := svc.ListLogins(ctx, created.AccountNumber, &models.ListLoginEventRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, logins)
+
+	consent, err := svc.GetConsent(ctx, created.AccountNumber)
+	if assert.NoError(t, err) && assert.NotNil(t, consent) {
+		assert.Empty(t, consent.IP)
+		assert.Empty(t, consent.UserAgent)
+		assert.True(t, consent.MarketingOptIn)
+	}
+
+	anonymized, err = svc.AnonymizeDueAccounts(ctx, 10)
+	assert.NoError(t, err)
+	assert.Zero(t, anonymized, "the deletion is complete")
+
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err, "the email can sign up again")
+}
+
+// confirmLink matches the email_change.confirm_url link of the email change emails
+var confirmLink = regexp.MustCompile(`https://app\.example\.com/email/confirm\?token=(\S+)`)
+
+// tokenSentTo waits for the email change email sent to recipient and returns its token
+func (n *notifications) tokenSentTo(t *testing.T, recipient string) string {
+	t.Helper()
+	var token string
+	assert.Eventually(t, func() bool {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		for _, message := range n.messages {
+			if message.Event != "email_change.confirm" || message.Recipient != recipient {
+				continue
+			}
+			if match := confirmLink.FindStringSubmatch(message.Text); match != nil {
+				token, _ = url.QueryUnescape(match[1])
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	return token
+}
+
+func (n *notifications) reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = nil
+}
+
+func TestUserService_EmailChange(t *testing.T) {
+	log := logger.NewTestLogger()
+	delivered := &notifications{}
+	queue := newNotificationQueue(t, delivered)
+	configuration := &config.Configuration{
+		Authorization: config.Authorization{SigningSecret: "test-signing-secret-0123456789abcdef"},
+		EmailChange:   config.EmailChange{TTL: "1h", ConfirmURL: "https://app.example.com/email/confirm"},
+	}
+	repo := memory.New()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository:    repository.Repository{Postgre: repo},
+		Config:        configuration,
+		HashConfig:    &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:     testJWTConfig,
+		Notifications: queue,
+	})
+	ctx := logger.WithLogger(context.Background(), log)
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "Other User", Email: "taken@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	confirm := func(token string) (*models.EmailChange, error) {
+		return svc.ConfirmEmailChange(ctx, &models.ConfirmEmailChangeRequest{Token: token})
+	}
+
+	t.Run("Rejects The Current And Taken Emails", func(t *testing.T) {
+		_, err := svc.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "Test@example.com"})
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+
+		_, err = svc.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "taken@example.com"})
+		assert.Equal(t, http.StatusConflict, errorc.GetResponse(err).Code)
+	})
+
+	t.Run("Rejects Invalid Tokens", func(t *testing.T) {
+		_, err := confirm("forged")
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+
+		other, err := generator.SignedPayload("unsubscribe:"+created.AccountNumber, time.Hour, configuration.Authorization.SigningSecret)
+		assert.NoError(t, err)
+		_, err = confirm(other)
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "tokens of another purpose are rejected")
+	})
+
+	t.Run("Switches Once Both Addresses Confirmed", func(t *testing.T) {
+		delivered.reset()
+		change, err := svc.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "new@example.com"})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Nil(t, change.OldConfirmedAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), change.ExpiresAt, time.Minute)
+		assert.Len(t, log.EventsWithMessage("audit: email change requested"), 1)
+
+		oldToken := delivered.tokenSentTo(t, "test@example.com")
+		newToken := delivered.tokenSentTo(t, "new@example.com")
+
+		change, err = confirm(newToken)
+		if assert.NoError(t, err) {
+			assert.NotNil(t, change.NewConfirmedAt)
+			assert.Nil(t, change.CompletedAt)
+		}
+		user, err := svc.GetByAccountNumber(ctx, created.AccountNumber)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "test@example.com", *user.Email, "the old address didn't confirm yet")
+		}
+
+		cached := cacheResponse(t, created.AccountNumber)
+		change, err = confirm(oldToken)
+		if assert.NoError(t, err) {
+			assert.NotNil(t, change.CompletedAt)
+		}
+		user, err = svc.GetByAccountNumber(ctx, created.AccountNumber)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "new@example.com", *user.Email)
+		}
+		assert.Len(t, log.EventsWithMessage("audit: email changed"), 1)
+		assert.False(t, cached(), "the cached /users/me responses are invalidated")
+
+		_, err = confirm(oldToken)
+		assert.NoError(t, err, "confirming again is a no-op")
+
+		assert.Eventually(t, func() bool {
+			delivered.mu.Lock()
+			defer delivered.mu.Unlock()
+			for _, message := range delivered.messages {
+				if message.Event == "email_change.completed" && message.Recipient == "test@example.com" {
+					return true
+				}
+			}
+			return false
+		}, time.Second, 10*time.Millisecond, "the old address is notified")
+	})
+
+	t.Run("Concurrent Confirmations Switch Once", func(t *testing.T) {
+		delivered.reset()
+		_, err := svc.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "race@example.com"})
+		if !assert.NoError(t, err) {
+			return
+		}
+		oldToken := delivered.tokenSentTo(t, "new@example.com")
+		_, err = confirm(delivered.tokenSentTo(t, "race@example.com"))
+		assert.NoError(t, err)
+
+		switched := len(log.EventsWithMessage("audit: email changed"))
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				change, err := confirm(oldToken)
+				if assert.NoError(t, err) {
+					assert.NotNil(t, change.CompletedAt)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, log.EventsWithMessage("audit: email changed"), switched+1)
+		user, err := svc.GetByAccountNumber(ctx, created.AccountNumber)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "race@example.com", *user.Email)
+		}
+	})
+
+	t.Run("A Newer Request Cancels The Pending One", func(t *testing.T) {
+		delivered.reset()
+		_, err := svc.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "first@example.com"})
+		assert.NoError(t, err)
+		firstToken := delivered.tokenSentTo(t, "first@example.com")
+
+		_, err = svc.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "second@example.com"})
+		assert.NoError(t, err)
+
+		_, err = confirm(firstToken)
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+	})
+
+	t.Run("An Account Without Email Only Confirms The New One", func(t *testing.T) {
+		delivered.reset()
+		phoneOnly, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Phone User", PhoneNumber: models.PhoneNumber{Number: "81234567890"}, Password: "password123"})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		change, err := svc.RequestEmailChange(ctx, phoneOnly.AccountNumber, &models.ChangeEmailRequest{Email: "phone@example.com"})
+		if assert.NoError(t, err) {
+			assert.NotNil(t, change.OldConfirmedAt)
+		}
+
+		change, err = confirm(delivered.tokenSentTo(t, "phone@example.com"))
+		if assert.NoError(t, err) {
+			assert.NotNil(t, change.CompletedAt)
+		}
+	})
+
+	t.Run("Not Configured", func(t *testing.T) {
+		unconfigured := service.NewUserService(&service.Dependencies{
+			Repository: repository.Repository{Postgre: repo},
+			HashConfig: &hashc.Configuration{Cost: generator.MinCost},
+			JWTConfig:  testJWTConfig,
+		})
+		_, err := unconfigured.RequestEmailChange(ctx, created.AccountNumber, &models.ChangeEmailRequest{Email: "other@example.com"})
+		assert.Equal(t, http.StatusInternalServerError, errorc.GetResponse(err).Code)
+	})
+
+	assert.NoError(t, queue.Close(context.Background()))
+}
+
+// codeTextedTo waits for the phone change code texted to phone and returns it
+func (n *notifications) codeTextedTo(t *testing.T, phone string) string {
+	t.Helper()
+	code := regexp.MustCompile(`code is ([0-9]{6})`)
+	var found string
+	assert.Eventually(t, func() bool {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		for _, message := range n.messages {
+			if match := code.FindStringSubmatch(message.Text); message.Event == "phone_change.code" && message.Phone == phone && match != nil {
+				found = match[1]
+			}
+		}
+		return found != ""
+	}, time.Second, 10*time.Millisecond)
+	return found
+}
+
+func TestUserService_PhoneChange(t *testing.T) {
+	log := logger.NewTestLogger()
+	delivered := &notifications{}
+	queue := newNotificationQueue(t, delivered)
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: memory.New()},
+		Config: &config.Configuration{
+			Authorization: config.Authorization{SigningSecret: "test-signing-secret-0123456789abcdef"},
+			PhoneChange:   config.PhoneChange{TTL: "10m", MaxAttempts: 2},
+		},
+		HashConfig:    &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:     testJWTConfig,
+		Notifications: queue,
+	})
+	ctx := logger.WithLogger(context.Background(), log)
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = svc.Create(ctx, &models.CreateUserRequest{Name: "Other User", PhoneNumber: models.PhoneNumber{Number: "081234567891"}, Password: "password123"})
+	assert.NoError(t, err)
+
+	request := func(number string) (*models.PhoneChange, error) {
+		return svc.RequestPhoneChange(ctx, created.AccountNumber, &models.ChangePhoneRequest{PhoneNumber: models.PhoneNumber{Number: number}})
+	}
+	verify := func(code string) (*models.PhoneChange, error) {
+		return svc.VerifyPhoneChange(ctx, created.AccountNumber, &models.VerifyPhoneChangeRequest{Code: code})
+	}
+
+	t.Run("Rejects Invalid And Taken Numbers", func(t *testing.T) {
+		_, err := request("0812")
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+
+		_, err = request("081234567891")
+		assert.Equal(t, http.StatusConflict, errorc.GetResponse(err).Code)
+
+		_, err = verify("123456")
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "nothing is pending")
+	})
+
+	t.Run("Switches After The Code", func(t *testing.T) {
+		change, err := request("0812-3456-7890")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "+6281234567890", change.PhoneNumber, "formatted to E.164")
+		assert.Equal(t, "ID", change.PhoneCountryCode)
+		assert.NotEqual(t, delivered.codeTextedTo(t, "+6281234567890"), change.CodeHash, "only the hash is stored")
+		assert.Len(t, log.EventsWithMessage("audit: phone change requested"), 1)
+
+		code := delivered.codeTextedTo(t, "+6281234567890")
+		_, err = verify(wrongCode(code))
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+
+		cached := cacheResponse(t, created.AccountNumber)
+		change, err = verify(code)
+		if assert.NoError(t, err) {
+			assert.NotNil(t, change.CompletedAt)
+		}
+		assert.False(t, cached(), "the cached /users/me is dropped")
+		user, err := svc.GetByAccountNumber(ctx, created.AccountNumber)
+		if assert.NoError(t, err) && assert.NotNil(t, user.PhoneNumber) {
+			assert.Equal(t, "+6281234567890", *user.PhoneNumber)
+		}
+		assert.Len(t, log.EventsWithMessage("audit: phone number changed"), 1)
+
+		_, err = verify(code)
+		assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code, "the change is no longer pending")
+	})
+
+	t.Run("Limits Wrong Codes", func(t *testing.T) {
+		delivered.reset()
+		_, err := request("081234567892")
+		assert.NoError(t, err)
+		code := delivered.codeTextedTo(t, "+6281234567892")
+
+		for range 2 {
+			_, err = verify(wrongCode(code))
+			assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+		}
+		_, err = verify(code)
+		assert.Equal(t, http.StatusTooManyRequests, errorc.GetResponse(err).Code, "even the right code is refused")
+	})
+
+	t.Run("Limits Concurrent Guesses", func(t *testing.T) {
+		delivered.reset()
+		_, err := request("081234567893")
+		assert.NoError(t, err)
+		code := delivered.codeTextedTo(t, "+6281234567893")
+
+		var wg sync.WaitGroup
+		codes := make(chan int, 10)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := verify(wrongCode(code))
+				codes <- errorc.GetResponse(err).Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+
+		counts := map[int]int{}
+		for code := range codes {
+			counts[code]++
+		}
+		assert.Equal(t, map[int]int{http.StatusBadRequest: 2, http.StatusTooManyRequests: 8}, counts, "only max_attempts codes are compared")
+	})
+
+	assert.NoError(t, queue.Close(context.Background()))
+}
+
+// cancellingPhoneChanges cancels the pending phone changes of the account once a code was
+// counted, like a newer RequestPhoneChange racing the verification
+type cancellingPhoneChanges struct {
+	pgsql.PhoneChangeRepository
+	accountNumber string
+}
+
+func (r *cancellingPhoneChanges) IncrementAttempts(ctx context.Context, id int64, max int) (int, error) {
+	attempts, err := r.PhoneChangeRepository.IncrementAttempts(ctx, id, max)
+	if err != nil {
+		return attempts, err
+	}
+	return attempts, r.CancelPending(ctx, r.accountNumber)
+}
+
+func TestUserService_VerifyCancelledPhoneChange(t *testing.T) {
+	delivered := &notifications{}
+	queue := newNotificationQueue(t, delivered)
+	repo := memory.New()
+	svc := service.NewUserService(&service.Dependencies{
+		Repository: repository.Repository{Postgre: repo},
+		Config: &config.Configuration{
+			Authorization: config.Authorization{SigningSecret: "test-signing-secret-0123456789abcdef"},
+			PhoneChange:   config.PhoneChange{TTL: "10m", MaxAttempts: 2},
+		},
+		HashConfig:    &hashc.Configuration{Cost: generator.MinCost},
+		JWTConfig:     testJWTConfig,
+		Notifications: queue,
+	})
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, &models.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = svc.RequestPhoneChange(ctx, created.AccountNumber, &models.ChangePhoneRequest{PhoneNumber: models.PhoneNumber{Number: "081234567890"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	code := delivered.codeTextedTo(t, "+6281234567890")
+
+	repo.PhoneChange = &cancellingPhoneChanges{PhoneChangeRepository: repo.PhoneChange, accountNumber: created.AccountNumber}
+	_, err = svc.VerifyPhoneChange(ctx, created.AccountNumber, &models.VerifyPhoneChangeRequest{Code: code})
+	assert.Equal(t, http.StatusBadRequest, errorc.GetResponse(err).Code)
+	assert.Contains(t, errorc.GetResponse(err).Message, "no longer pending")
+
+	user, err := svc.GetByAccountNumber(ctx, created.AccountNumber)
+	if assert.NoError(t, err) {
+		assert.Nil(t, user.PhoneNumber, "a cancelled change doesn't switch the number")
+	}
+	assert.NoError(t, queue.Close(context.Background()))
+}
+
+// cacheResponse installs a response cache holding a response of the account, and returns
+// whether it is still cached
+func cacheResponse(t *testing.T, accountNumber string) func() bool {
+	t.Helper()
+	responses := cache.NewResponses(cache.NewMemoryStore(10), time.Minute)
+	cache.SetDefault(responses)
+	t.Cleanup(func() { cache.SetDefault(nil) })
+
+	ctx := context.Background()
+	assert.NoError(t, responses.Set(ctx, accountNumber, "en /api/v1/users/me?", cache.Response{Status: http.StatusOK}, 0))
+	return func() bool {
+		_, ok, _ := responses.Get(ctx, accountNumber, "en /api/v1/users/me?")
+		return ok
+	}
+}
+
+// wrongCode returns a code other than code
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
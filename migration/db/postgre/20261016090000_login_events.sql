@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE login_events (
+    id BIGSERIAL PRIMARY KEY,
+    account_number VARCHAR(255) NOT NULL REFERENCES users (account_number),
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL DEFAULT '',
+    device VARCHAR(16) NOT NULL DEFAULT '',
+    os VARCHAR(64) NOT NULL DEFAULT '',
+    browser VARCHAR(64) NOT NULL DEFAULT '',
+    suspicious BOOLEAN NOT NULL DEFAULT FALSE,
+    reasons VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Serves both the recent logins of an account and the seen country/device check
+CREATE INDEX idx_login_events_account_number_created_at ON login_events (account_number, created_at DESC);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE login_events;
+
+-- +goose StatementEnd
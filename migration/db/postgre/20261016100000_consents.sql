@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change of consent adds a row with the full state, so the history shows what was
+-- agreed to, when, and from where; the newest row of an account is its current consent
+CREATE TABLE consents (
+    id BIGSERIAL PRIMARY KEY,
+    account_number VARCHAR(255) NOT NULL REFERENCES users (account_number),
+    terms_version VARCHAR(32) NOT NULL DEFAULT '',
+    terms_accepted_at TIMESTAMP,
+    marketing_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    marketing_updated_at TIMESTAMP,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_consents_account_number_created_at ON consents (account_number, created_at DESC);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE consents;
+
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A deletion requested through DELETE /api/v1/users/me waits for the grace period in
+-- deletion_scheduled_at; the sweep then anonymizes the row and sets deleted_at. The row and
+-- its account number stay, so login_events and consents keep pointing at it.
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at)
+WHERE
+    deletion_scheduled_at IS NOT NULL;
+
+-- Anonymized users have neither an email nor a phone number
+ALTER TABLE users DROP CONSTRAINT check_email_or_phone;
+
+ALTER TABLE users ADD CONSTRAINT check_email_or_phone CHECK (
+    (
+        email IS NOT NULL
+        AND email != ''
+    )
+    OR (
+        phone_number IS NOT NULL
+        AND phone_number != ''
+    )
+    OR deleted_at IS NOT NULL
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT check_email_or_phone;
+
+ALTER TABLE users ADD CONSTRAINT check_email_or_phone CHECK (
+    (
+        email IS NOT NULL
+        AND email != ''
+    )
+    OR (
+        phone_number IS NOT NULL
+        AND phone_number != ''
+    )
+) NOT VALID;
+
+DROP INDEX idx_users_deletion_scheduled_at;
+
+ALTER TABLE users DROP COLUMN deletion_scheduled_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A change of email waits for the confirmation of both the current and the new address; a
+-- newer request of the account cancels the pending ones
+CREATE TABLE email_changes (
+    id BIGSERIAL PRIMARY KEY,
+    account_number VARCHAR(255) NOT NULL REFERENCES users (account_number),
+    old_email VARCHAR(255) NULL,
+    new_email VARCHAR(255) NOT NULL,
+    old_confirmed_at TIMESTAMP,
+    new_confirmed_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_email_changes_account_number ON email_changes (account_number);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE email_changes;
+
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A change of phone number waits for the code sent to the new number; a newer request of the
+-- account cancels the pending ones
+CREATE TABLE phone_changes (
+    id BIGSERIAL PRIMARY KEY,
+    account_number VARCHAR(255) NOT NULL REFERENCES users (account_number),
+    phone_number VARCHAR(17) NOT NULL,
+    phone_country_code VARCHAR(3) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_phone_changes_account_number ON phone_changes (account_number);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE phone_changes;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Emails are now trimmed and lowercased before they are stored and looked up. Lowercase the
+-- existing ones too, except those that would collide with another account and need a manual
+-- merge
+UPDATE users u
+SET email = LOWER(TRIM(u.email))
+WHERE u.email IS NOT NULL
+  AND u.email <> LOWER(TRIM(u.email))
+  AND NOT EXISTS (
+      SELECT 1 FROM users o
+      WHERE o.id <> u.id AND LOWER(TRIM(o.email)) = LOWER(TRIM(u.email))
+  );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+-- The original case is not kept, there is nothing to restore
+SELECT 1;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Optional usernames, stored lowercased so they are unique regardless of case; anonymized
+-- users release theirs
+ALTER TABLE users ADD COLUMN username VARCHAR(30) NULL;
+
+CREATE UNIQUE INDEX idx_users_username_unique ON users (username)
+WHERE
+    username IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_users_username_unique;
+
+ALTER TABLE users DROP COLUMN username;
+
+-- +goose StatementEnd
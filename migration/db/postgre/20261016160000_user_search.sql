@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Trigram indexes for the admin user search, serving both the similarity operator (%) and
+-- ILIKE '%...%'. Creating the extension needs a role allowed to, e.g. the database owner.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_name_trgm ON users USING GIN (name gin_trgm_ops);
+
+CREATE INDEX idx_users_username_trgm ON users USING GIN (username gin_trgm_ops);
+
+CREATE INDEX idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+
+CREATE INDEX idx_users_phone_number_trgm ON users USING GIN (phone_number gin_trgm_ops);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+-- The extension stays, other objects may depend on it
+DROP INDEX idx_users_phone_number_trgm;
+
+DROP INDEX idx_users_email_trgm;
+
+DROP INDEX idx_users_username_trgm;
+
+DROP INDEX idx_users_name_trgm;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Optimistic locking: every UPDATE of a user bumps its version, and versioned updates
+-- (pgsql.UpdateVersioned) only apply to the version the client read
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN version;
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Who created and last updated each user (models.Audit): an account number, "admin" for
+-- the admin endpoints, NULL for signups and background jobs
+ALTER TABLE users ADD COLUMN created_by VARCHAR(64) NULL,
+ADD COLUMN updated_by VARCHAR(64) NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN created_by,
+DROP COLUMN updated_by;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The channels an account gets its optional notifications on; accounts without a row get
+-- them on every channel
+CREATE TABLE notification_preferences (
+    account_number VARCHAR(255) PRIMARY KEY REFERENCES users (account_number),
+    email BOOLEAN NOT NULL DEFAULT TRUE,
+    sms BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The deliveries that failed every attempt, as rendered, to look into and enqueue again
+CREATE TABLE notification_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    account_number VARCHAR(255) NOT NULL DEFAULT '', -- empty for notifications to no user
+    event VARCHAR(64) NOT NULL,
+    channel VARCHAR(32) NOT NULL DEFAULT '',
+    message JSONB NOT NULL,
+    attempts INT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_dead_letters_account_number ON notification_dead_letters (account_number);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE notification_dead_letters;
+DROP TABLE notification_preferences;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The requests of each API client of authorization.clients per quota period, added to by
+-- every instance (quota.Quotas.Flush)
+CREATE TABLE api_usage (
+    client VARCHAR(64) NOT NULL,
+    period VARCHAR(16) NOT NULL, -- daily or monthly
+    period_start TIMESTAMP NOT NULL, -- UTC
+    requests BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (client, period, period_start)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_usage;
+
+-- +goose StatementEnd
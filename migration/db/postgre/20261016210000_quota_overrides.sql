@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Quotas replacing those of authorization.clients for a client, set by support with
+-- PUT /admin/quotas/overrides/{client}; a NULL quota keeps the configured one, 0 is unlimited
+CREATE TABLE quota_overrides (
+    client VARCHAR(64) PRIMARY KEY,
+    daily_quota BIGINT,
+    monthly_quota BIGINT,
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP, -- NULL never expires
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE quota_overrides;
+
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sanitized request/response pairs captured for support investigations (debug_capture), kept
+-- until expires_at and replayed locally with the cli replay-capture command
+CREATE TABLE debug_captures (
+    id BIGSERIAL PRIMARY KEY,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    trigger VARCHAR(16) NOT NULL, -- token or account
+    label VARCHAR(64) NOT NULL DEFAULT '',
+    account_number VARCHAR(255) NOT NULL DEFAULT '',
+    method VARCHAR(16) NOT NULL,
+    path TEXT NOT NULL,
+    status INT NOT NULL,
+    request JSONB NOT NULL,
+    response JSONB NOT NULL,
+    captured_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_debug_captures_expires_at ON debug_captures (expires_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE debug_captures;
+
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+// Package postgre embeds the PostgreSQL migrations of the users module, the base schema.
+// Create new ones with make migrate-create; features added as a core.Module ship their own.
+package postgre
+
+import "embed"
+
+// FS holds the goose migrations of this directory
+//
+//go:embed *.sql
+var FS embed.FS